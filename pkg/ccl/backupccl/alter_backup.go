@@ -0,0 +1,121 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// alterBackupPlanHook implements PlanHookFn.
+func alterBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	alterBackup, ok := stmt.(*tree.AlterBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	if err := utilccl.CheckEnterpriseEnabled(
+		p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), "ALTER BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	if err := p.RequireAdminRole(ctx, "ALTER BACKUP"); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	backupFn, err := p.TypeAsString(ctx, alterBackup.Backup, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	newKMSFn, err := p.TypeAsString(ctx, alterBackup.NewKMS, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	oldKMSFn, err := p.TypeAsString(ctx, alterBackup.OldKMS, "ALTER BACKUP")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		backup, err := backupFn()
+		if err != nil {
+			return err
+		}
+		newKMS, err := newKMSFn()
+		if err != nil {
+			return err
+		}
+		oldKMS, err := oldKMSFn()
+		if err != nil {
+			return err
+		}
+		return rewrapBackupDataKeys(ctx, p, backup, oldKMS, newKMS)
+	}
+	return fn, nil, nil, false, nil
+}
+
+// rewrapBackupDataKeys wraps the data key of the backup stored in the given
+// location with the master key of a new KMS instead of that of the old KMS.
+// If the location is a collection, the data keys of all its backups are
+// rewrapped. The files of the backups are not rewritten.
+func rewrapBackupDataKeys(
+	ctx context.Context, p sql.PlanHookState, backup, oldKMSURI, newKMSURI string,
+) error {
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	settings := p.ExecCfg().Settings
+
+	store, err := mkStore(ctx, backup)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer store.Close()
+
+	// The incremental backups of a collection share the encryption info of
+	// their full backup.
+	fullDirs, err := listCollectionFullBackups(ctx, store)
+	if err != nil {
+		return err
+	}
+	if len(fullDirs) == 0 {
+		return rewrapDataKey(ctx, store, oldKMSURI, newKMSURI, settings)
+	}
+	for _, dir := range fullDirs {
+		uri, err := appendBackupDir(backup, dir)
+		if err != nil {
+			return err
+		}
+		if err := func() error {
+			fullStore, err := mkStore(ctx, uri)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open backup storage location")
+			}
+			defer fullStore.Close()
+			return rewrapDataKey(ctx, fullStore, oldKMSURI, newKMSURI, settings)
+		}(); err != nil {
+			return errors.Wrapf(err, "rewrapping data key of backup %s", dir)
+		}
+	}
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook(alterBackupPlanHook)
+}
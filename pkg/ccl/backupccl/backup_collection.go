@@ -0,0 +1,213 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"path"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// A backup collection is a storage location holding many backups, each in
+// its own subdirectory, as written by backup schedules: every full backup is
+// stored in a subdirectory named after the time it was taken (see
+// scheduledBackupDirFormat), and the incremental backups chained onto it in
+// similarly named subdirectories of its incrementalBackupsDir.
+
+// collectionBackupDirPattern matches the subdirectories named using
+// scheduledBackupDirFormat.
+const collectionBackupDirPattern = "[0-9]*/[0-9]*/[0-9]*-[0-9]*.[0-9][0-9]"
+
+// backupChain is a full backup in a collection along with the incremental
+// backups chained onto it.
+type backupChain struct {
+	// dirs are the subdirectories of the collection holding the full backup and
+	// then each of its incremental backups, in order.
+	dirs      []string
+	manifests []BackupManifest
+}
+
+// endTime returns the end time of the last backup of the chain.
+func (c *backupChain) endTime() hlc.Timestamp {
+	return c.manifests[len(c.manifests)-1].EndTime
+}
+
+// listCollectionFullBackups returns the subdirectories of the full backups in
+// the collection, in the order they were taken. It returns nothing if the
+// storage location holds a backup rather than a collection, or if listing is
+// not supported by the storage.
+func listCollectionFullBackups(
+	ctx context.Context, store cloud.ExternalStorage,
+) ([]string, error) {
+	if exists, err := containsManifest(ctx, store); err != nil || exists {
+		return nil, err
+	}
+	manifests, err := store.ListFiles(ctx, path.Join(collectionBackupDirPattern, BackupManifestName))
+	if err != nil {
+		if errors.Is(err, cloud.ErrListingUnsupported) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "listing backups in collection")
+	}
+	dirs := make([]string, len(manifests))
+	for i := range manifests {
+		dirs[i] = path.Dir(manifests[i])
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// loadBackupChain reads the manifest of the full backup stored in the
+// specified subdirectory of the collection and, if requested, those of the
+// incremental backups chained onto it.
+func loadBackupChain(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	collection string,
+	fullDir string,
+	encParams backupEncryptionParams,
+	withIncrementals bool,
+) (backupChain, error) {
+	uri, err := appendBackupDir(collection, fullDir)
+	if err != nil {
+		return backupChain{}, err
+	}
+	store, err := mkStore(ctx, uri)
+	if err != nil {
+		return backupChain{}, errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer store.Close()
+
+	// The incremental backups of a chain are encrypted using the encryption
+	// info of the full backup.
+	_, encryption, err := encParams.readEncryptionKey(ctx, store)
+	if err != nil {
+		return backupChain{}, err
+	}
+
+	full, err := readBackupManifestFromStore(ctx, store, encryption)
+	if err != nil {
+		return backupChain{}, err
+	}
+	chain := backupChain{dirs: []string{fullDir}, manifests: []BackupManifest{full}}
+	if !withIncrementals {
+		return chain, nil
+	}
+
+	incs, err := store.ListFiles(ctx,
+		path.Join(incrementalBackupsDir, collectionBackupDirPattern, BackupManifestName))
+	if err != nil {
+		return backupChain{}, errors.Wrap(err, "listing incremental backups")
+	}
+	sort.Strings(incs)
+	for _, inc := range incs {
+		m, err := readBackupManifest(ctx, store, inc, encryption)
+		if err != nil {
+			return backupChain{}, err
+		}
+		// Blank the stats to prevent memory blowup.
+		m.Statistics = nil
+		chain.dirs = append(chain.dirs, path.Join(fullDir, path.Dir(inc)))
+		chain.manifests = append(chain.manifests, m)
+	}
+	return chain, nil
+}
+
+// pickBackupChain returns the chain of the collection which should be
+// restored to reach the specified time, or the most recent chain if no time
+// is specified.
+//
+// The time is covered by the incremental backups of the most recent chain
+// whose full backup ended at or before it, if by any. Otherwise, it can only
+// be covered by the revision history of the first full backup which ended
+// after it. Whether the chain actually covers the time is checked when its
+// manifests are resolved.
+func pickBackupChain(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	collection string,
+	fullDirs []string,
+	endTime hlc.Timestamp,
+	encParams backupEncryptionParams,
+) (backupChain, error) {
+	if endTime.IsEmpty() {
+		return loadBackupChain(ctx, mkStore, collection, fullDirs[len(fullDirs)-1],
+			encParams, true /* withIncrementals */)
+	}
+
+	fulls := make([]backupChain, len(fullDirs))
+	for i, dir := range fullDirs {
+		var err error
+		fulls[i], err = loadBackupChain(ctx, mkStore, collection, dir,
+			encParams, false /* withIncrementals */)
+		if err != nil {
+			return backupChain{}, err
+		}
+	}
+	sort.Slice(fulls, func(i, j int) bool {
+		return fulls[i].endTime().Less(fulls[j].endTime())
+	})
+
+	i := sort.Search(len(fulls), func(i int) bool {
+		return endTime.Less(fulls[i].endTime())
+	})
+	if i > 0 {
+		chain, err := loadBackupChain(ctx, mkStore, collection, fulls[i-1].dirs[0],
+			encParams, true /* withIncrementals */)
+		if err != nil {
+			return backupChain{}, err
+		}
+		if endTime.LessEq(chain.endTime()) || i == len(fulls) {
+			return chain, nil
+		}
+	}
+	return fulls[i], nil
+}
+
+// resolveBackupCollection resolves a RESTORE from a backup collection into the
+// URIs of each backup of the chain to restore from, in the same form as the
+// layers of incremental backups explicitly specified to RESTORE. It returns
+// false if the URIs do not refer to a collection.
+func resolveBackupCollection(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	collections []string,
+	endTime hlc.Timestamp,
+	encParams backupEncryptionParams,
+) ([][]string, bool, error) {
+	store, err := mkStore(ctx, collections[0])
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer store.Close()
+
+	fullDirs, err := listCollectionFullBackups(ctx, store)
+	if err != nil || len(fullDirs) == 0 {
+		return nil, false, err
+	}
+	chain, err := pickBackupChain(ctx, mkStore, collections[0], fullDirs, endTime, encParams)
+	if err != nil {
+		return nil, false, err
+	}
+
+	from := make([][]string, len(chain.dirs))
+	for i, dir := range chain.dirs {
+		from[i] = make([]string, len(collections))
+		for j, collection := range collections {
+			if from[i][j], err = appendBackupDir(collection, dir); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return from, true, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	crypto_rand "crypto/rand"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// The files of a backup can be encrypted either with a key derived from a
+// passphrase, or with a random data key which is itself wrapped by the master
+// key of a KMS. In both cases, the EncryptionInfo needed to recover the key is
+// stored in plaintext in the encryption-info file of the full backup, and
+// shared by the incremental backups chained onto it. Wrapped data keys are
+// stored by master key ID, so that the master key can be rotated by replacing
+// the data key wrapped by the old master key with the data key wrapped by the
+// new one, without rewriting the files.
+
+// dataKeySize is the size of the data keys of backups encrypted using a KMS,
+// which are AES-256 keys.
+const dataKeySize = 32
+
+// backupEncryptionParams are the user-specified options with which the files
+// of backups are encrypted or decrypted, if any.
+type backupEncryptionParams struct {
+	passphrase []byte
+	kmsURI     string
+	settings   *cluster.Settings
+}
+
+// makeBackupEncryptionParams extracts the encryption options of a BACKUP,
+// RESTORE or SHOW BACKUP statement.
+func makeBackupEncryptionParams(
+	opts map[string]string, settings *cluster.Settings,
+) (backupEncryptionParams, error) {
+	params := backupEncryptionParams{settings: settings}
+	if passphrase, ok := opts[backupOptEncPassphrase]; ok {
+		params.passphrase = []byte(passphrase)
+	}
+	if kmsURI, ok := opts[backupOptEncKMS]; ok {
+		if params.passphrase != nil {
+			return backupEncryptionParams{}, errors.Errorf(
+				"cannot specify both %s and %s", backupOptEncPassphrase, backupOptEncKMS)
+		}
+		params.kmsURI = kmsURI
+	}
+	return params, nil
+}
+
+// isSet returns whether the files are encrypted.
+func (e backupEncryptionParams) isSet() bool {
+	return e.passphrase != nil || e.kmsURI != ""
+}
+
+// newEncryptionInfo picks the EncryptionInfo of a new full backup, returning
+// it along with the key to encrypt the files of the backup with.
+func (e backupEncryptionParams) newEncryptionInfo(
+	ctx context.Context,
+) (*EncryptionInfo, *roachpb.FileEncryptionOptions, error) {
+	if e.passphrase != nil {
+		salt, err := storageccl.GenerateSalt()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &EncryptionInfo{Salt: salt},
+			&roachpb.FileEncryptionOptions{Key: storageccl.GenerateKey(e.passphrase, salt)}, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := crypto_rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	info := &EncryptionInfo{DataKeyID: uuid.MakeV4()}
+	if err := wrapDataKey(ctx, e.kmsURI, e.settings, info, dataKey); err != nil {
+		return nil, nil, err
+	}
+	return info, &roachpb.FileEncryptionOptions{Key: dataKey}, nil
+}
+
+// keyFromEncryptionInfo returns the key to decrypt the files of the backup
+// with the given EncryptionInfo.
+func (e backupEncryptionParams) keyFromEncryptionInfo(
+	ctx context.Context, info *EncryptionInfo,
+) (*roachpb.FileEncryptionOptions, error) {
+	if e.passphrase != nil {
+		if len(info.EncryptedDataKeyByKMSMasterKeyID) > 0 {
+			return nil, errors.Errorf("backup is encrypted using a KMS, not a passphrase; "+
+				"use the %s option instead of %s", backupOptEncKMS, backupOptEncPassphrase)
+		}
+		return &roachpb.FileEncryptionOptions{
+			Key: storageccl.GenerateKey(e.passphrase, info.Salt),
+		}, nil
+	}
+
+	if len(info.EncryptedDataKeyByKMSMasterKeyID) == 0 {
+		return nil, errors.Errorf("backup is encrypted using a passphrase, not a KMS; "+
+			"use the %s option instead of %s", backupOptEncPassphrase, backupOptEncKMS)
+	}
+	dataKey, err := unwrapDataKey(ctx, e.kmsURI, e.settings, info)
+	if err != nil {
+		return nil, err
+	}
+	return &roachpb.FileEncryptionOptions{Key: dataKey}, nil
+}
+
+// readEncryptionKey reads the EncryptionInfo stored in the given location and
+// returns it along with the key to decrypt the files of the backup with, or
+// nothing if no encryption options were specified.
+func (e backupEncryptionParams) readEncryptionKey(
+	ctx context.Context, store cloud.ExternalStorage,
+) (*EncryptionInfo, *roachpb.FileEncryptionOptions, error) {
+	if !e.isSet() {
+		return nil, nil, nil
+	}
+	info, err := readEncryptionOptions(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := e.keyFromEncryptionInfo(ctx, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, key, nil
+}
+
+// wrapDataKey adds the data key, wrapped by the master key of the KMS, to the
+// EncryptionInfo.
+func wrapDataKey(
+	ctx context.Context,
+	kmsURI string,
+	settings *cluster.Settings,
+	info *EncryptionInfo,
+	dataKey []byte,
+) error {
+	kms, err := cloud.KMSFromURI(kmsURI, settings)
+	if err != nil {
+		return err
+	}
+	defer kms.Close()
+	masterKeyID, err := kms.MasterKeyID()
+	if err != nil {
+		return err
+	}
+	wrapped, err := kms.Encrypt(ctx, dataKey)
+	if err != nil {
+		return errors.Wrap(err, "wrapping data key")
+	}
+	if info.EncryptedDataKeyByKMSMasterKeyID == nil {
+		info.EncryptedDataKeyByKMSMasterKeyID = make(map[string][]byte)
+	}
+	info.EncryptedDataKeyByKMSMasterKeyID[masterKeyID] = wrapped
+	return nil
+}
+
+// unwrapDataKey returns the data key of the EncryptionInfo, using the master
+// key of the KMS to unwrap it.
+func unwrapDataKey(
+	ctx context.Context, kmsURI string, settings *cluster.Settings, info *EncryptionInfo,
+) ([]byte, error) {
+	kms, err := cloud.KMSFromURI(kmsURI, settings)
+	if err != nil {
+		return nil, err
+	}
+	defer kms.Close()
+	masterKeyID, err := kms.MasterKeyID()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, ok := info.EncryptedDataKeyByKMSMasterKeyID[masterKeyID]
+	if !ok {
+		return nil, errors.Errorf("backup data key %s is not wrapped by KMS master key %s",
+			info.DataKeyID, masterKeyID)
+	}
+	dataKey, err := kms.Decrypt(ctx, wrapped)
+	if err != nil {
+		return nil, errors.Wrap(err, "unwrapping data key")
+	}
+	return dataKey, nil
+}
+
+// kmsMasterKeyID returns the ID of the master key of the KMS.
+func kmsMasterKeyID(kmsURI string, settings *cluster.Settings) (string, error) {
+	kms, err := cloud.KMSFromURI(kmsURI, settings)
+	if err != nil {
+		return "", err
+	}
+	defer kms.Close()
+	return kms.MasterKeyID()
+}
+
+// rewrapDataKey replaces the data key of the backup stored in the given
+// location, wrapped by the master key of the old KMS, with the data key wrapped
+// by the master key of the new KMS. The backup can then only be decrypted
+// using the new KMS.
+//
+// A backup whose data key is already wrapped by the new KMS and no longer by
+// the old one is left as is, so that the rotation of a collection can be
+// retried after a failure.
+func rewrapDataKey(
+	ctx context.Context,
+	store cloud.ExternalStorage,
+	oldKMSURI, newKMSURI string,
+	settings *cluster.Settings,
+) error {
+	info, err := readEncryptionOptions(ctx, store)
+	if err != nil {
+		return err
+	}
+	if len(info.EncryptedDataKeyByKMSMasterKeyID) == 0 {
+		return errors.Errorf("backup is not encrypted using a KMS")
+	}
+	oldMasterKeyID, err := kmsMasterKeyID(oldKMSURI, settings)
+	if err != nil {
+		return err
+	}
+	newMasterKeyID, err := kmsMasterKeyID(newKMSURI, settings)
+	if err != nil {
+		return err
+	}
+	if oldMasterKeyID == newMasterKeyID {
+		return errors.Errorf("old and new KMS use the same master key %s", oldMasterKeyID)
+	}
+	if _, ok := info.EncryptedDataKeyByKMSMasterKeyID[oldMasterKeyID]; !ok {
+		if _, ok := info.EncryptedDataKeyByKMSMasterKeyID[newMasterKeyID]; ok {
+			return nil
+		}
+	}
+
+	dataKey, err := unwrapDataKey(ctx, oldKMSURI, settings, info)
+	if err != nil {
+		return err
+	}
+	if err := wrapDataKey(ctx, newKMSURI, settings, info, dataKey); err != nil {
+		return err
+	}
+	// Check that the new KMS unwraps the data key before dropping the old
+	// wrapping, which would otherwise leave the backup unreadable.
+	unwrapped, err := unwrapDataKey(ctx, newKMSURI, settings, info)
+	if err != nil {
+		return errors.Wrap(err, "verifying data key wrapped by new KMS")
+	}
+	if !bytes.Equal(unwrapped, dataKey) {
+		return errors.Errorf("data key unwrapped by new KMS does not match")
+	}
+	delete(info.EncryptedDataKeyByKMSMasterKeyID, oldMasterKeyID)
+	return writeEncryptionOptions(ctx, info, store)
+}
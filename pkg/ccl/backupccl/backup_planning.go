@@ -14,7 +14,6 @@ import (
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -47,7 +46,10 @@ import (
 const (
 	backupOptRevisionHistory = "revision_history"
 	backupOptEncPassphrase   = "encryption_passphrase"
+	backupOptEncKMS          = "kms"
 	backupOptWithPrivileges  = "privileges"
+	backupOptRevisionTimes   = "revision_timestamps"
+	backupOptDetached        = "detached"
 	localityURLParam         = "COCKROACH_LOCALITY"
 	defaultLocalityValue     = "default"
 )
@@ -77,6 +79,25 @@ var useTBI = settings.RegisterBoolSetting(
 var backupOptionExpectValues = map[string]sql.KVStringOptValidate{
 	backupOptRevisionHistory: sql.KVStringOptRequireNoValue,
 	backupOptEncPassphrase:   sql.KVStringOptRequireValue,
+	backupOptEncKMS:          sql.KVStringOptRequireValue,
+	backupOptDetached:        sql.KVStringOptRequireNoValue,
+}
+
+// backupIsDetached returns true if the backup statement was specified with
+// the detached option. A detached backup only creates the backup job, in the
+// current transaction, and returns its ID without waiting for it to complete.
+func backupIsDetached(backupStmt *tree.Backup) bool {
+	for _, opt := range backupStmt.Options {
+		if string(opt.Key) == backupOptDetached {
+			return true
+		}
+	}
+	return false
+}
+
+// detachedJobHeader is the header of the result of a detached BACKUP.
+var detachedJobHeader = sqlbase.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
 }
 
 type tableAndIndex struct {
@@ -150,8 +171,16 @@ func optsToKVOptions(opts map[string]string) tree.KVOptions {
 	for _, k := range sortedOpts {
 		opt := tree.KVOption{Key: tree.Name(k)}
 		if v := opts[k]; v != "" {
-			if k == backupOptEncPassphrase {
+			switch k {
+			case backupOptEncPassphrase:
 				v = "redacted"
+			case backupOptEncKMS:
+				// KMS URIs may contain credentials, as storage URIs do.
+				if sanitized, err := cloud.SanitizeExternalStorageURI(v, nil /* extraParams */); err != nil {
+					v = "redacted"
+				} else {
+					v = sanitized
+				}
 			}
 			opt.Value = tree.NewDString(v)
 		}
@@ -285,6 +314,7 @@ func backupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	detached := backupIsDetached(backupStmt)
 	header := sqlbase.ResultColumns{
 		{Name: "job_id", Typ: types.Int},
 		{Name: "status", Typ: types.String},
@@ -293,6 +323,9 @@ func backupPlanHook(
 		{Name: "index_entries", Typ: types.Int},
 		{Name: "bytes", Typ: types.Int},
 	}
+	if detached {
+		header = detachedJobHeader
+	}
 
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
@@ -309,7 +342,7 @@ func backupPlanHook(
 			return err
 		}
 
-		if !p.ExtendedEvalContext().TxnImplicit {
+		if !detached && !p.ExtendedEvalContext().TxnImplicit {
 			return errors.Errorf("BACKUP cannot be used inside a transaction")
 		}
 
@@ -386,9 +419,9 @@ func backupPlanHook(
 
 		makeCloudStorage := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
 
-		var encryptionPassphrase []byte
-		if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-			encryptionPassphrase = []byte(passphrase)
+		encParams, err := makeBackupEncryptionParams(opts, p.ExecCfg().Settings)
+		if err != nil {
+			return err
 		}
 
 		defaultURI, urisByLocalityKV, err := getURIsByLocalityKV(to, "")
@@ -406,23 +439,21 @@ func backupPlanHook(
 			defaultStore.Close()
 		}()
 
+		var encInfo *EncryptionInfo
 		var encryption *roachpb.FileEncryptionOptions
 		var prevBackups []BackupManifest
 		g := ctxgroup.WithContext(ctx)
 		if len(incrementalFrom) > 0 {
-			if encryptionPassphrase != nil {
+			if encParams.isSet() {
 				exportStore, err := makeCloudStorage(ctx, incrementalFrom[0])
 				if err != nil {
 					return err
 				}
 				defer exportStore.Close()
-				opts, err := readEncryptionOptions(ctx, exportStore)
+				encInfo, encryption, err = encParams.readEncryptionKey(ctx, exportStore)
 				if err != nil {
 					return err
 				}
-				encryption = &roachpb.FileEncryptionOptions{
-					Key: storageccl.GenerateKey(encryptionPassphrase, opts.Salt),
-				}
 			}
 			prevBackups = make([]BackupManifest, len(incrementalFrom))
 			for i := range incrementalFrom {
@@ -454,14 +485,9 @@ func backupPlanHook(
 				return err
 			}
 			if exists {
-				if encryptionPassphrase != nil {
-					encOpts, err := readEncryptionOptions(ctx, defaultStore)
-					if err != nil {
-						return err
-					}
-					encryption = &roachpb.FileEncryptionOptions{
-						Key: storageccl.GenerateKey(encryptionPassphrase, encOpts.Salt),
-					}
+				encInfo, encryption, err = encParams.readEncryptionKey(ctx, defaultStore)
+				if err != nil {
+					return err
 				}
 
 				prev, err := findPriorBackups(ctx, defaultStore)
@@ -595,6 +621,17 @@ func backupPlanHook(
 		// a 1.x node, meaning that if 1.1 nodes may resume a backup, the limitation
 		// of requiring full backups after schema changes remains.
 
+		// If we didn't load any prior backups from which get encryption info, we
+		// need to pick new encryption info, which is recorded below.
+		var newEncInfo *EncryptionInfo
+		if encParams.isSet() && encryption == nil {
+			newEncInfo, encryption, err = encParams.newEncryptionInfo(ctx)
+			if err != nil {
+				return err
+			}
+			encInfo = newEncInfo
+		}
+
 		backupManifest := BackupManifest{
 			StartTime:          startTime,
 			EndTime:            endTime,
@@ -611,6 +648,9 @@ func backupPlanHook(
 			Statistics:         tableStatistics,
 			DescriptorCoverage: backupStmt.DescriptorCoverage,
 		}
+		if encInfo != nil {
+			backupManifest.EncryptionDataKeyID = encInfo.DataKeyID
+		}
 
 		// Sanity check: re-run the validation that RESTORE will do, but this time
 		// including this backup, to ensure that the this backup plus any previous
@@ -637,22 +677,15 @@ func backupPlanHook(
 			return err
 		}
 
-		// If we didn't load any prior backups from which get encryption info, we
-		// need to pick a new salt and record it.
-		if encryptionPassphrase != nil && encryption == nil {
-			salt, err := storageccl.GenerateSalt()
-			if err != nil {
-				return err
-			}
+		if newEncInfo != nil {
 			exportStore, err := makeCloudStorage(ctx, defaultURI)
 			if err != nil {
 				return err
 			}
 			defer exportStore.Close()
-			if err := writeEncryptionOptions(ctx, &EncryptionInfo{Salt: salt}, exportStore); err != nil {
+			if err := writeEncryptionOptions(ctx, newEncInfo, exportStore); err != nil {
 				return err
 			}
-			encryption = &roachpb.FileEncryptionOptions{Key: storageccl.GenerateKey(encryptionPassphrase, salt)}
 		}
 
 		// TODO (lucy): For partitioned backups, also add verification for other
@@ -688,28 +721,8 @@ func backupPlanHook(
 			Details:  backupDetails,
 			Progress: jobspb.BackupProgress{},
 		}
-		var sj *jobs.StartableJob
-		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
-			sj, err = p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, jr, txn, resultsCh)
-			if err != nil {
-				return err
-			}
-			if len(spans) > 0 {
-				tsToProtect := endTime
-				rec := jobsprotectedts.MakeRecord(*backupDetails.ProtectedTimestampRecord, *sj.ID(), tsToProtect, spans)
-				return p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, rec)
-			}
-			return nil
-		}); err != nil {
-			if sj != nil {
-				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
-					log.Warningf(ctx, "failed to cleanup StartableJob: %v", cleanupErr)
-				}
-			}
-		}
 
-		// Collect telemetry.
-		{
+		collectTelemetry := func() {
 			telemetry.Count("backup.total.started")
 			if startTime.IsEmpty() {
 				telemetry.Count("backup.span.full")
@@ -734,6 +747,48 @@ func backupPlanHook(
 			}
 		}
 
+		if detached {
+			// When running inside an explicit transaction, we simply create the job
+			// record. We do not wait for the job to finish.
+			txn := p.ExtendedEvalContext().Txn
+			job, err := p.ExecCfg().JobRegistry.CreateJobWithTxn(ctx, jr, txn)
+			if err != nil {
+				return err
+			}
+			if len(spans) > 0 {
+				rec := jobsprotectedts.MakeRecord(*backupDetails.ProtectedTimestampRecord, *job.ID(), endTime, spans)
+				if err := p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, rec); err != nil {
+					return err
+				}
+			}
+			collectTelemetry()
+			telemetry.Count("backup.detached")
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(*job.ID()))}
+			return nil
+		}
+
+		var sj *jobs.StartableJob
+		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+			sj, err = p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, jr, txn, resultsCh)
+			if err != nil {
+				return err
+			}
+			if len(spans) > 0 {
+				tsToProtect := endTime
+				rec := jobsprotectedts.MakeRecord(*backupDetails.ProtectedTimestampRecord, *sj.ID(), tsToProtect, spans)
+				return p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, rec)
+			}
+			return nil
+		}); err != nil {
+			if sj != nil {
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Warningf(ctx, "failed to cleanup StartableJob: %v", cleanupErr)
+				}
+			}
+		}
+
+		collectTelemetry()
+
 		errCh, err := sj.Start(ctx)
 		if err != nil {
 			return err
@@ -60,6 +60,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/gogo/protobuf/proto"
 	"github.com/kr/pretty"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
@@ -2531,6 +2532,112 @@ func TestRestoreAsOfSystemTime(t *testing.T) {
 	})
 }
 
+func TestRestoreAsOfSystemTimeFromCollection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitNone)
+	defer cleanupFn()
+
+	// Lay out the backups the way a backup schedule would: two full backups in
+	// the collection, the first of which has an incremental backup.
+	const collection = LocalFoo + "/collection"
+	const full1 = collection + "/2020/01/01-000000.00"
+	const inc1 = full1 + "/" + incrementalBackupsDir + "/2020/01/01-010000.00"
+	const full2 = collection + "/2020/01/02-000000.00"
+
+	// The backups are taken as of whole microseconds right after each update,
+	// so that their end times can be compared with those shown by SHOW BACKUP.
+	backupAfter := func(ts string) (string, time.Time) {
+		var nanos int64
+		sqlDB.QueryRow(t, `SELECT (ceil($1::DECIMAL / 1000) * 1000)::INT`, ts).Scan(&nanos)
+		return fmt.Sprintf("%d.0000000000", nanos), timeutil.Unix(0, nanos)
+	}
+
+	var ts1, ts2, ts3 string
+	sqlDB.QueryRow(t,
+		`UPDATE data.bank SET balance = 100 RETURNING cluster_logical_timestamp()`).Scan(&ts1)
+	b1, end1 := backupAfter(ts1)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`BACKUP DATABASE data TO $1 AS OF SYSTEM TIME %s WITH revision_history`, b1), full1)
+	sqlDB.QueryRow(t,
+		`UPDATE data.bank SET balance = 200 RETURNING cluster_logical_timestamp()`).Scan(&ts2)
+	b2, end2 := backupAfter(ts2)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`BACKUP DATABASE data TO $1 AS OF SYSTEM TIME %s INCREMENTAL FROM $2 WITH revision_history`,
+		b2), inc1, full1)
+	sqlDB.QueryRow(t,
+		`UPDATE data.bank SET balance = 300 RETURNING cluster_logical_timestamp()`).Scan(&ts3)
+	b3, end3 := backupAfter(ts3)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`BACKUP DATABASE data TO $1 AS OF SYSTEM TIME %s WITH revision_history`, b3), full2)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 400`)
+
+	// The windows of the backups of the collection are listed in the order of
+	// their end times. The incremental backup picks up where its full backup
+	// ends, while the revision history of full backups starts wherever it was
+	// available, if known.
+	rows := sqlDB.Query(t, fmt.Sprintf(
+		`SELECT start_time, end_time, revision_history FROM [SHOW BACKUP '%s' WITH revision_timestamps]`,
+		collection))
+	defer rows.Close()
+	var windows [][2]pq.NullTime
+	for rows.Next() {
+		var start, end pq.NullTime
+		var revisionHistory bool
+		if err := rows.Scan(&start, &end, &revisionHistory); err != nil {
+			t.Fatal(err)
+		}
+		if !revisionHistory {
+			t.Fatalf("expected revision history in window %d", len(windows))
+		}
+		windows = append(windows, [2]pq.NullTime{start, end})
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, found %d", len(windows))
+	}
+	for i, end := range []time.Time{end1, end2, end3} {
+		start, actualEnd := windows[i][0], windows[i][1]
+		if !actualEnd.Valid || !actualEnd.Time.Equal(end) {
+			t.Errorf("window %d: expected end time %s, found %v", i, end, actualEnd.Time)
+		}
+		if start.Valid && !start.Time.Before(end) {
+			t.Errorf("window %d: start time %s is not before end time %s", i, start.Time, end)
+		}
+	}
+	if start := windows[1][0]; !start.Valid || !start.Time.Equal(end1) {
+		t.Errorf("expected the incremental backup to start at %s, found %v", end1, start.Time)
+	}
+
+	for _, tc := range []struct {
+		asOf    string
+		balance string
+	}{
+		// Covered by the revision history of the first full backup.
+		{asOf: ts1, balance: "100"},
+		// Covered by the incremental backup of the first full backup.
+		{asOf: ts2, balance: "200"},
+		// Covered by the revision history of the second full backup.
+		{asOf: ts3, balance: "300"},
+		// Without a time, the latest backup is restored.
+		{balance: "300"},
+	} {
+		sqlDB.Exec(t, `DROP DATABASE IF EXISTS restored CASCADE`)
+		sqlDB.Exec(t, `CREATE DATABASE restored`)
+		aost := ""
+		if tc.asOf != "" {
+			aost = fmt.Sprintf(" AS OF SYSTEM TIME %s", tc.asOf)
+		}
+		sqlDB.Exec(t, fmt.Sprintf(`RESTORE data.bank FROM '%s'%s WITH into_db = 'restored'`,
+			collection, aost))
+		sqlDB.CheckQueryResults(t, `SELECT DISTINCT balance FROM restored.bank`,
+			[][]string{{tc.balance}})
+	}
+}
+
 func TestRestoreAsOfSystemTimeGCBounds(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -2848,6 +2955,66 @@ func TestBackupEncrypted(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE neverappears.neverappears`, before)
 }
 
+func TestEncryptedBackupRestoreWithKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, dir, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitNone)
+	defer cleanupFn()
+
+	// The master keys of file based KMSs are read from the external IO
+	// directory.
+	for name, key := range map[string]string{
+		"a.key": "0123456789abcdef0123456789abcdef",
+		"b.key": "fedcba9876543210fedcba9876543210",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(key), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const kmsA, kmsB = "file:///a.key", "file:///b.key"
+	backupLoc := LocalFoo + "/kms"
+
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH kms = $2`, backupLoc, kmsA)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 100`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH kms = $2`, backupLoc, kmsA)
+
+	sqlDB.ExpectErr(t, `cannot specify both encryption_passphrase and kms`,
+		`SHOW BACKUP $1 WITH kms = $2, encryption_passphrase = 'abcdefg'`, backupLoc, kmsA)
+	sqlDB.ExpectErr(t, `backup is encrypted using a KMS, not a passphrase`,
+		`SHOW BACKUP $1 WITH encryption_passphrase = 'abcdefg'`, backupLoc)
+	sqlDB.ExpectErr(t, `file appears encrypted`, `SHOW BACKUP $1`, backupLoc)
+	sqlDB.ExpectErr(t, `backup data key .* is not wrapped by KMS master key`,
+		`SHOW BACKUP $1 WITH kms = $2`, backupLoc, kmsB)
+
+	sqlDB.ExpectErr(t, `old and new KMS use the same master key`,
+		`ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $3`, backupLoc, kmsA, kmsA)
+
+	// Rotate the master key: the backup can then only be read with the new
+	// KMS. Rotating it again is a no-op.
+	sqlDB.Exec(t, `ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $3`, backupLoc, kmsB, kmsA)
+	sqlDB.ExpectErr(t, `backup data key .* is not wrapped by KMS master key`,
+		`SHOW BACKUP $1 WITH kms = $2`, backupLoc, kmsA)
+	sqlDB.Exec(t, `SHOW BACKUP $1 WITH kms = $2`, backupLoc, kmsB)
+	sqlDB.Exec(t, `ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $3`, backupLoc, kmsB, kmsA)
+
+	sqlDB.Exec(t, `CREATE DATABASE restoredb`)
+	sqlDB.ExpectErr(t, `backup data key .* is not wrapped by KMS master key`,
+		`RESTORE data.bank FROM $1 WITH into_db = 'restoredb', kms = $2`, backupLoc, kmsA)
+	sqlDB.Exec(t, `RESTORE data.bank FROM $1 WITH into_db = 'restoredb', kms = $2`,
+		backupLoc, kmsB)
+	sqlDB.CheckQueryResults(t, `SELECT count(*), min(balance), max(balance) FROM restoredb.bank`,
+		[][]string{{strconv.Itoa(numAccounts), "100", "100"}})
+
+	passphraseLoc := LocalFoo + "/passphrase"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH encryption_passphrase = 'abcdefg'`,
+		passphraseLoc)
+	sqlDB.ExpectErr(t, `backup is encrypted using a passphrase, not a KMS`,
+		`SHOW BACKUP $1 WITH kms = $2`, passphraseLoc, kmsA)
+	sqlDB.ExpectErr(t, `backup is not encrypted using a KMS`,
+		`ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $3`, passphraseLoc, kmsB, kmsA)
+}
+
 func TestRestoredPrivileges(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -3247,6 +3414,80 @@ func TestBackupRestoreDropTable(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `SELECT * FROM data2.bank`, expected)
 }
 
+func TestBackupRestoreFunctionReferences(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE FUNCTION data.public.f() RETURNS INT LANGUAGE SQL
+		AS 'SELECT count(*) FROM data.bank'`)
+	sqlDB.Exec(t, "BACKUP DATABASE data TO $1", LocalFoo)
+	sqlDB.Exec(t, "CREATE DATABASE data2")
+	sqlDB.Exec(t, "RESTORE data.* FROM $1 WITH OPTIONS ('into_db'='data2')", LocalFoo)
+
+	// The function is not restored, so it does not prevent renaming or
+	// dropping the restored table.
+	sqlDB.Exec(t, `ALTER TABLE data2.bank RENAME TO data2.bank2`)
+	sqlDB.Exec(t, `DROP TABLE data2.bank2`)
+}
+
+func TestBackupRestoreTriggers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE FUNCTION reject_negative() RETURNS TRIGGER LANGUAGE SQL
+		AS 'SELECT NEW.* WHERE NEW.balance >= 0'`)
+	sqlDB.Exec(t, `CREATE TRIGGER reject BEFORE INSERT ON bank
+		FOR EACH ROW EXECUTE FUNCTION reject_negative()`)
+	sqlDB.Exec(t, "BACKUP DATABASE data TO $1", LocalFoo)
+	sqlDB.Exec(t, "CREATE DATABASE data2")
+
+	sqlDB.ExpectErr(t, `cannot restore table "bank" without the function of trigger "reject"`,
+		"RESTORE data.* FROM $1 WITH OPTIONS ('into_db'='data2')", LocalFoo)
+	sqlDB.Exec(t, "RESTORE data.* FROM $1 WITH OPTIONS ('into_db'='data2', 'skip_missing_triggers')",
+		LocalFoo)
+
+	// The trigger is not restored.
+	sqlDB.Exec(t, `INSERT INTO data2.bank VALUES (-1, -1, '')`)
+	sqlDB.CheckQueryResults(t, `SELECT balance FROM data2.bank WHERE id = -1`, [][]string{{"-1"}})
+}
+
+func TestBackupRestoreRowLevelTTL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitNone)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TABLE data.ttl (id INT PRIMARY KEY, created TIMESTAMPTZ)
+		WITH (ttl_expire_after = '30 days', ttl_column = 'created', ttl_job_cron = '@daily')`)
+	sqlDB.Exec(t, "BACKUP DATABASE data TO $1", LocalFoo)
+	sqlDB.Exec(t, "CREATE DATABASE data2")
+	sqlDB.Exec(t, "RESTORE data.ttl FROM $1 WITH OPTIONS ('into_db'='data2')", LocalFoo)
+
+	// The restored table has its own schedule.
+	sqlDB.CheckQueryResults(t, `
+		SELECT schedule_name = 'row-level-ttl-' || 'data2.ttl'::regclass::oid::string, schedule_expr
+		FROM system.scheduled_jobs WHERE executor_type = 'scheduled-row-level-ttl-executor'
+		ORDER BY schedule_id`,
+		[][]string{{"false", "@daily"}, {"true", "@daily"}},
+	)
+
+	// Dropping the restored table deletes its schedule, and not the schedule
+	// of the original table.
+	sqlDB.Exec(t, `DROP TABLE data2.ttl`)
+	sqlDB.CheckQueryResults(t, `
+		SELECT schedule_name = 'row-level-ttl-' || 'data.ttl'::regclass::oid::string
+		FROM system.scheduled_jobs WHERE executor_type = 'scheduled-row-level-ttl-executor'`,
+		[][]string{{"true"}},
+	)
+}
+
 func TestBackupRestoreIncrementalAddTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
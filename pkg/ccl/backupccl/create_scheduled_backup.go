@@ -0,0 +1,338 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+)
+
+const (
+	optFirstRun          = "first_run"
+	optOnExecFailure     = "on_execution_failure"
+	optOnPreviousRunning = "on_previous_running"
+)
+
+var scheduledBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
+	optFirstRun:          sql.KVStringOptRequireValue,
+	optOnExecFailure:     sql.KVStringOptRequireValue,
+	optOnPreviousRunning: sql.KVStringOptRequireValue,
+}
+
+var onExecFailureBehavior = map[string]jobspb.ScheduleDetails_ErrorHandlingBehavior{
+	"retry":      jobspb.ScheduleDetails_RETRY_SOON,
+	"reschedule": jobspb.ScheduleDetails_RETRY_SCHED,
+	"pause":      jobspb.ScheduleDetails_PAUSE_SCHED,
+}
+
+var onPreviousRunningBehavior = map[string]jobspb.ScheduleDetails_WaitBehavior{
+	"start": jobspb.ScheduleDetails_NO_WAIT,
+	"skip":  jobspb.ScheduleDetails_SKIP,
+	"wait":  jobspb.ScheduleDetails_WAIT,
+}
+
+var createScheduleHeader = sqlbase.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "name", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "backup_stmt", Typ: types.String},
+}
+
+// scheduledBackupEval is a representation of tree.ScheduledBackup, prepared
+// for evaluation.
+type scheduledBackupEval struct {
+	*tree.ScheduledBackup
+
+	scheduleName         func() (string, error)
+	recurrence           func() (string, error)
+	fullBackupRecurrence func() (string, error)
+	destinations         func() ([]string, error)
+	backupOptions        func() (map[string]string, error)
+	scheduleOptions      func() (map[string]string, error)
+}
+
+func makeScheduledBackupEval(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledBackup,
+) (*scheduledBackupEval, error) {
+	const op = "CREATE SCHEDULE FOR BACKUP"
+	eval := &scheduledBackupEval{ScheduledBackup: schedule}
+	var err error
+
+	if schedule.ScheduleLabel != nil {
+		eval.scheduleName, err = p.TypeAsString(ctx, schedule.ScheduleLabel, op)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	eval.recurrence, err = p.TypeAsString(ctx, schedule.Recurrence, op)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.FullBackup != nil && !schedule.FullBackup.AlwaysFull {
+		eval.fullBackupRecurrence, err = p.TypeAsString(ctx, schedule.FullBackup.Recurrence, op)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	eval.destinations, err = p.TypeAsStringArray(ctx, tree.Exprs(schedule.To), op)
+	if err != nil {
+		return nil, err
+	}
+	eval.backupOptions, err = p.TypeAsStringOpts(ctx, schedule.BackupOptions, backupOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	eval.scheduleOptions, err = p.TypeAsStringOpts(
+		ctx, schedule.ScheduleOptions, scheduledBackupOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	return eval, nil
+}
+
+// makeScheduleDetails returns the schedule configuration specified by the
+// schedule options.
+func makeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error) {
+	var details jobspb.ScheduleDetails
+	if v, ok := opts[optOnExecFailure]; ok {
+		onError, ok := onExecFailureBehavior[v]
+		if !ok {
+			return details, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%q is not a valid value for %s; expected one of retry, reschedule or pause",
+				v, optOnExecFailure)
+		}
+		details.OnError = onError
+	}
+	if v, ok := opts[optOnPreviousRunning]; ok {
+		wait, ok := onPreviousRunningBehavior[v]
+		if !ok {
+			return details, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%q is not a valid value for %s; expected one of start, skip or wait",
+				v, optOnPreviousRunning)
+		}
+		details.Wait = wait
+	}
+	return details, nil
+}
+
+// makeScheduledBackupStatement returns the BACKUP statement run by the
+// schedule. The statement backs up into the collection URIs; the executor
+// replaces them with the subdirectory of each individual backup.
+func makeScheduledBackupStatement(
+	eval *scheduledBackupEval, destinations []string, backupOpts map[string]string,
+) *tree.Backup {
+	backupStmt := &tree.Backup{}
+	if eval.Targets == nil {
+		backupStmt.DescriptorCoverage = tree.AllDescriptors
+	} else {
+		backupStmt.Targets = *eval.Targets
+	}
+
+	for _, dest := range destinations {
+		backupStmt.To = append(backupStmt.To, tree.NewDString(dest))
+	}
+
+	// Scheduled backups always run detached: the executor creates the backup
+	// job in the scheduler's transaction.
+	backupOpts[backupOptDetached] = ""
+	keys := make([]string, 0, len(backupOpts))
+	for k := range backupOpts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		if v := backupOpts[k]; v != "" {
+			opt.Value = tree.NewDString(v)
+		}
+		backupStmt.Options = append(backupStmt.Options, opt)
+	}
+	return backupStmt
+}
+
+func doCreateBackupSchedule(
+	ctx context.Context, p sql.PlanHookState, eval *scheduledBackupEval, resultsCh chan<- tree.Datums,
+) error {
+	if err := utilccl.CheckEnterpriseEnabled(
+		p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(),
+		"CREATE SCHEDULE FOR BACKUP",
+	); err != nil {
+		return err
+	}
+
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionAddScheduledJobsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"CREATE SCHEDULE FOR BACKUP requires all nodes to be upgraded")
+	}
+
+	// Only admins may run BACKUP, and the schedule runs it as its owner.
+	if err := p.RequireAdminRole(ctx, "BACKUP"); err != nil {
+		return err
+	}
+
+	env := jobs.ProdJobSchedulerEnv
+	sj := jobs.NewScheduledJob(env)
+	sj.SetOwner(p.User())
+
+	name := tree.ScheduledBackupExecutor.UserName() + " schedule"
+	if eval.scheduleName != nil {
+		scheduleName, err := eval.scheduleName()
+		if err != nil {
+			return err
+		}
+		name = scheduleName
+	}
+	sj.SetScheduleName(name)
+
+	recurrence, err := eval.recurrence()
+	if err != nil {
+		return err
+	}
+	if err := sj.SetSchedule(recurrence); err != nil {
+		return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+	}
+
+	var fullBackupRecurrence string
+	if eval.fullBackupRecurrence != nil {
+		fullBackupRecurrence, err = eval.fullBackupRecurrence()
+		if err != nil {
+			return err
+		}
+		if _, err := cronexpr.Parse(fullBackupRecurrence); err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"parsing full backup schedule expression: %q", fullBackupRecurrence)
+		}
+	}
+
+	scheduleOpts, err := eval.scheduleOptions()
+	if err != nil {
+		return err
+	}
+	details, err := makeScheduleDetails(scheduleOpts)
+	if err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(details)
+
+	if v, ok := scheduleOpts[optFirstRun]; ok {
+		firstRun, err := tree.ParseDTimestampTZ(p.EvalContext(), v, time.Microsecond)
+		if err != nil {
+			return err
+		}
+		sj.SetNextRun(firstRun.Time)
+	}
+
+	destinations, err := eval.destinations()
+	if err != nil {
+		return err
+	}
+	backupOpts, err := eval.backupOptions()
+	if err != nil {
+		return err
+	}
+	backupStmt := makeScheduledBackupStatement(eval, destinations, backupOpts)
+
+	// Make sure the statement can be parsed back by the executor.
+	if _, err := parser.ParseOne(backupStmt.String()); err != nil {
+		return errors.Wrap(err, "invalid backup statement")
+	}
+
+	args := &ScheduledBackupExecutionArgs{
+		BackupStatement:      backupStmt.String(),
+		FullBackupRecurrence: fullBackupRecurrence,
+	}
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledBackupExecutor.InternalName(), jobspb.ExecutionArguments{Args: any})
+
+	if err := sj.Create(ctx, p.ExecCfg().InternalExecutor, p.ExtendedEvalContext().Txn); err != nil {
+		return err
+	}
+
+	telemetry.Count("backup.schedule.created")
+	if fullBackupRecurrence != "" {
+		telemetry.Count("backup.schedule.incremental")
+	}
+
+	status := "ACTIVE"
+	if sj.IsPaused() {
+		status = "PAUSED"
+	}
+	nextRun, err := tree.MakeDTimestampTZ(sj.NextRun(), time.Microsecond)
+	if err != nil {
+		return err
+	}
+	// Do not display credentials or the encryption passphrase.
+	description, err := backupJobDescription(p, backupStmt, destinations, nil /* incrementalFrom */, backupOpts)
+	if err != nil {
+		return err
+	}
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(sj.ScheduleID())),
+		tree.NewDString(name),
+		tree.NewDString(status),
+		nextRun,
+		tree.NewDString(recurrence),
+		tree.NewDString(description),
+	}
+	return nil
+}
+
+// createBackupScheduleHook implements sql.PlanHookFn.
+func createBackupScheduleHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	eval, err := makeScheduledBackupEval(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		return doCreateBackupSchedule(ctx, p, eval, resultsCh)
+	}
+	return fn, createScheduleHeader, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(createBackupScheduleHook)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBackupSchedule(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, 1, InitNone)
+	defer cleanupFn()
+
+	var scheduleID int64
+	sqlDB.QueryRow(t, `
+CREATE SCHEDULE 'nightly' FOR BACKUP DATABASE data INTO $1
+WITH revision_history RECURRING '@daily' FULL BACKUP '@weekly'
+WITH SCHEDULE OPTIONS on_execution_failure = 'pause', on_previous_running = 'skip'`,
+		LocalFoo,
+	).Scan(&scheduleID, new(string), new(string), new(time.Time), new(string), new(string))
+
+	var name, executorType, recurrence string
+	sqlDB.QueryRow(t,
+		`SELECT schedule_name, executor_type, schedule_expr FROM system.scheduled_jobs WHERE schedule_id = $1`,
+		scheduleID,
+	).Scan(&name, &executorType, &recurrence)
+	require.Equal(t, "nightly", name)
+	require.Equal(t, tree.ScheduledBackupExecutor.InternalName(), executorType)
+	require.Equal(t, "@daily", recurrence)
+
+	sqlDB.CheckQueryResults(t,
+		`SELECT label, schedule_status, recurrence FROM [SHOW SCHEDULES FOR BACKUP]`,
+		[][]string{{"nightly", "ACTIVE", "@daily"}})
+
+	sqlDB.Exec(t, `PAUSE SCHEDULE $1`, scheduleID)
+	sqlDB.CheckQueryResults(t,
+		`SELECT label FROM [SHOW PAUSED SCHEDULES]`, [][]string{{"nightly"}})
+	sqlDB.CheckQueryResults(t,
+		`SELECT label FROM [SHOW RUNNING SCHEDULES]`, [][]string{})
+
+	sqlDB.Exec(t, `RESUME SCHEDULE $1`, scheduleID)
+	sqlDB.CheckQueryResults(t,
+		`SELECT id, schedule_status FROM [SHOW SCHEDULE $1]`, [][]string{
+			{tree.NewDInt(tree.DInt(scheduleID)).String(), "ACTIVE"},
+		})
+
+	sqlDB.Exec(t, `DROP SCHEDULES SELECT id FROM [SHOW SCHEDULES] WHERE label = 'nightly'`)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM system.scheduled_jobs`, [][]string{{"0"}})
+
+	sqlDB.ExpectErr(t, `parsing schedule expression`,
+		`CREATE SCHEDULE FOR BACKUP INTO $1 RECURRING 'sometimes'`, LocalFoo)
+	sqlDB.ExpectErr(t, `parsing full backup schedule expression`,
+		`CREATE SCHEDULE FOR BACKUP INTO $1 RECURRING '@daily' FULL BACKUP 'never'`, LocalFoo)
+	sqlDB.ExpectErr(t, `not a valid value for on_execution_failure`,
+		`CREATE SCHEDULE FOR BACKUP INTO $1 RECURRING '@daily'
+		 WITH SCHEDULE OPTIONS on_execution_failure = 'ignore'`, LocalFoo)
+	sqlDB.ExpectErr(t, `invalid option "bogus"`,
+		`CREATE SCHEDULE FOR BACKUP INTO $1 WITH bogus RECURRING '@daily'`, LocalFoo)
+}
+
+func TestPlanScheduledBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	now := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	const stmt = `BACKUP DATABASE foo TO ('nodelocal://0/foo?COCKROACH_LOCALITY=default', 'nodelocal://0/bar?COCKROACH_LOCALITY=dc%3Ddc1') WITH detached`
+
+	// Without a completed full backup, the schedule takes a full backup and
+	// computes when the next one is due.
+	args := &ScheduledBackupExecutionArgs{
+		BackupStatement:      stmt,
+		FullBackupRecurrence: "@weekly",
+	}
+	backupStmt, err := planScheduledBackup(args, now)
+	require.NoError(t, err)
+	require.Equal(t,
+		`BACKUP DATABASE foo TO ('nodelocal://0/foo/2020/06/01-123000.00?COCKROACH_LOCALITY=default', `+
+			`'nodelocal://0/bar/2020/06/01-123000.00?COCKROACH_LOCALITY=dc%3Ddc1') WITH detached`,
+		backupStmt.String())
+	require.Equal(t, time.Date(2020, 6, 7, 0, 0, 0, 0, time.UTC).UnixNano(), args.NextFullBackup)
+
+	// Once the full backup completed, incremental backups are chained onto it.
+	args.FullBackupDir = "2020/06/01-123000.00"
+	args.IncrementalBackupDirs = []string{"2020/06/01-123000.00/incremental/2020/06/02-000000.00"}
+	backupStmt, err = planScheduledBackup(args, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t,
+		`BACKUP DATABASE foo TO (`+
+			`'nodelocal://0/foo/2020/06/01-123000.00/incremental/2020/06/03-123000.00?COCKROACH_LOCALITY=default', `+
+			`'nodelocal://0/bar/2020/06/01-123000.00/incremental/2020/06/03-123000.00?COCKROACH_LOCALITY=dc%3Ddc1') `+
+			`INCREMENTAL FROM 'nodelocal://0/foo/2020/06/01-123000.00?COCKROACH_LOCALITY=default', `+
+			`'nodelocal://0/foo/2020/06/01-123000.00/incremental/2020/06/02-000000.00?COCKROACH_LOCALITY=default' `+
+			`WITH detached`,
+		backupStmt.String())
+
+	// When the next full backup is due, the chain starts over.
+	backupStmt, err = planScheduledBackup(args, now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, backupStmt.IncrementalFrom)
+
+	// Without a full backup recurrence, every backup is a full backup.
+	args.FullBackupRecurrence = ""
+	backupStmt, err = planScheduledBackup(args, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, backupStmt.IncrementalFrom)
+
+	dir, err := relativeBackupDir(
+		"nodelocal://0/foo?COCKROACH_LOCALITY=default",
+		"nodelocal://0/foo/2020/06/01-123000.00/incremental/2020/06/03-123000.00?COCKROACH_LOCALITY=default")
+	require.NoError(t, err)
+	require.Equal(t, "2020/06/01-123000.00/incremental/2020/06/03-123000.00", dir)
+
+	_, err = relativeBackupDir("nodelocal://0/foo", "nodelocal://0/bar/2020/06/01-123000.00")
+	require.Error(t, err)
+}
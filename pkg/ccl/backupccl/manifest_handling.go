@@ -557,13 +557,22 @@ func resolveBackupManifests(
 	return defaultURIs, mainBackupManifests, localityInfo, nil
 }
 
+// loadSQLDescsFromBackupsAtTime returns the descriptors valid at the given
+// time along with the manifest of the backup they were found in. Without a time,
+// the descriptors of the last backup are returned.
+//
+// A table which was taken offline by a schema change in progress at the given
+// time, such as an IMPORT INTO, is returned as of its last public version
+// instead, along with the time just before it went offline in tableEndTimes.
+// The data of the table must be restored as of that time: the data written
+// while the table was offline was never visible and may be partial.
 func loadSQLDescsFromBackupsAtTime(
 	backupManifests []BackupManifest, asOf hlc.Timestamp,
-) ([]sqlbase.Descriptor, BackupManifest) {
+) (_ []sqlbase.Descriptor, _ BackupManifest, tableEndTimes map[sqlbase.ID]hlc.Timestamp) {
 	lastBackupManifest := backupManifests[len(backupManifests)-1]
 
 	if asOf.IsEmpty() {
-		return lastBackupManifest.Descriptors, lastBackupManifest
+		return lastBackupManifest.Descriptors, lastBackupManifest, nil
 	}
 
 	for _, b := range backupManifests {
@@ -573,7 +582,7 @@ func loadSQLDescsFromBackupsAtTime(
 		lastBackupManifest = b
 	}
 	if len(lastBackupManifest.DescriptorChanges) == 0 {
-		return lastBackupManifest.Descriptors, lastBackupManifest
+		return lastBackupManifest.Descriptors, lastBackupManifest, nil
 	}
 
 	byID := make(map[sqlbase.ID]*sqlbase.Descriptor, len(lastBackupManifest.Descriptors))
@@ -588,6 +597,20 @@ func loadSQLDescsFromBackupsAtTime(
 		}
 	}
 
+	for id, desc := range byID {
+		t := desc.Table(hlc.Timestamp{})
+		if t == nil || t.State != sqlbase.TableDescriptor_OFFLINE {
+			continue
+		}
+		if public, offlineSince, ok := lastPublicTableRevision(backupManifests, id, asOf); ok {
+			byID[id] = public
+			if tableEndTimes == nil {
+				tableEndTimes = make(map[sqlbase.ID]hlc.Timestamp)
+			}
+			tableEndTimes[id] = offlineSince.Prev()
+		}
+	}
+
 	allDescs := make([]sqlbase.Descriptor, 0, len(byID))
 	for _, desc := range byID {
 		if t := desc.Table(hlc.Timestamp{}); t != nil {
@@ -605,7 +628,39 @@ func loadSQLDescsFromBackupsAtTime(
 		}
 		allDescs = append(allDescs, *desc)
 	}
-	return allDescs, lastBackupManifest
+	return allDescs, lastBackupManifest, tableEndTimes
+}
+
+// lastPublicTableRevision searches the revision history of the backups for the
+// last public revision of the table at or before the given time, and returns it
+// along with the time of the revision which took the table out of the public
+// state. The revision history of a table may span several backups of a chain.
+func lastPublicTableRevision(
+	backupManifests []BackupManifest, id sqlbase.ID, asOf hlc.Timestamp,
+) (public *sqlbase.Descriptor, until hlc.Timestamp, ok bool) {
+	for _, b := range backupManifests {
+		if asOf.Less(b.StartTime) {
+			break
+		}
+		for _, rev := range b.DescriptorChanges {
+			if asOf.Less(rev.Time) {
+				break
+			}
+			if rev.ID != id {
+				continue
+			}
+			if rev.Desc != nil {
+				if t := rev.Desc.Table(hlc.Timestamp{}); t != nil && t.State == sqlbase.TableDescriptor_PUBLIC {
+					public, until = rev.Desc, hlc.Timestamp{}
+					continue
+				}
+			}
+			if public != nil && until.IsEmpty() {
+				until = rev.Time
+			}
+		}
+	}
+	return public, until, public != nil && !until.IsEmpty()
 }
 
 // sanitizeLocalityKV returns a sanitized version of the input string where all
@@ -581,11 +581,13 @@ func rewriteBackupSpanKey(kr *storageccl.KeyRewriter, key roachpb.Key) (roachpb.
 func restore(
 	restoreCtx context.Context,
 	db *kv.DB,
+	codec keys.SQLCodec,
 	numClusterNodes int,
 	settings *cluster.Settings,
 	backupManifests []BackupManifest,
 	backupLocalityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
 	endTime hlc.Timestamp,
+	tableEndTimes map[sqlbase.ID]hlc.Timestamp,
 	tables []sqlbase.TableDescriptorInterface,
 	oldTableIDs []sqlbase.ID,
 	spans []roachpb.Span,
@@ -706,6 +708,19 @@ func restore(
 			}
 			idx := readyForImportSpan.progressIdx
 
+			// The data of tables which were offline at the restore time is
+			// restored as of when they were last public.
+			spanEndTime := endTime
+			if len(tableEndTimes) > 0 {
+				_, oldID, err := codec.DecodeTablePrefix(readyForImportSpan.Span.Key)
+				if err != nil {
+					return err
+				}
+				if ts, ok := tableEndTimes[sqlbase.ID(oldID)]; ok {
+					spanEndTime = ts
+				}
+			}
+
 			importRequest := &roachpb.ImportRequest{
 				// Import is a point request because we don't want DistSender to split
 				// it. Assume (but don't require) the entire post-rewrite span is on the
@@ -713,7 +728,7 @@ func restore(
 				RequestHeader: roachpb.RequestHeader{Key: newSpanKey},
 				DataSpan:      readyForImportSpan.Span,
 				Files:         readyForImportSpan.files,
-				EndTime:       endTime,
+				EndTime:       spanEndTime,
 				Rekeys:        rekeys,
 				Encryption:    encryption,
 			}
@@ -773,20 +788,21 @@ func restore(
 }
 
 // loadBackupSQLDescs extracts the backup descriptors, the latest backup
-// descriptor, and all the Descriptors for a backup to be restored. It upgrades
-// the table descriptors to the new FK representation if necessary. FKs that
-// can't be restored because the necessary tables are missing are omitted; if
-// skip_missing_foreign_keys was set, we should have aborted the RESTORE and
-// returned an error prior to this.
+// descriptor, and all the Descriptors for a backup to be restored, along with
+// the times the data of tables which were offline at the restore time must be
+// restored as of. It upgrades the table descriptors to the new FK
+// representation if necessary. FKs that can't be restored because the
+// necessary tables are missing are omitted; if skip_missing_foreign_keys was
+// set, we should have aborted the RESTORE and returned an error prior to this.
 func loadBackupSQLDescs(
 	ctx context.Context,
 	p sql.PlanHookState,
 	details jobspb.RestoreDetails,
 	encryption *roachpb.FileEncryptionOptions,
-) ([]BackupManifest, BackupManifest, []sqlbase.Descriptor, error) {
+) ([]BackupManifest, BackupManifest, []sqlbase.Descriptor, map[sqlbase.ID]hlc.Timestamp, error) {
 	backupManifests, err := loadBackupManifests(ctx, details.URIs, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, encryption)
 	if err != nil {
-		return nil, BackupManifest{}, nil, err
+		return nil, BackupManifest{}, nil, nil, err
 	}
 
 	// Upgrade the table descriptors to use the new FK representation.
@@ -794,10 +810,12 @@ func loadBackupSQLDescs(
 	// writing old-style descs in RestoreDetails (unless a job persists across
 	// an upgrade?).
 	if err := maybeUpgradeTableDescsInBackupManifests(ctx, backupManifests, p.ExecCfg().Codec, true /* skipFKsWithNoMatchingTable */); err != nil {
-		return nil, BackupManifest{}, nil, err
+		return nil, BackupManifest{}, nil, nil, err
 	}
 
-	allDescs, latestBackupManifest := loadSQLDescsFromBackupsAtTime(backupManifests, details.EndTime)
+	allDescs, latestBackupManifest, tableEndTimes := loadSQLDescsFromBackupsAtTime(
+		backupManifests, details.EndTime,
+	)
 
 	var sqlDescs []sqlbase.Descriptor
 	for _, desc := range allDescs {
@@ -805,7 +823,7 @@ func loadBackupSQLDescs(
 			sqlDescs = append(sqlDescs, desc)
 		}
 	}
-	return backupManifests, latestBackupManifest, sqlDescs, nil
+	return backupManifests, latestBackupManifest, sqlDescs, tableEndTimes, nil
 }
 
 type restoreResumer struct {
@@ -968,6 +986,20 @@ func createImportingDescriptors(
 
 	if !details.PrepareCompleted {
 		err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			// The schedules deleting the expired rows of tables with row-level
+			// TTL are not backed up, so they are created for the new tables.
+			for _, desc := range tableDescs {
+				if desc.RowLevelTTL == nil {
+					continue
+				}
+				desc.RowLevelTTL.ScheduleID = 0
+				if err := sql.CreateRowLevelTTLSchedule(
+					ctx, p.ExecCfg().InternalExecutor, txn, desc,
+				); err != nil {
+					return err
+				}
+			}
+
 			// Write the new TableDescriptors which are set in the OFFLINE state.
 			if err := WriteDescriptors(ctx, txn, databases, tables, types, details.DescriptorCoverage, r.settings, nil /* extra */); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors from %d databases", len(r.tables), len(databases))
@@ -996,7 +1028,7 @@ func (r *restoreResumer) Resume(
 	details := r.job.Details().(jobspb.RestoreDetails)
 	p := phs.(sql.PlanHookState)
 
-	backupManifests, latestBackupManifest, sqlDescs, err := loadBackupSQLDescs(
+	backupManifests, latestBackupManifest, sqlDescs, tableEndTimes, err := loadBackupSQLDescs(
 		ctx, p, details, details.Encryption,
 	)
 	if err != nil {
@@ -1029,11 +1061,13 @@ func (r *restoreResumer) Resume(
 	res, err := restore(
 		ctx,
 		p.ExecCfg().DB,
+		p.ExecCfg().Codec,
 		numClusterNodes,
 		p.ExecCfg().Settings,
 		backupManifests,
 		details.BackupLocalityInfo,
 		details.EndTime,
+		tableEndTimes,
 		tables,
 		oldTableIDs,
 		spans,
@@ -1231,6 +1265,9 @@ func (r *restoreResumer) dropTables(ctx context.Context, jr *jobs.Registry, txn
 	tablesToGC := make([]sqlbase.ID, 0, len(details.TableDescs))
 	for _, tbl := range details.TableDescs {
 		tablesToGC = append(tablesToGC, tbl.ID)
+		if err := sql.DeleteRowLevelTTLSchedule(ctx, r.execCfg.InternalExecutor, txn, tbl); err != nil {
+			return errors.Wrap(err, "dropping tables caused by restore fail/cancel")
+		}
 		tableToDrop := sqlbase.NewMutableExistingTableDescriptor(*tbl)
 		tableToDrop.Version++
 		tableToDrop.State = sqlbase.TableDescriptor_DROP
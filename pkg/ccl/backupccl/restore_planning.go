@@ -12,7 +12,6 @@ import (
 	"context"
 	"sort"
 
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -45,6 +44,7 @@ const (
 	restoreOptSkipMissingFKs       = "skip_missing_foreign_keys"
 	restoreOptSkipMissingSequences = "skip_missing_sequences"
 	restoreOptSkipMissingViews     = "skip_missing_views"
+	restoreOptSkipMissingTriggers  = "skip_missing_triggers"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -56,7 +56,9 @@ var restoreOptionExpectValues = map[string]sql.KVStringOptValidate{
 	restoreOptSkipMissingFKs:       sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingSequences: sql.KVStringOptRequireNoValue,
 	restoreOptSkipMissingViews:     sql.KVStringOptRequireNoValue,
+	restoreOptSkipMissingTriggers:  sql.KVStringOptRequireNoValue,
 	backupOptEncPassphrase:         sql.KVStringOptRequireValue,
+	backupOptEncKMS:                sql.KVStringOptRequireValue,
 }
 
 // rewriteViewQueryDBNames rewrites the passed table's ViewQuery replacing all
@@ -218,6 +220,17 @@ func allocateDescriptorRewrites(
 				}
 			}
 		}
+
+		// Functions are not backed up, so the trigger functions are always
+		// missing.
+		if len(table.Triggers) > 0 {
+			if _, ok := opts[restoreOptSkipMissingTriggers]; !ok {
+				return nil, errors.Errorf(
+					"cannot restore table %q without the function of trigger %q (or %q option)",
+					table.Name, table.Triggers[0].Name, restoreOptSkipMissingTriggers,
+				)
+			}
+		}
 	}
 
 	// Include the type descriptors when calculating the max ID.
@@ -692,6 +705,13 @@ func RewriteTableDescs(
 				table.DependedOnBy = append(table.DependedOnBy, ref)
 			}
 		}
+		// Functions are not backed up, so the functions that depended on the
+		// table do not exist in the restoring cluster.
+		table.DependedOnByFunctions = nil
+		// For the same reason, the triggers of the table are dropped. To get
+		// here, the user must have specified 'skip_missing_triggers' --
+		// otherwise, would have errored out in allocateDescriptorRewrites.
+		table.Triggers = nil
 
 		// rewriteCol is a closure that performs the ID rewrite logic on a column.
 		rewriteCol := func(col *sqlbase.ColumnDescriptor) error {
@@ -857,9 +877,31 @@ func doRestorePlan(
 	if len(from) < 1 || len(from[0]) < 1 {
 		return errors.New("invalid base backup specified")
 	}
-	baseStores := make([]cloud.ExternalStorage, len(from[0]))
-	for i := range from[0] {
-		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, from[0][i])
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+
+	encParams, err := makeBackupEncryptionParams(opts, p.ExecCfg().Settings)
+	if err != nil {
+		return err
+	}
+
+	// A single location may be a collection of backups, in which case the
+	// chain of backups covering the requested time is restored.
+	backupFrom := from
+	if len(from) == 1 {
+		chainFrom, isCollection, err := resolveBackupCollection(
+			ctx, mkStore, from[0], endTime, encParams,
+		)
+		if err != nil {
+			return err
+		}
+		if isCollection {
+			backupFrom = chainFrom
+		}
+	}
+
+	baseStores := make([]cloud.ExternalStorage, len(backupFrom[0]))
+	for i := range backupFrom[0] {
+		store, err := mkStore(ctx, backupFrom[0][i])
 		if err != nil {
 			return errors.Wrapf(err, "failed to open backup storage location")
 		}
@@ -867,18 +909,13 @@ func doRestorePlan(
 		baseStores[i] = store
 	}
 
-	var encryption *roachpb.FileEncryptionOptions
-	if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-		opts, err := readEncryptionOptions(ctx, baseStores[0])
-		if err != nil {
-			return err
-		}
-		encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts.Salt)
-		encryption = &roachpb.FileEncryptionOptions{Key: encryptionKey}
+	_, encryption, err := encParams.readEncryptionKey(ctx, baseStores[0])
+	if err != nil {
+		return err
 	}
 
 	defaultURIs, mainBackupManifests, localityInfo, err := resolveBackupManifests(
-		ctx, baseStores, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, from, endTime, encryption,
+		ctx, baseStores, mkStore, backupFrom, endTime, encryption,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,260 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+)
+
+// scheduledBackupDirFormat is the format of the subdirectory, relative to
+// the collection URI, in which each scheduled backup is stored.
+const scheduledBackupDirFormat = "2006/01/02-150405.00"
+
+// incrementalBackupsDir is the subdirectory of a full backup under which the
+// incremental backups chained onto it are stored.
+const incrementalBackupsDir = "incremental"
+
+type scheduledBackupExecutor struct {
+	ex sqlutil.InternalExecutor
+}
+
+var _ jobs.ScheduledJobExecutor = &scheduledBackupExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledBackupExecutor) ExecuteJob(
+	ctx context.Context, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args := &ScheduledBackupExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+
+	backupStmt, err := planScheduledBackup(args, sj.Env().Now())
+	if err != nil {
+		return err
+	}
+
+	// The backup is detached: the job is created in the scheduler's
+	// transaction, and we do not wait for it to complete.
+	row, err := e.ex.QueryRowEx(ctx, "backup-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: sj.Owner()},
+		backupStmt.String(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(row) != 1 {
+		return errors.AssertionFailedf("expected a single job ID, found %d columns", len(row))
+	}
+	jobID := int64(tree.MustBeDInt(row[0]))
+
+	if err := jobs.MarkJobCreatedBySchedule(ctx, sj.Env(), jobID, sj.ScheduleID(), e.ex, txn); err != nil {
+		return err
+	}
+	return setScheduledBackupArgs(sj, args)
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledBackupExecutor) NotifyJobTermination(
+	ctx context.Context, md *jobs.JobMetadata, sj *jobs.ScheduledJob, _ *kv.Txn,
+) error {
+	switch md.Status {
+	case jobs.StatusSucceeded:
+	case jobs.StatusFailed:
+		err := errors.Newf("backup job failed: %s", md.Payload.Error)
+		sj.AddScheduleChangeReason("backup job %d failed: %s", md.ID, md.Payload.Error)
+		jobs.DefaultHandleFailedRun(sj, md.ID, err)
+		return nil
+	default:
+		sj.AddScheduleChangeReason("backup job %d terminated with status %s", md.ID, md.Status)
+		return nil
+	}
+
+	details := md.Payload.GetBackup()
+	if details == nil {
+		return errors.AssertionFailedf("job %d is not a backup job", md.ID)
+	}
+
+	args := &ScheduledBackupExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+	backupStmt, err := parseScheduledBackupStatement(args)
+	if err != nil {
+		return err
+	}
+	collections, err := scheduledBackupCollections(backupStmt)
+	if err != nil {
+		return err
+	}
+	dir, err := relativeBackupDir(collections[0], details.URI)
+	if err != nil {
+		return err
+	}
+
+	if details.StartTime.IsEmpty() {
+		// A full backup completed: subsequent incremental backups are chained
+		// onto it.
+		args.FullBackupDir = dir
+		args.IncrementalBackupDirs = nil
+	} else if args.FullBackupDir != "" &&
+		strings.HasPrefix(dir, path.Join(args.FullBackupDir, incrementalBackupsDir)+"/") {
+		// Ignore incremental backups chained onto a full backup that has since
+		// been superseded.
+		args.IncrementalBackupDirs = append(args.IncrementalBackupDirs, dir)
+	}
+	return setScheduledBackupArgs(sj, args)
+}
+
+// planScheduledBackup returns the BACKUP statement to run for the schedule
+// with the specified args at the specified time. The backup is a full backup
+// if there is no completed full backup yet, or if the next full backup is
+// due; otherwise, it is an incremental backup chained onto the last full
+// backup. The args are updated to reflect the planned backup.
+func planScheduledBackup(
+	args *ScheduledBackupExecutionArgs, now time.Time,
+) (*tree.Backup, error) {
+	backupStmt, err := parseScheduledBackupStatement(args)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := scheduledBackupCollections(backupStmt)
+	if err != nil {
+		return nil, err
+	}
+
+	fullBackup := args.FullBackupDir == "" || args.FullBackupRecurrence == "" ||
+		now.UnixNano() >= args.NextFullBackup
+
+	var dir string
+	if fullBackup {
+		dir = now.UTC().Format(scheduledBackupDirFormat)
+		if args.FullBackupRecurrence != "" {
+			expr, err := cronexpr.Parse(args.FullBackupRecurrence)
+			if err != nil {
+				return nil, errors.Wrapf(err,
+					"parsing full backup schedule expression: %q", args.FullBackupRecurrence)
+			}
+			args.NextFullBackup = expr.Next(now).UnixNano()
+		}
+	} else {
+		dir = path.Join(args.FullBackupDir, incrementalBackupsDir,
+			now.UTC().Format(scheduledBackupDirFormat))
+		for _, prev := range append([]string{args.FullBackupDir}, args.IncrementalBackupDirs...) {
+			uri, err := appendBackupDir(collections[0], prev)
+			if err != nil {
+				return nil, err
+			}
+			backupStmt.IncrementalFrom = append(backupStmt.IncrementalFrom, tree.NewDString(uri))
+		}
+	}
+
+	to := make(tree.PartitionedBackup, len(collections))
+	for i, collection := range collections {
+		uri, err := appendBackupDir(collection, dir)
+		if err != nil {
+			return nil, err
+		}
+		to[i] = tree.NewDString(uri)
+	}
+	backupStmt.To = to
+	return backupStmt, nil
+}
+
+// parseScheduledBackupStatement parses the BACKUP statement stored in the args.
+func parseScheduledBackupStatement(args *ScheduledBackupExecutionArgs) (*tree.Backup, error) {
+	stmt, err := parser.ParseOne(args.BackupStatement)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing schedule backup statement")
+	}
+	backupStmt, ok := stmt.AST.(*tree.Backup)
+	if !ok {
+		return nil, errors.Newf("unexpected statement %T in backup schedule", stmt.AST)
+	}
+	return backupStmt, nil
+}
+
+// scheduledBackupCollections returns the collection URIs the scheduled
+// backup statement writes to.
+func scheduledBackupCollections(backupStmt *tree.Backup) ([]string, error) {
+	collections := make([]string, len(backupStmt.To))
+	for i, to := range backupStmt.To {
+		s, ok := to.(*tree.StrVal)
+		if !ok {
+			return nil, errors.AssertionFailedf("unexpected backup destination %T", to)
+		}
+		collections[i] = s.RawString()
+	}
+	if len(collections) == 0 {
+		return nil, errors.AssertionFailedf("backup schedule has no destinations")
+	}
+	return collections, nil
+}
+
+// appendBackupDir returns the URI of the specified subdirectory of the
+// collection URI.
+func appendBackupDir(collection string, dir string) (string, error) {
+	uri, err := url.Parse(collection)
+	if err != nil {
+		return "", err
+	}
+	uri.Path = path.Join(uri.Path, dir)
+	return uri.String(), nil
+}
+
+// relativeBackupDir returns the subdirectory of the collection URI which
+// the specified backup URI refers to.
+func relativeBackupDir(collection string, backupURI string) (string, error) {
+	collectionURI, err := url.Parse(collection)
+	if err != nil {
+		return "", err
+	}
+	uri, err := url.Parse(backupURI)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.TrimSuffix(collectionURI.Path, "/") + "/"
+	if !strings.HasPrefix(uri.Path, prefix) {
+		return "", errors.Newf("backup %s is not stored in collection %s", backupURI, collection)
+	}
+	return strings.TrimPrefix(uri.Path, prefix), nil
+}
+
+func setScheduledBackupArgs(sj *jobs.ScheduledJob, args *ScheduledBackupExecutionArgs) error {
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(sj.ExecutorType(), jobspb.ExecutionArguments{Args: any})
+	return nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledBackupExecutor.InternalName(),
+		func(ex sqlutil.InternalExecutor) (jobs.ScheduledJobExecutor, error) {
+			return &scheduledBackupExecutor{ex: ex}, nil
+		})
+}
@@ -10,12 +10,11 @@ package backupccl
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
-	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -55,7 +54,9 @@ func showBackupPlanHook(
 
 	expected := map[string]sql.KVStringOptValidate{
 		backupOptEncPassphrase:  sql.KVStringOptRequireValue,
+		backupOptEncKMS:         sql.KVStringOptRequireValue,
 		backupOptWithPrivileges: sql.KVStringOptRequireNoValue,
+		backupOptRevisionTimes:  sql.KVStringOptRequireNoValue,
 	}
 	optsFn, err := p.TypeAsStringOpts(ctx, backup.Options, expected)
 	if err != nil {
@@ -66,12 +67,20 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	_, showRevisionTimes := opts[backupOptRevisionTimes]
+	if showRevisionTimes && (backup.Details != tree.BackupDefaultDetails || backup.ShouldIncludeSchemas) {
+		return nil, nil, nil, false, errors.Errorf(
+			"%s cannot be combined with SHOW BACKUP RANGES, FILES or SCHEMAS", backupOptRevisionTimes)
+	}
+
 	var shower backupShower
-	switch backup.Details {
-	case tree.BackupRangeDetails:
+	switch {
+	case backup.Details == tree.BackupRangeDetails:
 		shower = backupShowerRanges
-	case tree.BackupFileDetails:
+	case backup.Details == tree.BackupFileDetails:
 		shower = backupShowerFiles
+	case showRevisionTimes:
+		shower = backupShowerRevisionTimes
 	default:
 		shower = backupShowerDefault(ctx, p, backup.ShouldIncludeSchemas, opts)
 	}
@@ -86,48 +95,68 @@ func showBackupPlanHook(
 			return err
 		}
 
-		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, str)
+		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+		store, err := mkStore(ctx, str)
 		if err != nil {
 			return errors.Wrapf(err, "make storage")
 		}
 		defer store.Close()
 
-		var encryption *roachpb.FileEncryptionOptions
-		if passphrase, ok := opts[backupOptEncPassphrase]; ok {
-			opts, err := readEncryptionOptions(ctx, store)
+		encParams, err := makeBackupEncryptionParams(opts, p.ExecCfg().Settings)
+		if err != nil {
+			return err
+		}
+
+		// The restorable windows of a collection are those of all its backups.
+		var manifests []BackupManifest
+		if showRevisionTimes {
+			fullDirs, err := listCollectionFullBackups(ctx, store)
 			if err != nil {
 				return err
 			}
-			encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts.Salt)
-			encryption = &roachpb.FileEncryptionOptions{Key: encryptionKey}
+			for _, dir := range fullDirs {
+				chain, err := loadBackupChain(ctx, mkStore, str, dir, encParams,
+					true /* withIncrementals */)
+				if err != nil {
+					return err
+				}
+				manifests = append(manifests, chain.manifests...)
+			}
 		}
 
-		incPaths, err := findPriorBackups(ctx, store)
-		if err != nil {
-			if errors.Is(err, cloud.ErrListingUnsupported) {
-				// If we do not support listing, we have to just assume there are none
-				// and show the specified base.
-				log.Warningf(ctx, "storage sink %T does not support listing, only resolving the base backup", store)
-				incPaths = nil
-			} else {
+		if len(manifests) == 0 {
+			_, encryption, err := encParams.readEncryptionKey(ctx, store)
+			if err != nil {
 				return err
 			}
-		}
 
-		manifests := make([]BackupManifest, len(incPaths)+1)
-		manifests[0], err = readBackupManifestFromStore(ctx, store, encryption)
-		if err != nil {
-			return err
-		}
+			incPaths, err := findPriorBackups(ctx, store)
+			if err != nil {
+				if errors.Is(err, cloud.ErrListingUnsupported) {
+					// If we do not support listing, we have to just assume there are none
+					// and show the specified base.
+					log.Warningf(ctx, "storage sink %T does not support listing, only resolving the base backup", store)
+					incPaths = nil
+				} else {
+					return err
+				}
+			}
 
-		for i := range incPaths {
-			m, err := readBackupManifest(ctx, store, incPaths[i], encryption)
+			manifests = make([]BackupManifest, len(incPaths)+1)
+			manifests[0], err = readBackupManifestFromStore(ctx, store, encryption)
 			if err != nil {
 				return err
 			}
-			// Blank the stats to prevent memory blowup.
-			m.Statistics = nil
-			manifests[i+1] = m
+
+			for i := range incPaths {
+				m, err := readBackupManifest(ctx, store, incPaths[i], encryption)
+				if err != nil {
+					return err
+				}
+				// Blank the stats to prevent memory blowup.
+				m.Statistics = nil
+				manifests[i+1] = m
+			}
 		}
 
 		// If we are restoring a backup with old-style foreign keys, skip over the
@@ -350,3 +379,45 @@ var backupShowerFiles = backupShower{
 func init() {
 	sql.AddPlanHook(showBackupPlanHook)
 }
+
+// backupShowerRevisionTimes lists the windows of time each backup can be
+// restored to. A backup taken with revision_history can be restored to any
+// time after the start of its revision history and up to its end time; other
+// backups only to their end time, in which case the window is a single
+// instant.
+var backupShowerRevisionTimes = backupShower{
+	header: sqlbase.ResultColumns{
+		{Name: "start_time", Typ: types.Timestamp},
+		{Name: "end_time", Typ: types.Timestamp},
+		{Name: "revision_history", Typ: types.Bool},
+	},
+
+	fn: func(manifests []BackupManifest) (rows []tree.Datums, err error) {
+		sort.SliceStable(manifests, func(i, j int) bool {
+			return manifests[i].EndTime.Less(manifests[j].EndTime)
+		})
+		for _, manifest := range manifests {
+			end, err := tree.MakeDTimestamp(timeutil.Unix(0, manifest.EndTime.WallTime), time.Nanosecond)
+			if err != nil {
+				return nil, err
+			}
+			var start tree.Datum = end
+			revisionHistory := manifest.MVCCFilter == MVCCFilter_All
+			if revisionHistory {
+				from := manifest.StartTime
+				if from.Less(manifest.RevisionStartTime) {
+					from = manifest.RevisionStartTime
+				}
+				start = tree.DNull
+				if from.WallTime != 0 {
+					start, err = tree.MakeDTimestamp(timeutil.Unix(0, from.WallTime), time.Nanosecond)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			rows = append(rows, tree.Datums{start, end, tree.MakeDBool(tree.DBool(revisionHistory))})
+		}
+		return rows, nil
+	},
+}
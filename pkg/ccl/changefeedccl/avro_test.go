@@ -306,6 +306,8 @@ func TestAvroSchema(t *testing.T) {
 			`TIMETZ`:       `["null","string"]`,
 			`TIMESTAMP`:    `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
 			`TIMESTAMPTZ`:  `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
+			`TSQUERY`:      `["null","string"]`,
+			`TSVECTOR`:     `["null","string"]`,
 			`UUID`:         `["null","string"]`,
 			`DECIMAL(3,2)`: `["null",{"type":"bytes","logicalType":"decimal","precision":3,"scale":2}]`,
 		}
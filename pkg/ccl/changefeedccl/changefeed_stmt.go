@@ -195,14 +195,15 @@ func changefeedPlanHook(
 		//   and `format` if the user didn't specify them.
 		// - Then `getEncoder` is run to return any configuration errors.
 		// - Then the changefeed is opted in to `OptKeyInValue` for any cloud
-		//   storage sink. Kafka etc have a key and value field in each message but
-		//   cloud storage sinks don't have anywhere to put the key. So if the key
-		//   is not in the value, then for DELETEs there is no way to recover which
-		//   key was deleted. We could make the user explicitly pass this option for
-		//   every cloud storage sink and error if they don't, but that seems
-		//   user-hostile for insufficient reason. We can't do this any earlier,
-		//   because we might return errors about `key_in_value` being incompatible
-		//   which is confusing when the user didn't type that option.
+		//   storage or webhook sink. Kafka etc have a key and value field in each
+		//   message but cloud storage and webhook sinks don't have anywhere to put
+		//   the key. So if the key is not in the value, then for DELETEs there is
+		//   no way to recover which key was deleted. We could make the user
+		//   explicitly pass this option for every cloud storage or webhook sink
+		//   and error if they don't, but that seems user-hostile for insufficient
+		//   reason. We can't do this any earlier, because we might return errors
+		//   about `key_in_value` being incompatible which is confusing when the
+		//   user didn't type that option.
 		// - Finally, we create a "canary" sink to test sink configuration and
 		//   connectivity. This has to go last because it is strange to return sink
 		//   connectivity errors before we've finished validating all the other
@@ -223,7 +224,7 @@ func changefeedPlanHook(
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
-		if isCloudStorageSink(parsedSink) {
+		if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
 
@@ -492,11 +493,21 @@ func validateChangefeedTable(
 	if tableDesc.IsSequence() {
 		return errors.Errorf(`CHANGEFEED cannot target sequences: %s`, tableDesc.Name)
 	}
+	if tableDesc.IsForeignTable() {
+		return errors.Errorf(`CHANGEFEED cannot target foreign tables: %s`, tableDesc.Name)
+	}
 	if len(tableDesc.Families) != 1 {
 		return errors.Errorf(
 			`CHANGEFEEDs are currently supported on tables with exactly 1 column family: %s has %d`,
 			tableDesc.Name, len(tableDesc.Families))
 	}
+	for i := range tableDesc.Columns {
+		if col := &tableDesc.Columns[i]; col.IsVirtual() {
+			return errors.Errorf(
+				`CHANGEFEEDs are not supported on tables with virtual computed columns: %s has %s`,
+				tableDesc.Name, col.Name)
+		}
+	}
 
 	if tableDesc.State == sqlbase.TableDescriptor_DROP {
 		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
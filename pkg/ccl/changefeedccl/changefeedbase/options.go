@@ -86,10 +86,14 @@ const (
 	SinkSchemeBuffer          = ``
 	SinkSchemeExperimentalSQL = `experimental-sql`
 	SinkSchemeKafka           = `kafka`
+	SinkSchemeWebhookHTTPS    = `webhook-https`
 	SinkParamSASLEnabled      = `sasl_enabled`
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
 	SinkParamSASLPassword     = `sasl_password`
+	SinkParamBatchSize        = `batch_size`
+	SinkParamFlushInterval    = `flush_interval`
+	SinkParamHeader           = `header`
 )
 
 // ChangefeedOptionExpectValues is used to parse changefeed options using
@@ -0,0 +1,431 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvfeed"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// replicationKeepaliveInterval is the interval at which keepalive messages
+// are sent to the clients of logical replication streams, and at which the
+// positions confirmed by the clients are recorded in their replication slots.
+var replicationKeepaliveInterval = 10 * time.Second
+
+func init() {
+	sql.StartReplicationCCL = runReplicationStream
+}
+
+// runReplicationStream runs a logical replication stream started with
+// START_REPLICATION. The changes to the published tables are read with a
+// kvfeed, like the changes of a changefeed, and are sent to the client as
+// pgoutput messages.
+//
+// The rows changed at the same wall time are sent as one transaction, whose
+// LSN is that wall time, once all the spans of the tables are resolved past
+// it. This means that the transactions of the stream do not necessarily match
+// the SQL transactions: the changes of concurrent transactions committed at
+// the same wall time are sent together.
+func runReplicationStream(
+	ctx context.Context, execCfg *sql.ExecutorConfig, spec sql.ReplicationStreamSpec,
+) error {
+	// The stream starts with the changes committed after StartLSN.
+	startTS := hlc.Timestamp{WallTime: int64(spec.StartLSN) + 1}.Prev()
+
+	targets, spans, err := fetchReplicationTargets(ctx, execCfg, spec.TableIDs, startTS)
+	if err != nil {
+		return err
+	}
+
+	s := &replicationStream{
+		execCfg:   execCfg,
+		spec:      spec,
+		frontier:  span.MakeFrontier(spans...),
+		pending:   make(map[int64][]encodeRow),
+		relations: make(map[sqlbase.ID]sqlbase.DescriptorVersion),
+		sent:      spec.StartLSN,
+		confirmed: spec.StartLSN,
+		recorded:  spec.StartLSN,
+	}
+	for _, sp := range spans {
+		s.frontier.Forward(sp, startTS)
+	}
+
+	if err := spec.Conn.BeginReplication(ctx, spec.DataConversion); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g := ctxgroup.WithContext(ctx)
+	entriesCh := make(chan []emitEntry)
+	if len(spans) > 0 {
+		mm := mon.MakeMonitor("replication-stream", mon.MemoryResource,
+			nil /* curCount */, nil /* maxHist */, -1 /* increment */, math.MaxInt64, execCfg.Settings)
+		mm.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(kvfeed.MemBufferDefaultCapacity))
+		defer mm.Stop(ctx)
+
+		metrics := execCfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
+		buf := kvfeed.MakeChanBuffer()
+		kvfeedCfg := kvfeed.Config{
+			Sink:               buf,
+			Settings:           execCfg.Settings,
+			DB:                 execCfg.DB,
+			Clock:              execCfg.Clock,
+			Gossip:             execCfg.Gossip,
+			Spans:              spans,
+			Targets:            targets,
+			LeaseMgr:           execCfg.LeaseManager,
+			Metrics:            &metrics.KVFeedMetrics,
+			MM:                 &mm,
+			InitialHighWater:   startTS,
+			WithDiff:           true,
+			SchemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			SchemaChangePolicy: changefeedbase.OptSchemaChangePolicyNoBackfill,
+		}
+		details := jobspb.ChangefeedDetails{
+			Targets: targets,
+			Opts:    map[string]string{changefeedbase.OptDiff: ""},
+		}
+		rowsFn := kvsToRows(execCfg.Codec, execCfg.LeaseManager, details, buf.Get)
+		g.GoCtx(func(ctx context.Context) error {
+			return kvfeed.Run(ctx, kvfeedCfg)
+		})
+		g.GoCtx(func(ctx context.Context) error {
+			for {
+				entries, err := rowsFn(ctx)
+				if err != nil {
+					return err
+				}
+				// The slice returned by rowsFn is reused by the next call.
+				entries = append([]emitEntry(nil), entries...)
+				select {
+				case entriesCh <- entries:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		})
+	}
+
+	runErr := s.run(ctx, entriesCh)
+	cancel()
+	if err := g.Wait(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return runErr
+}
+
+// fetchReplicationTargets returns the targets and the spans watched by a
+// replication stream. Like the targets of changefeeds, the tables must have a
+// single column family, and the stream fails if they are renamed.
+func fetchReplicationTargets(
+	ctx context.Context, execCfg *sql.ExecutorConfig, tableIDs []sqlbase.ID, ts hlc.Timestamp,
+) (jobspb.ChangefeedTargets, []roachpb.Span, error) {
+	var targets jobspb.ChangefeedTargets
+	var spans []roachpb.Span
+	err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		targets = make(jobspb.ChangefeedTargets, len(tableIDs))
+		spans = spans[:0]
+		txn.SetFixedTimestamp(ctx, ts)
+		for _, id := range tableIDs {
+			tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, execCfg.Codec, id)
+			if err != nil {
+				return err
+			}
+			targets[id] = jobspb.ChangefeedTarget{StatementTimeName: tableDesc.Name}
+			if err := changefeedbase.ValidateTable(targets, tableDesc); err != nil {
+				return err
+			}
+			spans = append(spans, tableDesc.PrimaryIndexSpan(execCfg.Codec))
+		}
+		return nil
+	})
+	return targets, spans, err
+}
+
+// replicationStream is the state of a logical replication stream.
+type replicationStream struct {
+	execCfg *sql.ExecutorConfig
+	spec    sql.ReplicationStreamSpec
+
+	// frontier tracks the resolved timestamps of the spans of the tables.
+	frontier *span.Frontier
+	// pending contains the changed rows that haven't been sent yet, by the
+	// wall time of their change.
+	pending map[int64][]encodeRow
+	// relations contains the version of the descriptor last described to the
+	// client of each table.
+	relations map[sqlbase.ID]sqlbase.DescriptorVersion
+	// sent is the LSN of the last transaction sent to the client.
+	sent tree.LSN
+	// xid is the counter used as the IDs of the transactions of the stream.
+	xid uint32
+
+	// confirmed is the last position flushed by the client, and recorded the
+	// last position recorded in the replication slot.
+	confirmed, recorded tree.LSN
+}
+
+// run sends the changes read from entriesCh to the client, until the client
+// ends the stream.
+func (s *replicationStream) run(ctx context.Context, entriesCh <-chan []emitEntry) error {
+	ticker := time.NewTicker(replicationKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case entries := <-entriesCh:
+			if err := s.handleEntries(ctx, entries); err != nil {
+				return err
+			}
+		case status, ok := <-s.spec.Feedback:
+			if !ok {
+				// The client ended the stream.
+				if err := s.recordConfirmed(ctx); err != nil {
+					return err
+				}
+				return s.spec.Conn.EndReplication(ctx)
+			}
+			if status.FlushedLSN > s.confirmed {
+				s.confirmed = status.FlushedLSN
+			}
+			if status.ReplyRequested {
+				if err := s.spec.Conn.SendKeepalive(ctx, s.walEnd(), false /* replyRequested */); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := s.recordConfirmed(ctx); err != nil {
+				return err
+			}
+			if err := s.spec.Conn.SendKeepalive(ctx, s.walEnd(), false /* replyRequested */); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// walEnd returns the position up to which all the changes have been sent.
+func (s *replicationStream) walEnd() tree.LSN {
+	resolved := s.frontier.Frontier()
+	if len(s.spec.TableIDs) == 0 {
+		// Without tables, there is never anything to send.
+		resolved = s.execCfg.Clock.Now()
+	}
+	if end := tree.LSN(resolved.WallTime - 1); end > s.sent {
+		return end
+	}
+	return s.sent
+}
+
+// recordConfirmed advances the replication slot to the last position flushed
+// by the client.
+func (s *replicationStream) recordConfirmed(ctx context.Context) error {
+	if s.confirmed <= s.recorded {
+		return nil
+	}
+	if err := sql.AdvanceReplicationSlot(
+		ctx, s.execCfg, s.spec.Slot, s.confirmed, s.spec.TableIDs,
+	); err != nil {
+		return err
+	}
+	s.recorded = s.confirmed
+	return nil
+}
+
+// handleEntries buffers the changed rows, and sends the transactions that
+// can't receive more changes once the spans are resolved.
+func (s *replicationStream) handleEntries(ctx context.Context, entries []emitEntry) error {
+	resolved := false
+	for _, e := range entries {
+		if e.resolved != nil {
+			if s.frontier.Forward(e.resolved.Span, e.resolved.Timestamp) {
+				resolved = true
+			}
+			continue
+		}
+		// Rows at or below the frontier were already sent, or precede the
+		// start of the stream.
+		if !s.frontier.Frontier().Less(e.row.updated) {
+			continue
+		}
+		wall := e.row.updated.WallTime
+		s.pending[wall] = append(s.pending[wall], e.row)
+	}
+	if !resolved {
+		return nil
+	}
+
+	frontier := s.frontier.Frontier().WallTime
+	var walls []int64
+	for wall := range s.pending {
+		if wall < frontier {
+			walls = append(walls, wall)
+		}
+	}
+	sort.Slice(walls, func(i, j int) bool { return walls[i] < walls[j] })
+	for _, wall := range walls {
+		if err := s.sendTxn(ctx, wall, s.pending[wall]); err != nil {
+			return err
+		}
+		delete(s.pending, wall)
+	}
+	return nil
+}
+
+// sendTxn sends the rows changed at the given wall time as a transaction.
+func (s *replicationStream) sendTxn(ctx context.Context, wall int64, rows []encodeRow) error {
+	lsn := tree.LSN(wall)
+	commitTime := timeutil.Unix(0, wall)
+	s.xid++
+	if err := s.spec.Conn.SendBegin(ctx, lsn, commitTime, s.xid); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := s.sendRow(ctx, lsn, row); err != nil {
+			return err
+		}
+	}
+	if err := s.spec.Conn.SendCommit(ctx, lsn, commitTime); err != nil {
+		return err
+	}
+	s.sent = lsn
+	return nil
+}
+
+// sendRow sends a changed row, preceded by the description of its table if
+// the client doesn't know the current version of the table yet.
+func (s *replicationStream) sendRow(ctx context.Context, lsn tree.LSN, row encodeRow) error {
+	desc := row.tableDesc
+	if v, ok := s.relations[desc.ID]; !ok || v != desc.Version {
+		rel, err := s.makeRelation(ctx, desc, row.updated)
+		if err != nil {
+			return err
+		}
+		if err := s.spec.Conn.SendRelation(ctx, lsn, rel); err != nil {
+			return err
+		}
+		s.relations[desc.ID] = desc.Version
+	}
+
+	relID := uint32(desc.ID)
+	switch {
+	case row.deleted:
+		if row.prevDeleted {
+			// The row didn't exist before.
+			return nil
+		}
+		oldRow, err := replicatedDatums(row.prevTableDesc, row.prevDatums)
+		if err != nil {
+			return err
+		}
+		return s.spec.Conn.SendDelete(ctx, lsn, relID, oldRow)
+	case row.prevDeleted:
+		newRow, err := replicatedDatums(desc, row.datums)
+		if err != nil {
+			return err
+		}
+		return s.spec.Conn.SendInsert(ctx, lsn, relID, newRow)
+	default:
+		newRow, err := replicatedDatums(desc, row.datums)
+		if err != nil {
+			return err
+		}
+		// The old row is only sent if it has the same columns as the new one.
+		var oldRow tree.Datums
+		if row.prevTableDesc.Version == desc.Version {
+			if oldRow, err = replicatedDatums(row.prevTableDesc, row.prevDatums); err != nil {
+				return err
+			}
+		}
+		return s.spec.Conn.SendUpdate(ctx, lsn, relID, oldRow, newRow)
+	}
+}
+
+// makeRelation describes a table to the client, as of the given timestamp.
+func (s *replicationStream) makeRelation(
+	ctx context.Context, desc *sqlbase.TableDescriptor, ts hlc.Timestamp,
+) (*pgwirebase.ReplicationRelation, error) {
+	var scName string
+	if err := s.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		txn.SetFixedTimestamp(ctx, ts)
+		var err error
+		scName, err = resolver.ResolveSchemaNameByID(
+			ctx, txn, s.execCfg.Codec, desc.ParentID, desc.GetParentSchemaID(),
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	rel := &pgwirebase.ReplicationRelation{
+		ID:        uint32(desc.ID),
+		Namespace: scName,
+		Name:      desc.Name,
+	}
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if !isReplicatedColumn(col) {
+			continue
+		}
+		rel.Columns = append(rel.Columns, pgwirebase.ReplicationColumn{
+			Name: col.Name,
+			Type: col.Type,
+			Key:  desc.PrimaryIndex.ContainsColumnID(col.ID),
+		})
+	}
+	return rel, nil
+}
+
+// isReplicatedColumn returns whether the values of a column are replicated.
+// Like in Postgres, the values of generated columns which are not stored are
+// not replicated. Hidden columns are not replicated either.
+func isReplicatedColumn(col *sqlbase.ColumnDescriptor) bool {
+	return !col.Hidden && !col.Virtual
+}
+
+// replicatedDatums decodes the values of the replicated columns of a row.
+func replicatedDatums(
+	desc *sqlbase.TableDescriptor, row sqlbase.EncDatumRow,
+) (tree.Datums, error) {
+	var a sqlbase.DatumAlloc
+	datums := make(tree.Datums, 0, len(row))
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if !isReplicatedColumn(col) {
+			continue
+		}
+		if err := row[i].EnsureDecoded(col.Type, &a); err != nil {
+			return nil, err
+		}
+		datums = append(datums, row[i].Datum)
+	}
+	return datums, nil
+}
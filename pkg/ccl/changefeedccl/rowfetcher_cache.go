@@ -113,6 +113,7 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 		c.codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&c.a,
@@ -175,6 +175,19 @@ func getSink(
 		makeSink = func() (Sink, error) {
 			return makeKafkaSink(cfg, u.Host, targets)
 		}
+	case isWebhookSink(u):
+		cfg, err := parseWebhookSinkParams(q, opts)
+		if err != nil {
+			return nil, err
+		}
+		// The endpoint is the sink URI with the `webhook-` prefix and all of
+		// the sink parameters stripped.
+		endpoint := *u
+		endpoint.Scheme = strings.TrimPrefix(u.Scheme, `webhook-`)
+		endpoint.RawQuery = ``
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(cfg, endpoint.String())
+		}
 	case isCloudStorageSink(u):
 		fileSizeParam := q.Get(changefeedbase.SinkParamFileSize)
 		q.Del(changefeedbase.SinkParamFileSize)
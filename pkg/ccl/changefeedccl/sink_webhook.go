@@ -0,0 +1,509 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = time.Second
+	webhookRequestTimeout       = 30 * time.Second
+	// webhookEventBufferSize bounds the number of messages that may be queued
+	// for the worker before EmitRow starts blocking.
+	webhookEventBufferSize = 1024
+)
+
+func isWebhookSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS
+}
+
+type webhookSinkConfig struct {
+	// batchSize is the maximum number of rows sent in a single request.
+	batchSize int
+	// flushInterval is the longest a row waits in a partially filled batch
+	// before the batch is sent. Zero means partial batches are only sent on
+	// Flush or when a resolved timestamp is emitted.
+	flushInterval time.Duration
+	headers       http.Header
+	caCert        []byte
+	clientCert    []byte
+	clientKey     []byte
+	retryOpts     retry.Options
+}
+
+func defaultWebhookRetryOptions() retry.Options {
+	return retry.Options{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		MaxRetries:     10,
+	}
+}
+
+// parseWebhookSinkParams consumes the webhook sink query parameters from q
+// and returns the resulting sink configuration.
+func parseWebhookSinkParams(q url.Values, opts map[string]string) (webhookSinkConfig, error) {
+	cfg := webhookSinkConfig{
+		batchSize:     defaultWebhookBatchSize,
+		flushInterval: defaultWebhookFlushInterval,
+		headers:       make(http.Header),
+		retryOpts:     defaultWebhookRetryOptions(),
+	}
+
+	// Each request body is a JSON array of the emitted values, so only
+	// formats that produce a JSON value for every row are supported.
+	if format := changefeedbase.FormatType(opts[changefeedbase.OptFormat]); format != `` &&
+		format != changefeedbase.OptFormatJSON {
+		return cfg, errors.Errorf(`%s sink requires %s=%s`,
+			changefeedbase.SinkSchemeWebhookHTTPS, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+	if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeKeyOnly {
+		return cfg, errors.Errorf(`%s sink does not support %s=%s`,
+			changefeedbase.SinkSchemeWebhookHTTPS, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeKeyOnly)
+	}
+
+	if batchSizeParam := q.Get(changefeedbase.SinkParamBatchSize); batchSizeParam != `` {
+		batchSize, err := strconv.Atoi(batchSizeParam)
+		if err != nil || batchSize <= 0 {
+			return cfg, errors.Errorf(`param %s must be a positive integer: %s`,
+				changefeedbase.SinkParamBatchSize, batchSizeParam)
+		}
+		cfg.batchSize = batchSize
+	}
+	q.Del(changefeedbase.SinkParamBatchSize)
+
+	if flushIntervalParam := q.Get(changefeedbase.SinkParamFlushInterval); flushIntervalParam != `` {
+		flushInterval, err := time.ParseDuration(flushIntervalParam)
+		if err != nil {
+			return cfg, errors.Wrapf(err, `param %s must be a duration`, changefeedbase.SinkParamFlushInterval)
+		}
+		if flushInterval < 0 {
+			return cfg, errors.Errorf(`param %s must not be negative: %s`,
+				changefeedbase.SinkParamFlushInterval, flushIntervalParam)
+		}
+		cfg.flushInterval = flushInterval
+	}
+	q.Del(changefeedbase.SinkParamFlushInterval)
+
+	for _, header := range q[changefeedbase.SinkParamHeader] {
+		parts := strings.SplitN(header, `:`, 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == `` {
+			return cfg, errors.Errorf(`param %s must be of the form "Name: value": %s`,
+				changefeedbase.SinkParamHeader, header)
+		}
+		cfg.headers.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	q.Del(changefeedbase.SinkParamHeader)
+
+	var err error
+	if cfg.caCert, err = decodeBase64SinkParam(q, changefeedbase.SinkParamCACert); err != nil {
+		return cfg, err
+	}
+	if cfg.clientCert, err = decodeBase64SinkParam(q, changefeedbase.SinkParamClientCert); err != nil {
+		return cfg, err
+	}
+	if cfg.clientKey, err = decodeBase64SinkParam(q, changefeedbase.SinkParamClientKey); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// decodeBase64SinkParam consumes the given base 64 encoded query parameter
+// from q and returns its decoded value, or nil if it was not specified.
+func decodeBase64SinkParam(q url.Values, param string) ([]byte, error) {
+	encoded := q.Get(param)
+	q.Del(param)
+	if encoded == `` {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, param, err)
+	}
+	return decoded, nil
+}
+
+func makeWebhookTLSConfig(cfg webhookSinkConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if cfg.caCert != nil {
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`invalid %s data provided`, changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		if cfg.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`,
+				changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if cfg.clientKey != nil {
+		return nil, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	return tlsConfig, nil
+}
+
+// webhookEvent is a unit of work for the webhookSink worker.
+type webhookEvent struct {
+	// payload is a row value or, if resolved is set, an encoded resolved
+	// timestamp.
+	payload  []byte
+	resolved bool
+	// flush asks the worker to send any partially filled batch. It carries
+	// no payload.
+	flush bool
+}
+
+// webhookSink emits to an HTTPS endpoint by POSTing batches of rows encoded
+// as a JSON array of row values. Resolved timestamps are POSTed individually
+// as the JSON object produced by the encoder.
+//
+// All requests are issued one at a time, in order, by a single worker
+// goroutine, and a request is only issued once every earlier request has been
+// acknowledged with a 2xx response. This guarantees that a resolved timestamp
+// is not delivered until every row emitted before it has been delivered.
+//
+// The first request that fails permanently stops the worker, so nothing
+// emitted after the failed request is delivered, and the error is returned by
+// every subsequent call to Emit and Flush.
+//
+// Like kafkaSink, it is not concurrency-safe; all calls to Emit and Flush
+// should be from the same goroutine.
+type webhookSink struct {
+	cfg    webhookSinkConfig
+	url    string
+	client *httputil.Client
+
+	eventCh chan webhookEvent
+
+	// workerCtx is canceled by Close to abort any in-progress request, and by
+	// the worker when it stops after a failed request.
+	workerCtx    context.Context
+	cancelWorker func()
+	worker       sync.WaitGroup
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		// err is the error the worker stopped with, if any.
+		err     error
+		flushCh chan struct{}
+	}
+}
+
+var _ Sink = (*webhookSink)(nil)
+
+func makeWebhookSink(cfg webhookSinkConfig, sinkURL string) (Sink, error) {
+	tlsConfig, err := makeWebhookTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := &httputil.Client{Client: &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}}
+	sink := &webhookSink{
+		cfg:     cfg,
+		url:     sinkURL,
+		client:  client,
+		eventCh: make(chan webhookEvent, webhookEventBufferSize),
+	}
+	sink.start()
+	return sink, nil
+}
+
+func (s *webhookSink) start() {
+	s.workerCtx, s.cancelWorker = context.WithCancel(context.Background())
+	s.worker.Add(1)
+	go s.workerLoop()
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	// Outstanding messages are dropped, which is allowed by the Sink
+	// interface.
+	s.cancelWorker()
+	s.worker.Wait()
+	if transport, ok := s.client.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, _ *sqlbase.TableDescriptor, _, value []byte, _ hlc.Timestamp,
+) error {
+	// The encoder is free to reuse the value's memory once we return, so the
+	// payload must be copied before handing it to the worker.
+	payload := append([]byte(nil), value...)
+	return s.emitEvent(ctx, webhookEvent{payload: payload})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, noTopic, resolved)
+	if err != nil {
+		return err
+	}
+	payload = append([]byte(nil), payload...)
+	return s.emitEvent(ctx, webhookEvent{payload: payload, resolved: true})
+}
+
+func (s *webhookSink) emitEvent(ctx context.Context, event webhookEvent) error {
+	s.mu.Lock()
+	if err := s.mu.err; err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.mu.inflight++
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.markDelivered(1, nil)
+		return ctx.Err()
+	case <-s.workerCtx.Done():
+		return s.workerErr()
+	case s.eventCh <- event:
+	}
+	return nil
+}
+
+// workerErr returns the error the worker stopped with.
+func (s *webhookSink) workerErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err != nil {
+		return s.mu.err
+	}
+	return s.workerCtx.Err()
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	err := s.mu.err
+	immediateFlush := inflight == 0 || err != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return err
+	}
+
+	// Ask the worker to send out any partially filled batch.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.workerCtx.Done():
+		return s.workerErr()
+	case s.eventCh <- webhookEvent{flush: true}:
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.workerCtx.Done():
+		return s.workerErr()
+	case <-flushCh:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.mu.err
+	}
+}
+
+// markDelivered records that n messages are no longer in flight, either
+// because they were acknowledged or because sending them failed with err. A
+// waiting Flush is woken up once nothing is in flight or the worker failed.
+func (s *webhookSink) markDelivered(n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil && s.mu.err == nil {
+		s.mu.err = err
+	}
+	s.mu.inflight -= int64(n)
+	if (s.mu.inflight == 0 || s.mu.err != nil) && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
+	}
+}
+
+func (s *webhookSink) workerLoop() {
+	defer s.worker.Done()
+
+	var batch [][]byte
+	sendBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		body := encodeWebhookBatch(batch)
+		n := len(batch)
+		batch = batch[:0]
+		err := s.sendWithRetry(body)
+		s.markDelivered(n, err)
+		return err
+	}
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+
+	for {
+		var err error
+		select {
+		case <-s.workerCtx.Done():
+			return
+		case <-timer.C:
+			timer.Read = true
+			err = sendBatch()
+		case event := <-s.eventCh:
+			switch {
+			case event.flush:
+				err = sendBatch()
+			case event.resolved:
+				// Every row emitted before the resolved timestamp must be
+				// acknowledged before the resolved timestamp is sent.
+				if err = sendBatch(); err == nil {
+					err = s.sendWithRetry(event.payload)
+				}
+				s.markDelivered(1, err)
+			default:
+				if len(batch) == 0 && s.cfg.flushInterval > 0 {
+					timer.Reset(s.cfg.flushInterval)
+				}
+				batch = append(batch, event.payload)
+				if len(batch) >= s.cfg.batchSize {
+					err = sendBatch()
+				}
+			}
+		}
+		if err != nil {
+			// Delivering anything emitted after a failed request could leave
+			// a gap in the rows received by the endpoint, so stop here. The
+			// error is returned by every subsequent call to the sink.
+			s.cancelWorker()
+			return
+		}
+	}
+}
+
+// encodeWebhookBatch returns a JSON array of the given JSON values.
+func encodeWebhookBatch(batch [][]byte) []byte {
+	size := 2 + len(batch)
+	for _, payload := range batch {
+		size += len(payload)
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	buf.WriteByte('[')
+	for i, payload := range batch {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(payload)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+// sendWithRetry POSTs body to the sink's endpoint, retrying with exponential
+// backoff on connection errors and on responses that indicate the request
+// may succeed later.
+func (s *webhookSink) sendWithRetry(body []byte) error {
+	ctx := s.workerCtx
+	var err error
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		var retryable bool
+		if retryable, err = s.send(ctx, body); err == nil || !retryable {
+			return err
+		}
+		log.Warningf(ctx, "webhook sink request failed, retrying: %v", err)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+		err = ctxErr
+	}
+	return err
+}
+
+// send POSTs body to the sink's endpoint once. It returns whether a failed
+// request may be retried.
+func (s *webhookSink) send(ctx context.Context, body []byte) (retryable bool, _ error) {
+	req, err := httputil.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for name, values := range s.cfg.headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set(`Content-Type`, `application/json`)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = errors.Errorf(`webhook sink %s returned %s`, s.url, resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusRequestTimeout, err
+}
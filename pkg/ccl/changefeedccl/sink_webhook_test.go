@@ -0,0 +1,353 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookTestServer is an httptest server standing in for a webhook
+// endpoint. It records the body of every request it receives and responds
+// with the next status code from statuses, or 200 once they are exhausted.
+type webhookTestServer struct {
+	*httptest.Server
+
+	mu struct {
+		syncutil.Mutex
+		statuses []int
+		bodies   []string
+		headers  []http.Header
+	}
+}
+
+func makeWebhookTestServer(statuses ...int) *webhookTestServer {
+	s := &webhookTestServer{}
+	s.mu.statuses = statuses
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.mu.bodies = append(s.mu.bodies, string(body))
+		s.mu.headers = append(s.mu.headers, r.Header)
+		status := http.StatusOK
+		if len(s.mu.statuses) > 0 {
+			status, s.mu.statuses = s.mu.statuses[0], s.mu.statuses[1:]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+func (s *webhookTestServer) Bodies() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mu.bodies...)
+}
+
+func (s *webhookTestServer) Headers() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]http.Header(nil), s.mu.headers...)
+}
+
+// SinkURI returns a webhook sink URI for the server, trusting the server's
+// self-signed certificate.
+func (s *webhookTestServer) SinkURI(params url.Values) string {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(err)
+	}
+	u.Scheme = changefeedbase.SinkSchemeWebhookHTTPS
+	caCert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: s.Certificate().Raw})
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set(changefeedbase.SinkParamCACert, base64.StdEncoding.EncodeToString(caCert))
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+func makeTestWebhookSink(t *testing.T, sinkURI string) Sink {
+	t.Helper()
+	opts := map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)}
+	s, err := getSink(
+		context.Background(), sinkURI, roachpb.NodeID(1), opts, jobspb.ChangefeedTargets{},
+		nil /* settings */, nil /* timestampOracle */, nil, /* makeExternalStorageFromURI */
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	table := &sqlbase.TableDescriptor{Name: `t`}
+	encoder, err := getEncoder(map[string]string{
+		changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON),
+	})
+	require.NoError(t, err)
+
+	srv := makeWebhookTestServer()
+	defer srv.Close()
+
+	params := url.Values{}
+	params.Set(changefeedbase.SinkParamBatchSize, `2`)
+	params.Set(changefeedbase.SinkParamFlushInterval, `0s`)
+	params.Add(changefeedbase.SinkParamHeader, `X-Test: foo`)
+	params.Add(changefeedbase.SinkParamHeader, `Authorization: Bearer secret`)
+	sink := makeTestWebhookSink(t, srv.SinkURI(params))
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	// Nothing inflight.
+	require.NoError(t, sink.Flush(ctx))
+	require.Empty(t, srv.Bodies())
+
+	// A full batch is sent without waiting for a flush.
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[2]`), []byte(`{"a":2}`), zeroTS))
+	testutils.SucceedsSoon(t, func() error {
+		if len(srv.Bodies()) != 1 {
+			return fmt.Errorf(`expected 1 request got %d`, len(srv.Bodies()))
+		}
+		return nil
+	})
+
+	// A partial batch is only sent when flushed.
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[3]`), []byte(`{"a":3}`), zeroTS))
+	resolved := hlc.Timestamp{WallTime: 4}
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, resolved))
+	require.NoError(t, sink.Flush(ctx))
+
+	resolvedPayload, err := encoder.EncodeResolvedTimestamp(ctx, ``, resolved)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`[{"a":1},{"a":2}]`,
+		`[{"a":3}]`,
+		string(resolvedPayload),
+	}, srv.Bodies())
+
+	for _, h := range srv.Headers() {
+		require.Equal(t, `application/json`, h.Get(`Content-Type`))
+		require.Equal(t, `foo`, h.Get(`X-Test`))
+		require.Equal(t, `Bearer secret`, h.Get(`Authorization`))
+	}
+}
+
+func TestWebhookSinkFlushInterval(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	table := &sqlbase.TableDescriptor{Name: `t`}
+
+	srv := makeWebhookTestServer()
+	defer srv.Close()
+
+	params := url.Values{}
+	params.Set(changefeedbase.SinkParamBatchSize, `100`)
+	params.Set(changefeedbase.SinkParamFlushInterval, `10ms`)
+	sink := makeTestWebhookSink(t, srv.SinkURI(params))
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+	testutils.SucceedsSoon(t, func() error {
+		if bodies := srv.Bodies(); len(bodies) != 1 || bodies[0] != `[{"a":1}]` {
+			return fmt.Errorf(`expected partial batch to be sent got %v`, bodies)
+		}
+		return nil
+	})
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	table := &sqlbase.TableDescriptor{Name: `t`}
+	encoder, err := getEncoder(map[string]string{
+		changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON),
+	})
+	require.NoError(t, err)
+
+	makeSink := func(srv *webhookTestServer) Sink {
+		q, err := url.ParseQuery(`batch_size=10&flush_interval=0s`)
+		require.NoError(t, err)
+		cfg, err := parseWebhookSinkParams(q, map[string]string{})
+		require.NoError(t, err)
+		cfg.retryOpts.InitialBackoff = time.Millisecond
+		cfg.retryOpts.MaxBackoff = time.Millisecond
+		cfg.retryOpts.MaxRetries = 3
+		cfg.caCert = pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: srv.Certificate().Raw})
+		sink, err := makeWebhookSink(cfg, srv.URL)
+		require.NoError(t, err)
+		return sink
+	}
+
+	t.Run(`retryable`, func(t *testing.T) {
+		srv := makeWebhookTestServer(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		defer srv.Close()
+		sink := makeSink(srv)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 2}))
+		require.NoError(t, sink.Flush(ctx))
+
+		// The resolved timestamp is only sent once the row was acknowledged.
+		bodies := srv.Bodies()
+		require.Len(t, bodies, 4)
+		for _, body := range bodies[:3] {
+			require.Equal(t, `[{"a":1}]`, body)
+		}
+		require.Contains(t, bodies[3], `"resolved"`)
+	})
+
+	t.Run(`retries exhausted`, func(t *testing.T) {
+		srv := makeWebhookTestServer(
+			http.StatusInternalServerError, http.StatusInternalServerError,
+			http.StatusInternalServerError, http.StatusInternalServerError,
+		)
+		defer srv.Close()
+		sink := makeSink(srv)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+		require.Regexp(t, `500 Internal Server Error`, sink.Flush(ctx))
+		require.Len(t, srv.Bodies(), 4)
+
+		// The error is sticky.
+		require.Regexp(t, `500 Internal Server Error`, sink.Flush(ctx))
+	})
+
+	t.Run(`not retryable`, func(t *testing.T) {
+		srv := makeWebhookTestServer(http.StatusBadRequest)
+		defer srv.Close()
+		sink := makeSink(srv)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+		require.Regexp(t, `400 Bad Request`, sink.Flush(ctx))
+		require.Len(t, srv.Bodies(), 1)
+	})
+
+	t.Run(`stops after failure`, func(t *testing.T) {
+		srv := makeWebhookTestServer(http.StatusBadRequest)
+		defer srv.Close()
+		sink := makeSink(srv)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// The partial batch is sent when the resolved timestamp is emitted, and
+		// the resolved timestamp must not be delivered after it failed.
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"a":1}`), zeroTS))
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 2}))
+		require.Regexp(t, `400 Bad Request`, sink.Flush(ctx))
+
+		// Every subsequent call fails with the same error and sends nothing.
+		require.Regexp(t, `400 Bad Request`,
+			sink.EmitRow(ctx, table, []byte(`[3]`), []byte(`{"a":3}`), zeroTS))
+		require.Regexp(t, `400 Bad Request`,
+			sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 4}))
+		require.Regexp(t, `400 Bad Request`, sink.Flush(ctx))
+		require.Equal(t, []string{`[{"a":1}]`}, srv.Bodies())
+	})
+}
+
+func TestWebhookSinkParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	jsonOpts := map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)}
+	tests := []struct {
+		uri    string
+		opts   map[string]string
+		expErr string
+	}{
+		{
+			uri:    `webhook-https://localhost?batch_size=0`,
+			expErr: `param batch_size must be a positive integer: 0`,
+		},
+		{
+			uri:    `webhook-https://localhost?flush_interval=soon`,
+			expErr: `param flush_interval must be a duration`,
+		},
+		{
+			uri:    `webhook-https://localhost?flush_interval=-1s`,
+			expErr: `param flush_interval must not be negative: -1s`,
+		},
+		{
+			uri:    `webhook-https://localhost?header=foo`,
+			expErr: `param header must be of the form "Name: value": foo`,
+		},
+		{
+			uri:    `webhook-https://localhost?ca_cert=!`,
+			expErr: `param ca_cert must be base 64 encoded`,
+		},
+		{
+			uri:    `webhook-https://localhost?client_cert=Zm9v`,
+			expErr: `client_cert requires client_key to be set`,
+		},
+		{
+			uri:    `webhook-https://localhost?client_key=Zm9v`,
+			expErr: `client_key requires client_cert to be set`,
+		},
+		{
+			uri:    `webhook-https://localhost?foo=bar`,
+			expErr: `unknown sink query parameter: foo`,
+		},
+		{
+			uri:    `webhook-https://localhost`,
+			opts:   map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatAvro)},
+			expErr: `webhook-https sink requires format=json`,
+		},
+		{
+			uri: `webhook-https://localhost`,
+			opts: map[string]string{
+				changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+				changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeKeyOnly),
+			},
+			expErr: `webhook-https sink does not support envelope=key_only`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.uri, func(t *testing.T) {
+			opts := test.opts
+			if opts == nil {
+				opts = jsonOpts
+			}
+			_, err := getSink(
+				ctx, test.uri, roachpb.NodeID(1), opts, jobspb.ChangefeedTargets{},
+				nil /* settings */, nil /* timestampOracle */, nil, /* makeExternalStorageFromURI */
+			)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.expErr)
+		})
+	}
+}
@@ -132,6 +132,7 @@ type PrettyDataKey struct {
 	Active  bool `json:",omitempty"`
 	Exposed bool `json:",omitempty"`
 	Created JSONTime
+	Bytes   uint64   `json:",omitempty"`
 	Files   []string `json:",omitempty"`
 }
 
@@ -142,6 +143,7 @@ type PrettyStoreKey struct {
 	Type     string
 	Created  JSONTime
 	Source   string
+	Bytes    uint64          `json:",omitempty"`
 	Files    []string        `json:",omitempty"`
 	DataKeys []PrettyDataKey `json:",omitempty"`
 }
@@ -181,6 +183,16 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Build a map of 'key ID' -> size of the sstables encrypted by the key.
+	envStats, err := db.GetEnvStats()
+	if err != nil {
+		return err
+	}
+	keyBytes := make(map[string]uint64, len(envStats.KeyStats))
+	for _, ks := range envStats.KeyStats {
+		keyBytes[ks.KeyID] = ks.Bytes
+	}
+
 	// Build a map of 'key ID' -> list of files
 	fileKeyMap := make(map[string][]string)
 
@@ -226,6 +238,7 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 			Type:    storeKey.EncryptionType.String(),
 			Created: JSONTime(timeutil.Unix(storeKey.CreationTime, 0)),
 			Source:  storeKey.Source,
+			Bytes:   keyBytes[storeKey.KeyId],
 		}
 
 		// Files encrypted by the store key. This should only be the data key registry.
@@ -246,6 +259,7 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 					Active:  (c.KeyId == keyRegistry.ActiveDataKeyId),
 					Exposed: c.WasExposed,
 					Created: JSONTime(timeutil.Unix(c.CreationTime, 0)),
+					Bytes:   keyBytes[c.KeyId],
 				}
 				files, ok := fileKeyMap[c.KeyId]
 				if ok {
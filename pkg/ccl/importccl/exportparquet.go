@@ -0,0 +1,323 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
+
+// parquetColumnSchema returns the description of a parquet column with the
+// given name and type.
+//
+// Types with a direct equivalent in parquet are mapped to it:
+//   BOOL -> BOOLEAN
+//   INT2, INT4 -> INT32; INT8 -> INT64
+//   FLOAT4 -> FLOAT; FLOAT8 -> DOUBLE
+//   DATE -> INT32 (DATE)
+//   TIMESTAMP, TIMESTAMPTZ -> INT64 (TIMESTAMP_MICROS)
+//   BYTES -> BYTE_ARRAY
+//   JSONB -> BYTE_ARRAY (JSON)
+//
+// All the other types, including DECIMAL, are written as strings
+// (BYTE_ARRAY (UTF8)) using their text representation, which preserves their
+// precision and can be imported back.
+func parquetColumnSchema(name string, typ *types.T) parquet.Column {
+	col := parquet.Column{Name: name, ConvertedType: parquet.ConvertedTypeNone}
+	switch typ.Family() {
+	case types.BoolFamily:
+		col.Type = parquet.TypeBoolean
+	case types.IntFamily:
+		col.Type = parquet.TypeInt64
+		if typ.Width() == 16 || typ.Width() == 32 {
+			col.Type = parquet.TypeInt32
+		}
+	case types.FloatFamily:
+		col.Type = parquet.TypeDouble
+		if typ.Width() == 32 {
+			col.Type = parquet.TypeFloat
+		}
+	case types.DateFamily:
+		col.Type, col.ConvertedType = parquet.TypeInt32, parquet.ConvertedTypeDate
+	case types.TimestampFamily, types.TimestampTZFamily:
+		col.Type, col.ConvertedType = parquet.TypeInt64, parquet.ConvertedTypeTimestampMicros
+	case types.BytesFamily:
+		col.Type = parquet.TypeByteArray
+	case types.JsonFamily:
+		col.Type, col.ConvertedType = parquet.TypeByteArray, parquet.ConvertedTypeJSON
+	default:
+		col.Type, col.ConvertedType = parquet.TypeByteArray, parquet.ConvertedTypeUTF8
+	}
+	return col
+}
+
+// datumToParquetValue converts a datum to the go native value expected by the
+// parquet writer for a column of the given type, following the mapping
+// described in parquetColumnSchema.
+func datumToParquetValue(d tree.Datum, typ *types.T, f *tree.FmtCtx) (interface{}, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	switch t := d.(type) {
+	case *tree.DBool:
+		return bool(*t), nil
+	case *tree.DInt:
+		if typ.Width() == 16 || typ.Width() == 32 {
+			return int32(*t), nil
+		}
+		return int64(*t), nil
+	case *tree.DFloat:
+		if typ.Width() == 32 {
+			return float32(*t), nil
+		}
+		return float64(*t), nil
+	case *tree.DDate:
+		if !t.IsFinite() {
+			return nil, errors.Errorf("cannot export infinite date %s to parquet", t)
+		}
+		return int32(t.UnixEpochDays()), nil
+	case *tree.DTimestamp:
+		return t.UnixNano() / int64(time.Microsecond), nil
+	case *tree.DTimestampTZ:
+		return t.UnixNano() / int64(time.Microsecond), nil
+	case *tree.DBytes:
+		return string(*t), nil
+	}
+	d.Format(f)
+	s := f.String()
+	f.Reset()
+	return s, nil
+}
+
+// parquetCompressionCodec returns the parquet codec corresponding to the given
+// compression.
+func parquetCompressionCodec(c execinfrapb.FileCompression) (parquet.CompressionCodec, error) {
+	switch c {
+	case execinfrapb.FileCompression_None:
+		return parquet.CompressionUncompressed, nil
+	case execinfrapb.FileCompression_Gzip:
+		return parquet.CompressionGzip, nil
+	case execinfrapb.FileCompression_Snappy:
+		return parquet.CompressionSnappy, nil
+	default:
+		return 0, errors.Errorf("unsupported compression codec %s", c)
+	}
+}
+
+func newParquetWriterProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ParquetWriterSpec,
+	input execinfra.RowSource,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+
+	if err := utilccl.CheckEnterpriseEnabled(
+		flowCtx.Cfg.Settings,
+		flowCtx.Cfg.ClusterID.Get(),
+		sql.ClusterOrganization.Get(&flowCtx.Cfg.Settings.SV),
+		"EXPORT",
+	); err != nil {
+		return nil, err
+	}
+
+	p := &parquetWriter{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		output:      output,
+	}
+	if err := p.out.Init(&execinfrapb.PostProcessSpec{}, p.OutputTypes(), flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type parquetWriter struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ParquetWriterSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+}
+
+var _ execinfra.Processor = &parquetWriter{}
+
+func (sp *parquetWriter) OutputTypes() []*types.T {
+	res := make([]*types.T, len(sqlbase.ExportColumns))
+	for i := range res {
+		res[i] = sqlbase.ExportColumns[i].Typ
+	}
+	return res
+}
+
+func (sp *parquetWriter) fileName(part string) string {
+	pattern := exportParquetFilePatternDefault
+	if sp.spec.NamePattern != "" {
+		pattern = sp.spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+func (sp *parquetWriter) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "parquetWriter")
+	defer tracing.FinishSpan(span)
+
+	err := func() error {
+		typs := sp.input.OutputTypes()
+		if len(sp.spec.ColumnNames) != len(typs) {
+			return errors.AssertionFailedf(
+				"expected %d column names, found %d", len(typs), len(sp.spec.ColumnNames))
+		}
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
+
+		alloc := &sqlbase.DatumAlloc{}
+
+		cols := make([]parquet.Column, len(typs))
+		for i := range typs {
+			cols[i] = parquetColumnSchema(sp.spec.ColumnNames[i], typs[i])
+		}
+		codec, err := parquetCompressionCodec(sp.spec.CompressionCodec)
+		if err != nil {
+			return err
+		}
+
+		f := tree.NewFmtCtx(tree.FmtExport)
+		defer f.Close()
+
+		chunk := 0
+		done := false
+		for {
+			var rows int64
+			var buf bytes.Buffer
+			pw, err := parquet.NewWriter(&buf, cols, parquet.WriterOptions{
+				Compression:  codec,
+				RowGroupSize: sp.spec.RowGroupSize,
+			})
+			if err != nil {
+				return err
+			}
+			for {
+				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
+					break
+				}
+				row, err := input.NextRow()
+				if err != nil {
+					return err
+				}
+				if row == nil {
+					done = true
+					break
+				}
+				rows++
+
+				// The writer buffers the records until a row group is complete, so
+				// they cannot be reused.
+				record := make([]interface{}, len(typs))
+				for i, ed := range row {
+					if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
+						return err
+					}
+					if record[i], err = datumToParquetValue(ed.Datum, typs[i], f); err != nil {
+						return err
+					}
+				}
+				if err := pw.AddRow(record); err != nil {
+					return err
+				}
+			}
+			if rows < 1 {
+				break
+			}
+			// Flush the last row group and write the footer.
+			if err := pw.Close(); err != nil {
+				return errors.Wrap(err, "failed to flush parquet writer")
+			}
+
+			conf, err := cloud.ExternalStorageConfFromURI(sp.spec.Destination)
+			if err != nil {
+				return err
+			}
+			es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+
+			nodeID, err := sp.flowCtx.EvalCtx.NodeID.OptionalNodeIDErr(47970)
+			if err != nil {
+				return err
+			}
+
+			part := fmt.Sprintf("n%d.%d", nodeID, chunk)
+			chunk++
+			filename := sp.fileName(part)
+			size := buf.Len()
+
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(buf.Bytes())); err != nil {
+				return err
+			}
+			res := sqlbase.EncDatumRow{
+				sqlbase.DatumToEncDatum(
+					types.String,
+					tree.NewDString(filename),
+				),
+				sqlbase.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(rows)),
+				),
+				sqlbase.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(size)),
+				),
+			}
+
+			cs, err := sp.out.EmitRow(ctx, res)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return errors.New("unexpected closure of consumer")
+			}
+			if done {
+				break
+			}
+		}
+
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
+}
+
+func init() {
+	rowexec.NewParquetWriterProcessor = newParquetWriterProcessor
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/workload/bank"
+)
+
+func TestExportImportBankParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	db, _, cleanup := setupExportableBank(t, 3, 100)
+	defer cleanup()
+
+	db.Exec(t, "UPDATE bank SET payload = payload || '✅' WHERE id = 5")
+	db.Exec(t, "UPDATE bank SET payload = NULL WHERE id % 2 = 0")
+
+	for _, compression := range []string{"none", "gzip", "snappy"} {
+		t.Run("compression="+compression, func(t *testing.T) {
+			var files []string
+
+			var asOf string
+			db.QueryRow(t, "SELECT cluster_logical_timestamp()").Scan(&asOf)
+
+			dest := "nodelocal://0/parquet-" + compression
+			for _, row := range db.QueryStr(t,
+				fmt.Sprintf(`EXPORT INTO PARQUET '%s'
+					WITH chunk_rows = $1, compression = '%s', row_group_size = '1KiB'
+					FROM SELECT * FROM bank AS OF SYSTEM TIME %s`, dest, compression, asOf), 13,
+			) {
+				if !strings.HasSuffix(row[0], ".parquet") {
+					t.Fatalf("unexpected file name %s", row[0])
+				}
+				files = append(files, row[0])
+			}
+
+			schema := bank.FromRows(1).Tables()[0].Schema
+			fileList := "'" + dest + "/" + strings.Join(files, "', '"+dest+"/") + "'"
+			db.Exec(t, fmt.Sprintf(`IMPORT TABLE bank2 %s PARQUET DATA (%s)`, schema, fileList))
+
+			db.CheckQueryResults(t,
+				fmt.Sprintf(`SELECT * FROM bank AS OF SYSTEM TIME %s ORDER BY id`, asOf), db.QueryStr(t, `SELECT * FROM bank2 ORDER BY id`),
+			)
+			db.Exec(t, "DROP TABLE bank2")
+		})
+	}
+}
+
+func TestExportImportParquetTypes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	const schema = `(
+		id INT8 PRIMARY KEY, b BOOL, i2 INT2, f4 FLOAT4, f8 FLOAT8, d DECIMAL,
+		dt DATE, ts TIMESTAMP, tz TIMESTAMPTZ, s STRING, by BYTES, j JSONB,
+		u UUID, iv INTERVAL, a INT[]
+	)`
+	sqlDB.Exec(t, `CREATE TABLE t `+schema)
+	sqlDB.Exec(t, `INSERT INTO t VALUES
+		(1, true, 2, 1.5, -2.25, 12345.6789, '2020-06-01', '2020-06-01 12:34:56.789012',
+		 '2020-06-01 12:34:56.789012+02', 'héllo', '\x00ff', '{"a": [1, null]}',
+		 '63616665-6630-3064-6465-616462656566', '1 day 2 hours', ARRAY[1, NULL, 3]),
+		(2, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL)`)
+
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/types' FROM SELECT * FROM t`)
+	sqlDB.Exec(t, `IMPORT TABLE t2 `+schema+` PARQUET DATA ('nodelocal://0/types/n1.0.parquet')`)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM t2 ORDER BY id`, sqlDB.QueryStr(t, `SELECT * FROM t ORDER BY id`),
+	)
+
+	// Columns are matched by name, and unknown columns are ignored unless
+	// strict_validation is specified.
+	sqlDB.Exec(t, `CREATE TABLE t3 (s STRING, id INT8 PRIMARY KEY)`)
+	sqlDB.Exec(t, `IMPORT INTO t3 PARQUET DATA ('nodelocal://0/types/n1.0.parquet')`)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM t3 ORDER BY id`, [][]string{{"héllo", "1"}, {"NULL", "2"}},
+	)
+	sqlDB.ExpectErr(t, "could not find column for parquet column b",
+		`IMPORT INTO t3 PARQUET DATA ('nodelocal://0/types/n1.0.parquet') WITH strict_validation`)
+
+	sqlDB.ExpectErr(t, "delimiter option is not supported for PARQUET export",
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH delimiter = '|' FROM SELECT * FROM t`)
+	sqlDB.ExpectErr(t, "row_group_size option is not supported for CSV export",
+		`EXPORT INTO CSV 'nodelocal://0/bad' WITH row_group_size = '1MiB' FROM SELECT * FROM t`)
+	sqlDB.ExpectErr(t, "unsupported compression codec snappy",
+		`EXPORT INTO CSV 'nodelocal://0/bad' WITH compression = 'snappy' FROM SELECT * FROM t`)
+}
@@ -0,0 +1,301 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// foreignScanner reads the files of a foreign table and emits its rows.
+//
+// The columns stored in the files are the visible columns of the table which
+// are not partition columns: CSV files hold them in order, and Parquet
+// columns are matched to them by name. The values of the partition columns
+// are taken from the path of each file.
+type foreignScanner struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ForeignScanSpec
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+
+	// dataCols are the ordinals of the columns stored in the files.
+	dataCols []int
+	// row is the row being built.
+	row tree.Datums
+}
+
+var _ execinfra.Processor = &foreignScanner{}
+
+func newForeignScanProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ForeignScanSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	fs := &foreignScanner{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		output:      output,
+		row:         make(tree.Datums, len(spec.Table.Columns)),
+	}
+	isPartCol := make(map[uint32]bool, len(spec.PartitionColumns))
+	for _, c := range spec.PartitionColumns {
+		isPartCol[c] = true
+	}
+	typs := make([]*types.T, len(spec.Table.Columns))
+	for i := range spec.Table.Columns {
+		typs[i] = spec.Table.Columns[i].Type
+		if !spec.Table.Columns[i].Hidden && !isPartCol[uint32(i)] {
+			fs.dataCols = append(fs.dataCols, i)
+		}
+	}
+	if err := fs.out.Init(post, typs, flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// OutputTypes is part of the execinfra.Processor interface.
+func (fs *foreignScanner) OutputTypes() []*types.T {
+	return fs.out.OutputTypes
+}
+
+// Run is part of the execinfra.Processor interface.
+func (fs *foreignScanner) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "foreignScanner")
+	defer tracing.FinishSpan(span)
+
+	err := fs.run(ctx)
+	execinfra.DrainAndClose(
+		ctx, fs.output, err, func(context.Context) {} /* pushTrailingMeta */)
+}
+
+func (fs *foreignScanner) run(ctx context.Context) error {
+	if len(fs.spec.Files) == 0 {
+		return nil
+	}
+	conf, err := cloud.ExternalStorageConfFromURI(fs.spec.URI)
+	if err != nil {
+		return err
+	}
+	es, err := fs.flowCtx.Cfg.ExternalStorage(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+
+	cols := fs.spec.Table.Columns
+	partNames := make([]string, len(fs.spec.PartitionColumns))
+	partTypes := make([]*types.T, len(fs.spec.PartitionColumns))
+	for i, c := range fs.spec.PartitionColumns {
+		partNames[i] = cols[c].Name
+		partTypes[i] = cols[c].Type
+	}
+
+	for _, file := range fs.spec.Files {
+		for i := range fs.row {
+			fs.row[i] = tree.DNull
+		}
+		if len(partNames) > 0 {
+			values, err := sql.ParseForeignPartitionPath(fs.flowCtx.EvalCtx, file, partNames, partTypes)
+			if err != nil {
+				return pgerror.WithCandidateCode(err, pgcode.FdwError)
+			}
+			for i, c := range fs.spec.PartitionColumns {
+				fs.row[c] = values[i]
+			}
+		}
+		more, err := fs.readFile(ctx, es, file)
+		if err != nil {
+			return errors.Wrapf(err, "reading %q", file)
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+// readFile reads the rows of a file and emits them. It returns false if the
+// consumer does not need more rows.
+func (fs *foreignScanner) readFile(
+	ctx context.Context, es cloud.ExternalStorage, file string,
+) (bool, error) {
+	r, err := es.ReadFile(ctx, file)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	name := file
+	if name == "" {
+		// The table is a single file read from its location.
+		name = fs.spec.URI
+	}
+	src, err := decompressingReader(r, name, fs.spec.Format.Compression)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+
+	switch fs.spec.Format.Format {
+	case roachpb.IOFileFormat_CSV:
+		return fs.readCSV(ctx, src)
+	case roachpb.IOFileFormat_Parquet:
+		return fs.readParquet(ctx, src)
+	default:
+		return false, errors.AssertionFailedf("unsupported format %s", fs.spec.Format.Format)
+	}
+}
+
+func (fs *foreignScanner) readCSV(ctx context.Context, src io.Reader) (bool, error) {
+	opts := fs.spec.Format.Csv
+	cr := csv.NewReader(src)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = !opts.StrictQuotes
+	cr.Comment = opts.Comment
+
+	cols := fs.spec.Table.Columns
+	for rowNum := int64(1); ; rowNum++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "row %d", rowNum)
+		}
+		if rowNum <= int64(opts.Skip) {
+			continue
+		}
+		if len(record) != len(fs.dataCols) {
+			return false, newImportRowError(
+				errors.Errorf("expected %d fields, got %d", len(fs.dataCols), len(record)),
+				strRecord(record, opts.Comma), rowNum)
+		}
+		for i, field := range record {
+			idx := fs.dataCols[i]
+			if opts.NullEncoding != nil && field == *opts.NullEncoding {
+				fs.row[idx] = tree.DNull
+				continue
+			}
+			fs.row[idx], err = sqlbase.ParseDatumStringAs(cols[idx].Type, field, fs.flowCtx.EvalCtx)
+			if err != nil {
+				return false, newImportRowError(
+					errors.Wrapf(err, "parse %q as %s", cols[idx].Name, cols[idx].Type.SQLString()),
+					strRecord(record, opts.Comma), rowNum)
+			}
+		}
+		if more, err := fs.emit(ctx); !more || err != nil {
+			return false, err
+		}
+	}
+}
+
+func (fs *foreignScanner) readParquet(ctx context.Context, src io.Reader) (bool, error) {
+	// The metadata of parquet files is stored in their footer, so the whole
+	// file is buffered before it is decoded.
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return false, err
+	}
+	pr, err := parquet.NewReader(data)
+	if err != nil {
+		return false, err
+	}
+	pcols := parquetColumns(pr)
+
+	cols := fs.spec.Table.Columns
+	colIdxByName := make(map[string]int, len(fs.dataCols))
+	for _, idx := range fs.dataCols {
+		colIdxByName[cols[idx].Name] = idx
+	}
+	// colToIdx maps the columns of the file to the columns of the table; -1 if
+	// the column is ignored.
+	colToIdx := make([]int, len(pcols))
+	for i := range pcols {
+		idx, ok := colIdxByName[pcols[i].name]
+		if !ok {
+			idx = -1
+		}
+		colToIdx[i] = idx
+	}
+
+	stream := &parquetRowStream{
+		reader:  pr,
+		numRows: pr.NumRows(),
+		numCols: len(pcols),
+		batch:   make([][]interface{}, len(pcols)),
+	}
+	for stream.Scan() {
+		record, err := stream.Row()
+		if err != nil {
+			return false, err
+		}
+		for i, v := range record.([]interface{}) {
+			idx := colToIdx[i]
+			if idx < 0 {
+				continue
+			}
+			fs.row[idx], err = parquetValueToDatum(v, pcols[i].elem, cols[idx].Type, fs.flowCtx.EvalCtx)
+			if err != nil {
+				return false, errors.Wrapf(err, "column %s", pcols[i].name)
+			}
+		}
+		if more, err := fs.emit(ctx); !more || err != nil {
+			return false, err
+		}
+	}
+	return true, stream.Err()
+}
+
+// emit checks the row being built against the NOT NULL constraints of the
+// table and emits it. The hidden primary key column is always NULL. It returns
+// false if the consumer does not need more rows.
+func (fs *foreignScanner) emit(ctx context.Context) (bool, error) {
+	cols := fs.spec.Table.Columns
+	row := make(sqlbase.EncDatumRow, len(fs.row))
+	for i, d := range fs.row {
+		if d == tree.DNull && !cols[i].Nullable && !cols[i].Hidden {
+			return false, sqlbase.NewNonNullViolationError(cols[i].Name)
+		}
+		row[i] = sqlbase.DatumToEncDatum(cols[i].Type, d)
+	}
+	status, err := fs.out.EmitRow(ctx, row)
+	if err != nil {
+		return false, err
+	}
+	return status == execinfra.NeedMoreRows, nil
+}
+
+func init() {
+	rowexec.NewForeignScanProcessor = newForeignScanProcessor
+}
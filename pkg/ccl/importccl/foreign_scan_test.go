@@ -0,0 +1,142 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestForeignTableScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("events/region=east/year=2019/0.csv", "1,a\n2,b\n")
+	writeFile("events/region=east/year=2020/0.csv", "3,c\n")
+	writeFile("events/region=west/year=2020/0.csv", "4,d\n5,\\N\n")
+	writeFile("events/region=west/year=2020/1.csv", "6,f\n")
+	writeFile("items.csv", "# items\nid|name\n1|x\n2|y\n")
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{ExternalIODir: dir},
+	})
+	defer tc.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	db.Exec(t, `CREATE FOREIGN DATA WRAPPER files OPTIONS (format 'csv')`)
+	db.Exec(t, `CREATE SERVER local FOREIGN DATA WRAPPER files OPTIONS (uri 'nodelocal://1/')`)
+	db.Exec(t, `CREATE FOREIGN TABLE events (id INT, v STRING, region STRING, year INT)
+		SERVER local OPTIONS (path 'events', partition_columns 'region, year', nullif '\N')`)
+	db.Exec(t, `CREATE FOREIGN TABLE items (id INT NOT NULL, name STRING)
+		SERVER local OPTIONS (path 'items.csv', delimiter '|', comment '#', skip '1')`)
+
+	t.Run("scan", func(t *testing.T) {
+		db.CheckQueryResults(t, `SELECT * FROM events ORDER BY id`, [][]string{
+			{"1", "a", "east", "2019"},
+			{"2", "b", "east", "2019"},
+			{"3", "c", "east", "2020"},
+			{"4", "d", "west", "2020"},
+			{"5", "NULL", "west", "2020"},
+			{"6", "f", "west", "2020"},
+		})
+		db.CheckQueryResults(t, `SELECT count(*), max(id) FROM events`, [][]string{{"6", "6"}})
+		db.CheckQueryResults(t, `SELECT * FROM items ORDER BY id`, [][]string{{"1", "x"}, {"2", "y"}})
+	})
+
+	t.Run("partition pruning", func(t *testing.T) {
+		db.CheckQueryResults(t,
+			`SELECT id FROM events WHERE year = 2020 AND region = 'west' AND v IS NOT NULL ORDER BY id`,
+			[][]string{{"4"}, {"6"}},
+		)
+		db.CheckQueryResults(t, `SELECT id FROM events WHERE year < 2020 ORDER BY id`,
+			[][]string{{"1"}, {"2"}},
+		)
+		db.CheckQueryResults(t, `SELECT id FROM events WHERE region = 'north'`, [][]string{})
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		db.CheckQueryResults(t, `SELECT count(*) FROM (SELECT * FROM events LIMIT 4)`,
+			[][]string{{"4"}},
+		)
+	})
+
+	t.Run("join", func(t *testing.T) {
+		db.CheckQueryResults(t,
+			`SELECT e.id, i.name FROM events AS e JOIN items AS i ON e.id = i.id ORDER BY e.id`,
+			[][]string{{"1", "x"}, {"2", "y"}},
+		)
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		db.Exec(t, `CREATE TABLE src (id INT PRIMARY KEY, s STRING, d DECIMAL)`)
+		db.Exec(t, `INSERT INTO src VALUES (1, 'one', 1.5), (2, NULL, 2.25)`)
+		db.Exec(t, `EXPORT INTO PARQUET 'nodelocal://1/src' FROM TABLE src`)
+		db.Exec(t, `CREATE FOREIGN TABLE src_ft (d DECIMAL, id INT, missing STRING)
+			SERVER local OPTIONS (path 'src', format 'parquet')`)
+		db.CheckQueryResults(t, `SELECT * FROM src_ft ORDER BY id`, [][]string{
+			{"1.5", "1", "NULL"},
+			{"2.25", "2", "NULL"},
+		})
+
+		// Several files, each made of several row groups.
+		db.Exec(t, `CREATE TABLE big (id INT PRIMARY KEY, s STRING, ts TIMESTAMP)`)
+		db.Exec(t, `INSERT INTO big SELECT i, IF(i % 3 = 0, NULL, repeat('x', i % 50)),
+			'2020-01-01'::TIMESTAMP + i * '1s'::INTERVAL FROM generate_series(1, 2000) AS g(i)`)
+		db.Exec(t, `EXPORT INTO PARQUET 'nodelocal://1/big'
+			WITH chunk_rows = 700, row_group_size = '1KiB' FROM TABLE big`)
+		db.Exec(t, `CREATE FOREIGN TABLE big_ft (id INT, s STRING, ts TIMESTAMP)
+			SERVER local OPTIONS (path 'big', format 'parquet')`)
+		db.CheckQueryResults(t, `SELECT count(*), count(s), sum(id), max(ts) FROM big_ft`,
+			db.QueryStr(t, `SELECT count(*), count(s), sum(id), max(ts) FROM big`))
+		db.CheckQueryResults(t,
+			`SELECT count(*) FROM big_ft AS f JOIN big AS b USING (id) WHERE f.s IS NOT DISTINCT FROM b.s`,
+			[][]string{{"2000"}},
+		)
+
+		writeFile("notparquet/0.parquet", "PAR1 but not really")
+		db.Exec(t, `CREATE FOREIGN TABLE notparquet (id INT)
+			SERVER local OPTIONS (path 'notparquet', format 'parquet')`)
+		db.ExpectErr(t, `parquet: not a parquet file`, `SELECT * FROM notparquet`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		db.ExpectErr(t, `use of rowid column not allowed with foreign table "events"`,
+			`SELECT rowid FROM events`)
+		db.ExpectErr(t, `FOR UPDATE is not allowed with foreign table "events"`,
+			`SELECT * FROM events FOR UPDATE`)
+		db.ExpectErr(t, `cannot mutate foreign table "events"`,
+			`INSERT INTO events VALUES (7, 'g', 'east', 2021)`)
+
+		writeFile("broken/0.csv", "1,x\n,y\n")
+		db.Exec(t, `CREATE FOREIGN TABLE broken (id INT NOT NULL, v STRING)
+			SERVER local OPTIONS (path 'broken', nullif '')`)
+		db.ExpectErr(t, `null value in column "id" violates not-null constraint`,
+			`SELECT * FROM broken`)
+	})
+}
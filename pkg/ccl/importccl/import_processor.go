@@ -192,6 +192,18 @@ func makeInputConverter(
 		return newAvroInputReader(
 			kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			kvCh, singleTable, spec.Format.Parquet, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_Shapefile:
+		return newShapefileInputReader(
+			kvCh, singleTable, spec.Format.Shapefile, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_GeoJSON:
+		return newGeoJSONInputReader(
+			kvCh, singleTable, spec.Format.GeoJSON, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx)
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
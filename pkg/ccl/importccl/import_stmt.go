@@ -74,7 +74,7 @@ const (
 
 	optMaxRowSize = "max_row_size"
 
-	// Turn on strict validation when importing avro records.
+	// Turn on strict validation when importing avro records or parquet files.
 	avroStrict = "strict_validation"
 	// Default input format is assumed to be OCF (object container file).
 	// This default can be changed by specified either of these options.
@@ -86,6 +86,12 @@ const (
 	// as either an inline JSON schema, or an external schema URI.
 	avroSchema    = "schema"
 	avroSchemaURI = "schema_uri"
+
+	// Name of the GEOMETRY or GEOGRAPHY column into which shapefile or GeoJSON
+	// geometries are imported.
+	geoGeometryColumn = "geometry_column"
+	// SRID of the imported shapefile or GeoJSON geometries.
+	geoSRID = "srid"
 )
 
 var importOptionExpectValues = map[string]sql.KVStringOptValidate{
@@ -116,6 +122,9 @@ var importOptionExpectValues = map[string]sql.KVStringOptValidate{
 	avroRecordsSeparatedBy: sql.KVStringOptRequireValue,
 	avroBinRecords:         sql.KVStringOptRequireNoValue,
 	avroJSONRecords:        sql.KVStringOptRequireNoValue,
+
+	geoGeometryColumn: sql.KVStringOptRequireValue,
+	geoSRID:           sql.KVStringOptRequireValue,
 }
 
 func makeStringSet(opts ...string) map[string]struct{} {
@@ -146,6 +155,9 @@ var mysqlOutAllowedOptions = makeStringSet(
 var mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs)
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 var pgDumpAllowedOptions = makeStringSet(optMaxRowSize, importOptionSkipFKs)
+var parquetAllowedOptions = makeStringSet(avroStrict)
+var shapefileAllowedOptions = makeStringSet(geoGeometryColumn, geoSRID)
+var geoJSONAllowedOptions = makeStringSet(geoGeometryColumn, geoSRID)
 
 func validateFormatOptions(
 	format string, specified map[string]string, formatAllowed map[string]struct{},
@@ -161,6 +173,23 @@ func validateFormatOptions(
 	return nil
 }
 
+// parseGeoSRIDOption returns the SRID specified for the imported geometries,
+// or 0 if none is specified.
+func parseGeoSRIDOption(opts map[string]string) (int32, error) {
+	override, ok := opts[geoSRID]
+	if !ok {
+		return 0, nil
+	}
+	srid, err := strconv.ParseInt(override, 10, 32)
+	if err != nil {
+		return 0, pgerror.Wrapf(err, pgcode.Syntax, "invalid %s value", geoSRID)
+	}
+	if srid <= 0 {
+		return 0, pgerror.Newf(pgcode.Syntax, "%s must be > 0", geoSRID)
+	}
+	return int32(srid), nil
+}
+
 func importJobDescription(
 	p sql.PlanHookState,
 	orig *tree.Import,
@@ -492,6 +521,33 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			telemetry.Count("import.format.parquet")
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
+		case "SHAPEFILE":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, shapefileAllowedOptions); err != nil {
+				return err
+			}
+			telemetry.Count("import.format.shapefile")
+			format.Format = roachpb.IOFileFormat_Shapefile
+			format.Shapefile.GeometryColumn = opts[geoGeometryColumn]
+			if format.Shapefile.SRID, err = parseGeoSRIDOption(opts); err != nil {
+				return err
+			}
+		case "GEOJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, geoJSONAllowedOptions); err != nil {
+				return err
+			}
+			telemetry.Count("import.format.geojson")
+			format.Format = roachpb.IOFileFormat_GeoJSON
+			format.GeoJSON.GeometryColumn = opts[geoGeometryColumn]
+			if format.GeoJSON.SRID, err = parseGeoSRIDOption(opts); err != nil {
+				return err
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -574,6 +630,12 @@ func importPlanHook(
 				return pgerror.New(pgcode.FeatureNotSupported, "Cannot use IMPORT INTO with interleaved tables")
 			}
 
+			// The rows of a foreign table live in external storage.
+			if found.IsForeignTable() {
+				return pgerror.Newf(pgcode.WrongObjectType,
+					"Cannot use IMPORT INTO with foreign table %q", found.Name)
+			}
+
 			// Validate target columns.
 			var intoCols []string
 			var isTargetCol = make(map[string]bool)
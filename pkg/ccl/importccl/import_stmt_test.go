@@ -3014,6 +3014,91 @@ func BenchmarkPgCopyConvertRecord(b *testing.B) {
 	b.ReportAllocs()
 }
 
+// TestImportIntoRestoreAsOfSystemTimeWhileOffline tests that restoring a table
+// as of a time when an IMPORT INTO had taken it offline restores its last
+// public version, without the rows the IMPORT INTO had already ingested.
+func TestImportIntoRestoreAsOfSystemTimeWhileOffline(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{ExternalIODir: dir},
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	importIngested := make(chan struct{})
+	finishImport := make(chan struct{})
+	tc.Server(0).JobRegistry().(*jobs.Registry).TestingResumerCreationKnobs = map[jobspb.Type]func(raw jobs.Resumer) jobs.Resumer{
+		jobspb.TypeImport: func(raw jobs.Resumer) jobs.Resumer {
+			r := raw.(*importResumer)
+			r.testingKnobs.afterImport = func(_ backupccl.RowCount) error {
+				importIngested <- struct{}{}
+				<-finishImport
+				return nil
+			}
+			return r
+		},
+	}
+
+	sqlDB := sqlutils.MakeSQLRunner(tc.Conns[0])
+	sqlDB.Exec(t, `CREATE DATABASE data`)
+	sqlDB.Exec(t, `CREATE TABLE data.t (id INT PRIMARY KEY, v STRING)`)
+	sqlDB.Exec(t, `INSERT INTO data.t SELECT i, 'before' FROM generate_series(1, 10) AS g(i)`)
+
+	var csv strings.Builder
+	for i := 11; i <= 20; i++ {
+		fmt.Fprintf(&csv, "%d,imported\n", i)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "data.csv"), []byte(csv.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const full = "nodelocal://0/full"
+	const inc = "nodelocal://0/inc"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, full)
+
+	importErr := make(chan error, 1)
+	go func() {
+		_, err := tc.Conns[0].Exec(`IMPORT INTO data.t (id, v) CSV DATA ('nodelocal://0/data.csv')`)
+		importErr <- err
+	}()
+
+	// The rows have been ingested but the table is still offline.
+	<-importIngested
+	var offlineTS string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&offlineTS)
+	close(finishImport)
+	if err := <-importErr; err != nil {
+		t.Fatal(err)
+	}
+	var publicTS string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&publicTS)
+
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH revision_history`,
+		inc, full)
+
+	for _, c := range []struct {
+		asOf     string
+		expected [][]string
+	}{
+		{asOf: offlineTS, expected: [][]string{{"10", "10", "before"}}},
+		{asOf: publicTS, expected: [][]string{{"20", "20", "before"}}},
+	} {
+		sqlDB.Exec(t, `DROP DATABASE IF EXISTS restored CASCADE`)
+		sqlDB.Exec(t, `CREATE DATABASE restored`)
+		sqlDB.Exec(t, fmt.Sprintf(
+			`RESTORE data.t FROM $1, $2 AS OF SYSTEM TIME %s WITH into_db = 'restored'`, c.asOf,
+		), full, inc)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), max(id), min(v) FROM restored.t`, c.expected)
+		sqlDB.CheckQueryResults(t,
+			`SELECT state FROM crdb_internal.tables WHERE database_name = 'restored' AND name = 't'`,
+			[][]string{{"PUBLIC"}},
+		)
+	}
+}
+
 // TestImportControlJob tests that PAUSE JOB, RESUME JOB, and CANCEL JOB
 // work as intended on import jobs.
 func TestImportControlJob(t *testing.T) {
@@ -0,0 +1,99 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// geoImportColumns describes how the features of a geospatial file, made of
+// a geometry and of named properties, map to the visible columns of the
+// imported table.
+type geoImportColumns struct {
+	// geoColIdx is the index of the column into which geometries are imported.
+	geoColIdx int
+	// colIdxByName maps the normalized names of the other columns to their
+	// indexes.
+	colIdxByName map[string]int
+}
+
+// makeGeoImportColumns returns the geoImportColumns of the given table. If
+// geometryColumn is empty, geometries are imported into the first GEOMETRY or
+// GEOGRAPHY column of the table.
+func makeGeoImportColumns(
+	tableDesc *sqlbase.TableDescriptor, geometryColumn string,
+) (geoImportColumns, error) {
+	c := geoImportColumns{geoColIdx: -1, colIdxByName: make(map[string]int)}
+	for idx, col := range tableDesc.VisibleColumns() {
+		isGeo := col.Type.Family() == types.GeometryFamily || col.Type.Family() == types.GeographyFamily
+		if geometryColumn != "" && col.Name == geometryColumn {
+			if !isGeo {
+				return geoImportColumns{}, errors.Errorf(
+					"column %s must be of type GEOMETRY or GEOGRAPHY to hold the imported geometries, found %s",
+					col.Name, col.Type.SQLString())
+			}
+			c.geoColIdx = idx
+			continue
+		}
+		if geometryColumn == "" && isGeo && c.geoColIdx < 0 {
+			c.geoColIdx = idx
+			continue
+		}
+		c.colIdxByName[col.Name] = idx
+	}
+	if c.geoColIdx < 0 {
+		if geometryColumn != "" {
+			return geoImportColumns{}, errors.Errorf("could not find geometry column %s", geometryColumn)
+		}
+		return geoImportColumns{}, errors.Errorf(
+			"table %s has no GEOMETRY or GEOGRAPHY column to hold the imported geometries", tableDesc.Name)
+	}
+	return c, nil
+}
+
+// propertyColIdx returns the index of the column into which the property with
+// the given name is imported, or false if the property is ignored.
+func (c *geoImportColumns) propertyColIdx(name string) (int, bool) {
+	idx, ok := c.colIdxByName[lex.NormalizeName(name)]
+	return idx, ok
+}
+
+// setGeometry sets the datum of the geometry column. A nil geometry is
+// imported as NULL.
+func (c *geoImportColumns) setGeometry(g *geo.Geometry, conv *row.DatumRowConverter) error {
+	if g == nil {
+		conv.Datums[c.geoColIdx] = tree.DNull
+		return nil
+	}
+	if conv.VisibleColTypes[c.geoColIdx].Family() == types.GeographyFamily {
+		geog, err := g.AsGeography()
+		if err != nil {
+			return err
+		}
+		conv.Datums[c.geoColIdx] = tree.NewDGeography(geog)
+		return nil
+	}
+	conv.Datums[c.geoColIdx] = tree.NewDGeometry(g)
+	return nil
+}
+
+// resetDatums sets the datums of all the visible columns to NULL before a
+// feature is imported. The datums are reused across rows, and features need
+// not all have the same properties.
+func resetDatums(conv *row.DatumRowConverter) {
+	for i := range conv.VisibleCols {
+		conv.Datums[i] = tree.DNull
+	}
+}
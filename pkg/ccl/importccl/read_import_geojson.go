@@ -0,0 +1,311 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+)
+
+// geoJSONNull is the JSON encoding of null.
+const geoJSONNull = "null"
+
+// geoJSONFeature holds the members of a GeoJSON object, which is expected to
+// be a Feature.
+type geoJSONFeature map[string]json.RawMessage
+
+// geoJSONMemberString returns the value of a string member of a GeoJSON
+// object, or the empty string if the member is absent or not a string.
+func geoJSONMemberString(obj geoJSONFeature, name string) string {
+	var s string
+	if v, ok := obj[name]; ok {
+		_ = json.Unmarshal(v, &s)
+	}
+	return s
+}
+
+// geoJSONValueToDatum converts the value of a property of a GeoJSON feature
+// to a datum of the target type. JSON columns receive the value as is; string
+// values are unquoted, and other values are converted from their JSON text.
+func geoJSONValueToDatum(
+	v json.RawMessage, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if string(v) == geoJSONNull {
+		return tree.DNull, nil
+	}
+	if targetT.Family() != types.JsonFamily && len(v) > 0 && v[0] == '"' {
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return nil, err
+		}
+		return sqlbase.ParseDatumStringAs(targetT, s, evalCtx)
+	}
+	return sqlbase.ParseDatumStringAs(targetT, string(v), evalCtx)
+}
+
+// geoJSONConsumer implements importRowConsumer interface.
+type geoJSONConsumer struct {
+	cols geoImportColumns
+	srid geopb.SRID
+}
+
+var _ importRowConsumer = &geoJSONConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (c *geoJSONConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	feature, ok := native.(geoJSONFeature)
+	if !ok {
+		return errors.Errorf("unexpected native type; expected geoJSONFeature found %T instead", native)
+	}
+	if typ := geoJSONMemberString(feature, "type"); typ != "Feature" {
+		return errors.Errorf("expected a GeoJSON Feature, found %q", typ)
+	}
+
+	resetDatums(conv)
+	var g *geo.Geometry
+	if v, ok := feature["geometry"]; ok && string(v) != geoJSONNull {
+		parsed, err := geo.ParseGeometryFromGeoJSON(v)
+		if err != nil {
+			return errors.Wrap(err, "invalid geometry")
+		}
+		if g, err = parsed.CloneWithSRID(c.srid); err != nil {
+			return err
+		}
+	}
+	if err := c.cols.setGeometry(g, conv); err != nil {
+		return err
+	}
+
+	if v, ok := feature["properties"]; ok && string(v) != geoJSONNull {
+		var properties map[string]json.RawMessage
+		if err := json.Unmarshal(v, &properties); err != nil {
+			return errors.Wrap(err, "invalid properties")
+		}
+		for name, v := range properties {
+			idx, ok := c.cols.propertyColIdx(name)
+			if !ok {
+				continue
+			}
+			datum, err := geoJSONValueToDatum(v, conv.VisibleColTypes[idx], conv.EvalCtx)
+			if err != nil {
+				return errors.Wrapf(err, "property %s", name)
+			}
+			conv.Datums[idx] = datum
+		}
+	}
+	return nil
+}
+
+// geoJSONRowStream implements importRowProducer interface. It reads the
+// features of either a FeatureCollection, whose features are streamed rather
+// than decoded at once, or of a sequence of Features such as newline-delimited
+// GeoJSON, and returns them as geoJSONFeatures.
+type geoJSONRowStream struct {
+	input *fileReader
+	dec   *json.Decoder
+	// inFeatures is set while reading the features array of a
+	// FeatureCollection.
+	inFeatures bool
+	feature    geoJSONFeature
+	err        error
+}
+
+var _ importRowProducer = &geoJSONRowStream{}
+
+// Progress implements importRowProducer interface.
+func (s *geoJSONRowStream) Progress() float32 {
+	return s.input.ReadFraction()
+}
+
+// Scan implements importRowProducer interface.
+func (s *geoJSONRowStream) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for {
+		if s.inFeatures {
+			if s.dec.More() {
+				s.feature = nil
+				s.err = s.dec.Decode(&s.feature)
+				return s.err == nil
+			}
+			// Consume the end of the features array, and the remaining members
+			// of the FeatureCollection.
+			if s.err = s.expectDelim(']'); s.err != nil {
+				return false
+			}
+			s.inFeatures = false
+			if _, s.err = s.readMembers(); s.err != nil {
+				return false
+			}
+			continue
+		}
+
+		if err := s.expectDelim('{'); err == io.EOF {
+			return false
+		} else if err != nil {
+			s.err = err
+			return false
+		}
+		s.feature, s.err = s.readMembers()
+		if s.err != nil {
+			return false
+		}
+		if s.inFeatures {
+			continue
+		}
+		return true
+	}
+}
+
+// expectDelim reads the next token, which must be the given delimiter.
+func (s *geoJSONRowStream) expectDelim(delim json.Delim) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return errors.Errorf("invalid GeoJSON: expected %s, found %v", delim, tok)
+	}
+	return nil
+}
+
+// readMembers reads the members of the current object up to its end, and
+// returns them. If a "features" member holding an array is found, reading
+// stops at the start of the array and inFeatures is set.
+func (s *geoJSONRowStream) readMembers() (geoJSONFeature, error) {
+	members := make(geoJSONFeature)
+	for s.dec.More() {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return nil, errors.Errorf("invalid GeoJSON: expected a member name, found %v", tok)
+		}
+		if name == "features" {
+			if err := s.expectDelim('['); err != nil {
+				return nil, err
+			}
+			s.inFeatures = true
+			return nil, nil
+		}
+		var v json.RawMessage
+		if err := s.dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		members[name] = v
+	}
+	if err := s.expectDelim('}'); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Err implements importRowProducer interface.
+func (s *geoJSONRowStream) Err() error {
+	return s.err
+}
+
+// Row implements importRowProducer interface.
+func (s *geoJSONRowStream) Row() (interface{}, error) {
+	return s.feature, nil
+}
+
+// Skip implements importRowProducer interface.
+func (s *geoJSONRowStream) Skip() error {
+	return nil
+}
+
+func newImportGeoJSONPipeline(
+	p *geoJSONInputReader, input *fileReader,
+) (importRowProducer, importRowConsumer, error) {
+	cols, err := makeGeoImportColumns(p.importContext.tableDesc, p.opts.GeometryColumn)
+	if err != nil {
+		return nil, nil, err
+	}
+	// GeoJSON coordinates are WGS 84 longitudes and latitudes, per RFC 7946.
+	srid := geopb.DefaultGeographySRID
+	if p.opts.SRID != 0 {
+		srid = geopb.SRID(p.opts.SRID)
+	}
+	producer := &geoJSONRowStream{input: input, dec: json.NewDecoder(input)}
+	consumer := &geoJSONConsumer{cols: cols, srid: srid}
+	return producer, consumer, nil
+}
+
+type geoJSONInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.GeoJSONOptions
+}
+
+var _ inputConverter = &geoJSONInputReader{}
+
+func newGeoJSONInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	geoJSONOpts roachpb.GeoJSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) (*geoJSONInputReader, error) {
+	return &geoJSONInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: geoJSONOpts,
+	}, nil
+}
+
+func (p *geoJSONInputReader) start(group ctxgroup.Group) {}
+
+func (p *geoJSONInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage)
+}
+
+func (p *geoJSONInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	producer, consumer, err := newImportGeoJSONPipeline(p, input)
+	if err != nil {
+		return err
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportGeoJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	desc := descForTable(t,
+		"CREATE TABLE t (id INT PRIMARY KEY, name STRING, attrs JSONB, geog GEOGRAPHY, geom GEOMETRY)",
+		10, 20, NoFKs)
+
+	// readRows imports the given GeoJSON, and returns the datums of each row
+	// formatted as strings.
+	readRows := func(t *testing.T, data string, opts roachpb.GeoJSONOptions) ([][]string, error) {
+		r, err := newGeoJSONInputReader(nil, desc, opts, 0, 1, &evalCtx)
+		require.NoError(t, err)
+		producer, consumer, err := newImportGeoJSONPipeline(r, &fileReader{Reader: strings.NewReader(data)})
+		if err != nil {
+			return nil, err
+		}
+		conv, err := row.NewDatumRowConverter(context.Background(), desc, nil, evalCtx.Copy(), nil)
+		require.NoError(t, err)
+		var rows [][]string
+		for producer.Scan() {
+			record, err := producer.Row()
+			require.NoError(t, err)
+			if err := consumer.FillDatums(record, int64(len(rows)), conv); err != nil {
+				return nil, err
+			}
+			strs := make([]string, len(conv.Datums))
+			for i, d := range conv.Datums {
+				strs[i] = d.String()
+			}
+			rows = append(rows, strs)
+		}
+		return rows, producer.Err()
+	}
+	geog := func(wkt string) string {
+		return tree.NewDGeography(geo.MustParseGeography(wkt)).String()
+	}
+	geom := func(wkt string) string {
+		return tree.NewDGeometry(geo.MustParseGeometry(wkt)).String()
+	}
+
+	t.Run("feature collection", func(t *testing.T) {
+		rows, err := readRows(t, `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "geometry": {"type": "Point", "coordinates": [1, 2]},
+      "properties": {"id": 1, "name": "foo", "attrs": {"a": [1, 2]}, "extra": true}
+    },
+    {
+      "type": "Feature",
+      "geometry": null,
+      "properties": {"ID": 2}
+    }
+  ],
+  "name": "places"
+}`, roachpb.GeoJSONOptions{})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"1", "'foo'", `'{"a": [1, 2]}'`, geog("SRID=4326;POINT(1 2)"), "NULL"},
+			{"2", "NULL", "NULL", "NULL", "NULL"},
+		}, rows)
+	})
+
+	t.Run("newline-delimited features", func(t *testing.T) {
+		rows, err := readRows(t, `{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]]}, "properties": {"id": 1, "attrs": "bar"}}
+{"type": "Feature", "geometry": {"type": "Point", "coordinates": [3, 4]}, "properties": {"id": 2, "name": "baz"}}
+`, roachpb.GeoJSONOptions{SRID: 3857, GeometryColumn: "geom"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"1", "NULL", `'"bar"'`, "NULL", geom("SRID=3857;LINESTRING(0 0, 1 1)")},
+			{"2", "'baz'", "NULL", "NULL", geom("SRID=3857;POINT(3 4)")},
+		}, rows)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			data     string
+			opts     roachpb.GeoJSONOptions
+			expected string
+		}{
+			{
+				data:     `{"type": "Point", "coordinates": [1, 2]}`,
+				expected: `expected a GeoJSON Feature, found "Point"`,
+			},
+			{
+				data:     `[{"type": "Feature"}]`,
+				expected: "invalid GeoJSON: expected {, found [",
+			},
+			{
+				data:     `{"type": "Feature", "properties": {"id": "abc"}}`,
+				expected: `property id: could not parse "abc" as type int`,
+			},
+			{
+				data:     `{"type": "Feature"}`,
+				opts:     roachpb.GeoJSONOptions{GeometryColumn: "missing"},
+				expected: "could not find geometry column missing",
+			},
+		} {
+			_, err := readRows(t, tc.data, tc.opts)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		}
+	})
+}
@@ -0,0 +1,390 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/errors"
+)
+
+// parquetReadBatchSize is the number of values read at once from each column
+// of a parquet file.
+const parquetReadBatchSize = 1024
+
+// julianDayOfUnixEpoch is the julian day number of 1970-01-01, used to decode
+// the legacy INT96 timestamps.
+const julianDayOfUnixEpoch = 2440588
+
+// parquetColumn describes a column of a parquet file.
+type parquetColumn struct {
+	name string
+	elem *parquet.SchemaElement
+}
+
+// parquetColumns returns the columns of the file read by pr. The reader only
+// supports flat schemas, in which each column holds a single optional or
+// required value.
+func parquetColumns(pr *parquet.Reader) []parquetColumn {
+	elems := pr.Columns()
+	cols := make([]parquetColumn, len(elems))
+	for i := range elems {
+		cols[i] = parquetColumn{name: lex.NormalizeName(elems[i].Name), elem: &elems[i]}
+	}
+	return cols
+}
+
+// parquetValueToDatum converts a value, as returned by the parquet reader, to
+// a datum of the target type.
+//
+// The reader returns values of go native types which depend on the physical
+// type of the column: bool, int32, int64, float32, float64, or string for byte
+// arrays. The logical (converted) type of the column is then used to interpret
+// dates, timestamps and decimals. Values that do not directly map to the
+// target type are converted through their string representation, as done by
+// the other import formats.
+func parquetValueToDatum(
+	x interface{}, elem *parquet.SchemaElement, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	var d tree.Datum
+	var err error
+
+	switch v := x.(type) {
+	case nil:
+		// Let the target table schema verify whether nulls are allowed.
+		return tree.DNull, nil
+	case bool:
+		d = tree.MakeDBool(tree.DBool(v))
+	case int32:
+		d, err = parquetIntToDatum(int64(v), elem, targetT)
+	case int64:
+		d, err = parquetIntToDatum(v, elem, targetT)
+	case float32:
+		d = tree.NewDFloat(tree.DFloat(v))
+	case float64:
+		d = tree.NewDFloat(tree.DFloat(v))
+	case string:
+		switch {
+		case elem.Type == parquet.TypeInt96:
+			d, err = parquetInt96ToDatum(v, targetT)
+		case elem.ConvertedType == parquet.ConvertedTypeDecimal:
+			var dec apd.Decimal
+			dec.Coeff.SetBytes([]byte(v))
+			if len(v) > 0 && v[0]&0x80 != 0 {
+				// The unscaled value is a big-endian two's complement integer.
+				dec.Coeff.Sub(&dec.Coeff, new(big.Int).Lsh(big.NewInt(1), uint(len(v)*8)))
+				dec.Coeff.Neg(&dec.Coeff)
+				dec.Negative = true
+			}
+			dec.Exponent = -elem.Scale
+			d = &tree.DDecimal{Decimal: dec}
+		case targetT.Family() == types.BytesFamily:
+			d = tree.NewDBytes(tree.DBytes(v))
+		default:
+			return sqlbase.ParseDatumStringAs(targetT, v, evalCtx)
+		}
+	default:
+		return nil, errors.Errorf("cannot handle type %T when converting to %s", x, targetT)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !targetT.Equivalent(d.ResolvedType()) {
+		// Go through the string representation, for example to import an
+		// integer into a DECIMAL column.
+		return sqlbase.ParseDatumStringAs(targetT, tree.AsStringWithFlags(d, tree.FmtExport), evalCtx)
+	}
+	return d, nil
+}
+
+// parquetIntToDatum converts an integer value of a column with the given
+// schema element to a datum.
+func parquetIntToDatum(
+	v int64, elem *parquet.SchemaElement, targetT *types.T,
+) (tree.Datum, error) {
+	switch elem.ConvertedType {
+	case parquet.ConvertedTypeDate:
+		date, err := pgdate.MakeDateFromUnixEpoch(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(date), nil
+	case parquet.ConvertedTypeTimestampMillis:
+		return makeParquetTimestamp(timeutil.Unix(0, v*int64(time.Millisecond)), targetT)
+	case parquet.ConvertedTypeTimestampMicros:
+		return makeParquetTimestamp(timeutil.Unix(0, v*int64(time.Microsecond)), targetT)
+	case parquet.ConvertedTypeDecimal:
+		var dec apd.Decimal
+		dec.SetFinite(v, -elem.Scale)
+		return &tree.DDecimal{Decimal: dec}, nil
+	default:
+		return tree.NewDInt(tree.DInt(v)), nil
+	}
+}
+
+// parquetInt96ToDatum converts a legacy INT96 timestamp to a datum. These
+// timestamps are made of the number of nanoseconds in the day followed by the
+// julian day number, both little-endian.
+func parquetInt96ToDatum(v string, targetT *types.T) (tree.Datum, error) {
+	if len(v) != 12 {
+		return nil, errors.Errorf("invalid INT96 value of length %d", len(v))
+	}
+	nanos := int64(binary.LittleEndian.Uint64([]byte(v[:8])))
+	days := int64(binary.LittleEndian.Uint32([]byte(v[8:])))
+	t := timeutil.Unix((days-julianDayOfUnixEpoch)*24*60*60, nanos)
+	return makeParquetTimestamp(t, targetT)
+}
+
+func makeParquetTimestamp(t time.Time, targetT *types.T) (tree.Datum, error) {
+	if targetT.Family() == types.TimestampTZFamily {
+		return tree.MakeDTimestampTZ(t, time.Microsecond)
+	}
+	return tree.MakeDTimestamp(t, time.Microsecond)
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	// colToIdx maps the columns of the file to the visible columns of the
+	// table; -1 if the column is ignored.
+	colToIdx []int
+	cols     []parquetColumn
+	strict   bool
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	record, ok := native.([]interface{})
+	if !ok {
+		return errors.Errorf("unexpected native type; expected []interface{} found %T instead", native)
+	}
+	for i, v := range record {
+		idx := p.colToIdx[i]
+		if idx < 0 {
+			continue
+		}
+		datum, err := parquetValueToDatum(v, p.cols[i].elem, conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "column %s", p.cols[i].name)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// Set any nil datums to DNull (in case the file does not have the
+	// column).
+	for i := range conv.Datums {
+		if _, isTargetCol := conv.IsTargetCol[i]; isTargetCol && conv.Datums[i] == nil {
+			if p.strict {
+				return errors.Errorf("column %s was not set in the parquet import", conv.VisibleCols[i].Name)
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// parquetRowStream implements importRowProducer interface. It reads the
+// columns of a parquet file in batches, and returns rows as []interface{}.
+type parquetRowStream struct {
+	reader  *parquet.Reader
+	numRows int64
+	numCols int
+	// batch holds the values read for each column.
+	batch    [][]interface{}
+	batchIdx int
+	// pos is the number of rows returned or skipped so far.
+	pos int64
+	err error
+}
+
+var _ importRowProducer = &parquetRowStream{}
+
+// Progress implements importRowProducer interface.
+func (s *parquetRowStream) Progress() float32 {
+	if s.numRows == 0 {
+		return 1
+	}
+	return float32(s.pos) / float32(s.numRows)
+}
+
+// Scan implements importRowProducer interface.
+func (s *parquetRowStream) Scan() bool {
+	if s.err != nil || s.pos >= s.numRows {
+		return false
+	}
+	if s.numCols > 0 && s.batchIdx < len(s.batch[0]) {
+		return true
+	}
+	s.fill()
+	return s.err == nil
+}
+
+func (s *parquetRowStream) fill() {
+	n := s.numRows - s.pos
+	if n > parquetReadBatchSize {
+		n = parquetReadBatchSize
+	}
+	for i := 0; i < s.numCols; i++ {
+		values, err := s.reader.ReadColumn(i, int(n))
+		if err != nil {
+			s.err = err
+			return
+		}
+		if int64(len(values)) != n {
+			s.err = errors.Errorf("expected %d values for column %d, found %d", n, i, len(values))
+			return
+		}
+		s.batch[i] = values
+	}
+	s.batchIdx = 0
+}
+
+// Err implements importRowProducer interface.
+func (s *parquetRowStream) Err() error {
+	return s.err
+}
+
+// Row implements importRowProducer interface.
+func (s *parquetRowStream) Row() (interface{}, error) {
+	record := make([]interface{}, s.numCols)
+	for i := range record {
+		record[i] = s.batch[i][s.batchIdx]
+	}
+	s.batchIdx++
+	s.pos++
+	return record, nil
+}
+
+// Skip implements importRowProducer interface.
+func (s *parquetRowStream) Skip() error {
+	s.batchIdx++
+	s.pos++
+	return nil
+}
+
+func newImportParquetPipeline(
+	p *parquetInputReader, data []byte,
+) (importRowProducer, importRowConsumer, error) {
+	pr, err := parquet.NewReader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	cols := parquetColumns(pr)
+
+	colIdxByName := make(map[string]int)
+	for idx, col := range p.importContext.tableDesc.VisibleColumns() {
+		colIdxByName[col.Name] = idx
+	}
+	colToIdx := make([]int, len(cols))
+	for i := range cols {
+		idx, ok := colIdxByName[cols[i].name]
+		if !ok {
+			if p.opts.StrictMode {
+				return nil, nil, errors.Errorf("could not find column for parquet column %s", cols[i].name)
+			}
+			idx = -1
+		}
+		colToIdx[i] = idx
+	}
+
+	consumer := &parquetConsumer{
+		colToIdx: colToIdx,
+		cols:     cols,
+		strict:   p.opts.StrictMode,
+	}
+	producer := &parquetRowStream{
+		reader:  pr,
+		numRows: pr.NumRows(),
+		numCols: len(cols),
+		batch:   make([][]interface{}, len(cols)),
+	}
+	return producer, consumer, nil
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	parquetOpts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) (*parquetInputReader, error) {
+
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: parquetOpts,
+	}, nil
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage)
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	// The metadata of parquet files is stored in their footer, so the whole
+	// file is buffered before it is decoded.
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	producer, consumer, err := newImportParquetPipeline(p, data)
+	if err != nil {
+		return err
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
@@ -0,0 +1,722 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/cockroachdb/cockroach/pkg/geo/geoprojbase"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+	"github.com/twpayne/go-geom"
+)
+
+const (
+	// shpFileCode is the magic number at the start of a .shp file.
+	shpFileCode = 9994
+	// shpHeaderSize is the size of the header of a .shp file.
+	shpHeaderSize = 100
+	// shpRecordHeaderSize is the size of the header of each record of a .shp
+	// file.
+	shpRecordHeaderSize = 8
+
+	// dbfHeaderSize is the size of the fixed part of the header of a .dbf
+	// file, which is followed by the field descriptors.
+	dbfHeaderSize = 32
+	// dbfFieldDescriptorSize is the size of each field descriptor of a .dbf
+	// file.
+	dbfFieldDescriptorSize = 32
+	// dbfHeaderTerminator ends the field descriptors of a .dbf file.
+	dbfHeaderTerminator = 0x0D
+	// dbfDeletedRecord flags a deleted record of a .dbf file.
+	dbfDeletedRecord = '*'
+)
+
+// Shape types of the records of a .shp file. Z and M shapes are imported as
+// their 2D equivalent, as only 2D geometries are supported.
+const (
+	shpNull        = 0
+	shpPoint       = 1
+	shpPolyLine    = 3
+	shpPolygon     = 5
+	shpMultiPoint  = 8
+	shpPointZ      = 11
+	shpPolyLineZ   = 13
+	shpPolygonZ    = 15
+	shpMultiPointZ = 18
+	shpPointM      = 21
+	shpPolyLineM   = 23
+	shpPolygonM    = 25
+	shpMultiPointM = 28
+)
+
+// shapefile holds the content of the files making up a shapefile.
+type shapefile struct {
+	shp []byte
+	// dbf and prj are nil if the shapefile has no attributes or no projection.
+	dbf []byte
+	prj []byte
+}
+
+// readZippedShapefile reads the shapefile contained in the given zip archive.
+// The archive must contain exactly one .shp file, along with its .dbf and
+// .prj files if any.
+func readZippedShapefile(data []byte) (shapefile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return shapefile{}, errors.Wrap(err, "shapefiles must be imported from a zip archive")
+	}
+	files := make(map[string]*zip.File)
+	var base string
+	for _, f := range zr.File {
+		// Skip directories and the resource forks added by macOS.
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Name))
+		name := strings.TrimSuffix(f.Name, path.Ext(f.Name))
+		if ext == ".shp" {
+			if base != "" {
+				return shapefile{}, errors.Errorf(
+					"zip archive contains several shapefiles: %s.shp and %s", base, f.Name)
+			}
+			base = name
+		}
+		files[strings.ToLower(name)+ext] = f
+	}
+	if base == "" {
+		return shapefile{}, errors.New("zip archive does not contain a .shp file")
+	}
+	readEntry := func(ext string) ([]byte, error) {
+		f, ok := files[strings.ToLower(base)+ext]
+		if !ok {
+			return nil, nil
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	}
+	var s shapefile
+	if s.shp, err = readEntry(".shp"); err != nil {
+		return shapefile{}, err
+	}
+	if s.dbf, err = readEntry(".dbf"); err != nil {
+		return shapefile{}, err
+	}
+	if s.prj, err = readEntry(".prj"); err != nil {
+		return shapefile{}, err
+	}
+	return s, nil
+}
+
+// shapefileSRID returns the SRID of the geometries of a shapefile with the
+// given projection. An SRID specified in the options takes precedence.
+func shapefileSRID(prj []byte, opts roachpb.ShapefileOptions) (geopb.SRID, error) {
+	if opts.SRID != 0 {
+		return geopb.SRID(opts.SRID), nil
+	}
+	if len(bytes.TrimSpace(prj)) == 0 {
+		return geopb.DefaultGeometrySRID, nil
+	}
+	srid, ok := geoprojbase.SRIDFromWKT(string(prj))
+	if !ok {
+		return 0, errors.WithHint(
+			errors.Errorf("unknown projection in .prj file: %s", prj),
+			"specify the SRID of the geometries with the srid option.")
+	}
+	return srid, nil
+}
+
+// dbfField describes a field of a .dbf file.
+type dbfField struct {
+	name string
+	// typ is the dBase type of the field, such as 'C' for characters or 'N'
+	// for numbers.
+	typ    byte
+	length int
+}
+
+// dbfReader reads the records of a .dbf file.
+type dbfReader struct {
+	fields     []dbfField
+	numRecords int
+	recordLen  int
+	data       []byte
+	pos        int
+}
+
+func newDBFReader(data []byte) (*dbfReader, error) {
+	if len(data) < dbfHeaderSize {
+		return nil, errors.New("invalid .dbf file: truncated header")
+	}
+	r := &dbfReader{
+		numRecords: int(binary.LittleEndian.Uint32(data[4:8])),
+		recordLen:  int(binary.LittleEndian.Uint16(data[10:12])),
+		data:       data,
+		pos:        int(binary.LittleEndian.Uint16(data[8:10])),
+	}
+	if r.pos > len(data) {
+		return nil, errors.New("invalid .dbf file: truncated header")
+	}
+	// The first byte of each record is the deletion flag.
+	recordLen := 1
+	for off := dbfHeaderSize; off < r.pos; off += dbfFieldDescriptorSize {
+		if data[off] == dbfHeaderTerminator {
+			break
+		}
+		if off+dbfFieldDescriptorSize > len(data) {
+			return nil, errors.New("invalid .dbf file: truncated field descriptor")
+		}
+		desc := data[off : off+dbfFieldDescriptorSize]
+		name := desc[:11]
+		if i := bytes.IndexByte(name, 0); i >= 0 {
+			name = name[:i]
+		}
+		f := dbfField{name: string(name), typ: desc[11], length: int(desc[16])}
+		r.fields = append(r.fields, f)
+		recordLen += f.length
+	}
+	if recordLen != r.recordLen {
+		return nil, errors.Errorf(
+			"invalid .dbf file: record length %d does not match fields length %d", r.recordLen, recordLen)
+	}
+	return r, nil
+}
+
+// next returns the raw values of the fields of the next record, and whether
+// the record is deleted. Returns io.EOF once all records have been read.
+func (r *dbfReader) next() ([]string, bool, error) {
+	if r.numRecords == 0 {
+		return nil, false, io.EOF
+	}
+	if r.pos+r.recordLen > len(r.data) {
+		return nil, false, errors.New("invalid .dbf file: truncated record")
+	}
+	record := r.data[r.pos : r.pos+r.recordLen]
+	r.pos += r.recordLen
+	r.numRecords--
+	values := make([]string, len(r.fields))
+	off := 1
+	for i, f := range r.fields {
+		values[i] = string(record[off : off+f.length])
+		off += f.length
+	}
+	return values, record[0] == dbfDeletedRecord, nil
+}
+
+// dbfValueToDatum converts the raw value of a field of a .dbf file to a datum
+// of the target type. Blank values of fields other than character fields are
+// imported as NULL.
+func dbfValueToDatum(
+	v string, f dbfField, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	switch f.typ {
+	case 'C':
+		return sqlbase.ParseDatumStringAs(targetT, strings.TrimRight(v, " \x00"), evalCtx)
+	case 'L':
+		switch strings.TrimSpace(v) {
+		case "T", "t", "Y", "y":
+			v = "true"
+		case "F", "f", "N", "n":
+			v = "false"
+		default:
+			return tree.DNull, nil
+		}
+	case 'D':
+		// Dates are stored as YYYYMMDD.
+		v = strings.TrimSpace(v)
+		if len(v) != 8 || v == "00000000" {
+			return tree.DNull, nil
+		}
+		v = v[:4] + "-" + v[4:6] + "-" + v[6:]
+	default:
+		v = strings.Trim(v, " \x00")
+		if v == "" {
+			return tree.DNull, nil
+		}
+	}
+	return sqlbase.ParseDatumStringAs(targetT, v, evalCtx)
+}
+
+// shpReader reads the records of a .shp file.
+type shpReader struct {
+	data []byte
+	pos  int
+}
+
+func newSHPReader(data []byte) (*shpReader, error) {
+	if len(data) < shpHeaderSize {
+		return nil, errors.New("invalid .shp file: truncated header")
+	}
+	if code := binary.BigEndian.Uint32(data[:4]); code != shpFileCode {
+		return nil, errors.Errorf("invalid .shp file: unexpected file code %d", code)
+	}
+	// The file length is in 16-bit words.
+	if n := int(binary.BigEndian.Uint32(data[24:28])) * 2; n < len(data) {
+		data = data[:n]
+	}
+	return &shpReader{data: data, pos: shpHeaderSize}, nil
+}
+
+// next returns the content of the next record. Returns io.EOF once all
+// records have been read.
+func (r *shpReader) next() ([]byte, error) {
+	if r.pos >= len(r.data) {
+		return nil, io.EOF
+	}
+	if r.pos+shpRecordHeaderSize > len(r.data) {
+		return nil, errors.New("invalid .shp file: truncated record header")
+	}
+	// The content length is in 16-bit words.
+	n := int(binary.BigEndian.Uint32(r.data[r.pos+4:r.pos+8])) * 2
+	start := r.pos + shpRecordHeaderSize
+	if start+n > len(r.data) {
+		return nil, errors.New("invalid .shp file: truncated record")
+	}
+	r.pos = start + n
+	return r.data[start:r.pos], nil
+}
+
+// fraction returns the fraction of the file read so far.
+func (r *shpReader) fraction() float32 {
+	return float32(r.pos) / float32(len(r.data))
+}
+
+// shpRecordReader decodes the little-endian content of a .shp record.
+type shpRecordReader struct {
+	b   []byte
+	err error
+}
+
+func (r *shpRecordReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.b) {
+		r.err = errors.New("invalid .shp file: truncated shape")
+		return nil
+	}
+	ret := r.b[:n]
+	r.b = r.b[n:]
+	return ret
+}
+
+func (r *shpRecordReader) int32() int {
+	b := r.read(4)
+	if b == nil {
+		return 0
+	}
+	return int(int32(binary.LittleEndian.Uint32(b)))
+}
+
+func (r *shpRecordReader) float64s(n int) []float64 {
+	b := r.read(8 * n)
+	if b == nil {
+		return nil
+	}
+	ret := make([]float64, n)
+	for i := range ret {
+		ret[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return ret
+}
+
+// parseShape parses the content of a .shp record into a geometry with the
+// given SRID. Returns nil for null shapes.
+//
+// PolyLines with several parts are imported as MultiLineStrings. The rings of
+// Polygons are grouped into polygons, each made of a clockwise outer ring
+// and of the counter-clockwise holes it contains; Polygons with several outer
+// rings are imported as MultiPolygons.
+func parseShape(content []byte, srid geopb.SRID) (*geo.Geometry, error) {
+	r := &shpRecordReader{b: content}
+	shapeType := r.int32()
+	var t geom.T
+	switch shapeType {
+	case shpNull:
+		return nil, r.err
+	case shpPoint, shpPointZ, shpPointM:
+		coords := r.float64s(2)
+		if r.err != nil {
+			return nil, r.err
+		}
+		t = geom.NewPointFlat(geom.XY, coords).SetSRID(int(srid))
+	case shpMultiPoint, shpMultiPointZ, shpMultiPointM:
+		// Skip the bounding box.
+		r.read(32)
+		numPoints := r.int32()
+		coords := r.float64s(2 * numPoints)
+		if r.err != nil {
+			return nil, r.err
+		}
+		t = geom.NewMultiPointFlat(geom.XY, coords).SetSRID(int(srid))
+	case shpPolyLine, shpPolyLineZ, shpPolyLineM, shpPolygon, shpPolygonZ, shpPolygonM:
+		// Skip the bounding box.
+		r.read(32)
+		numParts := r.int32()
+		numPoints := r.int32()
+		if numParts < 0 || numPoints < 0 {
+			return nil, errors.New("invalid .shp file: negative number of parts or points")
+		}
+		// Each part takes 4 bytes and each point 16 bytes; check the counts
+		// against the remaining content before allocating for them.
+		if numParts > len(r.b)/4 || numPoints > (len(r.b)-4*numParts)/16 {
+			return nil, errors.New("invalid .shp file: truncated shape")
+		}
+		starts := make([]int, numParts)
+		for i := range starts {
+			starts[i] = r.int32()
+		}
+		coords := r.float64s(2 * numPoints)
+		if r.err != nil {
+			return nil, r.err
+		}
+		// ends holds the end offset of each part in coords.
+		ends := make([]int, numParts)
+		for i, start := range starts {
+			end := numPoints
+			if i+1 < numParts {
+				end = starts[i+1]
+			}
+			if start < 0 || start > end || end > numPoints {
+				return nil, errors.New("invalid .shp file: invalid part offsets")
+			}
+			ends[i] = 2 * end
+		}
+		switch shapeType {
+		case shpPolyLine, shpPolyLineZ, shpPolyLineM:
+			if numParts <= 1 {
+				t = geom.NewLineStringFlat(geom.XY, coords).SetSRID(int(srid))
+			} else {
+				t = geom.NewMultiLineStringFlat(geom.XY, coords, ends).SetSRID(int(srid))
+			}
+		default:
+			t = makeShapePolygon(coords, ends, srid)
+		}
+	default:
+		return nil, errors.Errorf("unsupported shape type %d", shapeType)
+	}
+	return geo.NewGeometryFromGeom(t)
+}
+
+// makeShapePolygon returns the Polygon or MultiPolygon made of the rings of a
+// shapefile Polygon, given as flat XY coordinates and the end offset of each
+// ring. Outer rings are clockwise; each counter-clockwise ring is a hole of
+// the outer ring containing it, or is itself an outer ring if there is none.
+func makeShapePolygon(coords []float64, ends []int, srid geopb.SRID) geom.T {
+	var shells, holes [][]float64
+	start := 0
+	for _, end := range ends {
+		ring := coords[start:end]
+		start = end
+		if len(ring) == 0 {
+			continue
+		}
+		if ringSignedArea(ring) < 0 {
+			shells = append(shells, ring)
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+	polygons := make([][][]float64, len(shells))
+	for i, shell := range shells {
+		polygons[i] = [][]float64{shell}
+	}
+	for _, hole := range holes {
+		found := false
+		for i, shell := range shells {
+			if ringContainsPoint(shell, hole[0], hole[1]) {
+				polygons[i] = append(polygons[i], hole)
+				found = true
+				break
+			}
+		}
+		if !found {
+			polygons = append(polygons, [][]float64{hole})
+		}
+	}
+
+	switch len(polygons) {
+	case 0:
+		return geom.NewPolygon(geom.XY).SetSRID(int(srid))
+	case 1:
+		var flatCoords []float64
+		var ends []int
+		for _, ring := range polygons[0] {
+			flatCoords = append(flatCoords, ring...)
+			ends = append(ends, len(flatCoords))
+		}
+		return geom.NewPolygonFlat(geom.XY, flatCoords, ends).SetSRID(int(srid))
+	default:
+		var flatCoords []float64
+		endss := make([][]int, len(polygons))
+		for i, polygon := range polygons {
+			for _, ring := range polygon {
+				flatCoords = append(flatCoords, ring...)
+				endss[i] = append(endss[i], len(flatCoords))
+			}
+		}
+		return geom.NewMultiPolygonFlat(geom.XY, flatCoords, endss).SetSRID(int(srid))
+	}
+}
+
+// ringSignedArea returns the signed area of a ring given as flat XY
+// coordinates, which is negative if the ring is clockwise.
+func ringSignedArea(ring []float64) float64 {
+	var area float64
+	for i := 0; i+3 < len(ring); i += 2 {
+		area += ring[i]*ring[i+3] - ring[i+2]*ring[i+1]
+	}
+	return area / 2
+}
+
+// ringContainsPoint returns whether the point (x, y) is inside the ring given
+// as flat XY coordinates, using the even-odd rule.
+func ringContainsPoint(ring []float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-2; i < len(ring); j, i = i, i+2 {
+		xi, yi, xj, yj := ring[i], ring[i+1], ring[j], ring[j+1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// shapefileRecord is a record of a shapefile: the content of its .shp record
+// and the raw values of the fields of its .dbf record.
+type shapefileRecord struct {
+	shape  []byte
+	values []string
+}
+
+// shapefileConsumer implements importRowConsumer interface.
+type shapefileConsumer struct {
+	cols   geoImportColumns
+	srid   geopb.SRID
+	fields []dbfField
+	// fieldToIdx maps the fields of the .dbf file to the visible columns of
+	// the table; -1 if the field is ignored.
+	fieldToIdx []int
+}
+
+var _ importRowConsumer = &shapefileConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (c *shapefileConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	record, ok := native.(shapefileRecord)
+	if !ok {
+		return errors.Errorf("unexpected native type; expected shapefileRecord found %T instead", native)
+	}
+	resetDatums(conv)
+	g, err := parseShape(record.shape, c.srid)
+	if err != nil {
+		return err
+	}
+	if err := c.cols.setGeometry(g, conv); err != nil {
+		return err
+	}
+	for i, v := range record.values {
+		idx := c.fieldToIdx[i]
+		if idx < 0 {
+			continue
+		}
+		datum, err := dbfValueToDatum(v, c.fields[i], conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", c.fields[i].name)
+		}
+		conv.Datums[idx] = datum
+	}
+	return nil
+}
+
+// shapefileRowStream implements importRowProducer interface. It reads the
+// records of the .shp and .dbf files of a shapefile in lockstep, skipping
+// deleted records, and returns them as shapefileRecords.
+type shapefileRowStream struct {
+	shp *shpReader
+	// dbf is nil if the shapefile has no .dbf file.
+	dbf    *dbfReader
+	record shapefileRecord
+	err    error
+}
+
+var _ importRowProducer = &shapefileRowStream{}
+
+// Progress implements importRowProducer interface.
+func (s *shapefileRowStream) Progress() float32 {
+	return s.shp.fraction()
+}
+
+// Scan implements importRowProducer interface.
+func (s *shapefileRowStream) Scan() bool {
+	for s.err == nil {
+		shape, err := s.shp.next()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			s.err = err
+			return false
+		}
+		s.record = shapefileRecord{shape: shape}
+		if s.dbf == nil {
+			return true
+		}
+		values, deleted, err := s.dbf.next()
+		if err == io.EOF {
+			s.err = errors.New(".dbf file has fewer records than .shp file")
+			return false
+		}
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if !deleted {
+			s.record.values = values
+			return true
+		}
+	}
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (s *shapefileRowStream) Err() error {
+	return s.err
+}
+
+// Row implements importRowProducer interface.
+func (s *shapefileRowStream) Row() (interface{}, error) {
+	return s.record, nil
+}
+
+// Skip implements importRowProducer interface.
+func (s *shapefileRowStream) Skip() error {
+	return nil
+}
+
+func newImportShapefilePipeline(
+	p *shapefileInputReader, data []byte,
+) (importRowProducer, importRowConsumer, error) {
+	sf, err := readZippedShapefile(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	srid, err := shapefileSRID(sf.prj, p.opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	cols, err := makeGeoImportColumns(p.importContext.tableDesc, p.opts.GeometryColumn)
+	if err != nil {
+		return nil, nil, err
+	}
+	shp, err := newSHPReader(sf.shp)
+	if err != nil {
+		return nil, nil, err
+	}
+	producer := &shapefileRowStream{shp: shp}
+	consumer := &shapefileConsumer{cols: cols, srid: srid}
+	if sf.dbf != nil {
+		if producer.dbf, err = newDBFReader(sf.dbf); err != nil {
+			return nil, nil, err
+		}
+		consumer.fields = producer.dbf.fields
+		consumer.fieldToIdx = make([]int, len(consumer.fields))
+		for i, f := range consumer.fields {
+			idx, ok := cols.propertyColIdx(f.name)
+			if !ok {
+				idx = -1
+			}
+			consumer.fieldToIdx[i] = idx
+		}
+	}
+	return producer, consumer, nil
+}
+
+type shapefileInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ShapefileOptions
+}
+
+var _ inputConverter = &shapefileInputReader{}
+
+func newShapefileInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	shapefileOpts roachpb.ShapefileOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) (*shapefileInputReader, error) {
+	return &shapefileInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+		},
+		opts: shapefileOpts,
+	}, nil
+}
+
+func (p *shapefileInputReader) start(group ctxgroup.Group) {}
+
+func (p *shapefileInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage)
+}
+
+func (p *shapefileInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	// Shapefiles are imported from zip archives, whose directory is stored at
+	// their end, so the whole archive is buffered before it is decoded.
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	producer, consumer, err := newImportShapefilePipeline(p, data)
+	if err != nil {
+		return err
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
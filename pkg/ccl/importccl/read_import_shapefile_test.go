@@ -0,0 +1,333 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// testShape encodes the content of a .shp record of the given shape type.
+// Multi-part shapes are given as one slice of flat XY coordinates per part.
+func testShape(shapeType int32, parts ...[]float64) []byte {
+	var buf bytes.Buffer
+	write := func(v interface{}) {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	write(shapeType)
+	var coords []float64
+	for _, part := range parts {
+		coords = append(coords, part...)
+	}
+	switch shapeType {
+	case shpNull:
+	case shpPoint:
+		write(coords)
+	case shpPointZ:
+		write(coords)
+		write([]float64{0 /* z */, 0 /* m */})
+	case shpMultiPoint:
+		write(make([]float64, 4) /* bbox */)
+		write(int32(len(coords) / 2))
+		write(coords)
+	default:
+		write(make([]float64, 4) /* bbox */)
+		write(int32(len(parts)))
+		write(int32(len(coords) / 2))
+		start := int32(0)
+		for _, part := range parts {
+			write(start)
+			start += int32(len(part) / 2)
+		}
+		write(coords)
+	}
+	return buf.Bytes()
+}
+
+// testSHPFile encodes a .shp file made of the given shapes.
+func testSHPFile(shapes ...[]byte) []byte {
+	var records bytes.Buffer
+	for i, shape := range shapes {
+		_ = binary.Write(&records, binary.BigEndian, []int32{int32(i + 1), int32(len(shape) / 2)})
+		records.Write(shape)
+	}
+	header := make([]byte, shpHeaderSize)
+	binary.BigEndian.PutUint32(header[0:], shpFileCode)
+	binary.BigEndian.PutUint32(header[24:], uint32((shpHeaderSize+records.Len())/2))
+	binary.LittleEndian.PutUint32(header[28:], 1000 /* version */)
+	return append(header, records.Bytes()...)
+}
+
+// testDBFFile encodes a .dbf file with the given fields and records. Records
+// starting with dbfDeletedRecord are deleted.
+func testDBFFile(fields []dbfField, records [][]string) []byte {
+	recordLen := 1
+	for _, f := range fields {
+		recordLen += f.length
+	}
+	headerLen := dbfHeaderSize + dbfFieldDescriptorSize*len(fields) + 1
+	var buf bytes.Buffer
+	header := make([]byte, dbfHeaderSize)
+	header[0] = 3 // dBase III
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(records)))
+	binary.LittleEndian.PutUint16(header[8:], uint16(headerLen))
+	binary.LittleEndian.PutUint16(header[10:], uint16(recordLen))
+	buf.Write(header)
+	for _, f := range fields {
+		desc := make([]byte, dbfFieldDescriptorSize)
+		copy(desc, f.name)
+		desc[11] = f.typ
+		desc[16] = byte(f.length)
+		buf.Write(desc)
+	}
+	buf.WriteByte(dbfHeaderTerminator)
+	for _, record := range records {
+		flag := " "
+		if record[0] == string(dbfDeletedRecord) {
+			flag, record = record[0], record[1:]
+		}
+		buf.WriteString(flag)
+		for i, f := range fields {
+			v := record[i]
+			for len(v) < f.length {
+				v += " "
+			}
+			buf.WriteString(v)
+		}
+	}
+	buf.WriteByte(0x1A)
+	return buf.Bytes()
+}
+
+// testZip returns a zip archive of the given files.
+func testZip(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParseShape(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	square := func(x, y, size float64, clockwise bool) []float64 {
+		if clockwise {
+			return []float64{x, y, x, y + size, x + size, y + size, x + size, y, x, y}
+		}
+		return []float64{x, y, x + size, y, x + size, y + size, x, y + size, x, y}
+	}
+
+	testCases := []struct {
+		desc     string
+		shape    []byte
+		expected string
+	}{
+		{
+			desc:     "null",
+			shape:    testShape(shpNull),
+			expected: "",
+		},
+		{
+			desc:     "point",
+			shape:    testShape(shpPoint, []float64{1, 2}),
+			expected: "POINT(1 2)",
+		},
+		{
+			desc:     "point z",
+			shape:    testShape(shpPointZ, []float64{1, 2}),
+			expected: "POINT(1 2)",
+		},
+		{
+			desc:     "multipoint",
+			shape:    testShape(shpMultiPoint, []float64{1, 2, 3, 4}),
+			expected: "MULTIPOINT(1 2, 3 4)",
+		},
+		{
+			desc:     "polyline",
+			shape:    testShape(shpPolyLine, []float64{0, 0, 1, 1, 2, 0}),
+			expected: "LINESTRING(0 0, 1 1, 2 0)",
+		},
+		{
+			desc:     "polyline with several parts",
+			shape:    testShape(shpPolyLine, []float64{0, 0, 1, 1}, []float64{2, 2, 3, 3}),
+			expected: "MULTILINESTRING((0 0, 1 1), (2 2, 3 3))",
+		},
+		{
+			desc:     "polygon with a hole",
+			shape:    testShape(shpPolygon, square(0, 0, 10, true), square(1, 1, 2, false)),
+			expected: "POLYGON((0 0, 0 10, 10 10, 10 0, 0 0), (1 1, 3 1, 3 3, 1 3, 1 1))",
+		},
+		{
+			desc: "polygon with several outer rings",
+			shape: testShape(shpPolygon,
+				square(0, 0, 10, true), square(20, 0, 10, true), square(21, 1, 2, false)),
+			expected: "MULTIPOLYGON(((0 0, 0 10, 10 10, 10 0, 0 0)), " +
+				"((20 0, 20 10, 30 10, 30 0, 20 0), (21 1, 23 1, 23 3, 21 3, 21 1)))",
+		},
+		{
+			desc:     "polygon with a counter-clockwise outer ring",
+			shape:    testShape(shpPolygon, square(0, 0, 1, false)),
+			expected: "POLYGON((0 0, 1 0, 1 1, 0 1, 0 0))",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g, err := parseShape(tc.shape, 4326)
+			require.NoError(t, err)
+			if tc.expected == "" {
+				require.Nil(t, g)
+				return
+			}
+			require.Equal(t, geo.MustParseGeometry("SRID=4326;"+tc.expected), g)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := parseShape(testShape(31 /* MultiPatch */), 0)
+		require.EqualError(t, err, "unsupported shape type 31")
+		_, err = parseShape(testShape(shpPolyLine, []float64{0, 0, 1, 1})[:20], 0)
+		require.EqualError(t, err, "invalid .shp file: truncated shape")
+
+		// Part and point counts exceeding the content are rejected before
+		// allocating for them.
+		for _, offset := range []int{36 /* numParts */, 40 /* numPoints */} {
+			shape := testShape(shpPolyLine, []float64{0, 0, 1, 1})
+			binary.LittleEndian.PutUint32(shape[offset:], math.MaxInt32)
+			_, err = parseShape(shape, 0)
+			require.EqualError(t, err, "invalid .shp file: truncated shape")
+		}
+	})
+}
+
+func TestImportShapefile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fields := []dbfField{
+		{name: "ID", typ: 'N', length: 4},
+		{name: "NAME", typ: 'C', length: 8},
+		{name: "BUILT", typ: 'D', length: 8},
+		{name: "IGNORED", typ: 'L', length: 1},
+	}
+	shp := testSHPFile(
+		testShape(shpPoint, []float64{1, 2}),
+		testShape(shpPoint, []float64{3, 4}),
+		testShape(shpNull),
+	)
+	dbf := testDBFFile(fields, [][]string{
+		{"1", "foo", "20200102", "T"},
+		{"*", "2", "deleted", "", "F"},
+		{"3", "", "", "?"},
+	})
+	prj := []byte(`GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],` +
+		`PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`)
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	desc := descForTable(t,
+		"CREATE TABLE t (id INT PRIMARY KEY, name STRING, geom GEOMETRY, built DATE, other STRING)",
+		10, 20, NoFKs)
+
+	readRows := func(t *testing.T, data []byte, opts roachpb.ShapefileOptions) [][]tree.Datum {
+		r, err := newShapefileInputReader(nil, desc, opts, 0, 1, &evalCtx)
+		require.NoError(t, err)
+		producer, consumer, err := newImportShapefilePipeline(r, data)
+		require.NoError(t, err)
+		conv, err := row.NewDatumRowConverter(context.Background(), desc, nil, evalCtx.Copy(), nil)
+		require.NoError(t, err)
+		var rows [][]tree.Datum
+		for producer.Scan() {
+			record, err := producer.Row()
+			require.NoError(t, err)
+			require.NoError(t, consumer.FillDatums(record, int64(len(rows)), conv))
+			rows = append(rows, append([]tree.Datum(nil), conv.Datums...))
+		}
+		require.NoError(t, producer.Err())
+		require.Equal(t, float32(1), producer.Progress())
+		return rows
+	}
+	point := func(wkt string) tree.Datum {
+		return tree.NewDGeometry(geo.MustParseGeometry(wkt))
+	}
+	date := func(s string) tree.Datum {
+		d, err := tree.ParseDDate(nil, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("projection from the .prj file", func(t *testing.T) {
+		data := testZip(t, map[string][]byte{
+			"dir/roads.shp": shp, "dir/roads.dbf": dbf, "dir/roads.prj": prj,
+		})
+		require.Equal(t, [][]tree.Datum{
+			{tree.NewDInt(1), tree.NewDString("foo"), point("SRID=4326;POINT(1 2)"), date("2020-01-02"), tree.DNull},
+			{tree.NewDInt(3), tree.NewDString(""), tree.DNull, tree.DNull, tree.DNull},
+		}, readRows(t, data, roachpb.ShapefileOptions{}))
+	})
+
+	t.Run("srid option", func(t *testing.T) {
+		data := testZip(t, map[string][]byte{"roads.shp": shp, "roads.dbf": dbf, "roads.prj": prj})
+		rows := readRows(t, data, roachpb.ShapefileOptions{SRID: 3857})
+		require.Equal(t, point("SRID=3857;POINT(1 2)"), rows[0][2])
+	})
+
+	t.Run("no .prj file", func(t *testing.T) {
+		data := testZip(t, map[string][]byte{"roads.SHP": shp, "roads.DBF": dbf})
+		rows := readRows(t, data, roachpb.ShapefileOptions{})
+		require.Equal(t, point("POINT(1 2)"), rows[0][2])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r, err := newShapefileInputReader(nil, desc, roachpb.ShapefileOptions{}, 0, 1, &evalCtx)
+		require.NoError(t, err)
+		for _, tc := range []struct {
+			data     []byte
+			expected string
+		}{
+			{
+				data:     shp,
+				expected: "shapefiles must be imported from a zip archive: zip: not a valid zip file",
+			},
+			{
+				data:     testZip(t, map[string][]byte{"roads.dbf": dbf}),
+				expected: "zip archive does not contain a .shp file",
+			},
+			{
+				data:     testZip(t, map[string][]byte{"roads.shp": shp, "roads.prj": []byte(`PROJCS["Local"]`)}),
+				expected: `unknown projection in .prj file: PROJCS["Local"]`,
+			},
+		} {
+			_, _, err := newImportShapefilePipeline(r, tc.data)
+			require.EqualError(t, err, tc.expected)
+		}
+
+		r.opts.GeometryColumn = "name"
+		_, _, err = newImportShapefilePipeline(r, testZip(t, map[string][]byte{"roads.shp": shp}))
+		require.EqualError(t, err,
+			"column name must be of type GEOMETRY or GEOGRAPHY to hold the imported geometries, found STRING")
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package serverccl
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStoresEncryptionKeyStats verifies that the stores endpoint breaks the
+// files of an encrypted store down by the data key encrypting them.
+func TestStoresEncryptionKeyStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	keyFile := filepath.Join(dir, "16.key")
+	require.NoError(t, ioutil.WriteFile(
+		keyFile, []byte("111111111111111111111111111111111234567890123456"), 0600))
+	var encOptions baseccl.EncryptionOptions
+	encOptions.KeySource = baseccl.EncryptionKeySource_KeyFiles
+	encOptions.KeyFiles = &baseccl.EncryptionKeyFiles{
+		CurrentKey: keyFile,
+		OldKey:     "plain",
+	}
+	encOptions.DataKeyRotationPeriod = 1000 // arbitrary seconds
+	encOptionsBytes, err := protoutil.Marshal(&encOptions)
+	require.NoError(t, err)
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{
+		StoreSpecs: []base.StoreSpec{{
+			Path:            filepath.Join(dir, "store"),
+			UseFileRegistry: true,
+			ExtraOptions:    encOptionsBytes,
+		}},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	var resp serverpb.StoresResponse
+	require.NoError(t, serverutils.GetJSONProto(s, "/_status/stores/local", &resp))
+	require.Len(t, resp.Stores, 1)
+	store := resp.Stores[0]
+
+	var status enginepbccl.EncryptionStatus
+	require.NoError(t, protoutil.Unmarshal(store.EncryptionStatus, &status))
+	require.NotNil(t, status.ActiveDataKey)
+
+	var files uint64
+	var found bool
+	for _, ks := range store.KeyStats {
+		files += ks.Files
+		if ks.KeyID != status.ActiveDataKey.KeyId {
+			continue
+		}
+		found = true
+		require.Equal(t, store.ActiveKeyFiles, ks.Files)
+		require.Equal(t, store.ActiveKeyBytes, ks.Bytes)
+		require.Equal(t, status.ActiveDataKey.CreationTime, ks.CreationTime)
+		require.NotZero(t, ks.CreationTime)
+	}
+	require.True(t, found, "no key stats for active data key %s: %+v",
+		status.ActiveDataKey.KeyId, store.KeyStats)
+	require.Equal(t, store.TotalFiles, files)
+}
@@ -253,6 +253,10 @@ func (e *encryptionStatsHandler) GetKeyIDFromSettings(settings []byte) (string,
 	return s.KeyId, nil
 }
 
+func (e *encryptionStatsHandler) GetKeyCreationTime(keyID string) (int64, bool) {
+	return e.dataKM.getKeyCreationTime(keyID)
+}
+
 // Init initializes engine.NewEncryptedEncFunc.
 func init() {
 	storage.NewEncryptedEnvFunc = newEncryptedEnv
@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
@@ -23,8 +24,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/stretchr/testify/require"
@@ -276,7 +280,76 @@ func TestPebbleEncryption(t *testing.T) {
 	require.Equal(t, uint64(5), stats.TotalFiles)
 	require.Equal(t, uint64(5), stats.ActiveKeyFiles)
 	require.Equal(t, stats.TotalBytes, stats.ActiveKeyBytes)
+	require.Len(t, stats.KeyStats, 1)
+	require.Equal(t, s.ActiveDataKey.KeyId, stats.KeyStats[0].KeyID)
+	require.Equal(t, s.ActiveDataKey.CreationTime, stats.KeyStats[0].CreationTime)
+	require.Equal(t, stats.ActiveKeyFiles, stats.KeyStats[0].Files)
+	require.Equal(t, stats.ActiveKeyBytes, stats.KeyStats[0].Bytes)
 	t.Logf("EnvStats:\n%+v\n\n", *stats)
 
+	// Nothing needs rewriting while all files use the active key.
+	count, err := db.CompactEncryptedBefore(timeutil.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	db.Close()
+
+	// Rotating the store key rotates the data key, leaving the sstable under
+	// the previous data key.
+	writeToFile(t, memFS, "16b.key", []byte("222222222222222222222222222222221234567890123456"))
+	encOptions.KeyFiles = &baseccl.EncryptionKeyFiles{
+		CurrentKey: "16b.key",
+		OldKey:     "16.key",
+	}
+	encOptionsBytes, err = protoutil.Marshal(&encOptions)
+	require.NoError(t, err)
+	opts3 := storage.DefaultPebbleOptions()
+	opts3.Cache = pebble.NewCache(1 << 20)
+	defer opts3.Cache.Unref()
+
+	opts3.FS = memFS
+	db, err = storage.NewPebble(
+		context.Background(),
+		storage.PebbleConfig{
+			StorageConfig: base.StorageConfig{
+				Attrs:           roachpb.Attributes{},
+				MaxSize:         512 << 20,
+				UseFileRegistry: true,
+				ExtraOptions:    encOptionsBytes,
+			},
+			Opts: opts3,
+		})
+	require.NoError(t, err)
+	stats, err = db.GetEnvStats()
+	require.NoError(t, err)
+	require.Len(t, stats.KeyStats, 2)
+
+	count, err = db.CompactEncryptedBefore(timeutil.Unix(s.ActiveDataKey.CreationTime, 0))
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	count, err = db.CompactEncryptedBefore(timeutil.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	val, err = db.Get(storage.MVCCKey{Key: roachpb.Key("a")})
+	require.NoError(t, err)
+	require.Equal(t, "a", string(val))
+
+	// Once the compacted sstable is deleted, all files use the active key.
+	testutils.SucceedsSoon(t, func() error {
+		stats, err := db.GetEnvStats()
+		if err != nil {
+			return err
+		}
+		if len(stats.KeyStats) != 1 {
+			return errors.Errorf("expected files under a single key, found %+v", stats.KeyStats)
+		}
+		return nil
+	})
+	stats, err = db.GetEnvStats()
+	require.NoError(t, err)
+	require.NotEqual(t, s.ActiveDataKey.KeyId, stats.KeyStats[0].KeyID)
+	require.Equal(t, stats.TotalFiles, stats.ActiveKeyFiles)
+	require.Equal(t, stats.ActiveKeyFiles, stats.KeyStats[0].Files)
+	require.Equal(t, stats.ActiveKeyBytes, stats.KeyStats[0].Bytes)
+
 	db.Close()
 }
@@ -293,6 +293,20 @@ func (m *DataKeyManager) SetActiveStoreKeyInfo(
 	return nil
 }
 
+// getKeyCreationTime returns the creation time of the data or store key with
+// the given ID.
+func (m *DataKeyManager) getKeyCreationTime(id string) (int64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if key, found := m.mu.keyRegistry.DataKeys[id]; found {
+		return key.Info.CreationTime, true
+	}
+	if info, found := m.mu.keyRegistry.StoreKeys[id]; found {
+		return info.CreationTime, true
+	}
+	return 0, false
+}
+
 func (m *DataKeyManager) getScrubbedRegistry() *enginepbccl.DataKeysRegistry {
 	m.mu.Lock()
 	defer m.mu.Unlock()
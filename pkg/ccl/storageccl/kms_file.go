@@ -0,0 +1,91 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+// fileKMSScheme is the scheme of the URIs of file based KMSs, which take the
+// master key from a file in the external IO directory of the node, e.g.
+// file:///keys/backup.key. It is intended for on-premise deployments without
+// a key management service, and for tests.
+const fileKMSScheme = "file"
+
+// fileKMS is a KMS whose master key is the raw contents of a local file,
+// which must be a valid AES-128, AES-192 or AES-256 key.
+type fileKMS struct {
+	key []byte
+}
+
+var _ cloud.KMS = &fileKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(makeFileKMS, fileKMSScheme)
+}
+
+func makeFileKMS(uri string, settings *cluster.Settings) (cloud.KMS, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Host != "" {
+		return nil, errors.Errorf("file KMS URI %q must not specify a host", uri)
+	}
+	local, err := blobs.NewLocalStorage(settings.ExternalIODir)
+	if err != nil {
+		return nil, err
+	}
+	r, err := local.ReadFile(parsed.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading KMS master key")
+	}
+	defer r.Close()
+	key, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading KMS master key")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.Errorf("KMS master key must be 16, 24 or 32 bytes long, found %d", len(key))
+	}
+	return &fileKMS{key: key}, nil
+}
+
+// MasterKeyID implements the cloud.KMS interface. The ID is derived from the
+// key itself, so that it does not depend on where the key file is stored.
+func (k *fileKMS) MasterKeyID() (string, error) {
+	sum := sha256.Sum256(k.key)
+	return fileKMSScheme + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// Encrypt implements the cloud.KMS interface.
+func (k *fileKMS) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return EncryptFile(plaintext, k.key)
+}
+
+// Decrypt implements the cloud.KMS interface.
+func (k *fileKMS) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return DecryptFile(ciphertext, k.key)
+}
+
+// Close implements the cloud.KMS interface.
+func (k *fileKMS) Close() error {
+	return nil
+}
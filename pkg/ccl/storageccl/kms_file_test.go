@@ -0,0 +1,86 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	st := cluster.MakeTestingClusterSettings()
+	st.ExternalIODir = dir
+
+	writeKey := func(name string, key []byte) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), key, 0600))
+	}
+	writeKey("a.key", []byte("0123456789abcdef0123456789abcdef"))
+	writeKey("a-copy.key", []byte("0123456789abcdef0123456789abcdef"))
+	writeKey("b.key", []byte("fedcba9876543210"))
+	writeKey("bad.key", []byte("too short"))
+
+	a, err := cloud.KMSFromURI("file:///a.key", st)
+	require.NoError(t, err)
+	aCopy, err := cloud.KMSFromURI("file:///a-copy.key", st)
+	require.NoError(t, err)
+	b, err := cloud.KMSFromURI("file:///b.key", st)
+	require.NoError(t, err)
+
+	t.Run("master key ID", func(t *testing.T) {
+		aID, err := a.MasterKeyID()
+		require.NoError(t, err)
+		aCopyID, err := aCopy.MasterKeyID()
+		require.NoError(t, err)
+		bID, err := b.MasterKeyID()
+		require.NoError(t, err)
+		require.Equal(t, aID, aCopyID)
+		require.NotEqual(t, aID, bID)
+	})
+
+	t.Run("encrypt+decrypt", func(t *testing.T) {
+		plaintext := []byte("data key")
+		ciphertext, err := a.Encrypt(ctx, plaintext)
+		require.NoError(t, err)
+		require.NotEqual(t, plaintext, ciphertext)
+
+		decrypted, err := aCopy.Decrypt(ctx, ciphertext)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		_, err = b.Decrypt(ctx, ciphertext)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := cloud.KMSFromURI("file:///bad.key", st)
+		require.EqualError(t, err, "KMS master key must be 16, 24 or 32 bytes long, found 9")
+
+		_, err = cloud.KMSFromURI("file:///missing.key", st)
+		require.Error(t, err)
+
+		_, err = cloud.KMSFromURI("file:///../a.key", st)
+		require.Error(t, err)
+
+		_, err = cloud.KMSFromURI("unknown:///a.key", st)
+		require.EqualError(t, err, `unsupported KMS scheme: "unknown"`)
+	})
+}
@@ -1244,6 +1244,7 @@ var debugCmds = append(DebugCmdsForRocksDB,
 	debugSyncBenchCmd,
 	debugSyncTestCmd,
 	debugUnsafeRemoveDeadReplicasCmd,
+	debugRecoverCmd,
 	debugEnvCmd,
 	debugZipCmd,
 	debugMergeLogsCommand,
@@ -0,0 +1,323 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/spf13/cobra"
+)
+
+var debugRecoverCmd = &cobra.Command{
+	Use:   "recover [command]",
+	Short: "commands to recover unavailable ranges in case of quorum loss",
+	Long: `Set of commands to recover unavailable ranges.
+
+If a range lost quorum because a majority of its replicas are on nodes that
+are permanently lost, it can be made available again by rewriting its
+descriptor so that one of the surviving replicas becomes its only voter. The
+range is then up-replicated from that replica.
+
+This process is UNSAFE and should only be used with the supervision of a
+Cockroach Labs engineer. Writes which were committed on the lost replicas
+but not applied by the surviving one are lost, and the recovered data is not
+guaranteed to be consistent. The lost nodes must be decommissioned and must
+never rejoin the cluster.
+
+Unlike 'debug unsafe-remove-dead-replicas', the recovery is performed while
+the surviving nodes are running and proceeds in four steps:
+
+  cockroach debug recover collect-info > info.json
+  cockroach debug recover make-plan info.json > plan.json
+  cockroach debug recover apply-plan --confirm-decommissioned-nodes=<IDs> plan.json
+  cockroach debug recover verify plan.json
+
+collect-info retrieves the replicas of all nodes which can be reached.
+make-plan picks, for every range which lost quorum, the surviving replica which
+applied the most of its raft log, and reports the localities that were lost.
+apply-plan instructs the nodes holding the survivors to rewrite the range
+descriptors, refusing any range whose state changed since collection or whose
+lost nodes were not confirmed decommissioned.
+verify checks that all planned ranges have recovered.
+`,
+	RunE: usageAndErr,
+}
+
+var debugRecoverCollectInfoCmd = &cobra.Command{
+	Use:   "collect-info",
+	Short: "collect the replicas of all reachable nodes",
+	Long: `
+Collects the descriptors and raft applied indexes of the replicas on all nodes
+which can be reached and writes them to standard output as JSON. The nodes
+which could not be reached are reported on standard error; their replicas are
+considered lost when making a recovery plan.
+`,
+	Args: cobra.NoArgs,
+	RunE: MaybeDecorateGRPCError(runDebugRecoverCollectInfo),
+}
+
+func runDebugRecoverCollectInfo(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := collectRecoveryInfo(ctx)
+	if err != nil {
+		return err
+	}
+	return writeRecoveryProto(os.Stdout, &info)
+}
+
+var debugRecoverMakePlanCmd = &cobra.Command{
+	Use:   "make-plan <info file>",
+	Short: "compute a recovery plan from collected replica info",
+	Long: `
+Computes the updates needed to restore quorum to all ranges in the replica
+info produced by collect-info and writes them to standard output as JSON. A
+summary of the plan, including the localities whose loss made each range
+unavailable, is printed to standard error.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDebugRecoverMakePlan,
+}
+
+func runDebugRecoverMakePlan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var info loqrecoverypb.ClusterReplicaInfo
+	if err := readRecoveryProto(args[0], &info); err != nil {
+		return err
+	}
+	plan, report, err := loqrecovery.PlanReplicas(ctx, info)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stderr, "Nodes reporting replicas: %v\n", info.ReportingNodeIDs)
+	for _, r := range report.Ranges {
+		fmt.Fprintf(stderr, "r%d [%s, %s): keeping %s, removing %v",
+			r.RangeID, r.StartKey, r.EndKey, r.Survivor, r.Lost)
+		if len(r.LostLocalities) > 0 {
+			fmt.Fprintf(stderr, "; lost localities %v", r.LostLocalities)
+		}
+		fmt.Fprintf(stderr, "\n")
+	}
+	for _, desc := range report.Unrecoverable {
+		fmt.Fprintf(stderr, "WARNING: %s has no surviving voter and cannot be recovered\n", &desc)
+	}
+	for _, replica := range report.Stale {
+		fmt.Fprintf(stderr, "ignoring stale replica of %s on s%d\n", &replica.Desc, replica.StoreID)
+	}
+	for _, gap := range report.Gaps {
+		fmt.Fprintf(stderr, "WARNING: no replica found for span %s\n", gap)
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Fprintf(stderr, "No ranges need to be recovered\n")
+	}
+	return writeRecoveryProto(os.Stdout, &plan)
+}
+
+var debugRecoverApplyPlanCmd = &cobra.Command{
+	Use:   "apply-plan --confirm-decommissioned-nodes=[node ID,...] <plan file>",
+	Short: "apply a recovery plan to the cluster",
+	Long: `
+Applies the recovery plan produced by make-plan. Every update is sent to the
+node holding its surviving replica, which rewrites the range descriptor after
+checking that the replica is in the state it was when the plan was made and
+that the range cannot make progress without the decommissioned nodes.
+
+The --confirm-decommissioned-nodes flag takes a comma-separated list of the
+IDs of nodes which are decommissioned: they have been permanently shut down
+and will never rejoin the cluster. It must include all the nodes holding the
+lost replicas of the plan. A node which is merely unreachable must not be
+listed: if it came back, the surviving replicas could diverge from it.
+
+This command will prompt for confirmation before applying the plan.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverApplyPlan),
+}
+
+func runDebugRecoverApplyPlan(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var plan loqrecoverypb.ReplicaUpdatePlan
+	if err := readRecoveryProto(args[0], &plan); err != nil {
+		return err
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Printf("Nothing to do\n")
+		return nil
+	}
+	decommissioned := make(map[roachpb.NodeID]struct{})
+	for _, id := range recoverApplyPlanOpts.decommissionedNodeIDs {
+		decommissioned[roachpb.NodeID(id)] = struct{}{}
+		plan.DecommissionedNodeIDs = append(plan.DecommissionedNodeIDs, roachpb.NodeID(id))
+	}
+	var unconfirmed []roachpb.NodeID
+	for _, update := range plan.Updates {
+		fmt.Printf("r%d: keep %s, remove %v\n", update.RangeID, update.Survivor, update.LostReplicas)
+		for _, rDesc := range update.LostReplicas {
+			if _, ok := decommissioned[rDesc.NodeID]; !ok {
+				decommissioned[rDesc.NodeID] = struct{}{}
+				unconfirmed = append(unconfirmed, rDesc.NodeID)
+			}
+		}
+	}
+	if len(unconfirmed) > 0 {
+		return errors.Errorf("nodes %v hold lost replicas but are not listed in "+
+			"--confirm-decommissioned-nodes", unconfirmed)
+	}
+
+	fmt.Printf("Proceed with the above rewrites? [y/N] ")
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n")
+	if line[0] != 'y' && line[0] != 'Y' {
+		fmt.Printf("Aborting\n")
+		return nil
+	}
+
+	c, finish, err := getAdminClient(ctx, serverCfg)
+	if err != nil {
+		return err
+	}
+	defer finish()
+	resp, err := c.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{Plan: plan})
+	if err != nil {
+		return errors.Wrap(err, "failed to apply recovery plan")
+	}
+
+	failed := false
+	for _, nodeErr := range resp.Errors {
+		failed = true
+		fmt.Printf("n%d: %s\n", nodeErr.NodeID, nodeErr.Message)
+	}
+	for _, result := range resp.Results {
+		if result.Error != "" {
+			failed = true
+			fmt.Printf("r%d on s%d: %s\n", result.RangeID, result.StoreID, result.Error)
+			continue
+		}
+		fmt.Printf("r%d on s%d: recovered\n", result.RangeID, result.StoreID)
+	}
+	if failed {
+		return errors.New("recovery plan was not fully applied")
+	}
+	return nil
+}
+
+var debugRecoverVerifyCmd = &cobra.Command{
+	Use:   "verify <plan file>",
+	Short: "verify that a recovery plan was applied",
+	Long: `
+Collects the replicas of all reachable nodes again and checks that every range
+of the recovery plan produced by make-plan has recovered.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverVerify),
+}
+
+func runDebugRecoverVerify(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var plan loqrecoverypb.ReplicaUpdatePlan
+	if err := readRecoveryProto(args[0], &plan); err != nil {
+		return err
+	}
+	info, err := collectRecoveryInfo(ctx)
+	if err != nil {
+		return err
+	}
+	errs := loqrecovery.VerifyPlan(plan, info)
+	for _, err := range errs {
+		fmt.Printf("%s\n", err)
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("%d of %d ranges have not recovered", len(errs), len(plan.Updates))
+	}
+	fmt.Printf("All %d ranges have recovered\n", len(plan.Updates))
+	return nil
+}
+
+var debugRecoverCmds = []*cobra.Command{
+	debugRecoverCollectInfoCmd,
+	debugRecoverMakePlanCmd,
+	debugRecoverApplyPlanCmd,
+	debugRecoverVerifyCmd,
+}
+
+var recoverApplyPlanOpts struct {
+	decommissionedNodeIDs []int
+}
+
+func init() {
+	debugRecoverCmd.AddCommand(debugRecoverCmds...)
+
+	f := debugRecoverApplyPlanCmd.Flags()
+	f.IntSliceVar(&recoverApplyPlanOpts.decommissionedNodeIDs, "confirm-decommissioned-nodes", nil,
+		"list of the IDs of decommissioned nodes")
+}
+
+// collectRecoveryInfo retrieves the replicas of all reachable nodes through
+// the node the client is connected to. Unreachable nodes are reported on
+// standard error.
+func collectRecoveryInfo(ctx context.Context) (loqrecoverypb.ClusterReplicaInfo, error) {
+	c, finish, err := getAdminClient(ctx, serverCfg)
+	if err != nil {
+		return loqrecoverypb.ClusterReplicaInfo{}, err
+	}
+	defer finish()
+	resp, err := c.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{})
+	if err != nil {
+		return loqrecoverypb.ClusterReplicaInfo{}, errors.Wrap(err, "failed to collect replica info")
+	}
+	for _, nodeErr := range resp.Errors {
+		fmt.Fprintf(stderr, "n%d did not report: %s\n", nodeErr.NodeID, nodeErr.Message)
+	}
+	return resp.Info, nil
+}
+
+func writeRecoveryProto(w io.Writer, pb protoutil.Message) error {
+	m := jsonpb.Marshaler{Indent: "  "}
+	if err := m.Marshal(w, pb); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func readRecoveryProto(path string, pb protoutil.Message) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := jsonpb.Unmarshal(file, pb); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return nil
+}
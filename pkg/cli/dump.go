@@ -930,6 +930,16 @@ func dumpTableData(
 						if err != nil {
 							return err
 						}
+					case types.TSQueryFamily:
+						d, err = tree.ParseDTSQuery(string(t))
+						if err != nil {
+							return err
+						}
+					case types.TSVectorFamily:
+						d, err = tree.ParseDTSVector(string(t))
+						if err != nil {
+							return err
+						}
 					case types.ArrayFamily:
 						// We can only observe ARRAY types by their [] suffix.
 						d, err = tree.ParseDArrayFromString(
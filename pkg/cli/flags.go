@@ -488,6 +488,7 @@ func init() {
 
 	clientCmds := []*cobra.Command{
 		debugGossipValuesCmd,
+		debugRecoverCmd,
 		debugTimeSeriesDumpCmd,
 		debugZipCmd,
 		dumpCmd,
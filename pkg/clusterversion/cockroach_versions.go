@@ -67,6 +67,17 @@ const (
 	VersionAlterColumnTypeGeneral
 	VersionAlterSystemJobsAddCreatedByColumns
 	VersionAddScheduledJobsTable
+	VersionMaterializedViews
+	VersionUserDefinedFunctions
+	VersionListenNotify
+	VersionJWTAuthentication
+	VersionRowLevelTTL
+	VersionVirtualAndIdentityColumns
+	VersionTriggers
+	VersionLogicalReplication
+	VersionForeignDataWrappers
+	VersionTextSearch
+	VersionDeferrableConstraints
 
 	// Add new versions here (step one of two).
 )
@@ -511,6 +522,69 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionAddScheduledJobsTable,
 		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 7},
 	},
+	{
+		// VersionMaterializedViews enables the use of materialized views.
+		Key:     VersionMaterializedViews,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 8},
+	},
+	{
+		// VersionUserDefinedFunctions enables the use of function descriptors.
+		Key:     VersionUserDefinedFunctions,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 9},
+	},
+	{
+		// VersionListenNotify adds system.notifications and enables the use of
+		// LISTEN and NOTIFY.
+		Key:     VersionListenNotify,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 10},
+	},
+	{
+		// VersionJWTAuthentication enables the jwt HBA authentication method.
+		Key:     VersionJWTAuthentication,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 11},
+	},
+	{
+		// VersionRowLevelTTL enables row-level TTL storage parameters and jobs.
+		Key:     VersionRowLevelTTL,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 12},
+	},
+	{
+		// VersionVirtualAndIdentityColumns enables virtual computed columns and
+		// identity columns.
+		Key:     VersionVirtualAndIdentityColumns,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 13},
+	},
+	{
+		// VersionTriggers enables CREATE TRIGGER and the triggers field of table
+		// descriptors.
+		Key:     VersionTriggers,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 14},
+	},
+	{
+		// VersionLogicalReplication adds the system.publications and
+		// system.replication_slots tables and enables CREATE PUBLICATION and
+		// logical replication slots.
+		Key:     VersionLogicalReplication,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 15},
+	},
+	{
+		// VersionForeignDataWrappers adds the system.foreign_data_wrappers and
+		// system.foreign_servers tables and enables foreign tables.
+		Key:     VersionForeignDataWrappers,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 16},
+	},
+	{
+		// VersionTextSearch is the version where the TSQUERY and TSVECTOR types
+		// and inverted indexes on TSVECTOR columns were introduced.
+		Key:     VersionTextSearch,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 17},
+	},
+	{
+		// VersionDeferrableConstraints enables DEFERRABLE foreign key
+		// constraints.
+		Key:     VersionDeferrableConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 18},
+	},
 
 	// Add new versions here (step two of two).
 
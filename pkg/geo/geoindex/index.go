@@ -83,6 +83,11 @@ type GeographyIndex interface {
 	// ST_Intersects(g, x), where x are the indexed geometries.
 	Intersects(c context.Context, g *geo.Geography) (UnionKeySpans, error)
 
+	// DWithin returns the index spans to read and union for the relationship
+	// ST_DWithin(g, x, distanceMeters, false), where x are the indexed
+	// geometries. The distance is measured on the sphere.
+	DWithin(c context.Context, g *geo.Geography, distanceMeters float64) (UnionKeySpans, error)
+
 	// TestingInnerCovering returns an inner covering of g.
 	TestingInnerCovering(g *geo.Geography) s2.CellUnion
 }
@@ -113,6 +118,10 @@ type GeometryIndex interface {
 	// ST_Intersects(g, x), where x are the indexed geometries.
 	Intersects(c context.Context, g *geo.Geometry) (UnionKeySpans, error)
 
+	// DWithin returns the index spans to read and union for the relationship
+	// ST_DWithin(g, x, distance), where x are the indexed geometries.
+	DWithin(c context.Context, g *geo.Geometry, distance float64) (UnionKeySpans, error)
+
 	// TestingInnerCovering returns an inner covering of g.
 	TestingInnerCovering(g *geo.Geometry) s2.CellUnion
 }
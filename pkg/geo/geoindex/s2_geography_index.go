@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 )
 
@@ -85,6 +86,35 @@ func (i *s2GeographyIndex) Intersects(c context.Context, g *geo.Geography) (Unio
 	return intersects(c, i.rc, r), nil
 }
 
+// maxExpandLevelDiff bounds how much finer than the level corresponding to the
+// expansion radius the cells of an expanded covering can be. See
+// s2.CellUnion.ExpandByRadius.
+const maxExpandLevelDiff = 4
+
+// DWithin implements the GeographyIndex interface.
+func (i *s2GeographyIndex) DWithin(
+	c context.Context, g *geo.Geography, distanceMeters float64,
+) (UnionKeySpans, error) {
+	r, err := g.AsS2(geo.EmptyBehaviorOmit)
+	if err != nil {
+		return nil, err
+	}
+	spheroid, err := g.Spheroid()
+	if err != nil {
+		return nil, err
+	}
+	u := covering(i.rc, r)
+	if len(u) == 0 {
+		return nil, nil
+	}
+	// Distances on the sphere are proportional to the angle they subtend at the
+	// center of the sphere, so expanding the covering of g by that angle yields
+	// a region containing every point within distanceMeters of g. Any shape
+	// within that distance of g intersects the expanded region.
+	u.ExpandByRadius(s1.Angle(distanceMeters/spheroid.SphereRadius), maxExpandLevelDiff)
+	return intersects(c, i.rc, []s2.Region{&u}), nil
+}
+
 func (i *s2GeographyIndex) TestingInnerCovering(g *geo.Geography) s2.CellUnion {
 	r, _ := g.AsS2(geo.EmptyBehaviorOmit)
 	if r == nil {
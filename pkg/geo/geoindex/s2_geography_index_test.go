@@ -16,8 +16,10 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geogfn"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/datadriven"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO(sumeer): applies to this and the geometry test. The current test is
@@ -60,3 +62,40 @@ func TestS2GeographyIndexBasic(t *testing.T) {
 		}
 	})
 }
+
+func TestS2GeographyIndexDWithin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	index := NewS2GeographyIndex(*DefaultGeographyIndexConfig().S2Geography)
+	origin := geo.MustParseGeography("POINT(-73.9857 40.7484)")
+	for _, distance := range []float64{0, 10, 1000, 100000} {
+		spans, err := index.DWithin(ctx, origin, distance)
+		require.NoError(t, err)
+		// Every shape within the distance of the origin must have at least one
+		// index key in the spans.
+		for lng := -74.5; lng <= -73.5; lng += 0.01 {
+			for lat := 40.25; lat <= 41.25; lat += 0.01 {
+				g := geo.MustParseGeography(fmt.Sprintf("POINT(%f %f)", lng, lat))
+				d, err := geogfn.Distance(origin, g, geogfn.UseSphere)
+				require.NoError(t, err)
+				if d > distance {
+					continue
+				}
+				keys, err := index.InvertedIndexKeys(ctx, g)
+				require.NoError(t, err)
+				require.True(t, spansContainAnyKey(spans, keys),
+					"%s at distance %f not within spans for distance %f", g.EWKBHex(), d, distance)
+			}
+		}
+		// A shape on the other side of the world is not within the spans.
+		keys, err := index.InvertedIndexKeys(ctx, geo.MustParseGeography("POINT(100 -40)"))
+		require.NoError(t, err)
+		require.False(t, spansContainAnyKey(spans, keys))
+	}
+
+	// An empty shape produces no spans.
+	spans, err := index.DWithin(ctx, geo.MustParseGeography("POINT EMPTY"), 1000)
+	require.NoError(t, err)
+	require.Empty(t, spans)
+}
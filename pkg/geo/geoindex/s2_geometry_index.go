@@ -140,6 +140,33 @@ func (s *s2GeometryIndex) Intersects(c context.Context, g *geo.Geometry) (UnionK
 	return spans, nil
 }
 
+// DWithin implements the GeometryIndex interface.
+func (s *s2GeometryIndex) DWithin(
+	c context.Context, g *geo.Geometry, distance float64,
+) (UnionKeySpans, error) {
+	// Any shape within distance of g intersects the bounding box of g expanded
+	// by distance in every direction.
+	bbox := g.SpatialObject().BoundingBox
+	if bbox == nil {
+		// g is empty.
+		return nil, nil
+	}
+	minX, minY := bbox.MinX-distance, bbox.MinY-distance
+	maxX, maxY := bbox.MaxX+distance, bbox.MaxY+distance
+	expanded, err := geom.NewPolygon(geom.XY).SetCoords([][]geom.Coord{{
+		{minX, minY}, {minX, maxY}, {maxX, maxY}, {maxX, minY}, {minX, minY},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	expanded.SetSRID(int(g.SRID()))
+	expandedGeom, err := geo.NewGeometryFromGeom(expanded)
+	if err != nil {
+		return nil, err
+	}
+	return s.Intersects(c, expandedGeom)
+}
+
 // Converts to geom.T and clips to the rectangle bounds of the index.
 func (s *s2GeometryIndex) convertToGeomTAndTryClip(g *geo.Geometry) (geom.T, bool, error) {
 	gt, err := g.AsGeomT()
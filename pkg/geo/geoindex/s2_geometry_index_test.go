@@ -16,9 +16,11 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
 	"github.com/cockroachdb/cockroach/pkg/geo/geos"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/datadriven"
+	"github.com/stretchr/testify/require"
 )
 
 func TestS2GeometryIndexBasic(t *testing.T) {
@@ -67,6 +69,47 @@ func TestS2GeometryIndexBasic(t *testing.T) {
 	})
 }
 
+func TestS2GeometryIndexDWithin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	index := NewS2GeometryIndex(*DefaultGeometryIndexConfig().S2Geometry)
+	origin := geo.MustParseGeometry("LINESTRING(10 10, 20 15)")
+	for _, distance := range []float64{0, 1, 25, 1000} {
+		spans, err := index.DWithin(ctx, origin, distance)
+		require.NoError(t, err)
+		// Every shape within the distance of the origin must have at least one
+		// index key in the spans.
+		for x := -50.0; x <= 80; x += 2.5 {
+			for y := -50.0; y <= 80; y += 2.5 {
+				g := geo.MustParseGeometry(fmt.Sprintf("POINT(%f %f)", x, y))
+				d, err := geomfn.MinDistance(origin, g)
+				require.NoError(t, err)
+				if d > distance {
+					continue
+				}
+				keys, err := index.InvertedIndexKeys(ctx, g)
+				require.NoError(t, err)
+				require.True(t, spansContainAnyKey(spans, keys),
+					"%s at distance %f not within spans for distance %f", g.EWKBHex(), d, distance)
+			}
+		}
+	}
+
+	// Shapes exceeding the bounds of the index are found when the expanded
+	// origin exceeds the bounds.
+	spans, err := index.DWithin(ctx, origin, 20000)
+	require.NoError(t, err)
+	keys, err := index.InvertedIndexKeys(ctx, geo.MustParseGeometry("POINT(15000 15000)"))
+	require.NoError(t, err)
+	require.True(t, spansContainAnyKey(spans, keys))
+
+	// An empty shape produces no spans.
+	spans, err = index.DWithin(ctx, geo.MustParseGeometry("POINT EMPTY"), 1000)
+	require.NoError(t, err)
+	require.Empty(t, spans)
+}
+
 func TestClipEWKBByRect(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
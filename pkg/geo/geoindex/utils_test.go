@@ -58,6 +58,19 @@ func cellUnionToString(cells s2.CellUnion) string {
 	return strings.Join(strs, ", ")
 }
 
+// spansContainAnyKey returns whether any of the keys is contained in one of
+// the spans.
+func spansContainAnyKey(spans UnionKeySpans, keys []Key) bool {
+	for _, k := range keys {
+		for _, span := range spans {
+			if span.Start <= k && k <= span.End {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func spansToString(spans UnionKeySpans, err error) string {
 	if err != nil {
 		return err.Error()
@@ -0,0 +1,218 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/errors"
+)
+
+// NoCluster is the cluster ID assigned to geometries which do not belong to
+// any cluster.
+const NoCluster = -1
+
+// maxKMeansIterations bounds the number of iterations of ClusterKMeans, in
+// case the assignments of geometries to clusters fail to converge.
+const maxKMeansIterations = 1000
+
+// ClusterDBSCAN assigns each of the given geometries to a cluster using the
+// DBSCAN algorithm. A geometry is a core geometry of a cluster if at least
+// minPoints geometries, including itself, are within eps of it; geometries
+// within eps of a core geometry belong to the same cluster. Cluster IDs are
+// numbered from 0 in the order in which clusters are found.
+//
+// Geometries which are nil, empty, or are not within eps of any core geometry
+// are assigned NoCluster.
+func ClusterDBSCAN(geoms []*geo.Geometry, eps float64, minPoints int) ([]int, error) {
+	if eps < 0 {
+		return nil, errors.Newf("eps must not be negative")
+	}
+	if minPoints < 0 {
+		return nil, errors.Newf("minpoints must not be negative")
+	}
+	ids := make([]int, len(geoms))
+	for i := range ids {
+		ids[i] = NoCluster
+	}
+	// neighbors returns the indexes of the geometries within eps of geoms[i],
+	// including i itself.
+	neighbors := func(i int) ([]int, error) {
+		var ret []int
+		for j, g := range geoms {
+			if g == nil || g.Empty() {
+				continue
+			}
+			if j == i {
+				ret = append(ret, j)
+				continue
+			}
+			d, err := MinDistance(geoms[i], g)
+			if err != nil {
+				return nil, err
+			}
+			if d <= eps {
+				ret = append(ret, j)
+			}
+		}
+		return ret, nil
+	}
+
+	visited := make([]bool, len(geoms))
+	nextID := 0
+	for i, g := range geoms {
+		if visited[i] || g == nil || g.Empty() {
+			continue
+		}
+		visited[i] = true
+		queue, err := neighbors(i)
+		if err != nil {
+			return nil, err
+		}
+		if len(queue) < minPoints {
+			continue
+		}
+		id := nextID
+		nextID++
+		ids[i] = id
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if ids[j] == NoCluster {
+				ids[j] = id
+			}
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			jNeighbors, err := neighbors(j)
+			if err != nil {
+				return nil, err
+			}
+			if len(jNeighbors) >= minPoints {
+				queue = append(queue, jNeighbors...)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ClusterKMeans assigns each of the given geometries to one of k clusters
+// using the k-means algorithm on the centers of their bounding boxes. Initial
+// cluster centers are chosen deterministically, starting with the first
+// geometry and then repeatedly picking the geometry farthest from the centers
+// chosen so far. If there are fewer than k geometries, each is assigned its
+// own cluster.
+//
+// Geometries which are nil or empty are assigned NoCluster.
+func ClusterKMeans(geoms []*geo.Geometry, k int) ([]int, error) {
+	if k <= 0 {
+		return nil, errors.Newf("number of clusters must be greater than 0")
+	}
+	ids := make([]int, len(geoms))
+	var idxs []int
+	var xs, ys []float64
+	var srid int
+	for i, g := range geoms {
+		ids[i] = NoCluster
+		if g == nil || g.Empty() {
+			continue
+		}
+		if len(idxs) == 0 {
+			srid = int(g.SRID())
+		} else if int(g.SRID()) != srid {
+			return nil, geo.NewMismatchingSRIDsError(geoms[idxs[0]], g)
+		}
+		bbox := g.SpatialObject().BoundingBox
+		idxs = append(idxs, i)
+		xs = append(xs, (bbox.MinX+bbox.MaxX)/2)
+		ys = append(ys, (bbox.MinY+bbox.MaxY)/2)
+	}
+	if len(idxs) == 0 {
+		return ids, nil
+	}
+	if k > len(idxs) {
+		k = len(idxs)
+	}
+
+	sqDist := func(x1, y1, x2, y2 float64) float64 {
+		return (x1-x2)*(x1-x2) + (y1-y2)*(y1-y2)
+	}
+	centerXs := make([]float64, 0, k)
+	centerYs := make([]float64, 0, k)
+	centerXs, centerYs = append(centerXs, xs[0]), append(centerYs, ys[0])
+	// minSqDists holds the squared distance from each point to its closest
+	// center chosen so far.
+	minSqDists := make([]float64, len(xs))
+	for i := range xs {
+		minSqDists[i] = sqDist(xs[i], ys[i], xs[0], ys[0])
+	}
+	for len(centerXs) < k {
+		farthest := 0
+		for i := range minSqDists {
+			if minSqDists[i] > minSqDists[farthest] {
+				farthest = i
+			}
+		}
+		cx, cy := xs[farthest], ys[farthest]
+		centerXs, centerYs = append(centerXs, cx), append(centerYs, cy)
+		for i := range xs {
+			minSqDists[i] = math.Min(minSqDists[i], sqDist(xs[i], ys[i], cx, cy))
+		}
+	}
+
+	assignments := make([]int, len(xs))
+	for i := range assignments {
+		assignments[i] = NoCluster
+	}
+	sumXs := make([]float64, k)
+	sumYs := make([]float64, k)
+	counts := make([]int, k)
+	for iter := 0; iter < maxKMeansIterations; iter++ {
+		changed := false
+		for i := range xs {
+			closest := 0
+			closestSqDist := math.Inf(1)
+			for c := range centerXs {
+				if d := sqDist(xs[i], ys[i], centerXs[c], centerYs[c]); d < closestSqDist {
+					closest, closestSqDist = c, d
+				}
+			}
+			if assignments[i] != closest {
+				assignments[i] = closest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		for c := range counts {
+			sumXs[c], sumYs[c], counts[c] = 0, 0, 0
+		}
+		for i, c := range assignments {
+			sumXs[c] += xs[i]
+			sumYs[c] += ys[i]
+			counts[c]++
+		}
+		for c := range counts {
+			// A center with no geometries assigned to it stays where it is.
+			if counts[c] > 0 {
+				centerXs[c] = sumXs[c] / float64(counts[c])
+				centerYs[c] = sumYs[c] / float64(counts[c])
+			}
+		}
+	}
+	for i, idx := range idxs {
+		ids[idx] = assignments[i]
+	}
+	return ids, nil
+}
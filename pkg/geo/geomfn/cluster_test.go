@@ -0,0 +1,132 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterDBSCAN(t *testing.T) {
+	geoms := parseGeometries(t, []string{
+		"POINT(0 0)",
+		"POINT(0 1)",
+		"POINT(10 10)",
+		"POINT(0 2)",
+		"POINT(10 11)",
+		"POINT(50 50)",
+		"POINT EMPTY",
+	})
+	// A NULL geometry.
+	geoms = append(geoms, nil)
+
+	testCases := []struct {
+		desc      string
+		eps       float64
+		minPoints int
+		expected  []int
+	}{
+		{
+			desc:      "two clusters",
+			eps:       1,
+			minPoints: 2,
+			expected:  []int{0, 0, 1, 0, 1, NoCluster, NoCluster, NoCluster},
+		},
+		{
+			desc:      "border points join the cluster",
+			eps:       1,
+			minPoints: 3,
+			expected:  []int{0, 0, NoCluster, 0, NoCluster, NoCluster, NoCluster, NoCluster},
+		},
+		{
+			desc:      "every geometry is a core geometry",
+			eps:       0,
+			minPoints: 1,
+			expected:  []int{0, 1, 2, 3, 4, 5, NoCluster, NoCluster},
+		},
+		{
+			desc:      "one cluster",
+			eps:       100,
+			minPoints: 2,
+			expected:  []int{0, 0, 0, 0, 0, 0, NoCluster, NoCluster},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ids, err := ClusterDBSCAN(geoms, tc.eps, tc.minPoints)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ClusterDBSCAN(geoms, -1 /* eps */, 1 /* minPoints */)
+		require.EqualError(t, err, "eps must not be negative")
+		_, err = ClusterDBSCAN(geoms, 1 /* eps */, -1 /* minPoints */)
+		require.EqualError(t, err, "minpoints must not be negative")
+	})
+}
+
+func TestClusterKMeans(t *testing.T) {
+	geoms := parseGeometries(t, []string{
+		"POINT(0 0)",
+		"POINT(10 10)",
+		"POINT(1 0)",
+		"LINESTRING(9 9, 11 11)",
+		"POINT EMPTY",
+		"POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))",
+	})
+	// A NULL geometry.
+	geoms = append(geoms, nil)
+
+	testCases := []struct {
+		desc     string
+		k        int
+		expected []int
+	}{
+		{
+			desc:     "one cluster",
+			k:        1,
+			expected: []int{0, 0, 0, 0, NoCluster, 0, NoCluster},
+		},
+		{
+			desc:     "two clusters",
+			k:        2,
+			expected: []int{0, 1, 0, 1, NoCluster, 0, NoCluster},
+		},
+		{
+			desc:     "more clusters than geometries",
+			k:        10,
+			expected: []int{0, 1, 3, 1, NoCluster, 2, NoCluster},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ids, err := ClusterKMeans(geoms, tc.k)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ClusterKMeans(geoms, 0 /* k */)
+		require.EqualError(t, err, "number of clusters must be greater than 0")
+		_, err = ClusterKMeans([]*geo.Geometry{
+			geo.MustParseGeometry("POINT(1 1)"),
+			geo.MustParseGeometry("SRID=4326;POINT(1 1)"),
+		}, 1 /* k */)
+		require.Error(t, err)
+	})
+}
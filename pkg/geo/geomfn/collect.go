@@ -0,0 +1,200 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/cockroachdb/errors"
+	"github.com/twpayne/go-geom"
+)
+
+// Collect returns a Geometry collecting the given geometries. If all of them
+// are Points, LineStrings or Polygons, a MultiPoint, MultiLineString or
+// MultiPolygon is returned respectively; otherwise, a GeometryCollection is
+// returned. Empty geometries are omitted.
+func Collect(geoms []*geo.Geometry) (*geo.Geometry, error) {
+	if len(geoms) == 0 {
+		return nil, errors.Newf("cannot collect zero geometries")
+	}
+	srid := geoms[0].SRID()
+	ts := make([]geom.T, 0, len(geoms))
+	for _, g := range geoms {
+		if g.SRID() != srid {
+			return nil, geo.NewMismatchingSRIDsError(geoms[0], g)
+		}
+		if g.Empty() {
+			continue
+		}
+		t, err := g.AsGeomT()
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if len(ts) == 0 {
+		return geo.NewGeometryFromGeom(geom.NewGeometryCollection().SetSRID(int(srid)))
+	}
+
+	layout := ts[0].Layout()
+	var ret geom.T
+	switch ts[0].(type) {
+	case *geom.Point:
+		multiPoint := geom.NewMultiPoint(layout).SetSRID(int(srid))
+		for _, t := range ts {
+			p, ok := t.(*geom.Point)
+			if !ok {
+				return collectGeometryCollection(ts, srid)
+			}
+			if err := multiPoint.Push(p); err != nil {
+				return nil, err
+			}
+		}
+		ret = multiPoint
+	case *geom.LineString:
+		multiLineString := geom.NewMultiLineString(layout).SetSRID(int(srid))
+		for _, t := range ts {
+			ls, ok := t.(*geom.LineString)
+			if !ok {
+				return collectGeometryCollection(ts, srid)
+			}
+			if err := multiLineString.Push(ls); err != nil {
+				return nil, err
+			}
+		}
+		ret = multiLineString
+	case *geom.Polygon:
+		multiPolygon := geom.NewMultiPolygon(layout).SetSRID(int(srid))
+		for _, t := range ts {
+			p, ok := t.(*geom.Polygon)
+			if !ok {
+				return collectGeometryCollection(ts, srid)
+			}
+			if err := multiPolygon.Push(p); err != nil {
+				return nil, err
+			}
+		}
+		ret = multiPolygon
+	default:
+		return collectGeometryCollection(ts, srid)
+	}
+	return geo.NewGeometryFromGeom(ret)
+}
+
+// collectGeometryCollection returns a GeometryCollection made up of the given
+// geometries.
+func collectGeometryCollection(ts []geom.T, srid geopb.SRID) (*geo.Geometry, error) {
+	gc := geom.NewGeometryCollection().SetSRID(int(srid))
+	if err := gc.Push(ts...); err != nil {
+		return nil, err
+	}
+	return geo.NewGeometryFromGeom(gc)
+}
+
+// MakeLine returns a LineString made up of the points of the given
+// geometries, which must be Points, MultiPoints or LineStrings. If a
+// LineString starts where the line built so far ends, its first point is
+// skipped. Empty geometries are omitted.
+//
+// Returns nil if the geometries have fewer than two points between them.
+func MakeLine(geoms []*geo.Geometry) (*geo.Geometry, error) {
+	if len(geoms) == 0 {
+		return nil, nil
+	}
+	srid := geoms[0].SRID()
+	var layout geom.Layout
+	var flatCoords []float64
+	appendCoords := func(t geom.T, skipRepeatedFirst bool) error {
+		if layout == geom.NoLayout {
+			layout = t.Layout()
+		} else if t.Layout() != layout {
+			return errors.Newf("cannot make a line from geometries with mixed dimensions")
+		}
+		coords := t.FlatCoords()
+		if stride := layout.Stride(); skipRepeatedFirst && len(flatCoords) >= stride {
+			last := flatCoords[len(flatCoords)-stride:]
+			repeated := true
+			for i := range last {
+				if last[i] != coords[i] {
+					repeated = false
+					break
+				}
+			}
+			if repeated {
+				coords = coords[stride:]
+			}
+		}
+		flatCoords = append(flatCoords, coords...)
+		return nil
+	}
+	for _, g := range geoms {
+		if g.SRID() != srid {
+			return nil, geo.NewMismatchingSRIDsError(geoms[0], g)
+		}
+		if g.Empty() {
+			continue
+		}
+		t, err := g.AsGeomT()
+		if err != nil {
+			return nil, err
+		}
+		switch t := t.(type) {
+		case *geom.Point:
+			err = appendCoords(t, false /* skipRepeatedFirst */)
+		case *geom.MultiPoint:
+			for i := 0; i < t.NumPoints() && err == nil; i++ {
+				if p := t.Point(i); !p.Empty() {
+					err = appendCoords(p, false /* skipRepeatedFirst */)
+				}
+			}
+		case *geom.LineString:
+			err = appendCoords(t, true /* skipRepeatedFirst */)
+		default:
+			return nil, errors.Newf("%s geometry cannot be used to make a line", g.Shape())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if layout == geom.NoLayout || len(flatCoords) < 2*layout.Stride() {
+		return nil, nil
+	}
+	return geo.NewGeometryFromGeom(geom.NewLineStringFlat(layout, flatCoords).SetSRID(int(srid)))
+}
+
+// BoundingBoxGeometry returns the Geometry covering the given bounding box.
+// This is a Polygon, unless the bounding box has no width or height, in which
+// case a LineString or Point is returned instead.
+func BoundingBoxGeometry(bbox *geopb.BoundingBox, srid geopb.SRID) (*geo.Geometry, error) {
+	var t geom.T
+	switch {
+	case bbox.MinX == bbox.MaxX && bbox.MinY == bbox.MaxY:
+		t = geom.NewPointFlat(geom.XY, []float64{bbox.MinX, bbox.MinY}).SetSRID(int(srid))
+	case bbox.MinX == bbox.MaxX || bbox.MinY == bbox.MaxY:
+		t = geom.NewLineStringFlat(
+			geom.XY,
+			[]float64{bbox.MinX, bbox.MinY, bbox.MaxX, bbox.MaxY},
+		).SetSRID(int(srid))
+	default:
+		t = geom.NewPolygonFlat(
+			geom.XY,
+			[]float64{
+				bbox.MinX, bbox.MinY,
+				bbox.MinX, bbox.MaxY,
+				bbox.MaxX, bbox.MaxY,
+				bbox.MaxX, bbox.MinY,
+				bbox.MinX, bbox.MinY,
+			},
+			[]int{10},
+		).SetSRID(int(srid))
+	}
+	return geo.NewGeometryFromGeom(t)
+}
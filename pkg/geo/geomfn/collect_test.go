@@ -0,0 +1,156 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/stretchr/testify/require"
+)
+
+func parseGeometries(t *testing.T, wkts []string) []*geo.Geometry {
+	geoms := make([]*geo.Geometry, len(wkts))
+	for i, wkt := range wkts {
+		g, err := geo.ParseGeometry(wkt)
+		require.NoError(t, err)
+		geoms[i] = g
+	}
+	return geoms
+}
+
+func TestCollect(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		wkts     []string
+		expected string
+	}{
+		{
+			desc:     "points",
+			wkts:     []string{"POINT(1 1)", "POINT(2 2)"},
+			expected: "MULTIPOINT(1 1, 2 2)",
+		},
+		{
+			desc:     "linestrings with SRID",
+			wkts:     []string{"SRID=4326;LINESTRING(0 0, 1 1)", "SRID=4326;LINESTRING(2 2, 3 3)"},
+			expected: "SRID=4326;MULTILINESTRING((0 0, 1 1), (2 2, 3 3))",
+		},
+		{
+			desc:     "polygons",
+			wkts:     []string{"POLYGON((0 0, 1 0, 1 1, 0 0))", "POLYGON((2 2, 3 2, 3 3, 2 2))"},
+			expected: "MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)), ((2 2, 3 2, 3 3, 2 2)))",
+		},
+		{
+			desc:     "mixed types",
+			wkts:     []string{"POINT(1 1)", "LINESTRING(0 0, 1 1)"},
+			expected: "GEOMETRYCOLLECTION(POINT(1 1), LINESTRING(0 0, 1 1))",
+		},
+		{
+			desc:     "multi types",
+			wkts:     []string{"MULTIPOINT(1 1, 2 2)", "MULTIPOINT(3 3)"},
+			expected: "GEOMETRYCOLLECTION(MULTIPOINT(1 1, 2 2), MULTIPOINT(3 3))",
+		},
+		{
+			desc:     "empty geometries are omitted",
+			wkts:     []string{"POINT EMPTY", "POINT(1 1)", "LINESTRING EMPTY"},
+			expected: "MULTIPOINT(1 1)",
+		},
+		{
+			desc:     "only empty geometries",
+			wkts:     []string{"POINT EMPTY", "LINESTRING EMPTY"},
+			expected: "GEOMETRYCOLLECTION EMPTY",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := Collect(parseGeometries(t, tc.wkts))
+			require.NoError(t, err)
+			require.Equal(t, geo.MustParseGeometry(tc.expected), ret)
+		})
+	}
+
+	t.Run("errors on mismatching SRIDs", func(t *testing.T) {
+		_, err := Collect(parseGeometries(t, []string{"POINT(1 1)", "SRID=4326;POINT(2 2)"}))
+		require.Error(t, err)
+	})
+}
+
+func TestMakeLine(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		wkts     []string
+		expected string
+	}{
+		{
+			desc:     "points",
+			wkts:     []string{"POINT(1 1)", "POINT(2 2)", "POINT(2 2)", "POINT(3 1)"},
+			expected: "LINESTRING(1 1, 2 2, 2 2, 3 1)",
+		},
+		{
+			desc:     "multipoints and linestrings",
+			wkts:     []string{"MULTIPOINT(0 0, 1 1)", "LINESTRING(2 2, 3 3)"},
+			expected: "LINESTRING(0 0, 1 1, 2 2, 3 3)",
+		},
+		{
+			desc:     "linestring joined at the end of the line",
+			wkts:     []string{"SRID=4326;LINESTRING(0 0, 1 1)", "SRID=4326;LINESTRING(1 1, 2 0)"},
+			expected: "SRID=4326;LINESTRING(0 0, 1 1, 2 0)",
+		},
+		{
+			desc:     "empty geometries are omitted",
+			wkts:     []string{"POINT EMPTY", "POINT(1 1)", "LINESTRING EMPTY", "POINT(2 2)"},
+			expected: "LINESTRING(1 1, 2 2)",
+		},
+		{
+			desc:     "single point",
+			wkts:     []string{"POINT(1 1)"},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := MakeLine(parseGeometries(t, tc.wkts))
+			require.NoError(t, err)
+			if tc.expected == "" {
+				require.Nil(t, ret)
+				return
+			}
+			require.Equal(t, geo.MustParseGeometry(tc.expected), ret)
+		})
+	}
+
+	t.Run("errors on polygons", func(t *testing.T) {
+		_, err := MakeLine(parseGeometries(t, []string{"POINT(1 1)", "POLYGON((0 0, 1 0, 1 1, 0 0))"}))
+		require.Error(t, err)
+	})
+}
+
+func TestBoundingBoxGeometry(t *testing.T) {
+	testCases := []struct {
+		bbox     geopb.BoundingBox
+		expected string
+	}{
+		{geopb.BoundingBox{MinX: 1, MaxX: 3, MinY: 2, MaxY: 4}, "POLYGON((1 2, 1 4, 3 4, 3 2, 1 2))"},
+		{geopb.BoundingBox{MinX: 1, MaxX: 3, MinY: 2, MaxY: 2}, "LINESTRING(1 2, 3 2)"},
+		{geopb.BoundingBox{MinX: 1, MaxX: 1, MinY: 2, MaxY: 2}, "POINT(1 2)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			ret, err := BoundingBoxGeometry(&tc.bbox, 0 /* srid */)
+			require.NoError(t, err)
+			require.Equal(t, geo.MustParseGeometry(tc.expected), ret)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geos"
+	"github.com/cockroachdb/errors"
+	"github.com/twpayne/go-geom"
+)
+
+// AsMVTGeometry transforms a Geometry into the coordinate space of a Mapbox
+// Vector Tile, whose bounds are given by the bounding box of another
+// Geometry. The tile coordinate space ranges from 0 to extent on both axes,
+// with the Y axis pointing down.
+//
+// If clip is true, the geometry is first clipped to the bounds extended by
+// buffer units of tile coordinate space on each side. Coordinates are snapped
+// to the integer grid of the tile, and parts of the geometry which collapse as
+// a result are removed. Only the parts of a GeometryCollection with the
+// highest dimension are kept. The returned geometry has SRID 0.
+//
+// Returns nil if the geometry is empty, or if nothing remains of it after it
+// is clipped and snapped.
+func AsMVTGeometry(
+	g *geo.Geometry, bounds *geo.Geometry, extent int, buffer int, clip bool,
+) (*geo.Geometry, error) {
+	if extent <= 0 {
+		return nil, errors.Newf("extent must be greater than 0")
+	}
+	if buffer < 0 {
+		return nil, errors.Newf("buffer must not be negative")
+	}
+	bbox := bounds.SpatialObject().BoundingBox
+	if bbox == nil || bbox.MaxX <= bbox.MinX || bbox.MaxY <= bbox.MinY {
+		return nil, errors.Newf("bounds must have a positive width and height")
+	}
+	if g.Empty() {
+		return nil, nil
+	}
+
+	width := bbox.MaxX - bbox.MinX
+	height := bbox.MaxY - bbox.MinY
+	if clip {
+		bufferX := float64(buffer) * width / float64(extent)
+		bufferY := float64(buffer) * height / float64(extent)
+		clippedEWKB, err := geos.ClipEWKBByRect(
+			g.EWKB(), bbox.MinX-bufferX, bbox.MinY-bufferY, bbox.MaxX+bufferX, bbox.MaxY+bufferY,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if g, err = geo.ParseGeometryFromEWKB(clippedEWKB); err != nil {
+			return nil, err
+		}
+		if g.Empty() {
+			return nil, nil
+		}
+	}
+
+	t, err := g.AsGeomT()
+	if err != nil {
+		return nil, err
+	}
+	m := mvtTransformer{
+		minX:   bbox.MinX,
+		maxY:   bbox.MaxY,
+		scaleX: float64(extent) / width,
+		scaleY: float64(extent) / height,
+	}
+	if err := m.add(t); err != nil {
+		return nil, err
+	}
+	ret := m.result()
+	if ret == nil {
+		return nil, nil
+	}
+	return geo.NewGeometryFromGeom(ret)
+}
+
+// mvtTransformer accumulates the points, lines and polygons of a geometry
+// after transforming them into tile coordinate space.
+type mvtTransformer struct {
+	minX, maxY     float64
+	scaleX, scaleY float64
+
+	// points holds the flat XY coordinates of each point.
+	points []float64
+	// lines holds the flat XY coordinates of each line.
+	lines [][]float64
+	// polygons holds the flat XY coordinates of each ring of each polygon.
+	polygons [][][]float64
+}
+
+func (m *mvtTransformer) add(t geom.T) error {
+	switch t := t.(type) {
+	case *geom.Point:
+		if !t.Empty() {
+			m.points = append(m.points, m.snapCoords(t.FlatCoords(), t.Stride())...)
+		}
+	case *geom.MultiPoint:
+		for i := 0; i < t.NumPoints(); i++ {
+			if err := m.add(t.Point(i)); err != nil {
+				return err
+			}
+		}
+	case *geom.LineString:
+		m.addLineString(t)
+	case *geom.MultiLineString:
+		for i := 0; i < t.NumLineStrings(); i++ {
+			m.addLineString(t.LineString(i))
+		}
+	case *geom.Polygon:
+		m.addPolygon(t)
+	case *geom.MultiPolygon:
+		for i := 0; i < t.NumPolygons(); i++ {
+			m.addPolygon(t.Polygon(i))
+		}
+	case *geom.GeometryCollection:
+		for _, subG := range t.Geoms() {
+			if err := m.add(subG); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Newf("unknown geom type: %T", t)
+	}
+	return nil
+}
+
+func (m *mvtTransformer) addLineString(ls *geom.LineString) {
+	coords := m.snapCoords(ls.FlatCoords(), ls.Stride())
+	if len(coords) >= 4 {
+		m.lines = append(m.lines, coords)
+	}
+}
+
+// addPolygon adds the rings of the polygon which do not collapse when
+// snapped. If the exterior ring collapses, the whole polygon is dropped.
+func (m *mvtTransformer) addPolygon(p *geom.Polygon) {
+	var rings [][]float64
+	for i := 0; i < p.NumLinearRings(); i++ {
+		ring := p.LinearRing(i)
+		coords := m.snapCoords(ring.FlatCoords(), ring.Stride())
+		if len(coords) < 8 || mvtRingArea(coords) == 0 {
+			if i == 0 {
+				return
+			}
+			continue
+		}
+		rings = append(rings, coords)
+	}
+	if len(rings) > 0 {
+		m.polygons = append(m.polygons, rings)
+	}
+}
+
+// snapCoords transforms the given coordinates into tile coordinate space,
+// snapping them to the integer grid and dropping repeated points. Only the X
+// and Y coordinates are kept.
+func (m *mvtTransformer) snapCoords(flatCoords []float64, stride int) []float64 {
+	ret := make([]float64, 0, 2*len(flatCoords)/stride)
+	for i := 0; i < len(flatCoords); i += stride {
+		x := math.Round((flatCoords[i] - m.minX) * m.scaleX)
+		y := math.Round((m.maxY - flatCoords[i+1]) * m.scaleY)
+		if n := len(ret); n > 0 && ret[n-2] == x && ret[n-1] == y {
+			continue
+		}
+		ret = append(ret, x, y)
+	}
+	return ret
+}
+
+// result returns the geometry made up of the accumulated parts with the
+// highest dimension, or nil if there are none.
+func (m *mvtTransformer) result() geom.T {
+	switch {
+	case len(m.polygons) == 1:
+		flatCoords, ends := flattenRings(m.polygons[0])
+		return geom.NewPolygonFlat(geom.XY, flatCoords, ends)
+	case len(m.polygons) > 1:
+		var flatCoords []float64
+		endss := make([][]int, len(m.polygons))
+		for i, rings := range m.polygons {
+			var ends []int
+			for _, ring := range rings {
+				flatCoords = append(flatCoords, ring...)
+				ends = append(ends, len(flatCoords))
+			}
+			endss[i] = ends
+		}
+		return geom.NewMultiPolygonFlat(geom.XY, flatCoords, endss)
+	case len(m.lines) == 1:
+		return geom.NewLineStringFlat(geom.XY, m.lines[0])
+	case len(m.lines) > 1:
+		flatCoords, ends := flattenRings(m.lines)
+		return geom.NewMultiLineStringFlat(geom.XY, flatCoords, ends)
+	case len(m.points) == 2:
+		return geom.NewPointFlat(geom.XY, m.points)
+	case len(m.points) > 2:
+		return geom.NewMultiPointFlat(geom.XY, m.points)
+	default:
+		return nil
+	}
+}
+
+// flattenRings concatenates the given flat coordinates, returning the result
+// along with the offset at which each of them ends.
+func flattenRings(rings [][]float64) ([]float64, []int) {
+	var flatCoords []float64
+	ends := make([]int, len(rings))
+	for i, ring := range rings {
+		flatCoords = append(flatCoords, ring...)
+		ends[i] = len(flatCoords)
+	}
+	return flatCoords, ends
+}
+
+// mvtRingArea returns twice the signed area of the given closed ring of flat
+// XY coordinates.
+func mvtRingArea(flatCoords []float64) float64 {
+	var area float64
+	for i := 2; i < len(flatCoords); i += 2 {
+		area += flatCoords[i-2]*flatCoords[i+1] - flatCoords[i]*flatCoords[i-1]
+	}
+	return area
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsMVTGeometry(t *testing.T) {
+	// The bounds and extent are chosen so that one unit of the input is one
+	// unit of tile coordinate space, with the Y axis flipped.
+	bounds := geo.MustParseGeometry("LINESTRING(0 0, 100 100)")
+
+	testCases := []struct {
+		desc        string
+		wkt         string
+		buffer      int
+		clip        bool
+		expectedWKT string
+	}{
+		{
+			desc:        "point",
+			wkt:         "POINT(10 20)",
+			clip:        true,
+			expectedWKT: "POINT(10 80)",
+		},
+		{
+			desc:        "SRID is dropped",
+			wkt:         "SRID=3857;POINT(10 20)",
+			clip:        true,
+			expectedWKT: "POINT(10 80)",
+		},
+		{
+			desc:        "coordinates are snapped and deduplicated",
+			wkt:         "LINESTRING(0 0, 0.2 0.2, 10.4 9.6)",
+			expectedWKT: "LINESTRING(0 100, 10 90)",
+		},
+		{
+			desc:        "collapsed polygon",
+			wkt:         "POLYGON((1 1, 1.1 1, 1.1 1.1, 1 1))",
+			expectedWKT: "",
+		},
+		{
+			desc:        "collapsed interior ring",
+			wkt:         "POLYGON((0 0, 10 0, 10 10, 0 10, 0 0), (5 5, 5.1 5, 5.1 5.1, 5 5))",
+			expectedWKT: "POLYGON((0 100, 10 100, 10 90, 0 90, 0 100))",
+		},
+		{
+			desc:        "collapsed part of multilinestring",
+			wkt:         "MULTILINESTRING((0 0, 0.1 0.1), (0 0, 10 10), (20 20, 30 30))",
+			expectedWKT: "MULTILINESTRING((0 100, 10 90), (20 80, 30 70))",
+		},
+		{
+			desc:        "clipped to buffer",
+			wkt:         "LINESTRING(-50 50, 150 50)",
+			buffer:      10,
+			clip:        true,
+			expectedWKT: "LINESTRING(-10 50, 110 50)",
+		},
+		{
+			desc:        "not clipped",
+			wkt:         "LINESTRING(-50 50, 150 50)",
+			buffer:      10,
+			expectedWKT: "LINESTRING(-50 50, 150 50)",
+		},
+		{
+			desc:        "clipped away",
+			wkt:         "POINT(200 200)",
+			clip:        true,
+			expectedWKT: "",
+		},
+		{
+			desc:        "geometry collection keeps highest dimension",
+			wkt:         "GEOMETRYCOLLECTION(POINT(1 1), LINESTRING(0 0, 10 10))",
+			expectedWKT: "LINESTRING(0 100, 10 90)",
+		},
+		{
+			desc:        "empty",
+			wkt:         "POINT EMPTY",
+			clip:        true,
+			expectedWKT: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := geo.MustParseGeometry(tc.wkt)
+			ret, err := AsMVTGeometry(g, bounds, 100 /* extent */, tc.buffer, tc.clip)
+			require.NoError(t, err)
+			if tc.expectedWKT == "" {
+				require.Nil(t, ret)
+				return
+			}
+			require.Equal(t, geo.MustParseGeometry(tc.expectedWKT), ret)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		g := geo.MustParseGeometry("POINT(1 1)")
+		_, err := AsMVTGeometry(g, bounds, 0 /* extent */, 0 /* buffer */, true /* clip */)
+		require.EqualError(t, err, "extent must be greater than 0")
+		_, err = AsMVTGeometry(g, bounds, 100 /* extent */, -1 /* buffer */, true /* clip */)
+		require.EqualError(t, err, "buffer must not be negative")
+		_, err = AsMVTGeometry(g, geo.MustParseGeometry("POINT(1 1)"), 100 /* extent */, 0 /* buffer */, true /* clip */)
+		require.EqualError(t, err, "bounds must have a positive width and height")
+	})
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package geomvt encodes geometries as Mapbox Vector Tiles.
+package geomvt
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/twpayne/go-geom"
+)
+
+// Version is the version of the vector tile specification implemented by
+// this package.
+const Version = 2
+
+// DefaultExtent is the default width and height of a tile in tile coordinate
+// space.
+const DefaultExtent = 4096
+
+// DefaultBuffer is the default width of the margin around a tile, in tile
+// coordinate space, to which geometries are clipped.
+const DefaultBuffer = 256
+
+// Geometry command IDs, as defined by section 4.3 of the specification.
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+// Attribute is a key and value which describes a feature.
+type Attribute struct {
+	Key   string
+	Value Tile_Value
+}
+
+// LayerBuilder accumulates the features of a single layer of a vector tile.
+type LayerBuilder struct {
+	layer Tile_Layer
+	// keys and values map the keys and values of feature attributes to their
+	// indexes in the layer, so that each is only stored once.
+	keys   map[string]uint32
+	values map[string]uint32
+	// size is the encoded size of the layer, excluding its header.
+	size int
+}
+
+// NewLayerBuilder returns a LayerBuilder for a layer with the given name and
+// extent.
+func NewLayerBuilder(name string, extent uint32) *LayerBuilder {
+	return &LayerBuilder{
+		layer: Tile_Layer{
+			Version: Version,
+			Name:    name,
+			Extent:  extent,
+		},
+		keys:   make(map[string]uint32),
+		values: make(map[string]uint32),
+	}
+}
+
+// AddFeature adds a feature with the given geometry, ID and attributes to the
+// layer. The geometry must already be in the tile coordinate space, for
+// example as returned by geomfn.AsMVTGeometry. Empty geometries are skipped.
+func (b *LayerBuilder) AddFeature(g *geo.Geometry, id uint64, attrs []Attribute) error {
+	t, err := g.AsGeomT()
+	if err != nil {
+		return err
+	}
+	if t.Empty() {
+		return nil
+	}
+	var enc geometryEncoder
+	typ, err := enc.encode(t)
+	if err != nil {
+		return err
+	}
+	if len(enc.cmds) == 0 {
+		return nil
+	}
+	feature := Tile_Feature{
+		ID:       id,
+		Type:     typ,
+		Geometry: enc.cmds,
+	}
+	if len(attrs) > 0 {
+		feature.Tags = make([]uint32, 0, 2*len(attrs))
+		for i := range attrs {
+			feature.Tags = append(feature.Tags, b.keyIndex(attrs[i].Key), b.valueIndex(&attrs[i].Value))
+		}
+	}
+	b.layer.Features = append(b.layer.Features, feature)
+	b.size += feature.Size()
+	return nil
+}
+
+func (b *LayerBuilder) keyIndex(key string) uint32 {
+	idx, ok := b.keys[key]
+	if !ok {
+		idx = uint32(len(b.layer.Keys))
+		b.keys[key] = idx
+		b.layer.Keys = append(b.layer.Keys, key)
+		b.size += len(key)
+	}
+	return idx
+}
+
+func (b *LayerBuilder) valueIndex(value *Tile_Value) uint32 {
+	// The text representation of a value includes its kind, so values of
+	// different types that print the same way are not conflated.
+	key := value.String()
+	idx, ok := b.values[key]
+	if !ok {
+		idx = uint32(len(b.layer.Values))
+		b.values[key] = idx
+		b.layer.Values = append(b.layer.Values, *value)
+		b.size += value.Size()
+	}
+	return idx
+}
+
+// NumFeatures returns the number of features in the layer.
+func (b *LayerBuilder) NumFeatures() int {
+	return len(b.layer.Features)
+}
+
+// Size returns an estimate of the encoded size of the layer in bytes, which
+// is maintained as features are added so that it is cheap to compute.
+func (b *LayerBuilder) Size() int {
+	return b.size
+}
+
+// Marshal returns the encoding of a tile consisting of the layer.
+func (b *LayerBuilder) Marshal() ([]byte, error) {
+	tile := Tile{Layers: []Tile_Layer{b.layer}}
+	return protoutil.Marshal(&tile)
+}
+
+// geometryEncoder encodes geometries as a sequence of commands, as described
+// by section 4.3 of the specification. The parameters of each command are
+// relative to the cursor left behind by the previous command.
+type geometryEncoder struct {
+	cmds []uint32
+	x, y int32
+}
+
+func (e *geometryEncoder) encode(t geom.T) (Tile_GeomType, error) {
+	switch t := t.(type) {
+	case *geom.Point:
+		e.moveTo(t.FlatCoords())
+		return Tile_POINT, nil
+	case *geom.MultiPoint:
+		var coords []float64
+		for i := 0; i < t.NumPoints(); i++ {
+			if p := t.Point(i); !p.Empty() {
+				coords = append(coords, p.X(), p.Y())
+			}
+		}
+		if len(coords) > 0 {
+			e.cmds = append(e.cmds, command(cmdMoveTo, len(coords)/2))
+			for i := 0; i < len(coords); i += 2 {
+				e.param(coords[i], coords[i+1])
+			}
+		}
+		return Tile_POINT, nil
+	case *geom.LineString:
+		e.lineString(t.FlatCoords(), t.Stride())
+		return Tile_LINESTRING, nil
+	case *geom.MultiLineString:
+		for i := 0; i < t.NumLineStrings(); i++ {
+			ls := t.LineString(i)
+			e.lineString(ls.FlatCoords(), ls.Stride())
+		}
+		return Tile_LINESTRING, nil
+	case *geom.Polygon:
+		e.polygon(t)
+		return Tile_POLYGON, nil
+	case *geom.MultiPolygon:
+		for i := 0; i < t.NumPolygons(); i++ {
+			e.polygon(t.Polygon(i))
+		}
+		return Tile_POLYGON, nil
+	default:
+		return Tile_UNKNOWN, errors.Newf("geometry type %T cannot be encoded in a vector tile", t)
+	}
+}
+
+func (e *geometryEncoder) moveTo(coords []float64) {
+	e.cmds = append(e.cmds, command(cmdMoveTo, 1))
+	e.param(coords[0], coords[1])
+}
+
+func (e *geometryEncoder) lineString(flatCoords []float64, stride int) {
+	numCoords := len(flatCoords) / stride
+	if numCoords < 2 {
+		return
+	}
+	e.moveTo(flatCoords)
+	e.cmds = append(e.cmds, command(cmdLineTo, numCoords-1))
+	for i := 1; i < numCoords; i++ {
+		e.param(flatCoords[i*stride], flatCoords[i*stride+1])
+	}
+}
+
+// polygon encodes each ring of the polygon, omitting the closing point, which
+// is implied by the ClosePath command. The specification requires the
+// exterior ring to have a positive area in tile coordinates, and interior
+// rings to have a negative one, so rings are reversed if necessary.
+func (e *geometryEncoder) polygon(p *geom.Polygon) {
+	for i := 0; i < p.NumLinearRings(); i++ {
+		ring := p.LinearRing(i)
+		stride := ring.Stride()
+		flatCoords := ring.FlatCoords()
+		numCoords := len(flatCoords)/stride - 1
+		if numCoords < 3 {
+			continue
+		}
+		area := signedArea(flatCoords, stride)
+		reverse := (i == 0) != (area > 0)
+		coord := func(j int) (float64, float64) {
+			if reverse {
+				j = numCoords - 1 - j
+			}
+			return flatCoords[j*stride], flatCoords[j*stride+1]
+		}
+		e.cmds = append(e.cmds, command(cmdMoveTo, 1))
+		e.param(coord(0))
+		e.cmds = append(e.cmds, command(cmdLineTo, numCoords-1))
+		for j := 1; j < numCoords; j++ {
+			e.param(coord(j))
+		}
+		e.cmds = append(e.cmds, command(cmdClosePath, 1))
+	}
+}
+
+// param appends the zigzag encoded offset of the given point from the cursor,
+// and moves the cursor to the point.
+func (e *geometryEncoder) param(x, y float64) {
+	ix, iy := int32(math.Round(x)), int32(math.Round(y))
+	e.cmds = append(e.cmds, zigzag(ix-e.x), zigzag(iy-e.y))
+	e.x, e.y = ix, iy
+}
+
+func command(id uint32, count int) uint32 {
+	return (id & 0x7) | (uint32(count) << 3)
+}
+
+func zigzag(v int32) uint32 {
+	return uint32((v << 1) ^ (v >> 31))
+}
+
+// signedArea returns twice the signed area of the given closed ring, as
+// computed by the surveyor's formula.
+func signedArea(flatCoords []float64, stride int) float64 {
+	var area float64
+	for i := stride; i < len(flatCoords); i += stride {
+		area += flatCoords[i-stride]*flatCoords[i+1] - flatCoords[i]*flatCoords[i-stride+1]
+	}
+	return area
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomvt
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFeatureGeometry(t *testing.T) {
+	// The expected encodings are the examples from section 4.3.5 of the
+	// specification.
+	testCases := []struct {
+		wkt      string
+		typ      Tile_GeomType
+		expected []uint32
+	}{
+		{"POINT(25 17)", Tile_POINT, []uint32{9, 50, 34}},
+		{"MULTIPOINT(5 7, 3 2)", Tile_POINT, []uint32{17, 10, 14, 3, 9}},
+		{"LINESTRING(2 2, 2 10, 10 10)", Tile_LINESTRING, []uint32{9, 4, 4, 18, 0, 16, 16, 0}},
+		{
+			"MULTILINESTRING((2 2, 2 10, 10 10), (1 1, 3 5))",
+			Tile_LINESTRING,
+			[]uint32{9, 4, 4, 18, 0, 16, 16, 0, 9, 17, 17, 10, 4, 8},
+		},
+		{"POLYGON((3 6, 8 12, 20 34, 3 6))", Tile_POLYGON, []uint32{9, 6, 12, 18, 10, 12, 24, 44, 15}},
+		// The exterior ring has the wrong orientation, so it is reversed.
+		{"POLYGON((3 6, 20 34, 8 12, 3 6))", Tile_POLYGON, []uint32{9, 16, 24, 18, 24, 44, 33, 55, 15}},
+		{
+			"MULTIPOLYGON(((0 0, 10 0, 10 10, 0 10, 0 0)), ((11 11, 20 11, 20 20, 11 20, 11 11), (13 13, 13 17, 17 17, 17 13, 13 13)))",
+			Tile_POLYGON,
+			[]uint32{
+				9, 0, 0, 26, 20, 0, 0, 20, 19, 0, 15,
+				9, 22, 2, 26, 18, 0, 0, 18, 17, 0, 15,
+				9, 4, 13, 26, 0, 8, 8, 0, 0, 7, 15,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.wkt, func(t *testing.T) {
+			b := NewLayerBuilder("test", DefaultExtent)
+			require.NoError(t, b.AddFeature(geo.MustParseGeometry(tc.wkt), 0, nil))
+			require.Equal(t, 1, b.NumFeatures())
+			require.Equal(t, tc.typ, b.layer.Features[0].Type)
+			require.Equal(t, tc.expected, b.layer.Features[0].Geometry)
+		})
+	}
+
+	t.Run("empty geometries are skipped", func(t *testing.T) {
+		b := NewLayerBuilder("test", DefaultExtent)
+		require.NoError(t, b.AddFeature(geo.MustParseGeometry("POINT EMPTY"), 0, nil))
+		require.NoError(t, b.AddFeature(geo.MustParseGeometry("LINESTRING EMPTY"), 0, nil))
+		require.Equal(t, 0, b.NumFeatures())
+	})
+
+	t.Run("geometry collections are not supported", func(t *testing.T) {
+		b := NewLayerBuilder("test", DefaultExtent)
+		err := b.AddFeature(geo.MustParseGeometry("GEOMETRYCOLLECTION(POINT(1 1))"), 0, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestLayerBuilderMarshal(t *testing.T) {
+	b := NewLayerBuilder("roads", 256)
+	str := func(s string) Tile_Value {
+		return Tile_Value{Kind: &Tile_Value_StringValue{StringValue: s}}
+	}
+	uintVal := func(v uint64) Tile_Value {
+		return Tile_Value{Kind: &Tile_Value_UintValue{UintValue: v}}
+	}
+	require.NoError(t, b.AddFeature(geo.MustParseGeometry("POINT(1 1)"), 1, []Attribute{
+		{Key: "name", Value: str("main")},
+		{Key: "lanes", Value: uintVal(2)},
+	}))
+	require.NoError(t, b.AddFeature(geo.MustParseGeometry("POINT(2 2)"), 2, []Attribute{
+		{Key: "name", Value: str("2")},
+		{Key: "lanes", Value: uintVal(2)},
+	}))
+
+	encoded, err := b.Marshal()
+	require.NoError(t, err)
+	var tile Tile
+	require.NoError(t, protoutil.Unmarshal(encoded, &tile))
+	require.Len(t, tile.Layers, 1)
+
+	layer := tile.Layers[0]
+	require.Equal(t, uint32(Version), layer.Version)
+	require.Equal(t, "roads", layer.Name)
+	require.Equal(t, uint32(256), layer.Extent)
+	require.Equal(t, []string{"name", "lanes"}, layer.Keys)
+	// The string "2" and the integer 2 are distinct values, but the integer 2
+	// is only stored once.
+	require.Equal(t, []Tile_Value{str("main"), uintVal(2), str("2")}, layer.Values)
+	require.Len(t, layer.Features, 2)
+	require.Equal(t, uint64(1), layer.Features[0].ID)
+	require.Equal(t, []uint32{0, 0, 1, 1}, layer.Features[0].Tags)
+	require.Equal(t, uint64(2), layer.Features[1].ID)
+	require.Equal(t, []uint32{0, 2, 1, 1}, layer.Features[1].Tags)
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geoprojbase
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+)
+
+var (
+	// wktAuthorityRe matches the authority of the outermost element of a WKT
+	// projection, which is its last element.
+	wktAuthorityRe = regexp.MustCompile(`AUTHORITY\s*\[\s*"EPSG"\s*,\s*"?(\d+)"?\s*\]\s*\]\s*$`)
+	// wktNameRe matches the name of the outermost element of a WKT projection.
+	wktNameRe = regexp.MustCompile(`^\s*(?:PROJCS|GEOGCS)\s*\[\s*"([^"]*)"`)
+)
+
+// esriProjectionNames maps the normalized names of projections as written by
+// ESRI tools, which differ from the EPSG names, to their SRIDs.
+var esriProjectionNames = map[string]geopb.SRID{
+	"wgs84webmercatorauxiliarysphere": 3857,
+	"wgs84webmercator":                3857,
+}
+
+var projectionNames struct {
+	once sync.Once
+	// m maps the normalized names of the projections to their SRIDs.
+	m map[string]geopb.SRID
+}
+
+// normalizeProjectionName normalizes the name of a projection, so that names
+// written by different tools can be compared. For example, "GCS_WGS_1984" and
+// "WGS 84" both normalize to "wgs84".
+func normalizeProjectionName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	ret := b.String()
+	ret = strings.TrimPrefix(ret, "gcs")
+	return strings.Replace(ret, "wgs1984", "wgs84", 1)
+}
+
+// projectionsByName returns the map of normalized projection names to SRIDs.
+// If several projections have the same name, the one with the lowest SRID is
+// used.
+func projectionsByName() map[string]geopb.SRID {
+	projectionNames.once.Do(func() {
+		srids := make([]geopb.SRID, 0, len(Projections))
+		for srid := range Projections {
+			srids = append(srids, srid)
+		}
+		sort.Slice(srids, func(i, j int) bool { return srids[i] < srids[j] })
+		projectionNames.m = make(map[string]geopb.SRID, len(srids))
+		for _, srid := range srids {
+			m := wktNameRe.FindStringSubmatch(Projections[srid].SRText)
+			if m == nil {
+				continue
+			}
+			name := normalizeProjectionName(m[1])
+			if _, ok := projectionNames.m[name]; !ok {
+				projectionNames.m[name] = srid
+			}
+		}
+	})
+	return projectionNames.m
+}
+
+// SRIDFromWKT returns the SRID of the projection described by the given WKT,
+// such as the content of the .prj file of a shapefile. The EPSG authority of
+// the projection is used if present; otherwise, the projection is looked up
+// by name. Returns false if no known projection matches.
+func SRIDFromWKT(wkt string) (geopb.SRID, bool) {
+	if m := wktAuthorityRe.FindStringSubmatch(wkt); m != nil {
+		if srid, err := strconv.Atoi(m[1]); err == nil {
+			if _, ok := Projection(geopb.SRID(srid)); ok {
+				return geopb.SRID(srid), true
+			}
+		}
+	}
+	m := wktNameRe.FindStringSubmatch(wkt)
+	if m == nil {
+		return 0, false
+	}
+	name := normalizeProjectionName(m[1])
+	if srid, ok := esriProjectionNames[name]; ok {
+		return srid, true
+	}
+	srid, ok := projectionsByName()[name]
+	return srid, ok
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geoprojbase
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSRIDFromWKT(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		wkt      string
+		expected geopb.SRID
+		ok       bool
+	}{
+		{
+			desc:     "spatial reference text of a projection",
+			wkt:      Projections[4326].SRText,
+			expected: 4326,
+			ok:       true,
+		},
+		{
+			desc:     "EPSG authority",
+			wkt:      `PROJCS["some name",GEOGCS["WGS 84",AUTHORITY["EPSG","4326"]],AUTHORITY["EPSG","32610"]]`,
+			expected: 32610,
+			ok:       true,
+		},
+		{
+			desc:     "ESRI geographic coordinate system",
+			wkt:      `GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`,
+			expected: 4326,
+			ok:       true,
+		},
+		{
+			desc:     "ESRI UTM zone",
+			wkt:      `PROJCS["WGS_1984_UTM_Zone_10N",GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]]],PROJECTION["Transverse_Mercator"]]`,
+			expected: 32610,
+			ok:       true,
+		},
+		{
+			desc:     "ESRI web mercator",
+			wkt:      `PROJCS["WGS_1984_Web_Mercator_Auxiliary_Sphere",GEOGCS["GCS_WGS_1984"],PROJECTION["Mercator_Auxiliary_Sphere"]]`,
+			expected: 3857,
+			ok:       true,
+		},
+		{
+			desc: "unknown projection",
+			wkt:  `PROJCS["Some_Local_Grid",GEOGCS["GCS_Unknown"]]`,
+			ok:   false,
+		},
+		{
+			desc: "not WKT",
+			wkt:  `not a projection`,
+			ok:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			srid, ok := SRIDFromWKT(tc.wkt)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, srid)
+		})
+	}
+}
@@ -29,9 +29,9 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-// jobSchedulerEnv is an environment for running scheduled jobs.
+// JobSchedulerEnv is an environment for running scheduled jobs.
 // This environment facilitates dependency injection mechanism for tests.
-type jobSchedulerEnv interface {
+type JobSchedulerEnv interface {
 	// ScheduledJobsTableName returns the name of the scheduled_jobs table.
 	ScheduledJobsTableName() string
 	// SystemJobsTableName returns the name of the system jobs table.
@@ -43,13 +43,16 @@ type jobSchedulerEnv interface {
 	NowExpr() string
 }
 
-// production jobSchedulerEnv implementation.
+// production JobSchedulerEnv implementation.
 type prodJobSchedulerEnvImpl struct{}
 
-// ProdJobSchedulerEnv is a jobSchedulerEnv implementation suitable for production.
-var ProdJobSchedulerEnv jobSchedulerEnv = &prodJobSchedulerEnvImpl{}
+// ProdJobSchedulerEnv is a JobSchedulerEnv implementation suitable for production.
+var ProdJobSchedulerEnv JobSchedulerEnv = &prodJobSchedulerEnvImpl{}
 
-const createdByName = "crdb_schedule"
+// CreatedByScheduledJobs is the value of the created_by_type column of
+// system.jobs rows for jobs started by a schedule; created_by_id holds the
+// schedule ID.
+const CreatedByScheduledJobs = "crdb_schedule"
 
 func (e *prodJobSchedulerEnvImpl) ScheduledJobsTableName() string {
 	return "system.scheduled_jobs"
@@ -70,11 +73,11 @@ func (e *prodJobSchedulerEnvImpl) NowExpr() string {
 // jobScheduler is responsible for finding and starting scheduled
 // jobs that need to be executed.
 type jobScheduler struct {
-	env jobSchedulerEnv
+	env JobSchedulerEnv
 	ex  sqlutil.InternalExecutor
 }
 
-func newJobScheduler(env jobSchedulerEnv, ex sqlutil.InternalExecutor) *jobScheduler {
+func newJobScheduler(env JobSchedulerEnv, ex sqlutil.InternalExecutor) *jobScheduler {
 	if env == nil {
 		env = ProdJobSchedulerEnv
 	}
@@ -88,7 +91,7 @@ const allSchedules = 0
 
 // getFindSchedulesStatement returns SQL statement used for finding
 // scheduled jobs that should be started.
-func getFindSchedulesStatement(env jobSchedulerEnv, maxSchedules int64) string {
+func getFindSchedulesStatement(env JobSchedulerEnv, maxSchedules int64) string {
 	limitClause := ""
 	if maxSchedules > 0 {
 		limitClause = fmt.Sprintf("LIMIT %d", maxSchedules)
@@ -107,7 +110,7 @@ FROM %s S
 WHERE next_run < %s
 ORDER BY next_run
 %s
-`, env.SystemJobsTableName(), createdByName, env.ScheduledJobsTableName(), env.NowExpr(), limitClause)
+`, env.SystemJobsTableName(), CreatedByScheduledJobs, env.ScheduledJobsTableName(), env.NowExpr(), limitClause)
 }
 
 // unmarshalScheduledJob is a helper to deserialize a row returned by
@@ -265,7 +268,7 @@ func StartJobSchedulerDaemon(
 	ctx context.Context,
 	stopper *stop.Stopper,
 	sv *settings.Values,
-	env jobSchedulerEnv,
+	env JobSchedulerEnv,
 	db *kv.DB,
 	ex sqlutil.InternalExecutor,
 ) {
@@ -44,7 +44,7 @@ func addFakeJob(t *testing.T, h *testHelper, id int64, status Status, txn *kv.Tx
 		fmt.Sprintf(
 			"INSERT INTO %s (created_by_type, created_by_id, status, payload) VALUES ($1, $2, $3, $4)",
 			h.env.SystemJobsTableName()),
-		createdByName, id, status, payload,
+		CreatedByScheduledJobs, id, status, payload,
 	)
 	require.NoError(t, err)
 	require.Equal(t, 1, n)
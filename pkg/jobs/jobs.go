@@ -529,6 +529,7 @@ func (j *Job) canceled(ctx context.Context, fn func(context.Context, *kv.Txn) er
 		ju.UpdateStatus(StatusCanceled)
 		md.Payload.FinishedMicros = timeutil.ToUnixMicros(j.registry.clock.Now().GoTime())
 		ju.UpdatePayload(md.Payload)
+		j.maybeNotifyScheduledJobTermination(ctx, txn, md, StatusCanceled)
 		return nil
 	})
 }
@@ -552,6 +553,7 @@ func (j *Job) failed(
 		md.Payload.Error = err.Error()
 		md.Payload.FinishedMicros = timeutil.ToUnixMicros(j.registry.clock.Now().GoTime())
 		ju.UpdatePayload(md.Payload)
+		j.maybeNotifyScheduledJobTermination(ctx, txn, md, StatusFailed)
 		return nil
 	})
 }
@@ -578,6 +580,7 @@ func (j *Job) succeeded(ctx context.Context, fn func(context.Context, *kv.Txn) e
 			FractionCompleted: 1.0,
 		}
 		ju.UpdateProgress(md.Progress)
+		j.maybeNotifyScheduledJobTermination(ctx, txn, md, StatusSucceeded)
 		return nil
 	})
 }
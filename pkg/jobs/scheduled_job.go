@@ -48,7 +48,7 @@ type scheduledJobRecord struct {
 // ScheduledJob  is a representation of the scheduled job.
 // This struct can marshal/unmarshal changes made to the underlying system.scheduled_job table.
 type ScheduledJob struct {
-	env jobSchedulerEnv
+	env JobSchedulerEnv
 
 	// The "record" for this schedule job.  Do not access this field
 	// directly (except in tests); Use Get/Set methods on ScheduledJob instead.
@@ -59,7 +59,7 @@ type ScheduledJob struct {
 }
 
 // NewScheduledJob creates and initializes ScheduledJob.
-func NewScheduledJob(env jobSchedulerEnv) *ScheduledJob {
+func NewScheduledJob(env JobSchedulerEnv) *ScheduledJob {
 	return &ScheduledJob{
 		env:   env,
 		dirty: make(map[string]struct{}),
@@ -71,12 +71,33 @@ func (j *ScheduledJob) ScheduleID() int64 {
 	return j.rec.ScheduleID
 }
 
+// ScheduleName returns the name of this schedule.
+func (j *ScheduledJob) ScheduleName() string {
+	return j.rec.ScheduleName
+}
+
 // SetScheduleName updates schedule name.
 func (j *ScheduledJob) SetScheduleName(name string) {
 	j.rec.ScheduleName = name
 	j.markDirty("schedule_name")
 }
 
+// Owner returns the user who owns this schedule.
+func (j *ScheduledJob) Owner() string {
+	return j.rec.Owner
+}
+
+// SetOwner updates the owner of this schedule.
+func (j *ScheduledJob) SetOwner(owner string) {
+	j.rec.Owner = owner
+	j.markDirty("owner")
+}
+
+// Env returns the environment this schedule runs in.
+func (j *ScheduledJob) Env() JobSchedulerEnv {
+	return j.env
+}
+
 // NextRun returns the next time this schedule supposed to execute.
 // A sentinel value of time.Time{} indicates this schedule is paused.
 func (j *ScheduledJob) NextRun() time.Time {
@@ -106,6 +127,12 @@ func (j *ScheduledJob) SetSchedule(scheduleExpr string) error {
 	return j.ScheduleNextRun()
 }
 
+// ScheduleExpr returns the cron expression describing the periodicity of
+// this schedule, or an empty string for one-off schedules.
+func (j *ScheduledJob) ScheduleExpr() string {
+	return j.rec.ScheduleExpr
+}
+
 // HasRecurringSchedule returns true if this schedule job runs periodically.
 func (j *ScheduledJob) HasRecurringSchedule() bool {
 	return len(j.rec.ScheduleExpr) > 0
@@ -178,6 +205,36 @@ func (j *ScheduledJob) SetExecutionDetails(executor string, args jobspb.Executio
 	j.markDirty("executor_type", "execution_args")
 }
 
+// LoadScheduledJob loads the schedule with the specified ID.
+func LoadScheduledJob(
+	ctx context.Context,
+	env JobSchedulerEnv,
+	scheduleID int64,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) (*ScheduledJob, error) {
+	rows, cols, err := ex.QueryWithCols(ctx, "lookup-schedule", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		fmt.Sprintf("SELECT * FROM %s WHERE schedule_id = %d",
+			env.ScheduledJobsTableName(), scheduleID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 1 {
+		return nil, errors.Newf(
+			"expected to find 1 schedule, found %d with schedule_id=%d",
+			len(rows), scheduleID)
+	}
+
+	j := NewScheduledJob(env)
+	if err := j.InitFromDatums(rows[0], cols); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
 // InitFromDatums initializes this ScheduledJob object based on datums and column names.
 func (j *ScheduledJob) InitFromDatums(datums []tree.Datum, cols []sqlbase.ResultColumn) error {
 	if len(datums) != len(cols) {
@@ -306,6 +363,30 @@ func (j *ScheduledJob) Update(ctx context.Context, ex sqlutil.InternalExecutor,
 	return nil
 }
 
+// Delete removes this schedule.
+// If an error is returned, it is callers responsibility to handle it (e.g. rollback transaction).
+func (j *ScheduledJob) Delete(ctx context.Context, ex sqlutil.InternalExecutor, txn *kv.Txn) error {
+	if j.rec.ScheduleID == 0 {
+		return errors.New("cannot delete schedule: missing schedule id")
+	}
+
+	n, err := ex.ExecEx(ctx, "sched-delete", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		fmt.Sprintf("DELETE FROM %s WHERE schedule_id = %d",
+			j.env.ScheduledJobsTableName(), j.ScheduleID()),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if n != 1 {
+		return fmt.Errorf("expected to delete 1 schedule, deleted %d instead", n)
+	}
+
+	return nil
+}
+
 // marshalChanges marshals all changes in the in-memory representation and returns
 // the names of the columns and marshaled values.
 // If no error is returned, the job is not considered to be modified anymore.
@@ -14,11 +14,15 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
 
@@ -82,7 +86,7 @@ func DefaultHandleFailedRun(schedule *ScheduledJob, jobID int64, err error) {
 // with the job status changes.
 func NotifyJobTermination(
 	ctx context.Context,
-	env jobSchedulerEnv,
+	env JobSchedulerEnv,
 	md *JobMetadata,
 	scheduleID int64,
 	ex sqlutil.InternalExecutor,
@@ -113,31 +117,71 @@ func NotifyJobTermination(
 	return schedule.Update(ctx, ex, txn)
 }
 
-func lookupScheduleAndExecutor(
+// MarkJobCreatedBySchedule records that the job with the specified ID was
+// started by the specified schedule. Jobs marked this way count as running
+// jobs of the schedule, and the schedule's executor is notified when they
+// terminate.
+func MarkJobCreatedBySchedule(
 	ctx context.Context,
-	env jobSchedulerEnv,
+	env JobSchedulerEnv,
+	jobID int64,
 	scheduleID int64,
 	ex sqlutil.InternalExecutor,
 	txn *kv.Txn,
-) (*ScheduledJob, ScheduledJobExecutor, error) {
-	rows, cols, err := ex.QueryWithCols(ctx, "lookup-schedule", txn,
+) error {
+	n, err := ex.ExecEx(ctx, "mark-created-by-schedule", txn,
 		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
 		fmt.Sprintf(
-			"SELECT schedule_id, schedule_details, executor_type FROM %s WHERE schedule_id = %d",
-			env.ScheduledJobsTableName(), scheduleID))
-
+			"UPDATE %s SET created_by_type = $1, created_by_id = $2 WHERE id = $3",
+			env.SystemJobsTableName()),
+		CreatedByScheduledJobs, scheduleID, jobID,
+	)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
+	if n != 1 {
+		return errors.Newf("expected to update 1 job, updated %d instead", n)
+	}
+	return nil
+}
 
-	if len(rows) != 1 {
-		return nil, nil, errors.Newf(
-			"expected to find 1 schedule, found %d with schedule_id=%d",
-			len(rows), scheduleID)
+// maybeNotifyScheduledJobTermination notifies the schedule that started this
+// job, if any, that the job transitioned to the specified terminal status.
+// It is called from within the transaction that updates the job's status.
+func (j *Job) maybeNotifyScheduledJobTermination(
+	ctx context.Context, txn *kv.Txn, md JobMetadata, status Status,
+) {
+	settings := j.registry.settings
+	if settings == cluster.NoSettings ||
+		!settings.Version.IsActive(ctx, clusterversion.VersionAddScheduledJobsTable) {
+		return
 	}
 
-	j := NewScheduledJob(env)
-	if err := j.InitFromDatums(rows[0], cols); err != nil {
+	row, err := j.registry.ex.QueryRowEx(ctx, "lookup-created-by", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		"SELECT created_by_id FROM system.jobs WHERE id = $1 AND created_by_type = $2",
+		md.ID, CreatedByScheduledJobs)
+	if err == nil && row != nil && row[0] != tree.DNull {
+		md.Status = status
+		scheduleID := int64(tree.MustBeDInt(row[0]))
+		err = NotifyJobTermination(ctx, nil /* env */, &md, scheduleID, j.registry.ex, txn)
+	}
+	if err != nil {
+		// The schedule may have been dropped in the meantime. Failing to
+		// notify it must not prevent the job from reaching its terminal state.
+		log.Warningf(ctx, "failed to notify schedule of termination of job %d: %v", md.ID, err)
+	}
+}
+
+func lookupScheduleAndExecutor(
+	ctx context.Context,
+	env JobSchedulerEnv,
+	scheduleID int64,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) (*ScheduledJob, ScheduledJobExecutor, error) {
+	j, err := LoadScheduledJob(ctx, env, scheduleID, ex, txn)
+	if err != nil {
 		return nil, nil, err
 	}
 	executor, err := NewScheduledJobExecutor(j.ExecutorType(), ex)
@@ -12,6 +12,7 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -97,3 +98,23 @@ func TestJobTerminationNotification(t *testing.T) {
 	// Verify counts.
 	require.Equal(t, map[Status]int{StatusSucceeded: 1, StatusFailed: 1, StatusCanceled: 1}, ex.counts)
 }
+
+func TestMarkJobCreatedBySchedule(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	h, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	h.sqlDB.Exec(t, fmt.Sprintf(
+		"INSERT INTO %s (id, status, payload) VALUES (123, 'running', 'fake payload')",
+		h.env.SystemJobsTableName()))
+
+	require.NoError(t, MarkJobCreatedBySchedule(ctx, h.env, 123, 321, h.ex, nil))
+	h.sqlDB.CheckQueryResults(t,
+		fmt.Sprintf("SELECT created_by_type, created_by_id FROM %s WHERE id = 123",
+			h.env.SystemJobsTableName()),
+		[][]string{{CreatedByScheduledJobs, "321"}})
+
+	// Marking a job which does not exist is an error.
+	require.Error(t, MarkJobCreatedBySchedule(ctx, h.env, 456, 321, h.ex, nil))
+}
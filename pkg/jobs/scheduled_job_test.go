@@ -116,3 +116,30 @@ func TestPauseUnpauseJob(t *testing.T) {
 	require.Equal(t, "just because", loaded.rec.ScheduleChanges.Changes[0].Reason)
 	require.Equal(t, "we are back", loaded.rec.ScheduleChanges.Changes[1].Reason)
 }
+
+func TestLoadAndDeleteScheduledJob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	h, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	j := h.newScheduledJob(t, "test_job", "test sql")
+	j.SetOwner("testuser")
+	require.NoError(t, j.SetSchedule("@daily"))
+	require.NoError(t, j.Create(ctx, h.ex, nil))
+
+	loaded, err := LoadScheduledJob(ctx, h.env, j.ScheduleID(), h.ex, nil)
+	require.NoError(t, err)
+	require.Equal(t, j.ScheduleID(), loaded.ScheduleID())
+	require.Equal(t, "test_job", loaded.ScheduleName())
+	require.Equal(t, "testuser", loaded.Owner())
+	require.Equal(t, "@daily", loaded.ScheduleExpr())
+	require.Equal(t, InlineExecutorName, loaded.ExecutorType())
+
+	require.NoError(t, loaded.Delete(ctx, h.ex, nil))
+	_, err = LoadScheduledJob(ctx, h.env, j.ScheduleID(), h.ex, nil)
+	require.Error(t, err)
+
+	// Deleting a schedule twice is an error.
+	require.Error(t, loaded.Delete(ctx, h.ex, nil))
+}
@@ -379,6 +379,11 @@ const (
 	StatementDiagnosticsTableID         = 36
 	ScheduledJobsTableID                = 37
 	TenantsRangesID                     = 38 // pseudo
+	NotificationsTableID                = 39
+	PublicationsTableID                 = 40
+	ReplicationSlotsTableID             = 41
+	ForeignDataWrappersTableID          = 42
+	ForeignServersTableID               = 43
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
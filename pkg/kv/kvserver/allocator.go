@@ -213,9 +213,11 @@ func makeAllocatorRand(source rand.Source) allocatorRand {
 // RangeUsageInfo contains usage information (sizes and traffic) needed by the
 // allocator to make rebalancing decisions for a given range.
 type RangeUsageInfo struct {
-	LogicalBytes     int64
-	QueriesPerSecond float64
-	WritesPerSecond  float64
+	LogicalBytes        int64
+	QueriesPerSecond    float64
+	WritesPerSecond     float64
+	WriteBytesPerSecond float64
+	CPUPerSecond        float64
 }
 
 func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
@@ -228,6 +230,12 @@ func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
 	if writesPerSecond, dur := repl.writeStats.avgQPS(); dur >= MinStatsDuration {
 		info.WritesPerSecond = writesPerSecond
 	}
+	if writeBytesPerSecond, dur := repl.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+		info.WriteBytesPerSecond = writeBytesPerSecond
+	}
+	if cpuPerSecond, dur := repl.requestCPUStats.avgQPS(); dur >= MinStatsDuration {
+		info.CPUPerSecond = cpuPerSecond
+	}
 	return info
 }
 
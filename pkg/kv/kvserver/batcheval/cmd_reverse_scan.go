@@ -47,6 +47,10 @@ func ReverseScan(
 		FailOnMoreRecent: args.KeyLocking != lock.None,
 		Reverse:          true,
 	}
+	if h.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		opts.SkipLocked = true
+		opts.LockTable = makeLockTableView(cArgs.EvalCtx, h.Txn)
+	}
 
 	switch args.ScanFormat {
 	case roachpb.BATCH_RESPONSE:
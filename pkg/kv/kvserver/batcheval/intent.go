@@ -14,9 +14,11 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -129,3 +131,27 @@ func acquireUnreplicatedLocksOnKeys(
 		panic("unexpected scanFormat")
 	}
 }
+
+// lockTableView is a storage.LockTableView that consults a Range's
+// concurrency manager on behalf of a transaction.
+type lockTableView struct {
+	lm  concurrency.LockManager
+	txn *enginepb.TxnMeta
+}
+
+var _ storage.LockTableView = lockTableView{}
+
+// makeLockTableView returns a storage.LockTableView bound to the provided
+// transaction, which may be nil for non-transactional requests.
+func makeLockTableView(rec EvalContext, txn *roachpb.Transaction) lockTableView {
+	v := lockTableView{lm: rec.GetConcurrencyManager()}
+	if txn != nil {
+		v.txn = &txn.TxnMeta
+	}
+	return v
+}
+
+// IsKeyLockedByConflictingTxn implements the storage.LockTableView interface.
+func (v lockTableView) IsKeyLockedByConflictingTxn(key roachpb.Key) bool {
+	return v.lm.IsKeyLockedByConflictingTxn(key, v.txn)
+}
@@ -15,6 +15,7 @@ package concurrency
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -238,6 +239,13 @@ type LockManager interface {
 	// updated or released a lock or range of locks that it previously held.
 	// The Durability field of the lock update struct is ignored.
 	OnLockUpdated(context.Context, *roachpb.LockUpdate)
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a transaction other than the one provided, as of the time of the
+	// call. A nil TxnMeta is treated as a non-transactional request, which
+	// conflicts with all locks. The method is used by requests that use a
+	// SkipLocked wait policy to skip over locked keys during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key, *enginepb.TxnMeta) bool
 }
 
 // TransactionManager is concerned with tracking transactions that have their
@@ -289,6 +297,10 @@ type MetricExporter interface {
 	// lockTable.
 	LockTableDebug() string
 
+	// QueryLockTableState returns information about the locks in the
+	// lockTable and the requests waiting on them.
+	QueryLockTableState() []kvserverpb.LockStateInfo
+
 	// TxnWaitQueue returns the concurrency manager's txnWaitQueue.
 	// TODO(nvanbenschoten): this doesn't really fit into this interface. It
 	// would be nice if the txnWaitQueue was hidden behind the concurrency
@@ -321,6 +333,10 @@ type Request struct {
 	// The priority of the request. Only set if Txn is nil.
 	Priority roachpb.UserPriority
 
+	// The policy the request uses when it encounters conflicting locks held by
+	// other active transactions.
+	WaitPolicy lock.WaitPolicy
+
 	// The consistency level of the request. Only set if Txn is nil.
 	ReadConsistency roachpb.ReadConsistencyType
 
@@ -553,6 +569,17 @@ type lockTable interface {
 	//     txn.WriteTimestamp.
 	UpdateLocks(*roachpb.LockUpdate) error
 
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a transaction other than the one provided. Locks held by the
+	// provided transaction, including those held at earlier epochs, are not
+	// considered conflicting. The method does not enqueue the caller in any
+	// lock wait-queue.
+	IsKeyLockedByConflictingTxn(roachpb.Key, *enginepb.TxnMeta) bool
+
+	// QueryLockTableState returns information about each lock in the lockTable
+	// that is held or has waiters, with durations computed relative to now.
+	QueryLockTableState(now time.Time) []kvserverpb.LockStateInfo
+
 	// String returns a debug string representing the state of the lockTable.
 	String() string
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
@@ -55,8 +56,9 @@ type Config struct {
 	Stopper        *stop.Stopper
 	IntentResolver IntentResolver
 	// Metrics.
-	TxnWaitMetrics *txnwait.Metrics
-	SlowLatchGauge *metric.Gauge
+	TxnWaitMetrics   *txnwait.Metrics
+	SlowLatchGauge   *metric.Gauge
+	ContentionEvents *ContentionEventRegistry
 	// Configs + Knobs.
 	MaxLockTableSize  int64
 	DisableTxnPushing bool
@@ -91,6 +93,7 @@ func NewManager(cfg Config) Manager {
 			stopper:           cfg.Stopper,
 			ir:                cfg.IntentResolver,
 			lm:                m,
+			contentionEvents:  cfg.ContentionEvents,
 			disableTxnPushing: cfg.DisableTxnPushing,
 		},
 		// TODO(nvanbenschoten): move pkg/storage/txnwait to a new
@@ -319,6 +322,11 @@ func (m *managerImpl) OnLockUpdated(ctx context.Context, up *roachpb.LockUpdate)
 	}
 }
 
+// IsKeyLockedByConflictingTxn implements the LockManager interface.
+func (m *managerImpl) IsKeyLockedByConflictingTxn(key roachpb.Key, txn *enginepb.TxnMeta) bool {
+	return m.lt.IsKeyLockedByConflictingTxn(key, txn)
+}
+
 // OnTransactionUpdated implements the TransactionManager interface.
 func (m *managerImpl) OnTransactionUpdated(ctx context.Context, txn *roachpb.Transaction) {
 	m.twq.UpdateTxn(ctx, txn)
@@ -399,6 +407,11 @@ func (m *managerImpl) LockTableDebug() string {
 	return m.lt.String()
 }
 
+// QueryLockTableState implements the MetricExporter interface.
+func (m *managerImpl) QueryLockTableState() []kvserverpb.LockStateInfo {
+	return m.lt.QueryLockTableState(timeutil.Now())
+}
+
 // TxnWaitQueue implements the MetricExporter interface.
 func (m *managerImpl) TxnWaitQueue() *txnwait.Queue {
 	return m.twq.(*txnwait.Queue)
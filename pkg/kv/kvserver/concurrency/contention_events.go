@@ -0,0 +1,130 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package concurrency
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// ContentionEventRegistry aggregates the lock contention encountered by
+// requests waiting in the lock wait-queues of a store's ranges. Events are
+// aggregated per SQL table and index, as decoded from the contended key.
+// Contention on keys outside of the system tenant's table keyspace is not
+// recorded.
+//
+// A single ContentionEventRegistry is shared between all of the concurrency
+// Managers on a store, so it is safe for concurrent use.
+type ContentionEventRegistry struct {
+	mu struct {
+		syncutil.Mutex
+		events map[contentionIndexKey]*kvserverpb.IndexContentionEvents
+	}
+}
+
+type contentionIndexKey struct {
+	tableID uint32
+	indexID uint32
+}
+
+// NewContentionEventRegistry creates a new ContentionEventRegistry.
+func NewContentionEventRegistry() *ContentionEventRegistry {
+	r := &ContentionEventRegistry{}
+	r.mu.events = make(map[contentionIndexKey]*kvserverpb.IndexContentionEvents)
+	return r
+}
+
+// record adds a contention event on the provided key that lasted for the
+// provided duration. The method is a no-op on a nil registry.
+func (r *ContentionEventRegistry) record(key roachpb.Key, dur time.Duration) {
+	if r == nil {
+		return
+	}
+	rem, tenID, err := keys.DecodeTenantPrefix(key)
+	if err != nil || tenID != roachpb.SystemTenantID {
+		return
+	}
+	_, tableID, indexID, err := keys.SystemSQLCodec.DecodeIndexPrefix(rem)
+	if err != nil {
+		return
+	}
+	k := contentionIndexKey{tableID: tableID, indexID: indexID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.mu.events[k]
+	if !ok {
+		e = &kvserverpb.IndexContentionEvents{TableID: tableID, IndexID: indexID}
+		r.mu.events[k] = e
+	}
+	e.NumContentionEvents++
+	e.CumulativeContentionTime += dur
+}
+
+// Events returns the aggregated contention events recorded by the registry,
+// sorted by table and index.
+func (r *ContentionEventRegistry) Events() []kvserverpb.IndexContentionEvents {
+	r.mu.Lock()
+	events := make([]kvserverpb.IndexContentionEvents, 0, len(r.mu.events))
+	for _, e := range r.mu.events {
+		events = append(events, *e)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].TableID != events[j].TableID {
+			return events[i].TableID < events[j].TableID
+		}
+		return events[i].IndexID < events[j].IndexID
+	})
+	return events
+}
+
+// contentionEventTracker tracks the lock that a single request is waiting on
+// in the lockTableWaiter and records a contention event with the registry
+// each time the request stops waiting on that lock.
+type contentionEventTracker struct {
+	registry *ContentionEventRegistry
+	key      roachpb.Key
+	start    time.Time
+}
+
+// notify informs the tracker of the request's new waiting state.
+func (t *contentionEventTracker) notify(state waitingState) {
+	switch state.kind {
+	case waitFor, waitForDistinguished, waitSelf, waitElsewhere:
+		if t.key != nil && t.key.Equal(state.key) {
+			// Still waiting on the same lock.
+			return
+		}
+		t.done()
+		t.key = state.key
+		t.start = timeutil.Now()
+	default:
+		t.done()
+	}
+}
+
+// done records the event for the lock that the request was waiting on, if
+// any.
+func (t *contentionEventTracker) done() {
+	if t.key == nil {
+		return
+	}
+	t.registry.record(t.key, timeutil.Since(t.start))
+	t.key = nil
+}
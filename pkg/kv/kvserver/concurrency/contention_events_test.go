@@ -0,0 +1,66 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package concurrency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentionEventRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tenantCodec := keys.MakeSQLCodec(roachpb.MakeTenantID(10))
+	r := NewContentionEventRegistry()
+	r.record(keys.SystemSQLCodec.IndexPrefix(53, 2), 2*time.Second)
+	r.record(keys.SystemSQLCodec.IndexPrefix(52, 1), time.Second)
+	r.record(append(keys.SystemSQLCodec.IndexPrefix(53, 2), "foo"...), 3*time.Second)
+	// Keys outside of the system tenant's table keyspace are ignored.
+	r.record(roachpb.Key("a"), time.Second)
+	r.record(tenantCodec.IndexPrefix(53, 2), time.Second)
+
+	require.Equal(t, []kvserverpb.IndexContentionEvents{
+		{TableID: 52, IndexID: 1, NumContentionEvents: 1, CumulativeContentionTime: time.Second},
+		{TableID: 53, IndexID: 2, NumContentionEvents: 2, CumulativeContentionTime: 5 * time.Second},
+	}, r.Events())
+
+	// Recording on a nil registry is a no-op.
+	var nilRegistry *ContentionEventRegistry
+	nilRegistry.record(keys.SystemSQLCodec.IndexPrefix(53, 2), time.Second)
+}
+
+func TestContentionEventTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	keyA := keys.SystemSQLCodec.IndexPrefix(53, 1)
+	keyB := keys.SystemSQLCodec.IndexPrefix(53, 2)
+	r := NewContentionEventRegistry()
+	tr := contentionEventTracker{registry: r}
+	tr.notify(waitingState{kind: waitFor, key: keyA})
+	// Remaining on the same key does not record a new event.
+	tr.notify(waitingState{kind: waitForDistinguished, key: keyA})
+	tr.notify(waitingState{kind: waitFor, key: keyB})
+	tr.notify(waitingState{kind: doneWaiting})
+	tr.done()
+
+	events := r.Events()
+	require.Len(t, events, 2)
+	require.Equal(t, uint32(1), events[0].IndexID)
+	require.Equal(t, int64(1), events[0].NumContentionEvents)
+	require.Equal(t, uint32(2), events[1].IndexID)
+	require.Equal(t, int64(1), events[1].NumContentionEvents)
+}
@@ -17,14 +17,17 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
@@ -302,6 +305,10 @@ type lockTableGuardImpl struct {
 		state  waitingState
 		signal chan struct{}
 
+		// The time at which the request started actively waiting at the lock
+		// it is currently waiting at. Only used for observability.
+		curLockWaitStart time.Time
+
 		// locks for which this request has a reservation or is in the queue of
 		// writers (active or inactive) or actively waiting as a reader.
 		//
@@ -368,6 +375,24 @@ func releaseLockTableGuardImpl(g *lockTableGuardImpl) {
 	lockTableGuardImplPool.Put(g)
 }
 
+// lockWaiter returns information about the request as a waiter in the
+// wait-queue of a lock. Acquires g.mu.
+func (g *lockTableGuardImpl) lockWaiter(
+	active bool, str lock.Strength, now time.Time,
+) kvserverpb.LockWaiter {
+	w := kvserverpb.LockWaiter{ActiveWaiter: active, Strength: str}
+	if g.txn != nil {
+		txnCopy := *g.txn
+		w.WaitingTxn = &txnCopy
+	}
+	if active {
+		g.mu.Lock()
+		w.WaitDuration = now.Sub(g.mu.curLockWaitStart)
+		g.mu.Unlock()
+	}
+	return w
+}
+
 func (g *lockTableGuardImpl) ShouldWait() bool {
 	g.mu.Lock()
 	defer g.mu.Unlock()
@@ -547,6 +572,9 @@ type lockState struct {
 		locked bool
 		// LockStrength is always Exclusive
 		holder [lock.MaxDurability + 1]lockHolderInfo
+		// The time at which the lock transitioned to held. Only used for
+		// observability.
+		startTime time.Time
 	}
 
 	// Information about the requests waiting on the lock.
@@ -1008,6 +1036,31 @@ func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 	return l.holder.holder[index].txn, l.holder.holder[index].ts
 }
 
+// Returns information about the lock holder and the requests waiting on the
+// lock, for reporting through the status server.
+// REQUIRES: l.mu is locked.
+func (l *lockState) lockStateInfo(now time.Time) kvserverpb.LockStateInfo {
+	info := kvserverpb.LockStateInfo{Key: l.key}
+	if txn, _ := l.getLockHolder(); txn != nil {
+		txnCopy := *txn
+		info.LockHolder = &txnCopy
+		info.Durability = lock.Unreplicated
+		if l.holder.holder[lock.Replicated].txn != nil {
+			info.Durability = lock.Replicated
+		}
+		info.HoldDuration = now.Sub(l.holder.startTime)
+	}
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		qg := e.Value.(*queuedGuard)
+		info.Waiters = append(info.Waiters, qg.guard.lockWaiter(qg.active, lock.Exclusive, now))
+	}
+	for e := l.waitingReaders.Front(); e != nil; e = e.Next() {
+		g := e.Value.(*lockTableGuardImpl)
+		info.Waiters = append(info.Waiters, g.lockWaiter(true /* active */, lock.None, now))
+	}
+	return info
+}
+
 // Removes the current lock holder from the lock.
 // REQUIRES: l.mu is locked.
 func (l *lockState) clearLockHolder() {
@@ -1156,6 +1209,9 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 		g.mu.locks[l] = struct{}{}
 	}
 	// Make it an active waiter.
+	if !g.mu.startWait || !g.key.Equal(l.key) {
+		g.mu.curLockWaitStart = timeutil.Now()
+	}
 	g.key = l.key
 	g.mu.startWait = true
 	if g.isSameTxnAsReservation(waitForState) {
@@ -1295,6 +1351,7 @@ func (l *lockState) acquireLock(
 	}
 	l.reservation = nil
 	l.holder.locked = true
+	l.holder.startTime = timeutil.Now()
 	l.holder.holder[durability].txn = txn
 	l.holder.holder[durability].ts = ts
 	l.holder.holder[durability].seqs = append([]enginepb.TxnSeq(nil), txn.Sequence)
@@ -1323,6 +1380,7 @@ func (l *lockState) discoveredLock(
 		}
 	} else {
 		l.holder.locked = true
+		l.holder.startTime = timeutil.Now()
 	}
 	holder := &l.holder.holder[lock.Replicated]
 	if holder.txn == nil {
@@ -1808,6 +1866,13 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 			}
 		}
 	}
+	if req.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		// Requests that skip locked keys never wait in lock wait-queues.
+		// Instead, they consult the lockTable during evaluation through
+		// IsKeyLockedByConflictingTxn and skip over any keys that are locked
+		// by conflicting transactions.
+		return g
+	}
 	g.findNextLockAfter(true /* notify */)
 	return g
 }
@@ -1941,6 +2006,29 @@ func (t *lockTableImpl) AcquireLock(
 	return err
 }
 
+// IsKeyLockedByConflictingTxn implements the lockTable interface.
+func (t *lockTableImpl) IsKeyLockedByConflictingTxn(key roachpb.Key, txn *enginepb.TxnMeta) bool {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(key) {
+		ss = spanset.SpanLocal
+	}
+	tree := &t.locks[ss]
+	tree.mu.RLock()
+	defer tree.mu.RUnlock()
+	iter := tree.MakeIter()
+	iter.FirstOverlap(&lockState{key: key})
+	if !iter.Valid() {
+		return false
+	}
+	l := iter.Cur()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.holder.locked {
+		return false
+	}
+	return txn == nil || !l.isLockedBy(txn.ID)
+}
+
 // If force is false, removes all locks, except for those that are held with
 // replicated durability and have no distinguished waiter, and tells those
 // waiters to wait elsewhere or that they are done waiting. A replicated lock
@@ -2116,6 +2204,31 @@ func (t *lockTableImpl) Clear(disable bool) {
 	t.tryClearLocks(true /* force */)
 }
 
+// QueryLockTableState implements the lockTable interface.
+func (t *lockTableImpl) QueryLockTableState(now time.Time) []kvserverpb.LockStateInfo {
+	t.enabledMu.RLock()
+	defer t.enabledMu.RUnlock()
+	if !t.enabled {
+		return nil
+	}
+	var infos []kvserverpb.LockStateInfo
+	for i := 0; i < len(t.locks); i++ {
+		tree := &t.locks[i]
+		tree.mu.RLock()
+		iter := tree.MakeIter()
+		for iter.First(); iter.Valid(); iter.Next() {
+			l := iter.Cur()
+			l.mu.Lock()
+			if !l.isEmptyLock() {
+				infos = append(infos, l.lockStateInfo(now))
+			}
+			l.mu.Unlock()
+		}
+		tree.mu.RUnlock()
+	}
+	return infos
+}
+
 // For tests.
 func (t *lockTableImpl) String() string {
 	var buf strings.Builder
@@ -124,6 +124,12 @@ print
 <state of lock table>
 
  Calls lockTable.String.
+
+query-lock-table
+----
+<state of locks and waiters>
+
+ Calls lockTable.QueryLockTableState.
 */
 
 func TestLockTableBasic(t *testing.T) {
@@ -412,6 +418,25 @@ func TestLockTableBasic(t *testing.T) {
 			case "print":
 				return lt.(*lockTableImpl).String()
 
+			case "query-lock-table":
+				// Durations depend on the wall clock, so they are not printed.
+				var buf strings.Builder
+				for _, l := range lt.QueryLockTableState(timeutil.Now()) {
+					fmt.Fprintf(&buf, "lock: %s\n", l.Key)
+					if l.LockHolder != nil {
+						fmt.Fprintf(&buf, " holder: txn: %v, durability: %s\n", l.LockHolder.ID, l.Durability)
+					}
+					for _, w := range l.Waiters {
+						var txnS string
+						if w.WaitingTxn != nil {
+							txnS = w.WaitingTxn.ID.String()
+						}
+						fmt.Fprintf(&buf, " waiter: txn: %s, active: %t, strength: %s\n",
+							txnS, w.ActiveWaiter, w.Strength)
+					}
+				}
+				return buf.String()
+
 			default:
 				return fmt.Sprintf("unknown command: %s", d.Cmd)
 			}
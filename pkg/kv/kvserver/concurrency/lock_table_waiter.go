@@ -15,6 +15,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -106,6 +107,10 @@ type lockTableWaiterImpl struct {
 	// between separate concurrency.Manager instances.
 	finalizedTxnCache txnCache
 
+	// contentionEvents, if set, records the lock contention encountered by
+	// waiting requests.
+	contentionEvents *ContentionEventRegistry
+
 	// When set, WriteIntentError are propagated instead of pushing
 	// conflicting transactions.
 	disableTxnPushing bool
@@ -151,11 +156,15 @@ func (w *lockTableWaiterImpl) WaitOn(
 	// re-discover the intent(s) during evaluation and resolve them themselves.
 	var deferredResolution []roachpb.LockUpdate
 	defer w.resolveDeferredIntents(ctx, &err, &deferredResolution)
+	// Used to record the contention encountered while waiting.
+	contention := contentionEventTracker{registry: w.contentionEvents}
+	defer contention.done()
 	for {
 		select {
 		case <-newStateC:
 			timerC = nil
 			state := guard.CurState()
+			contention.notify(state)
 			switch state.kind {
 			case waitFor, waitForDistinguished:
 				// waitFor indicates that the request is waiting on another
@@ -174,6 +183,26 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// out the longer deadlock detection delay before recognizing and
 				// recovering from the failure of a transaction coordinator for
 				// *each* of that transaction's previously written intents.
+				//
+				// If the request is using an Error wait policy, it does not wait
+				// at all. If the conflict is a held lock then the request pushes
+				// the lock holder's transaction using a PUSH_TOUCH to determine
+				// whether the lock is abandoned or whether its holder is still
+				// active. If the conflict is a reservation holder, the request
+				// raises an error immediately because the reservation holder is
+				// known to be active.
+				if req.WaitPolicy == lock.WaitPolicy_Error {
+					if state.held {
+						err = w.pushLockTxn(ctx, req, state)
+					} else {
+						err = newWriteIntentErr(state)
+					}
+					if err != nil {
+						return err
+					}
+					continue
+				}
+
 				livenessPush := state.kind == waitForDistinguished
 				deadlockPush := true
 
@@ -420,20 +449,36 @@ func (w *lockTableWaiterImpl) pushLockTxn(
 	// push the lock holder's timestamp forward so the read request can read
 	// under the lock. For write-write conflicts, try to abort the lock holder
 	// entirely so the write request can revoke and replace the lock with its
-	// own lock.
+	// own lock. Requests using an Error wait policy only touch the lock
+	// holder's transaction record to determine whether it is still active.
 	h := w.pushHeader(req)
 	var pushType roachpb.PushTxnType
-	switch ws.guardAccess {
-	case spanset.SpanReadOnly:
-		pushType = roachpb.PUSH_TIMESTAMP
-		log.VEventf(ctx, 3, "pushing timestamp of txn %s above %s", ws.txn.ID.Short(), h.Timestamp)
-	case spanset.SpanReadWrite:
-		pushType = roachpb.PUSH_ABORT
-		log.VEventf(ctx, 3, "pushing txn %s to abort", ws.txn.ID.Short())
+	switch req.WaitPolicy {
+	case lock.WaitPolicy_Block:
+		switch ws.guardAccess {
+		case spanset.SpanReadOnly:
+			pushType = roachpb.PUSH_TIMESTAMP
+			log.VEventf(ctx, 3, "pushing timestamp of txn %s above %s", ws.txn.ID.Short(), h.Timestamp)
+		case spanset.SpanReadWrite:
+			pushType = roachpb.PUSH_ABORT
+			log.VEventf(ctx, 3, "pushing txn %s to abort", ws.txn.ID.Short())
+		}
+	case lock.WaitPolicy_Error:
+		pushType = roachpb.PUSH_TOUCH
+		log.VEventf(ctx, 3, "pushing txn %s to check if abandoned", ws.txn.ID.Short())
+	default:
+		log.Fatalf(ctx, "unexpected WaitPolicy: %v", req.WaitPolicy)
 	}
 
 	pusheeTxn, err := w.ir.PushTransaction(ctx, ws.txn, h, pushType)
 	if err != nil {
+		// If the push failed and the request is using an Error wait policy,
+		// the lock holder is still active. Convert the error into a
+		// WriteIntentError that is returned to the client.
+		if _, ok := err.GetDetail().(*roachpb.TransactionPushError); ok &&
+			req.WaitPolicy == lock.WaitPolicy_Error {
+			err = newWriteIntentErr(ws)
+		}
 		return err
 	}
 
@@ -476,6 +521,16 @@ func (w *lockTableWaiterImpl) pushLockTxn(
 	return w.ir.ResolveIntent(ctx, resolve, opts)
 }
 
+// newWriteIntentErr creates a WriteIntentError for a request that was unable
+// to proceed because of a conflicting lock and whose wait policy did not
+// permit it to wait for the lock to be released.
+func newWriteIntentErr(ws waitingState) *Error {
+	return roachpb.NewError(&roachpb.WriteIntentError{
+		Intents: []roachpb.Intent{roachpb.MakeIntent(ws.txn, ws.key)},
+		Reason:  roachpb.WriteIntentError_REASON_WAIT_POLICY,
+	})
+}
+
 // pushRequestTxn pushes the owner of the provided request.
 //
 // The method blocks until either the pusher's transaction is aborted or the
@@ -16,6 +16,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -82,6 +83,9 @@ func (g *mockLockTableGuard) OnLockUpdated(_ context.Context, up *roachpb.LockUp
 		g.notify()
 	}
 }
+func (g *mockLockTableGuard) IsKeyLockedByConflictingTxn(roachpb.Key, *enginepb.TxnMeta) bool {
+	panic("unimplemented")
+}
 
 func setupLockTableWaiterTest() (*lockTableWaiterImpl, *mockIntentResolver, *mockLockTableGuard) {
 	ir := &mockIntentResolver{}
@@ -326,6 +330,123 @@ func testWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPushTS hl
 	})
 }
 
+// TestLockTableWaiterWithErrorWaitPolicy tests the lockTableWaiter's behavior
+// under different waiting states with an Error wait policy.
+func TestLockTableWaiterWithErrorWaitPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn := makeTxnProto("request")
+	makeReq := func() Request {
+		return Request{
+			Txn:        &txn,
+			Timestamp:  txn.ReadTimestamp,
+			WaitPolicy: lock.WaitPolicy_Error,
+		}
+	}
+
+	t.Run("state", func(t *testing.T) {
+		t.Run("waitFor", func(t *testing.T) {
+			testErrorWaitPush(t, waitFor, makeReq)
+		})
+
+		t.Run("waitForDistinguished", func(t *testing.T) {
+			testErrorWaitPush(t, waitForDistinguished, makeReq)
+		})
+
+		t.Run("waitElsewhere", func(t *testing.T) {
+			testErrorWaitPush(t, waitElsewhere, makeReq)
+		})
+	})
+}
+
+func testErrorWaitPush(t *testing.T, k waitKind, makeReq func() Request) {
+	ctx := context.Background()
+	keyA := roachpb.Key("keyA")
+	testutils.RunTrueAndFalse(t, "lockHeld", func(t *testing.T, lockHeld bool) {
+		testutils.RunTrueAndFalse(t, "pusheeActive", func(t *testing.T, pusheeActive bool) {
+			if !lockHeld && !pusheeActive {
+				// Reservation holders are known to be active.
+				return
+			}
+			w, ir, g := setupLockTableWaiterTest()
+			defer w.stopper.Stop(ctx)
+			pusheeTxn := makeTxnProto("pushee")
+
+			req := makeReq()
+			g.state = waitingState{
+				kind:        k,
+				txn:         &pusheeTxn.TxnMeta,
+				key:         keyA,
+				held:        lockHeld,
+				guardAccess: spanset.SpanReadOnly,
+			}
+			g.notify()
+
+			// waitElsewhere does not cause a push if the lock is not held.
+			// It returns immediately.
+			if k == waitElsewhere && !lockHeld {
+				err := w.WaitOn(ctx, req, g)
+				require.Nil(t, err)
+				return
+			}
+
+			// If the lock is not held, the request raises an error immediately
+			// without pushing the reservation holder.
+			if !lockHeld {
+				err := w.WaitOn(ctx, req, g)
+				requireWaitPolicyWriteIntentErr(t, keyA, err)
+				return
+			}
+
+			ir.pushTxn = func(
+				_ context.Context,
+				pusheeArg *enginepb.TxnMeta,
+				h roachpb.Header,
+				pushType roachpb.PushTxnType,
+			) (*roachpb.Transaction, *Error) {
+				require.Equal(t, &pusheeTxn.TxnMeta, pusheeArg)
+				require.Equal(t, req.Txn, h.Txn)
+				require.Equal(t, roachpb.PUSH_TOUCH, pushType)
+
+				if pusheeActive {
+					return nil, roachpb.NewError(&roachpb.TransactionPushError{
+						PusheeTxn: roachpb.Transaction{TxnMeta: *pusheeArg},
+					})
+				}
+
+				// The lock holder is abandoned, so the request will resolve
+				// its lock and proceed.
+				resp := &roachpb.Transaction{TxnMeta: *pusheeArg, Status: roachpb.ABORTED}
+				ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
+					require.Equal(t, keyA, intent.Key)
+					require.Equal(t, pusheeTxn.ID, intent.Txn.ID)
+					require.Equal(t, roachpb.ABORTED, intent.Status)
+					g.state = waitingState{kind: doneWaiting}
+					g.notify()
+					return nil
+				}
+				return resp, nil
+			}
+
+			err := w.WaitOn(ctx, req, g)
+			if pusheeActive {
+				requireWaitPolicyWriteIntentErr(t, keyA, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	})
+}
+
+func requireWaitPolicyWriteIntentErr(t *testing.T, key roachpb.Key, err *Error) {
+	require.NotNil(t, err)
+	wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+	require.True(t, ok, "expected WriteIntentError, found %v", err)
+	require.Equal(t, roachpb.WriteIntentError_REASON_WAIT_POLICY, wiErr.Reason)
+	require.Len(t, wiErr.Intents, 1)
+	require.Equal(t, key, wiErr.Intents[0].Key)
+}
+
 func testWaitNoopUntilDone(t *testing.T, k waitKind, makeReq func() Request) {
 	ctx := context.Background()
 	w, _, g := setupLockTableWaiterTest()
@@ -0,0 +1,46 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+)
+
+// CollectReplicaInfo returns the descriptors and raft indexes of all
+// initialized replicas on the given stores.
+func CollectReplicaInfo(
+	ctx context.Context, stores *kvserver.Stores,
+) (loqrecoverypb.NodeReplicaInfo, error) {
+	var info loqrecoverypb.NodeReplicaInfo
+	err := stores.VisitStores(func(s *kvserver.Store) error {
+		s.VisitReplicas(func(r *kvserver.Replica) bool {
+			if !r.IsInitialized() {
+				return true
+			}
+			replica := loqrecoverypb.ReplicaInfo{
+				NodeID:           s.Ident.NodeID,
+				StoreID:          s.StoreID(),
+				Desc:             *r.Desc(),
+				RaftAppliedIndex: r.State().State.RaftAppliedIndex,
+			}
+			if status := r.RaftStatus(); status != nil {
+				replica.RaftCommittedIndex = status.Commit
+			}
+			info.Replicas = append(info.Replicas, replica)
+			return true
+		})
+		return nil
+	})
+	return info, err
+}
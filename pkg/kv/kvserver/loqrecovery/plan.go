@@ -0,0 +1,238 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/reports"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// RangeReport describes the recovery planned for a single range.
+type RangeReport struct {
+	RangeID  roachpb.RangeID
+	StartKey roachpb.RKey
+	EndKey   roachpb.RKey
+	// Survivor is the replica which becomes the only voter of the range.
+	Survivor roachpb.ReplicaDescriptor
+	// Lost are the replicas of the range on nodes that did not report.
+	Lost []roachpb.ReplicaDescriptor
+	// LostLocalities are the critical localities of the range which have no
+	// reporting node left. Their loss is what made the range lose quorum.
+	LostLocalities []reports.LocalityRepr
+}
+
+// PlanningReport summarizes a recovery plan for the operator.
+type PlanningReport struct {
+	// Ranges describes every range for which an update was planned.
+	Ranges []RangeReport
+	// Unrecoverable are the ranges which lost quorum but for which no survivor
+	// could be picked, as none of their voters reported.
+	Unrecoverable []roachpb.RangeDescriptor
+	// Stale are the replicas whose descriptor overlaps a newer descriptor of
+	// another range. They are left to replica GC and never picked as survivors.
+	Stale []loqrecoverypb.ReplicaInfo
+	// Gaps are the spans of the keyspace for which no replica reported a
+	// descriptor. The data in them is lost.
+	Gaps []roachpb.RSpan
+}
+
+// PlanReplicas computes the updates needed to restore quorum to all ranges in
+// the collected replica info. A range has lost quorum if the replicas of its
+// most recent descriptor on reporting nodes don't form a quorum. For each such
+// range, the reporting voter which applied the most raft log entries is picked
+// as the survivor; ties are broken by picking the highest store ID.
+func PlanReplicas(
+	ctx context.Context, info loqrecoverypb.ClusterReplicaInfo,
+) (loqrecoverypb.ReplicaUpdatePlan, PlanningReport, error) {
+	var plan loqrecoverypb.ReplicaUpdatePlan
+	var report PlanningReport
+
+	reportingNodes := make(map[roachpb.NodeID]struct{}, len(info.ReportingNodeIDs))
+	for _, nodeID := range info.ReportingNodeIDs {
+		reportingNodes[nodeID] = struct{}{}
+	}
+	isReporting := func(nodeID roachpb.NodeID) bool {
+		_, ok := reportingNodes[nodeID]
+		return ok
+	}
+
+	// Group the replicas by range and pick the most recent descriptor of each.
+	byRange := make(map[roachpb.RangeID][]loqrecoverypb.ReplicaInfo)
+	for _, replica := range info.Replicas {
+		byRange[replica.Desc.RangeID] = append(byRange[replica.Desc.RangeID], replica)
+	}
+	descs := make([]roachpb.RangeDescriptor, 0, len(byRange))
+	for _, replicas := range byRange {
+		latest := replicas[0].Desc
+		for _, replica := range replicas[1:] {
+			if replica.Desc.Generation > latest.Generation {
+				latest = replica.Desc
+			}
+		}
+		descs = append(descs, latest)
+	}
+
+	descs, stale := dropOverlappingDescriptors(descs)
+	for _, rangeID := range stale {
+		report.Stale = append(report.Stale, byRange[rangeID]...)
+	}
+	report.Gaps = findGaps(descs)
+
+	nodes := make(map[roachpb.NodeID]roachpb.NodeDescriptor, len(info.Nodes))
+	nodeLocalities := make(map[roachpb.NodeID]roachpb.Locality, len(info.Nodes))
+	reportingLocalities := make(map[reports.LocalityRepr]struct{})
+	for _, node := range info.Nodes {
+		nodes[node.NodeID] = node
+		nodeLocalities[node.NodeID] = node.Locality
+		if !isReporting(node.NodeID) {
+			continue
+		}
+		for i := range node.Locality.Tiers {
+			loc := roachpb.Locality{Tiers: node.Locality.Tiers[:i+1]}
+			reportingLocalities[reports.LocalityRepr(loc.String())] = struct{}{}
+		}
+	}
+
+	for i := range descs {
+		desc := &descs[i]
+		if desc.Replicas().CanMakeProgress(func(rDesc roachpb.ReplicaDescriptor) bool {
+			return isReporting(rDesc.NodeID)
+		}) {
+			continue
+		}
+
+		survivor, ok := pickSurvivor(byRange[desc.RangeID])
+		if !ok {
+			report.Unrecoverable = append(report.Unrecoverable, *desc)
+			continue
+		}
+		replDesc, _ := survivor.Desc.GetReplicaDescriptor(survivor.StoreID)
+		update := loqrecoverypb.ReplicaUpdate{
+			RangeID:         desc.RangeID,
+			StartKey:        desc.StartKey,
+			Survivor:        replDesc,
+			DescGeneration:  survivor.Desc.Generation,
+			MinAppliedIndex: survivor.RaftAppliedIndex,
+		}
+		var rangeStores []roachpb.StoreDescriptor
+		for _, rDesc := range desc.Replicas().All() {
+			if !isReporting(rDesc.NodeID) {
+				update.LostReplicas = append(update.LostReplicas, rDesc)
+			}
+			if node, ok := nodes[rDesc.NodeID]; ok {
+				rangeStores = append(rangeStores, roachpb.StoreDescriptor{
+					StoreID: rDesc.StoreID,
+					Node:    node,
+				})
+			}
+		}
+		plan.Updates = append(plan.Updates, update)
+
+		// Report the localities the range could not survive losing and which
+		// have indeed been lost.
+		var lostLocalities []reports.LocalityRepr
+		critical := reports.CriticalLocalitiesForRange(ctx, desc, nodeLocalities,
+			func(roachpb.NodeID) bool { return true }, rangeStores)
+		for _, loc := range critical {
+			if _, ok := reportingLocalities[loc]; !ok {
+				lostLocalities = append(lostLocalities, loc)
+			}
+		}
+		report.Ranges = append(report.Ranges, RangeReport{
+			RangeID:        desc.RangeID,
+			StartKey:       desc.StartKey,
+			EndKey:         desc.EndKey,
+			Survivor:       replDesc,
+			Lost:           update.LostReplicas,
+			LostLocalities: lostLocalities,
+		})
+	}
+	return plan, report, nil
+}
+
+// pickSurvivor returns the replica which should survive among the reported
+// replicas of a range. Only voters in their own descriptor are considered.
+func pickSurvivor(
+	replicas []loqrecoverypb.ReplicaInfo,
+) (survivor loqrecoverypb.ReplicaInfo, ok bool) {
+	for _, replica := range replicas {
+		replDesc, found := replica.Desc.GetReplicaDescriptor(replica.StoreID)
+		if !found || replDesc.GetType() == roachpb.LEARNER {
+			continue
+		}
+		if !ok || replica.RaftAppliedIndex > survivor.RaftAppliedIndex ||
+			(replica.RaftAppliedIndex == survivor.RaftAppliedIndex && replica.StoreID > survivor.StoreID) {
+			survivor, ok = replica, true
+		}
+	}
+	return survivor, ok
+}
+
+// dropOverlappingDescriptors removes the descriptors which overlap a
+// descriptor with a higher generation. These belong to replicas which were
+// merged away or otherwise removed but not yet garbage collected; reviving
+// them would resurrect stale data. The remaining descriptors are returned
+// sorted by start key along with the IDs of the dropped ranges.
+func dropOverlappingDescriptors(
+	descs []roachpb.RangeDescriptor,
+) ([]roachpb.RangeDescriptor, []roachpb.RangeID) {
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].StartKey.Less(descs[j].StartKey)
+	})
+	var stale []roachpb.RangeID
+	res := descs[:0]
+	for _, desc := range descs {
+		// Find the previously kept descriptors this one overlaps. If any of them
+		// is newer, this descriptor is stale; otherwise they all are.
+		i := len(res)
+		for i > 0 && desc.StartKey.Less(res[i-1].EndKey) {
+			i--
+		}
+		overlapped := res[i:]
+		newer := false
+		for _, prev := range overlapped {
+			if prev.Generation >= desc.Generation {
+				newer = true
+				break
+			}
+		}
+		if newer {
+			stale = append(stale, desc.RangeID)
+			continue
+		}
+		for _, prev := range overlapped {
+			stale = append(stale, prev.RangeID)
+		}
+		res = append(res[:i], desc)
+	}
+	return res, stale
+}
+
+// findGaps returns the spans of the keyspace not covered by any of the given
+// non-overlapping descriptors, which must be sorted by start key.
+func findGaps(descs []roachpb.RangeDescriptor) []roachpb.RSpan {
+	var gaps []roachpb.RSpan
+	prevEnd := roachpb.RKeyMin
+	for _, desc := range descs {
+		if prevEnd.Less(desc.StartKey) {
+			gaps = append(gaps, roachpb.RSpan{Key: prevEnd, EndKey: desc.StartKey})
+		}
+		prevEnd = desc.EndKey
+	}
+	if prevEnd.Less(roachpb.RKeyMax) {
+		gaps = append(gaps, roachpb.RSpan{Key: prevEnd, EndKey: roachpb.RKeyMax})
+	}
+	return gaps
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/reports"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// makeDesc returns a descriptor for the given span with a replica on each of
+// the given nodes. Node n has a single store with the same ID.
+func makeDesc(
+	rangeID roachpb.RangeID, start, end string, gen int64, nodes ...roachpb.NodeID,
+) roachpb.RangeDescriptor {
+	desc := roachpb.RangeDescriptor{
+		RangeID:       rangeID,
+		StartKey:      roachpb.RKey(start),
+		EndKey:        roachpb.RKey(end),
+		NextReplicaID: 1,
+		Generation:    gen,
+	}
+	if end == "" {
+		desc.EndKey = roachpb.RKeyMax
+	}
+	for _, n := range nodes {
+		desc.AddReplica(n, roachpb.StoreID(n), roachpb.VOTER_FULL)
+	}
+	return desc
+}
+
+func makeReplica(
+	desc roachpb.RangeDescriptor, nodeID roachpb.NodeID, appliedIndex uint64,
+) loqrecoverypb.ReplicaInfo {
+	return loqrecoverypb.ReplicaInfo{
+		NodeID:           nodeID,
+		StoreID:          roachpb.StoreID(nodeID),
+		Desc:             desc,
+		RaftAppliedIndex: appliedIndex,
+	}
+}
+
+// makeNodes returns descriptors for nodes 1 through n, with node i located in
+// region r((i-1)/3+1).
+func makeNodes(n int) []roachpb.NodeDescriptor {
+	regions := []string{"r1", "r2", "r3"}
+	var nodes []roachpb.NodeDescriptor
+	for i := 1; i <= n; i++ {
+		nodes = append(nodes, roachpb.NodeDescriptor{
+			NodeID: roachpb.NodeID(i),
+			Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: regions[(i-1)/3]},
+			}},
+		})
+	}
+	return nodes
+}
+
+func TestPlanReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	// r1 [a, c) has replicas on n1, n4 and n7, one per region, and keeps
+	// quorum after losing r3. r2 [c, max) has replicas on n1, n2, n7, n8 and
+	// n9 and loses quorum along with r3; n2 applied the most entries and
+	// survives.
+	r1 := makeDesc(1, "a", "c", 1, 1, 4, 7)
+	r2 := makeDesc(2, "c", "", 1, 1, 2, 7, 8, 9)
+	info := loqrecoverypb.ClusterReplicaInfo{
+		Replicas: []loqrecoverypb.ReplicaInfo{
+			makeReplica(r1, 1, 10),
+			makeReplica(r1, 4, 10),
+			makeReplica(r2, 1, 20),
+			makeReplica(r2, 2, 21),
+		},
+		Nodes:            makeNodes(9),
+		ReportingNodeIDs: []roachpb.NodeID{1, 2, 3, 4, 5, 6},
+	}
+
+	plan, report, err := PlanReplicas(ctx, info)
+	require.NoError(t, err)
+	require.Equal(t, []loqrecoverypb.ReplicaUpdate{{
+		RangeID:         2,
+		StartKey:        roachpb.RKey("c"),
+		Survivor:        roachpb.ReplicaDescriptor{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		DescGeneration:  1,
+		MinAppliedIndex: 21,
+		LostReplicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 7, StoreID: 7, ReplicaID: 3},
+			{NodeID: 8, StoreID: 8, ReplicaID: 4},
+			{NodeID: 9, StoreID: 9, ReplicaID: 5},
+		},
+	}}, plan.Updates)
+	require.Len(t, report.Ranges, 1)
+	require.Equal(t, []reports.LocalityRepr{"region=r3"}, report.Ranges[0].LostLocalities)
+	require.Equal(t, []roachpb.RSpan{{Key: roachpb.RKeyMin, EndKey: roachpb.RKey("a")}}, report.Gaps)
+	require.Empty(t, report.Unrecoverable)
+	require.Empty(t, report.Stale)
+}
+
+func TestPlanReplicasTieBreak(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	desc := makeDesc(1, "", "", 1, 1, 2, 3, 4, 5)
+	info := loqrecoverypb.ClusterReplicaInfo{
+		Replicas: []loqrecoverypb.ReplicaInfo{
+			makeReplica(desc, 1, 10),
+			makeReplica(desc, 2, 10),
+		},
+		Nodes:            makeNodes(5),
+		ReportingNodeIDs: []roachpb.NodeID{1, 2},
+	}
+	plan, _, err := PlanReplicas(ctx, info)
+	require.NoError(t, err)
+	require.Len(t, plan.Updates, 1)
+	require.Equal(t, roachpb.StoreID(2), plan.Updates[0].Survivor.StoreID)
+}
+
+func TestPlanReplicasStaleAndUnrecoverable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	// r1 was merged with r2 into [min, max) at generation 3, but n3 still has a
+	// stale replica of r2 from generation 2 which must not be revived.
+	merged := makeDesc(1, "", "", 3, 1, 2, 3)
+	staleRHS := makeDesc(2, "m", "", 2, 3, 4, 5)
+	// r3 has a learner on the only reporting node.
+	learner := makeDesc(3, "", "", 1, 4, 5)
+	learner.AddReplica(3, 3, roachpb.LEARNER)
+	learner.StartKey = roachpb.RKey("zz")
+	info := loqrecoverypb.ClusterReplicaInfo{
+		Replicas: []loqrecoverypb.ReplicaInfo{
+			makeReplica(merged, 1, 5),
+			makeReplica(merged, 3, 5),
+			makeReplica(staleRHS, 3, 3),
+		},
+		Nodes:            makeNodes(5),
+		ReportingNodeIDs: []roachpb.NodeID{1, 3},
+	}
+	plan, report, err := PlanReplicas(ctx, info)
+	require.NoError(t, err)
+	require.Empty(t, plan.Updates)
+	require.Len(t, report.Stale, 1)
+	require.Equal(t, roachpb.RangeID(2), report.Stale[0].Desc.RangeID)
+
+	info.ReportingNodeIDs = []roachpb.NodeID{3}
+	info.Replicas = []loqrecoverypb.ReplicaInfo{makeReplica(learner, 3, 1)}
+	plan, report, err = PlanReplicas(ctx, info)
+	require.NoError(t, err)
+	require.Empty(t, plan.Updates)
+	require.Len(t, report.Unrecoverable, 1)
+	require.Equal(t, roachpb.RangeID(3), report.Unrecoverable[0].RangeID)
+}
+
+func TestVerifyPlan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := makeDesc(1, "", "", 1, 1, 2, 3)
+	plan := loqrecoverypb.ReplicaUpdatePlan{
+		Updates: []loqrecoverypb.ReplicaUpdate{{
+			RangeID:        1,
+			Survivor:       roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 1},
+			DescGeneration: 1,
+		}},
+	}
+	info := loqrecoverypb.ClusterReplicaInfo{
+		Replicas:         []loqrecoverypb.ReplicaInfo{makeReplica(desc, 1, 10)},
+		ReportingNodeIDs: []roachpb.NodeID{1},
+	}
+	require.Len(t, VerifyPlan(plan, info), 1)
+
+	recovered := desc
+	recovered.SetReplicas(roachpb.MakeReplicaDescriptors(
+		[]roachpb.ReplicaDescriptor{plan.Updates[0].Survivor}))
+	recovered.IncrementGeneration()
+	info.Replicas = []loqrecoverypb.ReplicaInfo{makeReplica(recovered, 1, 11)}
+	require.Empty(t, VerifyPlan(plan, info))
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecoverLossOfQuorum stops a majority of the replicas of a range and
+// restores its quorum through the recovery admin RPCs.
+func TestRecoverLossOfQuorum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	// With manual replication, the system ranges only have a replica on n1
+	// and survive the loss of the other nodes.
+	tc := testcluster.StartTestCluster(t, 5, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	key := tc.ScratchRange(t)
+	desc := tc.AddReplicasOrFatal(t, key, tc.Targets(1, 2)...)
+	require.NoError(t, tc.Server(0).DB().Put(ctx, key, "a"))
+
+	s := tc.Server(0)
+	conn, err := s.RPCContext().GRPCDialNode(s.ServingRPCAddr(), s.NodeID(),
+		rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	admin := serverpb.NewAdminClient(conn)
+
+	tc.StopServer(1)
+	tc.StopServer(2)
+
+	collect, err := admin.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []roachpb.NodeID{1, 4, 5}, collect.Info.ReportingNodeIDs)
+	require.Len(t, collect.Errors, 2)
+
+	plan, _, err := loqrecovery.PlanReplicas(ctx, collect.Info)
+	require.NoError(t, err)
+	require.Len(t, plan.Updates, 1)
+	update := plan.Updates[0]
+	require.Equal(t, desc.RangeID, update.RangeID)
+	require.Equal(t, tc.Target(0).StoreID, update.Survivor.StoreID)
+	require.Len(t, update.LostReplicas, 2)
+
+	// The plan is refused until the lost nodes are confirmed decommissioned.
+	apply, err := admin.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{Plan: plan})
+	require.NoError(t, err)
+	require.Len(t, apply.Results, 1)
+	require.Contains(t, apply.Results[0].Error, "is not confirmed decommissioned")
+
+	// It is also refused while the lost nodes are live. The refused updates
+	// leave the range untouched, so they can be retried until the liveness
+	// records of the stopped nodes expire.
+	plan.DecommissionedNodeIDs = []roachpb.NodeID{
+		update.LostReplicas[0].NodeID, update.LostReplicas[1].NodeID,
+	}
+	testutils.SucceedsSoon(t, func() error {
+		apply, err := admin.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{Plan: plan})
+		if err != nil {
+			return err
+		}
+		if len(apply.Errors) > 0 {
+			return errors.Newf("n%d: %s", apply.Errors[0].NodeID, apply.Errors[0].Message)
+		}
+		if len(apply.Results) != 1 || apply.Results[0].Error != "" {
+			return errors.Newf("unexpected results %+v", apply.Results)
+		}
+		return nil
+	})
+
+	collect, err = admin.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{})
+	require.NoError(t, err)
+	require.Empty(t, loqrecovery.VerifyPlan(plan, collect.Info))
+
+	// The range is available again.
+	require.NoError(t, contextutil.RunWithTimeout(ctx, "put", 30*time.Second,
+		func(ctx context.Context) error {
+			return tc.Server(0).DB().Put(ctx, key, "b")
+		}))
+	kv, err := tc.Server(0).DB().Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), kv.ValueBytes())
+
+	// The meta2 record of the range is rewritten to the recovered descriptor,
+	// which only lists the survivor.
+	testutils.SucceedsSoon(t, func() error {
+		var metaDesc roachpb.RangeDescriptor
+		metaKey := keys.RangeMetaKey(desc.EndKey).AsRawKey()
+		if err := tc.Server(0).DB().GetProto(ctx, metaKey, &metaDesc); err != nil {
+			return err
+		}
+		if len(metaDesc.InternalReplicas) != 1 {
+			return errors.Newf("meta2 record not updated: %s", &metaDesc)
+		}
+		return nil
+	})
+
+	// Applying the plan again is refused, as the range has changed.
+	apply, err = admin.RecoveryApplyPlan(ctx, &serverpb.RecoveryApplyPlanRequest{Plan: plan})
+	require.NoError(t, err)
+	require.Len(t, apply.Results, 1)
+	require.Contains(t, apply.Results[0].Error, "does not match planned")
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// VerifyPlan checks the replica info collected after a plan was applied and
+// returns an error for every planned range which did not recover. A range has
+// recovered if the survivor's descriptor is newer than the planned one and can
+// make progress with the reporting nodes, which also holds once the range has
+// been up-replicated again.
+func VerifyPlan(
+	plan loqrecoverypb.ReplicaUpdatePlan, info loqrecoverypb.ClusterReplicaInfo,
+) []error {
+	type storeRange struct {
+		storeID roachpb.StoreID
+		rangeID roachpb.RangeID
+	}
+	replicas := make(map[storeRange]loqrecoverypb.ReplicaInfo, len(info.Replicas))
+	for _, replica := range info.Replicas {
+		replicas[storeRange{replica.StoreID, replica.Desc.RangeID}] = replica
+	}
+	reportingNodes := make(map[roachpb.NodeID]struct{}, len(info.ReportingNodeIDs))
+	for _, nodeID := range info.ReportingNodeIDs {
+		reportingNodes[nodeID] = struct{}{}
+	}
+
+	var errs []error
+	for _, update := range plan.Updates {
+		replica, ok := replicas[storeRange{update.Survivor.StoreID, update.RangeID}]
+		if !ok {
+			errs = append(errs, errors.Errorf("r%d: survivor %s not found",
+				update.RangeID, update.Survivor))
+			continue
+		}
+		desc := &replica.Desc
+		if desc.Generation <= update.DescGeneration {
+			errs = append(errs, errors.Errorf("r%d: descriptor %s on %s was not updated",
+				update.RangeID, desc, update.Survivor))
+			continue
+		}
+		if !desc.Replicas().CanMakeProgress(func(rDesc roachpb.ReplicaDescriptor) bool {
+			_, ok := reportingNodes[rDesc.NodeID]
+			return ok
+		}) {
+			errs = append(errs, errors.Errorf("r%d: descriptor %s on %s still cannot make progress",
+				update.RangeID, desc, update.Survivor))
+		}
+	}
+	return errs
+}
@@ -204,6 +204,14 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// writeBytesStats tracks the number of bytes written by applied raft
+	// commands, including ingested SSTables, in order to aid in replica
+	// rebalancing decisions when balancing on write bytes.
+	writeBytesStats *replicaStats
+	// requestCPUStats tracks the estimated CPU time, in nanoseconds, spent
+	// serving BatchRequests on the replica in order to aid in lease and replica
+	// rebalancing decisions when balancing on CPU. See estimateRequestCPU.
+	requestCPUStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -907,7 +915,9 @@ func (r *Replica) GetMVCCStats() enginepb.MVCCStats {
 	return *r.mu.state.Stats
 }
 
-// GetSplitQPS returns the Replica's queries/s request rate.
+// GetSplitQPS returns the Replica's queries/s request rate, or its load along
+// the dimension chosen by "kv.allocator.load_based_rebalancing.dimension" if
+// that isn't QPS.
 //
 // NOTE: This should only be used for load based splitting, only
 // works when the load based splitting cluster setting is enabled.
@@ -242,6 +242,22 @@ func (r *Replica) WritesPerSecond() float64 {
 	return wps
 }
 
+// WriteBytesPerSecond returns the range's average bytes written per second,
+// as measured by the size of the write batches applied by Raft plus the size
+// of any ingested SSTables.
+func (r *Replica) WriteBytesPerSecond() float64 {
+	wbps, _ := r.writeBytesStats.avgQPS()
+	return wbps
+}
+
+// RequestCPUPerSecond returns the range's average estimated request CPU time,
+// in nanoseconds per second, spent serving BatchRequests on this replica. See
+// estimateRequestCPU for how the CPU time of a request is estimated.
+func (r *Replica) RequestCPUPerSecond() float64 {
+	cpu, _ := r.requestCPUStats.avgQPS()
+	return cpu
+}
+
 func (r *Replica) needsSplitBySizeRLocked() bool {
 	exceeded, _ := r.exceedsMultipleOfSplitSizeRLocked(1)
 	return exceeded
@@ -363,6 +363,9 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		if r.requestCPUStats != nil {
+			r.requestCPUStats.resetRequestCounts()
+		}
 	}
 
 	// Sanity check to make sure that the lease sequence is moving in the right
@@ -436,6 +439,9 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		if r.requestCPUStats != nil {
+			r.requestCPUStats.resetRequestCounts()
+		}
 	}
 
 	// Inform the concurrency manager that the lease holder has been updated.
@@ -22,19 +22,21 @@ const (
 )
 
 type replicaWithStats struct {
-	repl *Replica
-	qps  float64
+	repl       *Replica
+	qps        float64
+	writeBytes float64
+	cpu        float64
 	// TODO(a-robinson): Include writes-per-second and logicalBytes of storage?
 }
 
 // replicaRankings maintains top-k orderings of the replicas in a store along
-// different dimensions of concern, such as QPS, keys written per second, and
-// disk used.
+// different dimensions of concern, such as QPS, bytes written per second, and
+// request CPU.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator *rrAccumulator
+		byLoad      [numLBRebalancingDimensions][]replicaWithStats
 	}
 }
 
@@ -44,25 +46,33 @@ func newReplicaRankings() *replicaRankings {
 
 func (rr *replicaRankings) newAccumulator() *rrAccumulator {
 	res := &rrAccumulator{}
-	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	for i := range res.byLoad {
+		res.byLoad[i].val = LBRebalancingDimension(i).replicaLoad
+	}
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
 func (rr *replicaRankings) topQPS() []replicaWithStats {
+	return rr.topLoad(LBRebalancingQueries)
+}
+
+// topLoad returns the replicas with the most load along the given dimension,
+// in decreasing order of load.
+func (rr *replicaRankings) topLoad(dim LBRebalancingDimension) []replicaWithStats {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if pq := &rr.mu.accumulator.byLoad[dim]; pq.Len() > 0 {
+		rr.mu.byLoad[dim] = consumeAccumulator(pq)
 	}
-	return rr.mu.byQPS
+	return rr.mu.byLoad[dim]
 }
 
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
@@ -74,21 +84,28 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // prevents concurrent loaders of data from messing with each other -- the last
 // `update`d accumulator will win.
 type rrAccumulator struct {
-	qps rrPriorityQueue
+	// byLoad holds a priority queue for each LBRebalancingDimension.
+	byLoad [numLBRebalancingDimensions]rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
+	for i := range a.byLoad {
+		a.byLoad[i].add(repl)
+	}
+}
+
+func (pq *rrPriorityQueue) add(repl replicaWithStats) {
 	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
 		return
 	}
 
 	// Otherwise, conditionally push if the new replica is more deserving than
 	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
 	}
 }
 
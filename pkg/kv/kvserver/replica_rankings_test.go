@@ -71,3 +71,37 @@ func TestReplicaRankings(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicaRankingsByDimension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rr := newReplicaRankings()
+	acc := rr.newAccumulator()
+	// Each replica ranks differently along each dimension.
+	for i, stats := range []replicaWithStats{
+		{qps: 3, writeBytes: 1, cpu: 2},
+		{qps: 2, writeBytes: 3, cpu: 1},
+		{qps: 1, writeBytes: 2, cpu: 3},
+	} {
+		stats.repl = &Replica{RangeID: roachpb.RangeID(i)}
+		acc.addReplica(stats)
+	}
+	rr.update(acc)
+
+	for _, tc := range []struct {
+		dim  LBRebalancingDimension
+		want []roachpb.RangeID
+	}{
+		{LBRebalancingQueries, []roachpb.RangeID{0, 1, 2}},
+		{LBRebalancingWriteBytes, []roachpb.RangeID{1, 2, 0}},
+		{LBRebalancingCPU, []roachpb.RangeID{2, 0, 1}},
+	} {
+		var got []roachpb.RangeID
+		for _, r := range rr.topLoad(tc.dim) {
+			got = append(got, r.repl.RangeID)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("got %v for the top %s ranges; want %v", got, tc.dim, tc.want)
+		}
+	}
+}
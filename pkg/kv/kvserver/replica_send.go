@@ -110,6 +110,9 @@ func (r *Replica) sendWithRangeID(
 	if pErr != nil {
 		log.Eventf(ctx, "replica.Send got error: %s", pErr)
 	} else {
+		if (useRaft || isReadOnly) && r.requestCPUStats != nil {
+			r.requestCPUStats.recordCount(estimateRequestCPU(ba, br), 0 /* nodeID */)
+		}
 		if filter := r.store.cfg.TestingKnobs.TestingResponseFilter; filter != nil {
 			pErr = filter(ctx, *ba, br)
 		}
@@ -117,6 +120,28 @@ func (r *Replica) sendWithRangeID(
 	return br, pErr
 }
 
+const (
+	// requestCPUPerBatch, requestCPUPerRequest and requestCPUPerByte make up
+	// the cost model used by estimateRequestCPU, in nanoseconds of CPU time.
+	requestCPUPerBatch   = 20000
+	requestCPUPerRequest = 5000
+	requestCPUPerByte    = 5
+)
+
+// estimateRequestCPU returns an estimate of the CPU time, in nanoseconds, that
+// serving the given batch took. Measuring the CPU time of a request directly
+// isn't possible since requests don't run on dedicated goroutines, so this
+// instead charges a fixed cost for the batch and each of its requests, plus a
+// cost proportional to the size of the batch and its response. The estimate
+// is only meaningful relative to the estimates for other requests.
+func estimateRequestCPU(ba *roachpb.BatchRequest, br *roachpb.BatchResponse) float64 {
+	size := ba.Size()
+	if br != nil {
+		size += br.Size()
+	}
+	return float64(requestCPUPerBatch + requestCPUPerRequest*len(ba.Requests) + requestCPUPerByte*size)
+}
+
 // batchExecutionFn is a method on Replica that is able to execute a
 // BatchRequest. It is called with the batch, along with the status of
 // the lease that the batch is operating under and a guard for the
@@ -226,6 +251,7 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 			Timestamp:       ba.Timestamp,
 			Priority:        ba.UserPriority,
 			ReadConsistency: ba.ReadConsistency,
+			WaitPolicy:      ba.WaitPolicy,
 			Requests:        ba.Requests,
 			LatchSpans:      latchSpans,
 			LockSpans:       lockSpans,
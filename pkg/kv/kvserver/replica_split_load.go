@@ -34,6 +34,23 @@ var SplitByLoadQPSThreshold = settings.RegisterPublicIntSetting(
 	2500, // 2500 req/s
 )
 
+// SplitByLoadWriteBytesThreshold wraps
+// "kv.range_split.load_write_bytes_threshold".
+var SplitByLoadWriteBytesThreshold = settings.RegisterPublicByteSizeSetting(
+	"kv.range_split.load_write_bytes_threshold",
+	"the write bytes per second over which, the range becomes a candidate for load based splitting "+
+		"when load-based rebalancing balances write bytes",
+	16<<20, // 16 MiB/s
+)
+
+// SplitByLoadCPUThreshold wraps "kv.range_split.load_cpu_threshold".
+var SplitByLoadCPUThreshold = settings.RegisterPublicNonNegativeDurationSetting(
+	"kv.range_split.load_cpu_threshold",
+	"the estimated request CPU time per second over which, the range becomes a candidate for load "+
+		"based splitting when load-based rebalancing balances request CPU",
+	250*time.Millisecond,
+)
+
 // SplitByLoadMergeDelay wraps "kv.range_split.by_load_merge_delay".
 var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	"kv.range_split.by_load_merge_delay",
@@ -41,9 +58,22 @@ var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	5*time.Minute,
 )
 
-// SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
-func (r *Replica) SplitByLoadQPSThreshold() float64 {
-	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
+// SplitByLoadThreshold returns the load over which the replica becomes a
+// candidate for load based splitting, in the units of the load dimension
+// chosen by "kv.allocator.load_based_rebalancing.dimension".
+func (r *Replica) SplitByLoadThreshold() float64 {
+	return splitByLoadThreshold(&r.store.cfg.Settings.SV)
+}
+
+func splitByLoadThreshold(sv *settings.Values) float64 {
+	switch LBRebalancingDimension(LoadBasedRebalancingDimension.Get(sv)) {
+	case LBRebalancingWriteBytes:
+		return float64(SplitByLoadWriteBytesThreshold.Get(sv))
+	case LBRebalancingCPU:
+		return float64(SplitByLoadCPUThreshold.Get(sv))
+	default:
+		return float64(SplitByLoadQPSThreshold.Get(sv))
+	}
 }
 
 // SplitByLoadEnabled returns whether load based splitting is enabled.
@@ -62,10 +92,27 @@ func (r *Replica) recordBatchForLoadBasedSplitting(
 	if !r.SplitByLoadEnabled() {
 		return
 	}
-	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), r.splitLoad(ba), func() roachpb.Span {
 		return spans.BoundarySpan(spanset.SpanGlobal)
 	})
 	if shouldInitSplit {
 		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().Now())
 	}
 }
+
+// splitLoad returns the load that the batch contributes towards load based
+// splitting, along the load dimension chosen by
+// "kv.allocator.load_based_rebalancing.dimension".
+func (r *Replica) splitLoad(ba *roachpb.BatchRequest) int {
+	switch LBRebalancingDimension(LoadBasedRebalancingDimension.Get(&r.store.cfg.Settings.SV)) {
+	case LBRebalancingWriteBytes:
+		if !ba.IsWrite() {
+			return 0
+		}
+		return ba.Size()
+	case LBRebalancingCPU:
+		return int(estimateRequestCPU(ba, nil /* br */))
+	default:
+		return len(ba.Requests)
+	}
+}
@@ -961,16 +961,15 @@ func (rq *replicateQueue) findTargetAndTransferLease(
 		return false, nil
 	}
 
-	avgQPS, qpsMeasurementDur := repl.leaseholderStats.avgQPS()
-	if qpsMeasurementDur < MinStatsDuration {
-		avgQPS = 0
-	}
-	err := rq.transferLease(ctx, repl, target, avgQPS)
+	err := rq.transferLease(ctx, repl, target, rangeUsageInfoForRepl(repl))
 	return err == nil, err
 }
 
 func (rq *replicateQueue) transferLease(
-	ctx context.Context, repl *Replica, target roachpb.ReplicaDescriptor, rangeQPS float64,
+	ctx context.Context,
+	repl *Replica,
+	target roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
 ) error {
 	rq.metrics.TransferLeaseCount.Inc(1)
 	log.VEventf(ctx, 1, "transferring lease to s%d", target.StoreID)
@@ -979,7 +978,7 @@ func (rq *replicateQueue) transferLease(
 	}
 	rq.lastLeaseTransfer.Store(timeutil.Now())
 	rq.allocator.storePool.updateLocalStoresAfterLeaseTransfer(
-		repl.store.StoreID(), target.StoreID, rangeQPS)
+		repl.store.StoreID(), target.StoreID, rangeUsageInfo)
 	return nil
 }
 
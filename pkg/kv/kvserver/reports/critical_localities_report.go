@@ -12,6 +12,7 @@ package reports
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -391,6 +392,36 @@ func (v *criticalLocalitiesVisitor) countRange(
 	}
 }
 
+// CriticalLocalitiesForRange returns the localities, expanded to every tier
+// level, whose loss would make the given range unavailable. The replicas of
+// the range are assumed to be on the given stores, and nodeChecker determines
+// which nodes are live.
+func CriticalLocalitiesForRange(
+	ctx context.Context,
+	r *roachpb.RangeDescriptor,
+	nodeLocalities map[roachpb.NodeID]roachpb.Locality,
+	nodeChecker func(roachpb.NodeID) bool,
+	storeDescs []roachpb.StoreDescriptor,
+) []LocalityRepr {
+	allLocalities := expandLocalities(nodeLocalities)
+	dedupLocal := make(map[string]roachpb.Locality)
+	for _, rep := range r.Replicas().All() {
+		for s, loc := range allLocalities[rep.NodeID] {
+			dedupLocal[s] = loc
+		}
+	}
+	rep := make(LocalityReport)
+	for _, loc := range dedupLocal {
+		processLocalityForRange(ctx, r, ZoneKey{}, rep, loc, nodeChecker, storeDescs)
+	}
+	res := make([]LocalityRepr, 0, len(rep))
+	for k := range rep {
+		res = append(res, k.locality)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
 // processLocalityForRange checks a single locality constraint against a
 // range with replicas in each of the stores given, contributing to rep.
 func processLocalityForRange(
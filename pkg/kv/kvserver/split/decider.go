@@ -28,14 +28,17 @@ const minSplitSuggestionInterval = time.Minute
 // the resultant ranges.
 //
 // Operations should call `Record` with a current timestamp. Operation counts
-// are aggregated over a second and a qps computed. If the QPS is above threshold,
-// a split finder is instantiated and the spans supplied to Record are sampled
-// for a duration (on the order of ten seconds). Assuming that load consistently
-// remains over threshold, and the workload touches a diverse enough set of keys
-// to benefit from a split, sampling will eventually instruct a caller of Record
-// to carry out a split. When the split is initiated, it can obtain the suggested
-// split point from MaybeSplitKey (which may have disappeared either due to a drop
-// in qps or a change in the workload).
+// are aggregated over a second and a qps computed. Callers may also record a
+// weight other than the number of operations, such as the number of bytes
+// written, in which case the "qps" (and the threshold) are in that unit. If the
+// QPS is above threshold, a split finder is instantiated and the spans supplied
+// to Record are sampled for a duration (on the order of ten seconds). Assuming
+// that load consistently remains over threshold, and the workload touches a
+// diverse enough set of keys to benefit from a split, sampling will eventually
+// instruct a caller of Record to carry out a split. When the split is
+// initiated, it can obtain the suggested split point from MaybeSplitKey (which
+// may have disappeared either due to a drop in qps or a change in the
+// workload).
 type Decider struct {
 	intn         func(n int) int // supplied to Init
 	qpsThreshold func() float64  // supplied to Init
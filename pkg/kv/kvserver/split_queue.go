@@ -217,10 +217,12 @@ func (sq *splitQueue) processAttempt(
 		batchHandledQPS := r.QueriesPerSecond()
 		raftAppliedQPS := r.WritesPerSecond()
 		splitQPS := r.loadBasedSplitter.LastQPS(now)
+		splitDim := LBRebalancingDimension(LoadBasedRebalancingDimension.Get(&r.store.cfg.Settings.SV))
 		reason := fmt.Sprintf(
-			"load at key %s (%.2f splitQPS, %.2f batches/sec, %.2f raft mutations/sec)",
+			"load at key %s (%.2f %s split load, %.2f batches/sec, %.2f raft mutations/sec)",
 			splitByLoadKey,
 			splitQPS,
+			splitDim,
 			batchHandledQPS,
 			raftAppliedQPS,
 		)
@@ -36,8 +36,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/container"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/compactor"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/idalloc"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftentry"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/tscache"
@@ -408,6 +410,7 @@ type Store struct {
 	raftEntryCache     *raftentry.Cache
 	limiters           batcheval.Limiters
 	txnWaitMetrics     *txnwait.Metrics
+	contentionEvents   *concurrency.ContentionEventRegistry
 	sstSnapshotStorage SSTSnapshotStorage
 	protectedtsCache   protectedts.Cache
 
@@ -835,6 +838,7 @@ func NewStore(
 
 	s.txnWaitMetrics = txnwait.NewMetrics(cfg.HistogramWindowInterval)
 	s.metrics.registry.AddMetricStruct(s.txnWaitMetrics)
+	s.contentionEvents = concurrency.NewContentionEventRegistry()
 
 	s.compactor = compactor.NewCompactor(
 		s.cfg.Settings,
@@ -1555,6 +1559,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// Connect rangefeeds to closed timestamp updates.
 	s.startClosedTimestampRangefeedSubscriber(ctx)
 
+	// Bound the age of the data keys encrypting the store's sstables.
+	s.startEncryptionRewriter(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
@@ -2225,6 +2232,8 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalWriteBytesPerSecond float64
+	var totalCPUPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
@@ -2251,9 +2260,21 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var writeBytes float64
+		if wbps, dur := r.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			writeBytes = wbps
+			totalWriteBytesPerSecond += wbps
+		}
+		var cpu float64
+		if avgCPU, dur := r.requestCPUStats.avgQPS(); dur >= MinStatsDuration {
+			cpu = avgCPU
+			totalCPUPerSecond += avgCPU
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
-			repl: r,
-			qps:  qps,
+			repl:       r,
+			qps:        qps,
+			writeBytes: writeBytes,
+			cpu:        cpu,
 		})
 		return true
 	})
@@ -2262,6 +2283,8 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.WriteBytesPerSecond = totalWriteBytesPerSecond
+	capacity.CPUPerSecond = totalCPUPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2545,6 +2568,27 @@ func (s *Store) HottestReplicas() []HotReplicaInfo {
 	return hotRepls
 }
 
+// QueryLockTableState returns information about the locks held in the lock
+// tables of the store's replicas and the requests waiting on them. Only
+// leaseholder replicas maintain lock tables.
+func (s *Store) QueryLockTableState() []kvserverpb.LockStateInfo {
+	var infos []kvserverpb.LockStateInfo
+	newStoreReplicaVisitor(s).Visit(func(repl *Replica) bool {
+		for _, info := range repl.concMgr.QueryLockTableState() {
+			info.RangeID = repl.RangeID
+			infos = append(infos, info)
+		}
+		return true // continue.
+	})
+	return infos
+}
+
+// ContentionEvents returns the lock contention encountered by requests on
+// the store, aggregated per SQL table and index.
+func (s *Store) ContentionEvents() []kvserverpb.IndexContentionEvents {
+	return s.contentionEvents.Events()
+}
+
 // StoreKeySpanStats carries the result of a stats computation over a key range.
 type StoreKeySpanStats struct {
 	ReplicaCount         int
@@ -0,0 +1,72 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// MaxDataKeyAge bounds the age of the encryption-at-rest data keys protecting
+// the sstables of each store. Data keys are rotated periodically, but the
+// sstables written under an old key are otherwise only rewritten under the
+// active one as they happen to be compacted, which may never happen for cold
+// data.
+var MaxDataKeyAge = settings.RegisterPublicNonNegativeDurationSetting(
+	"kv.store.encryption.max_data_key_age",
+	"if nonzero, sstables encrypted at rest under data keys created longer ago than this "+
+		"are compacted to rewrite them under the active data key; this should exceed the "+
+		"data key rotation period of the stores",
+	0,
+)
+
+// encryptionRewriteInterval is the interval at which stores look for sstables
+// encrypted under data keys older than MaxDataKeyAge.
+const encryptionRewriteInterval = 10 * time.Minute
+
+// startEncryptionRewriter starts a goroutine which periodically rewrites the
+// sstables encrypted under data keys older than MaxDataKeyAge.
+func (s *Store) startEncryptionRewriter(ctx context.Context) {
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(encryptionRewriteInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.rewriteOldEncryptedSSTables(ctx)
+			case <-s.stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
+
+// rewriteOldEncryptedSSTables compacts the key spans of the sstables encrypted
+// under data keys older than MaxDataKeyAge, if set.
+func (s *Store) rewriteOldEncryptedSSTables(ctx context.Context) {
+	maxAge := MaxDataKeyAge.Get(&s.ClusterSettings().SV)
+	if maxAge == 0 {
+		return
+	}
+	count, err := s.engine.CompactEncryptedBefore(timeutil.Now().Add(-maxAge))
+	if err != nil {
+		log.Warningf(ctx, "failed to compact sstables encrypted under old data keys: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Infof(ctx, "compacted %d candidate sstables encrypted under data keys older than %s",
+			count, maxAge)
+	}
+}
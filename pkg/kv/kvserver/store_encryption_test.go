@@ -0,0 +1,55 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+// compactEncryptedEngine records the calls to CompactEncryptedBefore.
+type compactEncryptedEngine struct {
+	storage.Engine
+	createdBefore []time.Time
+}
+
+func (e *compactEncryptedEngine) CompactEncryptedBefore(createdBefore time.Time) (int, error) {
+	e.createdBefore = append(e.createdBefore, createdBefore)
+	return 1, nil
+}
+
+func TestRewriteOldEncryptedSSTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	eng := &compactEncryptedEngine{}
+	s := &Store{cfg: StoreConfig{Settings: st}, engine: eng}
+
+	// Nothing is compacted unless kv.store.encryption.max_data_key_age is set.
+	s.rewriteOldEncryptedSSTables(ctx)
+	require.Empty(t, eng.createdBefore)
+
+	MaxDataKeyAge.Override(&st.SV, time.Hour)
+	before := timeutil.Now()
+	s.rewriteOldEncryptedSSTables(ctx)
+	after := timeutil.Now()
+	require.Len(t, eng.createdBefore, 1)
+	require.False(t, eng.createdBefore[0].Before(before.Add(-time.Hour)))
+	require.False(t, eng.createdBefore[0].After(after.Add(-time.Hour)))
+}
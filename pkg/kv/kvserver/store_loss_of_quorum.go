@@ -0,0 +1,357 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/raft"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// RecoverLossOfQuorum applies a loss of quorum recovery update to the
+// survivor replica of a range on this store. The range descriptor is rewritten
+// in place so that the survivor is its only voter, after which the replica's
+// raft group is recreated and campaigns to become the leader. Once it holds
+// the lease, the range becomes available again and is up-replicated by the
+// replicate queue.
+//
+// The rewritten descriptor is not proposed through raft, so the raft state of
+// the replica is reset to match it: the log entries past the applied index are
+// discarded and the HardState is moved to a new term, committed up to the
+// applied index. The meta records addressing the range are rewritten
+// asynchronously, once the ranges holding them are available.
+//
+// The update is refused unless the replica is in the state observed when the
+// recovery plan was made: it must hold the same replica ID and descriptor
+// generation, and must not have regressed below the planned applied index.
+// The operator must also have confirmed that enough of the range's nodes are
+// decommissioned that the replicas on all other nodes, the survivor included,
+// cannot form a quorum of the old configuration. This is what makes keeping
+// the survivor's replica ID safe: node liveness alone cannot tell a lost node
+// from one which is partitioned away and may come back.
+//
+// This is an unsafe operation. Writes which were committed on the lost
+// replicas but not applied by the survivor are lost, and the range may end up
+// inconsistent with its neighbours if a split or merge was in flight.
+func (s *Store) RecoverLossOfQuorum(
+	ctx context.Context, update loqrecoverypb.ReplicaUpdate, decommissioned []roachpb.NodeID,
+) (*roachpb.RangeDescriptor, error) {
+	r, err := s.GetReplica(update.RangeID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = r.AnnotateCtx(ctx)
+
+	r.raftMu.Lock()
+	defer r.raftMu.Unlock()
+
+	r.mu.RLock()
+	desc := *r.mu.state.Desc
+	appliedIndex := r.mu.state.RaftAppliedIndex
+	lastIndex := r.mu.lastIndex
+	replicaID := r.mu.replicaID
+	r.mu.RUnlock()
+
+	if err := s.checkLossOfQuorumUpdate(
+		&desc, appliedIndex, replicaID, update, decommissioned,
+	); err != nil {
+		return nil, err
+	}
+
+	newDesc := desc
+	newDesc.SetReplicas(roachpb.MakeReplicaDescriptors([]roachpb.ReplicaDescriptor{{
+		NodeID:    s.Ident.NodeID,
+		StoreID:   s.StoreID(),
+		ReplicaID: replicaID,
+	}}))
+	newDesc.IncrementGeneration()
+
+	batch := s.Engine().NewBatch()
+	defer batch.Close()
+	var ms enginepb.MVCCStats
+	if err := writeLossOfQuorumDescriptor(ctx, batch, &ms, s.Clock().Now(), &newDesc); err != nil {
+		return nil, err
+	}
+	discarded, logBytes, err := r.discardUncommittedRaftLogRaftMuLocked(
+		ctx, batch, appliedIndex, lastIndex,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	newStats := *r.mu.state.Stats
+	newStats.Add(ms)
+	if err := r.raftMu.stateLoader.SetMVCCStats(ctx, batch, &newStats); err != nil {
+		return nil, errors.Wrap(err, "updating MVCCStats")
+	}
+	if err := batch.Commit(true /* sync */); err != nil {
+		return nil, err
+	}
+	*r.mu.state.Stats = newStats
+	r.setDescLockedRaftMuLocked(ctx, &newDesc)
+	r.mu.lastIndex = appliedIndex
+	r.mu.lastTerm = invalidLastTerm
+	r.mu.raftLogSize -= logBytes
+	if r.mu.raftLogSize < 0 {
+		r.mu.raftLogSize = 0
+	}
+	r.mu.raftLogSizeTrusted = false
+	r.store.raftEntryCache.Drop(r.RangeID)
+	// The payloads of the discarded entries can only be removed once the batch
+	// deleting the entries is committed.
+	for _, ent := range discarded {
+		if _, err := maybePurgeSideloaded(
+			ctx, r.raftMu.sideloaded, ent.Index, ent.Index, ent.Term,
+		); err != nil {
+			log.Warningf(ctx, "loss of quorum recovery: unable to purge sideloaded entry %d: %v",
+				ent.Index, err)
+		}
+	}
+
+	log.Warningf(ctx, "loss of quorum recovery: rewrote range descriptor %s -> %s, "+
+		"discarding %d uncommitted raft log entries", &desc, &newDesc, len(discarded))
+
+	// The commands proposed by this replica were not applied. They may have
+	// been committed by the lost replicas, so their outcome is ambiguous.
+	r.mu.proposalBuf.FlushLockedWithoutProposing()
+	for _, p := range r.mu.proposals {
+		r.cleanupFailedProposalLocked(p)
+		p.finishApplication(ctx, proposalResult{
+			Err: roachpb.NewError(roachpb.NewAmbiguousResultError("loss of quorum recovery")),
+		})
+	}
+
+	// The raft group caches the configuration it was created with. Drop it so
+	// that it is recreated from the new descriptor and campaign right away,
+	// which with a single voter elects this replica immediately.
+	r.mu.internalRaftGroup = nil
+	if err := r.withRaftGroupLocked(false /* mayCampaignOnWake */, func(
+		raftGroup *raft.RawNode,
+	) (bool, error) {
+		return true, raftGroup.Campaign()
+	}); err != nil {
+		return nil, err
+	}
+
+	metaCtx := s.AnnotateCtx(context.Background())
+	if err := s.stopper.RunAsyncTask(metaCtx, "kvserver: updating recovered range addressing",
+		func(ctx context.Context) {
+			s.updateLossOfQuorumRangeAddressing(ctx, &newDesc)
+		}); err != nil {
+		log.Warningf(ctx, "loss of quorum recovery: unable to update meta records of %s: %v",
+			&newDesc, err)
+	}
+	return &newDesc, nil
+}
+
+// discardUncommittedRaftLogRaftMuLocked deletes the raft log entries of the
+// replica past its applied index and resets its HardState accordingly. Some of
+// these entries may have been committed by the lost replicas, but they were
+// never applied by the survivor and must not be applied on top of the
+// rewritten descriptor: a pending replication change would reinstate the old
+// replicas, and other commands were proposed against the old configuration.
+//
+// The HardState is moved to the next term, without a vote, so that messages
+// still in flight from the old configuration are ignored. Its commit index is
+// lowered to the applied index, as entries past it no longer exist.
+//
+// It returns the discarded entries, without their data, along with the size
+// of their keys and values.
+func (r *Replica) discardUncommittedRaftLogRaftMuLocked(
+	ctx context.Context, batch storage.ReadWriter, appliedIndex, lastIndex uint64,
+) ([]raftpb.Entry, int64, error) {
+	var discarded []raftpb.Entry
+	var diff enginepb.MVCCStats
+	for i := appliedIndex + 1; i <= lastIndex; i++ {
+		key := r.raftMu.stateLoader.RaftLogKey(i)
+		var ent raftpb.Entry
+		ok, err := storage.MVCCGetProto(ctx, batch, key, hlc.Timestamp{}, &ent, storage.MVCCGetOptions{})
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "loading raft log entry %d", i)
+		}
+		if !ok {
+			continue
+		}
+		if err := storage.MVCCDelete(ctx, batch, &diff, key, hlc.Timestamp{}, nil /* txn */); err != nil {
+			return nil, 0, err
+		}
+		discarded = append(discarded, raftpb.Entry{Index: ent.Index, Term: ent.Term})
+	}
+
+	hs, err := r.raftMu.stateLoader.LoadHardState(ctx, batch)
+	if err != nil {
+		return nil, 0, err
+	}
+	hs.Term++
+	hs.Vote = 0
+	hs.Commit = appliedIndex
+	if err := r.raftMu.stateLoader.SetHardState(ctx, batch, hs); err != nil {
+		return nil, 0, errors.Wrap(err, "updating HardState")
+	}
+	return discarded, -diff.SysBytes, nil
+}
+
+// updateLossOfQuorumRangeAddressing rewrites the meta1 or meta2 records of a
+// range whose descriptor was rewritten by a loss of quorum recovery. They still
+// hold the descriptor from before the recovery, which lists the lost replicas.
+// The meta records may live on a range which is yet to be recovered, so this
+// retries until it succeeds, the descriptor of the range changes again, which
+// rewrites the meta records itself, or the store stops.
+func (s *Store) updateLossOfQuorumRangeAddressing(
+	ctx context.Context, desc *roachpb.RangeDescriptor,
+) {
+	opts := retry.Options{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Closer:         s.stopper.ShouldQuiesce(),
+	}
+	for re := retry.StartWithCtx(ctx, opts); re.Next(); {
+		err := s.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if _, _, err := conditionalGetDescValueFromDB(ctx, txn, desc.StartKey,
+				func(existing *roachpb.RangeDescriptor) bool {
+					return existing != nil && existing.Equal(desc)
+				}); err != nil {
+				return err
+			}
+			b := txn.NewBatch()
+			if err := updateRangeAddressing(b, desc); err != nil {
+				return err
+			}
+			return txn.Run(ctx, b)
+		})
+		if err == nil {
+			log.Infof(ctx, "loss of quorum recovery: updated meta records of %s", desc)
+			return
+		}
+		if errors.HasType(err, (*roachpb.ConditionFailedError)(nil)) {
+			log.Infof(ctx, "loss of quorum recovery: %s changed before its meta records were updated",
+				desc)
+			return
+		}
+		log.Warningf(ctx, "loss of quorum recovery: unable to update meta records of %s: %v",
+			desc, err)
+	}
+}
+
+// checkLossOfQuorumUpdate verifies that the replica is still in the state the
+// recovery plan was made for.
+func (s *Store) checkLossOfQuorumUpdate(
+	desc *roachpb.RangeDescriptor,
+	appliedIndex uint64,
+	replicaID roachpb.ReplicaID,
+	update loqrecoverypb.ReplicaUpdate,
+	decommissioned []roachpb.NodeID,
+) error {
+	if !desc.IsInitialized() {
+		return errors.Errorf("r%d is not initialized", desc.RangeID)
+	}
+	if !desc.StartKey.Equal(update.StartKey) {
+		return errors.Errorf("r%d start key %s does not match planned %s",
+			desc.RangeID, desc.StartKey, update.StartKey)
+	}
+	if update.Survivor.StoreID != s.StoreID() || update.Survivor.ReplicaID != replicaID {
+		return errors.Errorf("r%d: replica %d on s%d is not the planned survivor %s",
+			desc.RangeID, replicaID, s.StoreID(), update.Survivor)
+	}
+	if desc.Generation != update.DescGeneration {
+		return errors.Errorf("r%d descriptor generation %d does not match planned %d; "+
+			"the range changed since the plan was made",
+			desc.RangeID, desc.Generation, update.DescGeneration)
+	}
+	if appliedIndex < update.MinAppliedIndex {
+		return errors.Errorf("r%d applied index %d is below planned %d",
+			desc.RangeID, appliedIndex, update.MinAppliedIndex)
+	}
+	isDecommissioned := func(nodeID roachpb.NodeID) bool {
+		for _, id := range decommissioned {
+			if id == nodeID {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rDesc := range update.LostReplicas {
+		if !isDecommissioned(rDesc.NodeID) {
+			return errors.Errorf("r%d: n%d holding lost replica %s is not confirmed decommissioned",
+				desc.RangeID, rDesc.NodeID, rDesc)
+		}
+		if live, err := s.cfg.NodeLiveness.IsLive(rDesc.NodeID); err == nil && live {
+			return errors.Errorf("r%d: n%d holding lost replica %s is live",
+				desc.RangeID, rDesc.NodeID, rDesc)
+		}
+	}
+	// Count the replicas on nodes which are not decommissioned as able to
+	// vote, even if they are not live: they may still come back.
+	canMakeProgress := desc.Replicas().CanMakeProgress(func(rDesc roachpb.ReplicaDescriptor) bool {
+		return !isDecommissioned(rDesc.NodeID)
+	})
+	if canMakeProgress {
+		return errors.Errorf("r%d can make progress with the replicas of %s on nodes "+
+			"not confirmed decommissioned; refusing to recover", desc.RangeID, desc)
+	}
+	return nil
+}
+
+// writeLossOfQuorumDescriptor writes the rewritten range descriptor to its
+// range-local key. The meta copies live on other ranges, and are rewritten by
+// updateLossOfQuorumRangeAddressing.
+//
+// If a descriptor change was in flight when quorum was lost, its intent is
+// found on the descriptor key. Such transactions are anchored on that key, so
+// their record lives on this range and was not committed; the record is
+// deleted and the intent aborted before retrying.
+func writeLossOfQuorumDescriptor(
+	ctx context.Context,
+	batch storage.ReadWriter,
+	ms *enginepb.MVCCStats,
+	now hlc.Timestamp,
+	desc *roachpb.RangeDescriptor,
+) error {
+	key := keys.RangeDescriptorKey(desc.StartKey)
+	err := storage.MVCCPutProto(ctx, batch, ms, key, now, nil /* txn */, desc)
+	if wiErr := (*roachpb.WriteIntentError)(nil); errors.As(err, &wiErr) {
+		if len(wiErr.Intents) != 1 {
+			return errors.Errorf("expected 1 intent, found %d: %s", len(wiErr.Intents), wiErr)
+		}
+		intent := wiErr.Intents[0]
+		log.Warningf(ctx, "loss of quorum recovery: aborting txn %s to resolve intent on %s",
+			intent.Txn.ID, key)
+		txnKey := keys.TransactionKey(intent.Txn.Key, intent.Txn.ID)
+		if err := storage.MVCCDelete(ctx, batch, ms, txnKey, hlc.Timestamp{}, nil); err != nil {
+			return err
+		}
+		update := roachpb.LockUpdate{
+			Span:   roachpb.Span{Key: intent.Key},
+			Txn:    intent.Txn,
+			Status: roachpb.ABORTED,
+		}
+		if _, err := storage.MVCCResolveWriteIntent(ctx, batch, ms, update); err != nil {
+			return err
+		}
+		return storage.MVCCPutProto(ctx, batch, ms, key, now, nil /* txn */, desc)
+	}
+	return err
+}
@@ -370,6 +370,7 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		detail.desc.Capacity.RangeCount++
 		detail.desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
+		detail.desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.WriteBytesPerSecond
 	case roachpb.REMOVE_REPLICA:
 		detail.desc.Capacity.RangeCount--
 		if detail.desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
@@ -382,14 +383,20 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		} else {
 			detail.desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
+		if detail.desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.WriteBytesPerSecond {
+			detail.desc.Capacity.WriteBytesPerSecond = 0
+		} else {
+			detail.desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.WriteBytesPerSecond
+		}
 	}
 	sp.detailsMu.storeDetails[storeID] = &detail
 }
 
 // updateLocalStoresAfterLeaseTransfer is used to update the local copies of the
-// involved store descriptors immediately after a lease transfer.
+// involved store descriptors immediately after a lease transfer. Only the load
+// served by the leaseholder (QPS and request CPU) moves with the lease.
 func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
-	from roachpb.StoreID, to roachpb.StoreID, rangeQPS float64,
+	from roachpb.StoreID, to roachpb.StoreID, rangeUsageInfo RangeUsageInfo,
 ) {
 	sp.detailsMu.Lock()
 	defer sp.detailsMu.Unlock()
@@ -397,10 +404,15 @@ func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
 	fromDetail := *sp.getStoreDetailLocked(from)
 	if fromDetail.desc != nil {
 		fromDetail.desc.Capacity.LeaseCount--
-		if fromDetail.desc.Capacity.QueriesPerSecond < rangeQPS {
+		if fromDetail.desc.Capacity.QueriesPerSecond < rangeUsageInfo.QueriesPerSecond {
 			fromDetail.desc.Capacity.QueriesPerSecond = 0
 		} else {
-			fromDetail.desc.Capacity.QueriesPerSecond -= rangeQPS
+			fromDetail.desc.Capacity.QueriesPerSecond -= rangeUsageInfo.QueriesPerSecond
+		}
+		if fromDetail.desc.Capacity.CPUPerSecond < rangeUsageInfo.CPUPerSecond {
+			fromDetail.desc.Capacity.CPUPerSecond = 0
+		} else {
+			fromDetail.desc.Capacity.CPUPerSecond -= rangeUsageInfo.CPUPerSecond
 		}
 		sp.detailsMu.storeDetails[from] = &fromDetail
 	}
@@ -408,7 +420,8 @@ func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
 	toDetail := *sp.getStoreDetailLocked(to)
 	if toDetail.desc != nil {
 		toDetail.desc.Capacity.LeaseCount++
-		toDetail.desc.Capacity.QueriesPerSecond += rangeQPS
+		toDetail.desc.Capacity.QueriesPerSecond += rangeUsageInfo.QueriesPerSecond
+		toDetail.desc.Capacity.CPUPerSecond += rangeUsageInfo.CPUPerSecond
 		sp.detailsMu.storeDetails[to] = &toDetail
 	}
 }
@@ -566,6 +579,14 @@ type StoreList struct {
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
+
+	// candidateWriteBytesPerSecond tracks write-bytes-per-second stats for
+	// stores that are eligible to be rebalance targets.
+	candidateWriteBytesPerSecond stat
+
+	// candidateCPUPerSecond tracks estimated request CPU stats for stores that
+	// are eligible to be rebalance targets.
+	candidateCPUPerSecond stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -580,6 +601,8 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
+		sl.candidateWriteBytesPerSecond.update(desc.Capacity.WriteBytesPerSecond)
+		sl.candidateCPUPerSecond.update(desc.Capacity.CPUPerSecond)
 	}
 	return sl
 }
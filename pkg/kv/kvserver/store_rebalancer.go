@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"sort"
@@ -40,6 +41,14 @@ const (
 	// by less than this amount even if the amount is greater than the percentage
 	// threshold. This avoids too many lease transfers in lightly loaded clusters.
 	minQPSThresholdDifference = 100
+
+	// minWriteBytesThresholdDifference is the equivalent of
+	// minQPSThresholdDifference for write bytes per second.
+	minWriteBytesThresholdDifference = 1 << 20 // 1 MiB/s
+
+	// minCPUThresholdDifference is the equivalent of minQPSThresholdDifference
+	// for the estimated request CPU time, in nanoseconds per second.
+	minCPUThresholdDifference = 50 * time.Millisecond
 )
 
 var (
@@ -84,11 +93,26 @@ var LoadBasedRebalancingMode = settings.RegisterPublicEnumSetting(
 	},
 )
 
+// LoadBasedRebalancingDimension controls which measure of load is balanced
+// across stores by load-based rebalancing, and which measure of load is used
+// to find split points by load-based splitting.
+var LoadBasedRebalancingDimension = settings.RegisterPublicEnumSetting(
+	"kv.allocator.load_based_rebalancing.dimension",
+	"the load dimension which load-based rebalancing balances across stores and load-based splitting splits ranges on",
+	"qps",
+	map[int64]string{
+		int64(LBRebalancingQueries):    "qps",
+		int64(LBRebalancingWriteBytes): "write_bytes",
+		int64(LBRebalancingCPU):        "cpu",
+	},
+)
+
 // qpsRebalanceThreshold is much like rangeRebalanceThreshold, but for
 // QPS rather than range count. This should be set higher than
 // rangeRebalanceThreshold because QPS can naturally vary over time as
 // workloads change and clients come and go, so we need to be a little more
-// forgiving to avoid thrashing.
+// forgiving to avoid thrashing. It applies to whichever dimension is chosen
+// by LoadBasedRebalancingDimension.
 var qpsRebalanceThreshold = func() *settings.FloatSetting {
 	s := settings.RegisterNonNegativeFloatSetting(
 		"kv.allocator.qps_rebalance_threshold",
@@ -115,6 +139,110 @@ const (
 	LBRebalancingLeasesAndReplicas
 )
 
+// LBRebalancingDimension is the measure of load that store-level rebalancing
+// tries to balance across stores.
+type LBRebalancingDimension int64
+
+const (
+	// LBRebalancingQueries balances the number of BatchRequests served per
+	// second by leaseholders.
+	LBRebalancingQueries LBRebalancingDimension = iota
+	// LBRebalancingWriteBytes balances the number of bytes written per second
+	// by replicas. Since every replica of a range applies its writes, only
+	// replica rebalancing, and not lease transfers, moves this load.
+	LBRebalancingWriteBytes
+	// LBRebalancingCPU balances the estimated CPU time spent per second
+	// serving BatchRequests on leaseholders.
+	LBRebalancingCPU
+
+	numLBRebalancingDimensions = iota
+)
+
+// String returns the unit of the dimension, for use in log messages.
+func (d LBRebalancingDimension) String() string {
+	switch d {
+	case LBRebalancingQueries:
+		return "qps"
+	case LBRebalancingWriteBytes:
+		return "write bytes/s"
+	case LBRebalancingCPU:
+		return "cpu ns/s"
+	default:
+		panic(fmt.Sprintf("unknown load-based rebalancing dimension %d", int64(d)))
+	}
+}
+
+// movesWithLease returns whether the load measured by the dimension is moved
+// from one store to another by transferring a range's lease, as opposed to
+// only by moving its replicas.
+func (d LBRebalancingDimension) movesWithLease() bool {
+	return d != LBRebalancingWriteBytes
+}
+
+// storeLoad returns the load of a store along the dimension.
+func (d LBRebalancingDimension) storeLoad(c *roachpb.StoreCapacity) float64 {
+	switch d {
+	case LBRebalancingWriteBytes:
+		return c.WriteBytesPerSecond
+	case LBRebalancingCPU:
+		return c.CPUPerSecond
+	default:
+		return c.QueriesPerSecond
+	}
+}
+
+// addStoreLoad adds the given (possibly negative) load to a store along the
+// dimension.
+func (d LBRebalancingDimension) addStoreLoad(c *roachpb.StoreCapacity, load float64) {
+	switch d {
+	case LBRebalancingWriteBytes:
+		c.WriteBytesPerSecond += load
+	case LBRebalancingCPU:
+		c.CPUPerSecond += load
+	default:
+		c.QueriesPerSecond += load
+	}
+}
+
+// replicaLoad returns the load of a replica along the dimension.
+func (d LBRebalancingDimension) replicaLoad(r replicaWithStats) float64 {
+	switch d {
+	case LBRebalancingWriteBytes:
+		return r.writeBytes
+	case LBRebalancingCPU:
+		return r.cpu
+	default:
+		return r.qps
+	}
+}
+
+// candidateLoad returns the statistics of the load of the candidate stores in
+// the list along the dimension.
+func (d LBRebalancingDimension) candidateLoad(sl StoreList) stat {
+	switch d {
+	case LBRebalancingWriteBytes:
+		return sl.candidateWriteBytesPerSecond
+	case LBRebalancingCPU:
+		return sl.candidateCPUPerSecond
+	default:
+		return sl.candidateQueriesPerSecond
+	}
+}
+
+// minThresholdDifference returns the minimum difference from the cluster mean
+// that the store rebalancer cares about along the dimension. See
+// minQPSThresholdDifference.
+func (d LBRebalancingDimension) minThresholdDifference() float64 {
+	switch d {
+	case LBRebalancingWriteBytes:
+		return minWriteBytesThresholdDifference
+	case LBRebalancingCPU:
+		return float64(minCPUThresholdDifference)
+	default:
+		return minQPSThresholdDifference
+	}
+}
+
 // StoreRebalancer is responsible for examining how the associated store's load
 // compares to the load on other stores in the cluster and transferring leases
 // or replicas away if the local store is overloaded.
@@ -196,22 +324,22 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 				continue
 			}
 
+			dim := LBRebalancingDimension(LoadBasedRebalancingDimension.Get(&sr.st.SV))
 			storeList, _, _ := sr.rq.allocator.storePool.getStoreList(storeFilterNone)
-			sr.rebalanceStore(ctx, mode, storeList)
+			sr.rebalanceStore(ctx, mode, dim, storeList)
 		}
 	})
 }
 
 func (sr *StoreRebalancer) rebalanceStore(
-	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
+	ctx context.Context, mode LBRebalancingMode, dim LBRebalancingDimension, storeList StoreList,
 ) {
-	qpsThresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
+	thresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
 
-	// First check if we should transfer leases away to better balance QPS.
-	qpsMinThreshold := math.Min(storeList.candidateQueriesPerSecond.mean*(1-qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean-minQPSThresholdDifference)
-	qpsMaxThreshold := math.Max(storeList.candidateQueriesPerSecond.mean*(1+qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean+minQPSThresholdDifference)
+	// First check if we should transfer leases away to better balance load.
+	meanLoad := dim.candidateLoad(storeList).mean
+	minThreshold := math.Min(meanLoad*(1-thresholdFraction), meanLoad-dim.minThresholdDifference())
+	maxThreshold := math.Max(meanLoad*(1+thresholdFraction), meanLoad+dim.minThresholdDifference())
 
 	var localDesc *roachpb.StoreDescriptor
 	for i := range storeList.stores {
@@ -224,90 +352,106 @@ func (sr *StoreRebalancer) rebalanceStore(
 		return
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
-		log.VEventf(ctx, 1, "local QPS %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold, storeList.candidateQueriesPerSecond.mean)
+	if !(dim.storeLoad(&localDesc.Capacity) > maxThreshold) {
+		log.VEventf(ctx, 1, "local load %.2f %s is below max threshold %.2f (mean=%.2f); no rebalancing needed",
+			dim.storeLoad(&localDesc.Capacity), dim, maxThreshold, meanLoad)
 		return
 	}
 
 	var replicasToMaybeRebalance []replicaWithStats
 	storeMap := storeListToMap(storeList)
+	hottestRanges := sr.replRankings.topLoad(dim)
 
-	log.Infof(ctx,
-		"considering load-based lease transfers for s%d with %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
-
-	hottestRanges := sr.replRankings.topQPS()
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
-		replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, localDesc, storeList, storeMap, qpsMinThreshold, qpsMaxThreshold)
-		replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
-		if replWithStats.repl == nil {
-			break
-		}
+	// Transferring leases doesn't move load, such as write bytes, which is
+	// incurred by every replica of a range, so go straight to considering
+	// replica rebalances for such dimensions.
+	if dim.movesWithLease() {
+		log.Infof(ctx,
+			"considering load-based lease transfers for s%d with %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+			localDesc.StoreID, dim.storeLoad(&localDesc.Capacity), dim, meanLoad, maxThreshold)
+
+		for dim.storeLoad(&localDesc.Capacity) > maxThreshold {
+			replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
+				ctx, dim, &hottestRanges, localDesc, storeList, storeMap, minThreshold, maxThreshold)
+			replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
+			if replWithStats.repl == nil {
+				break
+			}
 
-		log.VEventf(ctx, 1, "transferring r%d (%.2f qps) to s%d to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, target.StoreID)
-		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
-		if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
-			return sr.rq.transferLease(ctx, replWithStats.repl, target, replWithStats.qps)
-		}); err != nil {
-			log.Errorf(ctx, "unable to transfer lease to s%d: %+v", target.StoreID, err)
-			continue
+			load := dim.replicaLoad(replWithStats)
+			log.VEventf(ctx, 1, "transferring r%d (%.2f %s) to s%d to better balance load",
+				replWithStats.repl.RangeID, load, dim, target.StoreID)
+			timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
+			if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
+				return sr.rq.transferLease(ctx, replWithStats.repl, target, RangeUsageInfo{
+					QueriesPerSecond: replWithStats.qps,
+					CPUPerSecond:     replWithStats.cpu,
+				})
+			}); err != nil {
+				log.Errorf(ctx, "unable to transfer lease to s%d: %+v", target.StoreID, err)
+				continue
+			}
+			sr.metrics.LeaseTransferCount.Inc(1)
+
+			// Finally, update our local copies of the descriptors so that if
+			// additional transfers are needed we'll be making the decisions with more
+			// up-to-date info. The StorePool copies are updated by transferLease.
+			localDesc.Capacity.LeaseCount--
+			dim.addStoreLoad(&localDesc.Capacity, -load)
+			if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
+				otherDesc.Capacity.LeaseCount++
+				dim.addStoreLoad(&otherDesc.Capacity, load)
+			}
 		}
-		sr.metrics.LeaseTransferCount.Inc(1)
 
-		// Finally, update our local copies of the descriptors so that if
-		// additional transfers are needed we'll be making the decisions with more
-		// up-to-date info. The StorePool copies are updated by transferLease.
-		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
-		if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
-			otherDesc.Capacity.LeaseCount++
-			otherDesc.Capacity.QueriesPerSecond += replWithStats.qps
+		if !(dim.storeLoad(&localDesc.Capacity) > maxThreshold) {
+			log.Infof(ctx,
+				"load-based lease transfers successfully brought s%d down to %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+				localDesc.StoreID, dim.storeLoad(&localDesc.Capacity), dim, meanLoad, maxThreshold)
+			return
 		}
-	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
+		if mode != LBRebalancingLeasesAndReplicas {
+			log.Infof(ctx,
+				"ran out of leases worth transferring and load (%.2f %s) is still above desired threshold (%.2f)",
+				dim.storeLoad(&localDesc.Capacity), dim, maxThreshold)
+			return
+		}
 		log.Infof(ctx,
-			"load-based lease transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-			localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
-		return
-	}
-
-	if mode != LBRebalancingLeasesAndReplicas {
+			"ran out of leases worth transferring and load (%.2f %s) is still above desired threshold (%.2f); considering load-based replica rebalances",
+			dim.storeLoad(&localDesc.Capacity), dim, maxThreshold)
+	} else if mode != LBRebalancingLeasesAndReplicas {
 		log.Infof(ctx,
-			"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f)",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+			"load (%.2f %s) is above desired threshold (%.2f) but can only be moved by replica rebalances, which are disabled",
+			dim.storeLoad(&localDesc.Capacity), dim, maxThreshold)
 		return
 	}
-	log.Infof(ctx,
-		"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
-		localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
 
 	// Re-combine replicasToMaybeRebalance with what remains of hottestRanges so
 	// that we'll reconsider them for replica rebalancing.
 	replicasToMaybeRebalance = append(replicasToMaybeRebalance, hottestRanges...)
 
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	for dim.storeLoad(&localDesc.Capacity) > maxThreshold {
 		replWithStats, targets := sr.chooseReplicaToRebalance(
 			ctx,
+			dim,
 			&replicasToMaybeRebalance,
 			localDesc,
 			storeList,
 			storeMap,
-			qpsMinThreshold,
-			qpsMaxThreshold)
+			minThreshold,
+			maxThreshold)
 		if replWithStats.repl == nil {
 			log.Infof(ctx,
-				"ran out of replicas worth transferring and qps (%.2f) is still above desired threshold (%.2f); will check again soon",
-				localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+				"ran out of replicas worth transferring and load (%.2f %s) is still above desired threshold (%.2f); will check again soon",
+				dim.storeLoad(&localDesc.Capacity), dim, maxThreshold)
 			return
 		}
 
+		load := dim.replicaLoad(replWithStats)
 		descBeforeRebalance := replWithStats.repl.Desc()
-		log.VEventf(ctx, 1, "rebalancing r%d (%.2f qps) from %v to %v to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, descBeforeRebalance.Replicas(), targets)
+		log.VEventf(ctx, 1, "rebalancing r%d (%.2f %s) from %v to %v to better balance load",
+			replWithStats.repl.RangeID, load, dim, descBeforeRebalance.Replicas(), targets)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "relocate range", timeout, func(ctx context.Context) error {
 			return sr.rq.store.AdminRelocateRange(ctx, *descBeforeRebalance, targets)
@@ -319,7 +463,9 @@ func (sr *StoreRebalancer) rebalanceStore(
 
 		// Finally, update our local copies of the descriptors so that if
 		// additional transfers are needed we'll be making the decisions with more
-		// up-to-date info.
+		// up-to-date info. Load which moves with the lease moves to the new
+		// leaseholder, while load incurred by every replica moves to each of the
+		// newly added replicas.
 		//
 		// TODO(a-robinson): This just updates the copies used locally by the
 		// storeRebalancer. We may also want to update the copies in the StorePool
@@ -331,33 +477,40 @@ func (sr *StoreRebalancer) rebalanceStore(
 			}
 		}
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		dim.addStoreLoad(&localDesc.Capacity, -load)
 		for i := range targets {
 			if storeDesc := storeMap[targets[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount++
 				if i == 0 {
 					storeDesc.Capacity.LeaseCount++
-					storeDesc.Capacity.QueriesPerSecond += replWithStats.qps
+				}
+				if dim.movesWithLease() {
+					if i == 0 {
+						dim.addStoreLoad(&storeDesc.Capacity, load)
+					}
+				} else if !storeHasReplica(targets[i].StoreID, replicasBeforeRebalance) {
+					dim.addStoreLoad(&storeDesc.Capacity, load)
 				}
 			}
 		}
 	}
 
 	log.Infof(ctx,
-		"load-based replica transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"load-based replica transfers successfully brought s%d down to %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+		localDesc.StoreID, dim.storeLoad(&localDesc.Capacity), dim, meanLoad, maxThreshold)
 }
 
 // TODO(a-robinson): Should we take the number of leases on each store into
 // account here or just continue to let that happen in allocator.go?
 func (sr *StoreRebalancer) chooseLeaseToTransfer(
 	ctx context.Context,
+	dim LBRebalancingDimension,
 	hottestRanges *[]replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, roachpb.ReplicaDescriptor, []replicaWithStats) {
 	var considerForRebalance []replicaWithStats
 	now := sr.rq.store.Clock().Now()
@@ -373,39 +526,40 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, dim, replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving leases whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra leases to spare anyway). It's
-		// just unnecessary churn with no benefit to move leases responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving leases whose load is below some small fraction of
+		// the store's load (unless the store has extra leases to spare anyway).
+		// It's just unnecessary churn with no benefit to move leases responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		load := dim.replicaLoad(replWithStats)
+		if load < dim.storeLoad(&localDesc.Capacity)*minLoadFraction &&
 			float64(localDesc.Capacity.LeaseCount) <= storeList.candidateLeases.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %.2f %s is too little to matter relative to s%d's %.2f total",
+				replWithStats.repl.RangeID, load, dim, localDesc.StoreID, dim.storeLoad(&localDesc.Capacity))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f %s",
+			desc.RangeID, load, dim)
 
-		// Check all the other replicas in order of increasing qps. Learner replicas
-		// aren't allowed to become the leaseholder or raft leader, so only consider
-		// the `Voters` replicas.
+		// Check all the other replicas in order of increasing load. Learner
+		// replicas aren't allowed to become the leaseholder or raft leader, so only
+		// consider the `Voters` replicas.
 		candidates := desc.Replicas().DeepCopy().Voters()
 		sort.Slice(candidates, func(i, j int) bool {
-			var iQPS, jQPS float64
+			var iLoad, jLoad float64
 			if desc := storeMap[candidates[i].StoreID]; desc != nil {
-				iQPS = desc.Capacity.QueriesPerSecond
+				iLoad = dim.storeLoad(&desc.Capacity)
 			}
 			if desc := storeMap[candidates[j].StoreID]; desc != nil {
-				jQPS = desc.Capacity.QueriesPerSecond
+				jLoad = dim.storeLoad(&desc.Capacity)
 			}
-			return iQPS < jQPS
+			return iLoad < jLoad
 		})
 
 		var raftStatus *raft.Status
@@ -416,8 +570,8 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, candidate.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := dim.candidateLoad(storeList).mean
+			if shouldNotMoveTo(ctx, dim, storeMap, replWithStats, candidate.StoreID, meanLoad, minLoad, maxLoad) {
 				continue
 			}
 
@@ -461,12 +615,13 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 
 func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	ctx context.Context,
+	dim LBRebalancingDimension,
 	hottestRanges *[]replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, []roachpb.ReplicationTarget) {
 	now := sr.rq.store.Clock().Now()
 	for {
@@ -480,25 +635,26 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			return replicaWithStats{}, nil
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, dim, replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving ranges whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra ranges to spare anyway). It's
-		// just unnecessary churn with no benefit to move ranges responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving ranges whose load is below some small fraction of
+		// the store's load (unless the store has extra ranges to spare anyway).
+		// It's just unnecessary churn with no benefit to move ranges responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		load := dim.replicaLoad(replWithStats)
+		if load < dim.storeLoad(&localDesc.Capacity)*minLoadFraction &&
 			float64(localDesc.Capacity.RangeCount) <= storeList.candidateRanges.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %.2f %s is too little to matter relative to s%d's %.2f total",
+				replWithStats.repl.RangeID, load, dim, localDesc.StoreID, dim.storeLoad(&localDesc.Capacity))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f %s",
+			desc.RangeID, load, dim)
 
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
 		desiredReplicas := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
@@ -507,7 +663,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		currentReplicas := desc.Replicas().All()
 
 		// Check the range's existing diversity score, since we want to ensure we
-		// don't hurt locality diversity just to improve QPS.
+		// don't hurt locality diversity just to improve load.
 		curDiversity := rangeDiversityScore(
 			sr.rq.allocator.storePool.getLocalities(currentReplicas))
 
@@ -516,11 +672,11 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			if currentReplicas[i].StoreID == localDesc.StoreID {
 				continue
 			}
-			// Keep the replica in the range if we don't know its QPS or if its QPS
+			// Keep the replica in the range if we don't know its load or if its load
 			// is below the upper threshold. Punishing stores not in our store map
 			// could cause mass evictions if the storePool gets out of sync.
 			storeDesc, ok := storeMap[currentReplicas[i].StoreID]
-			if !ok || storeDesc.Capacity.QueriesPerSecond < maxQPS {
+			if !ok || dim.storeLoad(&storeDesc.Capacity) < maxLoad {
 				targets = append(targets, roachpb.ReplicationTarget{
 					NodeID:  currentReplicas[i].NodeID,
 					StoreID: currentReplicas[i].StoreID,
@@ -534,7 +690,8 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 
 		// Then pick out which new stores to add the remaining replicas to.
 		options := sr.rq.allocator.scorerOptions()
-		options.qpsRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadDimension = dim
 		for len(targets) < desiredReplicas {
 			// Use the preexisting AllocateTarget logic to ensure that considerations
 			// such as zone constraints, locality diversity, and full disk come
@@ -552,8 +709,8 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				break
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, target.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := dim.candidateLoad(storeList).mean
+			if shouldNotMoveTo(ctx, dim, storeMap, replWithStats, target.StoreID, meanLoad, minLoad, maxLoad) {
 				break
 			}
 
@@ -572,7 +729,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		// TODO(a-robinson): Support more incremental improvements -- move what we
 		// can if it makes things better even if it isn't great. For example,
 		// moving one of the other existing replicas that's on a store with less
-		// qps than the max threshold but above the mean would help in certain
+		// load than the max threshold but above the mean would help in certain
 		// locality configurations.
 		if len(targets) < desiredReplicas {
 			log.VEventf(ctx, 3, "couldn't find enough rebalance targets for r%d (%d/%d)",
@@ -587,10 +744,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			continue
 		}
 
-		// Pick the replica with the least QPS to be leaseholder;
+		// Pick the replica with the least load to be leaseholder;
 		// RelocateRange transfers the lease to the first provided target.
 		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
+		newLeaseLoad := math.MaxFloat64
 		var raftStatus *raft.Status
 		for i := 0; i < len(targets); i++ {
 			// Ensure we don't transfer the lease to an existing replica that is behind
@@ -605,9 +762,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			}
 
 			storeDesc, ok := storeMap[targets[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			if ok && dim.storeLoad(&storeDesc.Capacity) < newLeaseLoad {
 				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+				newLeaseLoad = dim.storeLoad(&storeDesc.Capacity)
 			}
 		}
 		targets[0], targets[newLeaseIdx] = targets[newLeaseIdx], targets[0]
@@ -617,18 +774,20 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 
 func shouldNotMoveAway(
 	ctx context.Context,
+	dim LBRebalancingDimension,
 	replWithStats replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	now hlc.Timestamp,
-	minQPS float64,
+	minLoad float64,
 ) bool {
 	if !replWithStats.repl.OwnsValidLease(now) {
 		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.RangeID)
 		return true
 	}
-	if localDesc.Capacity.QueriesPerSecond-replWithStats.qps < minQPS {
-		log.VEventf(ctx, 3, "moving r%d's %.2f qps would bring s%d below the min threshold (%.2f)",
-			replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, minQPS)
+	load := dim.replicaLoad(replWithStats)
+	if dim.storeLoad(&localDesc.Capacity)-load < minLoad {
+		log.VEventf(ctx, 3, "moving r%d's %.2f %s would bring s%d below the min threshold (%.2f)",
+			replWithStats.repl.RangeID, load, dim, localDesc.StoreID, minLoad)
 		return true
 	}
 	return false
@@ -636,12 +795,13 @@ func shouldNotMoveAway(
 
 func shouldNotMoveTo(
 	ctx context.Context,
+	dim LBRebalancingDimension,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	replWithStats replicaWithStats,
 	candidateStore roachpb.StoreID,
-	meanQPS float64,
-	minQPS float64,
-	maxQPS float64,
+	meanLoad float64,
+	minLoad float64,
+	maxLoad float64,
 ) bool {
 	storeDesc, ok := storeMap[candidateStore]
 	if !ok {
@@ -649,18 +809,20 @@ func shouldNotMoveTo(
 		return true
 	}
 
-	newCandidateQPS := storeDesc.Capacity.QueriesPerSecond + replWithStats.qps
-	if storeDesc.Capacity.QueriesPerSecond < minQPS {
-		if newCandidateQPS > maxQPS {
+	load := dim.replicaLoad(replWithStats)
+	candidateLoad := dim.storeLoad(&storeDesc.Capacity)
+	newCandidateLoad := candidateLoad + load
+	if candidateLoad < minLoad {
+		if newCandidateLoad > maxLoad {
 			log.VEventf(ctx, 3,
-				"r%d's %.2f qps would push s%d over the max threshold (%.2f) with %.2f qps afterwards",
-				replWithStats.repl.RangeID, replWithStats.qps, candidateStore, maxQPS, newCandidateQPS)
+				"r%d's %.2f %s would push s%d over the max threshold (%.2f) with %.2f afterwards",
+				replWithStats.repl.RangeID, load, dim, candidateStore, maxLoad, newCandidateLoad)
 			return true
 		}
-	} else if newCandidateQPS > meanQPS {
+	} else if newCandidateLoad > meanLoad {
 		log.VEventf(ctx, 3,
-			"r%d's %.2f qps would push s%d over the mean (%.2f) with %.2f qps afterwards",
-			replWithStats.repl.RangeID, replWithStats.qps, candidateStore, meanQPS, newCandidateQPS)
+			"r%d's %.2f %s would push s%d over the mean (%.2f) with %.2f afterwards",
+			replWithStats.repl.RangeID, load, dim, candidateStore, meanLoad, newCandidateLoad)
 		return true
 	}
 
@@ -72,8 +72,9 @@ var (
 
 type testRange struct {
 	// The first storeID in the list will be the leaseholder.
-	storeIDs []roachpb.StoreID
-	qps      float64
+	storeIDs   []roachpb.StoreID
+	qps        float64
+	writeBytes float64
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
@@ -99,8 +100,9 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		repl.leaseholderStats = newReplicaStats(s.Clock(), nil)
 		repl.writeStats = newReplicaStats(s.Clock(), nil)
 		acc.addReplica(replicaWithStats{
-			repl: repl,
-			qps:  r.qps,
+			repl:       repl,
+			qps:        r.qps,
+			writeBytes: r.writeBytes,
 		})
 	}
 	rr.update(acc)
@@ -181,7 +183,7 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 		loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 		hottestRanges := rr.topQPS()
 		_, target, _ := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+			ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 		if target.StoreID != tc.expectTarget {
 			t.Errorf("got target store %d for range with replicas %v and %f qps; want %d",
 				target.StoreID, tc.storeIDs, tc.qps, tc.expectTarget)
@@ -266,7 +268,7 @@ func TestChooseReplicaToRebalance(t *testing.T) {
 			loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 			hottestRanges := rr.topQPS()
 			_, targets := sr.chooseReplicaToRebalance(
-				ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+				ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 
 			if len(targets) != len(tc.expectTargets) {
 				t.Fatalf("chooseReplicaToRebalance(existing=%v, qps=%f) got %v; want %v",
@@ -295,6 +297,105 @@ func TestChooseReplicaToRebalance(t *testing.T) {
 	}
 }
 
+func TestChooseReplicaToRebalanceByWriteBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// The stores have the same layout of write bytes as noLocalityStores has of
+	// QPS, but no QPS at all, so that replica rebalancing must use the write
+	// bytes to pick its targets.
+	var stores []*roachpb.StoreDescriptor
+	for _, desc := range noLocalityStores {
+		desc := *desc
+		desc.Capacity.WriteBytesPerSecond = desc.Capacity.QueriesPerSecond * (1 << 10)
+		desc.Capacity.QueriesPerSecond = 0
+		stores = append(stores, &desc)
+	}
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+	storeList, _, _ := a.storePool.getStoreList(storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	const minWriteBytes = 800 << 10
+	const maxWriteBytes = 1200 << 10
+
+	localDesc := *stores[0]
+	cfg := TestStoreConfig(nil)
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	rq := newReplicateQueue(s, g, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	testCases := []struct {
+		storeIDs      []roachpb.StoreID
+		writeBytes    float64
+		expectTargets []roachpb.StoreID // the first listed store is expected to be the leaseholder
+	}{
+		{[]roachpb.StoreID{1}, 100 << 10, []roachpb.StoreID{5}},
+		{[]roachpb.StoreID{1}, 700 << 10, []roachpb.StoreID{5}},
+		{[]roachpb.StoreID{1}, 800 << 10, nil},
+		{[]roachpb.StoreID{1, 3}, 100 << 10, []roachpb.StoreID{5, 3}},
+		{[]roachpb.StoreID{1, 3, 4}, 500 << 10, []roachpb.StoreID{5, 4, 3}},
+		{[]roachpb.StoreID{1, 4, 5}, 100 << 10, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			s.cfg.DefaultZoneConfig.NumReplicas = proto.Int32(int32(len(tc.storeIDs)))
+			loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, writeBytes: tc.writeBytes}})
+			hottestRanges := rr.topLoad(LBRebalancingWriteBytes)
+			_, targets := sr.chooseReplicaToRebalance(
+				ctx, LBRebalancingWriteBytes, &hottestRanges, &localDesc, storeList, storeMap,
+				minWriteBytes, maxWriteBytes)
+
+			if len(targets) != len(tc.expectTargets) {
+				t.Fatalf("chooseReplicaToRebalance(existing=%v, writeBytes=%f) got %v; want %v",
+					tc.storeIDs, tc.writeBytes, targets, tc.expectTargets)
+			}
+			if len(targets) == 0 {
+				return
+			}
+
+			if targets[0].StoreID != tc.expectTargets[0] {
+				t.Errorf("chooseReplicaToRebalance(existing=%v, writeBytes=%f) chose s%d as leaseholder; want s%v",
+					tc.storeIDs, tc.writeBytes, targets[0], tc.expectTargets[0])
+			}
+
+			targetStores := make([]roachpb.StoreID, len(targets))
+			for i, target := range targets {
+				targetStores[i] = target.StoreID
+			}
+			sort.Sort(roachpb.StoreIDSlice(targetStores))
+			sort.Sort(roachpb.StoreIDSlice(tc.expectTargets))
+			if !reflect.DeepEqual(targetStores, tc.expectTargets) {
+				t.Errorf("chooseReplicaToRebalance(existing=%v, writeBytes=%f) chose targets %v; want %v",
+					tc.storeIDs, tc.writeBytes, targetStores, tc.expectTargets)
+			}
+		})
+	}
+}
+
 func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -350,7 +451,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	}
 
 	_, target, _ := sr.chooseLeaseToTransfer(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTarget := roachpb.StoreID(4)
 	if target.StoreID != expectTarget {
 		t.Errorf("got target store s%d for range with RaftStatus %v; want s%d",
@@ -365,7 +466,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	repl = hottestRanges[0].repl
 
 	_, targets := sr.chooseReplicaToRebalance(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTargets := []roachpb.ReplicationTarget{
 		{NodeID: 4, StoreID: 4}, {NodeID: 5, StoreID: 5}, {NodeID: 3, StoreID: 3},
 	}
@@ -117,6 +117,13 @@ func ErrPriority(err error) ErrorPriority {
 		// ConditionFailedError to an error state. More specifically, we want to
 		// allow rollbacks to savepoint after a ConditionFailedError.
 		return ErrorScoreUnambiguousError
+	case *WriteIntentError:
+		// A WriteIntentError that made it back to the client was returned
+		// because of the request's wait policy. The request had no effect, so
+		// the transaction is free to continue or to roll back to a savepoint.
+		if v.Reason == WriteIntentError_REASON_WAIT_POLICY {
+			return ErrorScoreUnambiguousError
+		}
 	}
 	return ErrorScoreNonRetriable
 }
@@ -588,6 +595,13 @@ func (e *WriteIntentError) message(_ *Error) string {
 			buf.WriteString(end[i].Key.String())
 		}
 	}
+
+	switch e.Reason {
+	case WriteIntentError_REASON_UNSPECIFIED:
+		// Nothing to say.
+	default:
+		fmt.Fprintf(&buf, " [reason=%s]", e.Reason)
+	}
 	return buf.String()
 }
 
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
@@ -92,3 +93,21 @@ func TestReadWithinUncertaintyIntervalError(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteIntentErrorReason(t *testing.T) {
+	wiErr := &WriteIntentError{Intents: []Intent{MakeIntent(&enginepb.TxnMeta{}, Key("a"))}}
+	if exp, a := `conflicting intents on "a"`, wiErr.Error(); a != exp {
+		t.Fatalf("expected: %s\ngot: %s", exp, a)
+	}
+	if p := ErrPriority(wiErr); p != ErrorScoreNonRetriable {
+		t.Fatalf("expected priority %d, got %d", ErrorScoreNonRetriable, p)
+	}
+
+	wiErr.Reason = WriteIntentError_REASON_WAIT_POLICY
+	if exp, a := `conflicting intents on "a" [reason=REASON_WAIT_POLICY]`, wiErr.Error(); a != exp {
+		t.Fatalf("expected: %s\ngot: %s", exp, a)
+	}
+	if p := ErrPriority(wiErr); p != ErrorScoreUnambiguousError {
+		t.Fatalf("expected priority %d, got %d", ErrorScoreUnambiguousError, p)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// jsonWebKey is a public key in the JSON Web Key format (RFC 7517). Only RSA
+// and elliptic curve keys are supported.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Elliptic curve keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet is a set of public keys indexed by key ID.
+type keySet map[string]crypto.PublicKey
+
+// parseKeySet parses a JSON Web Key Set, as served by the jwks_uri of
+// identity providers. Keys that are not meant to be used for signatures are
+// skipped.
+func parseKeySet(s string) (keySet, error) {
+	keys := make(keySet)
+	if s == "" {
+		return keys, nil
+	}
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(s), &jwks); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON Web Key Set")
+	}
+	for i := range jwks.Keys {
+		k := &jwks.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if _, ok := keys[k.Kid]; ok {
+			return nil, errors.Newf("duplicate key ID %q in JSON Web Key Set", k.Kid)
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %q", k.Kid)
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// issuerKeySets holds the key set of each issuer, indexed by issuer.
+type issuerKeySets map[string]keySet
+
+// parseIssuerKeySets parses a JSON object mapping each issuer to its JSON Web
+// Key Set. Keeping the key sets apart ensures that a token can only be signed
+// by a key of its own issuer.
+func parseIssuerKeySets(s string) (issuerKeySets, error) {
+	sets := make(issuerKeySets)
+	if s == "" {
+		return sets, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON Web Key Sets")
+	}
+	// Sort the issuers so that errors are deterministic.
+	issuers := make([]string, 0, len(raw))
+	for iss := range raw {
+		issuers = append(issuers, iss)
+	}
+	sort.Strings(issuers)
+	for _, iss := range issuers {
+		keys, err := parseKeySet(string(raw[iss]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "issuer %q", iss)
+		}
+		sets[iss] = keys
+	}
+	return sets, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Newf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Newf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer. Padding is
+// tolerated even though RFC 7518 forbids it.
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
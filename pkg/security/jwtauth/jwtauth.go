@@ -0,0 +1,170 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jwtauth validates JSON Web Tokens issued by external identity
+// providers, and maps them to SQL users. It is used by the "jwt" HBA
+// authentication method and by the OpenID Connect login flow of the Admin UI.
+package jwtauth
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/errors"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Issuers is the cluster setting holding the comma-separated list of the
+// issuers whose tokens are accepted.
+var Issuers = settings.RegisterStringSetting(
+	"server.jwt_authentication.issuers",
+	"comma-separated list of the accepted values of the iss claim of JSON Web Tokens",
+	"",
+)
+
+// Audience is the cluster setting holding the expected audience of the
+// tokens used for SQL authentication.
+var Audience = settings.RegisterStringSetting(
+	"server.jwt_authentication.audience",
+	"the value that the aud claim of JSON Web Tokens used for SQL authentication must contain; "+
+		"if empty, the audience is not checked",
+	"",
+)
+
+// JWKS is the cluster setting holding the keys used to validate the
+// signature of the tokens, as a JSON object mapping each issuer to its JSON
+// Web Key Set.
+var JWKS = settings.RegisterValidatedStringSetting(
+	"server.jwt_authentication.jwks",
+	"JSON object mapping each issuer to the JSON Web Key Set holding the public keys "+
+		"used to verify the signature of its JSON Web Tokens",
+	"",
+	func(_ *settings.Values, s string) error {
+		_, err := parseIssuerKeySets(s)
+		return err
+	},
+)
+
+// Claim is the cluster setting holding the name of the claim mapped to SQL
+// users.
+var Claim = settings.RegisterStringSetting(
+	"server.jwt_authentication.claim",
+	"the claim of JSON Web Tokens that holds the name of the SQL user",
+	"sub",
+)
+
+// validMethods are the accepted signing algorithms. Symmetric algorithms are
+// excluded since the keys are public.
+var validMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Config holds the parameters used to validate tokens.
+type Config struct {
+	// Issuers are the accepted issuers. A token is rejected if its issuer
+	// isn't in the list.
+	Issuers []string
+	// Audience, if set, must be included in the audience of the token.
+	Audience string
+	// Claim is the name of the claim holding the user name.
+	Claim string
+
+	keySets issuerKeySets
+}
+
+// ConfigFromSettings returns the Config described by the
+// server.jwt_authentication cluster settings.
+func ConfigFromSettings(sv *settings.Values) (Config, error) {
+	keySets, err := parseIssuerKeySets(JWKS.Get(sv))
+	if err != nil {
+		return Config{}, err
+	}
+	var issuers []string
+	for _, iss := range strings.Split(Issuers.Get(sv), ",") {
+		if iss = strings.TrimSpace(iss); iss != "" {
+			issuers = append(issuers, iss)
+		}
+	}
+	return Config{
+		Issuers:  issuers,
+		Audience: Audience.Get(sv),
+		Claim:    Claim.Get(sv),
+		keySets:  keySets,
+	}, nil
+}
+
+// Validate verifies the signature and the claims of a token, and returns the
+// value of the claim holding the user name. The user name is returned as is;
+// callers are responsible for normalizing it.
+func (c Config) Validate(token string) (string, error) {
+	if len(c.Issuers) == 0 {
+		return "", errors.New("no JWT issuer is configured")
+	}
+	p := &jwt.Parser{ValidMethods: validMethods}
+	claims := jwt.MapClaims{}
+	if _, err := p.ParseWithClaims(token, claims, c.keyFunc); err != nil {
+		return "", errors.Wrap(err, "invalid token")
+	}
+	// MapClaims.Valid only checks the time-based claims.
+	if _, ok := claims["exp"]; !ok {
+		return "", errors.New("token has no expiration time")
+	}
+	if c.Audience != "" && !containsString(audience(claims), c.Audience) {
+		return "", errors.Newf("token audience does not contain %q", c.Audience)
+	}
+	user, _ := claims[c.Claim].(string)
+	if user == "" {
+		return "", errors.Newf("token has no %q claim", c.Claim)
+	}
+	return user, nil
+}
+
+// keyFunc returns the key used to verify the signature of a token. The key is
+// looked up in the key set of the issuer of the token only, so that an issuer
+// cannot sign tokens on behalf of another one.
+func (c Config) keyFunc(t *jwt.Token) (interface{}, error) {
+	claims, _ := t.Claims.(jwt.MapClaims)
+	iss, _ := claims["iss"].(string)
+	if !containsString(c.Issuers, iss) {
+		return nil, errors.Newf("token issuer %q is not accepted", iss)
+	}
+	kid, _ := t.Header["kid"].(string)
+	key, ok := c.keySets[iss][kid]
+	if !ok {
+		return nil, errors.Newf("unknown key ID %q for issuer %q", kid, iss)
+	}
+	return key, nil
+}
+
+// audience returns the values of the aud claim, which can be either a
+// string or an array of strings.
+func audience(claims jwt.MapClaims) []string {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		res := make([]string, 0, len(aud))
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jwtauth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth"
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth/jwtauthtest"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func setString(t *testing.T, st *cluster.Settings, key, value string) {
+	t.Helper()
+	if err := settings.NewUpdater(&st.SV).Set(key, value, "s"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	idp := jwtauthtest.NewProvider(t, "key1")
+	other := jwtauthtest.NewProvider(t, "key2")
+	other.Issuer = "https://other.example.com"
+
+	st := cluster.MakeTestingClusterSettings()
+	setString(t, st, "server.jwt_authentication.issuers", other.Issuer+", "+jwtauthtest.Issuer)
+	setString(t, st, "server.jwt_authentication.jwks", jwtauthtest.KeySets(t, idp, other))
+	setString(t, st, "server.jwt_authentication.audience", "cockroach")
+	cfg, err := jwtauth.ConfigFromSettings(&st.SV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hour := time.Hour.Seconds()
+	now := float64(time.Now().Unix())
+	testCases := []struct {
+		token    string
+		expected string
+		err      string
+	}{
+		{
+			token:    idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach"}),
+			expected: "carl",
+		},
+		{
+			token:    idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": []string{"x", "cockroach"}}),
+			expected: "carl",
+		},
+		{
+			token: idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "x"}),
+			err:   `token audience does not contain "cockroach"`,
+		},
+		{
+			token: idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach", "iss": "https://evil.example.com"}),
+			err:   `token issuer "https://evil.example.com" is not accepted`,
+		},
+		{
+			token: idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach", "exp": now - hour}),
+			err:   "Token is expired",
+		},
+		{
+			token: idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach", "nbf": now + hour}),
+			err:   "Token is not valid yet",
+		},
+		{
+			token: idp.Sign(t, jwt.MapClaims{"aud": "cockroach"}),
+			err:   `token has no "sub" claim`,
+		},
+		{
+			token:    other.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach"}),
+			expected: "carl",
+		},
+		{
+			// The key of an issuer cannot be used to sign tokens of another one.
+			token: other.Sign(t, jwt.MapClaims{
+				"sub": "carl", "aud": "cockroach", "iss": jwtauthtest.Issuer,
+			}),
+			err: `unknown key ID "key2" for issuer "https://idp.example.com"`,
+		},
+		{
+			// An HMAC token signed with the public key must be rejected.
+			token: func() string {
+				tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"sub": "carl", "aud": "cockroach", "iss": jwtauthtest.Issuer, "exp": now + hour,
+				})
+				tok.Header["kid"] = "key1"
+				s, err := tok.SignedString([]byte(idp.JWKS(t)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return s
+			}(),
+			err: "signing method HS256 is invalid",
+		},
+		{
+			token: "not a token",
+			err:   "invalid token",
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			user, err := cfg.Validate(tc.token)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if user != tc.expected {
+				t.Fatalf("expected user %q, got %q", tc.expected, user)
+			}
+		})
+	}
+
+	// No token is accepted when no issuer is configured.
+	cfg.Issuers = nil
+	if _, err := cfg.Validate(testCases[0].token); !testutils.IsError(err, "no JWT issuer is configured") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestECKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(
+		`{%q: {"keys": [{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q}]}}`,
+		jwtauthtest.Issuer, enc(key.X.Bytes()), enc(key.Y.Bytes()))
+
+	st := cluster.MakeTestingClusterSettings()
+	setString(t, st, "server.jwt_authentication.issuers", jwtauthtest.Issuer)
+	setString(t, st, "server.jwt_authentication.jwks", jwks)
+	setString(t, st, "server.jwt_authentication.claim", "email")
+	cfg, err := jwtauth.ConfigFromSettings(&st.SV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"email": "carl@example.com", "iss": jwtauthtest.Issuer, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	tok.Header["kid"] = "ec"
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user, err := cfg.Validate(s); err != nil || user != "carl@example.com" {
+		t.Fatalf("unexpected result: %q, %v", user, err)
+	}
+}
+
+func TestInvalidJWKS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	for _, tc := range []struct {
+		jwks string
+		err  string
+	}{
+		{`not json`, "invalid JSON Web Key Sets"},
+		{`{"keys": []}`, `issuer "keys": invalid JSON Web Key Set`},
+		{`{"i": {"keys": [{"kty": "oct", "kid": "a"}]}}`,
+			`issuer "i": invalid key "a": unsupported key type "oct"`},
+		{`{"i": {"keys": [{"kty": "RSA", "kid": "a", "e": "AQAB"}]}}`,
+			`issuer "i": invalid key "a": missing key parameter`},
+		{`{"i": {"keys": [{"kty": "EC", "kid": "a", "crv": "P-256", "x": "AQ", "y": "AQ"}]}}`,
+			`issuer "i": invalid key "a": point is not on the curve`},
+		{`{"i": {"keys": [{"kty": "RSA", "kid": "a", "n": "AQ", "e": "AQ"},
+			{"kty": "RSA", "kid": "a", "n": "AQ", "e": "AQ"}]}}`, `issuer "i": duplicate key ID "a"`},
+	} {
+		if err := jwtauth.JWKS.Validate(&st.SV, tc.jwks); !testutils.IsError(err, tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.jwks, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jwtauthtest provides an identity provider for tests, which signs
+// JSON Web Tokens with a locally generated key.
+package jwtauthtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Issuer is the default issuer of the tokens signed by a Provider.
+const Issuer = "https://idp.example.com"
+
+// Provider signs tokens with an RSA key.
+type Provider struct {
+	// Issuer is the issuer of the signed tokens.
+	Issuer string
+	KeyID  string
+	key    *rsa.PrivateKey
+}
+
+// NewProvider generates a new key and returns a Provider using it.
+func NewProvider(t testing.TB, keyID string) *Provider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Provider{Issuer: Issuer, KeyID: keyID, key: key}
+}
+
+// JWKS returns the JSON Web Key Set holding the public key of the provider.
+func (p *Provider) JWKS(t testing.TB) string {
+	enc := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	b, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   enc(p.key.N),
+			"e":   enc(big.NewInt(int64(p.key.E))),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// KeySets returns the value of the server.jwt_authentication.jwks cluster
+// setting holding the key sets of the given providers.
+func KeySets(t testing.TB, providers ...*Provider) string {
+	sets := make(map[string]json.RawMessage, len(providers))
+	for _, p := range providers {
+		sets[p.Issuer] = json.RawMessage(p.JWKS(t))
+	}
+	b, err := json.Marshal(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// Sign returns a token holding the given claims, signed with the key of the
+// provider. The iss and exp claims are filled in if absent.
+func (p *Provider) Sign(t testing.TB, claims jwt.MapClaims) string {
+	if _, ok := claims["iss"]; !ok {
+		claims["iss"] = p.Issuer
+	}
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = p.KeyID
+	s, err := tok.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -15,24 +15,30 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -48,6 +54,18 @@ const (
 	secretLength = 16
 	// SessionCookieName is the name of the cookie used for HTTP auth.
 	SessionCookieName = "session"
+
+	// oidcLoginPath and oidcCallbackPath are the endpoints of the OpenID
+	// Connect login flow.
+	oidcLoginPath    = "/oidc/v1/login"
+	oidcCallbackPath = "/oidc/v1/callback"
+	// oidcStateCookieName is the name of the cookie holding the state
+	// parameter of an ongoing OpenID Connect login. It protects the callback
+	// against cross-site request forgery.
+	oidcStateCookieName = "oidc_state"
+	// oidcStateTimeout is the time allowed to complete a login with the
+	// identity provider.
+	oidcStateTimeout = 10 * time.Minute
 )
 
 var webSessionTimeout = settings.RegisterPublicNonNegativeDurationSetting(
@@ -56,6 +74,45 @@ var webSessionTimeout = settings.RegisterPublicNonNegativeDurationSetting(
 	7*24*time.Hour,
 )
 
+var oidcEnabled = settings.RegisterBoolSetting(
+	"server.oidc_authentication.enabled",
+	"enables logging in to the Admin UI with an OpenID Connect identity provider",
+	false,
+)
+
+var oidcProviderURL = settings.RegisterStringSetting(
+	"server.oidc_authentication.provider_url",
+	"the issuer URL of the OpenID Connect identity provider; its configuration is "+
+		"discovered from <provider_url>/.well-known/openid-configuration",
+	"",
+)
+
+var oidcClientID = settings.RegisterStringSetting(
+	"server.oidc_authentication.client_id",
+	"the client ID of the Admin UI registered with the OpenID Connect identity provider",
+	"",
+)
+
+var oidcClientSecret = settings.RegisterStringSetting(
+	"server.oidc_authentication.client_secret",
+	"the client secret of the Admin UI registered with the OpenID Connect identity provider",
+	"",
+)
+
+var oidcRedirectURL = settings.RegisterStringSetting(
+	"server.oidc_authentication.redirect_url",
+	"the URL of the "+oidcCallbackPath+" endpoint of the Admin UI, as registered with the "+
+		"OpenID Connect identity provider",
+	"",
+)
+
+var oidcScopes = settings.RegisterStringSetting(
+	"server.oidc_authentication.scopes",
+	"space-separated list of the scopes requested from the OpenID Connect identity provider; "+
+		"must include openid",
+	"openid",
+)
+
 type authenticationServer struct {
 	server *Server
 }
@@ -346,6 +403,161 @@ RETURNING id
 	return id, secret, nil
 }
 
+// oidcConfig returns the OAuth 2.0 configuration of the OpenID Connect login
+// flow. The endpoints of the identity provider are discovered from its
+// configuration document.
+func (s *authenticationServer) oidcConfig(ctx context.Context) (*oauth2.Config, error) {
+	sv := &s.server.st.SV
+	if !oidcEnabled.Get(sv) {
+		return nil, errors.New("OIDC authentication is disabled")
+	}
+	providerURL := strings.TrimSuffix(oidcProviderURL.Get(sv), "/")
+	resp, err := httputil.Get(ctx, providerURL+"/.well-known/openid-configuration")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch the OIDC provider configuration")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Newf("unable to fetch the OIDC provider configuration: %s", resp.Status)
+	}
+	var discovery struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return nil, errors.Wrap(err, "invalid OIDC provider configuration")
+	}
+	return &oauth2.Config{
+		ClientID:     oidcClientID.Get(sv),
+		ClientSecret: oidcClientSecret.Get(sv),
+		RedirectURL:  oidcRedirectURL.Get(sv),
+		Scopes:       strings.Fields(oidcScopes.Get(sv)),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+// oidcLogin starts the OpenID Connect authorization code flow by redirecting
+// the browser to the identity provider, which redirects it back to
+// oidcCallback once the user is authenticated.
+func (s *authenticationServer) oidcLogin(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	conf, err := s.oidcConfig(ctx)
+	if err != nil {
+		log.Warningf(ctx, "OIDC login failed: %v", err)
+		http.Error(w, "OIDC login is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	state := make([]byte, secretLength)
+	if _, err := rand.Read(state); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encodedState := base64.RawURLEncoding.EncodeToString(state)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    encodedState,
+		Path:     oidcCallbackPath,
+		MaxAge:   int(oidcStateTimeout.Seconds()),
+		HttpOnly: true,
+		Secure:   !s.server.cfg.DisableTLSForHTTP,
+	})
+	http.Redirect(w, req, conf.AuthCodeURL(encodedState), http.StatusFound)
+}
+
+// oidcCallback completes the OpenID Connect login: the authorization code is
+// exchanged for an ID token, whose subject is mapped to a SQL user for which
+// a web session is created.
+func (s *authenticationServer) oidcCallback(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	username, err := s.oidcAuthenticate(ctx, req)
+	if err != nil {
+		log.Warningf(ctx, "OIDC login failed: %v", err)
+		http.Error(w, "OIDC login failed", http.StatusUnauthorized)
+		return
+	}
+
+	id, secret, err := s.newAuthSession(ctx, username)
+	if err != nil {
+		http.Error(w, apiInternalError(ctx, err).Error(), http.StatusInternalServerError)
+		return
+	}
+	cookie, err := EncodeSessionCookie(&serverpb.SessionCookie{
+		ID:     id,
+		Secret: secret,
+	}, !s.server.cfg.DisableTLSForHTTP)
+	if err != nil {
+		http.Error(w, apiInternalError(ctx, err).Error(), http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, cookie)
+	// The state cannot be used again.
+	http.SetCookie(w, &http.Cookie{
+		Name:   oidcStateCookieName,
+		Path:   oidcCallbackPath,
+		MaxAge: -1,
+	})
+	http.Redirect(w, req, "/", http.StatusFound)
+}
+
+// oidcAuthenticate validates the callback request of the identity provider
+// and returns the name of the authenticated SQL user.
+func (s *authenticationServer) oidcAuthenticate(
+	ctx context.Context, req *http.Request,
+) (string, error) {
+	conf, err := s.oidcConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	query := req.URL.Query()
+	if e := query.Get("error"); e != "" {
+		return "", errors.Newf("identity provider returned %q: %s", e, query.Get("error_description"))
+	}
+	stateCookie, err := req.Cookie(oidcStateCookieName)
+	if err != nil {
+		return "", errors.New("missing state cookie")
+	}
+	if subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(query.Get("state"))) != 1 {
+		return "", errors.New("state mismatch")
+	}
+
+	token, err := conf.Exchange(ctx, query.Get("code"))
+	if err != nil {
+		return "", errors.Wrap(err, "unable to exchange the authorization code")
+	}
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return "", errors.New("no ID token was returned by the identity provider")
+	}
+	// The ID token is validated with the keys and the claim mapping used for
+	// the JWT authentication of SQL clients, but its issuer must be the
+	// provider and its audience the client ID of the Admin UI.
+	jwtConf, err := jwtauth.ConfigFromSettings(&s.server.st.SV)
+	if err != nil {
+		return "", err
+	}
+	jwtConf.Issuers = []string{oidcProviderURL.Get(&s.server.st.SV)}
+	jwtConf.Audience = conf.ClientID
+	tokenUser, err := jwtConf.Validate(idToken)
+	if err != nil {
+		return "", err
+	}
+	username := tree.Name(tokenUser).Normalize()
+
+	exists, canLogin, _, _, err := sql.GetUserHashedPassword(
+		ctx, s.server.sqlServer.execCfg.InternalExecutor, username,
+	)
+	if err != nil {
+		return "", err
+	}
+	if !exists || !canLogin {
+		return "", errors.Newf("user %q does not exist or cannot log in", username)
+	}
+	return username, nil
+}
+
 // authenticationMux implements http.Handler, and is used to provide session
 // authentication for an arbitrary "inner" handler.
 type authenticationMux struct {
@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth/jwtauthtest"
 	"github.com/cockroachdb/cockroach/pkg/server/debug"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -42,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
@@ -800,3 +803,140 @@ func TestGRPCAuthentication(t *testing.T) {
 		})
 	}
 }
+
+func TestOIDCLogin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+	ts := s.(*TestServer)
+
+	const clientID = "cockroach-ui"
+	provider := jwtauthtest.NewProvider(t, "key1")
+
+	// Serve a minimal identity provider, whose token endpoint returns an ID
+	// token for the user carl.
+	mux := http.NewServeMux()
+	idp := httptest.NewServer(mux)
+	defer idp.Close()
+	provider.Issuer = idp.URL
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer": %q, "authorization_endpoint": %q, "token_endpoint": %q}`,
+			idp.URL, idp.URL+"/authorize", idp.URL+"/token")
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if code := r.FormValue("code"); code != "abc" {
+			http.Error(w, "invalid code "+code, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "x", "token_type": "Bearer", "id_token": %q}`,
+			provider.Sign(t, jwt.MapClaims{"sub": "Carl", "aud": clientID}))
+	})
+
+	for _, stmt := range []string{
+		`CREATE USER carl`,
+		fmt.Sprintf(`SET CLUSTER SETTING server.oidc_authentication.provider_url = '%s'`, idp.URL),
+		fmt.Sprintf(`SET CLUSTER SETTING server.oidc_authentication.client_id = '%s'`, clientID),
+		fmt.Sprintf(`SET CLUSTER SETTING server.oidc_authentication.redirect_url = '%s'`,
+			ts.AdminURL()+oidcCallbackPath),
+		fmt.Sprintf(`SET CLUSTER SETTING server.jwt_authentication.jwks = '%s'`,
+			jwtauthtest.KeySets(t, provider)),
+		`SET CLUSTER SETTING server.oidc_authentication.enabled = true`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	httpClient, err := ts.GetHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	// The login endpoint redirects to the identity provider, and sets the
+	// state cookie.
+	var stateCookie *http.Cookie
+	var state string
+	testutils.SucceedsSoon(t, func() error {
+		resp, err := httpClient.Get(ts.AdminURL() + oidcLoginPath)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusFound {
+			return errors.Errorf("unexpected status %s", resp.Status)
+		}
+		location, err := resp.Location()
+		if err != nil {
+			return err
+		}
+		if a, e := location.Path, "/authorize"; a != e {
+			return errors.Errorf("redirected to %s, wanted %s", a, e)
+		}
+		if a, e := location.Query().Get("client_id"), clientID; a != e {
+			return errors.Errorf("got client ID %s, wanted %s", a, e)
+		}
+		state = location.Query().Get("state")
+		for _, c := range resp.Cookies() {
+			if c.Name == oidcStateCookieName {
+				stateCookie = c
+			}
+		}
+		if stateCookie == nil {
+			return errors.New("no state cookie was set")
+		}
+		return nil
+	})
+
+	callback := func(state, code string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?state=%s&code=%s",
+			ts.AdminURL(), oidcCallbackPath, url.QueryEscape(state), url.QueryEscape(code)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.AddCookie(stateCookie)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// A state mismatch or an invalid code is rejected.
+	if resp := callback("wrong", "abc"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 on state mismatch, got %s", resp.Status)
+	}
+	if resp := callback(state, "wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 on invalid code, got %s", resp.Status)
+	}
+
+	// A successful login creates a session for carl.
+	resp := callback(state, "abc")
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected status 302, got %s", resp.Status)
+	}
+	var sessionCookie *serverpb.SessionCookie
+	for _, c := range resp.Cookies() {
+		if c.Name == SessionCookieName {
+			if sessionCookie, err = decodeSessionCookie(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if sessionCookie == nil {
+		t.Fatalf("no session cookie was set: %v", resp.Cookies())
+	}
+	var username string
+	if err := db.QueryRow(
+		`SELECT username FROM system.web_sessions WHERE id = $1`, sessionCookie.ID,
+	).Scan(&username); err != nil {
+		t.Fatal(err)
+	}
+	if username != "carl" {
+		t.Fatalf("session created for user %s, wanted carl", username)
+	}
+}
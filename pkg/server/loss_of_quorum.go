@@ -0,0 +1,292 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The loss of quorum recovery RPCs must work while some ranges, possibly
+// including system ranges, are unavailable. They therefore find the nodes of
+// the cluster through gossip rather than through the node status and liveness
+// records, and only ever read replica state from the local stores.
+
+// RecoveryCollectReplicaInfo implements the serverpb.AdminServer interface.
+func (s *adminServer) RecoveryCollectReplicaInfo(
+	ctx context.Context, req *serverpb.RecoveryCollectReplicaInfoRequest,
+) (*serverpb.RecoveryCollectReplicaInfoResponse, error) {
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if req.Local {
+		info, err := loqrecovery.CollectReplicaInfo(ctx, s.server.node.stores)
+		if err != nil {
+			return nil, err
+		}
+		return &serverpb.RecoveryCollectReplicaInfoResponse{
+			Info: loqrecoverypb.ClusterReplicaInfo{
+				Replicas:         info.Replicas,
+				ReportingNodeIDs: []roachpb.NodeID{s.server.NodeID()},
+			},
+		}, nil
+	}
+
+	nodes, err := s.recoveryNodeDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	response := &serverpb.RecoveryCollectReplicaInfoResponse{}
+	response.Info.Nodes = nodes
+
+	type nodeResponse struct {
+		nodeID roachpb.NodeID
+		resp   *serverpb.RecoveryCollectReplicaInfoResponse
+		err    error
+	}
+	responses := make(chan nodeResponse, len(nodes))
+	for _, node := range nodes {
+		nodeID := node.NodeID // avoid data race
+		if err := s.server.stopper.RunAsyncTask(
+			ctx, "server.adminServer: collecting replica info",
+			func(ctx context.Context) {
+				var resp *serverpb.RecoveryCollectReplicaInfoResponse
+				err := contextutil.RunWithTimeout(ctx, "collect replica info", 5*base.NetworkTimeout,
+					func(ctx context.Context) error {
+						client, err := s.dialAdminNode(ctx, nodeID)
+						if err != nil {
+							return err
+						}
+						resp, err = client.RecoveryCollectReplicaInfo(ctx,
+							&serverpb.RecoveryCollectReplicaInfoRequest{Local: true})
+						return err
+					})
+				responses <- nodeResponse{nodeID: nodeID, resp: resp, err: err}
+			}); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	for remainingResponses := len(nodes); remainingResponses > 0; remainingResponses-- {
+		select {
+		case resp := <-responses:
+			if resp.err != nil {
+				response.Errors = append(response.Errors, serverpb.RecoveryNodeError{
+					NodeID: resp.nodeID, Message: resp.err.Error(),
+				})
+				continue
+			}
+			response.Info.Replicas = append(response.Info.Replicas, resp.resp.Info.Replicas...)
+			response.Info.ReportingNodeIDs = append(response.Info.ReportingNodeIDs, resp.nodeID)
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.DeadlineExceeded, "request of replica info canceled")
+		}
+	}
+
+	sort.Slice(response.Info.ReportingNodeIDs, func(i, j int) bool {
+		return response.Info.ReportingNodeIDs[i] < response.Info.ReportingNodeIDs[j]
+	})
+	sort.Slice(response.Errors, func(i, j int) bool {
+		return response.Errors[i].NodeID < response.Errors[j].NodeID
+	})
+	return response, nil
+}
+
+// RecoveryApplyPlan implements the serverpb.AdminServer interface.
+func (s *adminServer) RecoveryApplyPlan(
+	ctx context.Context, req *serverpb.RecoveryApplyPlanRequest,
+) (*serverpb.RecoveryApplyPlanResponse, error) {
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if req.Local {
+		response := &serverpb.RecoveryApplyPlanResponse{}
+		for _, update := range req.Plan.Updates {
+			result := loqrecoverypb.ReplicaUpdateResult{
+				RangeID: update.RangeID,
+				NodeID:  s.server.NodeID(),
+				StoreID: update.Survivor.StoreID,
+			}
+			if err := s.applyRecoveryUpdate(ctx, update, req.Plan.DecommissionedNodeIDs); err != nil {
+				log.Errorf(ctx, "loss of quorum recovery of r%d failed: %v", update.RangeID, err)
+				result.Error = err.Error()
+			}
+			response.Results = append(response.Results, result)
+		}
+		return response, nil
+	}
+
+	// Route each update to the node holding its survivor.
+	plans := make(map[roachpb.NodeID]*loqrecoverypb.ReplicaUpdatePlan)
+	for _, update := range req.Plan.Updates {
+		nodeID := update.Survivor.NodeID
+		if plans[nodeID] == nil {
+			plans[nodeID] = &loqrecoverypb.ReplicaUpdatePlan{
+				DecommissionedNodeIDs: req.Plan.DecommissionedNodeIDs,
+			}
+		}
+		plans[nodeID].Updates = append(plans[nodeID].Updates, update)
+	}
+
+	type nodeResponse struct {
+		nodeID roachpb.NodeID
+		resp   *serverpb.RecoveryApplyPlanResponse
+		err    error
+	}
+	response := &serverpb.RecoveryApplyPlanResponse{}
+	responses := make(chan nodeResponse, len(plans))
+	for nodeID, plan := range plans {
+		nodeID, plan := nodeID, plan // avoid data race
+		if err := s.server.stopper.RunAsyncTask(
+			ctx, "server.adminServer: applying recovery plan",
+			func(ctx context.Context) {
+				var resp *serverpb.RecoveryApplyPlanResponse
+				err := contextutil.RunWithTimeout(ctx, "apply recovery plan", 5*base.NetworkTimeout,
+					func(ctx context.Context) error {
+						client, err := s.dialAdminNode(ctx, nodeID)
+						if err != nil {
+							return err
+						}
+						resp, err = client.RecoveryApplyPlan(ctx,
+							&serverpb.RecoveryApplyPlanRequest{Plan: *plan, Local: true})
+						return err
+					})
+				responses <- nodeResponse{nodeID: nodeID, resp: resp, err: err}
+			}); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	for remainingResponses := len(plans); remainingResponses > 0; remainingResponses-- {
+		select {
+		case resp := <-responses:
+			if resp.err != nil {
+				response.Errors = append(response.Errors, serverpb.RecoveryNodeError{
+					NodeID: resp.nodeID, Message: resp.err.Error(),
+				})
+				continue
+			}
+			response.Results = append(response.Results, resp.resp.Results...)
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.DeadlineExceeded, "application of recovery plan canceled")
+		}
+	}
+
+	sort.Slice(response.Results, func(i, j int) bool {
+		return response.Results[i].RangeID < response.Results[j].RangeID
+	})
+	sort.Slice(response.Errors, func(i, j int) bool {
+		return response.Errors[i].NodeID < response.Errors[j].NodeID
+	})
+	return response, nil
+}
+
+// applyRecoveryUpdate applies a single loss of quorum recovery update on the
+// local store holding the survivor and records it in the event log.
+func (s *adminServer) applyRecoveryUpdate(
+	ctx context.Context, update loqrecoverypb.ReplicaUpdate, decommissioned []roachpb.NodeID,
+) error {
+	store, err := s.server.node.stores.GetStore(update.Survivor.StoreID)
+	if err != nil {
+		return err
+	}
+	newDesc, err := store.RecoverLossOfQuorum(ctx, update, decommissioned)
+	if err != nil {
+		return err
+	}
+
+	// The event log may itself live on a range which is yet to be recovered,
+	// so the event is recorded asynchronously and on a best effort basis. The
+	// recovery is always logged by the store.
+	info := struct {
+		Descriptor   string
+		LostReplicas []roachpb.ReplicaDescriptor
+	}{
+		Descriptor:   newDesc.String(),
+		LostReplicas: update.LostReplicas,
+	}
+	eventLogger := sql.MakeEventLogger(s.server.sqlServer.execCfg)
+	nodeID := s.server.NodeID()
+	eventCtx := s.server.AnnotateCtx(context.Background())
+	if err := s.server.stopper.RunAsyncTask(eventCtx, "server.adminServer: recording recovery",
+		func(ctx context.Context) {
+			if err := s.server.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+				return eventLogger.InsertEventRecord(
+					ctx, txn, sql.EventLogLossOfQuorumRecovery, int32(update.RangeID), int32(nodeID), info,
+				)
+			}); err != nil {
+				log.Errorf(ctx, "unable to record %s event for r%d: %s",
+					sql.EventLogLossOfQuorumRecovery, update.RangeID, err)
+			}
+		}); err != nil {
+		log.Errorf(ctx, "unable to record %s event for r%d: %s",
+			sql.EventLogLossOfQuorumRecovery, update.RangeID, err)
+	}
+	return nil
+}
+
+// recoveryNodeDescriptors returns the descriptors of all nodes known to
+// gossip, sorted by node ID.
+func (s *adminServer) recoveryNodeDescriptors() ([]roachpb.NodeDescriptor, error) {
+	var nodes []roachpb.NodeDescriptor
+	err := s.server.gossip.IterateInfos(gossip.KeyNodeIDPrefix, func(key string, i gossip.Info) error {
+		bytes, err := i.Value.GetBytes()
+		if err != nil {
+			return errors.NewAssertionErrorWithWrappedErrf(err,
+				"failed to extract bytes for key %q", key)
+		}
+		var d roachpb.NodeDescriptor
+		if err := protoutil.Unmarshal(bytes, &d); err != nil {
+			return errors.NewAssertionErrorWithWrappedErrf(err,
+				"failed to parse value for key %q", key)
+		}
+		// Node descriptors with NodeID 0 indicate removed nodes.
+		if d.NodeID != 0 {
+			nodes = append(nodes, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeID < nodes[j].NodeID })
+	return nodes, nil
+}
+
+// dialAdminNode returns an AdminClient for the given node.
+func (s *adminServer) dialAdminNode(
+	ctx context.Context, nodeID roachpb.NodeID,
+) (serverpb.AdminClient, error) {
+	addr, err := s.server.gossip.GetNodeIDAddress(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := s.server.rpcContext.GRPCDialNode(addr.String(), nodeID,
+		rpc.DefaultClass).Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return serverpb.NewAdminClient(conn), nil
+}
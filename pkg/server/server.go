@@ -534,7 +534,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		Storage:  protectedtsProvider,
 		Cache:    protectedtsProvider,
 		StatusFuncs: ptreconcile.StatusFuncs{
-			jobsprotectedts.MetaType: jobsprotectedts.MakeStatusFunc(jobRegistry),
+			jobsprotectedts.MetaType:    jobsprotectedts.MakeStatusFunc(jobRegistry),
+			sql.ReplicationSlotMetaType: sql.MakeReplicationSlotStatusFunc(internalExecutor),
 		},
 	})
 	registry.AddMetricStruct(protectedtsReconciler.Metrics())
@@ -1571,6 +1572,9 @@ func (s *Server) Start(ctx context.Context) error {
 	// The /login endpoint is, by definition, available pre-authentication.
 	s.mux.Handle(loginPath, gwMux)
 	s.mux.Handle(logoutPath, authHandler)
+	// So are the endpoints of the OpenID Connect login flow.
+	s.mux.Handle(oidcLoginPath, http.HandlerFunc(s.authentication.oidcLogin))
+	s.mux.Handle(oidcCallbackPath, http.HandlerFunc(s.authentication.oidcCallback))
 
 	// The /_status/vars endpoint is not authenticated either. Useful for monitoring.
 	s.mux.Handle(statusVars, http.HandlerFunc(s.status.handleVars))
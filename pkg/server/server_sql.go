@@ -82,6 +82,7 @@ type sqlServer struct {
 	// sqlMemMetrics are used to track memory usage of sql sessions.
 	sqlMemMetrics           sql.MemoryMetrics
 	stmtDiagnosticsRegistry *stmtdiagnostics.Registry
+	notificationRegistry    *sql.NotificationRegistry
 }
 
 // sqlServerOptionalArgs are the arguments supplied to newSQLServer which
@@ -539,6 +540,14 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		cfg.Settings,
 	)
 	execCfg.StmtDiagnosticsRecorder = stmtDiagnosticsRegistry
+	notificationRegistry := sql.NewNotificationRegistry(
+		cfg.Settings,
+		codec,
+		cfg.distSender,
+		cfg.clock,
+		cfg.circularInternalExecutor,
+	)
+	execCfg.NotificationRegistry = notificationRegistry
 
 	temporaryObjectCleaner := sql.NewTemporaryObjectCleaner(
 		cfg.Settings,
@@ -566,6 +575,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		adminMemMetrics:         adminMemMetrics,
 		sqlMemMetrics:           sqlMemMetrics,
 		stmtDiagnosticsRegistry: stmtDiagnosticsRegistry,
+		notificationRegistry:    notificationRegistry,
 	}, nil
 }
 
@@ -585,6 +595,7 @@ func (s *sqlServer) start(
 		return err
 	}
 	s.stmtDiagnosticsRegistry.Start(ctx, stopper)
+	s.notificationRegistry.Start(ctx, stopper)
 
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
@@ -1541,6 +1541,156 @@ func (s *statusServer) localHotRanges(ctx context.Context) serverpb.HotRangesRes
 	return resp
 }
 
+// Locks returns the locks held in the lock tables of the leaseholder
+// replicas on the requested node(s), along with the requests waiting on them.
+func (s *statusServer) Locks(
+	ctx context.Context, req *serverpb.LocksRequest,
+) (*serverpb.LocksResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.admin.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			return s.localLocks(ctx)
+		}
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.Locks(ctx, req)
+	}
+
+	var response serverpb.LocksResponse
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	remoteRequest := serverpb.LocksRequest{NodeID: "local"}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.Locks(ctx, &remoteRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, resp interface{}) {
+		locksResp := resp.(*serverpb.LocksResponse)
+		response.Locks = append(response.Locks, locksResp.Locks...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.Errors = append(response.Errors, serverpb.ListSessionsError{
+			NodeID:  nodeID,
+			Message: err.Error(),
+		})
+	}
+
+	if err := s.iterateNodes(ctx, "locks", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *statusServer) localLocks(ctx context.Context) (*serverpb.LocksResponse, error) {
+	var response serverpb.LocksResponse
+	nodeID := s.gossip.NodeID.Get()
+	includeRawKeys := debug.GatewayRemoteAllowed(ctx, s.st)
+	err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		for _, info := range store.QueryLockTableState() {
+			if !includeRawKeys {
+				info.Key = nil
+			}
+			response.Locks = append(response.Locks, serverpb.LocksResponse_Lock{
+				NodeID:  nodeID,
+				StoreID: store.StoreID(),
+				Lock:    info,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ContentionEvents returns the lock contention encountered by requests on
+// each store of the requested node(s), aggregated per SQL table and index.
+func (s *statusServer) ContentionEvents(
+	ctx context.Context, req *serverpb.ContentionEventsRequest,
+) (*serverpb.ContentionEventsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.admin.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			return s.localContentionEvents()
+		}
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.ContentionEvents(ctx, req)
+	}
+
+	var response serverpb.ContentionEventsResponse
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	remoteRequest := serverpb.ContentionEventsRequest{NodeID: "local"}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.ContentionEvents(ctx, &remoteRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, resp interface{}) {
+		eventsResp := resp.(*serverpb.ContentionEventsResponse)
+		response.Events = append(response.Events, eventsResp.Events...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.Errors = append(response.Errors, serverpb.ListSessionsError{
+			NodeID:  nodeID,
+			Message: err.Error(),
+		})
+	}
+
+	if err := s.iterateNodes(ctx, "contention events", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *statusServer) localContentionEvents() (*serverpb.ContentionEventsResponse, error) {
+	var response serverpb.ContentionEventsResponse
+	nodeID := s.gossip.NodeID.Get()
+	err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		for _, e := range store.ContentionEvents() {
+			response.Events = append(response.Events, serverpb.ContentionEventsResponse_Events{
+				NodeID:  nodeID,
+				StoreID: store.StoreID(),
+				Events:  e,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // Range returns rangeInfos for all nodes in the cluster about a specific
 // range. It also returns the range history for that range as well.
 func (s *statusServer) Range(
@@ -1963,6 +2113,14 @@ func (s *statusServer) Stores(
 		storeDetails.TotalBytes = envStats.TotalBytes
 		storeDetails.ActiveKeyFiles = envStats.ActiveKeyFiles
 		storeDetails.ActiveKeyBytes = envStats.ActiveKeyBytes
+		for _, ks := range envStats.KeyStats {
+			storeDetails.KeyStats = append(storeDetails.KeyStats, serverpb.EncryptionKeyStats{
+				KeyID:        ks.KeyID,
+				CreationTime: ks.CreationTime,
+				Files:        ks.Files,
+				Bytes:        ks.Bytes,
+			})
+		}
 
 		resp.Stores = append(resp.Stores, storeDetails)
 
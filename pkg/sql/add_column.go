@@ -42,6 +42,9 @@ func (p *planner) addColumnImpl(
 			toType.SQLString(),
 		)
 	}
+	if err := checkColumnDefSupportedInVersion(version, d); err != nil {
+		return err
+	}
 
 	newDef, seqDbDesc, seqName, seqOpts, err := params.p.processSerialInColumnDef(params.ctx, d, tn)
 	if err != nil {
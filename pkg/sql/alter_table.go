@@ -70,6 +70,11 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 		return nil, err
 	}
 
+	if tableDesc.IsForeignTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot alter foreign table %q", tableDesc.Name)
+	}
+
 	n.HoistAddColumnConstraints()
 
 	// See if there's any "inject statistics" in the query and type check the
@@ -320,6 +325,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 				continue
 			}
 
+			if ttl := n.tableDesc.RowLevelTTL; ttl != nil && ttl.ColumnID == colToDrop.ID {
+				return pgerror.Newf(pgcode.InvalidTableDefinition,
+					"cannot drop column %q since it is the %s of the table", colToDrop.Name, ttlColumnParam)
+			}
+
 			// If the dropped column uses a sequence, remove references to it from that sequence.
 			if len(colToDrop.UsesSequenceIds) > 0 {
 				if err := params.p.removeSequenceDependencies(params.ctx, n.tableDesc, colToDrop); err != nil {
@@ -637,6 +647,40 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return err
 			}
 
+		case *tree.AlterTableSetStorageParams:
+			if err := checkStorageParameters(
+				params.ctx, params.p.SemaCtx(), t.StorageParams, storageParamExpectedTypes,
+			); err != nil {
+				return err
+			}
+			oldScheduleID := rowLevelTTLScheduleID(n.tableDesc.TableDesc())
+			changed, err := params.p.setRowLevelTTLStorageParams(params.ctx, n.tableDesc, t.StorageParams)
+			if err != nil {
+				return err
+			}
+			if changed {
+				if err := params.p.updateRowLevelTTLSchedule(params.ctx, n.tableDesc, oldScheduleID); err != nil {
+					return err
+				}
+				descriptorChanged = true
+			}
+
+		case *tree.AlterTableResetStorageParams:
+			if err := checkResetStorageParameters(t.Params); err != nil {
+				return err
+			}
+			oldScheduleID := rowLevelTTLScheduleID(n.tableDesc.TableDesc())
+			changed, err := resetRowLevelTTLStorageParams(n.tableDesc, t.Params)
+			if err != nil {
+				return err
+			}
+			if changed {
+				if err := params.p.updateRowLevelTTLSchedule(params.ctx, n.tableDesc, oldScheduleID); err != nil {
+					return err
+				}
+				descriptorChanged = true
+			}
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
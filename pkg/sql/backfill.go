@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -161,6 +162,8 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 	var constraintsToAddBeforeValidation []sqlbase.ConstraintToUpdate
 	var constraintsToValidate []sqlbase.ConstraintToUpdate
 
+	var viewToRefresh *sqlbase.MaterializedViewRefresh
+
 	tableDesc, err := sc.updateJobRunningStatus(ctx, RunningStatusBackfill)
 	if err != nil {
 		return err
@@ -207,6 +210,8 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 				}
 			case *sqlbase.DescriptorMutation_PrimaryKeySwap, *sqlbase.DescriptorMutation_ComputedColumnSwap:
 				// The backfiller doesn't need to do anything here.
+			case *sqlbase.DescriptorMutation_MaterializedViewRefresh:
+				viewToRefresh = t.MaterializedViewRefresh
 			default:
 				return errors.AssertionFailedf(
 					"unsupported mutation: %+v", m)
@@ -222,7 +227,9 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 				}
 			case *sqlbase.DescriptorMutation_Constraint:
 				constraintsToDrop = append(constraintsToDrop, *t.Constraint)
-			case *sqlbase.DescriptorMutation_PrimaryKeySwap, *sqlbase.DescriptorMutation_ComputedColumnSwap:
+			case *sqlbase.DescriptorMutation_PrimaryKeySwap,
+				*sqlbase.DescriptorMutation_ComputedColumnSwap,
+				*sqlbase.DescriptorMutation_MaterializedViewRefresh:
 				// The backfiller doesn't need to do anything here.
 			default:
 				return errors.AssertionFailedf(
@@ -265,6 +272,13 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 		}
 	}
 
+	// Recompute the results of a materialized view into its new indexes.
+	if viewToRefresh != nil {
+		if err := sc.refreshMaterializedView(ctx, tableDesc, viewToRefresh); err != nil {
+			return err
+		}
+	}
+
 	// Add check and foreign key constraints, publish the new version of the table descriptor,
 	// and wait until the entire cluster is on the new version. This is basically
 	// a state transition for the schema change, which must happen after the
@@ -1306,6 +1320,22 @@ func (sc *SchemaChanger) backfillIndexes(
 	return sc.validateIndexes(ctx)
 }
 
+// refreshMaterializedView backfills the results of the view query of the
+// given materialized view into the new set of indexes in refresh. The new
+// indexes replace the view's existing ones when the mutation completes.
+func (sc *SchemaChanger) refreshMaterializedView(
+	ctx context.Context, table *sqlbase.TableDescriptor, refresh *sqlbase.MaterializedViewRefresh,
+) error {
+	// Construct a copy of the view descriptor whose indexes are the new set of
+	// indexes, so that the backfill writes the results of the query into them.
+	// The results are written at the timestamp that the query is evaluated at.
+	tableToRefresh := protoutil.Clone(table).(*sqlbase.TableDescriptor)
+	tableToRefresh.PrimaryIndex = refresh.NewPrimaryIndex
+	tableToRefresh.Indexes = refresh.NewIndexes
+	tableToRefresh.CreateAsOfTime = refresh.AsOf
+	return sc.backfillQueryIntoTable(ctx, tableToRefresh, table.ViewQuery, refresh.AsOf, "refreshView")
+}
+
 // truncateAndBackfillColumns performs the backfill operation on the given leased
 // table descriptors.
 //
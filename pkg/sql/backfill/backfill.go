@@ -170,11 +170,13 @@ func (cb *ColumnBackfiller) Init(
 		ColIdxMap:       desc.ColumnIdxMap(),
 		Cols:            desc.Columns,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         evalCtx,
 	}
 	return cb.fetcher.Init(
 		evalCtx.Codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&cb.alloc,
@@ -409,11 +411,13 @@ func (ib *IndexBackfiller) Init(
 		ColIdxMap:       ib.colIdxMap,
 		Cols:            cols,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         evalCtx,
 	}
 	return ib.fetcher.Init(
 		evalCtx.Codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&ib.alloc,
@@ -105,6 +105,7 @@ func unwrapDescriptor(
 	// TODO(ajwerner): Fill in the ModificationTime field for the descriptor.
 	desc.MaybeSetModificationTimeFromMVCCTimestamp(ctx, ts)
 	table, database, typ, schema := desc.Table(hlc.Timestamp{}), desc.GetDatabase(), desc.GetType(), desc.GetSchema()
+	function := desc.GetFunction()
 	switch {
 	case table != nil:
 		if err := table.MaybeFillInDescriptor(ctx, txn, codec); err != nil {
@@ -124,6 +125,8 @@ func unwrapDescriptor(
 		return sqlbase.NewImmutableTypeDescriptor(*typ), nil
 	case schema != nil:
 		return sqlbase.NewImmutableSchemaDescriptor(*schema), nil
+	case function != nil:
+		return sqlbase.NewImmutableFunctionDescriptor(*function), nil
 	default:
 		return nil, nil
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
@@ -33,6 +34,10 @@ var SupportedAggFns = []execinfrapb.AggregatorSpec_Func{
 	execinfrapb.AggregatorSpec_MAX,
 	execinfrapb.AggregatorSpec_BOOL_AND,
 	execinfrapb.AggregatorSpec_BOOL_OR,
+	execinfrapb.AggregatorSpec_ST_EXTENT,
+	execinfrapb.AggregatorSpec_ST_COLLECT,
+	execinfrapb.AggregatorSpec_ST_MAKELINE,
+	execinfrapb.AggregatorSpec_ST_UNION,
 }
 
 // aggregateFunc is an aggregate function that performs computation on a batch
@@ -114,6 +119,7 @@ type aggregateFuncsAlloc struct {
 
 func newAggregateFuncsAlloc(
 	allocator *colmem.Allocator,
+	evalCtx *tree.EvalContext,
 	aggTyps [][]*types.T,
 	aggFns []execinfrapb.AggregatorSpec_Func,
 	allocSize int64,
@@ -183,6 +189,21 @@ func newAggregateFuncsAlloc(
 			} else {
 				funcAllocs[i] = newBoolOrOrderedAggAlloc(allocator, allocSize)
 			}
+		case execinfrapb.AggregatorSpec_ST_EXTENT, execinfrapb.AggregatorSpec_ST_COLLECT,
+			execinfrapb.AggregatorSpec_ST_MAKELINE, execinfrapb.AggregatorSpec_ST_UNION:
+			// These aggregate functions don't have an optimized implementation,
+			// so we fall back to the aggregate builtins.
+			var constructor func(*tree.EvalContext, tree.Datums) tree.AggregateFunc
+			var outputType *types.T
+			constructor, outputType, err = execinfrapb.GetAggregateInfo(aggFns[i], aggTyps[i]...)
+			if err != nil {
+				break
+			}
+			if isHashAgg {
+				funcAllocs[i] = newDefaultHashAggAlloc(allocator, evalCtx, constructor, aggTyps[i], outputType, allocSize)
+			} else {
+				funcAllocs[i] = newDefaultOrderedAggAlloc(allocator, evalCtx, constructor, aggTyps[i], outputType, allocSize)
+			}
 		// NOTE: if you're adding an implementation of a new aggregate
 		// function, make sure to account for the memory under that struct in
 		// its constructor.
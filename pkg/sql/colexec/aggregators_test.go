@@ -19,9 +19,11 @@ import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coldatatestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -62,6 +64,7 @@ type aggregatorTestCase struct {
 type aggType struct {
 	new func(
 		allocator *colmem.Allocator,
+		evalCtx *tree.EvalContext,
 		input colexecbase.Operator,
 		typs []*types.T,
 		aggFns []execinfrapb.AggregatorSpec_Func,
@@ -78,6 +81,7 @@ var aggTypes = []aggType{
 		// with orderedAggregator.
 		new: func(
 			allocator *colmem.Allocator,
+			evalCtx *tree.EvalContext,
 			input colexecbase.Operator,
 			typs []*types.T,
 			aggFns []execinfrapb.AggregatorSpec_Func,
@@ -86,7 +90,7 @@ var aggTypes = []aggType{
 			_ bool,
 		) (colexecbase.Operator, error) {
 			return NewHashAggregator(
-				allocator, input, typs, aggFns, groupCols, aggCols)
+				allocator, evalCtx, input, typs, aggFns, groupCols, aggCols)
 		},
 		name: "hash",
 	},
@@ -146,6 +150,10 @@ func (tc *aggregatorTestCase) init() error {
 
 func TestAggregatorOneFunc(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 	testCases := []aggregatorTestCase{
 		{
 			input: tuples{
@@ -302,6 +310,7 @@ func TestAggregatorOneFunc(t *testing.T) {
 				tupleSource := newOpTestInput(tc.batchSize, tc.input, tc.typs)
 				a, err := NewOrderedAggregator(
 					testAllocator,
+					&evalCtx,
 					tupleSource,
 					tc.typs,
 					tc.aggFns,
@@ -335,6 +344,7 @@ func TestAggregatorOneFunc(t *testing.T) {
 							func(input []colexecbase.Operator) (colexecbase.Operator, error) {
 								return agg.new(
 									testAllocator,
+									&evalCtx,
 									input[0],
 									tc.typs,
 									tc.aggFns,
@@ -352,6 +362,10 @@ func TestAggregatorOneFunc(t *testing.T) {
 
 func TestAggregatorMultiFunc(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 	// TODO(yuzefovich): introduce nicer aliases for the protobuf generated
 	// ones and use those throughout the codebase.
 	avgFn := execinfrapb.AggregatorSpec_AVG
@@ -532,7 +546,7 @@ func TestAggregatorMultiFunc(t *testing.T) {
 				}
 				runTestsWithTyps(t, []tuples{tc.input}, [][]*types.T{tc.typs}, tc.expected, unorderedVerifier,
 					func(input []colexecbase.Operator) (colexecbase.Operator, error) {
-						return agg.new(testAllocator, input[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, &evalCtx, input[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
 					})
 			})
 		}
@@ -541,6 +555,10 @@ func TestAggregatorMultiFunc(t *testing.T) {
 
 func TestAggregatorAllFunctions(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 	testCases := []aggregatorTestCase{
 		{
 			aggFns: []execinfrapb.AggregatorSpec_Func{
@@ -626,7 +644,7 @@ func TestAggregatorAllFunctions(t *testing.T) {
 					tc.expected,
 					verifier,
 					func(input []colexecbase.Operator) (colexecbase.Operator, error) {
-						return agg.new(testAllocator, input[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, &evalCtx, input[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
 					})
 			})
 		}
@@ -635,6 +653,10 @@ func TestAggregatorAllFunctions(t *testing.T) {
 
 func TestAggregatorRandom(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 
 	// This test aggregates random inputs, keeping track of the expected results
 	// to make sure the aggregations are correct.
@@ -718,6 +740,7 @@ func TestAggregatorRandom(t *testing.T) {
 							source := newChunkingBatchSource(typs, cols, nTuples)
 							a, err := agg.new(
 								testAllocator,
+								&evalCtx,
 								source,
 								typs,
 								[]execinfrapb.AggregatorSpec_Func{
@@ -756,6 +779,9 @@ func TestAggregatorRandom(t *testing.T) {
 func BenchmarkAggregator(b *testing.B) {
 	rng, _ := randutil.NewPseudoRand()
 	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 
 	const bytesFixedLength = 8
 	for _, aggFn := range []execinfrapb.AggregatorSpec_Func{
@@ -835,6 +861,7 @@ func BenchmarkAggregator(b *testing.B) {
 										}
 										a, err := agg.new(
 											testAllocator,
+											&evalCtx,
 											source,
 											typs,
 											[]execinfrapb.AggregatorSpec_Func{aggFn},
@@ -871,6 +898,10 @@ func BenchmarkAggregator(b *testing.B) {
 
 func TestHashAggregator(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
 	tcs := []aggregatorTestCase{
 		{
 			// Test carry between output batches.
@@ -984,7 +1015,7 @@ func TestHashAggregator(t *testing.T) {
 			}
 			t.Run(fmt.Sprintf("numOfHashBuckets=%d", numOfHashBuckets), func(t *testing.T) {
 				runTests(t, []tuples{tc.input}, tc.expected, unorderedVerifier, func(sources []colexecbase.Operator) (colexecbase.Operator, error) {
-					a, err := NewHashAggregator(testAllocator, sources[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols)
+					a, err := NewHashAggregator(testAllocator, &evalCtx, sources[0], tc.typs, tc.aggFns, tc.groupCols, tc.aggCols)
 					a.(*hashAggregator).testingKnobs.numOfHashBuckets = uint64(numOfHashBuckets)
 					return a, err
 				})
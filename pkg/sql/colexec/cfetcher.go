@@ -181,6 +181,10 @@ type cFetcher struct {
 	// lockStr represents the row-level locking mode to use when fetching rows.
 	lockStr sqlbase.ScanLockingStrength
 
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -247,6 +251,7 @@ func (rf *cFetcher) Init(
 	allocator *colmem.Allocator,
 	reverse bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 	isCheck bool,
 	tables ...row.FetcherTableArgs,
@@ -258,6 +263,7 @@ func (rf *cFetcher) Init(
 
 	rf.reverse = reverse
 	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
 	rf.returnRangeInfo = returnRangeInfo
 
 	if len(tables) > 1 {
@@ -475,7 +481,8 @@ func (rf *cFetcher) StartScan(
 	}
 
 	f, err := row.NewKVFetcher(
-		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.lockStr, rf.returnRangeInfo,
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.lockStr, rf.lockWaitPolicy,
+		rf.returnRangeInfo,
 	)
 	if err != nil {
 		return err
@@ -601,7 +608,7 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 		case stateInitFetch:
 			moreKeys, kv, newSpan, err := rf.fetcher.NextKV(ctx)
 			if err != nil {
-				return nil, colexecerror.NewStorageError(err)
+				return nil, colexecerror.NewStorageError(row.ConvertFetchError(rf.table.desc, err))
 			}
 			if !moreKeys {
 				rf.machine.state[0] = stateEmitLastBatch
@@ -751,7 +758,7 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 			for {
 				moreRows, kv, _, err := rf.fetcher.NextKV(ctx)
 				if err != nil {
-					return nil, colexecerror.NewStorageError(err)
+					return nil, colexecerror.NewStorageError(row.ConvertFetchError(rf.table.desc, err))
 				}
 				if debugState {
 					log.Infof(ctx, "found kv %s, seeking to prefix %s", kv.Key, rf.machine.seekPrefix)
@@ -783,7 +790,7 @@ func (rf *cFetcher) nextBatch(ctx context.Context) (coldata.Batch, error) {
 		case stateFetchNextKVWithUnfinishedRow:
 			moreKVs, kv, _, err := rf.fetcher.NextKV(ctx)
 			if err != nil {
-				return nil, colexecerror.NewStorageError(err)
+				return nil, colexecerror.NewStorageError(row.ConvertFetchError(rf.table.desc, err))
 			}
 			if !moreKVs {
 				// No more data. Finalize the row and exit.
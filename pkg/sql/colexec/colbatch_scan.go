@@ -143,6 +143,7 @@ func newColBatchScan(
 	if _, _, err := initCRowFetcher(
 		flowCtx.Codec(), allocator, &fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap,
 		spec.Reverse, neededColumns, spec.IsCheck, spec.Visibility, spec.LockingStrength,
+		spec.LockingWaitPolicy,
 	); err != nil {
 		return nil, err
 	}
@@ -174,6 +175,7 @@ func initCRowFetcher(
 	isCheck bool,
 	scanVisibility execinfrapb.ScanVisibility,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 ) (index *sqlbase.IndexDescriptor, isSecondaryIndex bool, err error) {
 	immutDesc := sqlbase.NewImmutableTableDescriptor(*desc)
 	index, isSecondaryIndex, err = immutDesc.FindIndexByIndexIdx(indexIdx)
@@ -194,7 +196,8 @@ func initCRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := fetcher.Init(
-		codec, allocator, reverseScan, lockStr, true /* returnRangeInfo */, isCheck, tableArgs,
+		codec, allocator, reverseScan, lockStr, lockWaitPolicy, true /* returnRangeInfo */, isCheck,
+		tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
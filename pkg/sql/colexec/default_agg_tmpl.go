@@ -0,0 +1,209 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// {{/*
+// +build execgen_template
+//
+// This file is the execgen template for default_agg.eg.go. It's formatted in a
+// special way, so it's both valid Go and a valid text/template input. This
+// permits editing this file with editor support.
+//
+// */}}
+
+package colexec
+
+import (
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+func newDefault_AGGKINDAggAlloc(
+	allocator *colmem.Allocator,
+	evalCtx *tree.EvalContext,
+	constructor func(*tree.EvalContext, tree.Datums) tree.AggregateFunc,
+	inputTypes []*types.T,
+	outputType *types.T,
+	allocSize int64,
+) aggregateFuncAlloc {
+	return &default_AGGKINDAggAlloc{
+		aggAllocBase: aggAllocBase{
+			allocator: allocator,
+			allocSize: allocSize,
+		},
+		evalCtx:     evalCtx,
+		constructor: constructor,
+		inputTypes:  inputTypes,
+		converter:   getDatumToPhysicalFn(outputType),
+	}
+}
+
+// default_AGGKINDAgg is an aggregate function that performs the aggregation
+// by feeding the datums of the input rows into a tree.AggregateFunc. It is
+// used for aggregate functions that don't have an optimized implementation.
+type default_AGGKINDAgg struct {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	groups []bool
+	// {{end}}
+	allocator *colmem.Allocator
+	evalCtx   *tree.EvalContext
+	fn        tree.AggregateFunc
+	// inputTypes are the types of the arguments of the aggregate function.
+	inputTypes []*types.T
+	// inputArgs is the scratch space for the arguments of the aggregate
+	// function beyond the first one.
+	inputArgs tree.Datums
+	converter func(tree.Datum) (interface{}, error)
+	vec       coldata.Vec
+	nulls     *coldata.Nulls
+	curIdx    int
+	da        sqlbase.DatumAlloc
+}
+
+var _ aggregateFunc = &default_AGGKINDAgg{}
+
+const sizeOfDefault_AGGKINDAgg = int64(unsafe.Sizeof(default_AGGKINDAgg{}))
+
+func (a *default_AGGKINDAgg) Init(groups []bool, vec coldata.Vec) {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.groups = groups
+	// {{end}}
+	a.vec = vec
+	a.nulls = vec.Nulls()
+	a.Reset()
+}
+
+func (a *default_AGGKINDAgg) Reset() {
+	a.curIdx = 0
+	a.nulls.UnsetNulls()
+	a.fn.Reset(a.evalCtx.Ctx())
+}
+
+func (a *default_AGGKINDAgg) CurrentOutputIndex() int {
+	return a.curIdx
+}
+
+func (a *default_AGGKINDAgg) SetOutputIndex(idx int) {
+	a.curIdx = idx
+}
+
+func (a *default_AGGKINDAgg) Compute(batch coldata.Batch, inputIdxs []uint32) {
+	inputLen := batch.Length()
+	sel := batch.Selection()
+	a.allocator.PerformOperation(
+		[]coldata.Vec{a.vec},
+		func() {
+			for i := 0; i < inputLen; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				// {{if eq "_AGGKIND" "Ordered"}}
+				if a.groups[rowIdx] {
+					a.setResult()
+					a.curIdx++
+					a.fn.Reset(a.evalCtx.Ctx())
+				}
+				// {{end}}
+				var firstArg tree.Datum
+				for j, colIdx := range inputIdxs {
+					d := PhysicalTypeColElemToDatum(batch.ColVec(int(colIdx)), rowIdx, &a.da, a.inputTypes[j])
+					if j == 0 {
+						firstArg = d
+					} else {
+						a.inputArgs[j-1] = d
+					}
+				}
+				if err := a.fn.Add(a.evalCtx.Ctx(), firstArg, a.inputArgs...); err != nil {
+					colexecerror.ExpectedError(err)
+				}
+			}
+		},
+	)
+}
+
+// setResult writes the current result of the aggregate function at the
+// current output index.
+func (a *default_AGGKINDAgg) setResult() {
+	res, err := a.fn.Result()
+	if err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	if res == tree.DNull {
+		a.nulls.SetNull(a.curIdx)
+		return
+	}
+	converted, err := a.converter(res)
+	if err != nil {
+		colexecerror.InternalError(err)
+	}
+	coldata.SetValueAt(a.vec, converted, a.curIdx)
+}
+
+func (a *default_AGGKINDAgg) Flush() {
+	a.allocator.PerformOperation(
+		[]coldata.Vec{a.vec},
+		func() {
+			a.setResult()
+		},
+	)
+	a.curIdx++
+	// {{if eq "_AGGKIND" "Hash"}}
+	// The hash aggregator flushes each aggregate function exactly once, so we
+	// can release the memory held by the aggregate function right away.
+	a.fn.Close(a.evalCtx.Ctx())
+	// {{end}}
+}
+
+func (a *default_AGGKINDAgg) HandleEmptyInputScalar() {
+	a.allocator.PerformOperation(
+		[]coldata.Vec{a.vec},
+		func() {
+			a.curIdx = 0
+			a.setResult()
+		},
+	)
+}
+
+type default_AGGKINDAggAlloc struct {
+	aggAllocBase
+	aggFuncs []default_AGGKINDAgg
+
+	evalCtx     *tree.EvalContext
+	constructor func(*tree.EvalContext, tree.Datums) tree.AggregateFunc
+	inputTypes  []*types.T
+	converter   func(tree.Datum) (interface{}, error)
+}
+
+var _ aggregateFuncAlloc = &default_AGGKINDAggAlloc{}
+
+func (a *default_AGGKINDAggAlloc) newAggFunc() aggregateFunc {
+	if len(a.aggFuncs) == 0 {
+		a.allocator.AdjustMemoryUsage(sizeOfDefault_AGGKINDAgg * a.allocSize)
+		a.aggFuncs = make([]default_AGGKINDAgg, a.allocSize)
+	}
+	f := &a.aggFuncs[0]
+	a.aggFuncs = a.aggFuncs[1:]
+	f.allocator = a.allocator
+	f.evalCtx = a.evalCtx
+	f.fn = a.constructor(a.evalCtx, nil /* arguments */)
+	a.allocator.AdjustMemoryUsage(f.fn.Size())
+	f.inputTypes = a.inputTypes
+	if len(a.inputTypes) > 1 {
+		f.inputArgs = make(tree.Datums, len(a.inputTypes)-1)
+	}
+	f.converter = a.converter
+	return f
+}
@@ -202,6 +202,15 @@ func isSupported(mode sessiondata.VectorizeExecMode, spec *execinfrapb.Processor
 		if core.TableReader.IsCheck {
 			return errors.Newf("scrub table reader is unsupported in vectorized")
 		}
+		if core.TableReader.IndexIdx == 0 {
+			// Virtual columns are computed when scanning the primary index, which
+			// only the row-by-row fetcher supports.
+			for i := range core.TableReader.Table.Columns {
+				if core.TableReader.Table.Columns[i].IsVirtual() {
+					return errors.Newf("table reader on a table with virtual columns is unsupported in vectorized")
+				}
+			}
+		}
 		return nil
 
 	case core.Aggregator != nil:
@@ -735,6 +744,9 @@ func NewColOperator(
 			}
 			typs := make([]*types.T, len(spec.Input[0].ColumnTypes))
 			copy(typs, spec.Input[0].ColumnTypes)
+			// The aggregate functions that are computed by the aggregate builtins
+			// account for their memory usage against the aggregator's account.
+			evalCtx := flowCtx.NewEvalCtx()
 			if needHash {
 				hashAggregatorMemAccount := streamingMemAccount
 				if !useStreamingMemAccountForBuffering {
@@ -746,13 +758,15 @@ func NewColOperator(
 					// "unlimited") amount of memory to the aggregator.
 					hashAggregatorMemAccount = result.createBufferingUnlimitedMemAccount(ctx, flowCtx, "hash-aggregator")
 				}
+				evalCtx.SingleDatumAggMemAccount = hashAggregatorMemAccount
 				result.Op, err = NewHashAggregator(
-					colmem.NewAllocator(ctx, hashAggregatorMemAccount, factory), inputs[0], typs, aggFns,
+					colmem.NewAllocator(ctx, hashAggregatorMemAccount, factory), evalCtx, inputs[0], typs, aggFns,
 					aggSpec.GroupCols, aggCols,
 				)
 			} else {
+				evalCtx.SingleDatumAggMemAccount = streamingMemAccount
 				result.Op, err = NewOrderedAggregator(
-					streamingAllocator, inputs[0], typs, aggFns,
+					streamingAllocator, evalCtx, inputs[0], typs, aggFns,
 					aggSpec.GroupCols, aggCols, aggSpec.IsScalar(),
 				)
 				result.IsStreaming = true
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
@@ -172,6 +173,7 @@ const hashAggregatorAllocSize = 64
 // NewOrderedAggregator function.
 func NewHashAggregator(
 	allocator *colmem.Allocator,
+	evalCtx *tree.EvalContext,
 	input colexecbase.Operator,
 	typs []*types.T,
 	aggFns []execinfrapb.AggregatorSpec_Func,
@@ -191,7 +193,7 @@ func NewHashAggregator(
 		groupTypes[i] = typs[colIdx]
 	}
 
-	aggFnsAlloc, err := newAggregateFuncsAlloc(allocator, aggTyps, aggFns, hashAggregatorAllocSize, true /* isHashAgg */)
+	aggFnsAlloc, err := newAggregateFuncsAlloc(allocator, evalCtx, aggTyps, aggFns, hashAggregatorAllocSize, true /* isHashAgg */)
 
 	return &hashAggregator{
 		OneInputNode: NewOneInputNode(input),
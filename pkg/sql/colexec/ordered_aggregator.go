@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
@@ -94,9 +95,11 @@ var _ colexecbase.Operator = &orderedAggregator{}
 // NewOrderedAggregator creates an ordered aggregator on the given grouping
 // columns. aggCols is a slice where each index represents a new aggregation
 // function. The slice at that index specifies the columns of the input batch
-// that the aggregate function should work on.
+// that the aggregate function should work on. evalCtx is used by the aggregate
+// functions that are computed by the aggregate builtins.
 func NewOrderedAggregator(
 	allocator *colmem.Allocator,
+	evalCtx *tree.EvalContext,
 	input colexecbase.Operator,
 	typs []*types.T,
 	aggFns []execinfrapb.AggregatorSpec_Func,
@@ -153,7 +156,7 @@ func NewOrderedAggregator(
 
 	// We will be reusing the same aggregate functions, so we use 1 as the
 	// allocation size.
-	funcsAlloc, err := newAggregateFuncsAlloc(a.allocator, aggTypes, aggFns, 1 /* allocSize */, false /* isHashAgg */)
+	funcsAlloc, err := newAggregateFuncsAlloc(a.allocator, evalCtx, aggTypes, aggFns, 1 /* allocSize */, false /* isHashAgg */)
 	if err != nil {
 		return nil, errors.AssertionFailedf(
 			"this error should have been checked in isAggregateSupported\n%+v", err,
@@ -410,6 +410,22 @@ func getDatumToPhysicalFn(ct *types.T) func(tree.Datum) (interface{}, error) {
 			}
 			return d, nil
 		}
+	case types.TSQueryFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DTSQuery)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DTSQuery, found %s", reflect.TypeOf(datum))
+			}
+			return d, nil
+		}
+	case types.TSVectorFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DTSVector)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DTSVector, found %s", reflect.TypeOf(datum))
+			}
+			return d, nil
+		}
 	case types.EnumFamily:
 		return func(datum tree.Datum) (interface{}, error) {
 			d, ok := datum.(*tree.DEnum)
@@ -430,6 +430,9 @@ func (s *Server) SetupConn(
 		ctx, sd, args.SessionDefaults, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.getStatsForApplication(sd.ApplicationName),
 	)
+	if s.cfg.NotificationRegistry != nil {
+		ex.notifications = s.cfg.NotificationRegistry.newSession(stmtBuf)
+	}
 	return ConnectionHandler{ex}, nil
 }
 
@@ -700,6 +703,10 @@ func (s *Server) newConnExecutorWithTxn(
 	if tcModifier != nil {
 		tcModifier.CopyModifiedObjects(&ex.extraTxnState.descCollection)
 	}
+
+	// The executor does not commit the transaction, so the checking of
+	// deferrable constraints cannot be deferred until then.
+	ex.planner.extendedEvalCtx.DeferredConstraints = nil
 	return ex
 }
 
@@ -863,6 +870,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notifications != nil {
+		ex.notifications.close()
+	}
+
 	if closeType != panicClose {
 		ex.state.mon.Stop(ctx)
 		ex.sessionMon.Stop(ctx)
@@ -986,6 +997,15 @@ type connExecutor struct {
 		// processing the command at position txnRewindPos. When rewinding, we're
 		// going to restore this snapshot.
 		savepointsAtTxnRewindPos savepointStack
+
+		// deferredConstraints tracks the checking modes of deferrable
+		// constraints and the constraint checks deferred until commit.
+		deferredConstraints deferredConstraints
+
+		// deferredConstraintsAtTxnRewindPos is a snapshot of
+		// deferredConstraints before processing the command at position
+		// txnRewindPos, restored when rewinding like savepointsAtTxnRewindPos.
+		deferredConstraintsAtTxnRewindPos deferredConstraints
 	}
 
 	// sessionData contains the user-configurable connection variables.
@@ -1055,6 +1075,11 @@ type connExecutor struct {
 	// going to find a suitable time to close the connection.
 	draining bool
 
+	// notifications holds the channels this session is listening on and the
+	// notifications waiting to be delivered to the client. It is nil for
+	// sessions that can't receive notifications (e.g. internal executors).
+	notifications *sessionNotifications
+
 	// executorType is set to whether this executor is an ordinary executor which
 	// responds to user queries or an internal one.
 	executorType executorType
@@ -1160,9 +1185,15 @@ func (ex *connExecutor) resetExtraTxnState(
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	if ex.notifications != nil {
+		// LISTEN and UNLISTEN only take effect once the transaction commits.
+		ex.notifications.finishTxn(ev == txnCommit)
+	}
+
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
+		ex.extraTxnState.deferredConstraints.reset()
 		// After txn is finished, we need to call onTxnFinish (if it's non-nil).
 		if ex.extraTxnState.onTxnFinish != nil {
 			ex.extraTxnState.onTxnFinish(ev)
@@ -1171,7 +1202,8 @@ func (ex *connExecutor) resetExtraTxnState(
 	}
 	// NOTE: on txnRestart we don't need to muck with the savepoints stack. It's either a
 	// a ROLLBACK TO SAVEPOINT that generated the event, and that statement deals with the
-	// savepoints, or it's a rewind which also deals with them.
+	// savepoints, or it's a rewind which also deals with them. The same goes for the
+	// deferred constraints.
 
 	return nil
 }
@@ -1441,6 +1473,11 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case Sync:
 		// Note that the Sync result will flush results to the network connection.
 		res = ex.clientComm.CreateSyncResult(pos)
+		if ex.notifications != nil && ex.idleConn() {
+			// Make sure that the notifications that arrived during this batch are
+			// delivered now that we're out of a transaction.
+			ex.notifications.resignal()
+		}
 		if ex.draining {
 			// If we're draining, check whether this is a good time to finish the
 			// connection. If we're not inside a transaction, we stop processing
@@ -1460,6 +1497,13 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	case StartReplication:
+		res = ex.clientComm.CreateStartReplicationResult(pos)
+		var err error
+		ev, payload, err = ex.execStartReplication(ctx, tcmd, pos)
+		if err != nil {
+			return err
+		}
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -1473,6 +1517,16 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		notifRes := ex.clientComm.CreateNotificationResult(pos)
+		res = notifRes
+		// Notifications are only delivered in between transactions. If a
+		// transaction is open, they stay queued until the next Sync.
+		if ex.notifications != nil && ex.idleConn() {
+			for _, n := range ex.notifications.takeAll() {
+				notifRes.BufferNotification(n)
+			}
+		}
 	default:
 		panic(fmt.Sprintf("unsupported command type: %T", cmd))
 	}
@@ -1534,6 +1588,7 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case rewind:
 		ex.rewindPrepStmtNamespace(ctx)
 		ex.extraTxnState.savepoints = ex.extraTxnState.savepointsAtTxnRewindPos
+		ex.extraTxnState.deferredConstraints = ex.extraTxnState.deferredConstraintsAtTxnRewindPos.clone()
 		advInfo.rewCap.rewindAndUnlock(ctx)
 	case stayInPlace:
 		// Nothing to do. The same statement will be executed again.
@@ -1648,10 +1703,14 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(fmt.Sprintf("unsupported cmd: %T", cmd))
 			}
@@ -1676,6 +1735,7 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 	ex.stmtBuf.ltrim(ctx, pos)
 	ex.commitPrepStmtNamespace(ctx)
 	ex.extraTxnState.savepointsAtTxnRewindPos = ex.extraTxnState.savepoints.clone()
+	ex.extraTxnState.deferredConstraintsAtTxnRewindPos = ex.extraTxnState.deferredConstraints.clone()
 }
 
 // stmtDoesntNeedRetry returns true if the given statement does not need to be
@@ -1794,6 +1854,38 @@ func (ex *connExecutor) execCopyIn(
 	return nil, nil, nil
 }
 
+// execStartReplication runs a logical replication stream started with the
+// START_REPLICATION command. Like for CopyIn, the pgwire.conn hands the
+// connection over for the duration of the stream: the stream writes its
+// messages directly to the network connection, and the conn forwards the
+// status updates of the client through cmd.Feedback until the client ends
+// the stream.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication, pos CmdPos,
+) (fsm.Event, fsm.EventPayload, error) {
+	// When we're done, let the network connection resume reading commands.
+	defer close(cmd.Done)
+
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot be executed inside a transaction")}
+		return ev, payload, nil
+	}
+
+	// The results of the previous commands must reach the client before the
+	// messages of the stream.
+	if err := ex.clientComm.Flush(pos); err != nil {
+		return nil, nil, err
+	}
+	if err := startReplication(ctx, ex.server.cfg, ex.sessionData, cmd); err != nil {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload, nil
+	}
+	return nil, nil, nil
+}
+
 // stmtHasNoData returns true if describing a result of the input statement
 // type should return NoData.
 func stmtHasNoData(stmt tree.Statement) bool {
@@ -1990,8 +2082,11 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		DistSQLPlanner:    ex.server.cfg.DistSQLPlanner,
 		TxnModesSetter:    ex,
 		Jobs:              &ex.extraTxnState.jobs,
+		Notifications:     ex.notifications,
 		schemaAccessors:   scInterface,
 		sqlStatsCollector: ex.statsCollector,
+
+		DeferredConstraints: &ex.extraTxnState.deferredConstraints,
 	}
 }
 
@@ -2064,6 +2159,7 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx.AsOfTimestamp = nil
 	p.semaCtx.Annotations = nil
 	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	ex.resetEvalCtx(&p.extendedEvalCtx, txn, stmtTS)
 
@@ -2332,8 +2428,9 @@ func (ex *connExecutor) sessionEventf(ctx context.Context, format string, args .
 func (ex *connExecutor) notifyStatsRefresherOfNewTables(ctx context.Context) {
 	for _, desc := range ex.extraTxnState.descCollection.GetTableDescsWithNewVersion() {
 		// The CREATE STATISTICS run for an async CTAS query is initiated by the
-		// SchemaChanger, so we don't do it here.
-		if desc.IsTable() && !desc.IsAs() {
+		// SchemaChanger, so we don't do it here. Foreign tables have no
+		// statistics.
+		if desc.IsTable() && !desc.IsAs() && !desc.IsForeignTable() {
 			// Initiate a run of CREATE STATISTICS. We use a large number
 			// for rowsAffected because we want to make sure that stats always get
 			// created/refreshed here.
@@ -652,6 +652,18 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	// Run the constraint checks that were deferred until commit.
+	if err := validateDeferredConstraints(
+		ctx,
+		ex.extraTxnState.deferredConstraints.pending,
+		&ex.extraTxnState.descCollection,
+		ex.planner.ExtendedEvalContext().InternalExecutor.(*InternalExecutor),
+		ex.state.mu.txn,
+		ex.server.cfg.Codec,
+	); err != nil {
+		return err
+	}
+
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		return err
 	}
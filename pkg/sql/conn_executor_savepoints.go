@@ -111,10 +111,11 @@ func (ex *connExecutor) execSavepointInOpenState(
 	}
 
 	sp := savepoint{
-		name:            s.Name,
-		commitOnRelease: commitOnRelease,
-		kvToken:         token,
-		numDDL:          ex.extraTxnState.numDDL,
+		name:                s.Name,
+		commitOnRelease:     commitOnRelease,
+		kvToken:             token,
+		numDDL:              ex.extraTxnState.numDDL,
+		deferredConstraints: ex.extraTxnState.deferredConstraints.clone(),
 	}
 	savepoints.push(sp)
 
@@ -212,6 +213,7 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 	}
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.clone()
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -286,6 +288,7 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	}
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.clone()
 
 	// Special case for mixed-cluster versions, where regular savepoints
 	// are not yet enabled but we still support cockroach_restart. In
@@ -348,6 +351,12 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// deferredConstraints is a snapshot of the state of the deferrable
+	// constraints at the time the savepoint was created. It is restored on
+	// ROLLBACK TO SAVEPOINT, which undoes both the SET CONSTRAINTS statements
+	// and the writes whose constraint checks were deferred since then.
+	deferredConstraints deferredConstraints
 }
 
 type savepointStack []savepoint
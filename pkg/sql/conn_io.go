@@ -310,6 +310,21 @@ func (Flush) String() string {
 
 var _ Command = Flush{}
 
+// DeliverNotifications is a Command asking for the pending notifications of
+// the session to be delivered to the client. It is not generated by the
+// client; the NotificationRegistry pushes it when notifications arrive for a
+// session that is listening on their channel.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // CopyIn is the command for execution of the Copy-in pgwire subprotocol.
 type CopyIn struct {
 	Stmt *tree.CopyFrom
@@ -330,6 +345,32 @@ func (CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// StartReplication is the command for execution of a START_REPLICATION
+// command, which streams the changes of a replication slot to the client
+// using the CopyBoth subprotocol. Unlike CopyIn, the network routine keeps
+// reading from the connection while the stream runs, in order to forward the
+// status updates of the client.
+type StartReplication struct {
+	Stmt *tree.StartReplication
+	// Conn is the network connection. The stream writes to it directly.
+	Conn pgwirebase.ReplicationConn
+	// Feedback receives the status updates sent by the client. It is closed
+	// when the client ends the stream.
+	Feedback <-chan pgwirebase.StandbyStatusUpdate
+	// Done is closed once execution finishes, signaling that the network
+	// routine should stop forwarding status updates.
+	Done chan struct{}
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+func (StartReplication) String() string {
+	return "StartReplication"
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -614,8 +655,14 @@ type ClientComm interface {
 	CreateEmptyQueryResult(pos CmdPos) EmptyQueryResult
 	// CreateCopyInResult creates a result for a Copy-in command.
 	CreateCopyInResult(pos CmdPos) CopyInResult
+	// CreateStartReplicationResult creates a result for a StartReplication
+	// command.
+	CreateStartReplicationResult(pos CmdPos) StartReplicationResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateNotificationResult creates a result for a DeliverNotifications
+	// command.
+	CreateNotificationResult(pos CmdPos) NotificationResult
 
 	// lockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -785,6 +832,16 @@ type DrainResult interface {
 	ResultBase
 }
 
+// NotificationResult represents the result of a DeliverNotifications
+// command. When this result is closed, the buffered notifications are flushed
+// to the client.
+type NotificationResult interface {
+	ResultBase
+	// BufferNotification buffers a NotificationResponse message for the
+	// client.
+	BufferNotification(Notification)
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
@@ -797,6 +854,13 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// StartReplicationResult represents the result of a StartReplication command.
+// Closing this result produces a CommandComplete message once the stream has
+// ended.
+type StartReplicationResult interface {
+	ResultBase
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
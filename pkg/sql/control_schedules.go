@@ -0,0 +1,134 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type controlSchedulesNode struct {
+	schedules string
+	command   tree.ScheduleCommand
+	numRows   int
+}
+
+// ControlSchedules implements PAUSE/RESUME/DROP SCHEDULES.
+// Privileges: admin.
+func (p *planner) ControlSchedules(
+	ctx context.Context, n *tree.ControlSchedules,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionAddScheduledJobsTable) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s SCHEDULES requires all nodes to be upgraded", n.Command)
+	}
+	if err := p.RequireAdminRole(ctx, fmt.Sprintf("%s SCHEDULES", n.Command)); err != nil {
+		return nil, err
+	}
+
+	// The schedule IDs are produced by an arbitrary select statement which is
+	// run using the internal executor. Substitute placeholders with their
+	// values since the internal executor does not have access to them.
+	var evalErr error
+	f := tree.NewFmtCtx(tree.FmtSerializable)
+	f.SetPlaceholderFormat(func(ctx *tree.FmtCtx, placeholder *tree.Placeholder) {
+		d, err := placeholder.Eval(p.EvalContext())
+		if err != nil {
+			evalErr = err
+			return
+		}
+		d.Format(ctx)
+	})
+	f.FormatNode(n.Schedules)
+	schedules := f.CloseAndGetString()
+	if evalErr != nil {
+		return nil, evalErr
+	}
+
+	return &controlSchedulesNode{
+		schedules: schedules,
+		command:   n.Command,
+	}, nil
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *controlSchedulesNode) FastPathResults() (int, bool) {
+	return n.numRows, true
+}
+
+func (n *controlSchedulesNode) startExec(params runParams) error {
+	ex := params.p.ExecCfg().InternalExecutor
+	rows, err := ex.QueryEx(
+		params.ctx, "control-schedules", params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: params.p.User()},
+		n.schedules,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if len(row) != 1 {
+			return pgerror.Newf(pgcode.Syntax,
+				"%s SCHEDULES expects a single column source, got %d columns", n.command, len(row))
+		}
+		if row[0] == tree.DNull {
+			continue
+		}
+		scheduleID, ok := tree.AsDInt(row[0])
+		if !ok {
+			return errors.AssertionFailedf("%q: expected *DInt, found %T", row[0], row[0])
+		}
+
+		schedule, err := jobs.LoadScheduledJob(
+			params.ctx, jobs.ProdJobSchedulerEnv, int64(scheduleID), ex, params.p.txn)
+		if err != nil {
+			return err
+		}
+
+		switch n.command {
+		case tree.PauseSchedule:
+			schedule.Pause("operator paused schedule")
+			err = schedule.Update(params.ctx, ex, params.p.txn)
+		case tree.ResumeSchedule:
+			if err = schedule.Unpause("operator resumed schedule"); err == nil {
+				err = schedule.Update(params.ctx, ex, params.p.txn)
+			}
+		case tree.DropSchedule:
+			err = schedule.Delete(params.ctx, ex, params.p.txn)
+		default:
+			err = errors.AssertionFailedf("unhandled command %s", n.command)
+		}
+		if err != nil {
+			return err
+		}
+		n.numRows++
+	}
+	telemetry.Inc(sqltelemetry.ScheduleControlCounter(strings.ToLower(n.command.String())))
+	return nil
+}
+
+func (*controlSchedulesNode) Next(runParams) (bool, error) { return false, nil }
+
+func (*controlSchedulesNode) Values() tree.Datums { return nil }
+
+func (*controlSchedulesNode) Close(ctx context.Context) {}
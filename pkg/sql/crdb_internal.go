@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -43,6 +44,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -67,45 +70,47 @@ const crdbInternalName = sessiondata.CRDBInternalSchemaName
 var crdbInternal = virtualSchema{
 	name: crdbInternalName,
 	tableDefs: map[sqlbase.ID]virtualSchemaDef{
-		sqlbase.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
-		sqlbase.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
-		sqlbase.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
-		sqlbase.CrdbInternalClusterQueriesTableID:       crdbInternalClusterQueriesTable,
-		sqlbase.CrdbInternalClusterTransactionsTableID:  crdbInternalClusterTxnsTable,
-		sqlbase.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
-		sqlbase.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
-		sqlbase.CrdbInternalCreateStmtsTableID:          crdbInternalCreateStmtsTable,
-		sqlbase.CrdbInternalCreateTypeStmtsTableID:      crdbInternalCreateTypeStmtsTable,
-		sqlbase.CrdbInternalDatabasesTableID:            crdbInternalDatabasesTable,
-		sqlbase.CrdbInternalFeatureUsageID:              crdbInternalFeatureUsage,
-		sqlbase.CrdbInternalForwardDependenciesTableID:  crdbInternalForwardDependenciesTable,
-		sqlbase.CrdbInternalGossipNodesTableID:          crdbInternalGossipNodesTable,
-		sqlbase.CrdbInternalGossipAlertsTableID:         crdbInternalGossipAlertsTable,
-		sqlbase.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
-		sqlbase.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
-		sqlbase.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
-		sqlbase.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
-		sqlbase.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
-		sqlbase.CrdbInternalKVStoreStatusTableID:        crdbInternalKVStoreStatusTable,
-		sqlbase.CrdbInternalLeasesTableID:               crdbInternalLeasesTable,
-		sqlbase.CrdbInternalLocalQueriesTableID:         crdbInternalLocalQueriesTable,
-		sqlbase.CrdbInternalLocalTransactionsTableID:    crdbInternalLocalTxnsTable,
-		sqlbase.CrdbInternalLocalSessionsTableID:        crdbInternalLocalSessionsTable,
-		sqlbase.CrdbInternalLocalMetricsTableID:         crdbInternalLocalMetricsTable,
-		sqlbase.CrdbInternalPartitionsTableID:           crdbInternalPartitionsTable,
-		sqlbase.CrdbInternalPredefinedCommentsTableID:   crdbInternalPredefinedCommentsTable,
-		sqlbase.CrdbInternalRangesNoLeasesTableID:       crdbInternalRangesNoLeasesTable,
-		sqlbase.CrdbInternalRangesViewID:                crdbInternalRangesView,
-		sqlbase.CrdbInternalRuntimeInfoTableID:          crdbInternalRuntimeInfoTable,
-		sqlbase.CrdbInternalSchemaChangesTableID:        crdbInternalSchemaChangesTable,
-		sqlbase.CrdbInternalSessionTraceTableID:         crdbInternalSessionTraceTable,
-		sqlbase.CrdbInternalSessionVariablesTableID:     crdbInternalSessionVariablesTable,
-		sqlbase.CrdbInternalStmtStatsTableID:            crdbInternalStmtStatsTable,
-		sqlbase.CrdbInternalTableColumnsTableID:         crdbInternalTableColumnsTable,
-		sqlbase.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
-		sqlbase.CrdbInternalTablesTableID:               crdbInternalTablesTable,
-		sqlbase.CrdbInternalTxnStatsTableID:             crdbInternalTxnStatsTable,
-		sqlbase.CrdbInternalZonesTableID:                crdbInternalZonesTable,
+		sqlbase.CrdbInternalBackwardDependenciesTableID:    crdbInternalBackwardDependenciesTable,
+		sqlbase.CrdbInternalBuildInfoTableID:               crdbInternalBuildInfoTable,
+		sqlbase.CrdbInternalBuiltinFunctionsTableID:        crdbInternalBuiltinFunctionsTable,
+		sqlbase.CrdbInternalClusterContentionEventsTableID: crdbInternalClusterContentionEventsTable,
+		sqlbase.CrdbInternalClusterLocksTableID:            crdbInternalClusterLocksTable,
+		sqlbase.CrdbInternalClusterQueriesTableID:          crdbInternalClusterQueriesTable,
+		sqlbase.CrdbInternalClusterTransactionsTableID:     crdbInternalClusterTxnsTable,
+		sqlbase.CrdbInternalClusterSessionsTableID:         crdbInternalClusterSessionsTable,
+		sqlbase.CrdbInternalClusterSettingsTableID:         crdbInternalClusterSettingsTable,
+		sqlbase.CrdbInternalCreateStmtsTableID:             crdbInternalCreateStmtsTable,
+		sqlbase.CrdbInternalCreateTypeStmtsTableID:         crdbInternalCreateTypeStmtsTable,
+		sqlbase.CrdbInternalDatabasesTableID:               crdbInternalDatabasesTable,
+		sqlbase.CrdbInternalFeatureUsageID:                 crdbInternalFeatureUsage,
+		sqlbase.CrdbInternalForwardDependenciesTableID:     crdbInternalForwardDependenciesTable,
+		sqlbase.CrdbInternalGossipNodesTableID:             crdbInternalGossipNodesTable,
+		sqlbase.CrdbInternalGossipAlertsTableID:            crdbInternalGossipAlertsTable,
+		sqlbase.CrdbInternalGossipLivenessTableID:          crdbInternalGossipLivenessTable,
+		sqlbase.CrdbInternalGossipNetworkTableID:           crdbInternalGossipNetworkTable,
+		sqlbase.CrdbInternalIndexColumnsTableID:            crdbInternalIndexColumnsTable,
+		sqlbase.CrdbInternalJobsTableID:                    crdbInternalJobsTable,
+		sqlbase.CrdbInternalKVNodeStatusTableID:            crdbInternalKVNodeStatusTable,
+		sqlbase.CrdbInternalKVStoreStatusTableID:           crdbInternalKVStoreStatusTable,
+		sqlbase.CrdbInternalLeasesTableID:                  crdbInternalLeasesTable,
+		sqlbase.CrdbInternalLocalQueriesTableID:            crdbInternalLocalQueriesTable,
+		sqlbase.CrdbInternalLocalTransactionsTableID:       crdbInternalLocalTxnsTable,
+		sqlbase.CrdbInternalLocalSessionsTableID:           crdbInternalLocalSessionsTable,
+		sqlbase.CrdbInternalLocalMetricsTableID:            crdbInternalLocalMetricsTable,
+		sqlbase.CrdbInternalPartitionsTableID:              crdbInternalPartitionsTable,
+		sqlbase.CrdbInternalPredefinedCommentsTableID:      crdbInternalPredefinedCommentsTable,
+		sqlbase.CrdbInternalRangesNoLeasesTableID:          crdbInternalRangesNoLeasesTable,
+		sqlbase.CrdbInternalRangesViewID:                   crdbInternalRangesView,
+		sqlbase.CrdbInternalRuntimeInfoTableID:             crdbInternalRuntimeInfoTable,
+		sqlbase.CrdbInternalSchemaChangesTableID:           crdbInternalSchemaChangesTable,
+		sqlbase.CrdbInternalSessionTraceTableID:            crdbInternalSessionTraceTable,
+		sqlbase.CrdbInternalSessionVariablesTableID:        crdbInternalSessionVariablesTable,
+		sqlbase.CrdbInternalStmtStatsTableID:               crdbInternalStmtStatsTable,
+		sqlbase.CrdbInternalTableColumnsTableID:            crdbInternalTableColumnsTable,
+		sqlbase.CrdbInternalTableIndexesTableID:            crdbInternalTableIndexesTable,
+		sqlbase.CrdbInternalTablesTableID:                  crdbInternalTablesTable,
+		sqlbase.CrdbInternalTxnStatsTableID:                crdbInternalTxnStatsTable,
+		sqlbase.CrdbInternalZonesTableID:                   crdbInternalZonesTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -1298,6 +1303,258 @@ func populateSessionsTable(
 	return nil
 }
 
+// crdbInternalClusterLocksTable exposes the locks held in the lock tables of
+// the cluster's leaseholder replicas, along with the requests waiting on
+// them. Each lock has a row for its holder, if any, and a row per waiter.
+var crdbInternalClusterLocksTable = virtualSchemaTable{
+	comment: "locks held and waited on in the lock tables of all ranges (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.cluster_locks (
+  node_id         INT NOT NULL,
+  store_id        INT NOT NULL,
+  range_id        INT NOT NULL,
+  table_id        INT,
+  database_name   STRING,
+  table_name      STRING,
+  index_name      STRING,
+  lock_key        BYTES,
+  lock_key_pretty STRING,
+  txn_id          UUID,
+  lock_strength   STRING NOT NULL,
+  durability      STRING,
+  granted         BOOL NOT NULL,
+  contended       BOOL NOT NULL,
+  duration        INTERVAL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.cluster_locks"); err != nil {
+			return err
+		}
+		ss, err := p.extendedEvalCtx.StatusServer.OptionalErr()
+		if err != nil {
+			return err
+		}
+		response, err := ss.Locks(ctx, &serverpb.LocksRequest{})
+		if err != nil {
+			return err
+		}
+		// Report incomplete results as an error, since a missing lock may be
+		// exactly the one that explains the contention being debugged.
+		if len(response.Errors) > 0 {
+			rpcErr := response.Errors[0]
+			return errors.Errorf("could not retrieve locks from node %d: %s", rpcErr.NodeID, rpcErr.Message)
+		}
+		names, err := getIndexNamesByID(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		for i := range response.Locks {
+			l := &response.Locks[i]
+			tableID, dbName, tableName, indexName := tree.DNull, tree.DNull, tree.DNull, tree.DNull
+			lockKey, lockKeyPretty := tree.DNull, tree.DNull
+			if l.Lock.Key != nil {
+				lockKey = tree.NewDBytes(tree.DBytes(l.Lock.Key))
+				lockKeyPretty = tree.NewDString(keys.PrettyPrint(nil /* valDirs */, l.Lock.Key))
+				if _, tID, idxID, err := p.ExecCfg().Codec.DecodeIndexPrefix(l.Lock.Key); err == nil {
+					tableID = tree.NewDInt(tree.DInt(tID))
+					if tn, ok := names.tables[tID]; ok {
+						dbName = tree.NewDString(names.databases[names.parents[tID]])
+						tableName = tree.NewDString(tn)
+						if in, ok := names.indexes[tID][idxID]; ok {
+							indexName = tree.NewDString(in)
+						}
+					}
+				}
+			}
+			contended := tree.MakeDBool(tree.DBool(len(l.Lock.Waiters) > 0))
+			addLockRow := func(
+				txn *enginepb.TxnMeta, strength, durability tree.Datum, granted bool, dur tree.Datum,
+			) error {
+				txnID := tree.DNull
+				if txn != nil {
+					txnID = tree.NewDUuid(tree.DUuid{UUID: txn.ID})
+				}
+				return addRow(
+					tree.NewDInt(tree.DInt(l.NodeID)),
+					tree.NewDInt(tree.DInt(l.StoreID)),
+					tree.NewDInt(tree.DInt(l.Lock.RangeID)),
+					tableID,
+					dbName,
+					tableName,
+					indexName,
+					lockKey,
+					lockKeyPretty,
+					txnID,
+					strength,
+					durability,
+					tree.MakeDBool(tree.DBool(granted)),
+					contended,
+					dur,
+				)
+			}
+
+			if l.Lock.LockHolder != nil {
+				if err := addLockRow(
+					l.Lock.LockHolder,
+					tree.NewDString(lock.Exclusive.String()),
+					tree.NewDString(l.Lock.Durability.String()),
+					true, /* granted */
+					makeDurationInterval(l.Lock.HoldDuration),
+				); err != nil {
+					return err
+				}
+			}
+			for j := range l.Lock.Waiters {
+				w := &l.Lock.Waiters[j]
+				dur := tree.DNull
+				if w.ActiveWaiter {
+					dur = makeDurationInterval(w.WaitDuration)
+				}
+				if err := addLockRow(
+					w.WaitingTxn,
+					tree.NewDString(w.Strength.String()),
+					tree.DNull, /* durability */
+					false,      /* granted */
+					dur,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalClusterContentionEventsTable exposes the lock contention
+// encountered by requests on the cluster's stores, aggregated per table and
+// index.
+var crdbInternalClusterContentionEventsTable = virtualSchemaTable{
+	comment: "lock contention events aggregated per table and index (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.cluster_contention_events (
+  table_id                   INT NOT NULL,
+  index_id                   INT NOT NULL,
+  database_name              STRING,
+  table_name                 STRING,
+  index_name                 STRING,
+  num_contention_events      INT NOT NULL,
+  cumulative_contention_time INTERVAL NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.cluster_contention_events"); err != nil {
+			return err
+		}
+		ss, err := p.extendedEvalCtx.StatusServer.OptionalErr()
+		if err != nil {
+			return err
+		}
+		response, err := ss.ContentionEvents(ctx, &serverpb.ContentionEventsRequest{})
+		if err != nil {
+			return err
+		}
+		if len(response.Errors) > 0 {
+			rpcErr := response.Errors[0]
+			return errors.Errorf("could not retrieve contention events from node %d: %s", rpcErr.NodeID, rpcErr.Message)
+		}
+		names, err := getIndexNamesByID(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		// Each store aggregates its events separately, so combine them here.
+		type indexKey struct{ tableID, indexID uint32 }
+		var order []indexKey
+		events := make(map[indexKey]*kvserverpb.IndexContentionEvents)
+		for i := range response.Events {
+			e := &response.Events[i].Events
+			k := indexKey{tableID: e.TableID, indexID: e.IndexID}
+			agg, ok := events[k]
+			if !ok {
+				agg = &kvserverpb.IndexContentionEvents{TableID: e.TableID, IndexID: e.IndexID}
+				events[k] = agg
+				order = append(order, k)
+			}
+			agg.NumContentionEvents += e.NumContentionEvents
+			agg.CumulativeContentionTime += e.CumulativeContentionTime
+		}
+		sort.Slice(order, func(i, j int) bool {
+			if order[i].tableID != order[j].tableID {
+				return order[i].tableID < order[j].tableID
+			}
+			return order[i].indexID < order[j].indexID
+		})
+
+		for _, k := range order {
+			e := events[k]
+			dbName, tableName, indexName := tree.DNull, tree.DNull, tree.DNull
+			if tn, ok := names.tables[e.TableID]; ok {
+				dbName = tree.NewDString(names.databases[names.parents[e.TableID]])
+				tableName = tree.NewDString(tn)
+				if in, ok := names.indexes[e.TableID][e.IndexID]; ok {
+					indexName = tree.NewDString(in)
+				}
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(e.TableID)),
+				tree.NewDInt(tree.DInt(e.IndexID)),
+				dbName,
+				tableName,
+				indexName,
+				tree.NewDInt(tree.DInt(e.NumContentionEvents)),
+				makeDurationInterval(e.CumulativeContentionTime),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// indexNamesByID maps the IDs of all tables and indexes to their names, for
+// annotating key-level information returned by the KV layer.
+type indexNamesByID struct {
+	databases map[uint32]string
+	tables    map[uint32]string
+	parents   map[uint32]uint32
+	indexes   map[uint32]map[uint32]string
+}
+
+func getIndexNamesByID(ctx context.Context, p *planner) (indexNamesByID, error) {
+	descs, err := p.Tables().GetAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return indexNamesByID{}, err
+	}
+	names := indexNamesByID{
+		databases: make(map[uint32]string),
+		tables:    make(map[uint32]string),
+		parents:   make(map[uint32]uint32),
+		indexes:   make(map[uint32]map[uint32]string),
+	}
+	for _, desc := range descs {
+		id := uint32(desc.GetID())
+		switch desc := desc.(type) {
+		case *sqlbase.ImmutableTableDescriptor:
+			names.parents[id] = uint32(desc.ParentID)
+			names.tables[id] = desc.GetName()
+			names.indexes[id] = make(map[uint32]string)
+			for _, idx := range desc.AllNonDropIndexes() {
+				names.indexes[id][uint32(idx.ID)] = idx.Name
+			}
+		case *sqlbase.ImmutableDatabaseDescriptor:
+			names.databases[id] = desc.GetName()
+		}
+	}
+	return names, nil
+}
+
+func makeDurationInterval(d time.Duration) tree.Datum {
+	return tree.NewDInterval(
+		duration.MakeDuration(d.Nanoseconds(), 0 /* days */, 0 /* months */),
+		types.DefaultIntervalTypeMetadata,
+	)
+}
+
 // crdbInternalLocalMetricsTable exposes a snapshot of the metrics on the
 // current node.
 var crdbInternalLocalMetricsTable = virtualSchemaTable{
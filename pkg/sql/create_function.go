@@ -0,0 +1,280 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// createFunctionNode represents a CREATE FUNCTION statement.
+type createFunctionNode struct {
+	n *tree.CreateFunction
+	// body contains the function body, with all table names fully qualified.
+	body   string
+	dbDesc *sqlbase.ImmutableDatabaseDescriptor
+	// scName is the name of the schema of the function.
+	scName string
+
+	// planDeps tracks which tables and views the function body depends on.
+	// This is collected during the construction of the body's logical plan.
+	planDeps planDependencies
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.VersionUserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for function creation")
+	}
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+
+	name := n.n.Name.Object()
+	if n.dbDesc.GetID() == keys.SystemDatabaseID {
+		return errors.New("cannot create a function in the system database")
+	}
+	log.VEventf(params.ctx, 2, "dependencies for function %s:\n%s", name, n.planDeps.String())
+
+	overload, err := n.makeOverload(params)
+	if err != nil {
+		return err
+	}
+
+	codec := params.ExecCfg().Codec
+	found, schemaID, err := catalogkv.ResolveSchemaID(
+		params.ctx, params.p.txn, codec, n.dbDesc.GetID(), n.scName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.AssertionFailedf("schema %q of function %q not found", n.scName, name)
+	}
+	// Function names are resolved separately from the names of relations, so
+	// only other functions can collide with the new function. The database
+	// descriptor is read again, since the one used for planning may be stale.
+	dbDesc, err := catalogkv.MustGetDatabaseDescByID(params.ctx, params.p.txn, codec, n.dbDesc.GetID())
+	if err != nil {
+		return err
+	}
+	id, exists := dbDesc.FindFunction(schemaID, name)
+
+	var desc *sqlbase.MutableFunctionDescriptor
+	var oldDeps []sqlbase.ID
+	if exists {
+		existing, err := catalogkv.GetDescriptorByID(params.ctx, params.p.txn, codec, id)
+		if err != nil {
+			return err
+		}
+		fnDesc, ok := existing.(*sqlbase.ImmutableFunctionDescriptor)
+		if !ok {
+			return errors.AssertionFailedf("descriptor %d of function %q is not a function", id, name)
+		}
+		// Adding or replacing an overload requires the privilege to drop the
+		// existing function.
+		if err := params.p.CheckPrivilege(params.ctx, fnDesc, privilege.DROP); err != nil {
+			return err
+		}
+		desc = sqlbase.NewMutableExistingFunctionDescriptor(fnDesc.FunctionDescriptor)
+		oldDeps = desc.Dependencies()
+		if err := desc.AddOverload(overload, n.n.Replace); err != nil {
+			return err
+		}
+		desc.Version++
+		b := params.p.txn.NewBatch()
+		if err := catalogkv.WriteDescToBatch(
+			params.ctx, params.extendedEvalCtx.Tracing.KVTracingEnabled(),
+			params.ExecCfg().Settings, b, codec, desc.ID, desc,
+		); err != nil {
+			return err
+		}
+		if err := params.p.txn.Run(params.ctx, b); err != nil {
+			return err
+		}
+		if overload.Trigger {
+			// Refresh the copies of the body held by the triggers.
+			if err := params.p.updateTriggerBodies(params.ctx, desc); err != nil {
+				return err
+			}
+		}
+	} else {
+		id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.ExecCfg().DB, codec)
+		if err != nil {
+			return err
+		}
+		desc = sqlbase.NewMutableCreatedFunctionDescriptor(sqlbase.FunctionDescriptor{
+			Name:           name,
+			ID:             id,
+			Version:        1,
+			ParentID:       n.dbDesc.GetID(),
+			ParentSchemaID: schemaID,
+			Privileges:     sqlbase.NewDefaultFunctionPrivilegeDescriptor(),
+			Overloads:      []sqlbase.FunctionDescriptor_Overload{overload},
+		})
+		b := params.p.txn.NewBatch()
+		if err := catalogkv.WriteNewDescToBatch(
+			params.ctx, params.extendedEvalCtx.Tracing.KVTracingEnabled(),
+			params.ExecCfg().Settings, b, codec, desc.ID, desc,
+		); err != nil {
+			return err
+		}
+		if err := params.p.txn.Run(params.ctx, b); err != nil {
+			return err
+		}
+		if err := params.p.updateDatabaseFunctions(
+			params.ctx, dbDesc, func(mutDesc *sqlbase.MutableDatabaseDescriptor) {
+				mutDesc.AddFunction(schemaID, name, id)
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	// Persist the back-references in all referenced table descriptors.
+	return params.p.updateFunctionBackReferences(params.ctx, desc, oldDeps)
+}
+
+// makeOverload returns the overload defined by the CREATE FUNCTION statement.
+func (n *createFunctionNode) makeOverload(
+	params runParams,
+) (sqlbase.FunctionDescriptor_Overload, error) {
+	overload := sqlbase.FunctionDescriptor_Overload{
+		Args:       make([]sqlbase.FunctionDescriptor_Argument, len(n.n.Args)),
+		Volatility: int32(tree.VolatilityVolatile),
+		Body:       n.body,
+	}
+	if n.n.Options.Volatility != 0 {
+		overload.Volatility = int32(n.n.Options.Volatility)
+	}
+	if n.n.ReturnsTrigger() {
+		// Trigger functions have no arguments and are not called as regular
+		// functions, so they have no return type.
+		overload.Trigger = true
+		overload.ReturnType = types.Unknown
+		return overload, nil
+	}
+	for i := range n.n.Args {
+		typ, err := tree.ResolveType(params.ctx, n.n.Args[i].Type, params.p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return overload, err
+		}
+		overload.Args[i] = sqlbase.FunctionDescriptor_Argument{
+			Name: string(n.n.Args[i].Name),
+			Type: typ,
+		}
+	}
+	typ, err := tree.ResolveType(params.ctx, n.n.ReturnType, params.p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return overload, err
+	}
+	overload.ReturnType = typ
+	for id, dep := range n.planDeps {
+		if dep.desc.IsVirtualTable() {
+			continue
+		}
+		overload.DependsOn = append(overload.DependsOn, id)
+	}
+	return overload, nil
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
+
+// updateDatabaseFunctions applies fn to a new version of the given database
+// descriptor, which holds the names of the functions of the database, and
+// writes it.
+func (p *planner) updateDatabaseFunctions(
+	ctx context.Context,
+	dbDesc *sqlbase.ImmutableDatabaseDescriptor,
+	fn func(*sqlbase.MutableDatabaseDescriptor),
+) error {
+	mutDesc := sqlbase.NewMutableDatabaseDescriptor(*dbDesc.DatabaseDesc())
+	fn(mutDesc)
+	b := p.txn.NewBatch()
+	if err := catalogkv.WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(),
+		p.ExecCfg().Settings, b, p.ExecCfg().Codec, mutDesc.GetID(), mutDesc,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// updateFunctionBackReferences updates the back-references from the tables
+// that the function depended on, as given by oldDeps, to the tables that the
+// function depends on now.
+func (p *planner) updateFunctionBackReferences(
+	ctx context.Context, desc *sqlbase.MutableFunctionDescriptor, oldDeps []sqlbase.ID,
+) error {
+	newDeps := desc.Dependencies()
+	isDep := func(ids []sqlbase.ID, id sqlbase.ID) bool {
+		for _, other := range ids {
+			if other == id {
+				return true
+			}
+		}
+		return false
+	}
+	update := func(tableID sqlbase.ID, add bool) error {
+		tableDesc, err := p.Tables().GetMutableTableVersionByID(ctx, tableID, p.txn)
+		if err != nil {
+			return err
+		}
+		refs := tableDesc.DependedOnByFunctions[:0]
+		for _, ref := range tableDesc.DependedOnByFunctions {
+			if ref != desc.ID {
+				refs = append(refs, ref)
+			}
+		}
+		if add {
+			refs = append(refs, desc.ID)
+		}
+		tableDesc.DependedOnByFunctions = refs
+		return p.writeSchemaChange(
+			ctx, tableDesc, sqlbase.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)",
+				desc.Name, tableDesc.Name, tableDesc.ID),
+		)
+	}
+	for _, id := range oldDeps {
+		if !isDep(newDeps, id) {
+			if err := update(id, false /* add */); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range newDeps {
+		if !isDep(oldDeps, id) {
+			if err := update(id, true /* add */); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -51,6 +51,11 @@ func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNod
 		return nil, err
 	}
 
+	if tableDesc.IsForeignTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot create index on foreign table %q", tableDesc.Name)
+	}
+
 	return &createIndexNode{tableDesc: tableDesc, n: n}, nil
 }
 
@@ -48,6 +48,9 @@ type createTableNode struct {
 	n          *tree.CreateTable
 	dbDesc     *sqlbase.ImmutableDatabaseDescriptor
 	sourcePlan planNode
+	// foreign is set when creating a foreign table, whose rows are read from
+	// external storage.
+	foreign *sqlbase.TableDescriptor_ForeignTable
 
 	run createTableRun
 }
@@ -79,6 +82,8 @@ const (
 	storageParamBool storageParamType = iota
 	storageParamInt
 	storageParamFloat
+	storageParamString
+	storageParamInterval
 	storageParamUnimplemented
 )
 
@@ -112,6 +117,13 @@ var storageParamExpectedTypes = map[string]storageParamType{
 	`log_autovacuum_min_duration`:                 storageParamUnimplemented,
 	`toast.log_autovacuum_min_duration`:           storageParamUnimplemented,
 	`user_catalog_table`:                          storageParamUnimplemented,
+	ttlExpireAfterParam:                           storageParamInterval,
+	ttlColumnParam:                                storageParamString,
+	ttlJobCronParam:                               storageParamString,
+	ttlSelectBatchSizeParam:                       storageParamInt,
+	ttlDeleteBatchSizeParam:                       storageParamInt,
+	ttlRangeConcurrencyParam:                      storageParamInt,
+	ttlDeleteRateLimitParam:                       storageParamInt,
 }
 
 // minimumTypeUsageVersions defines the minimum version needed for a new
@@ -120,6 +132,8 @@ var minimumTypeUsageVersions = map[types.Family]clusterversion.VersionKey{
 	types.TimeTZFamily:    clusterversion.VersionTimeTZType,
 	types.GeographyFamily: clusterversion.VersionGeospatialType,
 	types.GeometryFamily:  clusterversion.VersionGeospatialType,
+	types.TSQueryFamily:   clusterversion.VersionTextSearch,
+	types.TSVectorFamily:  clusterversion.VersionTextSearch,
 }
 
 // isTypeSupportedInVersion returns whether a given type is supported in the given version.
@@ -146,6 +160,36 @@ func isTypeSupportedInVersion(v clusterversion.ClusterVersion, t *types.T) (bool
 	return v.IsActive(minVersion), nil
 }
 
+// checkColumnDefSupportedInVersion returns an error if the given column
+// definition uses a kind of column that is not supported in the given version.
+func checkColumnDefSupportedInVersion(
+	v clusterversion.ClusterVersion, d *tree.ColumnTableDef,
+) error {
+	if (d.IsVirtual() || d.IsGeneratedAsIdentity()) &&
+		!v.IsActive(clusterversion.VersionVirtualAndIdentityColumns) {
+		kind := "virtual computed"
+		if d.IsGeneratedAsIdentity() {
+			kind = "identity"
+		}
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s columns are not supported until version upgrade is finalized", kind)
+	}
+	return nil
+}
+
+// checkFKSupportedInVersion returns an error if the given foreign key
+// definition is DEFERRABLE and the given version does not support it.
+func checkFKSupportedInVersion(
+	v clusterversion.ClusterVersion, d *tree.ForeignKeyConstraintTableDef,
+) error {
+	if d.Deferrability != tree.NotDeferrable &&
+		!v.IsActive(clusterversion.VersionDeferrableConstraints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"deferrable constraints are not supported until version upgrade is finalized")
+	}
+	return nil
+}
+
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
 // This is because CREATE TABLE performs multiple KV operations on descriptors
 // and expects to see its own writes.
@@ -211,7 +255,11 @@ func getTableCreateParams(
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	if n.foreign != nil {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("foreign_table"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	}
 	isTemporary := n.n.Temporary
 
 	tKey, schemaID, err := getTableCreateParams(params, n.dbDesc.GetID(), isTemporary, n.n.Table.Table())
@@ -305,6 +353,7 @@ func (n *createTableNode) startExec(params runParams) error {
 		if err != nil {
 			return err
 		}
+		desc.ForeignTable = n.foreign
 
 		if desc.Adding() {
 			// if this table and all its references are created in the same
@@ -326,6 +375,14 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if changed, err := params.p.setRowLevelTTLStorageParams(params.ctx, &desc, n.n.StorageParams); err != nil {
+		return err
+	} else if changed {
+		if err := params.p.updateRowLevelTTLSchedule(params.ctx, &desc, 0 /* oldScheduleID */); err != nil {
+			return err
+		}
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx, tKey.Key(params.ExecCfg().Codec), id, &desc, params.EvalContext().Settings,
@@ -508,6 +565,10 @@ func (p *planner) resolveFK(
 	ts FKTableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
+	version := p.ExecCfg().Settings.Version.ActiveVersion(ctx)
+	if err := checkFKSupportedInVersion(version, d); err != nil {
+		return err
+	}
 	return ResolveFK(ctx, p.txn, p, tbl, d, backrefs, ts, validationBehavior, p.EvalContext())
 }
 
@@ -648,6 +709,10 @@ func ResolveFK(
 			tablePersistenceType,
 		)
 	}
+	if target.IsForeignTable() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"foreign key constraints cannot reference foreign table %q", target.Name)
+	}
 	if target.ID == tbl.ID {
 		// When adding a self-ref FK to an _existing_ table, we want to make sure
 		// we edit the same copy.
@@ -821,6 +886,8 @@ func ResolveFK(
 		Match:                 sqlbase.CompositeKeyMatchMethodValue[d.Match],
 		LegacyOriginIndex:     legacyOriginIndexID,
 		LegacyReferencedIndex: legacyReferencedIndexID,
+		Deferrable:            d.Deferrability != tree.NotDeferrable,
+		InitiallyDeferred:     d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -1250,6 +1317,9 @@ func MakeTableDesc(
 					defType.SQLString(),
 				)
 			}
+			if err := checkColumnDefSupportedInVersion(version, d); err != nil {
+				return desc, err
+			}
 			if d.PrimaryKey.Sharded {
 				// This function can sometimes be called when `st` is nil,
 				// and also before the version has been initialized. We only
@@ -1656,6 +1726,9 @@ func MakeTableDesc(
 			desc.Checks = append(desc.Checks, ck)
 
 		case *tree.ForeignKeyConstraintTableDef:
+			if err := checkFKSupportedInVersion(version, d); err != nil {
+				return desc, err
+			}
 			if err := ResolveFK(
 				ctx, txn, fkResolver, &desc, d, affected, NewTable, tree.ValidationDefault, evalCtx,
 			); err != nil {
@@ -1732,6 +1805,10 @@ func checkStorageParameters(
 			expectedType = types.Int
 		} else if validate == storageParamFloat {
 			expectedType = types.Float
+		} else if validate == storageParamString {
+			expectedType = types.String
+		} else if validate == storageParamInterval {
+			expectedType = types.Interval
 		} else {
 			return unimplemented.NewWithIssuef(43299, "storage parameter %q", k)
 		}
@@ -1900,6 +1977,7 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 			if c.ComputeExpr != nil {
 				if opts.Has(tree.LikeTableOptGenerated) {
 					def.Computed.Computed = true
+					def.Computed.Virtual = c.Virtual
 					def.Computed.Expr, err = parser.ParseExpr(*c.ComputeExpr)
 					if err != nil {
 						return nil, err
@@ -2079,6 +2157,12 @@ func incTelemetryForNewColumn(d *tree.ColumnTableDef) {
 	if d.IsComputed() {
 		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("computed"))
 	}
+	if d.IsVirtual() {
+		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("virtual"))
+	}
+	if d.IsGeneratedAsIdentity() {
+		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("identity"))
+	}
 	if d.HasDefaultExpr() {
 		telemetry.Inc(sqltelemetry.SchemaNewColumnTypeQualificationCounter("default_expr"))
 	}
@@ -0,0 +1,309 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+	fnDesc    *sqlbase.MutableFunctionDescriptor
+}
+
+// CreateTrigger creates a trigger.
+// Privileges: CREATE on table, EXECUTE on function.
+//   Notes: postgres requires TRIGGER on the table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionTriggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for trigger creation")
+	}
+
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, resolver.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if tableDesc.FindTriggerByName(string(n.Name)) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %q for relation %q already exists", n.Name, tableDesc.Name)
+	}
+
+	fnDesc, err := p.resolveTriggerFunction(ctx, n.FuncName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, fnDesc: fnDesc}, nil
+}
+
+// resolveTriggerFunction returns the descriptor of the trigger function with
+// the given name.
+func (p *planner) resolveTriggerFunction(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) (*sqlbase.MutableFunctionDescriptor, error) {
+	desc, err := p.findFunction(ctx, name.NumParts, name.Parts[:], p.CurrentSearchPath())
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, pgerror.Newf(pgcode.UndefinedFunction,
+			"function %s() does not exist", tree.ErrString(name))
+	}
+	if desc.TriggerOverload() == nil {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", tree.ErrString(name))
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	return sqlbase.NewMutableExistingFunctionDescriptor(desc.FunctionDescriptor), nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	trigger := sqlbase.TriggerDescriptor{
+		Name:       string(n.n.Name),
+		ActionTime: sqlbase.TriggerDescriptor_BEFORE,
+		OnInsert:   n.n.Events.Contains(tree.TriggerEventInsert),
+		OnUpdate:   n.n.Events.Contains(tree.TriggerEventUpdate),
+		OnDelete:   n.n.Events.Contains(tree.TriggerEventDelete),
+		FunctionID: n.fnDesc.ID,
+		Body:       n.fnDesc.TriggerOverload().Body,
+	}
+	if n.n.ActionTime == tree.TriggerAfter {
+		trigger.ActionTime = sqlbase.TriggerDescriptor_AFTER
+	}
+
+	// Make sure that the body of the function is valid for the table.
+	if _, err := makeOptTrigger(n.tableDesc.TableDesc(), &trigger); err != nil {
+		return err
+	}
+
+	n.tableDesc.AddTrigger(trigger)
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, sqlbase.InvalidMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	return params.p.updateTriggerBackReference(params.ctx, n.fnDesc.ID, n.tableDesc.ID, true /* add */)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
+
+// updateTriggerBackReference adds or removes the back-reference from the
+// trigger function with the given ID to the table with the given ID.
+func (p *planner) updateTriggerBackReference(
+	ctx context.Context, fnID, tableID sqlbase.ID, add bool,
+) error {
+	desc, err := catalogkv.GetDescriptorByID(ctx, p.txn, p.ExecCfg().Codec, fnID)
+	if err != nil {
+		return err
+	}
+	fnDesc, ok := desc.(*sqlbase.ImmutableFunctionDescriptor)
+	if !ok {
+		return errors.AssertionFailedf("descriptor %d is not a function", fnID)
+	}
+	mutDesc := sqlbase.NewMutableExistingFunctionDescriptor(fnDesc.FunctionDescriptor)
+	refs := mutDesc.DependedOnBy[:0]
+	for _, ref := range mutDesc.DependedOnBy {
+		if ref != tableID {
+			refs = append(refs, ref)
+		}
+	}
+	if add {
+		refs = append(refs, tableID)
+	}
+	mutDesc.DependedOnBy = refs
+	mutDesc.Version++
+	b := p.txn.NewBatch()
+	if err := catalogkv.WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(),
+		p.ExecCfg().Settings, b, p.ExecCfg().Codec, mutDesc.ID, mutDesc,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// updateTriggerBodies refreshes the copies of the body of the given trigger
+// function held by the triggers that execute it, after the function was
+// replaced.
+func (p *planner) updateTriggerBodies(
+	ctx context.Context, desc *sqlbase.MutableFunctionDescriptor,
+) error {
+	body := desc.TriggerOverload().Body
+	for _, tableID := range desc.DependedOnBy {
+		tableDesc, err := p.Tables().GetMutableTableVersionByID(ctx, tableID, p.txn)
+		if err != nil {
+			return err
+		}
+		for i := range tableDesc.Triggers {
+			trigger := &tableDesc.Triggers[i]
+			if trigger.FunctionID != desc.ID {
+				continue
+			}
+			trigger.Body = body
+			if _, err := makeOptTrigger(tableDesc.TableDesc(), trigger); err != nil {
+				return err
+			}
+		}
+		if err := p.writeSchemaChange(
+			ctx, tableDesc, sqlbase.InvalidMutationID,
+			fmt.Sprintf("updating triggers executing function %q in table %s(%d)",
+				desc.Name, tableDesc.Name, tableDesc.ID),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeOptTrigger returns the catalog representation of the given trigger of
+// the table. References to the columns of the NEW and OLD rows in the body of
+// the trigger are replaced with placeholders, as described in cat.Trigger.
+func makeOptTrigger(
+	desc *sqlbase.TableDescriptor, trigger *sqlbase.TriggerDescriptor,
+) (cat.Trigger, error) {
+	res := cat.Trigger{Name: tree.Name(trigger.Name)}
+	res.ActionTime, res.Events = optTriggerEvents(trigger)
+
+	stmt, err := parser.ParseOne(trigger.Body)
+	if err != nil {
+		return cat.Trigger{}, err
+	}
+	v := triggerRowVisitor{desc: desc}
+	newStmt, _ := tree.WalkStmt(&v, stmt.AST)
+	if v.err != nil {
+		return cat.Trigger{}, v.err
+	}
+	res.Body = tree.Serialize(newStmt)
+	res.ReturnsRows = newStmt.StatementType() == tree.Rows
+	return res, nil
+}
+
+// optTriggerEvents returns the action time and the events of the given
+// trigger.
+func optTriggerEvents(
+	trigger *sqlbase.TriggerDescriptor,
+) (tree.TriggerActionTime, tree.TriggerEvents) {
+	actionTime := tree.TriggerBefore
+	if trigger.ActionTime == sqlbase.TriggerDescriptor_AFTER {
+		actionTime = tree.TriggerAfter
+	}
+	var events tree.TriggerEvents
+	if trigger.OnInsert {
+		events = append(events, tree.TriggerEventInsert)
+	}
+	if trigger.OnUpdate {
+		events = append(events, tree.TriggerEventUpdate)
+	}
+	if trigger.OnDelete {
+		events = append(events, tree.TriggerEventDelete)
+	}
+	return actionTime, events
+}
+
+// triggerRowVisitor replaces the references to the columns of the NEW and OLD
+// rows of a trigger with placeholders.
+type triggerRowVisitor struct {
+	desc *sqlbase.TableDescriptor
+	err  error
+}
+
+var _ tree.Visitor = &triggerRowVisitor{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *triggerRowVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	name, ok := expr.(*tree.UnresolvedName)
+	if !ok || name.NumParts != 2 {
+		return true, expr
+	}
+	var offset int
+	switch strings.ToLower(name.Parts[1]) {
+	case "new":
+	case "old":
+		offset = len(v.desc.Columns)
+	default:
+		return true, expr
+	}
+
+	if name.Star {
+		// NEW.* expands to the visible columns of the row.
+		var tuple tree.Tuple
+		for i := range v.desc.Columns {
+			col := &v.desc.Columns[i]
+			if col.Hidden {
+				continue
+			}
+			tuple.Exprs = append(tuple.Exprs, v.columnRef(offset+i, col))
+			tuple.Labels = append(tuple.Labels, col.Name)
+		}
+		return false, &tree.TupleStar{Expr: &tuple}
+	}
+	for i := range v.desc.Columns {
+		col := &v.desc.Columns[i]
+		if col.Name == name.Parts[0] {
+			return false, v.columnRef(offset+i, col)
+		}
+	}
+	v.err = pgerror.Newf(pgcode.UndefinedColumn,
+		"record %q has no field %q", strings.ToLower(name.Parts[1]), name.Parts[0])
+	return false, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (*triggerRowVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// columnRef returns the placeholder for the given column of the NEW or OLD
+// row.
+func (v *triggerRowVisitor) columnRef(idx int, col *sqlbase.ColumnDescriptor) tree.Expr {
+	return &tree.CastExpr{
+		Expr:       &tree.Placeholder{Idx: tree.PlaceholderIdx(idx)},
+		Type:       col.Type,
+		SyntaxMode: tree.CastShort,
+	}
+}
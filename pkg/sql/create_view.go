@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -32,12 +33,13 @@ type createViewNode struct {
 	viewName tree.Name
 	// viewQuery contains the view definition, with all table names fully
 	// qualified.
-	viewQuery   string
-	ifNotExists bool
-	replace     bool
-	temporary   bool
-	dbDesc      *sqlbase.ImmutableDatabaseDescriptor
-	columns     sqlbase.ResultColumns
+	viewQuery    string
+	ifNotExists  bool
+	replace      bool
+	temporary    bool
+	materialized bool
+	dbDesc       *sqlbase.ImmutableDatabaseDescriptor
+	columns      sqlbase.ResultColumns
 
 	// planDeps tracks which tables and views the view being created
 	// depends on. This is collected during the construction of
@@ -51,7 +53,17 @@ type createViewNode struct {
 func (n *createViewNode) ReadingOwnWrites() {}
 
 func (n *createViewNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	if n.materialized {
+		// Make sure that all nodes in the cluster are able to recognize
+		// materialized views.
+		if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.VersionMaterializedViews) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"not all nodes are the correct version for materialized view creation")
+		}
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("materialized_view"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("view"))
+	}
 
 	viewName := string(n.viewName)
 	isTemporary := n.temporary
@@ -102,6 +114,10 @@ func (n *createViewNode) startExec(params runParams) error {
 			if !desc.IsView() {
 				return pgerror.Newf(pgcode.WrongObjectType, `%q is not a view`, viewName)
 			}
+			if desc.MaterializedView() {
+				return pgerror.Newf(pgcode.WrongObjectType,
+					`%q is a materialized view and cannot be replaced`, viewName)
+			}
 			replacingDesc = desc
 		default:
 			return err
@@ -146,6 +162,7 @@ func (n *createViewNode) startExec(params runParams) error {
 			&params.p.semaCtx,
 			params.p.EvalContext(),
 			isTemporary,
+			n.materialized,
 		)
 		if err != nil {
 			return err
@@ -158,9 +175,13 @@ func (n *createViewNode) startExec(params runParams) error {
 
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
+		createStmt := "CREATE VIEW"
+		if n.materialized {
+			createStmt = "CREATE MATERIALIZED VIEW"
+		}
 		if err = params.p.createDescriptorWithID(
 			params.ctx, tKey.Key(params.ExecCfg().Codec), id, &desc, params.EvalContext().Settings,
-			fmt.Sprintf("CREATE VIEW %q AS %q", n.viewName, n.viewQuery),
+			fmt.Sprintf("%s %q AS %q", createStmt, n.viewName, n.viewQuery),
 		); err != nil {
 			return err
 		}
@@ -234,6 +255,10 @@ func (n *createViewNode) Close(ctx context.Context)  {}
 // dependencies in the same transaction that the view is created and it
 // doesn't matter if reads/writes use a cached descriptor that doesn't
 // include the back-references.
+//
+// The exception is materialized views, which are created in the ADDING
+// state so that the schema changer can populate them with the results of
+// the view query, in the same way as CREATE TABLE ... AS.
 func makeViewTableDesc(
 	ctx context.Context,
 	viewName string,
@@ -247,6 +272,7 @@ func makeViewTableDesc(
 	semaCtx *tree.SemaContext,
 	evalCtx *tree.EvalContext,
 	temporary bool,
+	isMaterialized bool,
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := sqlbase.InitTableDescriptor(
 		id,
@@ -258,6 +284,13 @@ func makeViewTableDesc(
 		temporary,
 	)
 	desc.ViewQuery = viewQuery
+	if isMaterialized {
+		// Materialized views are stored like tables, so marking the view as
+		// materialized before its columns are added ensures that it gets a
+		// hidden rowid primary key.
+		desc.IsMaterializedView = true
+		desc.State = sqlbase.TableDescriptor_ADD
+	}
 	if err := addResultColumns(ctx, semaCtx, evalCtx, &desc, resultColumns); err != nil {
 		return sqlbase.MutableTableDescriptor{}, err
 	}
@@ -0,0 +1,432 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// deferredConstraint identifies a DEFERRABLE foreign key constraint.
+type deferredConstraint struct {
+	// tableID is the ID of the origin (referencing) table of the constraint.
+	tableID sqlbase.ID
+	name    string
+	// initiallyDeferred is set if the constraint is INITIALLY DEFERRED.
+	initiallyDeferred bool
+}
+
+// constraintMode is the checking mode of deferrable constraints, as set by
+// SET CONSTRAINTS.
+type constraintMode int8
+
+const (
+	// constraintModeDefault indicates that the checking mode of a constraint is
+	// given by its definition (INITIALLY DEFERRED or INITIALLY IMMEDIATE).
+	constraintModeDefault constraintMode = iota
+	constraintModeImmediate
+	constraintModeDeferred
+)
+
+// deferredConstraints holds the transaction-scoped state of the deferrable
+// constraints: the checking modes set with SET CONSTRAINTS, and the checks
+// deferred until the transaction commits.
+//
+// While a constraint is deferred, the check queries of the statements are run
+// as usual, but the keys of the violations they find are recorded instead of
+// failing the statement. At commit time, only those keys are checked again;
+// a violation may have been fixed by a later statement of the transaction,
+// which is the point of deferring the checks (e.g. to insert rows in tables
+// referencing each other). Rows that did not violate the constraint when they
+// were written can only start violating it through a later write, whose own
+// check records the violation.
+type deferredConstraints struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintMode
+	// modes are the modes set by SET CONSTRAINTS for constraints named
+	// explicitly. They override allMode.
+	modes map[string]constraintMode
+	// pending are the constraints whose checks have been deferred.
+	pending []*pendingCheck
+}
+
+// pendingCheck holds the violations of a deferred constraint found so far.
+type pendingCheck struct {
+	constraint deferredConstraint
+	// keys is the set of the values of the foreign key columns of the
+	// violations, formatted as SQL tuples of parsable datums. The value is true
+	// if the key contains a NULL, which can only happen for MATCH FULL
+	// constraints.
+	// TODO(sqlexec): account for the memory used by the keys.
+	keys map[string]bool
+}
+
+// addKey records a violation of the constraint with the given key.
+func (c *pendingCheck) addKey(keyVals tree.Datums) {
+	hasNull := false
+	typs := make([]*types.T, len(keyVals))
+	for i, d := range keyVals {
+		if d == tree.DNull {
+			hasNull = true
+		}
+		typs[i] = d.ResolvedType()
+	}
+	tuple := tree.NewDTuple(types.MakeTuple(typs), keyVals...)
+	c.keys[tree.AsStringWithFlags(tuple, tree.FmtParsable)] = hasNull
+}
+
+// isDeferred returns whether the checking of the given constraint is
+// currently deferred until the transaction commits.
+func (d *deferredConstraints) isDeferred(c deferredConstraint) bool {
+	mode, ok := d.modes[c.name]
+	if !ok {
+		mode = d.allMode
+	}
+	switch mode {
+	case constraintModeImmediate:
+		return false
+	case constraintModeDeferred:
+		return true
+	default:
+		return c.initiallyDeferred
+	}
+}
+
+// setMode applies a SET CONSTRAINTS statement.
+func (d *deferredConstraints) setMode(n *tree.SetConstraints) {
+	mode := constraintModeImmediate
+	if n.Deferred {
+		mode = constraintModeDeferred
+	}
+	if n.All {
+		d.allMode = mode
+		d.modes = nil
+		return
+	}
+	if d.modes == nil {
+		d.modes = make(map[string]constraintMode, len(n.Names))
+	}
+	for _, name := range n.Names {
+		d.modes[string(name)] = mode
+	}
+}
+
+// get returns the pending checks of the given constraint, creating them if
+// necessary.
+func (d *deferredConstraints) get(c deferredConstraint) *pendingCheck {
+	for _, p := range d.pending {
+		if p.constraint == c {
+			return p
+		}
+	}
+	p := &pendingCheck{constraint: c, keys: make(map[string]bool)}
+	d.pending = append(d.pending, p)
+	return p
+}
+
+// takeImmediate removes and returns the pending checks of the constraints that
+// are no longer deferred, after a SET CONSTRAINTS ... IMMEDIATE.
+func (d *deferredConstraints) takeImmediate() []*pendingCheck {
+	var res []*pendingCheck
+	pending := d.pending[:0]
+	for _, p := range d.pending {
+		if d.isDeferred(p.constraint) {
+			pending = append(pending, p)
+		} else {
+			res = append(res, p)
+		}
+	}
+	d.pending = pending
+	return res
+}
+
+// clone returns a copy of d, which is not affected by further changes to d.
+func (d *deferredConstraints) clone() deferredConstraints {
+	res := deferredConstraints{allMode: d.allMode}
+	if d.modes != nil {
+		res.modes = make(map[string]constraintMode, len(d.modes))
+		for name, mode := range d.modes {
+			res.modes[name] = mode
+		}
+	}
+	res.pending = make([]*pendingCheck, len(d.pending))
+	for i, p := range d.pending {
+		keys := make(map[string]bool, len(p.keys))
+		for k, hasNull := range p.keys {
+			keys[k] = hasNull
+		}
+		res.pending[i] = &pendingCheck{constraint: p.constraint, keys: keys}
+	}
+	return res
+}
+
+// reset clears the state when the transaction finishes.
+func (d *deferredConstraints) reset() {
+	*d = deferredConstraints{}
+}
+
+// maybeDeferCheck returns the pending checks of the given DEFERRABLE foreign
+// key constraint if its checking is currently deferred, or nil otherwise.
+// Checks are never deferred in implicit transactions, where the end of the
+// statement is also the end of the transaction.
+func (p *planner) maybeDeferCheck(fk cat.ForeignKeyConstraint) *pendingCheck {
+	d := p.extendedEvalCtx.DeferredConstraints
+	if fk == nil || d == nil || p.extendedEvalCtx.TxnImplicit {
+		return nil
+	}
+	c := deferredConstraint{
+		tableID:           sqlbase.ID(fk.OriginTableID()),
+		name:              fk.Name(),
+		initiallyDeferred: fk.Deferrability() == tree.DeferrableInitiallyDeferred,
+	}
+	if !d.isDeferred(c) {
+		return nil
+	}
+	return d.get(c)
+}
+
+// deferredCheckBatchSize is the maximum number of keys checked by a single
+// query when validating deferred constraints.
+const deferredCheckBatchSize = 1000
+
+// validateDeferredConstraints checks whether the violations recorded by the
+// given pending checks still exist. Uncommitted descriptor changes made by the
+// transaction in tc are made visible to the validation queries.
+func validateDeferredConstraints(
+	ctx context.Context,
+	pending []*pendingCheck,
+	tc *descs.Collection,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	codec keys.SQLCodec,
+) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	ie.tcModifier = tc
+	defer func() {
+		ie.tcModifier = nil
+	}()
+
+	getTableDesc := func(id sqlbase.ID) (*sqlbase.TableDescriptor, error) {
+		if mutDesc := tc.GetUncommittedTableByID(id).MutableTableDescriptor; mutDesc != nil {
+			return mutDesc.TableDesc(), nil
+		}
+		return sqlbase.GetTableDescFromID(ctx, txn, codec, id)
+	}
+	for _, p := range pending {
+		if len(p.keys) == 0 {
+			continue
+		}
+		srcTable, err := getTableDesc(p.constraint.tableID)
+		if err != nil {
+			return err
+		}
+		if srcTable.Dropped() {
+			continue
+		}
+		var fk *sqlbase.ForeignKeyConstraint
+		for i := range srcTable.OutboundFKs {
+			if srcTable.OutboundFKs[i].Name == p.constraint.name {
+				fk = &srcTable.OutboundFKs[i]
+				break
+			}
+		}
+		if fk == nil {
+			// The constraint was dropped later in the transaction.
+			continue
+		}
+		targetTable, err := getTableDesc(fk.ReferencedTableID)
+		if err != nil {
+			return err
+		}
+
+		// Check the keys in a deterministic order, so that the violation
+		// reported does not depend on the map iteration order.
+		var tuples, nullTuples []string
+		for k, hasNull := range p.keys {
+			if hasNull {
+				nullTuples = append(nullTuples, k)
+			} else {
+				tuples = append(tuples, k)
+			}
+		}
+		sort.Strings(tuples)
+		sort.Strings(nullTuples)
+		log.VEventf(ctx, 2, "validating %d keys of deferred constraint %q of table %q",
+			len(p.keys), fk.Name, srcTable.Name)
+
+		if len(nullTuples) > 0 {
+			// A MATCH FULL key mixing NULL and non-NULL values is a violation
+			// as long as a row with that key exists.
+			query, colNames, err := deferredMatchFullQuery(srcTable, fk, nullTuples)
+			if err != nil {
+				return err
+			}
+			values, err := ie.QueryRow(ctx, "validate deferred fk constraint", txn, query)
+			if err != nil {
+				return err
+			}
+			if values.Len() > 0 {
+				return pgerror.Newf(pgcode.ForeignKeyViolation,
+					"foreign key violation: MATCH FULL does not allow mixing of null and nonnull values %s for %s",
+					formatValues(colNames, values), fk.Name,
+				)
+			}
+		}
+		for len(tuples) > 0 {
+			batch := tuples
+			if len(batch) > deferredCheckBatchSize {
+				batch = batch[:deferredCheckBatchSize]
+			}
+			tuples = tuples[len(batch):]
+			query, colNames, err := deferredNonMatchingRowQuery(srcTable, fk, targetTable, batch)
+			if err != nil {
+				return err
+			}
+			values, err := ie.QueryRow(ctx, "validate deferred fk constraint", txn, query)
+			if err != nil {
+				return err
+			}
+			if values.Len() > 0 {
+				return pgerror.Newf(pgcode.ForeignKeyViolation,
+					"foreign key violation: %q row %s has no match in %q",
+					srcTable.Name, formatValues(colNames, values), targetTable.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// deferredNonMatchingRowQuery returns a query for a row of the referencing
+// table which has one of the given keys and has no match in the referenced
+// table. The keys are formatted SQL tuples of the values of the foreign key
+// columns, none of which is NULL.
+func deferredNonMatchingRowQuery(
+	srcTbl *sqlbase.TableDescriptor,
+	fk *sqlbase.ForeignKeyConstraint,
+	targetTbl *sqlbase.TableDescriptor,
+	tuples []string,
+) (sql string, originColNames []string, _ error) {
+	originColNames, err := fkOriginColNames(srcTbl, fk)
+	if err != nil {
+		return "", nil, err
+	}
+	referencedColNames, err := targetTbl.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	nCols := len(fk.OriginColumnIDs)
+	srcCols := make([]string, nCols)
+	on := make([]string, nCols)
+	for i := 0; i < nCols; i++ {
+		// s and t are table aliases used in the query.
+		srcCols[i] = fmt.Sprintf("s.%s", tree.NameString(originColNames[i]))
+		on[i] = fmt.Sprintf("t.%s = %s", tree.NameString(referencedColNames[i]), srcCols[i])
+	}
+	returnedCols := make([]string, len(originColNames))
+	for i, n := range originColNames {
+		returnedCols[i] = fmt.Sprintf("s.%s", tree.NameString(n))
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS s]@{IGNORE_FOREIGN_KEYS}
+		  WHERE %[3]s IN (%[4]s)
+		  AND NOT EXISTS (SELECT 1 FROM [%[5]d AS t] WHERE %[6]s)
+		  LIMIT 1`,
+		strings.Join(returnedCols, ", "), // 1
+		srcTbl.ID,                        // 2
+		formatTuple(srcCols),             // 3
+		strings.Join(tuples, ", "),       // 4
+		targetTbl.ID,                     // 5
+		strings.Join(on, " AND "),        // 6
+	), originColNames, nil
+}
+
+// deferredMatchFullQuery returns a query for a row of the referencing table
+// which has one of the given keys, all of which mix NULL and non-NULL values.
+func deferredMatchFullQuery(
+	srcTbl *sqlbase.TableDescriptor, fk *sqlbase.ForeignKeyConstraint, tuples []string,
+) (sql string, originColNames []string, _ error) {
+	originColNames, err := fkOriginColNames(srcTbl, fk)
+	if err != nil {
+		return "", nil, err
+	}
+	srcCols := make([]string, len(fk.OriginColumnIDs))
+	for i := range srcCols {
+		srcCols[i] = tree.NameString(originColNames[i])
+	}
+	returnedCols := make([]string, len(originColNames))
+	for i, n := range originColNames {
+		returnedCols[i] = tree.NameString(n)
+	}
+	keys := make([]string, len(tuples))
+	for i, t := range tuples {
+		keys[i] = fmt.Sprintf("%s IS NOT DISTINCT FROM %s", formatTuple(srcCols), t)
+	}
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS s]@{IGNORE_FOREIGN_KEYS} WHERE %[3]s LIMIT 1`,
+		strings.Join(returnedCols, ", "), // 1
+		srcTbl.ID,                        // 2
+		strings.Join(keys, " OR "),       // 3
+	), originColNames, nil
+}
+
+// formatTuple formats a tuple of the given expressions the same way as a
+// DTuple, so that a tuple with a single element is not ambiguous with a
+// parenthesized expression.
+func formatTuple(exprs []string) string {
+	if len(exprs) == 1 {
+		return "(" + exprs[0] + ",)"
+	}
+	return "(" + strings.Join(exprs, ", ") + ")"
+}
+
+// fkOriginColNames returns the names of the origin columns of the given foreign
+// key, followed by the names of the primary key columns of the table which are
+// not part of the foreign key.
+func fkOriginColNames(
+	srcTbl *sqlbase.TableDescriptor, fk *sqlbase.ForeignKeyConstraint,
+) ([]string, error) {
+	names, err := srcTbl.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkColID := range srcTbl.PrimaryIndex.ColumnIDs {
+		found := false
+		for _, id := range fk.OriginColumnIDs {
+			if pkColID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			column, err := srcTbl.FindActiveColumnByID(pkColID)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, column.Name)
+		}
+	}
+	return names, nil
+}
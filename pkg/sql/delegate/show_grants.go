@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 // delegateShowGrants implements SHOW GRANTS which returns grant details for the
@@ -46,6 +47,10 @@ FROM "".information_schema.table_privileges`
 	var cond bytes.Buffer
 	var orderBy string
 
+	if n.Targets != nil && n.Targets.Functions != nil {
+		return nil, unimplemented.NewWithIssueDetail(17511, "show grants on function", "SHOW GRANTS ON FUNCTION")
+	}
+
 	if n.Targets != nil && n.Targets.Databases != nil {
 		// Get grants of database from information_schema.schema_privileges
 		// if the type of target is database.
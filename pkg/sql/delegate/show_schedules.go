@@ -0,0 +1,72 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+func (d *delegator) delegateShowSchedules(n *tree.ShowSchedules) (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.Schedules)
+
+	columnExprs := []string{
+		"id",
+		"label",
+		"schedule_status",
+		"next_run",
+		"recurrence",
+		"jobsRunning",
+		"owner",
+		"created",
+	}
+
+	var whereExprs []string
+
+	switch n.WhichSchedules {
+	case tree.PausedSchedules:
+		whereExprs = append(whereExprs, "next_run IS NULL")
+	case tree.ActiveSchedules:
+		whereExprs = append(whereExprs, "next_run IS NOT NULL")
+	}
+
+	switch n.ExecutorType {
+	case tree.ScheduledBackupExecutor:
+		whereExprs = append(whereExprs, fmt.Sprintf(
+			"executor_type = '%s'", tree.ScheduledBackupExecutor.InternalName()))
+	}
+
+	if n.ScheduleID != nil {
+		whereExprs = append(whereExprs, fmt.Sprintf("schedule_id=(%s)",
+			tree.AsString(n.ScheduleID)))
+	}
+
+	var whereClause string
+	if len(whereExprs) > 0 {
+		whereClause = fmt.Sprintf("WHERE (%s)", strings.Join(whereExprs, " AND "))
+	}
+	return parse(fmt.Sprintf(
+		"SELECT %s FROM (SELECT schedule_id AS id, schedule_name AS label, "+
+			"(CASE WHEN next_run IS NULL THEN 'PAUSED' ELSE 'ACTIVE' END) AS schedule_status, "+
+			"next_run, schedule_expr AS recurrence, "+
+			"(SELECT count(*) FROM system.jobs WHERE status = 'running' AND "+
+			"created_by_type = %s AND created_by_id = schedule_id) AS jobsRunning, "+
+			"owner, created, executor_type, schedule_id FROM system.scheduled_jobs) %s",
+		strings.Join(columnExprs, ","),
+		lex.EscapeSQLString(jobs.CreatedByScheduledJobs),
+		whereClause,
+	))
+}
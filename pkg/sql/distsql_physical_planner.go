@@ -287,6 +287,7 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 	case *distinctNode:
 	case *exportNode:
 	case *filterNode:
+	case *foreignScanNode:
 	case *groupNode:
 	case *indexJoinNode:
 	case *joinNode:
@@ -335,6 +336,13 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		}
 		return checkSupportForPlanNode(n.source.plan)
 
+	case *foreignScanNode:
+		if err := checkExpr(n.filter); err != nil {
+			return cannotDistribute, err
+		}
+		// The files of a foreign table are read in parallel.
+		return shouldDistribute, nil
+
 	case *groupNode:
 		rec, err := checkSupportForPlanNode(n.plan)
 		if err != nil {
@@ -2295,6 +2303,9 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 			return nil, err
 		}
 
+	case *foreignScanNode:
+		plan, err = dsp.createPlanForForeignScan(planCtx, n)
+
 	case *groupNode:
 		plan, err = dsp.createPhysPlanForPlanNode(planCtx, n.plan)
 		if err != nil {
@@ -3199,7 +3210,8 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSVWriter processors to the input plan.
+// We add a new stage of CSVWriter or ParquetWriter processors to the input
+// plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -3208,13 +3220,31 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		return nil, err
 	}
 
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
-		Destination:      n.fileName,
-		NamePattern:      exportFilePatternDefault,
-		Options:          n.csvOpts,
-		ChunkRows:        int64(n.chunkSize),
-		CompressionCodec: n.fileCompression,
-	}}
+	var core execinfrapb.ProcessorCoreUnion
+	switch n.fileFormat {
+	case roachpb.IOFileFormat_Parquet:
+		cols := planColumns(n.source)
+		colNames := make([]string, len(cols))
+		for i := range cols {
+			colNames[i] = cols[i].Name
+		}
+		core.ParquetWriter = &execinfrapb.ParquetWriterSpec{
+			Destination:      n.fileName,
+			NamePattern:      exportParquetFilePatternDefault,
+			ColumnNames:      colNames,
+			ChunkRows:        int64(n.chunkSize),
+			CompressionCodec: n.fileCompression,
+			RowGroupSize:     n.rowGroupSize,
+		}
+	default:
+		core.CSVWriter = &execinfrapb.CSVWriterSpec{
+			Destination:      n.fileName,
+			NamePattern:      exportFilePatternDefault,
+			Options:          n.csvOpts,
+			ChunkRows:        int64(n.chunkSize),
+			CompressionCodec: n.fileCompression,
+		}
+	}
 
 	resTypes := make([]*types.T, len(sqlbase.ExportColumns))
 	for i := range sqlbase.ExportColumns {
@@ -3224,7 +3254,7 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
-	// The CSVWriter produces the same columns as the EXPORT statement.
+	// The writers produce the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(sqlbase.ExportColumns))
 	return plan, nil
 }
@@ -1142,7 +1142,17 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	}
 
 	for i := range plan.checkPlans {
-		log.VEventf(ctx, 1, "executing check query %d out of %d", i+1, len(plan.checkPlans))
+		if pending := planner.maybeDeferCheck(plan.checkPlans[i].deferrableFK); pending != nil {
+			// Record the violations instead of failing; their keys are checked
+			// again when the transaction commits.
+			keyVals := plan.checkPlans[i].keyVals
+			plan.checkPlans[i].plan.planNode.(*errorIfRowsNode).onRow = func(row tree.Datums) {
+				pending.addKey(keyVals(row))
+			}
+			log.VEventf(ctx, 1, "executing deferred check query %d out of %d", i+1, len(plan.checkPlans))
+		} else {
+			log.VEventf(ctx, 1, "executing check query %d out of %d", i+1, len(plan.checkPlans))
+		}
 		if err := dsp.planAndRunPostquery(
 			ctx,
 			plan.checkPlans[i].plan,
@@ -1209,6 +1219,9 @@ func (dsp *DistSQLPlanner) planAndRunPostquery(
 	// TODO(yuzefovich): at the moment, errOnlyResultWriter is sufficient here,
 	// but it may not be the case when we support cascades through the optimizer.
 	postqueryRecv.resultWriter = &errOnlyResultWriter{}
+	// The plans of AFTER triggers return the results of the trigger functions,
+	// which are ignored.
+	postqueryRecv.discardRows = true
 	dsp.Run(postqueryPlanCtx, planner.txn, postqueryPhysPlan, postqueryRecv, evalCtx, nil /* finishedSetupFn */)()
 	if postqueryRecv.commErr != nil {
 		return postqueryRecv.commErr
@@ -97,7 +97,7 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 	rowCount float64,
 	locking *tree.LockingItem,
 ) (exec.Node, error) {
-	if table.IsVirtualTable() {
+	if table.IsVirtualTable() || table.IsForeignTable() {
 		return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
 	}
 
@@ -412,6 +412,18 @@ func (e *distSQLSpecExecFactory) ConstructGeoLookupJoin(
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
 }
 
+func (e *distSQLSpecExecFactory) ConstructNearestNeighborScan(
+	table cat.Table,
+	index cat.Index,
+	needed exec.TableColumnOrdinalSet,
+	geoCol exec.TableColumnOrdinal,
+	origin tree.Datum,
+	k int64,
+	reqOrdering exec.OutputOrdering,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
+}
+
 func (e *distSQLSpecExecFactory) ConstructZigzagJoin(
 	leftTable cat.Table,
 	leftIndex cat.Index,
@@ -474,7 +486,7 @@ func (e *distSQLSpecExecFactory) RenameColumns(
 }
 
 func (e *distSQLSpecExecFactory) ConstructPlan(
-	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Node,
+	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Check,
 ) (exec.Plan, error) {
 	return constructPlan(e.planner, root, subqueries, cascades, checks)
 }
@@ -583,6 +595,7 @@ func (e *distSQLSpecExecFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -590,6 +603,12 @@ func (e *distSQLSpecExecFactory) ConstructCreateView(
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
 }
 
+func (e *distSQLSpecExecFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, body string, deps opt.ViewDeps,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
+}
+
 func (e *distSQLSpecExecFactory) ConstructSequenceSelect(sequence cat.Sequence) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning")
 }
@@ -13,12 +13,14 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -35,6 +37,7 @@ type dropDatabaseNode struct {
 	n               *tree.DropDatabase
 	dbDesc          *sqlbase.ImmutableDatabaseDescriptor
 	td              []toDelete
+	functions       []*sqlbase.MutableFunctionDescriptor
 	schemasToDelete []string
 }
 
@@ -69,6 +72,10 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
+	if err := p.checkNoReplicationSlots(ctx, dbDesc); err != nil {
+		return nil, err
+	}
+
 	schemas, err := p.Tables().GetSchemasForDatabase(ctx, p.txn, dbDesc.GetID())
 	if err != nil {
 		return nil, err
@@ -87,7 +94,25 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		tbNames = append(tbNames, toAppend...)
 	}
 
-	if len(tbNames) > 0 {
+	functions := make([]*sqlbase.MutableFunctionDescriptor, 0, len(dbDesc.Functions))
+	for _, fn := range dbDesc.Functions {
+		desc, err := catalogkv.GetDescriptorByID(ctx, p.txn, p.ExecCfg().Codec, fn.ID)
+		if err != nil {
+			return nil, err
+		}
+		fnDesc, ok := desc.(*sqlbase.ImmutableFunctionDescriptor)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"descriptor %d of function %q is not a function", fn.ID, fn.Name)
+		}
+		if err := p.CheckPrivilege(ctx, fnDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		functions = append(functions,
+			sqlbase.NewMutableExistingFunctionDescriptor(fnDesc.FunctionDescriptor))
+	}
+
+	if len(tbNames) > 0 || len(functions) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
@@ -154,7 +179,9 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td, schemasToDelete: schemasToDelete}, nil
+	return &dropDatabaseNode{
+		n: n, dbDesc: dbDesc, td: td, functions: functions, schemasToDelete: schemasToDelete,
+	}, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
@@ -179,6 +206,34 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 		return err
 	}
 
+	// Functions are dropped first, so that they do not prevent dropping the
+	// relations they depend on. The descriptors of these relations were read
+	// before, so the references are also removed from them.
+	droppedFunctions := make(map[sqlbase.ID]bool, len(n.functions))
+	for _, fnDesc := range n.functions {
+		if err := p.dropFunctionImpl(ctx, fnDesc, nil /* argTypes */); err != nil {
+			return err
+		}
+		droppedFunctions[fnDesc.ID] = true
+		tbNameStrings = append(tbNameStrings, fnDesc.Name)
+	}
+	for _, toDel := range n.td {
+		refs := toDel.desc.DependedOnByFunctions[:0]
+		for _, id := range toDel.desc.DependedOnByFunctions {
+			if !droppedFunctions[id] {
+				refs = append(refs, id)
+			}
+		}
+		toDel.desc.DependedOnByFunctions = refs
+		triggers := toDel.desc.Triggers[:0]
+		for _, trigger := range toDel.desc.Triggers {
+			if !droppedFunctions[trigger.FunctionID] {
+				triggers = append(triggers, trigger)
+			}
+		}
+		toDel.desc.Triggers = triggers
+	}
+
 	// When views, sequences, and tables are dropped, don't queue a separate job
 	// for each of them, since the single DROP DATABASE job will cover them all.
 	for _, toDel := range n.td {
@@ -249,6 +304,14 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 		return err
 	}
 
+	if err := p.removeDbPublications(ctx, n.dbDesc.GetID()); err != nil {
+		return err
+	}
+
+	if err := p.removeDbForeignData(ctx, n.dbDesc.GetID()); err != nil {
+		return err
+	}
+
 	// Log Drop Database event. This is an auditable log event and is recorded
 	// in the same transaction as the table descriptor update.
 	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
@@ -321,3 +384,46 @@ func (p *planner) removeDbComment(ctx context.Context, dbID sqlbase.ID) error {
 
 	return err
 }
+
+// checkNoReplicationSlots returns an error if replication slots were created
+// in the database, since they would stream the changes of a dropped database.
+func (p *planner) checkNoReplicationSlots(
+	ctx context.Context, dbDesc *sqlbase.ImmutableDatabaseDescriptor,
+) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionLogicalReplication) {
+		return nil
+	}
+	row, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryRowEx(
+		ctx,
+		"count-db-replication-slots",
+		p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		"SELECT count(*) FROM system.replication_slots WHERE database_id=$1",
+		dbDesc.GetID())
+	if err != nil {
+		return err
+	}
+	if n := tree.MustBeDInt(row[0]); n > 0 {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.ObjectInUse,
+				"database %q is used by %d replication slot(s)",
+				tree.ErrNameString(dbDesc.GetName()), n),
+			"Drop the replication slots with DROP_REPLICATION_SLOT first.")
+	}
+	return nil
+}
+
+func (p *planner) removeDbPublications(ctx context.Context, dbID sqlbase.ID) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionLogicalReplication) {
+		return nil
+	}
+	_, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.ExecEx(
+		ctx,
+		"delete-db-publications",
+		p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		"DELETE FROM system.publications WHERE database_id=$1",
+		dbID)
+
+	return err
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n  *tree.DropFunction
+	td []functionToDelete
+}
+
+// functionToDelete identifies an overload to be dropped.
+type functionToDelete struct {
+	desc *sqlbase.MutableFunctionDescriptor
+	// argTypes identifies the overload. It is nil if all the overloads of the
+	// function are to be dropped.
+	argTypes []*types.T
+}
+
+// DropFunction drops functions.
+// Privileges: DROP on function.
+//   Notes: postgres allows only the function owner to DROP a function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	td := make([]functionToDelete, 0, len(n.Functions))
+	// Multiple overloads of the same function may be dropped by the same
+	// statement, so we share the descriptors.
+	descs := make(map[sqlbase.ID]*sqlbase.MutableFunctionDescriptor)
+	for _, fo := range n.Functions {
+		desc, err := p.resolveFunctionObject(ctx, fo, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			// IfExists specified and the function does not exist.
+			continue
+		}
+		if prev, ok := descs[desc.ID]; ok {
+			desc = prev
+		}
+		descs[desc.ID] = desc
+		toDel := functionToDelete{desc: desc}
+		if fo.Args != nil {
+			toDel.argTypes, err = p.resolveFunctionArgTypes(ctx, fo)
+			if err != nil {
+				return nil, err
+			}
+			if desc.FindOverload(toDel.argTypes) == -1 {
+				if n.IfExists {
+					continue
+				}
+				return nil, pgerror.Newf(pgcode.UndefinedFunction,
+					"function %s does not exist", tree.ErrString(&fo))
+			}
+		}
+		if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		if len(toDel.argTypes) == 0 && desc.TriggerOverload() != nil {
+			// Triggers cannot be dropped in cascade either.
+			if err := p.triggerDependencyError(ctx, desc); err != nil {
+				return nil, err
+			}
+		}
+		if n.DropBehavior == tree.DropCascade {
+			// Nothing depends on functions yet, so CASCADE behaves like
+			// RESTRICT.
+			log.VEventf(ctx, 2, "CASCADE has no effect when dropping function %s", desc.Name)
+		}
+		td = append(td, toDel)
+	}
+
+	if len(td) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &dropFunctionNode{n: n, td: td}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+
+	for _, toDel := range n.td {
+		if err := params.p.dropFunctionImpl(params.ctx, toDel.desc, toDel.argTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
+
+// dropFunctionImpl drops the overload of the function with the given
+// argument types, or all its overloads if argTypes is nil. The descriptor is
+// deleted once no overload remains.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, desc *sqlbase.MutableFunctionDescriptor, argTypes []*types.T,
+) error {
+	oldDeps := desc.Dependencies()
+	if argTypes == nil {
+		desc.Overloads = nil
+	} else if i := desc.FindOverload(argTypes); i != -1 {
+		desc.Overloads = append(desc.Overloads[:i], desc.Overloads[i+1:]...)
+	}
+	if err := p.updateFunctionBackReferences(ctx, desc, oldDeps); err != nil {
+		return err
+	}
+
+	codec := p.ExecCfg().Codec
+	if len(desc.Overloads) > 0 {
+		desc.Version++
+		b := p.txn.NewBatch()
+		if err := catalogkv.WriteDescToBatch(
+			ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(),
+			p.ExecCfg().Settings, b, codec, desc.ID, desc,
+		); err != nil {
+			return err
+		}
+		return p.txn.Run(ctx, b)
+	}
+
+	dbDesc, err := catalogkv.MustGetDatabaseDescByID(ctx, p.txn, codec, desc.ParentID)
+	if err != nil {
+		return err
+	}
+	if err := p.updateDatabaseFunctions(
+		ctx, dbDesc, func(mutDesc *sqlbase.MutableDatabaseDescriptor) {
+			mutDesc.RemoveFunction(desc.ID)
+		},
+	); err != nil {
+		return err
+	}
+	return p.txn.Del(ctx, sqlbase.MakeDescMetadataKey(codec, desc.ID))
+}
+
+// resolveFunctionObject returns the descriptor of the function named by fo,
+// or nil if it does not exist and required is not set. If fo has no argument
+// list, the function must have a single overload.
+func (p *planner) resolveFunctionObject(
+	ctx context.Context, fo tree.FuncObj, required bool,
+) (*sqlbase.MutableFunctionDescriptor, error) {
+	desc, err := p.findFunction(ctx, fo.Name.NumParts, fo.Name.Parts[:], p.CurrentSearchPath())
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		if !required {
+			return nil, nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedFunction,
+			"function %s does not exist", tree.ErrString(fo.Name))
+	}
+	if fo.Args == nil && len(desc.Overloads) > 1 {
+		return nil, pgerror.Newf(pgcode.AmbiguousFunction,
+			"function name %q is not unique", tree.ErrString(fo.Name))
+	}
+	return sqlbase.NewMutableExistingFunctionDescriptor(desc.FunctionDescriptor), nil
+}
+
+// resolveFunctionArgTypes resolves the argument types listed in fo.
+func (p *planner) resolveFunctionArgTypes(
+	ctx context.Context, fo tree.FuncObj,
+) ([]*types.T, error) {
+	argTypes := make([]*types.T, len(fo.Args))
+	for i := range fo.Args {
+		typ, err := tree.ResolveType(ctx, fo.Args[i], p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, err
+		}
+		argTypes[i] = typ
+	}
+	return argTypes, nil
+}
+
+// functionDependencyError returns an error if the given relation cannot be
+// dropped or renamed because a function depends on it, or nil if there is no
+// such dependency. Function bodies are not rewritten, and dropping a function
+// in cascade is not supported, so this applies regardless of the drop
+// behavior.
+func (p *planner) functionDependencyError(
+	ctx context.Context, op string, desc *sqlbase.MutableTableDescriptor,
+) error {
+	if len(desc.DependedOnByFunctions) == 0 {
+		return nil
+	}
+	fnDesc, err := catalogkv.GetDescriptorByID(ctx, p.txn, p.ExecCfg().Codec, desc.DependedOnByFunctions[0])
+	if err != nil {
+		return err
+	}
+	return errors.WithHintf(
+		pgerror.Newf(pgcode.DependentObjectsStillExist,
+			"cannot %s relation %q because function %q depends on it",
+			op, desc.Name, fnDesc.GetName()),
+		"you can drop %s instead.", fnDesc.GetName())
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -56,6 +58,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if droppedDesc == nil {
 			continue
 		}
+		if err := checkDropTableForeign(n, droppedDesc); err != nil {
+			return nil, err
+		}
 
 		td[droppedDesc.ID] = toDelete{tn, droppedDesc}
 	}
@@ -98,13 +103,33 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 	return &dropTableNode{n: n, td: td}, nil
 }
 
+// checkDropTableForeign returns an error if DROP FOREIGN TABLE is used on a
+// regular table, or DROP TABLE on a foreign table.
+func checkDropTableForeign(n *tree.DropTable, desc *sqlbase.MutableTableDescriptor) error {
+	if n.IsForeign && !desc.IsForeignTable() {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is not a foreign table", desc.Name),
+			"Use DROP TABLE to remove a table.")
+	}
+	if !n.IsForeign && desc.IsForeignTable() {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", desc.Name),
+			"Use DROP FOREIGN TABLE to remove a foreign table.")
+	}
+	return nil
+}
+
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
 // This is because DROP TABLE performs multiple KV operations on descriptors
 // and expects to see its own writes.
 func (n *dropTableNode) ReadingOwnWrites() {}
 
 func (n *dropTableNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("table"))
+	if n.n.IsForeign {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("foreign_table"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("table"))
+	}
 
 	ctx := params.ctx
 	for _, toDel := range n.td {
@@ -296,6 +321,15 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
+	// Remove the schedule deleting the expired rows of the table.
+	if tableDesc.RowLevelTTL != nil {
+		oldScheduleID := tableDesc.RowLevelTTL.ScheduleID
+		tableDesc.RowLevelTTL = nil
+		if err := p.updateRowLevelTTLSchedule(ctx, tableDesc, oldScheduleID); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	// Drop all views that depend on this table, assuming that we wouldn't have
 	// made it to this point if `cascade` wasn't enabled.
 	for _, ref := range tableDesc.DependedOnBy {
@@ -341,6 +375,13 @@ func (p *planner) initiateDropTable(
 		return fmt.Errorf("table %q is being dropped", tableDesc.Name)
 	}
 
+	if err := p.functionDependencyError(ctx, "drop", tableDesc); err != nil {
+		return err
+	}
+	if err := p.updateTriggerBackReferences(ctx, tableDesc, false /* add */); err != nil {
+		return err
+	}
+
 	// If the table is not interleaved , use the delayed GC mechanism to
 	// schedule usage of the more efficient ClearRange pathway. ClearRange will
 	// only work if the entire hierarchy of interleaved tables are dropped at
@@ -0,0 +1,133 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *sqlbase.MutableTableDescriptor
+}
+
+// DropTrigger drops a trigger.
+// Privileges: CREATE on table.
+//   Notes: postgres requires the table owner to DROP a trigger.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists, resolver.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and the table does not exist.
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if tableDesc.FindTriggerByName(string(n.Name)) == nil {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %q for table %q does not exist", n.Name, tableDesc.Name)
+	}
+	if n.DropBehavior == tree.DropCascade {
+		// Nothing depends on triggers, so CASCADE behaves like RESTRICT.
+		log.VEventf(ctx, 2, "CASCADE has no effect when dropping trigger %s", n.Name)
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP TRIGGER performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	fnID := n.tableDesc.FindTriggerByName(string(n.n.Name)).FunctionID
+	n.tableDesc.RemoveTrigger(string(n.n.Name))
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, sqlbase.InvalidMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// The function still depends on the table if another trigger of the table
+	// executes it.
+	for i := range n.tableDesc.Triggers {
+		if n.tableDesc.Triggers[i].FunctionID == fnID {
+			return nil
+		}
+	}
+	return params.p.updateTriggerBackReference(params.ctx, fnID, n.tableDesc.ID, false /* add */)
+}
+
+func (*dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTriggerNode) Close(context.Context)        {}
+
+// updateTriggerBackReferences adds or removes the back-references from the
+// functions executed by the triggers of the given table to the table.
+func (p *planner) updateTriggerBackReferences(
+	ctx context.Context, tableDesc *sqlbase.MutableTableDescriptor, add bool,
+) error {
+	done := make(map[sqlbase.ID]bool, len(tableDesc.Triggers))
+	for i := range tableDesc.Triggers {
+		fnID := tableDesc.Triggers[i].FunctionID
+		if done[fnID] {
+			continue
+		}
+		done[fnID] = true
+		if err := p.updateTriggerBackReference(ctx, fnID, tableDesc.ID, add); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triggerDependencyError returns an error if the given function cannot be
+// dropped because triggers execute it, or nil if there is no such dependency.
+func (p *planner) triggerDependencyError(
+	ctx context.Context, desc *sqlbase.MutableFunctionDescriptor,
+) error {
+	if len(desc.DependedOnBy) == 0 {
+		return nil
+	}
+	tableDesc, err := catalogkv.GetDescriptorByID(ctx, p.txn, p.ExecCfg().Codec, desc.DependedOnBy[0])
+	if err != nil {
+		return err
+	}
+	return errors.WithHintf(
+		pgerror.Newf(pgcode.DependentObjectsStillExist,
+			"cannot drop function %q because a trigger on table %q depends on it",
+			desc.Name, tableDesc.GetName()),
+		"you can drop the triggers on %s instead.", tableDesc.GetName())
+}
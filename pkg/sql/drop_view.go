@@ -16,6 +16,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -45,6 +47,9 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 			// IfExists specified and the view did not exist.
 			continue
 		}
+		if err := checkViewMatchesMaterialized(droppedDesc, n.IsMaterialized); err != nil {
+			return nil, err
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -78,7 +83,11 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 func (n *dropViewNode) ReadingOwnWrites() {}
 
 func (n *dropViewNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("view"))
+	if n.n.IsMaterialized {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("materialized_view"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("view"))
+	}
 
 	ctx := params.ctx
 	for _, toDel := range n.td {
@@ -119,6 +128,21 @@ func (*dropViewNode) Next(runParams) (bool, error) { return false, nil }
 func (*dropViewNode) Values() tree.Datums          { return tree.Datums{} }
 func (*dropViewNode) Close(context.Context)        {}
 
+// checkViewMatchesMaterialized returns an error if desc is a materialized
+// view and the statement did not ask for one, or vice versa.
+func checkViewMatchesMaterialized(desc *sqlbase.MutableTableDescriptor, materialized bool) error {
+	if desc.MaterializedView() == materialized {
+		return nil
+	}
+	if materialized {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+	return errors.WithHint(
+		pgerror.Newf(pgcode.WrongObjectType, "%q is a materialized view", desc.Name),
+		"use the corresponding MATERIALIZED VIEW command",
+	)
+}
+
 func descInSlice(descID sqlbase.ID, td []toDelete) bool {
 	for _, toDel := range td {
 		if descID == toDel.desc.ID {
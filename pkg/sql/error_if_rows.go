@@ -25,6 +25,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr func(values tree.Datums) error
 
+	// onRow, if set, is called with every row produced by the wrapped node,
+	// instead of returning an error for the first one. It is used when the
+	// checking of a deferrable constraint is deferred.
+	onRow func(values tree.Datums)
+
 	nexted bool
 }
 
@@ -38,6 +43,16 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
+	if n.onRow != nil {
+		for {
+			ok, err := n.plan.Next(params)
+			if !ok || err != nil {
+				return false, err
+			}
+			n.onRow(n.plan.Values())
+		}
+	}
+
 	ok, err := n.plan.Next(params)
 	if err != nil {
 		return false, err
@@ -87,6 +87,9 @@ const (
 	// EventLogNodeRecommissioned is recorded when a decommissioned node is
 	// recommissioned.
 	EventLogNodeRecommissioned EventLogType = "node_recommissioned"
+	// EventLogLossOfQuorumRecovery is recorded when a range which lost quorum
+	// is recovered by rewriting its descriptor to a single surviving replica.
+	EventLogLossOfQuorumRecovery EventLogType = "loss_of_quorum_recovery"
 
 	// EventLogSetClusterSetting is recorded when a cluster setting is changed.
 	EventLogSetClusterSetting EventLogType = "set_cluster_setting"
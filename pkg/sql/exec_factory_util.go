@@ -29,7 +29,7 @@ func constructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 ) (exec.Plan, error) {
 	res := &planTop{
 		// TODO(radu): these fields can be modified by planning various opaque
@@ -80,7 +80,9 @@ func constructPlan(
 	if len(checks) > 0 {
 		res.checkPlans = make([]checkPlan, len(checks))
 		for i := range checks {
-			assignPlan(&res.checkPlans[i].plan, checks[i])
+			assignPlan(&res.checkPlans[i].plan, checks[i].Root)
+			res.checkPlans[i].deferrableFK = checks[i].DeferrableFK
+			res.checkPlans[i].keyVals = checks[i].KeyVals
 		}
 	}
 
@@ -667,6 +667,10 @@ type ExecutorConfig struct {
 
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
+
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions listening on their channel.
+	NotificationRegistry *NotificationRegistry
 }
 
 // Organization returns the value of cluster.organization.
@@ -1015,6 +1019,8 @@ func checkResultType(typ *types.T) error {
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -1207,6 +1213,9 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// Replication is set for connections opened in logical replication mode,
+	// which accept the commands of the streaming replication protocol.
+	Replication bool
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -31,9 +32,13 @@ type exportNode struct {
 	source planNode
 
 	fileName        string
+	fileFormat      roachpb.IOFileFormat_FileFormat
 	csvOpts         roachpb.CSVOptions
 	chunkSize       int
 	fileCompression execinfrapb.FileCompression
+	// rowGroupSize is the target size of the row groups of Parquet files. 0
+	// means the default size.
+	rowGroupSize int64
 }
 
 func (e *exportNode) startExec(params runParams) error {
@@ -53,25 +58,38 @@ func (e *exportNode) Close(ctx context.Context) {
 }
 
 const (
-	exportOptionDelimiter   = "delimiter"
-	exportOptionNullAs      = "nullas"
-	exportOptionChunkSize   = "chunk_rows"
-	exportOptionFileName    = "filename"
-	exportOptionCompression = "compression"
+	exportOptionDelimiter    = "delimiter"
+	exportOptionNullAs       = "nullas"
+	exportOptionChunkSize    = "chunk_rows"
+	exportOptionFileName     = "filename"
+	exportOptionCompression  = "compression"
+	exportOptionRowGroupSize = "row_group_size"
 )
 
 var exportOptionExpectValues = map[string]KVStringOptValidate{
-	exportOptionChunkSize:   KVStringOptRequireValue,
-	exportOptionDelimiter:   KVStringOptRequireValue,
-	exportOptionFileName:    KVStringOptRequireValue,
-	exportOptionNullAs:      KVStringOptRequireValue,
-	exportOptionCompression: KVStringOptRequireValue,
+	exportOptionChunkSize:    KVStringOptRequireValue,
+	exportOptionDelimiter:    KVStringOptRequireValue,
+	exportOptionFileName:     KVStringOptRequireValue,
+	exportOptionNullAs:       KVStringOptRequireValue,
+	exportOptionCompression:  KVStringOptRequireValue,
+	exportOptionRowGroupSize: KVStringOptRequireValue,
+}
+
+// exportOptionsByFormat lists the options which are only allowed for a
+// specific file format.
+var exportOptionsByFormat = map[string]roachpb.IOFileFormat_FileFormat{
+	exportOptionDelimiter:    roachpb.IOFileFormat_CSV,
+	exportOptionNullAs:       roachpb.IOFileFormat_CSV,
+	exportOptionRowGroupSize: roachpb.IOFileFormat_Parquet,
 }
 
 const exportChunkSizeDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
 const exportCompressionCodec = "gzip"
+const exportParquetCompressionCodec = "snappy"
+const exportCompressionNone = "none"
 
 // ConstructExport is part of the exec.Factory interface.
 func (ef *execFactory) ConstructExport(
@@ -81,7 +99,13 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	var format roachpb.IOFileFormat_FileFormat
+	switch fileFormat {
+	case "CSV":
+		format = roachpb.IOFileFormat_CSV
+	case "PARQUET":
+		format = roachpb.IOFileFormat_Parquet
+	default:
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -98,6 +122,12 @@ func (ef *execFactory) ConstructExport(
 	if err != nil {
 		return nil, err
 	}
+	for name := range optVals {
+		if f, ok := exportOptionsByFormat[name]; ok && f != format {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s option is not supported for %s export", name, fileFormat)
+		}
+	}
 
 	csvOpts := roachpb.CSVOptions{}
 
@@ -123,13 +153,33 @@ func (ef *execFactory) ConstructExport(
 		}
 	}
 
+	var rowGroupSize int64
+	if override, ok := optVals[exportOptionRowGroupSize]; ok {
+		rowGroupSize, err = humanizeutil.ParseBytes(override)
+		if err != nil {
+			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+		}
+		if rowGroupSize < 1 {
+			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid parquet row group size")
+		}
+	}
+
 	// Check whenever compression is expected and extract compression codec name in case
-	// of positive result
+	// of positive result. Parquet files are compressed with snappy by default.
 	var codec execinfrapb.FileCompression
+	if format == roachpb.IOFileFormat_Parquet {
+		codec = execinfrapb.FileCompression_Snappy
+	}
 	if name, ok := optVals[exportOptionCompression]; ok && len(name) != 0 {
-		if strings.EqualFold(name, exportCompressionCodec) {
+		switch {
+		case strings.EqualFold(name, exportCompressionCodec):
 			codec = execinfrapb.FileCompression_Gzip
-		} else {
+		case strings.EqualFold(name, exportParquetCompressionCodec) &&
+			format == roachpb.IOFileFormat_Parquet:
+			codec = execinfrapb.FileCompression_Snappy
+		case strings.EqualFold(name, exportCompressionNone):
+			codec = execinfrapb.FileCompression_None
+		default:
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s", name)
 		}
@@ -138,8 +188,10 @@ func (ef *execFactory) ConstructExport(
 	return &exportNode{
 		source:          input.(planNode),
 		fileName:        string(*fileNameStr),
+		fileFormat:      format,
 		csvOpts:         csvOpts,
 		chunkSize:       chunkSize,
 		fileCompression: codec,
+		rowGroupSize:    rowGroupSize,
 	}, nil
 }
@@ -0,0 +1,840 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// Foreign data wrappers and foreign servers are stored as rows of
+// system.foreign_data_wrappers and system.foreign_servers, keyed by the ID of
+// the database they belong to and their name. Foreign tables are regular table
+// descriptors with a ForeignTable field naming their server; they have no
+// data of their own and are scanned by reading the files found at the
+// location of their server.
+//
+// The options of the three kinds of objects are stored as "key=value"
+// strings. The options of a foreign table override the options of its server,
+// which override the options of its wrapper.
+
+// checkForeignDataWrappersSupported returns an error if the cluster can't use
+// foreign data wrappers yet.
+func checkForeignDataWrappersSupported(ctx context.Context, execCfg *ExecutorConfig) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionForeignDataWrappers) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for foreign data wrappers")
+	}
+	return nil
+}
+
+// foreignDataDatabase returns the descriptor of the current database, to
+// which foreign data wrappers and servers belong.
+func (p *planner) foreignDataDatabase(
+	ctx context.Context,
+) (*sqlbase.ImmutableDatabaseDescriptor, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	return p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+}
+
+// foreignOptionScope is a bitmask of the kinds of objects on which an option
+// can be set.
+type foreignOptionScope int
+
+const (
+	foreignWrapperScope foreignOptionScope = 1 << iota
+	foreignServerScope
+	foreignTableScope
+
+	anyForeignScope = foreignWrapperScope | foreignServerScope | foreignTableScope
+)
+
+const (
+	foreignOptURI              = "uri"
+	foreignOptPath             = "path"
+	foreignOptPartitionColumns = "partition_columns"
+	foreignOptFormat           = "format"
+	foreignOptDecompress       = "decompress"
+	foreignOptDelimiter        = "delimiter"
+	foreignOptComment          = "comment"
+	foreignOptNullIf           = "nullif"
+	foreignOptSkip             = "skip"
+	foreignOptStrictQuotes     = "strict_quotes"
+)
+
+var foreignOptionScopes = map[string]foreignOptionScope{
+	foreignOptURI:              foreignServerScope,
+	foreignOptPath:             foreignTableScope,
+	foreignOptPartitionColumns: foreignTableScope,
+	foreignOptFormat:           anyForeignScope,
+	foreignOptDecompress:       anyForeignScope,
+	foreignOptDelimiter:        anyForeignScope,
+	foreignOptComment:          anyForeignScope,
+	foreignOptNullIf:           anyForeignScope,
+	foreignOptSkip:             anyForeignScope,
+	foreignOptStrictQuotes:     anyForeignScope,
+}
+
+// csvOnlyForeignOptions are the options which only apply to CSV files.
+var csvOnlyForeignOptions = []string{
+	foreignOptDelimiter, foreignOptComment, foreignOptNullIf, foreignOptSkip, foreignOptStrictQuotes,
+}
+
+// evalForeignOptions checks that the given options can be set on an object of
+// the given scope, and returns them as "key=value" strings.
+func evalForeignOptions(opts tree.KVOptions, scope foreignOptionScope) ([]string, error) {
+	res := make([]string, 0, len(opts))
+	seen := make(map[string]struct{}, len(opts))
+	for _, opt := range opts {
+		key := string(opt.Key)
+		if s, ok := foreignOptionScopes[key]; !ok || s&scope == 0 {
+			return nil, pgerror.Newf(pgcode.FdwInvalidOptionName, "invalid option %q", key)
+		}
+		if _, ok := seen[key]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject, "option %q provided more than once", key)
+		}
+		seen[key] = struct{}{}
+		res = append(res, key+"="+opt.Value.(*tree.StrVal).RawString())
+	}
+	return res, nil
+}
+
+// mergeForeignOptions returns the options resulting of the given lists of
+// "key=value" strings; the later lists override the earlier ones.
+func mergeForeignOptions(lists ...[]string) map[string]string {
+	res := make(map[string]string)
+	for _, l := range lists {
+		for _, opt := range l {
+			kv := strings.SplitN(opt, "=", 2)
+			if len(kv) == 2 {
+				res[kv[0]] = kv[1]
+			}
+		}
+	}
+	return res
+}
+
+// makeForeignFileFormat returns the format of the files read by a foreign
+// table with the given options.
+func makeForeignFileFormat(opts map[string]string) (roachpb.IOFileFormat, error) {
+	var format roachpb.IOFileFormat
+	switch f := opts[foreignOptFormat]; strings.ToLower(f) {
+	case "", "csv":
+		format.Format = roachpb.IOFileFormat_CSV
+	case "parquet":
+		format.Format = roachpb.IOFileFormat_Parquet
+		for _, o := range csvOnlyForeignOptions {
+			if _, ok := opts[o]; ok {
+				return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+					"option %q is not supported with format %q", o, f)
+			}
+		}
+	default:
+		return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue, "unsupported format %q", f)
+	}
+
+	if override, ok := opts[foreignOptDecompress]; ok {
+		found := false
+		for name, value := range roachpb.IOFileFormat_Compression_value {
+			if strings.EqualFold(name, override) {
+				format.Compression = roachpb.IOFileFormat_Compression(value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+				"unsupported compression value: %q", override)
+		}
+	}
+
+	if override, ok := opts[foreignOptDelimiter]; ok {
+		comma, err := util.GetSingleRune(override)
+		if err != nil {
+			return format, pgerror.Wrap(err, pgcode.FdwInvalidAttributeValue, "invalid delimiter value")
+		}
+		format.Csv.Comma = comma
+	}
+	if override, ok := opts[foreignOptComment]; ok {
+		comment, err := util.GetSingleRune(override)
+		if err != nil {
+			return format, pgerror.Wrap(err, pgcode.FdwInvalidAttributeValue, "invalid comment value")
+		}
+		format.Csv.Comment = comment
+	}
+	if override, ok := opts[foreignOptNullIf]; ok {
+		format.Csv.NullEncoding = &override
+	}
+	if override, ok := opts[foreignOptSkip]; ok {
+		skip, err := strconv.Atoi(override)
+		if err != nil {
+			return format, pgerror.Wrapf(err, pgcode.FdwInvalidAttributeValue, "invalid %s value", foreignOptSkip)
+		}
+		if skip < 0 {
+			return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue, "%s must be >= 0", foreignOptSkip)
+		}
+		format.Csv.Skip = uint32(skip)
+	}
+	if override, ok := opts[foreignOptStrictQuotes]; ok {
+		strict, err := strconv.ParseBool(override)
+		if err != nil {
+			return format, pgerror.Wrapf(err, pgcode.FdwInvalidAttributeValue,
+				"invalid %s value", foreignOptStrictQuotes)
+		}
+		format.Csv.StrictQuotes = strict
+	}
+	return format, nil
+}
+
+// foreignTableURI returns the location of the files of a foreign table with
+// the given options: the path of the table, relative to the uri of its server.
+func foreignTableURI(opts map[string]string) (string, error) {
+	uri, err := url.Parse(opts[foreignOptURI])
+	if err != nil {
+		return "", err
+	}
+	if p := opts[foreignOptPath]; p != "" {
+		uri.Path = path.Join(uri.Path, p)
+	}
+	return uri.String(), nil
+}
+
+// foreignPartitionColumns returns the names of the columns of a foreign table
+// with the given options whose values are stored in the paths of its files,
+// as <column>=<value> directories.
+func foreignPartitionColumns(opts map[string]string) []string {
+	list := opts[foreignOptPartitionColumns]
+	if list == "" {
+		return nil
+	}
+	var res []string
+	for _, c := range strings.Split(list, ",") {
+		res = append(res, tree.Name(strings.TrimSpace(c)).Normalize())
+	}
+	return res
+}
+
+// lookupForeignDataWrapper returns the options of a foreign data wrapper. The
+// returned bool is false if it does not exist.
+func lookupForeignDataWrapper(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, dbID sqlbase.ID, name string,
+) ([]string, bool, error) {
+	row, err := ie.QueryRowEx(
+		ctx, "get-foreign-data-wrapper", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT options FROM system.foreign_data_wrappers WHERE database_id = $1 AND name = $2`,
+		dbID, name,
+	)
+	if err != nil || row == nil {
+		return nil, false, err
+	}
+	return datumToStrings(row[0]), true, nil
+}
+
+// lookupForeignServer returns the wrapper and the options of a foreign server.
+// The returned bool is false if it does not exist.
+func lookupForeignServer(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, dbID sqlbase.ID, name string,
+) (string, []string, bool, error) {
+	row, err := ie.QueryRowEx(
+		ctx, "get-foreign-server", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT wrapper, options FROM system.foreign_servers WHERE database_id = $1 AND name = $2`,
+		dbID, name,
+	)
+	if err != nil || row == nil {
+		return "", nil, false, err
+	}
+	return string(tree.MustBeDString(row[0])), datumToStrings(row[1]), true, nil
+}
+
+// resolveForeignServerOptions returns the options of a foreign server merged
+// with the options of its wrapper.
+func resolveForeignServerOptions(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, dbID sqlbase.ID, server string,
+) (wrapperOpts, serverOpts []string, _ error) {
+	wrapper, serverOpts, ok, err := lookupForeignServer(ctx, ie, txn, dbID, server)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, pgerror.Newf(pgcode.UndefinedObject, "server %q does not exist", server)
+	}
+	wrapperOpts, ok, err = lookupForeignDataWrapper(ctx, ie, txn, dbID, wrapper)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, pgerror.Newf(pgcode.UndefinedObject,
+			"foreign-data wrapper %q does not exist", wrapper)
+	}
+	return wrapperOpts, serverOpts, nil
+}
+
+// resolveForeignTableOptions returns the options of a foreign table merged
+// with the options of its server and wrapper.
+func resolveForeignTableOptions(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, desc *sqlbase.TableDescriptor,
+) (map[string]string, error) {
+	wrapperOpts, serverOpts, err := resolveForeignServerOptions(
+		ctx, ie, txn, desc.ParentID, desc.ForeignTable.Server,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return mergeForeignOptions(wrapperOpts, serverOpts, desc.ForeignTable.Options), nil
+}
+
+// sanitizeForeignOptions removes the secrets from the uri in a list of
+// "key=value" options.
+func sanitizeForeignOptions(opts []string) []string {
+	res := make([]string, len(opts))
+	for i, opt := range opts {
+		res[i] = opt
+		if strings.HasPrefix(opt, foreignOptURI+"=") {
+			uri, err := cloud.SanitizeExternalStorageURI(strings.TrimPrefix(opt, foreignOptURI+"="), nil)
+			if err != nil {
+				uri = "<invalid uri>"
+			}
+			res[i] = foreignOptURI + "=" + uri
+		}
+	}
+	return res
+}
+
+func datumToStrings(d tree.Datum) []string {
+	arr := tree.MustBeDArray(d)
+	res := make([]string, len(arr.Array))
+	for i, e := range arr.Array {
+		res[i] = string(tree.MustBeDString(e))
+	}
+	return res
+}
+
+func stringsToDatum(s []string) (tree.Datum, error) {
+	arr := tree.NewDArray(types.String)
+	for _, e := range s {
+		if err := arr.Append(tree.NewDString(e)); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+type createFDWNode struct {
+	n       *tree.CreateForeignDataWrapper
+	dbDesc  *sqlbase.ImmutableDatabaseDescriptor
+	options []string
+}
+
+// CreateForeignDataWrapper creates a foreign data wrapper.
+// Privileges: admin.
+//   Notes: postgres requires superuser.
+func (p *planner) CreateForeignDataWrapper(
+	ctx context.Context, n *tree.CreateForeignDataWrapper,
+) (planNode, error) {
+	if err := checkForeignDataWrappersSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "create a foreign-data wrapper"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.foreignDataDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := evalForeignOptions(n.Options, foreignWrapperScope)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := makeForeignFileFormat(mergeForeignOptions(options)); err != nil {
+		return nil, err
+	}
+
+	_, exists, err := lookupForeignDataWrapper(ctx, p.ExecCfg().InternalExecutor, p.txn, dbDesc.GetID(), string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "foreign-data wrapper %q already exists", n.Name)
+	}
+	return &createFDWNode{n: n, dbDesc: dbDesc, options: options}, nil
+}
+
+func (n *createFDWNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("foreign_data_wrapper"))
+
+	options, err := stringsToDatum(n.options)
+	if err != nil {
+		return err
+	}
+	_, err = params.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		"create-foreign-data-wrapper",
+		params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`INSERT INTO system.foreign_data_wrappers (database_id, name, owner, options)
+VALUES ($1, $2, $3, $4)`,
+		n.dbDesc.GetID(),
+		string(n.n.Name),
+		params.SessionData().User,
+		options,
+	)
+	return err
+}
+
+func (*createFDWNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFDWNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFDWNode) Close(context.Context)        {}
+
+type dropFDWNode struct {
+	n       *tree.DropForeignDataWrapper
+	dbDesc  *sqlbase.ImmutableDatabaseDescriptor
+	names   []string
+	servers []string
+	tables  []*sqlbase.MutableTableDescriptor
+}
+
+// DropForeignDataWrapper drops foreign data wrappers.
+// Privileges: admin.
+//   Notes: postgres requires ownership of the wrapper.
+func (p *planner) DropForeignDataWrapper(
+	ctx context.Context, n *tree.DropForeignDataWrapper,
+) (planNode, error) {
+	if err := checkForeignDataWrappersSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "drop a foreign-data wrapper"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.foreignDataDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ie := p.ExecCfg().InternalExecutor
+	node := &dropFDWNode{n: n, dbDesc: dbDesc}
+	for _, name := range n.Names {
+		_, exists, err := lookupForeignDataWrapper(ctx, ie, p.txn, dbDesc.GetID(), string(name))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "foreign-data wrapper %q does not exist", name)
+		}
+		node.names = append(node.names, string(name))
+
+		rows, err := ie.QueryEx(
+			ctx, "get-foreign-data-wrapper-servers", p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`SELECT name FROM system.foreign_servers WHERE database_id = $1 AND wrapper = $2`,
+			dbDesc.GetID(), string(name),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			server := string(tree.MustBeDString(row[0]))
+			if n.DropBehavior != tree.DropCascade {
+				return nil, errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop foreign-data wrapper %q because server %q depends on it", name, server),
+					"Use DROP ... CASCADE to drop the dependent objects too.")
+			}
+			tables, err := p.foreignServerTables(ctx, dbDesc.GetID(), server, n.DropBehavior)
+			if err != nil {
+				return nil, err
+			}
+			node.servers = append(node.servers, server)
+			node.tables = append(node.tables, tables...)
+		}
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FOREIGN DATA WRAPPER CASCADE drops tables.
+func (n *dropFDWNode) ReadingOwnWrites() {}
+
+func (n *dropFDWNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("foreign_data_wrapper"))
+
+	if err := params.p.dropForeignTables(
+		params.ctx, n.tables, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+	for _, server := range n.servers {
+		if err := deleteForeignServer(params, n.dbDesc.GetID(), server); err != nil {
+			return err
+		}
+	}
+	for _, name := range n.names {
+		if _, err := params.ExecCfg().InternalExecutor.ExecEx(
+			params.ctx,
+			"drop-foreign-data-wrapper",
+			params.p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`DELETE FROM system.foreign_data_wrappers WHERE database_id = $1 AND name = $2`,
+			n.dbDesc.GetID(), name,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFDWNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFDWNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFDWNode) Close(context.Context)        {}
+
+type createServerNode struct {
+	n       *tree.CreateServer
+	dbDesc  *sqlbase.ImmutableDatabaseDescriptor
+	options []string
+}
+
+// CreateServer creates a foreign server.
+// Privileges: admin, since the location of a server may embed credentials.
+//   Notes: postgres requires USAGE on the foreign data wrapper.
+func (p *planner) CreateServer(ctx context.Context, n *tree.CreateServer) (planNode, error) {
+	if err := checkForeignDataWrappersSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "create a foreign server"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.foreignDataDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ie := p.ExecCfg().InternalExecutor
+	_, exists, _, err := lookupForeignServer(ctx, ie, p.txn, dbDesc.GetID(), string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if n.IfNotExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "server %q already exists", n.Name)
+	}
+
+	wrapperOpts, ok, err := lookupForeignDataWrapper(ctx, ie, p.txn, dbDesc.GetID(), string(n.Wrapper))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "foreign-data wrapper %q does not exist", n.Wrapper)
+	}
+	options, err := evalForeignOptions(n.Options, foreignServerScope)
+	if err != nil {
+		return nil, err
+	}
+	opts := mergeForeignOptions(wrapperOpts, options)
+	uri, ok := opts[foreignOptURI]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.FdwOptionNameNotFound, "option %q is required", foreignOptURI)
+	}
+	if _, err := cloud.ExternalStorageConfFromURI(uri); err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.FdwInvalidAttributeValue, "invalid %s value", foreignOptURI)
+	}
+	if _, err := makeForeignFileFormat(opts); err != nil {
+		return nil, err
+	}
+	return &createServerNode{n: n, dbDesc: dbDesc, options: options}, nil
+}
+
+func (n *createServerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("foreign_server"))
+
+	options, err := stringsToDatum(n.options)
+	if err != nil {
+		return err
+	}
+	_, err = params.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		"create-foreign-server",
+		params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`INSERT INTO system.foreign_servers (database_id, name, wrapper, owner, options)
+VALUES ($1, $2, $3, $4, $5)`,
+		n.dbDesc.GetID(),
+		string(n.n.Name),
+		string(n.n.Wrapper),
+		params.SessionData().User,
+		options,
+	)
+	return err
+}
+
+func (*createServerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createServerNode) Close(context.Context)        {}
+
+type dropServerNode struct {
+	n      *tree.DropServer
+	dbDesc *sqlbase.ImmutableDatabaseDescriptor
+	names  []string
+	tables []*sqlbase.MutableTableDescriptor
+}
+
+// DropServer drops foreign servers, and their foreign tables with CASCADE.
+// Privileges: admin.
+//   Notes: postgres requires ownership of the server.
+func (p *planner) DropServer(ctx context.Context, n *tree.DropServer) (planNode, error) {
+	if err := checkForeignDataWrappersSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "drop a foreign server"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.foreignDataDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &dropServerNode{n: n, dbDesc: dbDesc}
+	for _, name := range n.Names {
+		_, _, exists, err := lookupForeignServer(ctx, p.ExecCfg().InternalExecutor, p.txn, dbDesc.GetID(), string(name))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "server %q does not exist", name)
+		}
+		tables, err := p.foreignServerTables(ctx, dbDesc.GetID(), string(name), n.DropBehavior)
+		if err != nil {
+			return nil, err
+		}
+		node.names = append(node.names, string(name))
+		node.tables = append(node.tables, tables...)
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP SERVER CASCADE drops tables.
+func (n *dropServerNode) ReadingOwnWrites() {}
+
+func (n *dropServerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("foreign_server"))
+
+	if err := params.p.dropForeignTables(
+		params.ctx, n.tables, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+	for _, name := range n.names {
+		if err := deleteForeignServer(params, n.dbDesc.GetID(), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropServerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropServerNode) Close(context.Context)        {}
+
+func deleteForeignServer(params runParams, dbID sqlbase.ID, name string) error {
+	_, err := params.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		"drop-foreign-server",
+		params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.foreign_servers WHERE database_id = $1 AND name = $2`,
+		dbID, name,
+	)
+	return err
+}
+
+// foreignServerTables returns the foreign tables of a server, to be dropped
+// along with it. An error is returned if there are any and the drop behavior
+// is not CASCADE.
+func (p *planner) foreignServerTables(
+	ctx context.Context, dbID sqlbase.ID, server string, behavior tree.DropBehavior,
+) ([]*sqlbase.MutableTableDescriptor, error) {
+	descs, err := catalogkv.GetAllDescriptors(ctx, p.txn, p.ExecCfg().Codec)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sqlbase.MutableTableDescriptor
+	for _, desc := range descs {
+		tbl, ok := desc.(*sqlbase.ImmutableTableDescriptor)
+		if !ok || tbl.ParentID != dbID || !tbl.IsForeignTable() || tbl.Dropped() ||
+			tbl.ForeignTable.Server != server {
+			continue
+		}
+		if behavior != tree.DropCascade {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop server %q because foreign table %q depends on it", server, tbl.Name),
+				"Use DROP ... CASCADE to drop the dependent objects too.")
+		}
+		mutDesc, err := p.Tables().GetMutableTableVersionByID(ctx, tbl.ID, p.txn)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.prepareDropWithTableDesc(ctx, mutDesc); err != nil {
+			return nil, err
+		}
+		for _, ref := range mutDesc.DependedOnBy {
+			if err := p.canRemoveDependentView(ctx, mutDesc, ref, behavior); err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, mutDesc)
+	}
+	return res, nil
+}
+
+// dropForeignTables drops the foreign tables of dropped servers.
+func (p *planner) dropForeignTables(
+	ctx context.Context, tables []*sqlbase.MutableTableDescriptor, jobDesc string,
+) error {
+	for _, desc := range tables {
+		if desc.Dropped() {
+			continue
+		}
+		if _, err := p.dropTableImpl(ctx, desc, true /* queueJob */, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateForeignTable creates a foreign table.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the schema and USAGE on the server.
+func (p *planner) CreateForeignTable(
+	ctx context.Context, n *tree.CreateForeignTable,
+) (planNode, error) {
+	if err := checkForeignDataWrappersSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+
+	un := n.Table.ToUnresolvedObjectName()
+	dbDesc, prefix, err := p.ResolveUncachedDatabase(ctx, un)
+	if err != nil {
+		return nil, err
+	}
+	n.Table.ObjectNamePrefix = prefix
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// Foreign tables only have plain columns: their data is not stored, so
+	// nothing can be enforced on it.
+	colNames := make(map[string]struct{})
+	for _, def := range n.Defs {
+		d, ok := def.(*tree.ColumnTableDef)
+		if !ok {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"foreign tables cannot have constraints, indexes or column families")
+		}
+		if d.PrimaryKey.IsPrimaryKey || d.Unique || d.HasDefaultExpr() || d.IsComputed() ||
+			d.IsSerial || d.IsGeneratedAsIdentity() || len(d.CheckExprs) > 0 ||
+			d.HasFKConstraint() || d.HasColumnFamily() {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"column %q of a foreign table can only have a NULL or NOT NULL constraint", d.Name)
+		}
+		colNames[string(d.Name)] = struct{}{}
+	}
+
+	options, err := evalForeignOptions(n.Options, foreignTableScope)
+	if err != nil {
+		return nil, err
+	}
+	wrapperOpts, serverOpts, err := resolveForeignServerOptions(
+		ctx, p.ExecCfg().InternalExecutor, p.txn, dbDesc.GetID(), string(n.Server),
+	)
+	if err != nil {
+		return nil, err
+	}
+	opts := mergeForeignOptions(wrapperOpts, serverOpts, options)
+	if _, err := makeForeignFileFormat(opts); err != nil {
+		return nil, err
+	}
+	for _, c := range foreignPartitionColumns(opts) {
+		if _, ok := colNames[c]; !ok {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"partition column %q does not exist", c)
+		}
+	}
+
+	return &createTableNode{
+		n: &tree.CreateTable{
+			Table:       n.Table,
+			IfNotExists: n.IfNotExists,
+			Defs:        n.Defs,
+		},
+		dbDesc: dbDesc,
+		foreign: &sqlbase.TableDescriptor_ForeignTable{
+			Server:  string(n.Server),
+			Options: options,
+		},
+	}, nil
+}
+
+// removeDbForeignData removes the foreign data wrappers and servers of a
+// dropped database. Its foreign tables are dropped with its other tables.
+func (p *planner) removeDbForeignData(ctx context.Context, dbID sqlbase.ID) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionForeignDataWrappers) {
+		return nil
+	}
+	for _, stmt := range []string{
+		"DELETE FROM system.foreign_servers WHERE database_id=$1",
+		"DELETE FROM system.foreign_data_wrappers WHERE database_id=$1",
+	} {
+		if _, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.ExecEx(
+			ctx,
+			"delete-db-foreign-data",
+			p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			stmt,
+			dbID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// foreignScanNode reads the rows of a foreign table from the files found at
+// the location of its server. The files are listed when the query is planned
+// and spread across ForeignScan processors running on the nodes of the
+// cluster; the node itself can only be executed by DistSQL.
+//
+// The files of a foreign table with partition columns are stored in
+// <column>=<value> directories, one level per partition column. The conjuncts
+// of the filter that only reference partition columns are evaluated for each
+// file when the query is planned, and the files for which one of them is not
+// true are not read.
+type foreignScanNode struct {
+	desc *sqlbase.ImmutableTableDescriptor
+	// cols are the columns of the table returned by the node.
+	cols          []sqlbase.ColumnDescriptor
+	resultColumns sqlbase.ResultColumns
+
+	// filter, if non-nil, is applied to the rows read from the files. Its
+	// tree.IndexedVar leaves are bound to the node.
+	filter     tree.TypedExpr
+	filterVars tree.IndexedVarHelper
+
+	// if non-zero, hardLimit indicates that the node only needs to provide
+	// this many rows (after applying any filter).
+	hardLimit int64
+
+	estimatedRowCount uint64
+
+	// pruneRow holds the values of cols for the file being considered while
+	// the files are pruned: the partition columns are set from the path of
+	// the file, the other columns are NULL.
+	pruneRow tree.Datums
+}
+
+// foreignHivePartitionNull is the value with which NULL partition values are
+// stored in the paths of partitioned files.
+const foreignHivePartitionNull = "__HIVE_DEFAULT_PARTITION__"
+
+// foreignScanNode implements tree.IndexedVarContainer.
+var _ tree.IndexedVarContainer = &foreignScanNode{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (n *foreignScanNode) IndexedVarEval(idx int, ctx *tree.EvalContext) (tree.Datum, error) {
+	return n.pruneRow[idx].Eval(ctx)
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (n *foreignScanNode) IndexedVarResolvedType(idx int) *types.T {
+	return n.resultColumns[idx].Typ
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (n *foreignScanNode) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	return (*tree.Name)(&n.resultColumns[idx].Name)
+}
+
+func (n *foreignScanNode) startExec(params runParams) error {
+	panic("foreign scans cannot be executed outside of distsql")
+}
+
+// Next is part of the planNode interface.
+func (n *foreignScanNode) Next(params runParams) (bool, error) {
+	panic("foreign scans cannot be executed outside of distsql")
+}
+
+// Values is part of the planNode interface.
+func (n *foreignScanNode) Values() tree.Datums {
+	panic("foreign scans cannot be executed outside of distsql")
+}
+
+// Close is part of the planNode interface.
+func (n *foreignScanNode) Close(ctx context.Context) {
+}
+
+// ParseForeignPartitionPath returns the values of the partition columns of a
+// foreign table stored in the path of one of its files, relative to the
+// location of the table. The path starts with a <column>=<value> directory
+// for each partition column, in order.
+func ParseForeignPartitionPath(
+	evalCtx *tree.EvalContext, file string, names []string, typs []*types.T,
+) (tree.Datums, error) {
+	parts := strings.Split(file, "/")
+	if len(parts) <= len(names) {
+		return nil, errors.Errorf("file %q is not in a partition directory", file)
+	}
+	res := make(tree.Datums, len(names))
+	for i, name := range names {
+		kv := strings.SplitN(parts[i], "=", 2)
+		if len(kv) != 2 || tree.Name(kv[0]).Normalize() != name {
+			return nil, errors.Errorf(
+				"directory %q of file %q does not match partition column %q", parts[i], file, name)
+		}
+		v, err := url.PathUnescape(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for partition column %q", name)
+		}
+		if v == foreignHivePartitionNull {
+			res[i] = tree.DNull
+			continue
+		}
+		if res[i], err = sqlbase.ParseDatumStringAs(typs[i], v, evalCtx); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for partition column %q", name)
+		}
+	}
+	return res, nil
+}
+
+// createPlanForForeignScan creates a distributed plan for a foreignScanNode.
+func (dsp *DistSQLPlanner) createPlanForForeignScan(
+	planCtx *PlanningCtx, n *foreignScanNode,
+) (*PhysicalPlan, error) {
+	if planCtx.planner == nil {
+		return nil, errors.AssertionFailedf("foreign scans require a planner")
+	}
+	ctx := planCtx.ctx
+	execCfg := planCtx.planner.ExecCfg()
+	opts, err := resolveForeignTableOptions(
+		ctx, execCfg.InternalExecutor, planCtx.planner.txn, n.desc.TableDesc(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	format, err := makeForeignFileFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+	uri, err := foreignTableURI(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	partNames := foreignPartitionColumns(opts)
+	partOrdinals := make([]uint32, len(partNames))
+	partTypes := make([]*types.T, len(partNames))
+	for i, name := range partNames {
+		col, _, err := n.desc.FindColumnByName(tree.Name(name))
+		if err != nil {
+			return nil, err
+		}
+		partOrdinals[i] = uint32(n.desc.ColumnIdxMap()[col.ID])
+		partTypes[i] = col.Type
+	}
+
+	files, err := dsp.listForeignFiles(ctx, execCfg, uri, partNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(partNames) > 0 && n.filter != nil {
+		files, err = n.pruneForeignFiles(planCtx.EvalContext(), files, partNames, partTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// scanNodeToTableOrdinalMap is a map from foreign scan node column ordinal
+	// to processor column ordinal.
+	scanNodeToTableOrdinalMap := toTableOrdinals(n.cols, n.desc, execinfra.ScanVisibilityPublic)
+	filter, err := physicalplan.MakeExpression(n.filter, planCtx, scanNodeToTableOrdinalMap)
+	if err != nil {
+		return nil, err
+	}
+	post := execinfrapb.PostProcessSpec{Filter: filter}
+
+	nodes := []roachpb.NodeID{dsp.gatewayNodeID}
+	if n.hardLimit != 0 {
+		// If the scan has a hard limit, use a single processor to avoid reading
+		// more rows than necessary.
+		post.Limit = uint64(n.hardLimit)
+	} else if !planCtx.isLocal {
+		nodes = dsp.foreignScanNodes(planCtx)
+	}
+
+	makeCore := func(nodeID roachpb.NodeID) physicalplan.ProcessorCorePlacement {
+		return physicalplan.ProcessorCorePlacement{
+			NodeID: nodeID,
+			Core: execinfrapb.ProcessorCoreUnion{ForeignScan: &execinfrapb.ForeignScanSpec{
+				Table:            *n.desc.TableDesc(),
+				URI:              uri,
+				Format:           format,
+				PartitionColumns: partOrdinals,
+			}},
+		}
+	}
+	var corePlacement []physicalplan.ProcessorCorePlacement
+	for i, file := range files {
+		// Round robin assign the files to the nodes.
+		if i < len(nodes) {
+			corePlacement = append(corePlacement, makeCore(nodes[i]))
+		}
+		spec := corePlacement[i%len(nodes)].Core.ForeignScan
+		spec.Files = append(spec.Files, file)
+	}
+	if len(corePlacement) == 0 {
+		// Plan a processor without files, which produces no rows, to keep the
+		// schema of the plan.
+		corePlacement = append(corePlacement, makeCore(dsp.gatewayNodeID))
+	}
+
+	typs := make([]*types.T, len(n.desc.Columns))
+	for i := range n.desc.Columns {
+		typs[i] = n.desc.Columns[i].Type
+	}
+
+	p := MakePhysicalPlan(dsp.gatewayNodeID)
+	p.TotalEstimatedScannedRows = n.estimatedRowCount
+	p.MaxEstimatedRowCount = n.estimatedRowCount
+	p.AddNoInputStage(corePlacement, post, typs, execinfrapb.Ordering{})
+	p.AddProjection(getOutputColumnsFromColsForScan(n.cols, scanNodeToTableOrdinalMap))
+	p.PlanToStreamColMap = identityMap(nil /* buf */, len(n.cols))
+	return &p, nil
+}
+
+// listForeignFiles returns the paths of the files of a foreign table, relative
+// to uri. If the storage can't list files, or if uri is the location of a
+// file rather than a directory, uri is read as a single file whose path is
+// empty.
+func (dsp *DistSQLPlanner) listForeignFiles(
+	ctx context.Context, execCfg *ExecutorConfig, uri string, partNames []string,
+) ([]string, error) {
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	var pattern strings.Builder
+	for _, name := range partNames {
+		pattern.WriteString(name)
+		pattern.WriteString("=*/")
+	}
+	pattern.WriteString("*")
+	files, err := store.ListFiles(ctx, pattern.String())
+	if errors.Is(err, cloud.ErrListingUnsupported) {
+		if len(partNames) > 0 {
+			return nil, pgerror.Newf(pgcode.FdwError,
+				"partition columns require a storage that can list files")
+		}
+		return []string{""}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 && len(partNames) == 0 {
+		if _, err := store.Size(ctx, "" /* basename */); err == nil {
+			return []string{""}, nil
+		}
+	}
+	return files, nil
+}
+
+// pruneForeignFiles returns the files for which all the conjuncts of the filter
+// that only reference partition columns can be true.
+func (n *foreignScanNode) pruneForeignFiles(
+	evalCtx *tree.EvalContext, files []string, partNames []string, partTypes []*types.T,
+) ([]string, error) {
+	// partCols maps the ordinals of the partition columns in partNames to the
+	// ordinals of the columns of the node.
+	partCols := make([]int, len(partNames))
+	var isPartCol []bool
+	for i, name := range partNames {
+		partCols[i] = -1
+		for j := range n.cols {
+			if n.cols[j].Name == name {
+				partCols[i] = j
+				if isPartCol == nil {
+					isPartCol = make([]bool, len(n.cols))
+				}
+				isPartCol[j] = true
+			}
+		}
+	}
+	if isPartCol == nil {
+		return files, nil
+	}
+
+	var conjuncts []tree.TypedExpr
+	for _, e := range splitForeignFilter(n.filter, nil /* res */) {
+		v := foreignPruneVisitor{isPartCol: isPartCol, prunable: true}
+		tree.WalkExprConst(&v, e)
+		if v.prunable && v.hasVar {
+			conjuncts = append(conjuncts, e)
+		}
+	}
+	if len(conjuncts) == 0 {
+		return files, nil
+	}
+
+	n.pruneRow = make(tree.Datums, len(n.cols))
+	evalCtx.PushIVarContainer(n)
+	defer evalCtx.PopIVarContainer()
+	res := files[:0:0]
+	for _, file := range files {
+		values, err := ParseForeignPartitionPath(evalCtx, file, partNames, partTypes)
+		if err != nil {
+			// The file is read, and the processor reports the error.
+			res = append(res, file)
+			continue
+		}
+		for i := range n.pruneRow {
+			n.pruneRow[i] = tree.DNull
+		}
+		for i, j := range partCols {
+			if j != -1 {
+				n.pruneRow[j] = values[i]
+			}
+		}
+		keep := true
+		for _, e := range conjuncts {
+			ok, err := sqlbase.RunFilter(e, evalCtx)
+			if err != nil {
+				log.VEventf(evalCtx.Context, 2, "cannot prune foreign file %q: %v", file, err)
+				ok = true
+			}
+			if !ok {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			res = append(res, file)
+		}
+	}
+	n.pruneRow = nil
+	return res, nil
+}
+
+// splitForeignFilter appends the conjuncts of a filter to res.
+func splitForeignFilter(e tree.TypedExpr, res []tree.TypedExpr) []tree.TypedExpr {
+	if and, ok := e.(*tree.AndExpr); ok {
+		res = splitForeignFilter(and.TypedLeft(), res)
+		return splitForeignFilter(and.TypedRight(), res)
+	}
+	return append(res, e)
+}
+
+// foreignPruneVisitor checks whether an expression can be evaluated when the
+// files of a foreign table are pruned: it must only reference partition
+// columns, and must not contain subqueries.
+type foreignPruneVisitor struct {
+	isPartCol []bool
+	prunable  bool
+	hasVar    bool
+}
+
+var _ tree.Visitor = &foreignPruneVisitor{}
+
+// VisitPre implements the tree.Visitor interface.
+func (v *foreignPruneVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if !v.prunable {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.IndexedVar:
+		v.hasVar = true
+		if !v.isPartCol[t.Idx] {
+			v.prunable = false
+		}
+		return false, expr
+	case *tree.Subquery:
+		v.prunable = false
+		return false, expr
+	}
+	return true, expr
+}
+
+// VisitPost implements the tree.Visitor interface.
+func (v *foreignPruneVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// foreignScanNodes returns the healthy nodes on which the files of a foreign
+// table can be read.
+func (dsp *DistSQLPlanner) foreignScanNodes(planCtx *PlanningCtx) []roachpb.NodeID {
+	descs, err := getAllNodeDescriptors(planCtx.planner)
+	if err != nil {
+		return []roachpb.NodeID{dsp.gatewayNodeID}
+	}
+	nodes := make([]roachpb.NodeID, 0, len(descs))
+	for i := range descs {
+		if dsp.CheckNodeHealthAndVersion(planCtx, descs[i].NodeID) == NodeOK {
+			nodes = append(nodes, descs[i].NodeID)
+		}
+	}
+	if len(nodes) == 0 {
+		return []roachpb.NodeID{dsp.gatewayNodeID}
+	}
+	return nodes
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+var _ tree.FunctionReferenceResolver = &planner{}
+
+// ResolveFunction implements the tree.FunctionReferenceResolver interface.
+// It looks up a function created with CREATE FUNCTION. It returns nil if no
+// such function exists.
+func (p *planner) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, searchPath sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	if name.Star || name.NumParts > 3 {
+		return nil, nil
+	}
+	desc, err := p.findFunction(ctx, name.NumParts, name.Parts[:], searchPath)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	if len(desc.Overloads) == 1 && desc.Overloads[0].Trigger {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"trigger functions can only be called as triggers")
+	}
+	return makeUserDefinedFunctionDefinition(desc), nil
+}
+
+// findFunction returns the descriptor of the function with the given name,
+// or nil if no such function exists. The name parts are in reverse order,
+// as in tree.UnresolvedName.
+func (p *planner) findFunction(
+	ctx context.Context, numParts int, parts []string, searchPath sessiondata.SearchPath,
+) (desc *sqlbase.ImmutableFunctionDescriptor, err error) {
+	fnName := parts[0]
+	switch numParts {
+	case 1:
+		curDB := p.CurrentDatabase()
+		if curDB == "" {
+			return nil, nil
+		}
+		iter := searchPath.Iter()
+		for scName, ok := iter.Next(); ok && desc == nil; scName, ok = iter.Next() {
+			if desc, err = p.lookupFunction(ctx, curDB, scName, fnName); err != nil {
+				return nil, err
+			}
+		}
+	case 2:
+		// The prefix is either a schema in the current database, or a database
+		// whose public schema contains the function.
+		if curDB := p.CurrentDatabase(); curDB != "" {
+			if desc, err = p.lookupFunction(ctx, curDB, parts[1], fnName); err != nil {
+				return nil, err
+			}
+		}
+		if desc == nil {
+			if desc, err = p.lookupFunction(ctx, parts[1], tree.PublicSchema, fnName); err != nil {
+				return nil, err
+			}
+		}
+	case 3:
+		if desc, err = p.lookupFunction(ctx, parts[2], parts[1], fnName); err != nil {
+			return nil, err
+		}
+	}
+	return desc, nil
+}
+
+// lookupFunction returns the descriptor of the function with the given name
+// in the given database and schema, or nil if there is no such function.
+func (p *planner) lookupFunction(
+	ctx context.Context, dbName, scName, fnName string,
+) (*sqlbase.ImmutableFunctionDescriptor, error) {
+	codec := p.ExecCfg().Codec
+	// The names of the functions are held by the database descriptor, so
+	// the cached copy of the descriptor cannot be used.
+	dbDesc, err := p.LogicalSchemaAccessor().GetDatabaseDesc(
+		ctx, p.txn, codec, dbName, tree.DatabaseLookupFlags{AvoidCached: true},
+	)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+	found, scID, err := catalogkv.ResolveSchemaID(ctx, p.txn, codec, dbDesc.GetID(), scName)
+	if err != nil || !found {
+		return nil, err
+	}
+	id, found := dbDesc.DatabaseDesc().FindFunction(scID, fnName)
+	if !found {
+		return nil, nil
+	}
+	desc, err := catalogkv.GetDescriptorByID(ctx, p.txn, codec, id)
+	if err != nil {
+		return nil, err
+	}
+	fnDesc, _ := desc.(*sqlbase.ImmutableFunctionDescriptor)
+	return fnDesc, nil
+}
+
+// makeUserDefinedFunctionDefinition returns the definition of a function
+// created with CREATE FUNCTION. Each overload evaluates its body with the
+// internal executor, within the transaction of the calling statement.
+func makeUserDefinedFunctionDefinition(
+	desc *sqlbase.ImmutableFunctionDescriptor,
+) *tree.FunctionDefinition {
+	props := tree.FunctionProperties{
+		// The arguments are bound to the body, which decides how to handle
+		// NULLs.
+		NullableArgs: true,
+		// The internal executor is not available on remote nodes.
+		DistsqlBlocklist: true,
+		Category:         "User-defined",
+	}
+	overloads := make([]tree.Overload, 0, len(desc.Overloads))
+	for i := range desc.Overloads {
+		o := &desc.Overloads[i]
+		if o.Trigger {
+			// Trigger functions can only be executed by the triggers of tables.
+			continue
+		}
+		vol := tree.Volatility(o.Volatility)
+		if vol == tree.VolatilityVolatile {
+			props.Impure = true
+		}
+		query := makeFunctionQuery(o)
+		overloads = append(overloads, tree.Overload{
+			Types:      o.ArgTypes(),
+			ReturnType: tree.FixedReturnType(o.ReturnType),
+			Volatility: vol,
+			Body:       o.Body,
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				qargs := make([]interface{}, len(args))
+				for i := range args {
+					qargs[i] = args[i]
+				}
+				row, err := evalCtx.InternalExecutor.QueryRow(
+					evalCtx.Ctx(), "udf", evalCtx.Txn, query, qargs...,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if row == nil {
+					return tree.DNull, nil
+				}
+				return row[0], nil
+			},
+		})
+	}
+	return tree.NewUserDefinedFunctionDefinition(desc.Name, &props, overloads)
+}
+
+// makeFunctionQuery returns the query that computes the result of the given
+// overload. The arguments are passed as placeholders; named arguments are
+// additionally exposed as the columns of a lateral VALUES clause, so that
+// the body can refer to them by name.
+func makeFunctionQuery(o *sqlbase.FunctionDescriptor_Overload) string {
+	var values, names []string
+	for i := range o.Args {
+		if o.Args[i].Name == "" {
+			continue
+		}
+		values = append(values, tree.PlaceholderIdx(i).String()+"::"+o.Args[i].Type.SQLString())
+		names = append(names, tree.NameString(o.Args[i].Name))
+	}
+	var buf strings.Builder
+	buf.WriteString(`SELECT "$value"::`)
+	buf.WriteString(o.ReturnType.SQLString())
+	buf.WriteString(" FROM ")
+	if len(names) > 0 {
+		buf.WriteString(`(VALUES (`)
+		buf.WriteString(strings.Join(values, ", "))
+		buf.WriteString(`)) AS "$args"(`)
+		buf.WriteString(strings.Join(names, ", "))
+		buf.WriteString("), LATERAL ")
+	}
+	buf.WriteString("(")
+	buf.WriteString(o.Body)
+	buf.WriteString(`) AS "$result"("$value") LIMIT 1`)
+	return buf.String()
+}
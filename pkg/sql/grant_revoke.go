@@ -34,6 +34,8 @@ import (
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Functions != nil {
+		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnFunction)
 	} else {
 		sqltelemetry.IncIAMGrantPrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -60,6 +62,8 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
 	if n.Targets.Databases != nil {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnDatabase)
+	} else if n.Targets.Functions != nil {
+		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnFunction)
 	} else {
 		sqltelemetry.IncIAMRevokePrivilegesCounter(sqltelemetry.OnTable)
 	}
@@ -174,6 +178,20 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 					return err
 				}
 			}
+
+		case *sqlbase.MutableFunctionDescriptor:
+			d.Version++
+			if err := catalogkv.WriteDescToBatch(
+				ctx,
+				p.extendedEvalCtx.Tracing.KVTracingEnabled(),
+				p.ExecCfg().Settings,
+				b,
+				p.ExecCfg().Codec,
+				descriptor.GetID(),
+				descriptor,
+			); err != nil {
+				return err
+			}
 		}
 	}
 
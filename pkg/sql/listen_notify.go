@@ -0,0 +1,126 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// checkNotificationsSupported returns an error if the session can't use
+// LISTEN and NOTIFY.
+func (p *planner) checkNotificationsSupported(ctx context.Context, stmt string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionListenNotify) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s requires all nodes to be upgraded to %s",
+			stmt, clusterversion.VersionByKey(clusterversion.VersionListenNotify))
+	}
+	if p.ExecCfg().NotificationRegistry == nil {
+		return pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported", stmt)
+	}
+	return nil
+}
+
+type listenNode struct {
+	n *tree.Listen
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkNotificationsSupported(ctx, "LISTEN"); err != nil {
+		return nil, err
+	}
+	if p.extendedEvalCtx.Notifications == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is only supported in client sessions")
+	}
+	return &listenNode{n: n}, nil
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	// The session starts listening once the transaction commits.
+	params.extendedEvalCtx.Notifications.stage(notificationOp{
+		channel: string(n.n.Channel),
+		listen:  true,
+	})
+	return nil
+}
+
+func (*listenNode) Next(runParams) (bool, error) { return false, nil }
+func (*listenNode) Values() tree.Datums          { return tree.Datums{} }
+func (*listenNode) Close(context.Context)        {}
+
+type unlistenNode struct {
+	n *tree.Unlisten
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if err := p.checkNotificationsSupported(ctx, "UNLISTEN"); err != nil {
+		return nil, err
+	}
+	if p.extendedEvalCtx.Notifications == nil {
+		// A session that can't listen has nothing to stop listening to.
+		return newZeroNode(nil /* columns */), nil
+	}
+	return &unlistenNode{n: n}, nil
+}
+
+func (n *unlistenNode) startExec(params runParams) error {
+	params.extendedEvalCtx.Notifications.stage(notificationOp{
+		channel: string(n.n.Channel),
+		all:     n.n.All,
+	})
+	return nil
+}
+
+func (*unlistenNode) Next(runParams) (bool, error) { return false, nil }
+func (*unlistenNode) Values() tree.Datums          { return tree.Datums{} }
+func (*unlistenNode) Close(context.Context)        {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.checkNotificationsSupported(ctx, "NOTIFY"); err != nil {
+		return nil, err
+	}
+	if p.EvalContext().TxnReadOnly {
+		return nil, pgerror.New(pgcode.ReadOnlySQLTransaction,
+			"cannot execute NOTIFY in a read-only transaction")
+	}
+	if len(n.Payload) > maxNotificationPayloadLength {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	// The notification is written in the transaction, and is only delivered
+	// to the listeners once it commits.
+	return params.ExecCfg().NotificationRegistry.sendNotification(
+		params.ctx, params.p.txn, params.ExecCfg().NodeID.SQLInstanceID(),
+		string(n.n.Channel), n.n.Payload,
+	)
+}
+
+func (*notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (*notifyNode) Values() tree.Datums          { return tree.Datums{} }
+func (*notifyNode) Close(context.Context)        {}
@@ -0,0 +1,456 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// nearestNeighborRadiusGrowthFactor is the factor by which nearestNeighborNode
+// grows its search radius when it has found fewer than k rows.
+const nearestNeighborRadiusGrowthFactor = 4
+
+// nearestNeighborNode returns the k rows of a table whose geospatial column is
+// closest to a constant origin shape, in increasing order of distance. Each
+// row is followed by its distance from the origin.
+//
+// The rows are found using a geospatial inverted index on the column. The
+// index is scanned with coverings of the region within a search radius r of
+// the origin: every non-empty shape within distance r of the origin is found
+// by such a scan. Once at least k of the candidate rows found so far are
+// within distance r, no other row can be closer to the origin than the k
+// closest candidates, and the result is exact. Otherwise the radius is either
+// set to the distance of the k-th closest candidate, which is guaranteed to
+// find enough rows with the next scan, or grown exponentially if there are
+// fewer than k candidates. When the radius reaches the size of the indexed
+// domain, the entire index is scanned.
+//
+// Rows with an empty shape are not in the inverted index. They are at an
+// infinite distance from the origin, and are only returned if the table has
+// fewer than k rows with non-empty shapes.
+type nearestNeighborNode struct {
+	desc *sqlbase.ImmutableTableDescriptor
+	// index is the geospatial inverted index used to find candidate rows.
+	index *sqlbase.IndexDescriptor
+
+	// neededCols are the ordinals of the table columns returned by the node.
+	neededCols util.FastIntSet
+	// geoColIdx is the ordinal of the indexed geospatial column.
+	geoColIdx int
+
+	origin tree.Datum
+	k      int
+
+	// columns are the columns of the needed table columns, in ordinal order,
+	// followed by the distance column.
+	columns     sqlbase.ResultColumns
+	reqOrdering ReqOrdering
+
+	run nearestNeighborRun
+}
+
+// nearestNeighborCandidate is a row found by nearestNeighborNode.
+type nearestNeighborCandidate struct {
+	// values contains the needed columns of the row followed by its distance.
+	values   tree.Datums
+	distance float64
+}
+
+type nearestNeighborRun struct {
+	// rows are the rows to return, in increasing order of distance.
+	rows   []nearestNeighborCandidate
+	rowIdx int
+
+	// seen contains the encoded primary keys of all candidate rows.
+	seen map[string]struct{}
+
+	indexFetcher   row.Fetcher
+	primaryFetcher row.Fetcher
+	alloc          sqlbase.DatumAlloc
+	distanceFn     *tree.BinOp
+	acc            mon.BoundAccount
+}
+
+func (n *nearestNeighborNode) startExec(params runParams) error {
+	ctx := params.ctx
+	n.run.seen = make(map[string]struct{})
+	n.run.acc = params.EvalContext().Mon.MakeBoundAccount()
+	n.run.distanceFn = tree.NewTypedBinaryExpr(tree.Distance, n.origin, n.origin, types.Float).Fn
+	if err := n.initFetchers(params); err != nil {
+		return err
+	}
+
+	dWithin, maxDistance, initialDistance, err := n.makeIndexSearch()
+	if err != nil {
+		return err
+	}
+	codec := params.ExecCfg().Codec
+	indexPrefix := sqlbase.MakeIndexKeyPrefix(codec, n.desc.TableDesc(), n.index.ID)
+
+	var candidates []nearestNeighborCandidate
+	complete := false
+	for r := 0.0; ; {
+		var spans roachpb.Spans
+		if r >= maxDistance {
+			// The radius covers the entire indexed domain, so scan the whole index.
+			spans = roachpb.Spans{n.desc.IndexSpan(codec, n.index.ID)}
+			complete = true
+		} else {
+			keySpans, err := dWithin(ctx, r)
+			if err != nil {
+				return err
+			}
+			if spans, err = makeGeoKeySpans(indexPrefix, keySpans); err != nil {
+				return err
+			}
+		}
+		log.VEventf(ctx, 2, "nearest neighbor search with radius %f: %d spans", r, len(spans))
+		newCandidates, err := n.fetchCandidates(params, spans)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, newCandidates...)
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].distance < candidates[j].distance
+		})
+
+		if complete {
+			break
+		}
+		if len(candidates) >= n.k {
+			kth := candidates[n.k-1].distance
+			if kth <= r {
+				// Every row within distance r of the origin is a candidate, and at
+				// least k of them are within that distance.
+				break
+			}
+			// The next scan finds at least the k closest candidates.
+			r = kth
+			continue
+		}
+		if r == 0 {
+			r = initialDistance
+		} else {
+			r *= nearestNeighborRadiusGrowthFactor
+		}
+	}
+
+	if len(candidates) < n.k {
+		// All rows with non-empty shapes have been found. Add rows with empty
+		// shapes, which are infinitely far away from the origin.
+		span := n.desc.PrimaryIndexSpan(codec)
+		remaining, err := n.fetchRows(params, roachpb.Spans{span}, n.k-len(candidates))
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, remaining...)
+	}
+
+	if len(candidates) > n.k {
+		candidates = candidates[:n.k]
+	}
+	n.run.rows = candidates
+	return nil
+}
+
+// initFetchers initializes the fetchers for the inverted index and the primary
+// index.
+func (n *nearestNeighborNode) initFetchers(params runParams) error {
+	codec := params.ExecCfg().Codec
+	colIdxMap := n.desc.ColumnIdxMap()
+
+	var pkCols util.FastIntSet
+	for _, id := range n.desc.PrimaryIndex.ColumnIDs {
+		pkCols.Add(colIdxMap[id])
+	}
+	if err := n.run.indexFetcher.Init(
+		codec,
+		false, /* reverse */
+		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		&n.run.alloc,
+		row.FetcherTableArgs{
+			Desc:             n.desc,
+			Index:            n.index,
+			ColIdxMap:        colIdxMap,
+			IsSecondaryIndex: true,
+			Cols:             n.desc.Columns,
+			ValNeededForCol:  pkCols,
+		},
+	); err != nil {
+		return err
+	}
+
+	neededCols := n.neededCols.Copy()
+	neededCols.Add(n.geoColIdx)
+	return n.run.primaryFetcher.Init(
+		codec,
+		false, /* reverse */
+		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
+		false, /* returnRangeInfo */
+		false, /* isCheck */
+		&n.run.alloc,
+		row.FetcherTableArgs{
+			Desc:             n.desc,
+			Index:            &n.desc.PrimaryIndex,
+			ColIdxMap:        colIdxMap,
+			IsSecondaryIndex: false,
+			Cols:             n.desc.Columns,
+			ValNeededForCol:  neededCols,
+			EvalCtx:          params.EvalContext(),
+		},
+	)
+}
+
+// nearestNeighborSearchFn returns the spans of a geospatial inverted index
+// that contain every shape within the given distance of the origin.
+type nearestNeighborSearchFn func(ctx context.Context, distance float64) (geoindex.UnionKeySpans, error)
+
+// makeIndexSearch returns the function used to search the inverted index, the
+// search radius at which the whole indexed domain is covered, and the radius
+// of the first search after the initial search for shapes intersecting the
+// origin.
+func (n *nearestNeighborNode) makeIndexSearch() (
+	search nearestNeighborSearchFn,
+	maxDistance float64,
+	initialDistance float64,
+	err error,
+) {
+	switch t := n.origin.(type) {
+	case *tree.DGeography:
+		cfg := n.index.GeoConfig.S2Geography
+		if cfg == nil {
+			return nil, 0, 0, errors.AssertionFailedf("index %s is not a geography index", n.index.Name)
+		}
+		idx := geoindex.NewS2GeographyIndex(*cfg)
+		spheroid, err := t.Geography.Spheroid()
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		search = func(ctx context.Context, distance float64) (geoindex.UnionKeySpans, error) {
+			if distance == 0 {
+				return idx.Intersects(ctx, t.Geography)
+			}
+			return idx.DWithin(ctx, t.Geography, distance)
+		}
+		// No two points on the sphere are more than half of its circumference
+		// apart.
+		maxDistance = math.Pi * spheroid.SphereRadius
+		initialDistance = maxDistance / math.Exp2(float64(cfg.S2Config.MaxLevel))
+		return search, maxDistance, initialDistance, nil
+
+	case *tree.DGeometry:
+		cfg := n.index.GeoConfig.S2Geometry
+		if cfg == nil {
+			return nil, 0, 0, errors.AssertionFailedf("index %s is not a geometry index", n.index.Name)
+		}
+		idx := geoindex.NewS2GeometryIndex(*cfg)
+		search = func(ctx context.Context, distance float64) (geoindex.UnionKeySpans, error) {
+			if distance == 0 {
+				return idx.Intersects(ctx, t.Geometry)
+			}
+			return idx.DWithin(ctx, t.Geometry, distance)
+		}
+		// Once the bounding box of the origin expanded by the search radius
+		// contains the bounds of the index, the search finds every shape.
+		bbox := t.Geometry.SpatialObject().BoundingBox
+		maxDistance = math.Max(
+			math.Max(bbox.MinX-cfg.MinX, cfg.MaxX-bbox.MaxX),
+			math.Max(bbox.MinY-cfg.MinY, cfg.MaxY-bbox.MaxY),
+		)
+		maxDistance = math.Max(maxDistance, 0)
+		initialDistance = math.Max(cfg.MaxX-cfg.MinX, cfg.MaxY-cfg.MinY) /
+			math.Exp2(float64(cfg.S2Config.MaxLevel))
+		return search, maxDistance, initialDistance, nil
+
+	default:
+		return nil, 0, 0, errors.AssertionFailedf("unexpected origin type %s", n.origin.ResolvedType())
+	}
+}
+
+// makeGeoKeySpans converts spans of geospatial index keys into spans of the
+// inverted index with the given prefix.
+func makeGeoKeySpans(indexPrefix []byte, keySpans geoindex.UnionKeySpans) (roachpb.Spans, error) {
+	spans := make(roachpb.Spans, 0, len(keySpans))
+	for _, s := range keySpans {
+		start, err := sqlbase.EncodeTableKey(
+			append([]byte(nil), indexPrefix...), tree.NewDInt(tree.DInt(s.Start)), encoding.Ascending,
+		)
+		if err != nil {
+			return nil, err
+		}
+		end, err := sqlbase.EncodeTableKey(
+			append([]byte(nil), indexPrefix...), tree.NewDInt(tree.DInt(s.End)), encoding.Ascending,
+		)
+		if err != nil {
+			return nil, err
+		}
+		// The end key of a KeySpan is inclusive.
+		spans = append(spans, roachpb.Span{Key: start, EndKey: roachpb.Key(end).PrefixEnd()})
+	}
+	return spans, nil
+}
+
+// fetchCandidates scans the given spans of the inverted index, and returns
+// the rows found in them that were not found by a previous scan.
+func (n *nearestNeighborNode) fetchCandidates(
+	params runParams, spans roachpb.Spans,
+) ([]nearestNeighborCandidate, error) {
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	ctx := params.ctx
+	traceKV := params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	if err := n.run.indexFetcher.StartScan(
+		ctx, params.p.txn, spans, false /* limitBatches */, 0 /* limitHint */, traceKV,
+	); err != nil {
+		return nil, err
+	}
+
+	codec := params.ExecCfg().Codec
+	colIdxMap := n.desc.ColumnIdxMap()
+	primaryPrefix := sqlbase.MakeIndexKeyPrefix(codec, n.desc.TableDesc(), n.desc.PrimaryIndex.ID)
+	pkValues := make(tree.Datums, len(n.desc.Columns))
+	var primarySpans roachpb.Spans
+	for {
+		encRow, _, _, err := n.run.indexFetcher.NextRow(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if encRow == nil {
+			break
+		}
+		// Only decode the primary key columns; the value of the indexed column in
+		// the inverted index is an S2 cell ID, not a shape.
+		for _, id := range n.desc.PrimaryIndex.ColumnIDs {
+			idx := colIdxMap[id]
+			if err := encRow[idx].EnsureDecoded(n.desc.Columns[idx].Type, &n.run.alloc); err != nil {
+				return nil, err
+			}
+			pkValues[idx] = encRow[idx].Datum
+		}
+		key, _, err := sqlbase.EncodeIndexKey(
+			n.desc.TableDesc(), &n.desc.PrimaryIndex, colIdxMap, pkValues, primaryPrefix,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := n.run.seen[string(key)]; ok {
+			continue
+		}
+		n.run.seen[string(key)] = struct{}{}
+		primarySpans = append(primarySpans, roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()})
+	}
+	if len(primarySpans) == 0 {
+		return nil, nil
+	}
+	sort.Sort(primarySpans)
+	return n.fetchRows(params, primarySpans, 0 /* limit */)
+}
+
+// fetchRows scans the given spans of the primary index and returns the rows
+// in them along with their distances from the origin. Rows that were already
+// returned by fetchCandidates are skipped when scanning for rows with empty
+// shapes. If limit is positive, at most limit rows are returned.
+func (n *nearestNeighborNode) fetchRows(
+	params runParams, spans roachpb.Spans, limit int,
+) ([]nearestNeighborCandidate, error) {
+	ctx := params.ctx
+	traceKV := params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	if err := n.run.primaryFetcher.StartScan(
+		ctx, params.p.txn, spans, false /* limitBatches */, 0 /* limitHint */, traceKV,
+	); err != nil {
+		return nil, err
+	}
+
+	codec := params.ExecCfg().Codec
+	colIdxMap := n.desc.ColumnIdxMap()
+	primaryPrefix := sqlbase.MakeIndexKeyPrefix(codec, n.desc.TableDesc(), n.desc.PrimaryIndex.ID)
+	var rows []nearestNeighborCandidate
+	for limit <= 0 || len(rows) < limit {
+		datums, _, _, err := n.run.primaryFetcher.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if datums == nil {
+			break
+		}
+		if limit > 0 {
+			// This is the scan for rows with empty shapes.
+			key, _, err := sqlbase.EncodeIndexKey(
+				n.desc.TableDesc(), &n.desc.PrimaryIndex, colIdxMap, datums, primaryPrefix,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := n.run.seen[string(key)]; ok {
+				continue
+			}
+		}
+		d, err := n.run.distanceFn.Fn(params.EvalContext(), datums[n.geoColIdx], n.origin)
+		if err != nil {
+			return nil, err
+		}
+		distance := float64(*d.(*tree.DFloat))
+
+		values := make(tree.Datums, 0, n.neededCols.Len()+1)
+		var size uintptr
+		n.neededCols.ForEach(func(i int) {
+			values = append(values, datums[i])
+			size += datums[i].Size()
+		})
+		values = append(values, d)
+		size += d.Size()
+		if err := n.run.acc.Grow(ctx, int64(size)); err != nil {
+			return nil, err
+		}
+		rows = append(rows, nearestNeighborCandidate{values: values, distance: distance})
+	}
+	return rows, nil
+}
+
+// Next is part of the planNode interface.
+func (n *nearestNeighborNode) Next(params runParams) (bool, error) {
+	if n.run.rowIdx >= len(n.run.rows) {
+		return false, nil
+	}
+	n.run.rowIdx++
+	return true, nil
+}
+
+// Values is part of the planNode interface.
+func (n *nearestNeighborNode) Values() tree.Datums {
+	return n.run.rows[n.run.rowIdx-1].values
+}
+
+// Close is part of the planNode interface.
+func (n *nearestNeighborNode) Close(ctx context.Context) {
+	n.run.rows = nil
+	n.run.seen = nil
+	n.run.acc.Close(ctx)
+}
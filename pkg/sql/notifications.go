@@ -0,0 +1,450 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// maxNotificationPayloadLength is the maximum length of the payload of a
+// NOTIFY statement. It matches the default limit in Postgres.
+const maxNotificationPayloadLength = 8000
+
+// notificationsTTL controls how long rows are kept in system.notifications.
+// Notifications are delivered to listeners as soon as they are committed, so
+// the rows only need to outlive the rangefeed catch-up window.
+var notificationsTTL = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.notifications.ttl",
+	"the amount of time for which sent notifications are retained in system.notifications",
+	time.Hour,
+)
+
+// notificationsCleanupInterval is the interval at which expired rows are
+// removed from system.notifications.
+var notificationsCleanupInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.notifications.cleanup_interval",
+	"the interval at which expired notifications are removed from system.notifications, "+
+		"set to zero to disable",
+	10*time.Minute,
+)
+
+// Notification is an asynchronous message sent with NOTIFY to the sessions
+// that are listening on its channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// NodeID is the ID of the node of the session that sent the notification.
+	// It is reported to clients in place of the process ID of the sender.
+	NodeID int32
+}
+
+// NotificationRegistry fans out the notifications written to
+// system.notifications by any node of the cluster to the local sessions that
+// are listening on their channel. Notifications are discovered through a
+// rangefeed over the table, so they are only seen once the transaction that
+// sent them has committed.
+type NotificationRegistry struct {
+	settings   *cluster.Settings
+	codec      keys.SQLCodec
+	distSender *kvcoord.DistSender
+	clock      *hlc.Clock
+	ie         *InternalExecutor
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps each channel to the sessions listening on it.
+		listeners map[string]map[*sessionNotifications]struct{}
+	}
+}
+
+// NewNotificationRegistry creates a new NotificationRegistry. Start needs to
+// be called for notifications to be delivered.
+func NewNotificationRegistry(
+	settings *cluster.Settings,
+	codec keys.SQLCodec,
+	distSender *kvcoord.DistSender,
+	clock *hlc.Clock,
+	ie *InternalExecutor,
+) *NotificationRegistry {
+	r := &NotificationRegistry{
+		settings:   settings,
+		codec:      codec,
+		distSender: distSender,
+		clock:      clock,
+		ie:         ie,
+	}
+	r.mu.listeners = make(map[string]map[*sessionNotifications]struct{})
+	return r
+}
+
+// Start starts the rangefeed that watches system.notifications and the
+// periodic cleanup of expired notifications.
+func (r *NotificationRegistry) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	r.watchNotifications(ctx, stopper)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "notifications-cleanup", r.cleanupLoop)
+}
+
+// watchNotifications runs a rangefeed over system.notifications, restarting
+// it from the last resolved timestamp whenever it fails.
+func (r *NotificationRegistry) watchNotifications(ctx context.Context, stopper *stop.Stopper) {
+	prefix := r.codec.TablePrefix(uint32(sqlbase.NotificationsTable.ID))
+	span := roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+
+	var resolved struct {
+		syncutil.Mutex
+		ts hlc.Timestamp
+	}
+	resolved.ts = r.clock.Now()
+
+	eventCh := make(chan *roachpb.RangeFeedEvent)
+	if err := stopper.RunAsyncTask(ctx, "notifications rangefeed", func(ctx context.Context) {
+		for {
+			resolved.Lock()
+			ts := resolved.ts
+			resolved.Unlock()
+			const withDiff = false
+			log.VEventf(ctx, 1, "starting notifications rangefeed from %v on %v", ts, span)
+			err := r.distSender.RangeFeed(ctx, span, ts, withDiff, eventCh)
+			if err != nil && ctx.Err() == nil {
+				log.Warningf(ctx, "notifications rangefeed failed, restarting: %v", err)
+			}
+			if ctx.Err() != nil {
+				log.VEventf(ctx, 1, "exiting notifications rangefeed")
+				return
+			}
+		}
+	}); err != nil {
+		// This will only fail if the stopper has been stopped.
+		return
+	}
+
+	// seen contains the keys of the notifications delivered above the
+	// resolved timestamp. A restarted rangefeed replays these, and they
+	// must not be delivered twice.
+	seen := make(map[string]hlc.Timestamp)
+	alloc := &sqlbase.DatumAlloc{}
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-eventCh:
+				switch {
+				case e.Checkpoint != nil:
+					resolved.Lock()
+					if resolved.ts.Less(e.Checkpoint.ResolvedTS) {
+						resolved.ts = e.Checkpoint.ResolvedTS
+					}
+					for k, ts := range seen {
+						if ts.LessEq(resolved.ts) {
+							delete(seen, k)
+						}
+					}
+					resolved.Unlock()
+				case e.Error != nil:
+					log.Warningf(ctx, "got an error from the notifications rangefeed: %v", e.Error.Error)
+				case e.Val != nil:
+					// Expired notifications are deleted by the cleanup loop.
+					if len(e.Val.Value.RawBytes) == 0 {
+						continue
+					}
+					if _, ok := seen[string(e.Val.Key)]; ok {
+						continue
+					}
+					seen[string(e.Val.Key)] = e.Val.Value.Timestamp
+					n, err := decodeNotification(alloc, e.Val.Value)
+					if err != nil {
+						log.Warningf(ctx, "%s: unable to decode notification: %v", e.Val.Key, err)
+						continue
+					}
+					r.deliver(n)
+				}
+			}
+		}
+	})
+}
+
+// decodeNotification decodes the value of a row of system.notifications. All
+// the columns of the table are in a single family, so the value is a tuple.
+func decodeNotification(alloc *sqlbase.DatumAlloc, v roachpb.Value) (Notification, error) {
+	var n Notification
+	b, err := v.GetTuple()
+	if err != nil {
+		return n, err
+	}
+	var colID sqlbase.ColumnID
+	for len(b) > 0 {
+		_, _, colIDDiff, _, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return n, err
+		}
+		colID += sqlbase.ColumnID(colIDDiff)
+		col, err := sqlbase.NotificationsTable.FindColumnByID(colID)
+		if err != nil {
+			return n, err
+		}
+		var d tree.Datum
+		if d, b, err = sqlbase.DecodeTableValue(alloc, col.Type, b); err != nil {
+			return n, err
+		}
+		switch col.Name {
+		case "channel":
+			n.Channel = string(tree.MustBeDString(d))
+		case "payload":
+			n.Payload = string(tree.MustBeDString(d))
+		case "node_id":
+			n.NodeID = int32(tree.MustBeDInt(d))
+		}
+	}
+	return n, nil
+}
+
+// deliver queues the notification on all the sessions listening on its
+// channel.
+func (r *NotificationRegistry) deliver(n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for s := range r.mu.listeners[n.Channel] {
+		s.enqueue(n)
+	}
+}
+
+func (r *NotificationRegistry) listen(channel string, s *sessionNotifications) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions, ok := r.mu.listeners[channel]
+	if !ok {
+		sessions = make(map[*sessionNotifications]struct{})
+		r.mu.listeners[channel] = sessions
+	}
+	sessions[s] = struct{}{}
+}
+
+func (r *NotificationRegistry) unlisten(channel string, s *sessionNotifications) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := r.mu.listeners[channel]
+	delete(sessions, s)
+	if len(sessions) == 0 {
+		delete(r.mu.listeners, channel)
+	}
+}
+
+// cleanupLoop periodically deletes the notifications older than
+// sql.notifications.ttl.
+func (r *NotificationRegistry) cleanupLoop(ctx context.Context) {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		interval := notificationsCleanupInterval.Get(&r.settings.SV)
+		disabled := interval == 0
+		if disabled {
+			// Check again later whether the cleanup has been re-enabled.
+			interval = time.Minute
+		}
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Read = true
+		}
+		if disabled || !r.settings.Version.IsActive(ctx, clusterversion.VersionListenNotify) {
+			continue
+		}
+		if err := r.deleteExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "error deleting expired notifications: %v", err)
+		}
+	}
+}
+
+func (r *NotificationRegistry) deleteExpired(ctx context.Context) error {
+	const batchSize = 1000
+	cutoff := timeutil.Now().Add(-notificationsTTL.Get(&r.settings.SV))
+	for {
+		n, err := r.ie.ExecEx(
+			ctx, "delete-expired-notifications", nil, /* txn */
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`DELETE FROM system.notifications WHERE created < $1 LIMIT $2`,
+			cutoff, batchSize,
+		)
+		if err != nil {
+			return err
+		}
+		if n < batchSize {
+			return nil
+		}
+	}
+}
+
+// sendNotification writes a notification to system.notifications in the
+// given transaction. It will be delivered once the transaction commits.
+func (r *NotificationRegistry) sendNotification(
+	ctx context.Context, txn *kv.Txn, nodeID base.SQLInstanceID, channel, payload string,
+) error {
+	_, err := r.ie.ExecEx(
+		ctx, "notify", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`INSERT INTO system.notifications (channel, payload, node_id) VALUES ($1, $2, $3)`,
+		channel, payload, int64(nodeID),
+	)
+	return err
+}
+
+// notificationOp is a LISTEN or UNLISTEN staged in a transaction.
+type notificationOp struct {
+	channel string
+	listen  bool
+	// all is set for UNLISTEN *.
+	all bool
+}
+
+// sessionNotifications holds the LISTEN state of a session, as well as the
+// notifications waiting to be sent to its client.
+//
+// Notifications are queued by the registry and the session is signaled by
+// pushing a DeliverNotifications command in its StmtBuf. The connExecutor
+// only sends them to the client in between transactions.
+type sessionNotifications struct {
+	registry *NotificationRegistry
+	stmtBuf  *StmtBuf
+
+	// channels is the set of channels the session is listening on. It is only
+	// accessed by the connExecutor's goroutine.
+	channels map[string]struct{}
+	// staged are the LISTEN and UNLISTEN operations of the current
+	// transaction. They are applied when the transaction commits.
+	staged []notificationOp
+
+	mu struct {
+		syncutil.Mutex
+		queue []Notification
+		// signaled is set when a DeliverNotifications command has been pushed
+		// to the StmtBuf and not yet processed.
+		signaled bool
+	}
+}
+
+func (r *NotificationRegistry) newSession(stmtBuf *StmtBuf) *sessionNotifications {
+	return &sessionNotifications{
+		registry: r,
+		stmtBuf:  stmtBuf,
+		channels: make(map[string]struct{}),
+	}
+}
+
+// stage records a LISTEN or UNLISTEN operation, to be applied if the current
+// transaction commits.
+func (s *sessionNotifications) stage(op notificationOp) {
+	s.staged = append(s.staged, op)
+}
+
+// finishTxn applies or discards the operations staged in the transaction
+// that just finished.
+func (s *sessionNotifications) finishTxn(commit bool) {
+	if commit {
+		for _, op := range s.staged {
+			switch {
+			case op.all:
+				for channel := range s.channels {
+					s.registry.unlisten(channel, s)
+					delete(s.channels, channel)
+				}
+			case op.listen:
+				if _, ok := s.channels[op.channel]; !ok {
+					s.registry.listen(op.channel, s)
+					s.channels[op.channel] = struct{}{}
+				}
+			default:
+				if _, ok := s.channels[op.channel]; ok {
+					s.registry.unlisten(op.channel, s)
+					delete(s.channels, op.channel)
+				}
+			}
+		}
+	}
+	s.staged = s.staged[:0]
+}
+
+// close stops listening on all the channels.
+func (s *sessionNotifications) close() {
+	s.staged = s.staged[:0]
+	for channel := range s.channels {
+		s.registry.unlisten(channel, s)
+	}
+	s.channels = nil
+}
+
+// enqueue queues a notification for delivery, signaling the connExecutor
+// if it hasn't already been signaled.
+func (s *sessionNotifications) enqueue(n Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.queue = append(s.mu.queue, n)
+	s.signalLocked()
+}
+
+// resignal is called by the connExecutor at the end of every batch of
+// commands. A DeliverNotifications command might have been skipped, for
+// example if a command of the batch failed, or processed while a
+// transaction was open, so the connExecutor is signaled again if
+// notifications are still pending.
+func (s *sessionNotifications) resignal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.signaled = false
+	if len(s.mu.queue) > 0 {
+		s.signalLocked()
+	}
+}
+
+func (s *sessionNotifications) signalLocked() {
+	if s.mu.signaled {
+		return
+	}
+	// The push can only fail if the StmtBuf is closed, in which case the
+	// session is terminating.
+	if err := s.stmtBuf.Push(context.Background(), DeliverNotifications{}); err == nil {
+		s.mu.signaled = true
+	}
+}
+
+// takeAll returns the pending notifications and clears the queue.
+func (s *sessionNotifications) takeAll() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := s.mu.queue
+	s.mu.queue = nil
+	s.mu.signaled = false
+	return queue
+}
@@ -179,6 +179,18 @@ func (f *stubFactory) ConstructGeoLookupJoin(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructNearestNeighborScan(
+	table cat.Table,
+	index cat.Index,
+	needed exec.TableColumnOrdinalSet,
+	geoCol exec.TableColumnOrdinal,
+	origin tree.Datum,
+	k int64,
+	reqOrdering exec.OutputOrdering,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructZigzagJoin(
 	leftTable cat.Table,
 	leftIndex cat.Index,
@@ -220,7 +232,7 @@ func (f *stubFactory) RenameColumns(input exec.Node, colNames []string) (exec.No
 }
 
 func (f *stubFactory) ConstructPlan(
-	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Node,
+	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Check,
 ) (exec.Plan, error) {
 	return struct{}{}, nil
 }
@@ -393,6 +405,7 @@ func (f *stubFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -400,6 +413,12 @@ func (f *stubFactory) ConstructCreateView(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, body string, deps opt.ViewDeps,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructExport(
 	input exec.Node, fileName tree.TypedExpr, fileFormat string, options []exec.KVOption,
 ) (exec.Node, error) {
@@ -80,6 +80,16 @@ type Column interface {
 	// computed columns, but they can depend on all other columns, including
 	// columns with default values.
 	ComputedExprStr() string
+
+	// IsGeneratedAsIdentity returns true if the column is an identity column.
+	// The default value of an identity column is drawn from a sequence, and
+	// explicit values are ignored when inserting with OVERRIDING USER VALUE.
+	IsGeneratedAsIdentity() bool
+
+	// IsGeneratedAlwaysAsIdentity returns true if the column is an identity
+	// column into which explicit values can only be inserted with OVERRIDING
+	// SYSTEM VALUE.
+	IsGeneratedAlwaysAsIdentity() bool
 }
 
 // IsMutationColumn is a convenience function that returns true if the column at
@@ -41,6 +41,16 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table is actually a materialized
+	// view. Materialized views are the same as tables in all aspects, other than
+	// that they cannot be mutated.
+	IsMaterializedView() bool
+
+	// IsForeignTable returns true if this table is a foreign table, whose rows
+	// are read from files in external storage. Foreign tables cannot be
+	// mutated.
+	IsForeignTable() bool
+
 	// ColumnCount returns the number of public columns in the table. Public
 	// columns are not currently being added or dropped from the table. This
 	// method should be used when mutation columns can be ignored (the common
@@ -132,6 +142,35 @@ type Table interface {
 
 	// InboundForeignKey returns the ith inbound foreign key reference.
 	InboundForeignKey(i int) ForeignKeyConstraint
+
+	// TriggerCount returns the number of row-level triggers on the table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger, where i < TriggerCount. Triggers are
+	// ordered by name, which is the order in which they are fired.
+	Trigger(i int) Trigger
+}
+
+// Trigger describes a row-level trigger on a table, created by CREATE TRIGGER.
+// The trigger executes Body for every row modified by a statement matching one
+// of its Events, either before or after the row is modified.
+//
+// Body is the statement of the trigger function, in which references to the
+// columns of the NEW and OLD rows are replaced by placeholders: if the table
+// has n public columns, $1 to $n are the NEW values of the columns in ordinal
+// order, and $n+1 to $2n are the OLD values. The NEW values are NULL for a
+// DELETE and the OLD values are NULL for an INSERT.
+//
+// If ReturnsRows is set, the first row returned by Body is the new value of
+// the row in a BEFORE trigger, as the values of the visible columns of the
+// table; if no row is returned, the modification of the row is skipped. The
+// result is ignored in an AFTER trigger.
+type Trigger struct {
+	Name        tree.Name
+	ActionTime  tree.TriggerActionTime
+	Events      tree.TriggerEvents
+	Body        string
+	ReturnsRows bool
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -245,4 +284,9 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checking of the constraint can be
+	// deferred until the end of the transaction, and whether it is deferred by
+	// default.
+	Deferrability() tree.ConstraintDeferrability
 }
@@ -155,6 +155,17 @@ func FindTableColumnByName(tab Table, name tree.Name) int {
 	return -1
 }
 
+// HasTriggers returns true if the table has at least one trigger fired by the
+// given event.
+func HasTriggers(tab Table, event tree.TriggerEvent) bool {
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		if tab.Trigger(i).Events.Contains(event) {
+			return true
+		}
+	}
+	return false
+}
+
 // FormatTable nicely formats a catalog table using a treeprinter for debugging
 // and testing.
 func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
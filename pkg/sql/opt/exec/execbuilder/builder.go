@@ -42,7 +42,7 @@ type Builder struct {
 
 	// checks accumulates check queries that are run after the main query and
 	// any cascades.
-	checks []exec.Node
+	checks []exec.Check
 
 	// nameGen is used to generate names for the tables that will be created for
 	// each relational subexpression when evalCtx.SessionData.SaveTablesPrefix is
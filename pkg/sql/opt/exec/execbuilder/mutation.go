@@ -113,6 +113,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -132,6 +136,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	//  - there are no cascading queries (e.g. AFTER triggers);
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	//  - the input is Values with at most InsertFastPathMaxRows, and there are no
 	//    subqueries;
 	values, ok := ins.Input.(*memo.ValuesExpr)
@@ -487,6 +496,12 @@ func (b *Builder) tryBuildDeleteRange(del *memo.DeleteExpr) (_ execPlan, ok bool
 	}
 
 	tab := b.mem.Metadata().Table(del.Table)
+
+	// Triggers are executed for each deleted row, so the rows must be fetched.
+	if cat.HasTriggers(tab, tree.TriggerEventDelete) {
+		return execPlan{}, false, nil
+	}
+
 	if tab.DeletableIndexCount() > 1 {
 		// Any secondary index prevents fast path, because separate delete batches
 		// must be formulated to delete rows from them.
@@ -573,6 +588,9 @@ func (b *Builder) tryBuildDeleteRangeOnInterleaving(
 	// match the interleaving (i.e. a prefix of the PK of the child references the
 	// PK of the ancestor).
 	for _, parent := range queue {
+		if cat.HasTriggers(parent, tree.TriggerEventDelete) {
+			return execPlan{}, false, nil
+		}
 		for i, n := 0, parent.InboundForeignKeyCount(); i < n; i++ {
 			fk := parent.InboundForeignKey(i)
 			child, ok := tables[fk.OriginTableID()]
@@ -727,23 +745,51 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 		if err != nil {
 			return err
 		}
-		// Wrap the query in an error node.
-		mkErr := func(row tree.Datums) error {
+		keyVals := func(row tree.Datums) tree.Datums {
 			keyVals := make(tree.Datums, len(c.KeyCols))
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
-			return mkFKCheckErr(md, c, keyVals)
+			return keyVals
+		}
+		// Wrap the query in an error node.
+		mkErr := func(row tree.Datums) error {
+			return mkFKCheckErr(md, c, keyVals(row))
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		check := exec.Check{Root: node}
+		if fk := deferrableFK(md, c); fk != nil {
+			check.DeferrableFK = fk
+			check.KeyVals = keyVals
+		}
+		b.checks = append(b.checks, check)
 	}
 	return nil
 }
 
+// deferrableFK returns the foreign key verified by the given check if the
+// check can be deferred until the transaction commits, or nil otherwise. Only
+// validated DEFERRABLE constraints can be deferred; checks for RESTRICT actions
+// are never deferred.
+func deferrableFK(md *opt.Metadata, c *memo.FKChecksItem) cat.ForeignKeyConstraint {
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+		if fk.DeleteReferenceAction() == tree.Restrict || fk.UpdateReferenceAction() == tree.Restrict {
+			return nil
+		}
+	}
+	if fk.Deferrability() == tree.NotDeferrable || !fk.Validated() {
+		return nil
+	}
+	return fk
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
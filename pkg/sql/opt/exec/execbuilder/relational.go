@@ -190,6 +190,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.ScanExpr:
 		ep, err = b.buildScan(t)
 
+	case *memo.NearestNeighborScanExpr:
+		ep, err = b.buildNearestNeighborScan(t)
+
 	case *memo.SelectExpr:
 		ep, err = b.buildSelect(t)
 
@@ -257,6 +260,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.CreateViewExpr:
 		ep, err = b.buildCreateView(t)
 
+	case *memo.CreateFunctionExpr:
+		ep, err = b.buildCreateFunction(t)
+
 	case *memo.WithExpr:
 		ep, err = b.buildWith(t)
 
@@ -515,6 +521,31 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 	return res, nil
 }
 
+func (b *Builder) buildNearestNeighborScan(scan *memo.NearestNeighborScanExpr) (execPlan, error) {
+	md := b.mem.Metadata()
+	tab := md.Table(scan.Table)
+
+	// The node produces the needed table columns, followed by the distance.
+	needed, output := b.getColumns(scan.Cols, scan.Table)
+	output.Set(int(scan.DistanceCol), needed.Len())
+	res := execPlan{outputCols: output}
+
+	root, err := b.factory.ConstructNearestNeighborScan(
+		tab,
+		tab.Index(scan.Index),
+		needed,
+		exec.TableColumnOrdinal(scan.Table.ColumnOrdinal(scan.GeoCol)),
+		scan.Origin,
+		int64(scan.K),
+		res.reqOrdering(scan),
+	)
+	if err != nil {
+		return execPlan{}, err
+	}
+	res.root = root
+	return res, nil
+}
+
 func (b *Builder) buildSelect(sel *memo.SelectExpr) (execPlan, error) {
 	input, err := b.buildRelational(sel.Input)
 	if err != nil {
@@ -264,7 +264,18 @@ func (b *Builder) buildFunction(
 			return nil, err
 		}
 	}
-	funcRef := tree.WrapFunction(fn.Name)
+	var funcRef tree.ResolvableFunctionReference
+	if fn.Properties.UserDefined {
+		// User-defined functions cannot be looked up by name among the builtins.
+		props := *fn.Properties
+		funcRef = tree.ResolvableFunctionReference{
+			FunctionReference: tree.NewUserDefinedFunctionDefinition(
+				fn.Name, &props, []tree.Overload{*fn.Overload},
+			),
+		}
+	} else {
+		funcRef = tree.WrapFunction(fn.Name)
+	}
 	return tree.NewTypedFuncExpr(
 		funcRef,
 		0, /* aggQualifier */
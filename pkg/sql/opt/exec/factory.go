@@ -80,6 +80,23 @@ type Factory interface {
 		locking *tree.LockingItem,
 	) (Node, error)
 
+	// ConstructNearestNeighborScan returns a node that returns the k rows of the
+	// given table whose geospatial column geoCol is closest to the origin, using
+	// the given geospatial inverted index to find them.
+	//   - Only the given set of needed columns are part of the result, followed
+	//     by a FLOAT column containing the distance of each row from the origin
+	//     (as computed by the <-> operator).
+	//   - The rows are returned in increasing order of distance.
+	ConstructNearestNeighborScan(
+		table cat.Table,
+		index cat.Index,
+		needed TableColumnOrdinalSet,
+		geoCol TableColumnOrdinal,
+		origin tree.Datum,
+		k int64,
+		reqOrdering OutputOrdering,
+	) (Node, error)
+
 	// ConstructFilter returns a node that applies a filter on the results of
 	// the given input node.
 	ConstructFilter(n Node, filter tree.TypedExpr, reqOrdering OutputOrdering) (Node, error)
@@ -309,7 +326,7 @@ type Factory interface {
 	// Checks are executed after all cascades have been executed. They don't
 	// return results but can generate errors (e.g. foreign key check failures).
 	ConstructPlan(
-		root Node, subqueries []Subquery, cascades []Cascade, checks []Node,
+		root Node, subqueries []Subquery, cascades []Cascade, checks []Check,
 	) (Plan, error)
 
 	// ConstructExplain returns a node that implements EXPLAIN (OPT), showing
@@ -498,11 +515,18 @@ type Factory interface {
 		ifNotExists bool,
 		replace bool,
 		temporary bool,
+		materialized bool,
 		viewQuery string,
 		columns sqlbase.ResultColumns,
 		deps opt.ViewDeps,
 	) (Node, error)
 
+	// ConstructCreateFunction returns a node that implements a CREATE FUNCTION
+	// statement.
+	ConstructCreateFunction(
+		schema cat.Schema, cf *tree.CreateFunction, body string, deps opt.ViewDeps,
+	) (Node, error)
+
 	// ConstructSequenceSelect creates a node that implements a scan of a sequence
 	// as a data source.
 	ConstructSequenceSelect(sequence cat.Sequence) (Node, error)
@@ -748,6 +772,26 @@ type Cascade struct {
 	) (Plan, error)
 }
 
+// Check describes a query that is executed after the main query and its
+// cascades. It doesn't return results but can generate an error (e.g. a
+// foreign key violation).
+type Check struct {
+	// Root is the Node that generates the error, as returned by
+	// ConstructErrorIfRows.
+	Root Node
+
+	// DeferrableFK is set if the check verifies a DEFERRABLE foreign key
+	// constraint; the execution engine can record the violations found by the
+	// check instead of failing, and verify them again when the transaction
+	// commits.
+	DeferrableFK cat.ForeignKeyConstraint
+
+	// KeyVals returns the values of the foreign key columns in a row produced
+	// by the input of Root, i.e. the key of a violation. It is set along with
+	// DeferrableFK.
+	KeyVals func(row tree.Datums) tree.Datums
+}
+
 // InsertFastPathMaxRows is the maximum number of rows for which we can use the
 // insert fast path.
 const InsertFastPathMaxRows = 10000
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -952,6 +953,39 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForArrayExpr(
 	return len(arr.Array) == 1, constraints
 }
 
+// makeInvertedIndexSpansForTSQueryExpr is the implementation of
+// makeInvertedIndexSpans for tsvector inverted indexes. The input query is the
+// tsquery to produce spans for; there is one span for every lexeme that the
+// matching documents must contain. If allPaths is true, the slice is populated
+// with all constraints found. Otherwise, this function stops at the first
+// constraint.
+func (c *indexConstraintCtx) makeInvertedIndexSpansForTSQueryExpr(
+	q *tree.DTSQuery, constraints []*constraint.Constraint, allPaths bool,
+) (bool, []*constraint.Constraint) {
+	lexemes := q.RequiredLexemes()
+	if len(lexemes) == 0 {
+		// The query can match documents which don't contain any lexeme, or only
+		// lexemes with a given prefix.
+		out := &constraint.Constraint{}
+		c.unconstrained(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+
+	for _, l := range lexemes {
+		out := &constraint.Constraint{}
+		v := tree.NewDTSVector(tsearch.TSVector{{Text: l}})
+		c.eqSpan(0 /* offset */, v, out)
+		constraints = append(constraints, out)
+
+		if !allPaths {
+			break
+		}
+	}
+	// The spans are never tight, since the positions, weights and operators of
+	// the query must still be checked.
+	return false, constraints
+}
+
 // makeInvertedIndexSpansForExpr is analogous to makeSpansForExpr, but it is
 // used for inverted indexes. If allPaths is true, the slice is populated with
 // all constraints found. Otherwise, this function stops at the first
@@ -993,6 +1027,30 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			log.Errorf(context.TODO(), "unexpected type in inverted index: %s", rightDatum.ResolvedType())
 		}
 
+	case opt.TSMatchesOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+		if c.isIndexColumn(rhs, 0 /* index */) {
+			// The match operator is commutative.
+			lhs, rhs = rhs, lhs
+		}
+
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			out := &constraint.Constraint{}
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+		if rightDatum == tree.DNull {
+			out := &constraint.Constraint{}
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		if q, ok := tree.AsDTSQuery(rightDatum); ok {
+			return c.makeInvertedIndexSpansForTSQueryExpr(q, constraints, allPaths)
+		}
+		log.Errorf(context.TODO(), "unexpected type in inverted index: %s", rightDatum.ResolvedType())
+
 	case opt.AndOp, opt.FiltersOp:
 		var out *constraint.Constraint
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
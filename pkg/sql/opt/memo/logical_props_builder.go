@@ -133,6 +133,53 @@ func (b *logicalPropsBuilder) buildScanProps(scan *ScanExpr, rel *props.Relation
 	}
 }
 
+func (b *logicalPropsBuilder) buildNearestNeighborScanProps(
+	scan *NearestNeighborScanExpr, rel *props.Relational,
+) {
+	md := scan.Memo().Metadata()
+
+	// Output Columns
+	// --------------
+	// Output columns are the projected table columns, plus the distance column.
+	rel.OutputCols = scan.Cols.Copy()
+	rel.OutputCols.Add(scan.DistanceCol)
+
+	// Not Null Columns
+	// ----------------
+	// Initialize not-NULL columns from the table schema. The distance is never
+	// NULL, since the geospatial column is not nullable.
+	rel.NotNullCols = tableNotNullCols(md, scan.Table)
+	rel.NotNullCols.Add(scan.DistanceCol)
+	rel.NotNullCols.IntersectionWith(rel.OutputCols)
+
+	// Outer Columns
+	// -------------
+	// NearestNeighborScan operator never has outer columns.
+
+	// Functional Dependencies
+	// -----------------------
+	// Initialize key FD's from the table schema. The distance is determined by
+	// the geospatial column.
+	rel.FuncDeps.CopyFrom(MakeTableFuncDep(md, scan.Table))
+	rel.FuncDeps.AddSynthesizedCol(opt.MakeColSet(scan.GeoCol), scan.DistanceCol)
+	rel.FuncDeps.MakeNotNull(rel.NotNullCols)
+	rel.FuncDeps.ProjectCols(rel.OutputCols)
+
+	// Cardinality
+	// -----------
+	// The scan returns at most K rows.
+	rel.Cardinality = props.AnyCardinality
+	if scan.K < math.MaxUint32 {
+		rel.Cardinality = rel.Cardinality.Limit(uint32(scan.K))
+	}
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildNearestNeighborScan(scan, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildSequenceSelectProps(
 	seq *SequenceSelectExpr, rel *props.Relational,
 ) {
@@ -1292,6 +1339,12 @@ func (b *logicalPropsBuilder) buildCreateViewProps(cv *CreateViewExpr, rel *prop
 	BuildSharedProps(cv, &rel.Shared)
 }
 
+func (b *logicalPropsBuilder) buildCreateFunctionProps(
+	cf *CreateFunctionExpr, rel *props.Relational,
+) {
+	BuildSharedProps(cf, &rel.Shared)
+}
+
 func (b *logicalPropsBuilder) buildFiltersItemProps(item *FiltersItem, scalar *props.Scalar) {
 	BuildSharedProps(item.Condition, &scalar.Shared)
 
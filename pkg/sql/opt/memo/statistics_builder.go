@@ -206,6 +206,9 @@ func (sb *statisticsBuilder) availabilityFromInput(e RelExpr) bool {
 	case *ScanExpr:
 		return sb.makeTableStatistics(t.Table).Available
 
+	case *NearestNeighborScanExpr:
+		return sb.makeTableStatistics(t.Table).Available
+
 	case *LookupJoinExpr:
 		ensureLookupJoinInputProps(t, sb)
 		return t.lookupProps.Stats.Available && t.Input.Relational().Stats.Available
@@ -348,6 +351,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.ScanOp:
 		return sb.colStatScan(colSet, e.(*ScanExpr))
 
+	case opt.NearestNeighborScanOp:
+		return sb.colStatNearestNeighborScan(colSet, e.(*NearestNeighborScanExpr))
+
 	case opt.SelectOp:
 		return sb.colStatSelect(colSet, e.(*SelectExpr))
 
@@ -714,6 +720,59 @@ func (sb *statisticsBuilder) colStatScan(colSet opt.ColSet, scan *ScanExpr) *pro
 	return colStat
 }
 
+// +-----------------------+
+// | Nearest Neighbor Scan |
+// +-----------------------+
+
+func (sb *statisticsBuilder) buildNearestNeighborScan(
+	scan *NearestNeighborScanExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(scan)
+
+	inputStats := sb.makeTableStatistics(scan.Table)
+	s.RowCount = inputStats.RowCount
+	if inputStats.RowCount > 0 {
+		s.RowCount = min(float64(scan.K), inputStats.RowCount)
+		s.Selectivity = s.RowCount / inputStats.RowCount
+	}
+
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatNearestNeighborScan(
+	colSet opt.ColSet, scan *NearestNeighborScanExpr,
+) *props.ColumnStatistic {
+	relProps := scan.Relational()
+	s := &relProps.Stats
+
+	if colSet.Contains(scan.DistanceCol) {
+		// The distance column is not part of the table, so there are no table
+		// statistics for it. Assume the distances are all distinct.
+		colStat, _ := s.ColStats.Add(colSet)
+		colStat.DistinctCount = s.RowCount
+		colStat.NullCount = 0
+		sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+		return colStat
+	}
+
+	inputColStat := sb.colStatTable(scan.Table, colSet)
+	colStat := sb.copyColStat(colSet, s, inputColStat)
+	if s.Selectivity != 1 {
+		tableStats := sb.makeTableStatistics(scan.Table)
+		colStat.ApplySelectivity(s.Selectivity, tableStats.RowCount)
+	}
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +--------+
 // | Select |
 // +--------+
@@ -542,6 +542,12 @@ func (c *CustomFuncs) FoldFunction(
 		return nil
 	}
 
+	// User-defined functions evaluate their body with the internal executor,
+	// which should not happen during optimization.
+	if private.Properties.UserDefined {
+		return nil
+	}
+
 	if !c.CanFoldOperator(private.Overload.Volatility) {
 		return nil
 	}
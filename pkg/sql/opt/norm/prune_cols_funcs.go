@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -183,6 +184,18 @@ func neededMutationFetchCols(
 		}
 	}
 
+	// Triggers have access to all the columns of the old row, which are passed
+	// to AFTER triggers through the buffered mutation input.
+	event := tree.TriggerEventUpdate
+	if op == opt.DeleteOp {
+		event = tree.TriggerEventDelete
+	}
+	if op != opt.InsertOp && cat.HasTriggers(tabMeta.Table, event) {
+		for i, n := 0, tabMeta.Table.ColumnCount(); i < n; i++ {
+			cols.Add(tabMeta.MetaID.ColumnID(i))
+		}
+	}
+
 	return cols
 }
 
@@ -139,6 +139,7 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperator{
 	JsonSomeExistsOp: tree.JSONSomeExists,
 	JsonAllExistsOp:  tree.JSONAllExists,
 	OverlapsOp:       tree.Overlaps,
+	TSMatchesOp:      tree.TSMatches,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -161,6 +162,7 @@ var BinaryOpReverseMap = map[Operator]tree.BinaryOperator{
 	FetchTextOp:     tree.JSONFetchText,
 	FetchValPathOp:  tree.JSONFetchValPath,
 	FetchTextPathOp: tree.JSONFetchTextPath,
+	DistanceOp:      tree.Distance,
 }
 
 // UnaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -203,6 +205,11 @@ var AggregateOpReverseMap = map[Operator]string{
 	AnyNotNullAggOp:   "any_not_null",
 	PercentileDiscOp:  "percentile_disc_impl",
 	PercentileContOp:  "percentile_cont_impl",
+	STAsMVTOp:         "st_asmvt",
+	STExtentOp:        "st_extent",
+	STCollectOp:       "st_collect",
+	STMakeLineOp:      "st_makeline",
+	STUnionOp:         "st_union",
 }
 
 // WindowOpReverseMap maps from an optimizer operator type to the name of a
@@ -219,6 +226,9 @@ var WindowOpReverseMap = map[Operator]string{
 	FirstValueOp:  "first_value",
 	LastValueOp:   "last_value",
 	NthValueOp:    "nth_value",
+
+	STClusterDBSCANOp: "st_clusterdbscan",
+	STClusterKMeansOp: "st_clusterkmeans",
 }
 
 // NegateOpMap maps from a comparison operator type to its negated operator
@@ -281,7 +291,8 @@ func AggregateIgnoresNulls(op Operator) bool {
 
 	case AnyNotNullAggOp, AvgOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp,
 		ConstNotNullAggOp, CorrOp, CountOp, MaxOp, MinOp, SqrDiffOp, StdDevOp,
-		StringAggOp, SumOp, SumIntOp, VarianceOp, XorAggOp, PercentileDiscOp, PercentileContOp:
+		StringAggOp, SumOp, SumIntOp, VarianceOp, XorAggOp, PercentileDiscOp, PercentileContOp,
+		STAsMVTOp, STExtentOp, STCollectOp, STMakeLineOp, STUnionOp:
 		return true
 
 	case ArrayAggOp, ConcatAggOp, ConstAggOp, CountRowsOp, FirstAggOp, JsonAggOp,
@@ -304,10 +315,11 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		ConstNotNullAggOp, CorrOp, FirstAggOp, JsonAggOp, JsonbAggOp,
 		MaxOp, MinOp, SqrDiffOp, StdDevOp, StringAggOp, SumOp, SumIntOp,
 		VarianceOp, XorAggOp, PercentileDiscOp, PercentileContOp,
-		JsonObjectAggOp, JsonbObjectAggOp:
+		JsonObjectAggOp, JsonbObjectAggOp, STExtentOp, STCollectOp,
+		STMakeLineOp, STUnionOp:
 		return true
 
-	case CountOp, CountRowsOp:
+	case CountOp, CountRowsOp, STAsMVTOp:
 		return false
 
 	default:
@@ -330,13 +342,18 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		ConstNotNullAggOp, CountOp, CountRowsOp, FirstAggOp,
 		JsonAggOp, JsonbAggOp, MaxOp, MinOp, SqrDiffOp,
 		StringAggOp, SumOp, SumIntOp, XorAggOp, PercentileDiscOp, PercentileContOp,
-		JsonObjectAggOp, JsonbObjectAggOp:
+		JsonObjectAggOp, JsonbObjectAggOp, STAsMVTOp, STCollectOp, STUnionOp:
 		return true
 
 	case VarianceOp, StdDevOp, CorrOp:
 		// These aggregations return NULL if they are given a single not-NULL input.
 		return false
 
+	case STExtentOp, STMakeLineOp:
+		// These aggregations return NULL if they are only given empty geometries,
+		// or too few points to make a line.
+		return false
+
 	default:
 		panic(errors.AssertionFailedf("unhandled op %s", log.Safe(op)))
 	}
@@ -371,7 +388,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 
 	case AnyNotNullAggOp, BitAndAggOp, BitOrAggOp, BoolAndOp,
 		BoolOrOp, ConstAggOp, ConstNotNullAggOp, FirstAggOp,
-		MaxOp, MinOp, SumOp, SumIntOp, XorAggOp:
+		MaxOp, MinOp, SumOp, SumIntOp, XorAggOp, STExtentOp, STUnionOp:
 		return inner == outer
 
 	case CountOp, CountRowsOp:
@@ -381,7 +398,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 
 	case ArrayAggOp, AvgOp, ConcatAggOp, CorrOp, JsonAggOp,
 		JsonbAggOp, PercentileContOp, PercentileDiscOp, SqrDiffOp,
-		StdDevOp, StringAggOp, VarianceOp:
+		StdDevOp, StringAggOp, VarianceOp, STAsMVTOp, STCollectOp, STMakeLineOp:
 		return false
 
 	default:
@@ -394,12 +411,13 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 func AggregateIgnoresDuplicates(op Operator) bool {
 	switch op {
 	case AnyNotNullAggOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp,
-		ConstAggOp, ConstNotNullAggOp, FirstAggOp, MaxOp, MinOp:
+		ConstAggOp, ConstNotNullAggOp, FirstAggOp, MaxOp, MinOp, STExtentOp, STUnionOp:
 		return true
 
 	case ArrayAggOp, AvgOp, ConcatAggOp, CountOp, CorrOp, CountRowsOp, SumIntOp,
 		SumOp, SqrDiffOp, VarianceOp, StdDevOp, XorAggOp, JsonAggOp, JsonbAggOp,
-		StringAggOp, PercentileDiscOp, PercentileContOp:
+		StringAggOp, PercentileDiscOp, PercentileContOp, STAsMVTOp, STCollectOp,
+		STMakeLineOp:
 		return false
 
 	default:
@@ -0,0 +1,176 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := cf.Name.ToTableName()
+	sch, _ := b.resolveSchemaForCreate(&tn)
+	schID := b.factory.Metadata().AddSchema(sch)
+
+	if cf.Options.Language == "" {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified"))
+	}
+	if lang := strings.ToLower(string(cf.Options.Language)); lang != "sql" {
+		panic(unimplemented.Newf("create function language", "language %q is not supported", lang))
+	}
+	if cf.Options.Body == "" {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified"))
+	}
+	if cf.ReturnsTrigger() {
+		return b.buildCreateTriggerFunction(cf, schID)
+	}
+
+	argTypes := make([]*types.T, len(cf.Args))
+	for i := range cf.Args {
+		argTypes[i] = b.resolveFunctionSignatureType(cf.Args[i].Type)
+	}
+	returnType := b.resolveFunctionSignatureType(cf.ReturnType)
+
+	stmt, err := parser.ParseOne(cf.Options.Body)
+	if err != nil {
+		panic(pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid function body"))
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(unimplemented.Newf("create function body",
+			"function bodies other than SELECT statements are not supported"))
+	}
+	if stmt.NumPlaceholders > len(cf.Args) {
+		panic(pgerror.Newf(pgcode.UndefinedParameter,
+			"there is no parameter $%d", stmt.NumPlaceholders))
+	}
+
+	// The arguments are referred to by name or by position ($1, $2, ...) in
+	// the body. Named arguments are exposed as outer columns, and positional
+	// arguments as placeholders of the argument types.
+	argScope := inScope.push()
+	for i := range cf.Args {
+		if cf.Args[i].Name != "" {
+			b.synthesizeColumn(argScope, string(cf.Args[i].Name), argTypes[i], nil /* expr */, nil /* scalar */)
+		}
+	}
+	defer func(prev tree.PlaceholderInfo, prevKeep bool, prevAnn tree.Annotations) {
+		b.semaCtx.Placeholders = prev
+		b.KeepPlaceholders = prevKeep
+		b.semaCtx.Annotations = prevAnn
+	}(b.semaCtx.Placeholders, b.KeepPlaceholders, b.semaCtx.Annotations)
+	b.semaCtx.Placeholders = tree.PlaceholderInfo{}
+	b.semaCtx.Placeholders.Types = argTypes
+	b.semaCtx.Placeholders.TypeHints = argTypes
+	b.KeepPlaceholders = true
+
+	// We build the body to:
+	//  - check the statement semantically,
+	//  - get the fully resolved names into the AST, and
+	//  - collect the dependencies in b.viewDeps.
+	// The result is not otherwise used.
+	b.insideViewDef = true
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+	defer func() {
+		b.insideViewDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.qualifyDataSourceNamesInAST = false
+	}()
+
+	b.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
+	b.pushWithFrame()
+	defScope := b.buildStmtAtRoot(sel, nil /* desiredTypes */, argScope)
+	b.popWithFrame(defScope)
+
+	if len(defScope.cols) != 1 {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"function body must return a single column, found %d", len(defScope.cols)))
+	}
+	ret := &tree.CastExpr{Expr: &defScope.cols[0], Type: returnType, SyntaxMode: tree.CastShort}
+	if _, err := tree.TypeCheck(b.ctx, ret, b.semaCtx, returnType); err != nil {
+		panic(pgerror.Wrapf(err, pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", returnType.SQLString()))
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema: schID,
+			Syntax: cf,
+			Body:   tree.AsStringWithFlags(sel, tree.FmtParsable),
+			Deps:   b.viewDeps,
+		},
+	)
+	return outScope
+}
+
+// buildCreateTriggerFunction builds a CREATE FUNCTION statement for a function
+// returning TRIGGER. The body of a trigger function refers to the NEW and OLD
+// rows of the table of the trigger, so it can only be checked semantically
+// when it is executed by a trigger.
+func (b *Builder) buildCreateTriggerFunction(
+	cf *tree.CreateFunction, schID opt.SchemaID,
+) (outScope *scope) {
+	if len(cf.Args) > 0 {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+			"trigger functions cannot have declared arguments"))
+	}
+	stmt, err := parser.ParseOne(cf.Options.Body)
+	if err != nil {
+		panic(pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid function body"))
+	}
+	switch stmt.AST.(type) {
+	case *tree.Select, *tree.Insert, *tree.Update, *tree.Delete:
+	default:
+		panic(unimplemented.Newf("create trigger function body",
+			"trigger function bodies other than SELECT, INSERT, UPSERT, UPDATE and DELETE "+
+				"statements are not supported"))
+	}
+	if stmt.NumPlaceholders > 0 {
+		panic(pgerror.Newf(pgcode.UndefinedParameter,
+			"there is no parameter $%d", stmt.NumPlaceholders))
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema: schID,
+			Syntax: cf,
+			Body:   tree.AsStringWithFlags(stmt.AST, tree.FmtParsable),
+		},
+	)
+	return outScope
+}
+
+// resolveFunctionSignatureType resolves the type of an argument or the return
+// type of a function.
+func (b *Builder) resolveFunctionSignatureType(ref tree.ResolvableTypeReference) *types.T {
+	typ, err := tree.ResolveType(b.ctx, ref, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+	if typ.UserDefined() {
+		panic(unimplemented.NewWithIssue(17511,
+			"user-defined types in function signatures are not supported"))
+	}
+	return typ
+}
@@ -38,6 +38,7 @@ package optbuilder
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/geo/geomvt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
@@ -252,7 +253,8 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 		return true
 	}
 	switch a.def.Name {
-	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg", "json_object_agg", "jsonb_object_agg":
+	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg", "json_object_agg", "jsonb_object_agg",
+		"st_asmvt", "st_collect", "st_makeline":
 		return true
 	default:
 		return false
@@ -682,11 +684,12 @@ func (b *Builder) buildAggregateFunction(
 	tempScope := fromScope.startAggFunc()
 	tempScopeColsBefore := len(tempScope.cols)
 
+	argExprs := getTypedAggregateArgs(def.Name, f.Exprs)
 	info := aggregateInfo{
 		FuncExpr: f,
 		def:      *def,
 		distinct: (f.Type == tree.DistinctFuncType),
-		args:     make(memo.ScalarListExpr, len(f.Exprs)),
+		args:     make(memo.ScalarListExpr, len(argExprs)),
 	}
 
 	// Temporarily set b.subquery to nil so we don't add outer columns to the
@@ -695,8 +698,8 @@ func (b *Builder) buildAggregateFunction(
 	b.subquery = nil
 	defer func() { b.subquery = subq }()
 
-	for i, pexpr := range f.Exprs {
-		info.args[i] = b.buildAggArg(pexpr.(tree.TypedExpr), &info, tempScope, fromScope)
+	for i, pexpr := range argExprs {
+		info.args[i] = b.buildAggArg(pexpr, &info, tempScope, fromScope)
 	}
 
 	// If we have a filter, add it to tempScope after all the arguments. We'll
@@ -747,6 +750,35 @@ func (b *Builder) buildAggregateFunction(
 	return &info
 }
 
+// getTypedAggregateArgs returns the arguments to the aggregate function as a
+// []tree.TypedExpr. In the case of arguments with default values, it fills in
+// the values if they are missing, since the optimizer operators for aggregates
+// have a fixed number of arguments. See also getTypedWindowArgs.
+func getTypedAggregateArgs(name string, exprs tree.Exprs) []tree.TypedExpr {
+	argExprs := getTypedExprs(exprs)
+
+	switch name {
+	// The layer name of st_asmvt is "default" by default, and its extent is
+	// 4096. The geometry and feature ID column names are empty by default,
+	// which means that they are chosen automatically.
+	case "st_asmvt":
+		if len(argExprs) < 2 {
+			argExprs = append(argExprs, tree.NewDString("default"))
+		}
+		if len(argExprs) < 3 {
+			argExprs = append(argExprs, tree.NewDInt(geomvt.DefaultExtent))
+		}
+		if len(argExprs) < 4 {
+			argExprs = append(argExprs, tree.NewDString(""))
+		}
+		if len(argExprs) < 5 {
+			argExprs = append(argExprs, tree.NewDString(""))
+		}
+	}
+
+	return argExprs
+}
+
 func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.ScalarExpr {
 	switch name {
 	case "rank":
@@ -771,6 +803,10 @@ func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.Scal
 		return b.factory.ConstructLastValue(args[0])
 	case "nth_value":
 		return b.factory.ConstructNthValue(args[0], args[1])
+	case "st_clusterdbscan":
+		return b.factory.ConstructSTClusterDBSCAN(args[0], args[1], args[2])
+	case "st_clusterkmeans":
+		return b.factory.ConstructSTClusterKMeans(args[0], args[1])
 	default:
 		return b.constructAggregate(name, args)
 	}
@@ -828,6 +864,16 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructJsonObjectAgg(args[0], args[1])
 	case "jsonb_object_agg":
 		return b.factory.ConstructJsonbObjectAgg(args[0], args[1])
+	case "st_asmvt":
+		return b.factory.ConstructSTAsMVT(args[0], args[1], args[2], args[3], args[4])
+	case "st_extent":
+		return b.factory.ConstructSTExtent(args[0])
+	case "st_collect":
+		return b.factory.ConstructSTCollect(args[0])
+	case "st_makeline":
+		return b.factory.ConstructSTMakeLine(args[0])
+	case "st_union":
+		return b.factory.ConstructSTUnion(args[0])
 	}
 
 	panic(errors.AssertionFailedf("unhandled aggregate: %s", name))
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -208,6 +209,11 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 			// UPSERT and INDEX ON CONFLICT DO UPDATE may modify rows if the
 			// DO NOTHING clause is not present.
 			b.checkPrivilege(depName, tab, privilege.UPDATE)
+
+			if tab.TriggerCount() > 0 {
+				panic(unimplemented.NewWithIssuef(28296,
+					"UPSERT and INSERT ... ON CONFLICT DO UPDATE are not supported on tables with triggers"))
+			}
 		}
 	}
 
@@ -263,6 +269,10 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		rows := mb.replaceDefaultExprs(ins.Rows)
 
 		mb.buildInputForInsert(inScope, rows)
+
+		// Check the values targeting identity columns against the OVERRIDING
+		// clause, if any.
+		mb.checkIdentityColsForInsert(ins.Overriding, mb.extractValuesInput(ins.Rows))
 	} else {
 		mb.buildInputForInsert(inScope, nil /* rows */)
 	}
@@ -601,6 +611,72 @@ func (mb *mutationBuilder) buildInputForInsert(inScope *scope, inputRows *tree.S
 	}
 }
 
+// checkIdentityColsForInsert validates the input values targeting identity
+// columns. A GENERATED ALWAYS AS IDENTITY column cannot be targeted with
+// explicit values unless OVERRIDING SYSTEM VALUE is specified. DEFAULT
+// specifiers in a VALUES clause are allowed, since they are replaced by the
+// identity default. If OVERRIDING USER VALUE is specified, the input values for
+// all identity columns are ignored, and the identity default is used instead.
+func (mb *mutationBuilder) checkIdentityColsForInsert(
+	overriding tree.OverridingKind, values *tree.ValuesClause,
+) {
+	// isDefault returns true if the i-th input column is a VALUES column that
+	// only contains DEFAULT specifiers.
+	isDefault := func(i int) bool {
+		if values == nil {
+			return false
+		}
+		for _, tuple := range values.Rows {
+			if _, ok := tuple[i].(tree.DefaultVal); !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	var ignored opt.ColSet
+	for i, colID := range mb.targetColList {
+		ord := mb.tabID.ColumnOrdinal(colID)
+		tabCol := mb.tab.Column(ord)
+		if !tabCol.IsGeneratedAsIdentity() {
+			continue
+		}
+		switch overriding {
+		case tree.OverridingNone:
+			if tabCol.IsGeneratedAlwaysAsIdentity() && !isDefault(i) {
+				panic(errors.WithHint(
+					errors.WithDetailf(
+						pgerror.Newf(pgcode.GeneratedAlways,
+							"cannot insert into column %q", tabCol.ColName()),
+						"Column %q is an identity column defined as GENERATED ALWAYS.",
+						tabCol.ColName(),
+					),
+					"Use OVERRIDING SYSTEM VALUE to override.",
+				))
+			}
+
+		case tree.OverridingUserValue:
+			// Forget the input column, so that the identity default is synthesized
+			// in its place by addSynthesizedColsForInsert. Clear its name so that
+			// it cannot be confused with the synthesized column.
+			mb.insertOrds[ord] = -1
+			mb.outScope.cols[i].name = ""
+			ignored.Add(colID)
+		}
+	}
+
+	if !ignored.Empty() {
+		targetColList := make(opt.ColList, 0, len(mb.targetColList))
+		for _, colID := range mb.targetColList {
+			if !ignored.Contains(colID) {
+				targetColList = append(targetColList, colID)
+			}
+		}
+		mb.targetColList = targetColList
+		mb.targetColSet = mb.targetColSet.Difference(ignored)
+	}
+}
+
 // addSynthesizedColsForInsert wraps an Insert input expression with a Project
 // operator containing any default (or nullable) columns and any computed
 // columns that are not yet part of the target column list. This includes all
@@ -619,6 +695,9 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// synthesized or not).
 	mb.roundDecimalValues(mb.insertOrds, false /* roundComputedCols */)
 
+	// Fire the BEFORE INSERT triggers, which may change or skip the rows.
+	mb.buildBeforeTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedCols(
 		mb.insertOrds,
@@ -644,6 +723,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(mb.outScope.expr, mb.checks, private)
 
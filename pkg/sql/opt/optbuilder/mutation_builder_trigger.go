@@ -0,0 +1,419 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+)
+
+// This file contains methods that add the row-level triggers of the mutated
+// table to a mutation.
+//
+// -- BEFORE triggers --
+//
+// BEFORE triggers are evaluated as part of the mutation input. Each trigger
+// becomes a call to a function that runs the trigger body and returns the new
+// values of the visible columns of the row as a tuple, or NULL if the row must
+// be skipped:
+//
+//   project
+//    ├── columns: a_new b_new ...
+//    ├── select
+//    │    ├── project
+//    │    │    ├── <mutation input>
+//    │    │    └── projections
+//    │    │         └── trg(a, b, NULL, NULL) [as=trg]
+//    │    └── filters
+//    │         └── trg IS DISTINCT FROM NULL
+//    └── projections
+//         ├── (trg).@1 [as=a_new]
+//         └── (trg).@2 [as=b_new]
+//
+// The triggers are fired in order of their names; each trigger observes the
+// row returned by the previous one. They are built before the computed
+// columns, so that computed columns are evaluated on the values returned by
+// the triggers.
+//
+// -- AFTER triggers --
+//
+// AFTER triggers run after the statement modifies all the rows. They are
+// planned as cascades (see memo.FKCascade), which call the trigger function
+// once for every row in the buffered mutation input.
+
+// buildBeforeTriggers adds the BEFORE triggers of the table that are fired by
+// the given event to the mutation input.
+func (mb *mutationBuilder) buildBeforeTriggers(event tree.TriggerEvent) {
+	if !hasTriggers(mb.tab, tree.TriggerBefore, event) {
+		return
+	}
+
+	var newOrds []scopeOrdinal
+	switch event {
+	case tree.TriggerEventInsert:
+		newOrds = mb.insertOrds
+
+	case tree.TriggerEventUpdate:
+		// A trigger can change any column of the row, so all the visible columns
+		// are updated.
+		newOrds = mb.updateOrds
+		for i, n := 0, mb.tab.ColumnCount(); i < n; i++ {
+			col := mb.tab.Column(i)
+			if newOrds[i] == -1 && !col.IsHidden() && !col.IsComputed() {
+				newOrds[i] = mb.fetchOrds[i]
+				tabColID := mb.tabID.ColumnID(i)
+				mb.targetColList = append(mb.targetColList, tabColID)
+				mb.targetColSet.Add(tabColID)
+			}
+		}
+	}
+
+	visible := visibleColumnOrdinals(mb.tab)
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime != tree.TriggerBefore || !trigger.Events.Contains(event) {
+			continue
+		}
+
+		// Build the arguments of the trigger function: the NEW values followed
+		// by the OLD values of the public columns.
+		numCols := mb.tab.ColumnCount()
+		args := make(memo.ScalarListExpr, numCols*2)
+		for j := 0; j < numCols; j++ {
+			args[j] = mb.triggerArg(newOrds, j)
+			args[numCols+j] = mb.triggerArg(mb.fetchOrds, j)
+		}
+		private := makeTriggerFunctionPrivate(mb.tab, &trigger, event)
+		fn := mb.b.factory.ConstructFunction(args, private)
+
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		fnCol := mb.b.synthesizeColumn(projectionsScope, string(trigger.Name), private.Typ, nil, fn)
+		fnColID := fnCol.id
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+
+		// Skip the rows for which the trigger returned NULL.
+		mb.outScope.expr = mb.b.factory.ConstructSelect(
+			mb.outScope.expr,
+			memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(
+				mb.b.factory.ConstructIsNot(
+					mb.b.factory.ConstructVariable(fnColID), memo.NullSingleton,
+				),
+			)},
+		)
+
+		if event == tree.TriggerEventDelete {
+			continue
+		}
+
+		// Use the values returned by the trigger as the new values of the row.
+		projectionsScope = mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		for j, ord := range visible {
+			col := mb.tab.Column(ord)
+			if col.IsComputed() {
+				continue
+			}
+			access := mb.b.factory.ConstructColumnAccess(
+				mb.b.factory.ConstructVariable(fnColID), memo.TupleOrdinal(j),
+			)
+			mb.b.synthesizeColumn(projectionsScope, string(col.ColName()), col.DatumType(), nil, access)
+			newOrds[ord] = scopeOrdinal(len(projectionsScope.cols) - 1)
+		}
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+
+	if event != tree.TriggerEventDelete {
+		// Make sure that column names refer to the values returned by the
+		// triggers, and round them as the original values were.
+		mb.disambiguateColumns()
+		mb.roundDecimalValues(newOrds, false /* roundComputedCols */)
+	}
+}
+
+// triggerArg returns the expression that passes the value of the given table
+// column to a trigger function, or NULL if the column is not part of the row.
+func (mb *mutationBuilder) triggerArg(scopeOrds []scopeOrdinal, tabOrd int) opt.ScalarExpr {
+	if scopeOrds[tabOrd] == -1 {
+		return mb.b.factory.ConstructNull(mb.tab.Column(tabOrd).DatumType())
+	}
+	return mb.b.factory.ConstructVariable(mb.scopeOrdToColID(scopeOrds[tabOrd]))
+}
+
+// buildAfterTriggers adds a cascade for each AFTER trigger of the table that is
+// fired by the given event. It must be called after the FK checks and
+// cascades are built.
+func (mb *mutationBuilder) buildAfterTriggers(event tree.TriggerEvent) {
+	if !hasTriggers(mb.tab, tree.TriggerAfter, event) {
+		return
+	}
+
+	numCols := mb.tab.ColumnCount()
+	var oldValues, newValues opt.ColList
+	if event != tree.TriggerEventInsert {
+		oldValues = make(opt.ColList, numCols)
+		for i := range oldValues {
+			oldValues[i] = mb.scopeOrdToColID(mb.fetchOrds[i])
+		}
+	}
+	if event != tree.TriggerEventDelete {
+		newValues = make(opt.ColList, numCols)
+		for i := range newValues {
+			newValues[i] = mb.scopeOrdToColID(mb.mapToReturnScopeOrd(i))
+		}
+	}
+
+	if mb.withID == 0 {
+		mb.withID = mb.b.factory.Memo().NextWithID()
+	}
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime != tree.TriggerAfter || !trigger.Events.Contains(event) {
+			continue
+		}
+		mb.cascades = append(mb.cascades, memo.FKCascade{
+			FKName: string(trigger.Name),
+			Builder: &afterTriggerBuilder{
+				table:   mb.tab,
+				trigger: i,
+				event:   event,
+			},
+			WithID:    mb.withID,
+			OldValues: oldValues,
+			NewValues: newValues,
+		})
+	}
+}
+
+// afterTriggerBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers. It builds a query that calls the trigger function for every row
+// modified by the mutation:
+//
+//   project
+//    ├── columns: trg
+//    ├── with-scan &1
+//    │    ├── columns: a_old b_old a_new b_new
+//    │    └── mapping: ...
+//    └── projections
+//         └── trg(a_new, b_new, a_old, b_old) [as=trg]
+//
+type afterTriggerBuilder struct {
+	table cat.Table
+	// trigger is the ordinal of the trigger (can be passed to table.Trigger).
+	trigger int
+	event   tree.TriggerEvent
+}
+
+var _ memo.CascadeBuilder = &afterTriggerBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *afterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	f := factoryI.(*norm.Factory)
+	md := f.Metadata()
+
+	// Enact panic handling similar to Builder.Build().
+	defer func() {
+		if r := recover(); r != nil {
+			if ok, e := errorutil.ShouldCatch(r); ok {
+				err = e
+			} else {
+				panic(r)
+			}
+		}
+	}()
+
+	inCols := append(oldValues[:len(oldValues):len(oldValues)], newValues...)
+	outCols := make(opt.ColList, len(inCols))
+	for i := range outCols {
+		c := md.ColumnMeta(inCols[i])
+		outCols[i] = md.AddColumn(c.Alias, c.Type)
+	}
+	input := f.ConstructWithScan(&memo.WithScanPrivate{
+		With:         binding,
+		InCols:       inCols,
+		OutCols:      outCols,
+		BindingProps: bindingProps,
+		ID:           md.NextUniqueID(),
+	})
+
+	outOldValues, outNewValues := outCols[:len(oldValues)], outCols[len(oldValues):]
+	numCols := tb.table.ColumnCount()
+	args := make(memo.ScalarListExpr, numCols*2)
+	for i := 0; i < numCols; i++ {
+		typ := tb.table.Column(i).DatumType()
+		if len(outNewValues) > 0 {
+			args[i] = f.ConstructVariable(outNewValues[i])
+		} else {
+			args[i] = f.ConstructNull(typ)
+		}
+		if len(outOldValues) > 0 {
+			args[numCols+i] = f.ConstructVariable(outOldValues[i])
+		} else {
+			args[numCols+i] = f.ConstructNull(typ)
+		}
+	}
+
+	trigger := tb.table.Trigger(tb.trigger)
+	private := makeTriggerFunctionPrivate(tb.table, &trigger, tb.event)
+	fnCol := md.AddColumn(string(trigger.Name), private.Typ)
+	return f.ConstructProject(
+		input,
+		memo.ProjectionsExpr{f.ConstructProjectionsItem(f.ConstructFunction(args, private), fnCol)},
+		opt.ColSet{},
+	), nil
+}
+
+// hasTriggers returns true if the table has at least one trigger that is fired
+// at the given time by the given event.
+func hasTriggers(tab cat.Table, actionTime tree.TriggerActionTime, event tree.TriggerEvent) bool {
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		trigger := tab.Trigger(i)
+		if trigger.ActionTime == actionTime && trigger.Events.Contains(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleColumnOrdinals returns the ordinals of the visible public columns of
+// the table; these are the columns of the rows returned by trigger bodies.
+func visibleColumnOrdinals(tab cat.Table) []int {
+	var ords []int
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		if !tab.Column(i).IsHidden() {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// triggerDepthKey is the context key that holds the number of trigger
+// functions being executed by the current goroutine, including those executed
+// by nested statements.
+type triggerDepthKey struct{}
+
+// makeTriggerFunctionPrivate returns the FunctionPrivate for a call to the
+// function of the given trigger. The function takes the NEW and OLD values of
+// the public columns of the table, and returns the new values of the visible
+// columns of the row as a tuple, or NULL if the row must be skipped.
+func makeTriggerFunctionPrivate(
+	tab cat.Table, trigger *cat.Trigger, event tree.TriggerEvent,
+) *memo.FunctionPrivate {
+	numCols := tab.ColumnCount()
+	visible := visibleColumnOrdinals(tab)
+	contents := make([]*types.T, len(visible))
+	for i, ord := range visible {
+		contents[i] = tab.Column(ord).DatumType()
+	}
+	typ := types.MakeTuple(contents)
+
+	body := trigger.Body
+	returnsRows := trigger.ReturnsRows
+	name := trigger.Name
+	argTypes := make(tree.ArgTypes, numCols*2)
+	for i := 0; i < numCols; i++ {
+		colName := string(tab.Column(i).ColName())
+		colTyp := tab.Column(i).DatumType()
+		argTypes[i].Name, argTypes[i].Typ = "new_"+colName, colTyp
+		argTypes[numCols+i].Name, argTypes[numCols+i].Typ = "old_"+colName, colTyp
+	}
+
+	overload := &tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(typ),
+		Volatility: tree.VolatilityVolatile,
+		Body:       body,
+		Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			// Triggers can modify tables with triggers; limit the nesting depth to
+			// avoid infinite recursion.
+			ctx := evalCtx.Ctx()
+			depth, _ := ctx.Value(triggerDepthKey{}).(int)
+			if limit := evalCtx.SessionData.OptimizerFKCascadesLimit; depth >= limit {
+				return nil, pgerror.Newf(pgcode.TriggeredActionException,
+					"trigger depth limit (%d) reached while executing trigger %q", limit, name)
+			}
+			ctx = context.WithValue(ctx, triggerDepthKey{}, depth+1)
+
+			qargs := make([]interface{}, len(args))
+			for i := range args {
+				qargs[i] = args[i]
+			}
+			rows, err := evalCtx.InternalExecutor.Query(ctx, "trigger", evalCtx.Txn, body, qargs...)
+			if err != nil {
+				return nil, err
+			}
+
+			// Unless the body returns a row, the row is unchanged.
+			row := args[:numCols]
+			if event == tree.TriggerEventDelete {
+				row = args[numCols:]
+			}
+			vals := make(tree.Datums, len(visible))
+			if returnsRows {
+				if len(rows) == 0 {
+					return tree.DNull, nil
+				}
+				if len(rows[0]) != len(visible) {
+					return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+						"returned row structure does not match the structure of the triggering table")
+				}
+				for i := range vals {
+					if rows[0][i] == tree.DNull {
+						vals[i] = tree.DNull
+						continue
+					}
+					if vals[i], err = tree.PerformCast(evalCtx, rows[0][i], contents[i]); err != nil {
+						return nil, err
+					}
+				}
+			} else {
+				for i, ord := range visible {
+					vals[i] = row[ord]
+				}
+			}
+			return tree.NewDTuple(typ, vals...), nil
+		},
+	}
+	return &memo.FunctionPrivate{
+		Name: string(name),
+		Typ:  typ,
+		Properties: &tree.FunctionProperties{
+			// The values of the columns are passed to the body as they are.
+			NullableArgs: true,
+			Impure:       true,
+			// The internal executor is not available on remote nodes.
+			DistsqlBlocklist: true,
+			UserDefined:      true,
+		},
+		Overload: overload,
+	}
+}
@@ -476,6 +476,7 @@ func (b *Builder) buildFunction(
 	if err != nil {
 		panic(err)
 	}
+	def = def.ForNumArgs(len(f.Exprs))
 
 	if isAggregate(def) {
 		panic(errors.AssertionFailedf("aggregate function should have been replaced"))
@@ -485,6 +486,13 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if def.UserDefined {
+		b.checkUserDefinedFunction(def)
+		if out := b.tryInlineFunction(f, inScope, outScope, outCol, colRefs); out != nil {
+			return out
+		}
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -679,6 +687,8 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructJsonSomeExists(left, right)
 	case tree.Overlaps:
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp)))
 }
@@ -721,6 +731,8 @@ func (b *Builder) constructBinary(
 		return b.factory.ConstructFetchValPath(left, right)
 	case tree.JSONFetchTextPath:
 		return b.factory.ConstructFetchTextPath(left, right)
+	case tree.Distance:
+		return b.factory.ConstructDistance(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled binary operator: %s", log.Safe(bin)))
 }
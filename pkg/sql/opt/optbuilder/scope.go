@@ -901,11 +901,26 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := t.Func.ResolveWithResolver(
+			s.builder.ctx, s.builder.semaCtx.SearchPath, s.builder.semaCtx.GetFunctionResolver(),
+		)
 		if err != nil {
 			panic(err)
 		}
 
+		if def.UserDefined {
+			// User-defined functions are not cached in the AST, so copy the
+			// function expression with its definition so that type checking can
+			// find it without mutating the tree. Since the function may be
+			// altered or dropped later, the memo cannot be reused.
+			s.builder.DisableMemoReuse = true
+			copy := *t
+			copy.Func.FunctionReference = def
+			expr = &copy
+			break
+		}
+		def = def.ForNumArgs(len(t.Exprs))
+
 		if isGenerator(def) && s.replaceSRFs {
 			expr = s.replaceSRF(t, def)
 			break
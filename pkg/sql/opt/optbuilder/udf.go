@@ -0,0 +1,133 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// checkUserDefinedFunction panics if the given user-defined function cannot
+// be used in the current context.
+func (b *Builder) checkUserDefinedFunction(def *tree.FunctionDefinition) {
+	if b.insideViewDef {
+		// Views and functions do not track their dependencies on functions, so
+		// they cannot refer to user-defined functions.
+		panic(unimplemented.NewWithIssuef(17511,
+			"user-defined function %s cannot be used in a view or function definition", def.Name))
+	}
+}
+
+// tryInlineFunction attempts to replace a call to a user-defined function
+// with the expression that makes up its body. This is possible when:
+//
+//  - the function is immutable, and
+//  - its body is a single expression, without a FROM clause or a subquery,
+//    and
+//  - all the arguments are constants, placeholders or column references, so
+//    that substituting them in the body does not change the number of times
+//    they are evaluated.
+//
+// For example, given:
+//
+//   CREATE FUNCTION add(a INT, b INT) RETURNS INT IMMUTABLE LANGUAGE SQL
+//     AS 'SELECT a + b'
+//
+// the call add(x, 1) is built as (x::INT8 + 1::INT8)::INT8.
+//
+// It returns nil if the function cannot be inlined, in which case the body is
+// evaluated during execution.
+func (b *Builder) tryInlineFunction(
+	f *tree.FuncExpr, inScope, outScope *scope, outCol *scopeColumn, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	overload := f.ResolvedOverload()
+	if overload.Volatility > tree.VolatilityImmutable {
+		return nil
+	}
+	for _, arg := range f.Exprs {
+		switch arg.(type) {
+		case tree.Datum, *scopeColumn, *tree.Placeholder:
+		default:
+			return nil
+		}
+	}
+
+	body, ok := functionBodyExpr(overload.Body)
+	if !ok {
+		return nil
+	}
+
+	// Substitute the arguments, referred to either by name or by position.
+	args := overload.Types.(tree.ArgTypes)
+	argExpr := func(i int) tree.Expr {
+		return &tree.CastExpr{Expr: f.Exprs[i], Type: args[i].Typ, SyntaxMode: tree.CastShort}
+	}
+	inlinable := true
+	body, err := tree.SimpleVisit(body, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 && !t.Star {
+				for i := range args {
+					if args[i].Name == t.Parts[0] {
+						return false, argExpr(i), nil
+					}
+				}
+			}
+			inlinable = false
+		case *tree.Placeholder:
+			if int(t.Idx) < len(args) {
+				return false, argExpr(int(t.Idx)), nil
+			}
+			inlinable = false
+		case *tree.Subquery:
+			inlinable = false
+		case *tree.FuncExpr:
+			// Only scalar builtin functions can be inlined.
+			def, resolveErr := t.Func.Resolve(b.semaCtx.SearchPath)
+			if resolveErr != nil || def.Class != tree.NormalClass || t.WindowDef != nil {
+				inlinable = false
+			}
+		}
+		return inlinable, expr, nil
+	})
+	if err != nil || !inlinable {
+		return nil
+	}
+
+	retType := f.ResolvedType()
+	texpr := inScope.resolveType(
+		&tree.CastExpr{Expr: body, Type: retType, SyntaxMode: tree.CastShort}, retType,
+	)
+	out := b.buildScalar(texpr, inScope, nil, nil, colRefs)
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
+
+// functionBodyExpr returns the expression computed by a function body of the
+// form SELECT <expr>, or false if the body has any other form.
+func functionBodyExpr(body string) (tree.Expr, bool) {
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		return nil, false
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, false
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.Distinct || clause.DistinctOn != nil || len(clause.From.Tables) != 0 ||
+		clause.Where != nil || clause.GroupBy != nil || clause.Having != nil ||
+		clause.Window != nil || len(clause.Exprs) != 1 {
+		return nil, false
+	}
+	return clause.Exprs[0].Expr, true
+}
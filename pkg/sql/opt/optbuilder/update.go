@@ -156,6 +156,37 @@ func (mb *mutationBuilder) addTargetColsForUpdate(exprs tree.UpdateExprs) {
 					len(expr.Names), n))
 			}
 		}
+
+		mb.checkIdentityColsForUpdate(expr)
+	}
+}
+
+// checkIdentityColsForUpdate raises an error if the given SET expression, whose
+// target columns were just added to targetColList, assigns a value other than
+// DEFAULT to a GENERATED ALWAYS AS IDENTITY column.
+func (mb *mutationBuilder) checkIdentityColsForUpdate(expr *tree.UpdateExpr) {
+	targetIdx := len(mb.targetColList) - len(expr.Names)
+	for i := range expr.Names {
+		tabCol := mb.tab.Column(mb.tabID.ColumnOrdinal(mb.targetColList[targetIdx+i]))
+		if !tabCol.IsGeneratedAlwaysAsIdentity() {
+			continue
+		}
+		val := expr.Expr
+		if expr.Tuple {
+			// The values of a subquery cannot be DEFAULT.
+			val = nil
+			if t, ok := expr.Expr.(*tree.Tuple); ok {
+				val = t.Exprs[i]
+			}
+		}
+		if _, ok := val.(tree.DefaultVal); !ok {
+			panic(errors.WithDetailf(
+				pgerror.Newf(pgcode.GeneratedAlways,
+					"column %q can only be updated to DEFAULT", tabCol.ColName()),
+				"Column %q is an identity column defined as GENERATED ALWAYS.",
+				tabCol.ColName(),
+			))
+		}
 	}
 }
 
@@ -308,6 +339,9 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 	// the correct columns.
 	mb.disambiguateColumns()
 
+	// Fire the BEFORE UPDATE triggers, which may change or skip the rows.
+	mb.buildBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Add all computed columns in case their values have changed.
 	mb.addSynthesizedCols(
 		mb.updateOrds,
@@ -329,6 +363,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -536,6 +536,19 @@ func (b *Builder) resolveTableForMutation(
 			"%q does not resolve to a table", tree.ErrString(n)))
 	}
 
+	// Reject mutations on materialized views; their contents can only be
+	// changed by REFRESH MATERIALIZED VIEW.
+	if tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot mutate materialized view %q", tab.Name()))
+	}
+	// Reject mutations on foreign tables; their contents are read from external
+	// storage.
+	if tab.IsForeignTable() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot mutate foreign table %q", tab.Name()))
+	}
+
 	if outerAlias != nil {
 		alias = *outerAlias
 	}
@@ -321,7 +321,7 @@ func (b *Builder) buildAggregationAsWindow(
 // projecting the default argument to some window functions when we could just
 // not do that projection.
 func (b *Builder) getTypedWindowArgs(w *windowInfo) []tree.TypedExpr {
-	argExprs := getTypedExprs(w.Exprs)
+	argExprs := getTypedAggregateArgs(w.def.Name, w.Exprs)
 
 	switch w.def.Name {
 	// The second argument of {lead,lag} is 1 by default, and the third argument
@@ -0,0 +1,35 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ordering
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+)
+
+func nearestNeighborScanCanProvideOrdering(
+	expr memo.RelExpr, required *physical.OrderingChoice,
+) bool {
+	// NearestNeighborScan returns rows in increasing order of distance; rows
+	// with equal distances are returned in an arbitrary order.
+	var provided physical.OrderingChoice
+	provided.AppendCol(expr.(*memo.NearestNeighborScanExpr).DistanceCol, false /* descending */)
+	return provided.Implies(required)
+}
+
+func nearestNeighborScanBuildProvided(
+	expr memo.RelExpr, required *physical.OrderingChoice,
+) opt.Ordering {
+	scan := expr.(*memo.NearestNeighborScanExpr)
+	provided := opt.Ordering{opt.MakeOrderingColumn(scan.DistanceCol, false /* descending */)}
+	return trimProvided(provided, required, &scan.Relational().FuncDeps)
+}
@@ -100,6 +100,11 @@ func init() {
 		buildChildReqOrdering: noChildReqOrdering,
 		buildProvidedOrdering: scanBuildProvided,
 	}
+	funcMap[opt.NearestNeighborScanOp] = funcs{
+		canProvideOrdering:    nearestNeighborScanCanProvideOrdering,
+		buildChildReqOrdering: noChildReqOrdering,
+		buildProvidedOrdering: nearestNeighborScanBuildProvided,
+	}
 	funcMap[opt.SelectOp] = funcs{
 		canProvideOrdering:    selectCanProvideOrdering,
 		buildChildReqOrdering: selectBuildChildReqOrdering,
@@ -372,6 +372,7 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
@@ -405,6 +406,16 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 		col.ComputedExpr = &s
 	}
 
+	if def.IsGeneratedAsIdentity() {
+		// The test catalog does not create sequences, so identity columns draw
+		// their values from unique_rowid().
+		s := "unique_rowid()"
+		col.DefaultExpr = &s
+		col.Nullable = false
+		col.Identity = true
+		col.IdentityAlways = def.GeneratedIdentity.GeneratedAsIdentityType == tree.GeneratedAlways
+	}
+
 	tt.Columns = append(tt.Columns, col)
 }
 
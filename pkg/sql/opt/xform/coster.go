@@ -138,6 +138,11 @@ const (
 	// slower than some float functions, so this is a somewhat data-backed
 	// guess.
 	geoFnCost = cpuCostFactor * 10
+
+	// nearestNeighborCandidateMultiplier is the estimated number of candidate
+	// rows, per returned row, that a nearest neighbor scan fetches from the
+	// inverted index before it can prove that its result is exact.
+	nearestNeighborCandidateMultiplier = 4
 )
 
 // Init initializes a new coster structure with the given memo.
@@ -163,6 +168,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 	case opt.ScanOp:
 		cost = c.computeScanCost(candidate.(*memo.ScanExpr), required)
 
+	case opt.NearestNeighborScanOp:
+		cost = c.computeNearestNeighborScanCost(candidate.(*memo.NearestNeighborScanExpr))
+
 	case opt.SelectOp:
 		cost = c.computeSelectCost(candidate.(*memo.SelectExpr))
 
@@ -317,6 +325,16 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	return memo.Cost(rowCount)*(seqIOCostFactor+perRowCost) + preferConstrainedScanCost
 }
 
+func (c *coster) computeNearestNeighborScanCost(scan *memo.NearestNeighborScanExpr) memo.Cost {
+	// The scan fetches a multiple of the number of rows it returns from the
+	// inverted index, and looks up each candidate row in the primary index in
+	// order to compute its distance from the origin.
+	candidates := scan.Relational().Stats.RowCount * nearestNeighborCandidateMultiplier
+	perRowCost := c.rowScanCost(scan.Table, cat.PrimaryIndex, scan.Cols.Len())
+	perRowCost += randIOCostFactor + geoFnCost
+	return memo.Cost(candidates) * perRowCost
+}
+
 func (c *coster) computeSelectCost(sel *memo.SelectExpr) memo.Cost {
 	// The filter has to be evaluated on each input row.
 	inputRowCount := sel.Input.Relational().Stats.RowCount
@@ -15,6 +15,7 @@ import (
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
@@ -1465,6 +1466,146 @@ func (c *CustomFuncs) GenerateLimitedScans(
 	}
 }
 
+// GenerateNearestNeighborScans enumerates all non-partial geospatial inverted
+// indexes on the Scan operator's table and tries to construct new
+// NearestNeighborScan operators from them. It applies when the required
+// ordering of the Limit is a single ascending column that is projected as the
+// distance between the indexed column and a constant shape, as in:
+//
+//   SELECT * FROM t ORDER BY geom <-> 'POINT(1 1)' LIMIT 10
+//
+// The NearestNeighborScan returns the distance column along with the table
+// columns, in increasing order of distance, so the distance projection is
+// replaced by a passthrough column in a new Project that is added to the
+// Limit's group:
+//
+//   (Project
+//     (NearestNeighborScan t@geom_idx (distance=d origin='POINT(1 1)' k=10))
+//     ...
+//   )
+//
+// Rows with an empty geospatial value are not included in the inverted index,
+// but they are at an infinite distance from every shape. The indexed column
+// must be non-nullable, since a NULL distance would sort before all others.
+func (c *CustomFuncs) GenerateNearestNeighborScans(
+	grp memo.RelExpr,
+	scanPrivate *memo.ScanPrivate,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+	limit tree.Datum,
+	required physical.OrderingChoice,
+) {
+	// Row-level locking is not supported by nearest neighbor scans.
+	if scanPrivate.Locking != nil {
+		return
+	}
+
+	// The required ordering must be a single ascending column.
+	if len(required.Columns) != 1 || required.Columns[0].Descending {
+		return
+	}
+
+	// Find the projected distance that the ordering refers to.
+	distanceIdx := -1
+	for i := range projections {
+		if required.Columns[0].Group.Contains(projections[i].Col) {
+			distanceIdx = i
+			break
+		}
+	}
+	if distanceIdx == -1 {
+		return
+	}
+	geoCol, origin, ok := c.extractDistanceArgs(projections[distanceIdx].Element)
+	if !ok || !scanPrivate.Cols.Contains(geoCol) {
+		return
+	}
+
+	// The indexed column must be non-nullable.
+	md := c.e.mem.Metadata()
+	tab := md.Table(scanPrivate.Table)
+	if tab.Column(scanPrivate.Table.ColumnOrdinal(geoCol)).IsNullable() {
+		return
+	}
+
+	// All rows are at an infinite distance from an empty origin, so an index
+	// cannot help to find the closest ones.
+	var so geopb.SpatialObject
+	switch t := origin.(type) {
+	case *tree.DGeometry:
+		so = t.SpatialObject()
+	case *tree.DGeography:
+		so = t.SpatialObject()
+	default:
+		return
+	}
+	if so.BoundingBox == nil {
+		return
+	}
+
+	// The distance column is passed through from the NearestNeighborScan
+	// rather than projected.
+	newProjections := make(memo.ProjectionsExpr, 0, len(projections)-1)
+	newProjections = append(newProjections, projections[:distanceIdx]...)
+	newProjections = append(newProjections, projections[distanceIdx+1:]...)
+	newPassthrough := passthrough.Copy()
+	newPassthrough.Add(projections[distanceIdx].Col)
+
+	iter := makeScanIndexIter(c.e.mem, scanPrivate, rejectNonInvertedIndexes|rejectPartialIndexes)
+	for iter.Next() {
+		if scanPrivate.Table.ColumnID(iter.Index().Column(0).Ordinal) != geoCol {
+			continue
+		}
+		if scanPrivate.Flags.ForceIndex && scanPrivate.Flags.Index != iter.IndexOrdinal() {
+			continue
+		}
+		config := iter.Index().GeoConfig()
+		if !geoindex.IsGeographyConfig(config) && !geoindex.IsGeometryConfig(config) {
+			continue
+		}
+
+		nnScan := c.e.f.ConstructNearestNeighborScan(&memo.NearestNeighborScanPrivate{
+			Table:       scanPrivate.Table,
+			Index:       iter.IndexOrdinal(),
+			Cols:        scanPrivate.Cols,
+			GeoCol:      geoCol,
+			Origin:      origin,
+			DistanceCol: projections[distanceIdx].Col,
+			K:           int(*limit.(*tree.DInt)),
+		})
+		c.e.mem.AddProjectToGroup(&memo.ProjectExpr{
+			Input:       nnScan,
+			Projections: newProjections,
+			Passthrough: newPassthrough,
+		}, grp)
+	}
+}
+
+// extractDistanceArgs returns the variable column and the constant shape of a
+// <-> operator between a geospatial column and a constant, in either order. If
+// the expression does not have this form, ok is false.
+func (c *CustomFuncs) extractDistanceArgs(
+	e opt.ScalarExpr,
+) (col opt.ColumnID, origin tree.Datum, ok bool) {
+	dist, ok := e.(*memo.DistanceExpr)
+	if !ok {
+		return 0, nil, false
+	}
+	left, right := dist.Left, dist.Right
+	if _, isVar := right.(*memo.VariableExpr); isVar {
+		left, right = right, left
+	}
+	variable, ok := left.(*memo.VariableExpr)
+	if !ok {
+		return 0, nil, false
+	}
+	cnst, ok := right.(*memo.ConstExpr)
+	if !ok {
+		return 0, nil, false
+	}
+	return variable.Col, cnst.Value, true
+}
+
 // ScanIsConstrained returns true if the scan operator with the given
 // ScanPrivate is constrained.
 func (c *CustomFuncs) ScanIsConstrained(sp *memo.ScanPrivate) bool {
@@ -12,6 +12,7 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -304,8 +306,9 @@ func (oc *optCatalog) dataSourceForDesc(
 	desc *sqlbase.ImmutableTableDescriptor,
 	name *cat.DataSourceName,
 ) (cat.DataSource, error) {
-	if desc.IsTable() {
-		// Tables require invalidation logic for cached wrappers.
+	if desc.IsTable() || desc.MaterializedView() {
+		// Tables require invalidation logic for cached wrappers. Materialized
+		// views are stored like tables, so they are read like tables too.
 		return oc.dataSourceForTable(ctx, flags, desc, name)
 	}
 
@@ -543,6 +546,9 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers are the row-level triggers of the table, ordered by name.
+	triggers []cat.Trigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap map[sqlbase.ColumnID]int
@@ -608,6 +614,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 	for i := range ot.desc.InboundFKs {
@@ -622,6 +630,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 
@@ -680,6 +690,25 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// The triggers are stored ordered by name in the descriptor.
+	ot.triggers = make([]cat.Trigger, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger, err := makeOptTrigger(desc.TableDesc(), &desc.Triggers[i])
+		if err != nil {
+			// The body no longer matches the table, for example because a column
+			// it refers to was dropped. Don't prevent using the table; instead,
+			// report the error when the trigger is fired.
+			trigger = cat.Trigger{Name: tree.Name(desc.Triggers[i].Name)}
+			trigger.ActionTime, trigger.Events = optTriggerEvents(&desc.Triggers[i])
+			trigger.Body = fmt.Sprintf(
+				"SELECT crdb_internal.force_error(%s, %s)",
+				lex.EscapeSQLString(pgerror.GetPGCode(err).String()),
+				lex.EscapeSQLString(err.Error()),
+			)
+		}
+		ot.triggers[i] = trigger
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -780,6 +809,16 @@ func (ot *optTable) IsVirtualTable() bool {
 	return false
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
+// IsForeignTable is part of the cat.Table interface.
+func (ot *optTable) IsForeignTable() bool {
+	return ot.desc.IsForeignTable()
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.desc.Columns)
@@ -876,6 +915,16 @@ func (ot *optTable) InboundForeignKey(i int) cat.ForeignKeyConstraint {
 	return &ot.inboundFKs[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return ot.triggers[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID sqlbase.ColumnID) (int, error) {
@@ -1246,6 +1295,9 @@ type optForeignKeyConstraint struct {
 	match        sqlbase.ForeignKeyReference_Match
 	deleteAction sqlbase.ForeignKeyReference_Action
 	updateAction sqlbase.ForeignKeyReference_Action
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1317,6 +1369,18 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return sqlbase.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	switch {
+	case fk.initiallyDeferred:
+		return tree.DeferrableInitiallyDeferred
+	case fk.deferrable:
+		return tree.DeferrableInitiallyImmediate
+	default:
+		return tree.NotDeferrable
+	}
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc *sqlbase.ImmutableTableDescriptor
@@ -1472,6 +1536,16 @@ func (ot *optVirtualTable) IsVirtualTable() bool {
 	return true
 }
 
+// IsMaterializedView is part of the cat.Table interface.
+func (ot *optVirtualTable) IsMaterializedView() bool {
+	return false
+}
+
+// IsForeignTable is part of the cat.Table interface.
+func (ot *optVirtualTable) IsForeignTable() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optVirtualTable) ColumnCount() int {
 	// Virtual tables expose an extra (bogus) PK column.
@@ -1574,6 +1648,16 @@ func (ot *optVirtualTable) InboundForeignKey(i int) cat.ForeignKeyConstraint {
 	panic("no FKs")
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic("no triggers")
+}
+
 type optDummyVirtualPKColumn struct{}
 
 var _ cat.Column = optDummyVirtualPKColumn{}
@@ -1638,6 +1722,16 @@ func (optDummyVirtualPKColumn) ComputedExprStr() string {
 	return ""
 }
 
+// IsGeneratedAsIdentity is part of the cat.Column interface.
+func (optDummyVirtualPKColumn) IsGeneratedAsIdentity() bool {
+	return false
+}
+
+// IsGeneratedAlwaysAsIdentity is part of the cat.Column interface.
+func (optDummyVirtualPKColumn) IsGeneratedAlwaysAsIdentity() bool {
+	return false
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
@@ -24,6 +24,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -90,6 +92,9 @@ func (ef *execFactory) ConstructScan(
 			reqOrdering, rowCount, locking,
 		)
 	}
+	if table.IsForeignTable() {
+		return ef.constructForeignScan(table, needed, indexConstraint, hardLimit, rowCount, locking)
+	}
 
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -141,6 +146,44 @@ func (ef *execFactory) ConstructScan(
 	return scan, nil
 }
 
+func (ef *execFactory) constructForeignScan(
+	table cat.Table,
+	needed exec.TableColumnOrdinalSet,
+	indexConstraint *constraint.Constraint,
+	hardLimit int64,
+	rowCount float64,
+	locking *tree.LockingItem,
+) (exec.Node, error) {
+	tabDesc := table.(*optTable).desc
+	if locking != nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not allowed with foreign table %q", locking.Strength, tabDesc.Name)
+	}
+	// The rows of a foreign table have no primary key: the hidden column which
+	// would be its primary key is not stored in the files.
+	if indexConstraint != nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"use of %s column not allowed with foreign table %q",
+			tabDesc.PrimaryIndex.ColumnNames[0], tabDesc.Name)
+	}
+	n := &foreignScanNode{
+		desc:              tabDesc,
+		hardLimit:         hardLimit,
+		estimatedRowCount: uint64(rowCount),
+	}
+	for c, ok := needed.Next(0); ok; c, ok = needed.Next(c + 1) {
+		col := table.Column(c).(*sqlbase.ColumnDescriptor)
+		if col.Hidden {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"use of %s column not allowed with foreign table %q", col.Name, tabDesc.Name)
+		}
+		n.cols = append(n.cols, *col)
+	}
+	n.resultColumns = sqlbase.ResultColumnsFromColDescs(tabDesc.GetID(), n.cols)
+	n.filterVars = tree.MakeIndexedVarHelper(n, len(n.cols))
+	return n, nil
+}
+
 func (ef *execFactory) constructVirtualScan(
 	table cat.Table,
 	index cat.Index,
@@ -234,6 +277,11 @@ func (ef *execFactory) ConstructFilter(
 		s.reqOrdering = ReqOrdering(reqOrdering)
 		return s, nil
 	}
+	// Likewise for a foreignScanNode.
+	if s, ok := n.(*foreignScanNode); ok && s.filter == nil && s.hardLimit == 0 {
+		s.filter = s.filterVars.Rebind(filter)
+		return s, nil
+	}
 	// Create a filterNode.
 	src := asDataSource(n)
 	f := &filterNode{
@@ -759,6 +807,33 @@ func (ef *execFactory) ConstructGeoLookupJoin(
 	return nil, errors.Errorf("Geospatial joins are not yet supported")
 }
 
+// ConstructNearestNeighborScan is part of the exec.Factory interface.
+func (ef *execFactory) ConstructNearestNeighborScan(
+	table cat.Table,
+	index cat.Index,
+	needed exec.TableColumnOrdinalSet,
+	geoCol exec.TableColumnOrdinal,
+	origin tree.Datum,
+	k int64,
+	reqOrdering exec.OutputOrdering,
+) (exec.Node, error) {
+	tabDesc := table.(*optTable).desc
+	indexDesc := index.(*optIndex).desc
+
+	n := &nearestNeighborNode{
+		desc:        tabDesc,
+		index:       indexDesc,
+		neededCols:  needed.Copy(),
+		geoColIdx:   int(geoCol),
+		origin:      origin,
+		k:           int(k),
+		reqOrdering: ReqOrdering(reqOrdering),
+	}
+	n.columns = sqlbase.ResultColumnsFromColDescs(tabDesc.GetID(), makeColDescList(table, needed))
+	n.columns = append(n.columns, sqlbase.ResultColumn{Name: "distance", Typ: types.Float})
+	return n, nil
+}
+
 // Helper function to create a scanNode from just a table / index descriptor
 // and requested cols.
 func (ef *execFactory) constructScanForZigzag(
@@ -1007,7 +1082,7 @@ func (ef *execFactory) ConstructWindow(root exec.Node, wi exec.WindowInfo) (exec
 
 // ConstructPlan is part of the exec.Factory interface.
 func (ef *execFactory) ConstructPlan(
-	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Node,
+	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Check,
 ) (exec.Plan, error) {
 	// No need to spool at the root.
 	if spool, ok := root.(*spoolNode); ok {
@@ -1688,6 +1763,7 @@ func (ef *execFactory) ConstructCreateView(
 	ifNotExists bool,
 	replace bool,
 	temporary bool,
+	materialized bool,
 	viewQuery string,
 	columns sqlbase.ResultColumns,
 	deps opt.ViewDeps,
@@ -1717,14 +1793,39 @@ func (ef *execFactory) ConstructCreateView(
 	}
 
 	return &createViewNode{
-		viewName:    tree.Name(viewName),
-		ifNotExists: ifNotExists,
-		replace:     replace,
-		temporary:   temporary,
-		viewQuery:   viewQuery,
-		dbDesc:      schema.(*optSchema).desc,
-		columns:     columns,
-		planDeps:    planDeps,
+		viewName:     tree.Name(viewName),
+		ifNotExists:  ifNotExists,
+		replace:      replace,
+		temporary:    temporary,
+		materialized: materialized,
+		viewQuery:    viewQuery,
+		dbDesc:       schema.(*optSchema).desc,
+		columns:      columns,
+		planDeps:     planDeps,
+	}, nil
+}
+
+// ConstructCreateFunction is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, body string, deps opt.ViewDeps,
+) (exec.Node, error) {
+	planDeps := make(planDependencies, len(deps))
+	for _, d := range deps {
+		desc, err := getDescForDataSource(d.DataSource)
+		if err != nil {
+			return nil, err
+		}
+		entry := planDeps[desc.ID]
+		entry.desc = desc
+		planDeps[desc.ID] = entry
+	}
+
+	return &createFunctionNode{
+		n:        cf,
+		body:     body,
+		dbDesc:   schema.(*optSchema).desc,
+		scName:   schema.(*optSchema).name.Schema(),
+		planDeps: planDeps,
 	}, nil
 }
 
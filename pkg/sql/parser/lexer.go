@@ -95,13 +95,15 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			}
 		case NOT:
 			switch nextID {
-			case BETWEEN, IN, LIKE, ILIKE, SIMILAR:
+			case BETWEEN, IN, LIKE, ILIKE, SIMILAR, DEFERRABLE:
 				lval.id = NOT_LA
 			}
 		case GENERATED:
 			switch nextID {
 			case ALWAYS:
 				lval.id = GENERATED_ALWAYS
+			case BY:
+				lval.id = GENERATED_BY_DEFAULT
 			}
 
 		case WITH:
@@ -138,6 +138,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b GEOMETRY(POINT,4326))`},
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b TSVECTOR, c TSQUERY)`},
 		{`CREATE TABLE a (b "char")`},
 		{`CREATE TABLE a (b INT8 NULL)`},
 		{`CREATE TABLE a (b INT8 CONSTRAINT maybe NULL)`},
@@ -185,6 +186,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) ON DELETE CASCADE DEFERRABLE)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
@@ -199,6 +203,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo (bar) DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE CASCADE)`},
@@ -235,6 +241,10 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a.b (b INT8)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT8)`},
 		{`CREATE TABLE a (b INT8 AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT8 AS (a + b) VIRTUAL)`},
+		{`CREATE TABLE a (b INT8 GENERATED ALWAYS AS IDENTITY)`},
+		{`CREATE TABLE a (b INT8 GENERATED BY DEFAULT AS IDENTITY)`},
+		{`CREATE TABLE a (b INT8 GENERATED ALWAYS AS IDENTITY ( START 10 INCREMENT 2 ))`},
 		{`CREATE TABLE view (view INT8)`},
 
 		{`CREATE TABLE a (b INT8 CONSTRAINT c PRIMARY KEY)`},
@@ -310,6 +320,11 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW IF NOT EXISTS a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
+		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a`},
 		{`CREATE TEMPORARY VIEW a AS SELECT b`},
 
 		{`CREATE SEQUENCE a`},
@@ -363,6 +378,47 @@ func TestParse(t *testing.T) {
 		{`DROP TYPE IF EXISTS db.sc.a, sc.a CASCADE`},
 		{`DROP TYPE IF EXISTS db.sc.a, sc.a RESTRICT`},
 
+		{`CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+		{`CREATE FUNCTION f(INT8, STRING) RETURNS STRING LANGUAGE sql AS 'SELECT $2'`},
+		{`CREATE FUNCTION a.f(x INT8, y INT8) RETURNS INT8 LANGUAGE sql IMMUTABLE AS 'SELECT x + y'`},
+		{`CREATE FUNCTION db.sc.f(x INT8) RETURNS INT8 LANGUAGE sql STABLE AS 'SELECT max(a) FROM t WHERE b = x'`},
+		{`CREATE OR REPLACE FUNCTION f(x INT8) RETURNS INT8 LANGUAGE sql VOLATILE AS 'SELECT x'`},
+		{`EXPLAIN CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql AS 'SELECT 1'`},
+
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION f(INT8, STRING), db.sc.g`},
+		{`DROP FUNCTION IF EXISTS f(INT8) CASCADE`},
+		{`DROP FUNCTION IF EXISTS a.f, b.g() RESTRICT`},
+
+		{`CREATE FUNCTION f() RETURNS trigger LANGUAGE sql AS 'SELECT 1'`},
+		{`CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW EXECUTE FUNCTION f()`},
+		{`CREATE TRIGGER t AFTER INSERT OR UPDATE OR DELETE ON db.sc.a FOR EACH ROW EXECUTE FUNCTION sc.f()`},
+		{`EXPLAIN CREATE TRIGGER t BEFORE DELETE ON a FOR EACH ROW EXECUTE FUNCTION f()`},
+
+		{`DROP TRIGGER t ON a`},
+		{`DROP TRIGGER IF EXISTS t ON db.sc.a CASCADE`},
+		{`DROP TRIGGER t ON a RESTRICT`},
+
+		{`CREATE PUBLICATION p`},
+		{`CREATE PUBLICATION p FOR TABLE a, db.sc.b`},
+		{`CREATE PUBLICATION p FOR ALL TABLES`},
+		{`DROP PUBLICATION p`},
+		{`DROP PUBLICATION IF EXISTS p, q CASCADE`},
+
+		{`CREATE FOREIGN DATA WRAPPER w`},
+		{`CREATE FOREIGN DATA WRAPPER w OPTIONS (format 'parquet')`},
+		{`CREATE SERVER s FOREIGN DATA WRAPPER w OPTIONS (uri 'nodelocal://1/data', delimiter '|')`},
+		{`CREATE SERVER IF NOT EXISTS s FOREIGN DATA WRAPPER w`},
+		{`CREATE FOREIGN TABLE t (a INT8, b STRING NOT NULL) SERVER s`},
+		{`CREATE FOREIGN TABLE IF NOT EXISTS db.sc.t (a INT8) SERVER s OPTIONS (path 'logs', partition_columns 'day')`},
+		{`DROP FOREIGN DATA WRAPPER w`},
+		{`DROP FOREIGN DATA WRAPPER IF EXISTS w, v CASCADE`},
+		{`DROP SERVER s RESTRICT`},
+		{`DROP SERVER IF EXISTS s, r`},
+		{`DROP FOREIGN TABLE t`},
+		{`DROP FOREIGN TABLE IF EXISTS t, db.sc.u CASCADE`},
+
 		{`DELETE FROM a`},
 		{`EXPLAIN DELETE FROM a`},
 		{`DELETE FROM a.b`},
@@ -379,6 +435,14 @@ func TestParse(t *testing.T) {
 
 		{`DISCARD ALL`},
 
+		{`LISTEN foo`},
+		{`LISTEN "Foo"`},
+		{`NOTIFY foo`},
+		{`NOTIFY foo, 'bar'`},
+		{`NOTIFY foo, e'it\'s'`},
+		{`UNLISTEN foo`},
+		{`UNLISTEN *`},
+
 		{`DROP DATABASE a`},
 		{`EXPLAIN DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
@@ -413,6 +477,8 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
 		{`DROP SEQUENCE a`},
 		{`EXPLAIN DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
@@ -631,6 +697,8 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT EXECUTE ON FUNCTION f TO foo`},
+		{`GRANT EXECUTE, DROP ON FUNCTION f(INT8), db.sc.g() TO foo, bar`},
 		{`GRANT rolea, roleb TO usera, userb`},
 		{`GRANT rolea, roleb TO usera, userb WITH ADMIN OPTION`},
 
@@ -642,6 +710,7 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE EXECUTE ON FUNCTION f(INT8, STRING) FROM public`},
 		{`REVOKE rolea, roleb FROM usera, userb`},
 		{`REVOKE ADMIN OPTION FOR rolea, roleb FROM usera, userb`},
 
@@ -653,6 +722,8 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1, 2), (3, 4)`},
 		{`INSERT INTO a VALUES (a + 1, 2 * 3)`},
 		{`INSERT INTO a(a, b) VALUES (1, 2)`},
+		{`INSERT INTO a OVERRIDING SYSTEM VALUE VALUES (1, 2)`},
+		{`INSERT INTO a(a, b) OVERRIDING USER VALUE SELECT c, d FROM e`},
 		{`INSERT INTO a SELECT b, c FROM d`},
 		{`INSERT INTO a DEFAULT VALUES`},
 		{`INSERT INTO a VALUES (1) RETURNING a, b`},
@@ -733,6 +804,8 @@ func TestParse(t *testing.T) {
 		{`SELECT (a->'x')->'y'`},
 		{`SELECT (a->'x')->>'y'`},
 		{`SELECT b && c`},
+		{`SELECT b <-> c`},
+		{`SELECT b @@ c`},
 		{`SELECT |/a`},
 		{`SELECT ||/a`},
 
@@ -1109,6 +1182,10 @@ func TestParse(t *testing.T) {
 		{`SET a = off`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a, b DEFERRED`},
+		{`SET CONSTRAINTS a IMMEDIATE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
 		{`SET TRANSACTION PRIORITY LOW`},
 		{`SET TRANSACTION PRIORITY NORMAL`},
@@ -1253,6 +1330,8 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
 		{`ALTER TABLE a VALIDATE CONSTRAINT a`},
+		{`ALTER TABLE a SET (ttl_expire_after = '30 days', ttl_column = 'created')`},
+		{`ALTER TABLE a RESET (ttl_expire_after, ttl_column)`},
 		{`ALTER TABLE a ADD PRIMARY KEY (x, y, z)`},
 		{`ALTER TABLE a ADD PRIMARY KEY (x, y, z) USING HASH WITH BUCKET_COUNT = 10 INTERLEAVE IN PARENT b (x, y)`},
 		{`ALTER TABLE a ADD CONSTRAINT "primary" PRIMARY KEY (x, y, z)`},
@@ -1434,6 +1513,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN BACKUP TABLE foo TO 'bar'`},
 		{`BACKUP TABLE foo.foo, baz.baz TO 'bar'`},
 
+		{`ALTER BACKUP 'foo' ADD NEW_KMS = 'bar' WITH OLD_KMS = 'baz'`},
+		{`ALTER BACKUP $1 ADD NEW_KMS = $2 WITH OLD_KMS = $3`},
 		{`SHOW BACKUP 'bar'`},
 		{`SHOW BACKUP 'bar' WITH foo = 'bar'`},
 		{`EXPLAIN SHOW BACKUP 'bar'`},
@@ -1502,6 +1583,19 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
 
+		{`CREATE SCHEDULE FOR BACKUP INTO 'bar' RECURRING '@daily'`},
+		{`CREATE SCHEDULE 'foo' FOR BACKUP TABLE foo INTO 'bar' RECURRING '@hourly' FULL BACKUP '@daily'`},
+		{`CREATE SCHEDULE 'foo' FOR BACKUP DATABASE foo, bar INTO ('a', 'b') WITH revision_history RECURRING $1 FULL BACKUP ALWAYS`},
+		{`CREATE SCHEDULE FOR BACKUP INTO 'bar' RECURRING '@daily' WITH SCHEDULE OPTIONS on_execution_failure = 'pause', first_run = 'now'`},
+		{`PAUSE SCHEDULES SELECT a`},
+		{`RESUME SCHEDULES SELECT a`},
+		{`DROP SCHEDULES SELECT a`},
+		{`EXPLAIN DROP SCHEDULES SELECT a`},
+		{`SHOW SCHEDULES`},
+		{`SHOW RUNNING SCHEDULES`},
+		{`SHOW PAUSED SCHEDULES FOR BACKUP`},
+		{`SHOW SCHEDULE 123`},
+
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
 
@@ -1553,6 +1647,12 @@ func TestParse2(t *testing.T) {
 		{`CREATE TABLE a (UNIQUE INDEX (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`,
 			`CREATE TABLE a (UNIQUE (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE FUNCTION f(x INT, y STRING) RETURNS INT AS 'SELECT x' IMMUTABLE LANGUAGE SQL`,
+			`CREATE FUNCTION f(x INT8, y STRING) RETURNS INT8 LANGUAGE sql IMMUTABLE AS 'SELECT x'`},
+		{`CREATE FUNCTION f() RETURNS STRING LANGUAGE 'sql' AS $$SELECT 'a'$$`,
+			`CREATE FUNCTION f() RETURNS STRING LANGUAGE sql AS e'SELECT \'a\''`},
+		{`CREATE TRIGGER t BEFORE UPDATE ON a FOR ROW EXECUTE PROCEDURE f()`,
+			`CREATE TRIGGER t BEFORE UPDATE ON a FOR EACH ROW EXECUTE FUNCTION f()`},
 		{`CREATE INDEX ON a (b) INCLUDE (c)`, `CREATE INDEX ON a (b) STORING (c)`},
 
 		{`CREATE INDEX a ON b USING GIN (c)`,
@@ -1646,6 +1746,8 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT b <<= c`, `SELECT inet_contained_by_or_equals(b, c)`},
 		{`SELECT b >>= c`, `SELECT inet_contains_or_equals(b, c)`},
+		{`SELECT a FROM t ORDER BY b<->c LIMIT 10`, `SELECT a FROM t ORDER BY b <-> c LIMIT 10`},
+		{`SELECT a FROM t WHERE a<-1`, `SELECT a FROM t WHERE a < -1`},
 
 		{`SELECT NUMERIC 'foo'`, `SELECT DECIMAL 'foo'`},
 		{`SELECT REAL 'foo'`, `SELECT FLOAT4 'foo'`},
@@ -2007,6 +2109,11 @@ $function$`,
 		{`PAUSE JOB a`, `PAUSE JOBS VALUES (a)`},
 		{`EXPLAIN PAUSE JOB a`, `EXPLAIN PAUSE JOBS VALUES (a)`},
 		{`SHOW JOB a`, `SHOW JOBS VALUES (a)`},
+		{`PAUSE SCHEDULE a`, `PAUSE SCHEDULES VALUES (a)`},
+		{`RESUME SCHEDULE a`, `RESUME SCHEDULES VALUES (a)`},
+		{`DROP SCHEDULE a`, `DROP SCHEDULES VALUES (a)`},
+		{`CREATE SCHEDULE foo FOR BACKUP INTO 'bar' RECURRING '@daily' WITH SCHEDULE OPTIONS (first_run = 'now')`,
+			`CREATE SCHEDULE 'foo' FOR BACKUP INTO 'bar' RECURRING '@daily' WITH SCHEDULE OPTIONS first_run = 'now'`},
 		{`EXPLAIN SHOW JOB a`, `EXPLAIN SHOW JOBS VALUES (a)`},
 		{`SHOW JOB WHEN COMPLETE a`, `SHOW JOBS WHEN COMPLETE VALUES (a)`},
 		{`EXPLAIN SHOW JOB WHEN COMPLETE a`, `EXPLAIN SHOW JOBS WHEN COMPLETE VALUES (a)`},
@@ -2216,6 +2323,22 @@ $function$`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE NO ACTION ON DELETE NO ACTION)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`,
 		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x))`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) NOT DEFERRABLE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x))`,
+		},
 		{
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE RESTRICT ON DELETE RESTRICT)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON DELETE RESTRICT ON UPDATE RESTRICT)`,
@@ -2337,6 +2460,8 @@ $function$`,
 			`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other (x, y) ON DELETE CASCADE ON UPDATE SET NULL)`,
 		},
 		{`CREATE TABLE a (b INT8 GENERATED ALWAYS AS (a + b) STORED)`, `CREATE TABLE a (b INT8 AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT8 GENERATED ALWAYS AS (a + b) VIRTUAL)`, `CREATE TABLE a (b INT8 AS (a + b) VIRTUAL)`},
+		{`CREATE TABLE a (b INT8 GENERATED BY DEFAULT AS IDENTITY (START WITH 10))`, `CREATE TABLE a (b INT8 GENERATED BY DEFAULT AS IDENTITY ( START WITH 10 ))`},
 
 		{`ALTER TABLE a ALTER b DROP STORED`, `ALTER TABLE a ALTER COLUMN b DROP STORED`},
 		{`ALTER TABLE a ADD b INT8`, `ALTER TABLE a ADD COLUMN b INT8`},
@@ -3234,19 +3359,17 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE CONVERSION a`, 0, `create conversion`, ``},
 		{`CREATE DEFAULT CONVERSION a`, 0, `create def conv`, ``},
 		{`CREATE EXTENSION a`, 0, `create extension a`, ``},
-		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`, ``},
-		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`, ``},
-		{`CREATE FUNCTION a`, 17511, `create`, ``},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
-		{`CREATE MATERIALIZED VIEW a`, 41649, ``, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
-		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
+		{`CREATE PUBLICATION a WITH (publish = 'insert')`, 0, `create publication with`, ``},
+		{`CREATE PUBLICATION a FOR ALL TABLES WITH (publish = 'insert')`, 0, `create publication with`, ``},
 		{`CREATE RULE a`, 0, `create rule`, ``},
-		{`CREATE SERVER a`, 0, `create server`, ``},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`, ``},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`, ``},
-		{`CREATE TRIGGER a`, 28296, `create`, ``},
+		{`CREATE TRIGGER a BEFORE INSERT ON b FOR EACH ROW WHEN (true) EXECUTE FUNCTION f()`, 28296, `create trigger when`, ``},
+		{`CREATE TRIGGER a BEFORE UPDATE OF c ON b FOR EACH ROW EXECUTE FUNCTION f()`, 28296, `create trigger update of`, ``},
+		{`CREATE TRIGGER a AFTER TRUNCATE ON b FOR EACH STATEMENT EXECUTE FUNCTION f()`, 28296, `create trigger truncate`, ``},
+		{`CREATE TRIGGER a AFTER INSERT ON b FOR EACH STATEMENT EXECUTE FUNCTION f()`, 28296, `create trigger for each statement`, ``},
 
 		{`DROP AGGREGATE a`, 0, `drop aggregate`, ``},
 		{`DROP CAST a`, 0, `drop cast`, ``},
@@ -3254,25 +3377,18 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP CONVERSION a`, 0, `drop conversion`, ``},
 		{`DROP DOMAIN a`, 27796, `drop`, ``},
 		{`DROP EXTENSION a`, 0, `drop extension a`, ``},
-		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`, ``},
-		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`, ``},
-		{`DROP FUNCTION a`, 17511, `drop `, ``},
 		{`DROP LANGUAGE a`, 17511, `drop language a`, ``},
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
-		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
 		{`DROP RULE a`, 0, `drop rule`, ``},
 		{`DROP SCHEMA a`, 26443, `drop`, ``},
-		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
-		{`DROP TRIGGER a`, 28296, `drop`, ``},
 
 		{`DISCARD PLANS`, 0, `discard plans`, ``},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`, ``},
 		{`DISCARD TEMP`, 0, `discard temp`, ``},
 		{`DISCARD TEMPORARY`, 0, `discard temp`, ``},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`, ``},
 		{`SET LOCAL foo = bar`, 32562, ``, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`, ``},
 
@@ -3286,17 +3402,13 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`CREATE TABLE a AS SELECT b WITH NO DATA`, 0, `create table as with no data`, ``},
 
-		{`CREATE TABLE a(b INT8 AS (123) VIRTUAL)`, 0, `virtual computed columns`, ``},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`, ``},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`, ``},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable unique`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) INITIALLY DEFERRED)`, 31632, `deferrable unique`, ``},
+		{`ALTER TABLE a ADD CONSTRAINT c UNIQUE (b) DEFERRABLE INITIALLY IMMEDIATE`, 31632, `deferrable unique`, ``},
+		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable check`, ``},
 
 		{`CREATE TABLE a (LIKE b INCLUDING COMMENTS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING IDENTITY)`, 47071, `like table`, ``},
@@ -3380,8 +3492,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`, ``},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`, ``},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`, ``},
-		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`, ``},
-		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`, ``},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`, ``},
 		{`CREATE TABLE a(b XML)`, 0, `xml`, ``},
 
@@ -3535,6 +3645,60 @@ func TestParseOne(t *testing.T) {
 	}
 }
 
+func TestParseReplicationCommand(t *testing.T) {
+	testData := []struct {
+		sql      string
+		isRepl   bool
+		expected string
+		err      string
+	}{
+		{`SELECT 1`, false, ``, ``},
+		{`"identify_system"`, false, ``, ``},
+		{`IDENTIFY_SYSTEM`, true, `IDENTIFY_SYSTEM`, ``},
+		{`identify_system;`, true, `IDENTIFY_SYSTEM`, ``},
+		{`CREATE_REPLICATION_SLOT s LOGICAL pgoutput`, true,
+			`CREATE_REPLICATION_SLOT s LOGICAL pgoutput`, ``},
+		{`CREATE_REPLICATION_SLOT "My Slot" TEMPORARY LOGICAL "pgoutput" NOEXPORT_SNAPSHOT`, true,
+			`CREATE_REPLICATION_SLOT "My Slot" TEMPORARY LOGICAL pgoutput`, ``},
+		{`CREATE_REPLICATION_SLOT s PHYSICAL`, true, ``, `physical replication is not supported`},
+		{`CREATE_REPLICATION_SLOT s LOGICAL pgoutput EXPORT_SNAPSHOT`, true, ``, `EXPORT_SNAPSHOT is not supported`},
+		{`CREATE_REPLICATION_SLOT s`, true, ``, `syntax error at end of input`},
+		{`DROP_REPLICATION_SLOT s`, true, `DROP_REPLICATION_SLOT s`, ``},
+		{`DROP_REPLICATION_SLOT s WAIT`, true, `DROP_REPLICATION_SLOT s WAIT`, ``},
+		{`DROP_REPLICATION_SLOT s NOW`, true, ``, `syntax error at or near "NOW"`},
+		{`START_REPLICATION SLOT s LOGICAL 0/0`, true, `START_REPLICATION SLOT s LOGICAL 0/0`, ``},
+		{`START_REPLICATION SLOT s LOGICAL 16/b374d848 (proto_version '1', publication_names '"a",b', binary)`, true,
+			`START_REPLICATION SLOT s LOGICAL 16/B374D848 (proto_version '1', publication_names '"a",b', binary)`, ``},
+		{`START_REPLICATION SLOT s LOGICAL 0/0 (proto_version '1'`, true, ``, `syntax error at end of input`},
+		{`START_REPLICATION SLOT s LOGICAL 0-0`, true, ``, `invalid input syntax for type pg_lsn: "0-0"`},
+		{`START_REPLICATION SLOT s PHYSICAL 0/0`, true, ``, `physical replication is not supported`},
+		{`BASE_BACKUP`, true, ``, `BASE_BACKUP is not supported`},
+	}
+	for _, d := range testData {
+		t.Run(d.sql, func(t *testing.T) {
+			stmt, isRepl, err := parser.ParseReplicationCommand(d.sql)
+			if isRepl != d.isRepl {
+				t.Fatalf("expected replication command: %t, got %t", d.isRepl, isRepl)
+			}
+			if d.err != "" {
+				if !testutils.IsError(err, d.err) {
+					t.Fatalf("expected error %q, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isRepl {
+				return
+			}
+			if s := stmt.AST.String(); s != d.expected {
+				t.Fatalf("expected %s, got %s", d.expected, s)
+			}
+		})
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
 	testCases := []struct {
 		name, query string
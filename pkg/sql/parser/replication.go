@@ -0,0 +1,273 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// ParseReplicationCommand parses a command of the streaming replication
+// protocol, as sent by logical replication clients on connections opened
+// with replication=database. The replication commands have their own small
+// grammar in Postgres (repl_gram.y), so they are parsed by hand here rather
+// than in sql.y.
+//
+// The returned boolean is false if sql is not a replication command, in which
+// case it should be parsed as regular SQL.
+func ParseReplicationCommand(sql string) (Statement, bool, error) {
+	toks, err := scanReplicationCommand(sql)
+	if err != nil || len(toks) == 0 || toks[0].quoted {
+		return Statement{}, false, nil //nolint:returnerrcheck
+	}
+	p := replParser{toks: toks}
+	var stmt tree.Statement
+	switch cmd := p.keyword(); cmd {
+	case "identify_system":
+		stmt = &tree.IdentifySystem{}
+	case "create_replication_slot":
+		stmt, err = p.parseCreateReplicationSlot()
+	case "drop_replication_slot":
+		stmt, err = p.parseDropReplicationSlot()
+	case "start_replication":
+		stmt, err = p.parseStartReplication()
+	case "timeline_history", "base_backup", "read_replication_slot":
+		err = unimplemented.Newf("replication "+cmd, "%s is not supported", strings.ToUpper(cmd))
+	default:
+		return Statement{}, false, nil
+	}
+	if err == nil && !p.done() {
+		p.next()
+		err = p.syntaxError()
+	}
+	if err != nil {
+		return Statement{}, true, err
+	}
+	return Statement{AST: stmt, SQL: sql}, true, nil
+}
+
+func (p *replParser) parseCreateReplicationSlot() (tree.Statement, error) {
+	n := &tree.CreateReplicationSlot{}
+	name, ok := p.ident()
+	if !ok {
+		return nil, p.syntaxError()
+	}
+	n.Name = tree.Name(name)
+	if p.peekKeyword() == "temporary" {
+		p.next()
+		n.Temporary = true
+	}
+	switch p.keyword() {
+	case "logical":
+	case "physical":
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"physical replication is not supported")
+	default:
+		return nil, p.syntaxError()
+	}
+	if n.Plugin, ok = p.ident(); !ok {
+		return nil, p.syntaxError()
+	}
+	switch p.peekKeyword() {
+	case "noexport_snapshot":
+		p.next()
+	case "export_snapshot", "use_snapshot":
+		return nil, unimplemented.Newf("replication snapshot",
+			"%s is not supported", strings.ToUpper(p.keyword()))
+	}
+	return n, nil
+}
+
+func (p *replParser) parseDropReplicationSlot() (tree.Statement, error) {
+	name, ok := p.ident()
+	if !ok {
+		return nil, p.syntaxError()
+	}
+	n := &tree.DropReplicationSlot{Name: tree.Name(name)}
+	if p.peekKeyword() == "wait" {
+		p.next()
+		n.Wait = true
+	}
+	return n, nil
+}
+
+func (p *replParser) parseStartReplication() (tree.Statement, error) {
+	n := &tree.StartReplication{}
+	if p.keyword() != "slot" {
+		return nil, p.syntaxError()
+	}
+	name, ok := p.ident()
+	if !ok {
+		return nil, p.syntaxError()
+	}
+	n.Slot = tree.Name(name)
+	switch p.keyword() {
+	case "logical":
+	case "physical":
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"physical replication is not supported")
+	default:
+		return nil, p.syntaxError()
+	}
+	lsn := p.next()
+	if lsn.quoted || lsn.punct != 0 {
+		return nil, p.syntaxError()
+	}
+	var err error
+	if n.StartLSN, err = tree.ParseLSN(lsn.s); err != nil {
+		return nil, err
+	}
+	if p.peek().punct != '(' {
+		return n, nil
+	}
+	p.next()
+	for {
+		key, ok := p.ident()
+		if !ok {
+			return nil, p.syntaxError()
+		}
+		opt := tree.ReplicationOption{Key: tree.Name(key)}
+		if t := p.peek(); t.str {
+			p.next()
+			opt.Value = t.s
+		}
+		n.Options = append(n.Options, opt)
+		switch p.next().punct {
+		case ',':
+		case ')':
+			return n, nil
+		default:
+			return nil, p.syntaxError()
+		}
+	}
+}
+
+// replToken is a token of a replication command.
+type replToken struct {
+	s string
+	// punct is set for the punctuation tokens '(', ')' and ','.
+	punct byte
+	// quoted is set for double-quoted identifiers, str for string literals.
+	quoted, str bool
+}
+
+// scanReplicationCommand splits a replication command into tokens. A trailing
+// semicolon is ignored.
+func scanReplicationCommand(sql string) ([]replToken, error) {
+	var toks []replToken
+	s := strings.TrimRight(strings.TrimSpace(sql), ";")
+	for i := 0; i < len(s); {
+		switch ch := s[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(' || ch == ')' || ch == ',':
+			toks = append(toks, replToken{punct: ch})
+			i++
+		case ch == '"' || ch == '\'':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, pgerror.New(pgcode.Syntax, "unterminated quoted string")
+				}
+				if s[i] == ch {
+					if i+1 < len(s) && s[i+1] == ch {
+						i++
+					} else {
+						i++
+						break
+					}
+				}
+				b.WriteByte(s[i])
+			}
+			toks = append(toks, replToken{s: b.String(), quoted: ch == '"', str: ch == '\''})
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\r(),\"'", rune(s[j])) {
+				j++
+			}
+			toks = append(toks, replToken{s: s[i:j]})
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+// replParser is a recursive-descent parser over the tokens of a replication
+// command.
+type replParser struct {
+	toks []replToken
+	pos  int
+}
+
+func (p *replParser) done() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *replParser) peek() replToken {
+	if p.done() {
+		return replToken{}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *replParser) next() replToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// peekKeyword returns the next token as a lowercase keyword, or the empty
+// string if it is not a keyword.
+func (p *replParser) peekKeyword() string {
+	t := p.peek()
+	if t.quoted || t.str || t.punct != 0 {
+		return ""
+	}
+	return strings.ToLower(t.s)
+}
+
+func (p *replParser) keyword() string {
+	k := p.peekKeyword()
+	p.pos++
+	return k
+}
+
+// ident consumes an identifier. Unquoted identifiers are normalized like in
+// SQL.
+func (p *replParser) ident() (string, bool) {
+	t := p.next()
+	switch {
+	case t.quoted:
+		return t.s, true
+	case t.str || t.punct != 0 || t.s == "":
+		return "", false
+	}
+	return lex.NormalizeName(t.s), true
+}
+
+// syntaxError reports an unexpected token. It refers to the last consumed
+// token.
+func (p *replParser) syntaxError() error {
+	if p.pos > len(p.toks) {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of input")
+	}
+	t := p.toks[p.pos-1]
+	s := t.s
+	if t.punct != 0 {
+		s = string(t.punct)
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error at or near %q", s)
+}
@@ -258,6 +258,14 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = CONTAINED_BY
 			return
+		case '-': // <->
+			// Note that "<-" on its own is scanned as '<' followed by '-', so
+			// that "a<-1" continues to mean "a < -1".
+			if s.peekN(1) == '>' {
+				s.pos += 2
+				lval.id = DISTANCE
+				return
+			}
 		}
 		return
 
@@ -338,6 +346,10 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = CONTAINS
 			return
+		case '@': // @@
+			s.pos++
+			lval.id = AT_AT
+			return
 		}
 		return
 
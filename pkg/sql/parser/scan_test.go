@@ -39,6 +39,9 @@ func TestScanner(t *testing.T) {
 		{`<=`, []int{LESS_EQUALS}},
 		{`<<`, []int{LSHIFT}},
 		{`<<=`, []int{INET_CONTAINED_BY_OR_EQUALS}},
+		{`<->`, []int{DISTANCE}},
+		{`@@`, []int{AT_AT}},
+		{`<-`, []int{'<', '-'}},
 		{`>`, []int{'>'}},
 		{`>=`, []int{GREATER_EQUALS}},
 		{`>>`, []int{RSHIFT}},
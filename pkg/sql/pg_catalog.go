@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
@@ -123,13 +124,10 @@ var pgCatalog = virtualSchema{
 		"pg_prepared_statements",
 		"pg_prepared_xacts",
 		"pg_proc",
-		"pg_publication",
 		"pg_publication_rel",
-		"pg_publication_tables",
 		"pg_range",
 		"pg_replication_origin",
 		"pg_replication_origin_status",
-		"pg_replication_slots",
 		"pg_rewrite",
 		"pg_roles",
 		"pg_rules",
@@ -246,6 +244,9 @@ var pgCatalog = virtualSchema{
 		sqlbase.PgCatalogStatActivityTableID:        pgCatalogStatActivityTable,
 		sqlbase.PgCatalogSecurityLabelTableID:       pgCatalogSecurityLabelTable,
 		sqlbase.PgCatalogSharedSecurityLabelTableID: pgCatalogSharedSecurityLabelTable,
+		sqlbase.PgCatalogPublicationTableID:         pgCatalogPublicationTable,
+		sqlbase.PgCatalogPublicationTablesTableID:   pgCatalogPublicationTablesTable,
+		sqlbase.PgCatalogReplicationSlotsTableID:    pgCatalogReplicationSlotsTable,
 	},
 	// Postgres's catalogs are ill-defined when there is no current
 	// database set. Simply reject any attempts to use them in that
@@ -629,6 +630,8 @@ var (
 	relKindView     = tree.NewDString("v")
 	relKindSequence = tree.NewDString("S")
 
+	relKindMaterializedView = tree.NewDString("m")
+
 	relPersistencePermanent = tree.NewDString("p")
 )
 
@@ -673,7 +676,9 @@ CREATE TABLE pg_catalog.pg_class (
 		// The only difference between tables, views and sequences are the relkind and relam columns.
 		relKind := relKindTable
 		relAm := forwardIndexOid
-		if table.IsView() {
+		if table.MaterializedView() {
+			relKind = relKindMaterializedView
+		} else if table.IsView() {
 			relKind = relKindView
 			relAm = oidZero
 		} else if table.IsSequence() {
@@ -864,6 +869,8 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		condeferrable := tree.DBoolFalse
+		condeferred := tree.DBoolFalse
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -882,6 +889,8 @@ func populateTableConstraints(
 		case sqlbase.ConstraintTypeFK:
 			oid = h.ForeignKeyConstraintOid(db, scName, table.TableDesc(), con.FK)
 			contype = conTypeFK
+			condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+			condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 			// Foreign keys don't have a single linked index. Pick the first one
 			// that matches on the referenced table.
 			referencedTable, err := tableLookup.getTableByID(con.FK.ReferencedTableID)
@@ -946,8 +955,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1521,8 +1530,47 @@ CREATE TABLE pg_catalog.pg_extension (
 	},
 }
 
+// getForeignData returns the rows of system.foreign_data_wrappers or
+// system.foreign_servers for the given database, or all databases if it is
+// nil.
+func getForeignData(
+	ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, query string,
+) ([]tree.Datums, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionForeignDataWrappers) {
+		return nil, nil
+	}
+	dbID := tree.DNull
+	if dbContext != nil {
+		dbID = tree.NewDInt(tree.DInt(dbContext.GetID()))
+	}
+	return p.extendedEvalCtx.ExecCfg.InternalExecutor.QueryEx(
+		ctx,
+		"select-foreign-data",
+		p.EvalContext().Txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		query,
+		dbID,
+	)
+}
+
+func foreignDataWrapperOid(dbID sqlbase.ID, name string) *tree.DOid {
+	h := makeOidHasher()
+	h.writeTypeTag(foreignDataWrapperTypeTag)
+	h.writeUInt32(uint32(dbID))
+	h.writeStr(name)
+	return h.getOid()
+}
+
+func foreignServerOid(dbID sqlbase.ID, name string) *tree.DOid {
+	h := makeOidHasher()
+	h.writeTypeTag(foreignServerTypeTag)
+	h.writeUInt32(uint32(dbID))
+	h.writeStr(name)
+	return h.getOid()
+}
+
 var pgCatalogForeignDataWrapperTable = virtualSchemaTable{
-	comment: `foreign data wrappers (empty - feature does not exist)
+	comment: `foreign data wrappers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-data-wrapper.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_foreign_data_wrapper (
@@ -1534,14 +1582,36 @@ CREATE TABLE pg_catalog.pg_foreign_data_wrapper (
   fdwacl STRING[],
   fdwoptions STRING[]
 )`,
-	populate: func(_ context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign data wrappers are not supported.
+	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		wrappers, err := getForeignData(ctx, p, dbContext,
+			`SELECT database_id, name, owner, options FROM system.foreign_data_wrappers
+WHERE $1::INT8 IS NULL OR database_id = $1::INT8
+ORDER BY database_id, name`)
+		if err != nil {
+			return err
+		}
+		for _, fdw := range wrappers {
+			dbID := sqlbase.ID(tree.MustBeDInt(fdw[0]))
+			name := string(tree.MustBeDString(fdw[1]))
+			if err := addRow(
+				foreignDataWrapperOid(dbID, name),             // oid
+				tree.NewDName(name),                           // fdwname
+				h.UserOid(string(tree.MustBeDString(fdw[2]))), // fdwowner
+				oidZero,    // fdwhandler
+				oidZero,    // fdwvalidator
+				tree.DNull, // fdwacl
+				fdw[3],     // fdwoptions
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
 
 var pgCatalogForeignServerTable = virtualSchemaTable{
-	comment: `foreign servers (empty - feature does not exist)
+	comment: `foreign servers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-server.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_foreign_server (
@@ -1554,14 +1624,43 @@ CREATE TABLE pg_catalog.pg_foreign_server (
   srvacl STRING[],
   srvoptions STRING[]
 )`,
-	populate: func(_ context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign servers are not supported.
+	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		servers, err := getForeignData(ctx, p, dbContext,
+			`SELECT database_id, name, wrapper, owner, options FROM system.foreign_servers
+WHERE $1::INT8 IS NULL OR database_id = $1::INT8
+ORDER BY database_id, name`)
+		if err != nil {
+			return err
+		}
+		for _, srv := range servers {
+			dbID := sqlbase.ID(tree.MustBeDInt(srv[0]))
+			name := string(tree.MustBeDString(srv[1]))
+			wrapper := string(tree.MustBeDString(srv[2]))
+			// The uri of a server can hold credentials.
+			options, err := stringsToDatum(sanitizeForeignOptions(datumToStrings(srv[4])))
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				foreignServerOid(dbID, name),                  // oid
+				tree.NewDName(name),                           // srvname
+				h.UserOid(string(tree.MustBeDString(srv[3]))), // srvowner
+				foreignDataWrapperOid(dbID, wrapper),          // srvfdw
+				tree.DNull,                                    // srvtype
+				tree.DNull,                                    // srvversion
+				tree.DNull,                                    // srvacl
+				options,                                       // srvoptions
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
 
 var pgCatalogForeignTableTable = virtualSchemaTable{
-	comment: `foreign tables (empty  - feature does not exist)
+	comment: `foreign tables
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-table.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_foreign_table (
@@ -1569,9 +1668,22 @@ CREATE TABLE pg_catalog.pg_foreign_table (
   ftserver OID,
   ftoptions STRING[]
 )`,
-	populate: func(_ context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign tables are not supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual,
+			func(db *sqlbase.ImmutableDatabaseDescriptor, _ string, table *sqlbase.ImmutableTableDescriptor) error {
+				if !table.IsForeignTable() {
+					return nil
+				}
+				options, err := stringsToDatum(table.ForeignTable.Options)
+				if err != nil {
+					return err
+				}
+				return addRow(
+					tableOid(table.ID), // ftrelid
+					foreignServerOid(table.ParentID, table.ForeignTable.Server), // ftserver
+					options, // ftoptions
+				)
+			})
 	},
 }
 
@@ -1846,7 +1958,7 @@ CREATE TABLE pg_catalog.pg_locks (
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
-	comment: `available materialized views (empty - feature does not exist)
+	comment: `available materialized views
 https://www.postgresql.org/docs/9.6/view-pg-matviews.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_matviews (
@@ -1859,7 +1971,21 @@ CREATE TABLE pg_catalog.pg_matviews (
   definition TEXT
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual,
+			func(db *sqlbase.ImmutableDatabaseDescriptor, scName string, desc *sqlbase.ImmutableTableDescriptor) error {
+				if !desc.MaterializedView() {
+					return nil
+				}
+				return addRow(
+					tree.NewDName(scName),                 // schemaname
+					tree.NewDName(desc.Name),              // matviewname
+					tree.DNull,                            // matviewowner
+					tree.DNull,                            // tablespace
+					tree.MakeDBool(len(desc.Indexes) > 0), // hasindexes
+					tree.MakeDBool(!desc.Adding()),        // ispopulated
+					tree.NewDString(desc.ViewQuery),       // definition
+				)
+			})
 	},
 }
 
@@ -2896,6 +3022,203 @@ CREATE TABLE pg_catalog.pg_shseclabel (
 	},
 }
 
+// getPublications returns the publications of the given database, or of all
+// the databases if dbContext is nil. A publication is represented as a datum
+// row, containing database id, name, owner, whether it covers all the tables
+// of the database, and the array of the ids of its tables.
+func getPublications(
+	ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor,
+) ([]tree.Datums, error) {
+	dbID := tree.DNull
+	if dbContext != nil {
+		dbID = tree.NewDInt(tree.DInt(dbContext.GetID()))
+	}
+	return p.extendedEvalCtx.ExecCfg.InternalExecutor.QueryEx(
+		ctx,
+		"select-publications",
+		p.EvalContext().Txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT database_id, name, owner, all_tables, table_ids FROM system.publications
+WHERE $1::INT8 IS NULL OR database_id = $1::INT8
+ORDER BY database_id, name`,
+		dbID,
+	)
+}
+
+func publicationOid(dbID sqlbase.ID, name string) *tree.DOid {
+	h := makeOidHasher()
+	h.writeTypeTag(publicationTypeTag)
+	h.writeUInt32(uint32(dbID))
+	h.writeStr(name)
+	return h.getOid()
+}
+
+var pgCatalogPublicationTable = virtualSchemaTable{
+	comment: `publications
+https://www.postgresql.org/docs/10/catalog-pg-publication.html`,
+	schema: `
+CREATE TABLE pg_catalog.pg_publication (
+	oid OID,
+	pubname NAME,
+	pubowner OID,
+	puballtables BOOL,
+	pubinsert BOOL,
+	pubupdate BOOL,
+	pubdelete BOOL,
+	pubtruncate BOOL
+)`,
+	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		pubs, err := getPublications(ctx, p, dbContext)
+		if err != nil {
+			return err
+		}
+		for _, pub := range pubs {
+			dbID := sqlbase.ID(tree.MustBeDInt(pub[0]))
+			name := string(tree.MustBeDString(pub[1]))
+			if err := addRow(
+				publicationOid(dbID, name),                    // oid
+				tree.NewDName(name),                           // pubname
+				h.UserOid(string(tree.MustBeDString(pub[2]))), // pubowner
+				pub[3],          // puballtables
+				tree.DBoolTrue,  // pubinsert
+				tree.DBoolTrue,  // pubupdate
+				tree.DBoolTrue,  // pubdelete
+				tree.DBoolFalse, // pubtruncate
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var pgCatalogPublicationTablesTable = virtualSchemaTable{
+	comment: `tables published by publications
+https://www.postgresql.org/docs/10/view-pg-publication-tables.html`,
+	schema: `
+CREATE TABLE pg_catalog.pg_publication_tables (
+	pubname NAME,
+	schemaname NAME,
+	tablename NAME
+)`,
+	populate: func(ctx context.Context, p *planner, dbContext *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		pubs, err := getPublications(ctx, p, dbContext)
+		if err != nil || len(pubs) == 0 {
+			return err
+		}
+		type publishedTable struct {
+			scName string
+			table  *sqlbase.ImmutableTableDescriptor
+		}
+		var tables []publishedTable
+		byID := make(map[sqlbase.ID]publishedTable)
+		if err := forEachTableDesc(ctx, p, dbContext, hideVirtual,
+			func(_ *sqlbase.ImmutableDatabaseDescriptor, scName string, table *sqlbase.ImmutableTableDescriptor) error {
+				if !table.IsTable() || table.Temporary {
+					return nil
+				}
+				t := publishedTable{scName: scName, table: table}
+				tables = append(tables, t)
+				byID[table.ID] = t
+				return nil
+			}); err != nil {
+			return err
+		}
+		for _, pub := range pubs {
+			dbID := sqlbase.ID(tree.MustBeDInt(pub[0]))
+			pubName := tree.NewDName(string(tree.MustBeDString(pub[1])))
+			addTable := func(t publishedTable) error {
+				return addRow(
+					pubName,                     // pubname
+					tree.NewDName(t.scName),     // schemaname
+					tree.NewDName(t.table.Name), // tablename
+				)
+			}
+			if tree.MustBeDBool(pub[3]) {
+				for _, t := range tables {
+					if t.table.ParentID != dbID {
+						continue
+					}
+					if err := addTable(t); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			for _, d := range tree.MustBeDArray(pub[4]).Array {
+				// Tables dropped since the publication was created are skipped.
+				if t, ok := byID[sqlbase.ID(tree.MustBeDInt(d))]; ok {
+					if err := addTable(t); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	},
+}
+
+var pgCatalogReplicationSlotsTable = virtualSchemaTable{
+	comment: `replication slots
+https://www.postgresql.org/docs/10/view-pg-replication-slots.html`,
+	schema: `
+CREATE TABLE pg_catalog.pg_replication_slots (
+	slot_name NAME,
+	plugin NAME,
+	slot_type TEXT,
+	datoid OID,
+	database NAME,
+	temporary BOOL,
+	active BOOL,
+	active_pid INT4,
+	xmin INT8,
+	catalog_xmin INT8,
+	restart_lsn TEXT,
+	confirmed_flush_lsn TEXT
+)`,
+	populate: func(ctx context.Context, p *planner, _ *sqlbase.ImmutableDatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		// Like in Postgres, replication slots are visible from every database.
+		rows, err := p.extendedEvalCtx.ExecCfg.InternalExecutor.QueryEx(
+			ctx,
+			"select-replication-slots",
+			p.EvalContext().Txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`SELECT s.slot_name, s.plugin, s.database_id, n.name, s.confirmed_flush_lsn
+FROM system.replication_slots AS s
+LEFT JOIN system.namespace AS n ON n."parentID" = 0 AND n.id = s.database_id
+ORDER BY s.slot_name`,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			dbName := tree.DNull
+			if row[3] != tree.DNull {
+				dbName = tree.NewDName(string(tree.MustBeDString(row[3])))
+			}
+			lsn := tree.NewDString(tree.LSN(tree.MustBeDInt(row[4])).String())
+			if err := addRow(
+				tree.NewDName(string(tree.MustBeDString(row[0]))), // slot_name
+				tree.NewDName(string(tree.MustBeDString(row[1]))), // plugin
+				tree.NewDString("logical"),                        // slot_type
+				tree.NewDOid(tree.MustBeDInt(row[2])),             // datoid
+				dbName,                                            // database
+				tree.DBoolFalse,                                   // temporary
+				tree.DBoolFalse,                                   // active
+				tree.DNull,                                        // active_pid
+				tree.DNull,                                        // xmin
+				tree.DNull,                                        // catalog_xmin
+				lsn,                                               // restart_lsn
+				lsn,                                               // confirmed_flush_lsn
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // typOid is the only OID generation approach that does not use oidHasher, because
 // object identifiers for types are not arbitrary, but instead need to be kept in
 // sync with Postgres.
@@ -2956,6 +3279,8 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.TimestampTZFamily: typCategoryDateTime,
 	types.ArrayFamily:       typCategoryArray,
 	types.TupleFamily:       typCategoryPseudo,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
@@ -2985,7 +3310,7 @@ CREATE TABLE pg_catalog.pg_views (
 		// because it does not distinguish views in separate databases.
 		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /*virtual schemas do not have views*/
 			func(db *sqlbase.ImmutableDatabaseDescriptor, scName string, desc *sqlbase.ImmutableTableDescriptor) error {
-				if !desc.IsView() {
+				if !desc.IsView() || desc.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -3183,6 +3508,9 @@ const (
 	collationTypeTag
 	operatorTypeTag
 	enumEntryTypeTag
+	publicationTypeTag
+	foreignDataWrapperTypeTag
+	foreignServerTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
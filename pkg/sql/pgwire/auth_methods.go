@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -62,6 +63,13 @@ func loadDefaultMethods() {
 	// the current rule.
 	RegisterAuthMethod("trust", authTrust, clusterversion.VersionAuthLocalAndTrustRejectMethods, hba.ConnAny, nil)
 
+	// The "jwt" method requires a JSON Web Token, passed as a clear text
+	// password, signed by one of the issuers configured in the
+	// server.jwt_authentication cluster settings.
+	//
+	// As with "password", this method should only be used over secure
+	// connections.
+	RegisterAuthMethod("jwt", authJWT, clusterversion.VersionJWTAuthentication, hba.ConnAny, nil)
 }
 
 // AuthMethod defines a method for authentication of a connection.
@@ -174,6 +182,45 @@ func authCertPassword(
 	return fn(ctx, c, tlsState, pwRetrieveFn, pwValidUntilFn, execCfg, entry)
 }
 
+func authJWT(
+	ctx context.Context,
+	c AuthConn,
+	_ tls.ConnectionState,
+	_ PasswordRetrievalFn,
+	_ PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	_ *hba.Entry,
+) (security.UserAuthHook, error) {
+	if err := c.SendAuthRequest(authCleartextPassword, nil /* data */); err != nil {
+		return nil, err
+	}
+	pwdData, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	token, err := passwordString(pwdData)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := jwtauth.ConfigFromSettings(&execCfg.Settings.SV)
+	if err != nil {
+		return nil, err
+	}
+	tokenUser, err := cfg.Validate(token)
+	if err != nil {
+		c.Logf(ctx, "JWT validation failed: %v", err)
+		return nil, errors.New("JWT authentication failed")
+	}
+	tokenUser = tree.Name(tokenUser).Normalize()
+	return func(requestedUser string, _ bool) (func(), error) {
+		if requestedUser != tokenUser {
+			c.Logf(ctx, "JWT is for user %q", tokenUser)
+			return nil, errors.Errorf("JWT authentication failed for user %q", requestedUser)
+		}
+		return nil, nil
+	}, nil
+}
+
 func authTrust(
 	_ context.Context,
 	_ AuthConn,
@@ -28,6 +28,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/jwtauth/jwtauthtest"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -39,6 +40,7 @@ import (
 	"github.com/cockroachdb/datadriven"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/errors/stdstrings"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/lib/pq"
 )
 
@@ -429,3 +431,61 @@ func fmtErr(err error) string {
 	}
 	return "ok"
 }
+
+func TestJWTAuthentication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	idp := jwtauthtest.NewProvider(t, "key1")
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER carl`)
+	sqlDB.Exec(t, `CREATE USER dave`)
+	sqlDB.Exec(t, `CREATE USER nologin NOLOGIN`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.issuers = $1`, jwtauthtest.Issuer)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.audience = 'cockroach'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.jwks = $1`,
+		jwtauthtest.KeySets(t, idp))
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = 'host all all all jwt'`)
+
+	connect := func(user, token string) error {
+		pgURL, cleanupFn := sqlutils.PGUrlWithOptionalClientCerts(
+			t, s.ServingSQLAddr(), t.Name(), url.UserPassword(user, token), false /* withClientCerts */)
+		defer cleanupFn()
+		conn, err := gosql.Open("postgres", pgURL.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		var current string
+		if err := conn.QueryRow(`SELECT current_user`).Scan(&current); err != nil {
+			return err
+		}
+		if current != user {
+			t.Fatalf("expected to be connected as %s, got %s", user, current)
+		}
+		return nil
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		return connect("carl", idp.Sign(t, jwt.MapClaims{"sub": "Carl", "aud": "cockroach"}))
+	})
+	for _, tc := range []struct {
+		user  string
+		token string
+		err   string
+	}{
+		{"carl", "not a token", "JWT authentication failed"},
+		{"carl", idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "other"}), "JWT authentication failed"},
+		{"dave", idp.Sign(t, jwt.MapClaims{"sub": "carl", "aud": "cockroach"}),
+			`JWT authentication failed for user "dave"`},
+		{"nologin", idp.Sign(t, jwt.MapClaims{"sub": "nologin", "aud": "cockroach"}),
+			"nologin does not have login privilege"},
+	} {
+		if err := connect(tc.user, tc.token); !testutils.IsError(err, tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.user, tc.err, err)
+		}
+	}
+}
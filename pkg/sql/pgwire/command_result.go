@@ -213,6 +213,14 @@ func (r *commandResult) AppendNotice(noticeErr error) {
 	)
 }
 
+// BufferNotification is part of the NotificationResult interface.
+func (r *commandResult) BufferNotification(n sql.Notification) {
+	r.flushBeforeCloseFuncs = append(
+		r.flushBeforeCloseFuncs,
+		func(ctx context.Context) error { return r.conn.bufferNotification(n) },
+	)
+}
+
 // SetColumns is part of the CommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
 	r.assertNotReleased()
@@ -89,6 +89,20 @@ type conn struct {
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
 
+	// replication groups the state of the logical replication stream of
+	// connections opened in replication mode. See pgoutput.go.
+	replication struct {
+		// feedback and done are set by the reader goroutine while a stream
+		// started with START_REPLICATION is active. The status updates of the
+		// client are forwarded to feedback until done is closed by the
+		// processor goroutine.
+		feedback chan pgwirebase.StandbyStatusUpdate
+		done     chan struct{}
+		// conv is used by the processor goroutine to encode the replicated
+		// rows.
+		conv sessiondata.DataConversionConfig
+	}
+
 	sv *settings.Values
 
 	// testingLogEnabled is used in unit tests in this package to
@@ -328,6 +342,16 @@ Loop:
 		timeReceived := timeutil.Now()
 		log.VEventf(ctx, 2, "pgwire: processing %s", typ)
 
+		if c.replication.feedback != nil {
+			var handled bool
+			if handled, err = c.handleReplicationMsg(ctx, typ); err != nil {
+				break Loop
+			}
+			if handled {
+				continue
+			}
+		}
+
 		if !authDone {
 			if typ == pgwirebase.ClientMsgPassword {
 				var pwd []byte
@@ -616,6 +640,14 @@ func (c *conn) bufferNotice(ctx context.Context, noticeErr error) error {
 	return writeErrFields(ctx, c.sv, noticeErr, &c.msgBuilder, &c.writerState.buf)
 }
 
+func (c *conn) bufferNotification(n sql.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(n.NodeID)
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context, sqlServer *sql.Server,
 ) (sql.ConnectionHandler, error) {
@@ -681,6 +713,12 @@ func (c *conn) handleSimpleQuery(
 
 	tracing.AnnotateTrace()
 
+	if c.sessionArgs.Replication {
+		if handled, err := c.handleReplicationCommand(ctx, query, timeReceived); handled {
+			return err
+		}
+	}
+
 	startParse := timeutil.Now()
 	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
 	if err != nil {
@@ -1416,6 +1454,11 @@ func (c *conn) CreateDrainResult(pos sql.CmdPos) sql.DrainResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateNotificationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateNotificationResult(pos sql.CmdPos) sql.NotificationResult {
+	return c.newMiscResult(pos, flush)
+}
+
 // CreateBindResult is part of the sql.ClientComm interface.
 func (c *conn) CreateBindResult(pos sql.CmdPos) sql.BindResult {
 	return c.newMiscResult(pos, bindComplete)
@@ -1453,6 +1496,14 @@ func (c *conn) CreateCopyInResult(pos sql.CmdPos) sql.CopyInResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateStartReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateStartReplicationResult(pos sql.CmdPos) sql.StartReplicationResult {
+	res := c.newMiscResult(pos, commandComplete)
+	res.cmdCompleteTag = "START_REPLICATION"
+	res.stmtType = tree.Ack
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
@@ -0,0 +1,317 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// This file implements the pgwirebase.ReplicationConn interface, which encodes
+// a logical replication stream like the pgoutput plugin of Postgres (protocol
+// version 1). See:
+// https://www.postgresql.org/docs/current/protocol-replication.html
+// https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+//
+// The messages are written directly to the network connection by the
+// processor goroutine, like the messages of the Copy-in subprotocol, while
+// the reader goroutine forwards the status updates of the client.
+
+// Types of the messages sent inside CopyData messages during replication.
+const (
+	replMsgXLogData  byte = 'w'
+	replMsgKeepalive byte = 'k'
+
+	// Types of the messages sent by the client inside CopyData messages.
+	replMsgStandbyStatusUpdate byte = 'r'
+)
+
+// Types of the pgoutput messages, wrapped in XLogData messages.
+const (
+	pgoutputBegin    byte = 'B'
+	pgoutputCommit   byte = 'C'
+	pgoutputRelation byte = 'R'
+	pgoutputInsert   byte = 'I'
+	pgoutputUpdate   byte = 'U'
+	pgoutputDelete   byte = 'D'
+
+	pgoutputNewTuple byte = 'N'
+	pgoutputOldTuple byte = 'O'
+
+	pgoutputNullValue byte = 'n'
+	pgoutputTextValue byte = 't'
+
+	// pgoutputReplicaIdentityFull is reported for all tables since the old
+	// tuples of updates and deletes contain all the columns.
+	pgoutputReplicaIdentityFull byte = 'f'
+)
+
+var _ pgwirebase.ReplicationConn = &conn{}
+
+// pgTime returns the number of microseconds between t and Jan 1, 2000, the
+// representation of times in replication messages.
+func pgTime(t time.Time) int64 {
+	return duration.DiffMicros(t, pgwirebase.PGEpochJDate)
+}
+
+// BeginReplication is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) BeginReplication(ctx context.Context, conv sessiondata.DataConversionConfig) error {
+	c.replication.conv = conv
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatText))
+	c.msgBuilder.putInt16(0)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// EndReplication is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) EndReplication(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// initXLogData begins a XLogData message wrapping a pgoutput message of the
+// given type.
+func (c *conn) initXLogData(lsn tree.LSN, typ byte) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.writeByte(replMsgXLogData)
+	c.msgBuilder.putInt64(int64(lsn))
+	c.msgBuilder.putInt64(int64(lsn))
+	c.msgBuilder.putInt64(pgTime(timeutil.Now()))
+	c.msgBuilder.writeByte(typ)
+}
+
+// SendBegin is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendBegin(
+	ctx context.Context, finalLSN tree.LSN, commitTime time.Time, xid uint32,
+) error {
+	c.initXLogData(finalLSN, pgoutputBegin)
+	c.msgBuilder.putInt64(int64(finalLSN))
+	c.msgBuilder.putInt64(pgTime(commitTime))
+	c.msgBuilder.putInt32(int32(xid))
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCommit is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendCommit(ctx context.Context, commitLSN tree.LSN, commitTime time.Time) error {
+	c.initXLogData(commitLSN, pgoutputCommit)
+	c.msgBuilder.writeByte(0 /* flags */)
+	c.msgBuilder.putInt64(int64(commitLSN))
+	// The end LSN of the transaction is the same as its commit LSN: LSNs are
+	// the commit timestamps of the transactions.
+	c.msgBuilder.putInt64(int64(commitLSN))
+	c.msgBuilder.putInt64(pgTime(commitTime))
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendRelation is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendRelation(
+	ctx context.Context, lsn tree.LSN, rel *pgwirebase.ReplicationRelation,
+) error {
+	c.initXLogData(lsn, pgoutputRelation)
+	c.msgBuilder.putInt32(int32(rel.ID))
+	c.msgBuilder.writeTerminatedString(rel.Namespace)
+	c.msgBuilder.writeTerminatedString(rel.Name)
+	c.msgBuilder.writeByte(pgoutputReplicaIdentityFull)
+	c.msgBuilder.putInt16(int16(len(rel.Columns)))
+	for i := range rel.Columns {
+		col := &rel.Columns[i]
+		var flags byte
+		if col.Key {
+			flags = 1
+		}
+		c.msgBuilder.writeByte(flags)
+		c.msgBuilder.writeTerminatedString(col.Name)
+		c.msgBuilder.putInt32(int32(col.Type.Oid()))
+		c.msgBuilder.putInt32(col.Type.TypeModifier())
+	}
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendInsert is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendInsert(
+	ctx context.Context, lsn tree.LSN, relID uint32, row tree.Datums,
+) error {
+	c.initXLogData(lsn, pgoutputInsert)
+	c.msgBuilder.putInt32(int32(relID))
+	c.writeTuple(ctx, pgoutputNewTuple, row)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendUpdate is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendUpdate(
+	ctx context.Context, lsn tree.LSN, relID uint32, oldRow, row tree.Datums,
+) error {
+	c.initXLogData(lsn, pgoutputUpdate)
+	c.msgBuilder.putInt32(int32(relID))
+	if oldRow != nil {
+		c.writeTuple(ctx, pgoutputOldTuple, oldRow)
+	}
+	c.writeTuple(ctx, pgoutputNewTuple, row)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendDelete is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendDelete(
+	ctx context.Context, lsn tree.LSN, relID uint32, oldRow tree.Datums,
+) error {
+	c.initXLogData(lsn, pgoutputDelete)
+	c.msgBuilder.putInt32(int32(relID))
+	c.writeTuple(ctx, pgoutputOldTuple, oldRow)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// writeTuple writes the TupleData of a pgoutput message. All the values are
+// encoded in the text format.
+func (c *conn) writeTuple(ctx context.Context, typ byte, row tree.Datums) {
+	c.msgBuilder.writeByte(typ)
+	c.msgBuilder.putInt16(int16(len(row)))
+	for _, d := range row {
+		if d == tree.DNull {
+			c.msgBuilder.writeByte(pgoutputNullValue)
+			continue
+		}
+		c.msgBuilder.writeByte(pgoutputTextValue)
+		c.msgBuilder.writeTextDatum(ctx, d, c.replication.conv, d.ResolvedType())
+	}
+}
+
+// SendKeepalive is part of the pgwirebase.ReplicationConn interface.
+func (c *conn) SendKeepalive(ctx context.Context, walEnd tree.LSN, replyRequested bool) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.writeByte(replMsgKeepalive)
+	c.msgBuilder.putInt64(int64(walEnd))
+	c.msgBuilder.putInt64(pgTime(timeutil.Now()))
+	var reply byte
+	if replyRequested {
+		reply = 1
+	}
+	c.msgBuilder.writeByte(reply)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// handleReplicationCommand handles a simple query on a connection opened in
+// replication mode. The returned boolean is false if the query is not a
+// replication command, in which case it is to be handled as regular SQL.
+//
+// START_REPLICATION is pushed as a sql.StartReplication command, and the
+// connection starts forwarding the status updates of the client to the
+// stream; see handleReplicationMsg.
+func (c *conn) handleReplicationCommand(
+	ctx context.Context, query string, timeReceived time.Time,
+) (bool, error) {
+	startParse := timeutil.Now()
+	stmt, ok, err := parser.ParseReplicationCommand(query)
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return true, c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+	}
+	endParse := timeutil.Now()
+
+	if sr, ok := stmt.AST.(*tree.StartReplication); ok {
+		feedback := make(chan pgwirebase.StandbyStatusUpdate, 16)
+		done := make(chan struct{})
+		c.replication.feedback, c.replication.done = feedback, done
+		return true, c.stmtBuf.Push(ctx, sql.StartReplication{
+			Stmt:     sr,
+			Conn:     c,
+			Feedback: feedback,
+			Done:     done,
+		})
+	}
+	return true, c.stmtBuf.Push(ctx, sql.ExecStmt{
+		Statement:    stmt,
+		TimeReceived: timeReceived,
+		ParseStart:   startParse,
+		ParseEnd:     endParse,
+	})
+}
+
+// handleReplicationMsg handles a message received while a replication stream
+// is active. The returned boolean is false if the message is to be handled
+// normally, which is the case once the stream has ended.
+func (c *conn) handleReplicationMsg(
+	ctx context.Context, typ pgwirebase.ClientMessageType,
+) (bool, error) {
+	select {
+	case <-c.replication.done:
+		// The stream ended on the server side, e.g. because of an error.
+		c.replication.feedback, c.replication.done = nil, nil
+		return false, nil
+	default:
+	}
+
+	switch typ {
+	case pgwirebase.ClientMsgCopyData:
+		upd, ok, err := readStandbyStatusUpdate(&c.readBuf)
+		if err != nil || !ok {
+			return true, err
+		}
+		select {
+		case c.replication.feedback <- upd:
+		case <-c.replication.done:
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+		return true, nil
+	case pgwirebase.ClientMsgCopyDone:
+		// The client ends the stream.
+		close(c.replication.feedback)
+		c.replication.feedback, c.replication.done = nil, nil
+		return true, nil
+	default:
+		// Any other message ends the stream too, and is handled normally.
+		close(c.replication.feedback)
+		c.replication.feedback, c.replication.done = nil, nil
+		return false, nil
+	}
+}
+
+// readStandbyStatusUpdate decodes the payload of a CopyData message sent by a
+// replication client. The returned boolean is false for messages other than
+// standby status updates, which are ignored.
+func readStandbyStatusUpdate(
+	buf *pgwirebase.ReadBuffer,
+) (pgwirebase.StandbyStatusUpdate, bool, error) {
+	var upd pgwirebase.StandbyStatusUpdate
+	typ, err := buf.GetBytes(1)
+	if err != nil {
+		return upd, false, err
+	}
+	if typ[0] != replMsgStandbyStatusUpdate {
+		return upd, false, nil
+	}
+	var lsns [4]uint64
+	for i := range lsns {
+		if lsns[i], err = buf.GetUint64(); err != nil {
+			return upd, false, err
+		}
+	}
+	reply, err := buf.GetBytes(1)
+	if err != nil {
+		return upd, false, err
+	}
+	upd.WrittenLSN = tree.LSN(lsns[0])
+	upd.FlushedLSN = tree.LSN(lsns[1])
+	upd.AppliedLSN = tree.LSN(lsns[2])
+	upd.ClientTime = pgwirebase.PGEpochJDate.Add(time.Duration(int64(lsns[3])) * time.Microsecond)
+	upd.ReplyRequested = reply[0] != 0
+	return upd, true, nil
+}
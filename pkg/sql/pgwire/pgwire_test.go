@@ -1935,3 +1935,87 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	pgxConfig, err := pgx.ParseConnectionString(pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := pgx.Connect(pgxConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	expectNotification := func(channel, payload string) {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		defer cancel()
+		n, err := conn.WaitForNotification(waitCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Channel != channel || n.Payload != payload {
+			t.Fatalf("expected notification %s: %q, got %s: %q", channel, payload, n.Channel, n.Payload)
+		}
+		if n.PID != uint32(s.NodeID()) {
+			t.Fatalf("expected notification from node %d, got %d", s.NodeID(), n.PID)
+		}
+	}
+
+	if err := conn.Listen("foo"); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	// Notifications are only delivered when the transaction that sent them
+	// commits.
+	sqlDB.Exec(t, `BEGIN; NOTIFY foo, 'rolled back'; ROLLBACK`)
+	sqlDB.Exec(t, `NOTIFY bar, 'other channel'`)
+	sqlDB.Exec(t, `BEGIN; NOTIFY foo, 'a'; NOTIFY foo; COMMIT`)
+	expectNotification("foo", "a")
+	expectNotification("foo", "")
+
+	// A LISTEN in a transaction that is rolled back has no effect.
+	if _, err := conn.Exec(`BEGIN; LISTEN bar; ROLLBACK`); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Exec(t, `NOTIFY bar, 'not listening'`)
+	sqlDB.Exec(t, `NOTIFY foo, 'b'`)
+	expectNotification("foo", "b")
+
+	if err := conn.Unlisten("foo"); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Exec(t, `NOTIFY foo, 'not listening'`)
+	waitCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	if n, err := conn.WaitForNotification(waitCtx); err == nil {
+		t.Fatalf("expected no notification, got %v", n)
+	}
+
+	sqlDB.ExpectErr(t, "payload string too long",
+		fmt.Sprintf(`NOTIFY foo, '%s'`, strings.Repeat("a", 8001)))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`SET TRANSACTION READ ONLY`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`NOTIFY foo`); !testutils.IsError(err, "cannot execute NOTIFY in a read-only transaction") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+}
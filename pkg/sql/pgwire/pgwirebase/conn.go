@@ -12,8 +12,12 @@ package pgwirebase
 
 import (
 	"context"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // Conn exposes some functionality of a pgwire network connection to be
@@ -38,3 +42,77 @@ type Conn interface {
 	// payload.
 	SendCommandComplete(tag []byte) error
 }
+
+// ReplicationConn exposes the functionality of a pgwire network connection
+// used by the logical replication stream started with START_REPLICATION. The
+// changes are sent to the client in the format of the pgoutput plugin of
+// Postgres, wrapped in the CopyBoth subprotocol.
+// See: https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+type ReplicationConn interface {
+	// BeginReplication sends the message initiating the CopyBoth subprotocol.
+	// The values of the replicated rows are encoded in the text format, using
+	// the given conversion settings.
+	BeginReplication(ctx context.Context, conv sessiondata.DataConversionConfig) error
+
+	// SendBegin sends the beginning of a transaction. finalLSN is the LSN of
+	// the commit of the transaction.
+	SendBegin(ctx context.Context, finalLSN tree.LSN, commitTime time.Time, xid uint32) error
+
+	// SendRelation describes a table to the client. It must be sent before the
+	// first change to the table, and again when the table's schema changes.
+	SendRelation(ctx context.Context, lsn tree.LSN, rel *ReplicationRelation) error
+
+	// SendInsert sends a row inserted in the table with the given ID.
+	SendInsert(ctx context.Context, lsn tree.LSN, relID uint32, row tree.Datums) error
+
+	// SendUpdate sends a row updated in the table with the given ID. oldRow is
+	// the previous value of the row; it is omitted if nil.
+	SendUpdate(ctx context.Context, lsn tree.LSN, relID uint32, oldRow, row tree.Datums) error
+
+	// SendDelete sends a row deleted from the table with the given ID. oldRow
+	// is the previous value of the row.
+	SendDelete(ctx context.Context, lsn tree.LSN, relID uint32, oldRow tree.Datums) error
+
+	// SendCommit sends the end of the transaction started by the last call to
+	// SendBegin.
+	SendCommit(ctx context.Context, commitLSN tree.LSN, commitTime time.Time) error
+
+	// SendKeepalive sends a keepalive message reporting the current end of
+	// the stream. If replyRequested is set, the client is asked to reply with
+	// a status update immediately.
+	SendKeepalive(ctx context.Context, walEnd tree.LSN, replyRequested bool) error
+
+	// EndReplication sends the message ending the CopyBoth subprotocol.
+	EndReplication(ctx context.Context) error
+}
+
+// ReplicationRelation describes a replicated table in a Relation message.
+type ReplicationRelation struct {
+	ID        uint32
+	Namespace string
+	Name      string
+	Columns   []ReplicationColumn
+}
+
+// ReplicationColumn describes a column of a ReplicationRelation.
+type ReplicationColumn struct {
+	Name string
+	Type *types.T
+	// Key is set if the column is part of the replica identity of the table,
+	// i.e. its primary key.
+	Key bool
+}
+
+// StandbyStatusUpdate is the status update message sent by replication
+// clients to report their progress.
+type StandbyStatusUpdate struct {
+	// WrittenLSN, FlushedLSN and AppliedLSN are the positions of the stream
+	// written to disk, flushed to disk and applied by the client. The flushed
+	// position is used to advance the replication slot.
+	WrittenLSN tree.LSN
+	FlushedLSN tree.LSN
+	AppliedLSN tree.LSN
+	ClientTime time.Time
+	// ReplyRequested is set if the client asks for a keepalive immediately.
+	ReplyRequested bool
+}
@@ -640,6 +640,23 @@ func parseClientProvidedSessionParameters(
 			}
 			foundBufferSize = true
 
+		case "replication":
+			// Connections opened with replication=database accept the commands
+			// of the streaming replication protocol, which are used to start
+			// a logical replication stream. Physical replication, requested
+			// with a boolean value, is not supported.
+			switch strings.ToLower(value) {
+			case "database":
+				args.Replication = true
+			case "false", "off", "no", "0":
+			case "true", "on", "yes", "1":
+				return sql.SessionArgs{}, pgerror.New(pgcode.FeatureNotSupported,
+					"physical replication is not supported")
+			default:
+				return sql.SessionArgs{}, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for parameter \"replication\": %q", value)
+			}
+
 		default:
 			exists, configurable := sql.IsSessionVariableConfigurable(key)
 
@@ -181,6 +181,12 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -501,6 +507,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSQuery:
+		enc := v.TSQuery.Encode(nil)
+		b.putInt32(int32(len(enc)))
+		b.write(enc)
+	case *tree.DTSVector:
+		enc := v.TSVector.Encode(nil)
+		b.putInt32(int32(len(enc)))
+		b.write(enc)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
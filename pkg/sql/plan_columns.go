@@ -55,6 +55,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.columns
 	case *scanNode:
 		return n.resultColumns
+	case *foreignScanNode:
+		return n.resultColumns
 	case *unionNode:
 		return n.columns
 	case *valuesNode:
@@ -87,6 +89,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.columns
 	case *lookupJoinNode:
 		return n.columns
+	case *nearestNeighborNode:
+		return n.columns
 	case *zigzagJoinNode:
 		return n.columns
 	case *vTableLookupJoinNode:
@@ -82,6 +82,8 @@ func planReqOrdering(plan planNode) ReqOrdering {
 		return n.ordering
 	case *lookupJoinNode:
 		return n.reqOrdering
+	case *nearestNeighborNode:
+		return n.reqOrdering
 	case *zigzagJoinNode:
 		return n.reqOrdering
 	}
@@ -72,6 +72,16 @@ type extendedEvalContext struct {
 
 	Jobs *jobsCollection
 
+	// Notifications is the LISTEN state of the session. It is nil if the
+	// session does not support notifications, e.g. for internal executors.
+	Notifications *sessionNotifications
+
+	// DeferredConstraints is the state of the deferrable constraints of the
+	// transaction. It is nil if the checking of constraints cannot be deferred
+	// until the transaction commits, e.g. for internal executors running in a
+	// transaction they do not own.
+	DeferredConstraints *deferredConstraints
+
 	schemaAccessors *schemaInterface
 
 	sqlStatsCollector *sqlStatsCollector
@@ -285,6 +295,7 @@ func newInternalPlanner(
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
 	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
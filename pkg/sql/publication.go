@@ -0,0 +1,321 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// Publications are stored as rows of system.publications, keyed by the ID of
+// the database they belong to and their name. A publication either lists the
+// IDs of its tables or covers all the tables of its database; in the latter
+// case, the tables are only resolved when a replication stream starts.
+
+// checkLogicalReplicationSupported returns an error if the cluster can't use
+// publications and replication slots yet.
+func checkLogicalReplicationSupported(ctx context.Context, execCfg *ExecutorConfig) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionLogicalReplication) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for logical replication")
+	}
+	return nil
+}
+
+// publicationDatabase returns the descriptor of the current database, to
+// which publications belong.
+func (p *planner) publicationDatabase(
+	ctx context.Context,
+) (*sqlbase.ImmutableDatabaseDescriptor, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	return p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+}
+
+type createPublicationNode struct {
+	n        *tree.CreatePublication
+	dbDesc   *sqlbase.ImmutableDatabaseDescriptor
+	tableIDs []sqlbase.ID
+}
+
+// CreatePublication creates a publication.
+// Privileges: CREATE on database and on the published tables; admin for
+// FOR ALL TABLES.
+//   Notes: postgres requires CREATE on database and ownership of the tables,
+//          and superuser for FOR ALL TABLES.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.publicationDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if n.AllTables {
+		if err := p.RequireAdminRole(ctx, "create a publication FOR ALL TABLES"); err != nil {
+			return nil, err
+		}
+	}
+
+	var tableIDs []sqlbase.ID
+	seen := make(map[sqlbase.ID]struct{})
+	for i := range n.Tables {
+		tn := &n.Tables[i]
+		tableDesc, err := p.ResolveUncachedTableDescriptor(
+			ctx, tn, true /* required */, resolver.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+			return nil, err
+		}
+		if tableDesc.ParentID != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"table %q does not belong to database %q", tree.ErrString(tn), dbDesc.GetName())
+		}
+		if tableDesc.Temporary {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"table %q cannot be replicated: temporary tables cannot be published", tree.ErrString(tn))
+		}
+		if tableDesc.IsForeignTable() {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"table %q cannot be replicated: foreign tables cannot be published", tree.ErrString(tn))
+		}
+		if _, ok := seen[tableDesc.ID]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"relation %q is already member of publication %q", tableDesc.Name, n.Name)
+		}
+		seen[tableDesc.ID] = struct{}{}
+		tableIDs = append(tableIDs, tableDesc.ID)
+	}
+
+	exists, err := publicationExists(ctx, p.ExecCfg().InternalExecutor, p.txn, dbDesc.GetID(), string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", n.Name)
+	}
+
+	return &createPublicationNode{n: n, dbDesc: dbDesc, tableIDs: tableIDs}, nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("publication"))
+
+	tableIDs := tree.NewDArray(types.Int)
+	for _, id := range n.tableIDs {
+		if err := tableIDs.Append(tree.NewDInt(tree.DInt(id))); err != nil {
+			return err
+		}
+	}
+	_, err := params.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		"create-publication",
+		params.p.txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`INSERT INTO system.publications (database_id, name, owner, all_tables, table_ids)
+VALUES ($1, $2, $3, $4, $5)`,
+		n.dbDesc.GetID(),
+		string(n.n.Name),
+		params.SessionData().User,
+		n.n.AllTables,
+		tableIDs,
+	)
+	return err
+}
+
+func (*createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createPublicationNode) Close(context.Context)        {}
+
+type dropPublicationNode struct {
+	dbDesc *sqlbase.ImmutableDatabaseDescriptor
+	names  []string
+}
+
+// DropPublication drops publications.
+// Privileges: ownership of the publication or admin.
+//   Notes: postgres requires ownership of the publication.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.publicationDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &dropPublicationNode{dbDesc: dbDesc}
+	for _, name := range n.Names {
+		row, err := p.ExecCfg().InternalExecutor.QueryRowEx(
+			ctx, "get-publication-owner", p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`SELECT owner FROM system.publications WHERE database_id = $1 AND name = $2`,
+			dbDesc.GetID(), string(name),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+		if owner := string(tree.MustBeDString(row[0])); !isAdmin && owner != p.User() {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of publication %s", name)
+		}
+		node.names = append(node.names, string(name))
+	}
+	return node, nil
+}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("publication"))
+
+	for _, name := range n.names {
+		if _, err := params.ExecCfg().InternalExecutor.ExecEx(
+			params.ctx,
+			"drop-publication",
+			params.p.txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`DELETE FROM system.publications WHERE database_id = $1 AND name = $2`,
+			n.dbDesc.GetID(), name,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropPublicationNode) Close(context.Context)        {}
+
+// publicationExists returns whether the publication with the given name exists
+// in the given database.
+func publicationExists(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, dbID sqlbase.ID, name string,
+) (bool, error) {
+	row, err := ie.QueryRowEx(
+		ctx, "publication-exists", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT 1 FROM system.publications WHERE database_id = $1 AND name = $2`,
+		dbID, name,
+	)
+	return row != nil, err
+}
+
+// databasePublications returns the names of the publications of the given
+// database.
+func databasePublications(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, dbID sqlbase.ID,
+) ([]string, error) {
+	rows, err := ie.QueryEx(
+		ctx, "get-database-publications", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT name FROM system.publications WHERE database_id = $1`,
+		dbID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = string(tree.MustBeDString(row[0]))
+	}
+	return names, nil
+}
+
+// resolvePublicationTables returns the IDs of the tables published by the
+// given publications of a database, in ascending order. Publications FOR ALL
+// TABLES cover the tables of the database that exist at the timestamp of the
+// transaction, except for the foreign tables. The tables dropped since they
+// were published are skipped.
+func resolvePublicationTables(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	codec keys.SQLCodec,
+	dbID sqlbase.ID,
+	names []string,
+) ([]sqlbase.ID, error) {
+	ids := make(map[sqlbase.ID]struct{})
+	allTables := false
+	for _, name := range names {
+		row, err := ie.QueryRowEx(
+			ctx, "get-publication", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`SELECT all_tables, table_ids FROM system.publications WHERE database_id = $1 AND name = $2`,
+			dbID, name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return nil, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+		if tree.MustBeDBool(row[0]) {
+			allTables = true
+		}
+		for _, d := range tree.MustBeDArray(row[1]).Array {
+			ids[sqlbase.ID(tree.MustBeDInt(d))] = struct{}{}
+		}
+	}
+
+	descs, err := catalogkv.GetAllDescriptors(ctx, txn, codec)
+	if err != nil {
+		return nil, err
+	}
+	var res []sqlbase.ID
+	for _, desc := range descs {
+		tbl, ok := desc.(*sqlbase.ImmutableTableDescriptor)
+		if !ok || tbl.ParentID != dbID || !tbl.IsTable() || tbl.Temporary || tbl.IsForeignTable() ||
+			tbl.Dropped() {
+			continue
+		}
+		if _, ok := ids[tbl.ID]; ok || allTables {
+			res = append(res, tbl.ID)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+type refreshMaterializedViewNode struct {
+	n    *tree.RefreshMaterializedView
+	desc *sqlbase.MutableTableDescriptor
+}
+
+// RefreshMaterializedView recomputes the results of a materialized view.
+// Privileges: CREATE on view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	// The view is refreshed by a schema change job that only starts once the
+	// transaction that queued it commits, so the refresh would not be visible
+	// to the rest of an explicit transaction.
+	if !p.EvalContext().TxnImplicit {
+		return nil, pgerror.Newf(pgcode.InvalidTransactionState,
+			"cannot refresh view in an explicit transaction")
+	}
+	desc, err := p.ResolveMutableTableDescriptorEx(ctx, n.Name, true /* required */, resolver.ResolveRequireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.MaterializedView() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return &refreshMaterializedViewNode{n: n, desc: desc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because REFRESH MATERIALIZED VIEW performs multiple KV operations
+// on descriptors and expects to see its own writes.
+func (n *refreshMaterializedViewNode) ReadingOwnWrites() {}
+
+func (n *refreshMaterializedViewNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeRefreshCounter("materialized_view"))
+
+	// We refresh a materialized view by creating a new set of indexes to write
+	// the result of the view query into. The existing set of indexes remains
+	// present and readable so that reads of the view during the refresh
+	// operation return consistent data. The schema change process backfills
+	// the results of the view query into the new set of indexes, and then
+	// swaps the new set of indexes in for the old one atomically. Because
+	// readers are never blocked, REFRESH MATERIALIZED VIEW CONCURRENTLY is
+	// executed in exactly the same way.
+
+	// Prepare the new set of indexes by cloning all existing indexes on the view.
+	newPrimaryIndex := protoutil.Clone(&n.desc.PrimaryIndex).(*sqlbase.IndexDescriptor)
+	newIndexes := make([]sqlbase.IndexDescriptor, len(n.desc.Indexes))
+	for i := range n.desc.Indexes {
+		newIndexes[i] = *protoutil.Clone(&n.desc.Indexes[i]).(*sqlbase.IndexDescriptor)
+	}
+
+	// Allocate new IDs for the new indexes.
+	getID := func() sqlbase.IndexID {
+		res := n.desc.NextIndexID
+		n.desc.NextIndexID++
+		return res
+	}
+	newPrimaryIndex.ID = getID()
+	for i := range newIndexes {
+		newIndexes[i].ID = getID()
+	}
+
+	// Queue the refresh mutation.
+	n.desc.AddMaterializedViewRefreshMutation(&sqlbase.MaterializedViewRefresh{
+		NewPrimaryIndex: *newPrimaryIndex,
+		NewIndexes:      newIndexes,
+		AsOf:            params.p.Txn().ReadTimestamp(),
+	})
+
+	return params.p.writeSchemaChange(
+		params.ctx,
+		n.desc,
+		n.desc.ClusterVersion.NextMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
@@ -0,0 +1,556 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// Replication slots are stored as rows of system.replication_slots. A slot
+// records the position up to which its client has confirmed the changes of
+// the stream, and holds a protected timestamp record at that position so that
+// the changes that haven't been confirmed yet can't be garbage collected. The
+// record only covers the published tables: those of the publications of the
+// stream reading from the slot, or, until a stream advances it, those of all
+// the publications of the database of the slot.
+//
+// Positions in the stream (LSNs) are the wall times of the commit timestamps
+// of the transactions: a slot confirmed up to LSN L streams the transactions
+// whose commit timestamp has a wall time greater than L.
+
+// ReplicationSlotMetaType is the value used in the ptpb.Record.MetaType field
+// for records associated with replication slots. The Meta field contains the
+// name of the slot.
+//
+// This value must not be changed as it is used durably in the database.
+const ReplicationSlotMetaType = "replication_slot"
+
+// pgoutputPlugin is the only supported output plugin of replication slots.
+const pgoutputPlugin = "pgoutput"
+
+// maxReplicationSlotNameLength matches the limit of Postgres.
+const maxReplicationSlotNameLength = 63
+
+// MakeReplicationSlotStatusFunc returns a function which determines whether
+// the protected timestamp record of the replication slot named in meta should
+// be removed by the reconciler, i.e. whether the slot doesn't exist anymore.
+// It has the signature of a ptreconcile.StatusFunc.
+func MakeReplicationSlotStatusFunc(
+	ie *InternalExecutor,
+) func(ctx context.Context, txn *kv.Txn, meta []byte) (shouldRemove bool, _ error) {
+	return func(ctx context.Context, txn *kv.Txn, meta []byte) (shouldRemove bool, _ error) {
+		slot, err := getReplicationSlot(ctx, ie, txn, string(meta))
+		if err != nil {
+			return false, err
+		}
+		return slot == nil, nil
+	}
+}
+
+// replicationSlot is a row of system.replication_slots.
+type replicationSlot struct {
+	name              string
+	plugin            string
+	databaseID        sqlbase.ID
+	owner             string
+	confirmedFlushLSN tree.LSN
+	protectedTSRecord uuid.UUID
+}
+
+// getReplicationSlot returns the replication slot with the given name, or nil
+// if it doesn't exist.
+func getReplicationSlot(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, name string,
+) (*replicationSlot, error) {
+	row, err := ie.QueryRowEx(
+		ctx, "get-replication-slot", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`SELECT plugin, database_id, owner, confirmed_flush_lsn, protected_ts_record
+FROM system.replication_slots WHERE slot_name = $1`,
+		name,
+	)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return &replicationSlot{
+		name:              name,
+		plugin:            string(tree.MustBeDString(row[0])),
+		databaseID:        sqlbase.ID(tree.MustBeDInt(row[1])),
+		owner:             string(tree.MustBeDString(row[2])),
+		confirmedFlushLSN: tree.LSN(tree.MustBeDInt(row[3])),
+		protectedTSRecord: row[4].(*tree.DUuid).UUID,
+	}, nil
+}
+
+// replicationSlotSpans returns the spans protected by the protected timestamp
+// records of replication slots: the given published tables, and the
+// descriptors, which are needed to decode the changes to the tables.
+func replicationSlotSpans(codec keys.SQLCodec, tableIDs []sqlbase.ID) []roachpb.Span {
+	descPrefix := codec.TablePrefix(keys.DescriptorTableID)
+	spans := make([]roachpb.Span, 0, len(tableIDs)+1)
+	spans = append(spans, roachpb.Span{Key: descPrefix, EndKey: descPrefix.PrefixEnd()})
+	for _, id := range tableIDs {
+		prefix := codec.TablePrefix(uint32(id))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+	return spans
+}
+
+// makeReplicationSlotRecord makes a protected timestamp record protecting the
+// changes to the given tables after the given position of a replication slot.
+func makeReplicationSlotRecord(
+	codec keys.SQLCodec, slot string, lsn tree.LSN, tableIDs []sqlbase.ID,
+) *ptpb.Record {
+	return &ptpb.Record{
+		ID:        uuid.MakeV4(),
+		Timestamp: hlc.Timestamp{WallTime: int64(lsn)},
+		Mode:      ptpb.PROTECT_AFTER,
+		MetaType:  ReplicationSlotMetaType,
+		Meta:      []byte(slot),
+		Spans:     replicationSlotSpans(codec, tableIDs),
+	}
+}
+
+// checkReplicationSlotName returns an error if the name of a replication slot
+// is invalid. Like in Postgres, the names can only contain lower case letters,
+// numbers and underscores.
+func checkReplicationSlotName(name string) error {
+	if name == "" {
+		return pgerror.Newf(pgcode.InvalidName, "replication slot name %q is too short", name)
+	}
+	if len(name) > maxReplicationSlotNameLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidName,
+					"replication slot name %q contains invalid character", name),
+				"Replication slot names may only contain lower case letters, numbers, and the underscore character.",
+			)
+		}
+	}
+	return nil
+}
+
+var identifySystemColumns = sqlbase.ResultColumns{
+	{Name: "systemid", Typ: types.String},
+	{Name: "timeline", Typ: types.Int},
+	{Name: "xlogpos", Typ: types.String},
+	{Name: "dbname", Typ: types.String},
+}
+
+// IdentifySystem implements the IDENTIFY_SYSTEM replication command. The
+// system identifier is the cluster ID, and the current position is the
+// current time. There is a single timeline.
+func (p *planner) IdentifySystem(ctx context.Context, n *tree.IdentifySystem) (planNode, error) {
+	return &delayedNode{
+		name:    n.String(),
+		columns: identifySystemColumns,
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			dbName := tree.DNull
+			if db := p.CurrentDatabase(); db != "" {
+				dbName = tree.NewDString(db)
+			}
+			v := p.newContainerValuesNode(identifySystemColumns, 1)
+			if _, err := v.rows.AddRow(ctx, tree.Datums{
+				tree.NewDString(p.ExecCfg().ClusterID().String()),
+				tree.NewDInt(1),
+				tree.NewDString(tree.LSN(p.ExecCfg().Clock.Now().WallTime).String()),
+				dbName,
+			}); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+var createReplicationSlotColumns = sqlbase.ResultColumns{
+	{Name: "slot_name", Typ: types.String},
+	{Name: "consistent_point", Typ: types.String},
+	{Name: "snapshot_name", Typ: types.String},
+	{Name: "output_plugin", Typ: types.String},
+}
+
+// CreateReplicationSlot implements the CREATE_REPLICATION_SLOT replication
+// command. The slot belongs to the current database, and starts at the
+// timestamp of the transaction creating it.
+// Privileges: admin.
+//   Notes: postgres requires the REPLICATION role attribute.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *tree.CreateReplicationSlot,
+) (planNode, error) {
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "use replication slots"); err != nil {
+		return nil, err
+	}
+	if err := checkReplicationSlotName(string(n.Name)); err != nil {
+		return nil, err
+	}
+	if n.Temporary {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"temporary replication slots are not supported")
+	}
+	if n.Plugin != pgoutputPlugin {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"output plugin %q is not supported", n.Plugin)
+	}
+	dbDesc, err := p.publicationDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delayedNode{
+		name:    n.String(),
+		columns: createReplicationSlotColumns,
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("replication_slot"))
+
+			ie := p.ExecCfg().InternalExecutor
+			slot, err := getReplicationSlot(ctx, ie, p.txn, string(n.Name))
+			if err != nil {
+				return nil, err
+			}
+			if slot != nil {
+				return nil, pgerror.Newf(pgcode.DuplicateObject,
+					"replication slot %q already exists", n.Name)
+			}
+
+			// The slot is only tied to publications once a stream reads from it,
+			// so until then it protects the tables of all the publications of
+			// its database.
+			pubs, err := databasePublications(ctx, ie, p.txn, dbDesc.GetID())
+			if err != nil {
+				return nil, err
+			}
+			tableIDs, err := resolvePublicationTables(
+				ctx, ie, p.txn, p.ExecCfg().Codec, dbDesc.GetID(), pubs,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// The transactions committed at the timestamp of this transaction
+			// may not be visible to it, so the slot starts just before.
+			lsn := tree.LSN(p.txn.ReadTimestamp().WallTime - 1)
+			rec := makeReplicationSlotRecord(p.ExecCfg().Codec, string(n.Name), lsn, tableIDs)
+			if err := p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, p.txn, rec); err != nil {
+				return nil, err
+			}
+			if _, err := ie.ExecEx(
+				ctx, "create-replication-slot", p.txn,
+				sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+				`INSERT INTO system.replication_slots
+  (slot_name, plugin, database_id, owner, confirmed_flush_lsn, protected_ts_record)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+				string(n.Name), n.Plugin, dbDesc.GetID(), p.User(), int64(lsn),
+				tree.NewDUuid(tree.DUuid{UUID: rec.ID}),
+			); err != nil {
+				return nil, err
+			}
+
+			v := p.newContainerValuesNode(createReplicationSlotColumns, 1)
+			if _, err := v.rows.AddRow(ctx, tree.Datums{
+				tree.NewDString(string(n.Name)),
+				tree.NewDString(lsn.String()),
+				tree.DNull,
+				tree.NewDString(n.Plugin),
+			}); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+type dropReplicationSlotNode struct {
+	n *tree.DropReplicationSlot
+}
+
+// DropReplicationSlot implements the DROP_REPLICATION_SLOT replication
+// command.
+// Privileges: admin.
+//   Notes: postgres requires the REPLICATION role attribute.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *tree.DropReplicationSlot,
+) (planNode, error) {
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "use replication slots"); err != nil {
+		return nil, err
+	}
+	return &dropReplicationSlotNode{n: n}, nil
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("replication_slot"))
+
+	// The activity of slots is not tracked, so WAIT has no effect. A stream
+	// reading from a dropped slot fails the next time it tries to advance it.
+	ie := params.ExecCfg().InternalExecutor
+	slot, err := getReplicationSlot(params.ctx, ie, params.p.txn, string(n.n.Name))
+	if err != nil {
+		return err
+	}
+	if slot == nil {
+		return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q does not exist", n.n.Name)
+	}
+	return dropReplicationSlot(params.ctx, params.ExecCfg(), params.p.txn, slot)
+}
+
+func (*dropReplicationSlotNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropReplicationSlotNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropReplicationSlotNode) Close(context.Context)        {}
+
+// dropReplicationSlot removes a replication slot and releases its protected
+// timestamp record.
+func dropReplicationSlot(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn, slot *replicationSlot,
+) error {
+	if err := execCfg.ProtectedTimestampProvider.Release(
+		ctx, txn, slot.protectedTSRecord,
+	); err != nil && !errors.Is(err, protectedts.ErrNotExists) {
+		return err
+	}
+	_, err := execCfg.InternalExecutor.ExecEx(
+		ctx, "drop-replication-slot", txn,
+		sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+		`DELETE FROM system.replication_slots WHERE slot_name = $1`,
+		slot.name,
+	)
+	return err
+}
+
+// AdvanceReplicationSlot records that the client of a replication slot has
+// confirmed the changes up to the given position, and moves the protected
+// timestamp of the slot accordingly. The new record protects the given tables,
+// which are those published by the publications of the stream. Positions
+// before the current one of the slot are ignored.
+func AdvanceReplicationSlot(
+	ctx context.Context, execCfg *ExecutorConfig, name string, lsn tree.LSN, tableIDs []sqlbase.ID,
+) error {
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		slot, err := getReplicationSlot(ctx, execCfg.InternalExecutor, txn, name)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q does not exist", name)
+		}
+		if lsn <= slot.confirmedFlushLSN {
+			return nil
+		}
+
+		// Protected timestamp records can't be moved, so the record is replaced.
+		pts := execCfg.ProtectedTimestampProvider
+		if err := pts.Release(ctx, txn, slot.protectedTSRecord); err != nil &&
+			!errors.Is(err, protectedts.ErrNotExists) {
+			return err
+		}
+		rec := makeReplicationSlotRecord(execCfg.Codec, name, lsn, tableIDs)
+		if err := pts.Protect(ctx, txn, rec); err != nil {
+			return err
+		}
+		_, err = execCfg.InternalExecutor.ExecEx(
+			ctx, "advance-replication-slot", txn,
+			sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+			`UPDATE system.replication_slots SET confirmed_flush_lsn = $2, protected_ts_record = $3
+WHERE slot_name = $1`,
+			name, int64(lsn), tree.NewDUuid(tree.DUuid{UUID: rec.ID}),
+		)
+		return err
+	})
+}
+
+// ReplicationStreamSpec describes a logical replication stream started with
+// START_REPLICATION.
+type ReplicationStreamSpec struct {
+	// Slot is the name of the replication slot of the stream.
+	Slot string
+	// DatabaseID is the ID of the database of the slot.
+	DatabaseID sqlbase.ID
+	// StartLSN is the position after which changes are streamed.
+	StartLSN tree.LSN
+	// TableIDs are the IDs of the tables published by the publications
+	// requested by the client.
+	TableIDs []sqlbase.ID
+	// User is the user running the stream.
+	User string
+	// DataConversion is used to encode the values of the streamed rows.
+	DataConversion sessiondata.DataConversionConfig
+
+	// Conn is the connection to which the stream is written.
+	Conn pgwirebase.ReplicationConn
+	// Feedback receives the status updates of the client. It is closed when
+	// the client ends the stream.
+	Feedback <-chan pgwirebase.StandbyStatusUpdate
+}
+
+// StartReplicationCCL is the hook running a logical replication stream. It
+// is set by the changefeedccl package, which reads the changes with the
+// rangefeeds of changefeeds. It returns once the client ends the stream.
+var StartReplicationCCL = func(
+	ctx context.Context, execCfg *ExecutorConfig, spec ReplicationStreamSpec,
+) error {
+	return sqlbase.NewCCLRequiredError(errors.New(
+		"logical replication requires a CCL binary"))
+}
+
+// startReplication implements the START_REPLICATION replication command. It
+// checks the slot and the options of the pgoutput plugin requested by the
+// client, then runs the replication stream.
+// Privileges: admin.
+//   Notes: postgres requires the REPLICATION role attribute.
+func startReplication(
+	ctx context.Context, execCfg *ExecutorConfig, sd *sessiondata.SessionData, cmd StartReplication,
+) error {
+	if err := checkLogicalReplicationSupported(ctx, execCfg); err != nil {
+		return err
+	}
+	publications, err := parsePgoutputOptions(cmd.Stmt.Options)
+	if err != nil {
+		return err
+	}
+
+	spec := ReplicationStreamSpec{
+		Slot:           string(cmd.Stmt.Slot),
+		User:           sd.User,
+		DataConversion: sd.DataConversion,
+		Conn:           cmd.Conn,
+		Feedback:       cmd.Feedback,
+	}
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		p, cleanup := newInternalPlanner("start-replication", txn, sd.User, &MemoryMetrics{}, execCfg)
+		defer cleanup()
+		if err := p.RequireAdminRole(ctx, "use replication slots"); err != nil {
+			return err
+		}
+
+		slot, err := getReplicationSlot(ctx, execCfg.InternalExecutor, txn, spec.Slot)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q does not exist", spec.Slot)
+		}
+		if sd.Database == "" {
+			return errNoDatabase
+		}
+		dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, sd.Database, true /* required */)
+		if err != nil {
+			return err
+		}
+		if dbDesc.GetID() != slot.databaseID {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication slot %q was not created in this database", spec.Slot)
+		}
+		spec.DatabaseID = slot.databaseID
+
+		// Like in Postgres, the stream starts from the confirmed position of the
+		// slot if the client asks for an earlier one.
+		spec.StartLSN = cmd.Stmt.StartLSN
+		if spec.StartLSN < slot.confirmedFlushLSN {
+			spec.StartLSN = slot.confirmedFlushLSN
+		}
+
+		spec.TableIDs, err = resolvePublicationTables(
+			ctx, execCfg.InternalExecutor, txn, execCfg.Codec, slot.databaseID, publications,
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	telemetry.Inc(sqltelemetry.ReplicationStreamCounter)
+	return StartReplicationCCL(ctx, execCfg, spec)
+}
+
+// parsePgoutputOptions checks the options of the pgoutput plugin passed to
+// START_REPLICATION, and returns the names of the requested publications.
+func parsePgoutputOptions(opts []tree.ReplicationOption) ([]string, error) {
+	var publications []string
+	for _, opt := range opts {
+		switch opt.Key {
+		case "proto_version":
+			v, err := strconv.Atoi(opt.Value)
+			if err != nil {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid proto_version: %q", opt.Value)
+			}
+			if v != 1 {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%d but we only support protocol 1", v)
+			}
+		case "publication_names":
+			publications = splitPublicationNames(opt.Value)
+		case "binary":
+			if b, err := tree.ParseDBool(opt.Value); err != nil || *b {
+				return nil, pgerror.New(pgcode.FeatureNotSupported,
+					"binary replication is not supported")
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Key)
+		}
+	}
+	if len(publications) == 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+	}
+	return publications, nil
+}
+
+// splitPublicationNames splits the comma-separated list of publication names
+// of the publication_names option. Names are normalized unless they are
+// double-quoted. Quoted names can't contain commas.
+func splitPublicationNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+			name = strings.Replace(name[1:len(name)-1], `""`, `"`, -1)
+		} else {
+			name = lex.NormalizeName(name)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
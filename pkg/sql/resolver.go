@@ -244,6 +244,29 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		// Privileges are shared by all the overloads of a function.
+		descs := make([]sqlbase.DescriptorInterface, 0, len(targets.Functions))
+		for _, fo := range targets.Functions {
+			desc, err := p.resolveFunctionObject(ctx, fo, true /* required */)
+			if err != nil {
+				return nil, err
+			}
+			if fo.Args != nil {
+				argTypes, err := p.resolveFunctionArgTypes(ctx, fo)
+				if err != nil {
+					return nil, err
+				}
+				if desc.FindOverload(argTypes) == -1 {
+					return nil, pgerror.Newf(pgcode.UndefinedFunction,
+						"function %s does not exist", tree.ErrString(&fo))
+				}
+			}
+			descs = append(descs, desc)
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -539,6 +562,8 @@ func newInternalLookupCtxFromDescriptors(
 			descs[i] = sqlbase.NewImmutableTypeDescriptor(*t.Type)
 		case *sqlbase.Descriptor_Schema:
 			descs[i] = sqlbase.NewImmutableSchemaDescriptor(*t.Schema)
+		case *sqlbase.Descriptor_Function:
+			descs[i] = sqlbase.NewImmutableFunctionDescriptor(*t.Function)
 		}
 	}
 	return newInternalLookupCtx(descs, prefix)
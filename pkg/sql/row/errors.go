@@ -66,6 +66,24 @@ func ConvertBatchError(
 	return origPErr.GoError()
 }
 
+// ConvertFetchError attempts to map a key-value error generated during a
+// key-value fetch from the provided table to a user friendly SQL error.
+func ConvertFetchError(tableDesc *sqlbase.ImmutableTableDescriptor, err error) error {
+	var wiErr *roachpb.WriteIntentError
+	if errors.As(err, &wiErr) && wiErr.Reason == roachpb.WriteIntentError_REASON_WAIT_POLICY {
+		return NewLockNotAvailableError(tableDesc)
+	}
+	return err
+}
+
+// NewLockNotAvailableError creates an error that represents an inability to
+// acquire a lock on a row of the provided table without waiting. It is
+// returned when a locking read uses the NOWAIT wait policy.
+func NewLockNotAvailableError(tableDesc *sqlbase.ImmutableTableDescriptor) error {
+	return pgerror.Newf(pgcode.LockNotAvailable,
+		"could not obtain lock on row in relation %q", tableDesc.Name)
+}
+
 // NewUniquenessConstraintViolationError creates an error that represents a
 // violation of a UNIQUE constraint.
 func NewUniquenessConstraintViolationError(
@@ -118,6 +136,7 @@ func NewUniquenessConstraintViolationError(
 		codec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&sqlbase.DatumAlloc{},
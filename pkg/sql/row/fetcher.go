@@ -89,6 +89,10 @@ type tableInfo struct {
 	// id pair at the start of the key.
 	knownPrefixLength int
 
+	// virtualCols are the needed virtual computed columns, which are computed
+	// once a row has been decoded from the primary index.
+	virtualCols virtualColumns
+
 	// -- Fields updated during a scan --
 
 	keyValTypes []*types.T
@@ -141,6 +145,10 @@ type FetcherTableArgs struct {
 	Cols             []sqlbase.ColumnDescriptor
 	// The indexes (0 to # of columns - 1) of the columns to return.
 	ValNeededForCol util.FastIntSet
+	// EvalCtx is used to compute the virtual computed columns of the table. It
+	// must be set if ValNeededForCol contains virtual columns and the primary
+	// index is scanned.
+	EvalCtx *tree.EvalContext
 }
 
 // Fetcher handles fetching kvs and forming table rows for an
@@ -196,6 +204,10 @@ type Fetcher struct {
 	// lockStr represents the row-level locking mode to use when fetching rows.
 	lockStr sqlbase.ScanLockingStrength
 
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -251,6 +263,7 @@ func (rf *Fetcher) Init(
 	codec keys.SQLCodec,
 	reverse bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
@@ -263,6 +276,7 @@ func (rf *Fetcher) Init(
 	rf.codec = codec
 	rf.reverse = reverse
 	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
 	rf.returnRangeInfo = returnRangeInfo
 	rf.alloc = alloc
 	rf.isCheck = isCheck
@@ -299,6 +313,7 @@ func (rf *Fetcher) Init(
 			indexColIdx: oldTable.indexColIdx[:0],
 			keyVals:     oldTable.keyVals[:0],
 			extraVals:   oldTable.extraVals[:0],
+			virtualCols: virtualColumns{colIdxs: oldTable.virtualCols.colIdxs[:0]},
 		}
 
 		var err error
@@ -341,6 +356,16 @@ func (rf *Fetcher) Init(
 			}
 		}
 
+		// Virtual columns are not stored in the primary index. Instead, the
+		// columns they reference are fetched, and they are computed from them.
+		var virtualColDeps util.FastIntSet
+		if !table.isSecondaryIndex {
+			virtualColDeps, err = table.initVirtualCols(tableArgs.EvalCtx)
+			if err != nil {
+				return err
+			}
+		}
+
 		table.knownPrefixLength = len(
 			sqlbase.MakeIndexKeyPrefix(codec, table.desc.TableDesc(), table.index.ID),
 		)
@@ -349,6 +374,10 @@ func (rf *Fetcher) Init(
 		indexColumnIDs, table.indexColumnDirs = table.index.FullColumnIDs()
 
 		table.neededValueColsByIdx = tableArgs.ValNeededForCol.Copy()
+		table.neededValueColsByIdx.UnionWith(virtualColDeps)
+		for _, idx := range table.virtualCols.colIdxs {
+			table.neededValueColsByIdx.Remove(idx)
+		}
 		neededIndexCols := 0
 		nIndexCols := len(indexColumnIDs)
 		if cap(table.indexColIdx) >= nIndexCols {
@@ -483,6 +512,7 @@ func (rf *Fetcher) StartScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
@@ -563,6 +593,7 @@ func (rf *Fetcher) StartInconsistentScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
@@ -604,7 +635,7 @@ func (rf *Fetcher) NextKey(ctx context.Context) (rowDone bool, err error) {
 	for {
 		ok, rf.kv, _, err = rf.kvFetcher.NextKV(ctx)
 		if err != nil {
-			return false, err
+			return false, ConvertFetchError(rf.tables[0].desc, err)
 		}
 		rf.kvEnd = !ok
 		if rf.kvEnd {
@@ -1181,6 +1212,9 @@ func (rf *Fetcher) NextRow(
 		}
 		if rowDone {
 			err := rf.finalizeRow()
+			if err == nil {
+				err = rf.computeVirtualCols()
+			}
 			return rf.rowReadyTable.row, rf.rowReadyTable.desc.TableDesc(), rf.rowReadyTable.index, err
 		}
 	}
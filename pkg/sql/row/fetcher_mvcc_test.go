@@ -109,6 +109,7 @@ func TestRowFetcherMVCCMetadata(t *testing.T) {
 		keys.SystemSQLCodec,
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		true,  /* isCheck */
 		&sqlbase.DatumAlloc{},
@@ -77,6 +77,7 @@ func initFetcher(
 		fetcherCodec,
 		reverseScan,
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		alloc,
@@ -57,6 +57,9 @@ type txnKVFetcher struct {
 	reverse         bool
 	// lockStr represents the locking mode to use when fetching KVs.
 	lockStr sqlbase.ScanLockingStrength
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
@@ -164,6 +167,24 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 	}
 }
 
+// getWaitPolicy returns the configured lock wait policy to use for key-value
+// scans.
+func (f *txnKVFetcher) getWaitPolicy() lock.WaitPolicy {
+	switch f.lockWaitPolicy {
+	case sqlbase.ScanLockingWaitPolicy_BLOCK:
+		return lock.WaitPolicy_Block
+
+	case sqlbase.ScanLockingWaitPolicy_SKIP:
+		return lock.WaitPolicy_SkipLocked
+
+	case sqlbase.ScanLockingWaitPolicy_ERROR:
+		return lock.WaitPolicy_Error
+
+	default:
+		panic(fmt.Sprintf("unknown wait policy %s", f.lockWaitPolicy))
+	}
+}
+
 // makeKVBatchFetcher initializes a kvBatchFetcher for the given spans.
 //
 // If useBatchLimit is true, batches are limited to kvBatchSize. If
@@ -178,6 +199,7 @@ func makeKVBatchFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	sendFn := func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
@@ -188,7 +210,7 @@ func makeKVBatchFetcher(
 		return res, nil
 	}
 	return makeKVBatchFetcherWithSendFunc(
-		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, returnRangeInfo,
+		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, returnRangeInfo,
 	)
 }
 
@@ -201,6 +223,7 @@ func makeKVBatchFetcherWithSendFunc(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
@@ -253,6 +276,7 @@ func makeKVBatchFetcherWithSendFunc(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		lockStr:         lockStr,
+		lockWaitPolicy:  lockWaitPolicy,
 		returnRangeInfo: returnRangeInfo,
 	}, nil
 }
@@ -272,6 +296,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		ba.Header.TargetBytes = 10 * (1 << 20)
 	}
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = f.getWaitPolicy()
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	keyLocking := f.getKeyLockingStrength()
 	if f.reverse {
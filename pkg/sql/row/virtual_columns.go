@@ -0,0 +1,168 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package row
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// virtualColumns holds the state needed to compute the virtual computed
+// columns of a table when its rows are read from the primary index, in which
+// they are not stored.
+type virtualColumns struct {
+	// colIdxs are the indexes into tableInfo.cols of the needed virtual
+	// columns.
+	colIdxs []int
+	// exprs are the computed expressions of the columns in colIdxs.
+	exprs []tree.TypedExpr
+	// depIdxs are the indexes into tableInfo.cols of the columns referenced
+	// by exprs.
+	depIdxs util.FastIntSet
+
+	evalCtx *tree.EvalContext
+	ivars   sqlbase.RowIndexedVarContainer
+}
+
+// initVirtualCols prepares the computation of the needed virtual columns of
+// the table. The virtual columns are removed from the needed columns, which
+// are decoded from KVs, and the columns they reference are added instead.
+// It returns the indexes into cols of the added columns.
+func (t *tableInfo) initVirtualCols(evalCtx *tree.EvalContext) (util.FastIntSet, error) {
+	t.virtualCols = virtualColumns{colIdxs: t.virtualCols.colIdxs[:0]}
+	var cols []sqlbase.ColumnDescriptor
+	for i := range t.cols {
+		col := &t.cols[i]
+		if col.IsVirtual() && t.neededCols.Contains(int(col.ID)) {
+			t.virtualCols.colIdxs = append(t.virtualCols.colIdxs, i)
+			cols = append(cols, *col)
+		}
+	}
+	if len(cols) == 0 {
+		return util.FastIntSet{}, nil
+	}
+	if evalCtx == nil {
+		return util.FastIntSet{}, errors.AssertionFailedf(
+			"virtual column %q of table %q requested without an evaluation context",
+			cols[0].Name, t.desc.Name)
+	}
+
+	tn := tree.MakeUnqualifiedTableName(tree.Name(t.desc.Name))
+	var txCtx transform.ExprTransformContext
+	semaCtx := tree.MakeSemaContext()
+	exprs, err := schemaexpr.MakeComputedExprs(
+		evalCtx.Context, cols, t.desc, &tn, &txCtx, evalCtx, &semaCtx, false, /* addingCols */
+	)
+	if err != nil {
+		return util.FastIntSet{}, err
+	}
+
+	// The computed expressions refer to the public columns of the table.
+	v := depsVisitor{tableCols: t.desc.Columns, colIdxMap: t.colIdxMap}
+	for _, expr := range exprs {
+		tree.WalkExprConst(&v, expr)
+		if v.err != nil {
+			return util.FastIntSet{}, v.err
+		}
+	}
+	for _, idx := range t.virtualCols.colIdxs {
+		t.neededCols.Remove(int(t.cols[idx].ID))
+	}
+	v.deps.ForEach(func(idx int) {
+		t.neededCols.Add(int(t.cols[idx].ID))
+	})
+
+	t.virtualCols.exprs = exprs
+	t.virtualCols.depIdxs = v.deps
+	t.virtualCols.evalCtx = evalCtx
+	t.virtualCols.ivars = sqlbase.RowIndexedVarContainer{
+		Cols:    t.desc.Columns,
+		Mapping: t.colIdxMap,
+	}
+	return v.deps, nil
+}
+
+// computeVirtualCols fills in the needed virtual columns of the row that was
+// just decoded.
+func (rf *Fetcher) computeVirtualCols() error {
+	table := rf.rowReadyTable
+	vc := &table.virtualCols
+	if len(vc.colIdxs) == 0 {
+		return nil
+	}
+	if table.rowIsDeleted {
+		for _, idx := range vc.colIdxs {
+			table.row[idx] = sqlbase.EncDatum{Datum: tree.DNull}
+		}
+		return nil
+	}
+
+	var err error
+	vc.depIdxs.ForEach(func(idx int) {
+		if err != nil {
+			return
+		}
+		if err = table.row[idx].EnsureDecoded(table.cols[idx].Type, rf.alloc); err == nil {
+			table.decodedRow[idx] = table.row[idx].Datum
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	vc.ivars.CurSourceRow = table.decodedRow
+	vc.evalCtx.PushIVarContainer(&vc.ivars)
+	defer vc.evalCtx.PopIVarContainer()
+	for i, idx := range vc.colIdxs {
+		d, err := vc.exprs[i].Eval(vc.evalCtx)
+		if err != nil {
+			return err
+		}
+		table.row[idx] = sqlbase.DatumToEncDatum(table.cols[idx].Type, d)
+	}
+	return nil
+}
+
+// depsVisitor collects the indexes into the fetched columns of the columns
+// referenced by computed expressions.
+type depsVisitor struct {
+	tableCols []sqlbase.ColumnDescriptor
+	colIdxMap map[sqlbase.ColumnID]int
+	deps      util.FastIntSet
+	err       error
+}
+
+var _ tree.Visitor = &depsVisitor{}
+
+// VisitPre implements the tree.Visitor interface.
+func (v *depsVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	if ivar, ok := expr.(*tree.IndexedVar); ok {
+		col := &v.tableCols[ivar.Idx]
+		idx, ok := v.colIdxMap[col.ID]
+		if !ok {
+			v.err = errors.AssertionFailedf("column %q referenced by a virtual column is not fetched", col.Name)
+			return false, expr
+		}
+		v.deps.Add(idx)
+		return false, expr
+	}
+	return true, expr
+}
+
+// VisitPost implements the tree.Visitor interface.
+func (*depsVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
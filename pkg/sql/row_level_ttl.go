@@ -0,0 +1,438 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+)
+
+// The storage parameters configuring row-level TTL.
+const (
+	ttlExpireAfterParam      = `ttl_expire_after`
+	ttlColumnParam           = `ttl_column`
+	ttlJobCronParam          = `ttl_job_cron`
+	ttlSelectBatchSizeParam  = `ttl_select_batch_size`
+	ttlDeleteBatchSizeParam  = `ttl_delete_batch_size`
+	ttlRangeConcurrencyParam = `ttl_range_concurrency`
+	ttlDeleteRateLimitParam  = `ttl_delete_rate_limit`
+)
+
+// defaultTTLJobCron is the schedule of the TTL jobs of a table which doesn't
+// set ttl_job_cron.
+const defaultTTLJobCron = "@hourly"
+
+var ttlJobEnabled = settings.RegisterBoolSetting(
+	"sql.ttl.job.enabled",
+	"whether the jobs deleting expired rows of tables with row-level TTL are started",
+	true,
+)
+
+var ttlDefaultSelectBatchSize = settings.RegisterPositiveIntSetting(
+	"sql.ttl.default_select_batch_size",
+	"default number of expired rows selected at once by row-level TTL jobs",
+	500,
+)
+
+var ttlDefaultDeleteBatchSize = settings.RegisterPositiveIntSetting(
+	"sql.ttl.default_delete_batch_size",
+	"default number of expired rows deleted in a single transaction by row-level TTL jobs",
+	100,
+)
+
+var ttlDefaultRangeConcurrency = settings.RegisterPositiveIntSetting(
+	"sql.ttl.default_range_concurrency",
+	"default number of ranges processed concurrently by each row-level TTL job",
+	4,
+)
+
+var ttlDefaultDeleteRateLimit = settings.RegisterNonNegativeIntSetting(
+	"sql.ttl.default_delete_rate_limit",
+	"default maximum number of rows deleted per second by each row-level TTL job; 0 means unlimited",
+	0,
+)
+
+// isRowLevelTTLParam returns whether the storage parameter configures
+// row-level TTL.
+func isRowLevelTTLParam(name string) bool {
+	switch name {
+	case ttlExpireAfterParam, ttlColumnParam, ttlJobCronParam, ttlSelectBatchSizeParam,
+		ttlDeleteBatchSizeParam, ttlRangeConcurrencyParam, ttlDeleteRateLimitParam:
+		return true
+	}
+	return false
+}
+
+// setRowLevelTTLStorageParams applies the row-level TTL storage parameters
+// among params to the table descriptor. It returns whether the descriptor
+// changed. The schedule of the table is not updated; see
+// updateRowLevelTTLSchedule.
+func (p *planner) setRowLevelTTLStorageParams(
+	ctx context.Context, desc *sqlbase.MutableTableDescriptor, params tree.StorageParams,
+) (bool, error) {
+	var ttl *sqlbase.TableDescriptor_RowLevelTTL
+	if desc.RowLevelTTL != nil {
+		c := *desc.RowLevelTTL
+		ttl = &c
+	} else {
+		ttl = &sqlbase.TableDescriptor_RowLevelTTL{}
+	}
+	found := false
+	for _, sp := range params {
+		k := string(sp.Key)
+		if !isRowLevelTTLParam(k) {
+			continue
+		}
+		if !found {
+			found = true
+			if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionRowLevelTTL) {
+				return false, pgerror.Newf(pgcode.FeatureNotSupported,
+					"row-level TTL requires all nodes to be upgraded to %s",
+					clusterversion.VersionByKey(clusterversion.VersionRowLevelTTL))
+			}
+		}
+		if sp.Value == nil {
+			return false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"storage parameter %q requires a value", k)
+		}
+		expectedType := types.Int
+		switch k {
+		case ttlExpireAfterParam:
+			expectedType = types.Interval
+		case ttlColumnParam, ttlJobCronParam:
+			expectedType = types.String
+		}
+		typedExpr, err := tree.TypeCheckAndRequire(ctx, sp.Value, &p.semaCtx, expectedType, k)
+		if err != nil {
+			return false, err
+		}
+		d, err := typedExpr.Eval(p.EvalContext())
+		if err != nil {
+			return false, err
+		}
+		if d == tree.DNull {
+			return false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"storage parameter %q cannot be NULL", k)
+		}
+
+		switch k {
+		case ttlExpireAfterParam:
+			iv := tree.MustBeDInterval(d)
+			if iv.Duration.Compare(duration.Duration{}) <= 0 {
+				return false, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s must be a positive interval", k)
+			}
+			ttl.ExpireAfter = iv.Duration.String()
+		case ttlColumnParam:
+			name := string(tree.MustBeDString(d))
+			col, dropped, err := desc.FindColumnByName(tree.Name(name))
+			if err != nil {
+				return false, err
+			}
+			if dropped {
+				return false, sqlbase.NewUndefinedColumnError(name)
+			}
+			switch col.Type.Family() {
+			case types.TimestampTZFamily, types.TimestampFamily, types.DateFamily:
+			default:
+				return false, pgerror.Newf(pgcode.InvalidTableDefinition,
+					"%s %q must be of type TIMESTAMPTZ, TIMESTAMP or DATE, not %s",
+					k, col.Name, col.Type.SQLString())
+			}
+			ttl.ColumnID = col.ID
+		case ttlJobCronParam:
+			cron := string(tree.MustBeDString(d))
+			if _, err := cronexpr.Parse(cron); err != nil {
+				return false, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+					"invalid %s %q", k, cron)
+			}
+			ttl.JobCron = cron
+		default:
+			v := int64(tree.MustBeDInt(d))
+			if v < 0 || (v == 0 && k != ttlDeleteRateLimitParam) {
+				return false, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s must be positive", k)
+			}
+			switch k {
+			case ttlSelectBatchSizeParam:
+				ttl.SelectBatchSize = v
+			case ttlDeleteBatchSizeParam:
+				ttl.DeleteBatchSize = v
+			case ttlRangeConcurrencyParam:
+				ttl.RangeConcurrency = v
+			case ttlDeleteRateLimitParam:
+				ttl.DeleteRateLimit = v
+			}
+		}
+	}
+	if !found {
+		return false, nil
+	}
+	if ttl.ExpireAfter == "" || ttl.ColumnID == 0 {
+		return false, pgerror.Newf(pgcode.InvalidTableDefinition,
+			"row-level TTL requires both %s and %s to be set", ttlExpireAfterParam, ttlColumnParam)
+	}
+	if desc.IsInterleaved() {
+		return false, pgerror.Newf(pgcode.FeatureNotSupported,
+			"row-level TTL is not supported on interleaved tables")
+	}
+	if desc.RowLevelTTL != nil && desc.RowLevelTTL.Equal(ttl) {
+		return false, nil
+	}
+	desc.RowLevelTTL = ttl
+	return true, nil
+}
+
+// resetRowLevelTTLStorageParams resets the row-level TTL storage parameters
+// among names. Resetting ttl_expire_after disables row-level TTL on the
+// table. It returns whether the descriptor changed.
+func resetRowLevelTTLStorageParams(
+	desc *sqlbase.MutableTableDescriptor, names tree.NameList,
+) (bool, error) {
+	if desc.RowLevelTTL == nil {
+		return false, nil
+	}
+	for _, name := range names {
+		if name == ttlExpireAfterParam {
+			desc.RowLevelTTL = nil
+			return true, nil
+		}
+	}
+	changed := false
+	for _, name := range names {
+		ttl := *desc.RowLevelTTL
+		switch string(name) {
+		case ttlColumnParam:
+			return false, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"cannot reset %s without resetting %s", ttlColumnParam, ttlExpireAfterParam)
+		case ttlJobCronParam:
+			ttl.JobCron = ""
+		case ttlSelectBatchSizeParam:
+			ttl.SelectBatchSize = 0
+		case ttlDeleteBatchSizeParam:
+			ttl.DeleteBatchSize = 0
+		case ttlRangeConcurrencyParam:
+			ttl.RangeConcurrency = 0
+		case ttlDeleteRateLimitParam:
+			ttl.DeleteRateLimit = 0
+		}
+		if !ttl.Equal(desc.RowLevelTTL) {
+			desc.RowLevelTTL = &ttl
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// checkResetStorageParameters returns an error if one of the names is not a
+// known storage parameter.
+func checkResetStorageParameters(names tree.NameList) error {
+	for _, name := range names {
+		k := string(name)
+		if _, ok := storageParamExpectedTypes[k]; !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "invalid storage parameter %q", k)
+		}
+	}
+	return nil
+}
+
+// rowLevelTTLScheduleID returns the ID of the schedule of the table, or 0 if
+// the table has no row-level TTL.
+func rowLevelTTLScheduleID(desc *sqlbase.TableDescriptor) int64 {
+	if desc.RowLevelTTL == nil {
+		return 0
+	}
+	return desc.RowLevelTTL.ScheduleID
+}
+
+// rowLevelTTLScheduleName returns the name of the schedule of the table.
+func rowLevelTTLScheduleName(id sqlbase.ID) string {
+	return fmt.Sprintf("row-level-ttl-%d", id)
+}
+
+// updateRowLevelTTLSchedule makes the schedule of the table reflect its
+// row-level TTL configuration: the schedule is created if the table has TTL
+// and had none, updated if the table had TTL already, and deleted if the
+// table no longer has TTL. The ID of the schedule is stored in the
+// descriptor, which the caller is responsible for writing.
+func (p *planner) updateRowLevelTTLSchedule(
+	ctx context.Context, desc *sqlbase.MutableTableDescriptor, oldScheduleID int64,
+) error {
+	env := jobs.ProdJobSchedulerEnv
+	ie := p.ExecCfg().InternalExecutor
+	txn := p.txn
+
+	if desc.RowLevelTTL == nil {
+		if oldScheduleID == 0 {
+			return nil
+		}
+		sj, err := jobs.LoadScheduledJob(ctx, env, oldScheduleID, ie, txn)
+		if err != nil {
+			return err
+		}
+		return sj.Delete(ctx, ie, txn)
+	}
+
+	cron := desc.RowLevelTTL.JobCron
+	if cron == "" {
+		cron = defaultTTLJobCron
+	}
+	if oldScheduleID != 0 {
+		sj, err := jobs.LoadScheduledJob(ctx, env, oldScheduleID, ie, txn)
+		if err != nil {
+			return err
+		}
+		if sj.ScheduleExpr() == cron {
+			desc.RowLevelTTL.ScheduleID = oldScheduleID
+			return nil
+		}
+		if err := sj.SetSchedule(cron); err != nil {
+			return err
+		}
+		desc.RowLevelTTL.ScheduleID = oldScheduleID
+		return sj.Update(ctx, ie, txn)
+	}
+
+	return CreateRowLevelTTLSchedule(ctx, ie, txn, desc.TableDesc())
+}
+
+// CreateRowLevelTTLSchedule creates a new schedule for the table with
+// row-level TTL and stores its ID in the descriptor, which the caller is
+// responsible for writing. It is used by RESTORE, since the schedules of the
+// tables are not backed up.
+func CreateRowLevelTTLSchedule(
+	ctx context.Context, ie sqlutil.InternalExecutor, txn *kv.Txn, desc *sqlbase.TableDescriptor,
+) error {
+	cron := desc.RowLevelTTL.JobCron
+	if cron == "" {
+		cron = defaultTTLJobCron
+	}
+	sj := jobs.NewScheduledJob(jobs.ProdJobSchedulerEnv)
+	sj.SetOwner(security.RootUser)
+	sj.SetScheduleName(rowLevelTTLScheduleName(desc.ID))
+	if err := sj.SetSchedule(cron); err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:    jobspb.ScheduleDetails_SKIP,
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+	if err := setRowLevelTTLScheduleArgs(sj, desc.ID); err != nil {
+		return err
+	}
+	if err := sj.Create(ctx, ie, txn); err != nil {
+		return err
+	}
+	desc.RowLevelTTL.ScheduleID = sj.ScheduleID()
+	return nil
+}
+
+// DeleteRowLevelTTLSchedule deletes the schedule of the table, if it has
+// one. It is used by RESTORE to clean up the tables it failed to restore.
+func DeleteRowLevelTTLSchedule(
+	ctx context.Context, ie sqlutil.InternalExecutor, txn *kv.Txn, desc *sqlbase.TableDescriptor,
+) error {
+	scheduleID := rowLevelTTLScheduleID(desc)
+	if scheduleID == 0 {
+		return nil
+	}
+	sj, err := jobs.LoadScheduledJob(ctx, jobs.ProdJobSchedulerEnv, scheduleID, ie, txn)
+	if err != nil {
+		return err
+	}
+	return sj.Delete(ctx, ie, txn)
+}
+
+// reassignRowLevelTTLSchedule makes the schedule of the table, which was
+// created for the table it replaces, refer to the table.
+func (p *planner) reassignRowLevelTTLSchedule(
+	ctx context.Context, desc *sqlbase.MutableTableDescriptor,
+) error {
+	if desc.RowLevelTTL == nil || desc.RowLevelTTL.ScheduleID == 0 {
+		return nil
+	}
+	ie := p.ExecCfg().InternalExecutor
+	sj, err := jobs.LoadScheduledJob(ctx, jobs.ProdJobSchedulerEnv, desc.RowLevelTTL.ScheduleID, ie, p.txn)
+	if err != nil {
+		return err
+	}
+	sj.SetScheduleName(rowLevelTTLScheduleName(desc.ID))
+	if err := setRowLevelTTLScheduleArgs(sj, desc.ID); err != nil {
+		return err
+	}
+	return sj.Update(ctx, ie, p.txn)
+}
+
+// setRowLevelTTLScheduleArgs sets the execution arguments of the schedule of
+// the table with the given ID.
+func setRowLevelTTLScheduleArgs(sj *jobs.ScheduledJob, id sqlbase.ID) error {
+	any, err := pbtypes.MarshalAny(&jobspb.RowLevelTTLExecutionArgs{TableID: id})
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(), jobspb.ExecutionArguments{Args: any})
+	return nil
+}
+
+// rowLevelTTLStorageParams returns the storage parameters describing the
+// row-level TTL configuration of the table, omitting those left at their
+// defaults, in the format used by SHOW CREATE.
+func rowLevelTTLStorageParams(desc *sqlbase.TableDescriptor) ([]string, error) {
+	ttl := desc.RowLevelTTL
+	if ttl == nil {
+		return nil, nil
+	}
+	col, err := desc.FindColumnByID(ttl.ColumnID)
+	if err != nil {
+		return nil, err
+	}
+	quote := func(s string) string {
+		return tree.NewDString(s).String()
+	}
+	res := []string{
+		fmt.Sprintf("%s = %s", ttlExpireAfterParam, quote(ttl.ExpireAfter)),
+		fmt.Sprintf("%s = %s", ttlColumnParam, quote(col.Name)),
+	}
+	if ttl.JobCron != "" {
+		res = append(res, fmt.Sprintf("%s = %s", ttlJobCronParam, quote(ttl.JobCron)))
+	}
+	for _, p := range []struct {
+		name string
+		v    int64
+	}{
+		{ttlSelectBatchSizeParam, ttl.SelectBatchSize},
+		{ttlDeleteBatchSizeParam, ttl.DeleteBatchSize},
+		{ttlRangeConcurrencyParam, ttl.RangeConcurrency},
+		{ttlDeleteRateLimitParam, ttl.DeleteRateLimit},
+	} {
+		if p.v != 0 {
+			res = append(res, fmt.Sprintf("%s = %d", p.name, p.v))
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,626 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/limit"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"golang.org/x/time/rate"
+)
+
+var (
+	metaRowLevelTTLRowsSelected = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_selected",
+		Help:        "Number of expired rows selected by row-level TTL jobs",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLRowsDeleted = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_deleted",
+		Help:        "Number of expired rows deleted by row-level TTL jobs",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLSelectDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.select_duration",
+		Help:        "Duration of the queries selecting expired rows in row-level TTL jobs",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRowLevelTTLDeleteDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.delete_duration",
+		Help:        "Duration of the queries deleting expired rows in row-level TTL jobs",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+)
+
+// RowLevelTTLMetrics are the metrics of the jobs deleting expired rows.
+type RowLevelTTLMetrics struct {
+	RowsSelected   *metric.Counter
+	RowsDeleted    *metric.Counter
+	SelectDuration *metric.Histogram
+	DeleteDuration *metric.Histogram
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*RowLevelTTLMetrics) MetricStruct() {}
+
+// makeRowLevelTTLMetrics makes the metrics of row-level TTL jobs.
+func makeRowLevelTTLMetrics(histogramWindow time.Duration) metric.Struct {
+	return &RowLevelTTLMetrics{
+		RowsSelected:   metric.NewCounter(metaRowLevelTTLRowsSelected),
+		RowsDeleted:    metric.NewCounter(metaRowLevelTTLRowsDeleted),
+		SelectDuration: metric.NewLatency(metaRowLevelTTLSelectDuration, histogramWindow),
+		DeleteDuration: metric.NewLatency(metaRowLevelTTLDeleteDuration, histogramWindow),
+	}
+}
+
+// rowLevelTTLExecutor starts the jobs deleting the expired rows of a table
+// on behalf of its schedule.
+type rowLevelTTLExecutor struct {
+	ex sqlutil.InternalExecutor
+}
+
+var _ jobs.ScheduledJobExecutor = &rowLevelTTLExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *rowLevelTTLExecutor) ExecuteJob(
+	ctx context.Context, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args := &jobspb.RowLevelTTLExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+	execCfg := e.ex.(*InternalExecutor).s.cfg
+	if !ttlJobEnabled.Get(&execCfg.Settings.SV) {
+		sj.AddScheduleChangeReason("row-level TTL jobs are disabled")
+		return nil
+	}
+
+	desc, err := sqlbase.GetTableDescFromID(ctx, txn, execCfg.Codec, args.TableID)
+	if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+		sj.AddScheduleChangeReason("table %d does not exist", args.TableID)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if desc.Dropped() || desc.RowLevelTTL == nil {
+		sj.AddScheduleChangeReason("table %d has no row-level TTL", args.TableID)
+		return nil
+	}
+	if desc.State == sqlbase.TableDescriptor_OFFLINE {
+		// The table is being restored.
+		sj.AddScheduleChangeReason("table %d is offline", args.TableID)
+		return nil
+	}
+	expireAfter, err := tree.ParseDInterval(desc.RowLevelTTL.ExpireAfter)
+	if err != nil {
+		return err
+	}
+
+	job, err := execCfg.JobRegistry.CreateJobWithTxn(ctx, jobs.Record{
+		Description:   fmt.Sprintf("row-level TTL for table %s", desc.Name),
+		Username:      sj.Owner(),
+		DescriptorIDs: sqlbase.IDs{desc.ID},
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: desc.ID,
+			Cutoff:  duration.Add(sj.Env().Now(), expireAfter.Duration.Mul(-1)),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+	}, txn)
+	if err != nil {
+		return err
+	}
+	return jobs.MarkJobCreatedBySchedule(ctx, sj.Env(), *job.ID(), sj.ScheduleID(), e.ex, txn)
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *rowLevelTTLExecutor) NotifyJobTermination(
+	ctx context.Context, md *jobs.JobMetadata, sj *jobs.ScheduledJob, _ *kv.Txn,
+) error {
+	switch md.Status {
+	case jobs.StatusSucceeded:
+	case jobs.StatusFailed:
+		sj.AddScheduleChangeReason("row-level TTL job %d failed: %s", md.ID, md.Payload.Error)
+		jobs.DefaultHandleFailedRun(sj, md.ID, errors.Newf("row-level TTL job failed: %s", md.Payload.Error))
+	default:
+		sj.AddScheduleChangeReason("row-level TTL job %d terminated with status %s", md.ID, md.Status)
+	}
+	return nil
+}
+
+// ttlColumn is a column of the primary key of a table with row-level TTL.
+type ttlColumn struct {
+	name string
+	typ  *types.T
+	dir  encoding.Direction
+}
+
+// ttlSpan is a span of the primary index processed by a row-level TTL job.
+// The bounds are primary key prefixes: the span holds the rows whose key is
+// at or after start, and before end. A nil bound is unbounded.
+type ttlSpan struct {
+	start, end tree.Datums
+}
+
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+
+	mu struct {
+		syncutil.Mutex
+		rowsDeleted    int64
+		spansCompleted int64
+	}
+}
+
+var _ jobs.Resumer = &rowLevelTTLResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) Resume(
+	ctx context.Context, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RowLevelTTLDetails)
+
+	var desc *sqlbase.TableDescriptor
+	var spans []ttlSpan
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		var err error
+		desc, err = sqlbase.GetTableDescFromID(ctx, txn, execCfg.Codec, details.TableID)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() || desc.RowLevelTTL == nil {
+			return nil
+		}
+		spans, err = makeTTLSpans(ctx, txn, execCfg.Codec, desc)
+		return err
+	}); err != nil {
+		if errors.Is(err, sqlbase.ErrDescriptorNotFound) {
+			// The table was dropped since the job was created.
+			return nil
+		}
+		return err
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	ttl := desc.RowLevelTTL
+	sv := &execCfg.Settings.SV
+	selectBatchSize := ttl.SelectBatchSize
+	if selectBatchSize == 0 {
+		selectBatchSize = ttlDefaultSelectBatchSize.Get(sv)
+	}
+	deleteBatchSize := ttl.DeleteBatchSize
+	if deleteBatchSize == 0 {
+		deleteBatchSize = ttlDefaultDeleteBatchSize.Get(sv)
+	}
+	rangeConcurrency := ttl.RangeConcurrency
+	if rangeConcurrency == 0 {
+		rangeConcurrency = ttlDefaultRangeConcurrency.Get(sv)
+	}
+	deleteRateLimit := ttl.DeleteRateLimit
+	if deleteRateLimit == 0 {
+		deleteRateLimit = ttlDefaultDeleteRateLimit.Get(sv)
+	}
+
+	d, err := makeTTLDeleter(execCfg, desc, details.Cutoff)
+	if err != nil {
+		return err
+	}
+	d.selectBatchSize = int(selectBatchSize)
+	d.deleteBatchSize = int(deleteBatchSize)
+	if deleteRateLimit > 0 {
+		d.limiter = limit.NewLimiter(rate.Limit(deleteRateLimit))
+	}
+
+	if err := r.job.FractionProgressed(ctx,
+		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			prog := details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+			prog.SpansTotal = int64(len(spans))
+			return 0
+		},
+	); err != nil {
+		return err
+	}
+
+	spanCh := make(chan ttlSpan)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(spanCh)
+		for _, s := range spans {
+			select {
+			case spanCh <- s:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	for i := int64(0); i < rangeConcurrency; i++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for s := range spanCh {
+				deleted, err := d.deleteExpiredRows(ctx, s)
+				if err != nil {
+					return err
+				}
+				if err := r.spanCompleted(ctx, deleted, len(spans)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// spanCompleted updates the progress of the job once a span is processed.
+func (r *rowLevelTTLResumer) spanCompleted(ctx context.Context, deleted int64, total int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.rowsDeleted += deleted
+	r.mu.spansCompleted++
+	return r.job.FractionProgressed(ctx,
+		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			prog := details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+			prog.RowsDeleted = r.mu.rowsDeleted
+			prog.SpansCompleted = r.mu.spansCompleted
+			return float32(r.mu.spansCompleted) / float32(total)
+		},
+	)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnFailOrCancel(context.Context, interface{}) error { return nil }
+
+// makeTTLSpans splits the primary index of the table into spans along the
+// boundaries of its ranges, so that the spans can be processed concurrently.
+func makeTTLSpans(
+	ctx context.Context, txn *kv.Txn, codec keys.SQLCodec, desc *sqlbase.TableDescriptor,
+) ([]ttlSpan, error) {
+	indexSpan := desc.PrimaryIndexSpan(codec)
+	ranges, err := ScanMetaKVs(ctx, txn, indexSpan)
+	if err != nil {
+		return nil, err
+	}
+	var alloc sqlbase.DatumAlloc
+	var spans []ttlSpan
+	var start tree.Datums
+	for _, r := range ranges {
+		var rangeDesc roachpb.RangeDescriptor
+		if err := r.ValueProto(&rangeDesc); err != nil {
+			return nil, err
+		}
+		endKey := rangeDesc.EndKey.AsRawKey()
+		if endKey.Compare(indexSpan.EndKey) >= 0 {
+			break
+		}
+		end, err := decodeTTLKeyPrefix(codec, &alloc, desc, endKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(end) == 0 {
+			continue
+		}
+		spans = append(spans, ttlSpan{start: start, end: end})
+		start = end
+	}
+	return append(spans, ttlSpan{start: start}), nil
+}
+
+// decodeTTLKeyPrefix decodes the values of the primary key columns held by a
+// key of the primary index. Range boundaries are not necessarily row
+// boundaries, so only the columns fully held by the key are decoded.
+func decodeTTLKeyPrefix(
+	codec keys.SQLCodec, alloc *sqlbase.DatumAlloc, desc *sqlbase.TableDescriptor, key roachpb.Key,
+) (tree.Datums, error) {
+	indexID, rest, err := sqlbase.DecodeIndexKeyPrefix(codec, desc, key)
+	if err != nil || indexID != desc.PrimaryIndex.ID {
+		// The key is not in the primary index, which happens at its boundaries.
+		return nil, nil //nolint:returnerrcheck
+	}
+	idx := &desc.PrimaryIndex
+	var res tree.Datums
+	for i, colID := range idx.ColumnIDs {
+		if len(rest) == 0 {
+			break
+		}
+		col, err := desc.FindColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+		dir, err := idx.ColumnDirections[i].ToEncodingDirection()
+		if err != nil {
+			return nil, err
+		}
+		var d tree.Datum
+		d, rest, err = sqlbase.DecodeTableKey(alloc, col.Type, rest, dir)
+		if err != nil {
+			// The key ends within the encoding of the column.
+			break
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// ttlDeleter deletes the expired rows of a table.
+type ttlDeleter struct {
+	db      *kv.DB
+	ie      *InternalExecutor
+	metrics *RowLevelTTLMetrics
+
+	tableID   sqlbase.ID
+	pk        []ttlColumn
+	ttlColumn string
+	cutoff    tree.Datum
+
+	selectBatchSize int
+	deleteBatchSize int
+	// limiter limits the rate of deletions. It is nil if the rate is
+	// unlimited.
+	limiter *limit.LimiterBurstDisabled
+}
+
+func makeTTLDeleter(
+	execCfg *ExecutorConfig, desc *sqlbase.TableDescriptor, cutoff time.Time,
+) (*ttlDeleter, error) {
+	d := &ttlDeleter{
+		db:      execCfg.DB,
+		ie:      execCfg.InternalExecutor,
+		metrics: execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*RowLevelTTLMetrics),
+		tableID: desc.ID,
+	}
+	idx := &desc.PrimaryIndex
+	for i, colID := range idx.ColumnIDs {
+		col, err := desc.FindColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+		dir, err := idx.ColumnDirections[i].ToEncodingDirection()
+		if err != nil {
+			return nil, err
+		}
+		d.pk = append(d.pk, ttlColumn{name: col.Name, typ: col.Type, dir: dir})
+	}
+
+	col, err := desc.FindColumnByID(desc.RowLevelTTL.ColumnID)
+	if err != nil {
+		return nil, err
+	}
+	d.ttlColumn = col.Name
+	switch col.Type.Family() {
+	case types.TimestampTZFamily:
+		d.cutoff, err = tree.MakeDTimestampTZ(cutoff, time.Microsecond)
+	case types.TimestampFamily:
+		d.cutoff, err = tree.MakeDTimestamp(cutoff.UTC(), time.Microsecond)
+	case types.DateFamily:
+		d.cutoff, err = tree.NewDDateFromTime(cutoff.UTC())
+	default:
+		err = errors.AssertionFailedf("unexpected type %s of TTL column %q", col.Type.SQLString(), col.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// deleteExpiredRows deletes the expired rows of the span, and returns the
+// number of rows deleted. The rows are selected in batches, in the order of
+// the primary index, and deleted in smaller batches. The transactions have a
+// low priority so that they yield to the foreground traffic of the table.
+func (d *ttlDeleter) deleteExpiredRows(ctx context.Context, span ttlSpan) (int64, error) {
+	var deleted int64
+	lower, lowerInclusive := span.start, true
+	for {
+		stmt, args := d.selectStatement(lower, lowerInclusive, span.end)
+		var rows []tree.Datums
+		start := timeutil.Now()
+		if err := d.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetUserPriority(roachpb.MinUserPriority); err != nil {
+				return err
+			}
+			var err error
+			rows, err = d.ie.QueryEx(ctx, "ttl-select", txn,
+				sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+				stmt, args...,
+			)
+			return err
+		}); err != nil {
+			return deleted, err
+		}
+		d.metrics.SelectDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+		d.metrics.RowsSelected.Inc(int64(len(rows)))
+
+		for i := 0; i < len(rows); i += d.deleteBatchSize {
+			batch := rows[i:]
+			if len(batch) > d.deleteBatchSize {
+				batch = batch[:d.deleteBatchSize]
+			}
+			if d.limiter != nil {
+				if err := d.limiter.WaitN(ctx, len(batch)); err != nil {
+					return deleted, err
+				}
+			}
+			stmt, args := d.deleteStatement(batch)
+			var n int
+			start := timeutil.Now()
+			if err := d.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+				if err := txn.SetUserPriority(roachpb.MinUserPriority); err != nil {
+					return err
+				}
+				var err error
+				n, err = d.ie.ExecEx(ctx, "ttl-delete", txn,
+					sqlbase.InternalExecutorSessionDataOverride{User: security.RootUser},
+					stmt, args...,
+				)
+				return err
+			}); err != nil {
+				return deleted, err
+			}
+			d.metrics.DeleteDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+			d.metrics.RowsDeleted.Inc(int64(n))
+			deleted += int64(n)
+		}
+
+		if len(rows) < d.selectBatchSize {
+			return deleted, nil
+		}
+		lower, lowerInclusive = rows[len(rows)-1], false
+	}
+}
+
+// selectStatement returns the query selecting the next batch of expired
+// rows between the bounds, and its arguments.
+func (d *ttlDeleter) selectStatement(
+	lower tree.Datums, lowerInclusive bool, upper tree.Datums,
+) (string, []interface{}) {
+	var buf bytes.Buffer
+	args := []interface{}{d.cutoff}
+	buf.WriteString("SELECT ")
+	for i, c := range d.pk {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(c.name))
+	}
+	fmt.Fprintf(&buf, " FROM [%d AS t] WHERE %s < $1", d.tableID, tree.NameString(d.ttlColumn))
+	if len(lower) > 0 {
+		buf.WriteString(" AND (")
+		args = d.writeKeyPredicate(&buf, lower, true /* after */, lowerInclusive, args)
+		buf.WriteString(")")
+	}
+	if len(upper) > 0 {
+		buf.WriteString(" AND (")
+		args = d.writeKeyPredicate(&buf, upper, false /* after */, false /* inclusive */, args)
+		buf.WriteString(")")
+	}
+	buf.WriteString(" ORDER BY ")
+	for i, c := range d.pk {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(c.name))
+		if c.dir == encoding.Descending {
+			buf.WriteString(" DESC")
+		}
+	}
+	fmt.Fprintf(&buf, " LIMIT %d", d.selectBatchSize)
+	return buf.String(), args
+}
+
+// deleteStatement returns the statement deleting the rows with the given
+// primary keys, if they are still expired, and its arguments.
+func (d *ttlDeleter) deleteStatement(rows []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	args := []interface{}{d.cutoff}
+	fmt.Fprintf(&buf, "DELETE FROM [%d AS t] WHERE %s < $1 AND (", d.tableID, tree.NameString(d.ttlColumn))
+	for i, c := range d.pk {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(c.name))
+	}
+	buf.WriteString(") IN (")
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(")
+		for j, c := range d.pk {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			args = append(args, row[j])
+			fmt.Fprintf(&buf, "$%d::%s", len(args), c.typ.SQLString())
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(")")
+	return buf.String(), args
+}
+
+// writeKeyPredicate writes a predicate selecting the rows whose primary key
+// comes after (or before, if after is false) the key prefix in the order of
+// the primary index. The rows whose primary key starts with the prefix are
+// selected if inclusive is set. The values of the prefix are appended to
+// args, which is returned.
+func (d *ttlDeleter) writeKeyPredicate(
+	buf *bytes.Buffer, prefix tree.Datums, after, inclusive bool, args []interface{},
+) []interface{} {
+	placeholders := make([]string, len(prefix))
+	for i, v := range prefix {
+		args = append(args, v)
+		placeholders[i] = fmt.Sprintf("$%d::%s", len(args), d.pk[i].typ.SQLString())
+	}
+	n := len(prefix)
+	if inclusive {
+		n++
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(" OR ")
+		}
+		buf.WriteString("(")
+		for j := 0; j < i && j < len(prefix); j++ {
+			fmt.Fprintf(buf, "%s = %s AND ", tree.NameString(d.pk[j].name), placeholders[j])
+		}
+		if i == len(prefix) {
+			// The rows whose primary key starts with the prefix.
+			buf.WriteString("true)")
+			continue
+		}
+		op := "<"
+		if after == (d.pk[i].dir == encoding.Ascending) {
+			op = ">"
+		}
+		fmt.Fprintf(buf, "%s %s %s)", tree.NameString(d.pk[i].name), op, placeholders[i])
+	}
+	return args
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = makeRowLevelTTLMetrics
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{job: job}
+	})
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		func(ex sqlutil.InternalExecutor) (jobs.ScheduledJobExecutor, error) {
+			return &rowLevelTTLExecutor{ex: ex}, nil
+		})
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/tests"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowLevelTTLJob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	// The primary key has a descending column, and the table is split in the
+	// middle of rows sharing the same first column, to exercise the bounds of
+	// the spans processed concurrently.
+	sqlDB.Exec(t, `
+CREATE TABLE t (
+	a INT,
+	b STRING,
+	created TIMESTAMPTZ,
+	PRIMARY KEY (a, b DESC)
+) WITH (
+	ttl_expire_after = '1 hour',
+	ttl_column = 'created',
+	ttl_select_batch_size = 7,
+	ttl_delete_batch_size = 3,
+	ttl_range_concurrency = 2
+)`)
+	sqlDB.Exec(t, `ALTER TABLE t SPLIT AT VALUES (10, 'b'), (20), (30, 'a')`)
+	sqlDB.Exec(t, `
+INSERT INTO t
+SELECT a, b, CASE WHEN (a + ascii(b)) % 2 = 0 THEN now() - '2 hours'::INTERVAL ELSE now() END
+FROM generate_series(0, 39) AS a, unnest(ARRAY['a', 'b', 'c']) AS b`)
+
+	var expired, remaining int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM t WHERE created < now() - '1 hour'::INTERVAL`).Scan(&expired)
+	sqlDB.QueryRow(t, `SELECT count(*) FROM t WHERE created >= now() - '1 hour'::INTERVAL`).Scan(&remaining)
+
+	desc := sqlbase.GetTableDescriptor(kvDB, keys.SystemSQLCodec, "defaultdb", "t")
+	require.NotNil(t, desc.RowLevelTTL)
+	scheduleID := desc.RowLevelTTL.ScheduleID
+
+	// Run the schedule's executor directly rather than waiting for the job
+	// scheduler daemon.
+	ie := s.InternalExecutor().(*InternalExecutor)
+	require.NoError(t, kvDB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		sj, err := jobs.LoadScheduledJob(ctx, jobs.ProdJobSchedulerEnv, scheduleID, ie, txn)
+		if err != nil {
+			return err
+		}
+		return (&rowLevelTTLExecutor{ex: ie}).ExecuteJob(ctx, sj, txn)
+	}))
+
+	var jobID int64
+	sqlDB.QueryRow(t,
+		`SELECT id FROM system.jobs WHERE created_by_type = $1 AND created_by_id = $2`,
+		jobs.CreatedByScheduledJobs, scheduleID,
+	).Scan(&jobID)
+	registry := s.JobRegistry().(*jobs.Registry)
+	require.NoError(t, registry.Run(ctx, ie, []int64{jobID}))
+
+	var count int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM t`).Scan(&count)
+	require.Equal(t, remaining, count)
+	sqlDB.QueryRow(t, `SELECT count(*) FROM t WHERE created < now() - '1 hour'::INTERVAL`).Scan(&count)
+	require.Equal(t, 0, count)
+
+	job, err := registry.LoadJob(ctx, jobID)
+	require.NoError(t, err)
+	progress := job.Progress()
+	require.Equal(t, int64(expired), progress.GetRowLevelTTL().RowsDeleted)
+	require.Equal(t, progress.GetRowLevelTTL().SpansTotal, progress.GetRowLevelTTL().SpansCompleted)
+	require.Equal(t, float32(1), progress.GetFractionCompleted())
+
+	metrics := registry.MetricsStruct().RowLevelTTL.(*RowLevelTTLMetrics)
+	require.Equal(t, int64(expired), metrics.RowsSelected.Count())
+	require.Equal(t, int64(expired), metrics.RowsDeleted.Count())
+}
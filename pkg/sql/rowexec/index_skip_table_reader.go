@@ -123,12 +123,14 @@ func newIndexSkipTableReader(
 		IsSecondaryIndex: isSecondaryIndex,
 		Cols:             cols,
 		ValNeededForCol:  neededColumns,
+		EvalCtx:          flowCtx.EvalCtx,
 	}
 
 	if err := t.fetcher.Init(
 		flowCtx.Codec(),
 		t.reverse,
 		spec.LockingStrength,
+		spec.LockingWaitPolicy,
 		true,  /* returnRangeInfo */
 		false, /* isCheck */
 		&t.alloc,
@@ -65,6 +65,7 @@ func initRowFetcher(
 	alloc *sqlbase.DatumAlloc,
 	scanVisibility execinfrapb.ScanVisibility,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 ) (index *sqlbase.IndexDescriptor, isSecondaryIndex bool, err error) {
 	immutDesc := sqlbase.NewImmutableTableDescriptor(*desc)
 	index, isSecondaryIndex, err = immutDesc.FindIndexByIndexIdx(indexIdx)
@@ -83,11 +84,13 @@ func initRowFetcher(
 		IsSecondaryIndex: isSecondaryIndex,
 		Cols:             cols,
 		ValNeededForCol:  valNeededForCol,
+		EvalCtx:          flowCtx.EvalCtx,
 	}
 	if err := fetcher.Init(
 		flowCtx.Codec(),
 		reverseScan,
 		lockStr,
+		lockWaitPolicy,
 		true, /* returnRangeInfo */
 		isCheck,
 		alloc,
@@ -211,15 +211,37 @@ func (sc *SchemaChanger) maybeBackfillCreateTableAs(
 	}
 	log.Info(ctx, "starting backfill for CREATE TABLE AS")
 
+	return sc.backfillQueryIntoTable(ctx, table, table.CreateQuery, table.CreateAsOfTime, "ctasBackfill")
+}
+
+// maybe backfill a created materialized view by executing the view query.
+// Return nil if successfully backfilled.
+func (sc *SchemaChanger) maybeBackfillMaterializedView(
+	ctx context.Context, table *sqlbase.TableDescriptor,
+) error {
+	if !(table.Adding() && table.MaterializedView()) {
+		return nil
+	}
+	log.Info(ctx, "starting backfill for CREATE MATERIALIZED VIEW")
+
+	return sc.backfillQueryIntoTable(ctx, table, table.ViewQuery, table.CreateAsOfTime, "materializedViewBackfill")
+}
+
+// backfillQueryIntoTable executes query as of the timestamp ts and writes
+// its results into all of the indexes of table. opName is used to name the
+// internal planner that runs the query.
+func (sc *SchemaChanger) backfillQueryIntoTable(
+	ctx context.Context, table *sqlbase.TableDescriptor, query string, ts hlc.Timestamp, opName string,
+) error {
 	return sc.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		txn.SetFixedTimestamp(ctx, table.CreateAsOfTime)
+		txn.SetFixedTimestamp(ctx, ts)
 
 		// Create an internal planner as the planner used to serve the user query
 		// would have committed by this point.
-		p, cleanup := NewInternalPlanner("ctasBackfill", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg)
+		p, cleanup := NewInternalPlanner(opName, txn, security.RootUser, &MemoryMetrics{}, sc.execCfg)
 		defer cleanup()
 		localPlanner := p.(*planner)
-		stmt, err := parser.ParseOne(table.CreateQuery)
+		stmt, err := parser.ParseOne(query)
 		if err != nil {
 			return err
 		}
@@ -476,6 +498,10 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 		return err
 	}
 
+	if err := sc.maybeBackfillMaterializedView(ctx, tableDesc); err != nil {
+		return err
+	}
+
 	if err := sc.maybeMakeAddTablePublic(ctx, tableDesc); err != nil {
 		return err
 	}
@@ -504,7 +530,8 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 	if sc.mutationID == sqlbase.InvalidMutationID {
 		// Nothing more to do.
 		isCreateTableAs := tableDesc.Adding() && tableDesc.IsAs()
-		return waitToUpdateLeases(isCreateTableAs /* refreshStats */)
+		isMaterializedView := tableDesc.Adding() && tableDesc.MaterializedView()
+		return waitToUpdateLeases(isCreateTableAs || isMaterializedView /* refreshStats */)
 	}
 
 	if err := sc.initJobRunningStatus(ctx); err != nil {
@@ -839,27 +866,45 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 							IndexID: indexDesc.ID,
 						})
 
-					dropTime := timeutil.Now().UnixNano()
-					indexGCDetails := jobspb.SchemaChangeGCDetails{
-						Indexes: []jobspb.SchemaChangeGCDetails_DroppedIndex{
-							{
-								IndexID:  indexDesc.ID,
-								DropTime: dropTime,
-							},
-						},
-						ParentID: sc.tableID,
-					}
-
 					description := sc.job.Payload().Description
 					if isRollback {
 						description = "ROLLBACK of " + description
 					}
-					gcJobRecord := CreateGCJobRecord(description, sc.job.Payload().Username, indexGCDetails)
-					indexGCJob, err := sc.jobRegistry.CreateStartableJobWithTxn(ctx, gcJobRecord, txn, nil /* resultsCh */)
+					indexGCJob, err := sc.createIndexGCJob(ctx, indexDesc.ID, txn, description)
+					if err != nil {
+						return err
+					}
+					childJobs = append(childJobs, indexGCJob)
+				}
+			}
+
+			// If we are refreshing a materialized view, then create GC jobs for
+			// the set of indexes that is no longer needed. This happens before the
+			// call to MakeMutationComplete, which swaps the backfilled indexes in
+			// for the existing ones.
+			if refresh := mutation.GetMaterializedViewRefresh(); refresh != nil {
+				var toGC []sqlbase.IndexID
+				description := sc.job.Payload().Description
+				if mutation.Direction == sqlbase.DescriptorMutation_ADD {
+					// The refresh succeeded, so the existing indexes are garbage.
+					toGC = append(toGC, scDesc.PrimaryIndex.ID)
+					for i := range scDesc.Indexes {
+						toGC = append(toGC, scDesc.Indexes[i].ID)
+					}
+				} else {
+					// The refresh is being rolled back, so the indexes that were
+					// (partially) backfilled are garbage.
+					description = "ROLLBACK of " + description
+					toGC = append(toGC, refresh.NewPrimaryIndex.ID)
+					for i := range refresh.NewIndexes {
+						toGC = append(toGC, refresh.NewIndexes[i].ID)
+					}
+				}
+				for _, id := range toGC {
+					indexGCJob, err := sc.createIndexGCJob(ctx, id, txn, description)
 					if err != nil {
 						return err
 					}
-					log.VEventf(ctx, 2, "created index GC job %d", *indexGCJob.ID())
 					childJobs = append(childJobs, indexGCJob)
 				}
 			}
@@ -1011,6 +1056,31 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 	return descs[sc.tableID], nil
 }
 
+// createIndexGCJob creates, but does not start, a GC job for the index with
+// the given ID on the table being changed.
+func (sc *SchemaChanger) createIndexGCJob(
+	ctx context.Context, indexID sqlbase.IndexID, txn *kv.Txn, jobDesc string,
+) (*jobs.StartableJob, error) {
+	dropTime := timeutil.Now().UnixNano()
+	indexGCDetails := jobspb.SchemaChangeGCDetails{
+		Indexes: []jobspb.SchemaChangeGCDetails_DroppedIndex{
+			{
+				IndexID:  indexID,
+				DropTime: dropTime,
+			},
+		},
+		ParentID: sc.tableID,
+	}
+
+	gcJobRecord := CreateGCJobRecord(jobDesc, sc.job.Payload().Username, indexGCDetails)
+	indexGCJob, err := sc.jobRegistry.CreateStartableJobWithTxn(ctx, gcJobRecord, txn, nil /* resultsCh */)
+	if err != nil {
+		return nil, err
+	}
+	log.VEventf(ctx, 2, "created index GC job %d", *indexGCJob.ID())
+	return indexGCJob, nil
+}
+
 // maybeUpdateZoneConfigsForPKChange moves zone configs for any rewritten
 // indexes from the old index over to the new index.
 func (sc *SchemaChanger) maybeUpdateZoneConfigsForPKChange(
@@ -1440,9 +1510,12 @@ func (sc *SchemaChanger) reverseMutation(
 		if col := mutation.GetColumn(); col != nil {
 			columns[col.Name] = struct{}{}
 		}
-		// PrimaryKeySwap and ComputedColumnSwap don't have a concept of the state machine.
-		if pkSwap, computedColumnsSwap :=
-			mutation.GetPrimaryKeySwap(), mutation.GetComputedColumnSwap(); pkSwap != nil || computedColumnsSwap != nil {
+		// PrimaryKeySwap, ComputedColumnSwap and MaterializedViewRefresh don't
+		// have a concept of the state machine.
+		if pkSwap, computedColumnsSwap, refresh :=
+			mutation.GetPrimaryKeySwap(),
+			mutation.GetComputedColumnSwap(),
+			mutation.GetMaterializedViewRefresh(); pkSwap != nil || computedColumnsSwap != nil || refresh != nil {
 			return mutation, columns
 		}
 
@@ -107,7 +107,11 @@ func FormatColumnForDisplay(
 	} else {
 		f.WriteString(" NOT NULL")
 	}
-	if desc.DefaultExpr != nil {
+	if desc.IsGeneratedAlwaysAsIdentity() {
+		f.WriteString(" GENERATED ALWAYS AS IDENTITY")
+	} else if desc.IsGeneratedAsIdentity() {
+		f.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	} else if desc.DefaultExpr != nil {
 		f.WriteString(" DEFAULT ")
 		typed, err := DeserializeTableDescExpr(ctx, semaCtx, tbl, *desc.DefaultExpr)
 		if err != nil {
@@ -122,7 +126,11 @@ func FormatColumnForDisplay(
 			return "", err
 		}
 		f.WriteString(tree.SerializeForDisplay(typed))
-		f.WriteString(") STORED")
+		if desc.IsVirtual() {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString(), nil
 }
@@ -19,6 +19,10 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomvt"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -55,6 +59,10 @@ func initAggregateBuiltins() {
 					"found %v", k, a))
 			}
 		}
+		if n, ok := aggregateNonAggregates[k]; ok && n.props.Class != tree.NormalClass {
+			panic(fmt.Sprintf("%s: non-aggregate overloads should be marked with the "+
+				"tree.NormalClass function class, found %v", k, n))
+		}
 
 		builtins[k] = v
 	}
@@ -344,6 +352,39 @@ var aggregates = map[string]builtinDefinition{
 			"Aggregates values as a JSON or JSONB object.", tree.VolatilityStable),
 	),
 
+	"st_asmvt": makeBuiltin(aggProps(),
+		makeAggOverload([]*types.T{types.AnyTuple}, types.Bytes, newSTAsMVTAggregate,
+			stAsMVTInfo, tree.VolatilityStable),
+		makeAggOverload([]*types.T{types.AnyTuple, types.String}, types.Bytes, newSTAsMVTAggregate,
+			stAsMVTInfo, tree.VolatilityStable),
+		makeAggOverload([]*types.T{types.AnyTuple, types.String, types.Int}, types.Bytes,
+			newSTAsMVTAggregate, stAsMVTInfo, tree.VolatilityStable),
+		makeAggOverload([]*types.T{types.AnyTuple, types.String, types.Int, types.String}, types.Bytes,
+			newSTAsMVTAggregate, stAsMVTInfo, tree.VolatilityStable),
+		makeAggOverload([]*types.T{types.AnyTuple, types.String, types.Int, types.String, types.String},
+			types.Bytes, newSTAsMVTAggregate, stAsMVTInfo, tree.VolatilityStable),
+	),
+
+	"st_extent": makeBuiltin(aggProps(),
+		makeAggOverload([]*types.T{types.Geometry}, types.Geometry, newSTExtentAggregate,
+			"Returns the bounding box of the selected geometries.", tree.VolatilityImmutable),
+	),
+	"st_collect": makeBuiltin(aggProps(),
+		makeAggOverload([]*types.T{types.Geometry}, types.Geometry, newSTCollectAggregate,
+			"Collects the selected geometries into a single multi-geometry or GeometryCollection.",
+			tree.VolatilityImmutable),
+	),
+	"st_makeline": makeBuiltin(aggProps(),
+		makeAggOverload([]*types.T{types.Geometry}, types.Geometry, newSTMakeLineAggregate,
+			"Makes a LineString from the points of the selected Point, MultiPoint or LineString geometries.",
+			tree.VolatilityImmutable),
+	),
+	"st_union": makeBuiltin(aggProps(),
+		makeAggOverload([]*types.T{types.Geometry}, types.Geometry, newSTUnionAggregate,
+			"Returns the union of the selected geometries as a single Geometry object.",
+			tree.VolatilityImmutable),
+	),
+
 	AnyNotNull: makePrivate(makeBuiltin(aggProps(),
 		makeAggOverloadWithReturnType(
 			[]*types.T{types.Any},
@@ -440,9 +481,37 @@ var aggregates = map[string]builtinDefinition{
 	)),
 }
 
+// aggregateNonAggregates holds the normal overloads of aggregates which share
+// their name with a normal function. An application of such an aggregate is
+// resolved to the normal overloads when none of the aggregate overloads
+// accepts its number of arguments.
+var aggregateNonAggregates = map[string]builtinDefinition{
+	"st_union": makeBuiltin(defProps(),
+		geometryOverload2(
+			func(ctx *tree.EvalContext, a *tree.DGeometry, b *tree.DGeometry) (tree.Datum, error) {
+				union, err := geomfn.Union(a.Geometry, b.Geometry)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeometry(union), err
+			},
+			types.Geometry,
+			infoBuilder{
+				info:         "Returns the union of the given geometries as a single Geometry object.",
+				libraryUsage: usesGEOS,
+			},
+			tree.VolatilityImmutable,
+		),
+	),
+}
+
 // AnyNotNull is the name of the aggregate returned by NewAnyNotNullAggregate.
 const AnyNotNull = "any_not_null"
 
+const stAsMVTInfo = `Aggregates rows into a Mapbox Vector Tile containing a single layer.
+
+The arguments after the row are the name of the layer (default "default"), the extent of the tile in tile coordinate space (default 4096), the name of the geometry column of the row (by default, its first geometry column) and the name of an integer column of the row to use as the ID of each feature (by default, features have no ID). The geometries must already be in tile coordinate space, as returned by ST_AsMVTGeom. The remaining columns of the row become the attributes of each feature.`
+
 func makePrivate(b builtinDefinition) builtinDefinition {
 	b.props.Private = true
 	return b
@@ -584,6 +653,10 @@ var _ tree.AggregateFunc = &intBitOrAggregate{}
 var _ tree.AggregateFunc = &bitBitOrAggregate{}
 var _ tree.AggregateFunc = &percentileDiscAggregate{}
 var _ tree.AggregateFunc = &percentileContAggregate{}
+var _ tree.AggregateFunc = &stAsMVTAggregate{}
+var _ tree.AggregateFunc = &stExtentAggregate{}
+var _ tree.AggregateFunc = &geometryListAggregate{}
+var _ tree.AggregateFunc = &stUnionAggregate{}
 
 const sizeOfArrayAggregate = int64(unsafe.Sizeof(arrayAggregate{}))
 const sizeOfAvgAggregate = int64(unsafe.Sizeof(avgAggregate{}))
@@ -620,6 +693,10 @@ const sizeOfIntBitOrAggregate = int64(unsafe.Sizeof(intBitOrAggregate{}))
 const sizeOfBitBitOrAggregate = int64(unsafe.Sizeof(bitBitOrAggregate{}))
 const sizeOfPercentileDiscAggregate = int64(unsafe.Sizeof(percentileDiscAggregate{}))
 const sizeOfPercentileContAggregate = int64(unsafe.Sizeof(percentileContAggregate{}))
+const sizeOfSTAsMVTAggregate = int64(unsafe.Sizeof(stAsMVTAggregate{}))
+const sizeOfSTExtentAggregate = int64(unsafe.Sizeof(stExtentAggregate{}))
+const sizeOfGeometryListAggregate = int64(unsafe.Sizeof(geometryListAggregate{}))
+const sizeOfSTUnionAggregate = int64(unsafe.Sizeof(stUnionAggregate{}))
 
 // singleDatumAggregateBase is a utility struct that helps aggregate builtins
 // that store a single datum internally track their memory usage related to
@@ -3103,3 +3180,380 @@ func (a *jsonObjectAggregate) Close(ctx context.Context) {
 func (a *jsonObjectAggregate) Size() int64 {
 	return sizeOfJSONObjectAggregate
 }
+
+// stAsMVTAggregate aggregates rows into a vector tile. See stAsMVTInfo.
+type stAsMVTAggregate struct {
+	singleDatumAggregateBase
+
+	// constArgs are the constant arguments that follow the row, if any.
+	constArgs tree.Datums
+	// layer is initialized when the first row is added.
+	layer *geomvt.LayerBuilder
+	// geomIdx and idIdx are the positions of the geometry and feature ID
+	// columns in the row. idIdx is -1 if features have no ID.
+	geomIdx, idIdx int
+	labels         []string
+	attrs          []geomvt.Attribute
+}
+
+func newSTAsMVTAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return &stAsMVTAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		constArgs:                arguments,
+	}
+}
+
+// Add adds the row as a feature of the tile. NULL rows are skipped.
+func (a *stAsMVTAggregate) Add(ctx context.Context, datum tree.Datum, others ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	row, ok := datum.(*tree.DTuple)
+	if !ok {
+		return errors.AssertionFailedf("expected a tuple, found %T", datum)
+	}
+	if a.layer == nil {
+		// The arguments following the row are either passed in others, if they
+		// are not constant, or were passed when the aggregate was created.
+		params := append(append(tree.Datums(nil), others...), a.constArgs...)
+		if err := a.init(row, params); err != nil {
+			return err
+		}
+	}
+
+	geomDatum := row.D[a.geomIdx]
+	if geomDatum == tree.DNull {
+		return nil
+	}
+	var id uint64
+	if a.idIdx >= 0 && row.D[a.idIdx] != tree.DNull {
+		if v := int64(tree.MustBeDInt(row.D[a.idIdx])); v > 0 {
+			id = uint64(v)
+		}
+	}
+	a.attrs = a.attrs[:0]
+	for i, d := range row.D {
+		if i == a.geomIdx || i == a.idIdx || d == tree.DNull {
+			continue
+		}
+		a.attrs = append(a.attrs, geomvt.Attribute{Key: a.labels[i], Value: mvtValueFromDatum(d)})
+	}
+	if err := a.layer.AddFeature(tree.MustBeDGeometry(geomDatum).Geometry, id, a.attrs); err != nil {
+		return err
+	}
+	return a.updateMemoryUsage(ctx, int64(a.layer.Size()))
+}
+
+// init resolves the layer parameters and the positions of the geometry and
+// feature ID columns of the rows from the first row.
+func (a *stAsMVTAggregate) init(row *tree.DTuple, params tree.Datums) error {
+	name, extent := "default", int64(geomvt.DefaultExtent)
+	var geomName, idName string
+	if len(params) > 0 && params[0] != tree.DNull {
+		name = string(tree.MustBeDString(params[0]))
+	}
+	if len(params) > 1 && params[1] != tree.DNull {
+		extent = int64(tree.MustBeDInt(params[1]))
+	}
+	if len(params) > 2 && params[2] != tree.DNull {
+		geomName = string(tree.MustBeDString(params[2]))
+	}
+	if len(params) > 3 && params[3] != tree.DNull {
+		idName = string(tree.MustBeDString(params[3]))
+	}
+	if extent <= 0 || extent > math.MaxUint32 {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "extent must be between 1 and %d", uint32(math.MaxUint32))
+	}
+
+	typ := row.ResolvedType()
+	contents := typ.TupleContents()
+	a.labels = make([]string, len(contents))
+	for i := range contents {
+		if labels := typ.TupleLabels(); i < len(labels) && labels[i] != "" {
+			a.labels[i] = labels[i]
+		} else {
+			a.labels[i] = fmt.Sprintf("f%d", i+1)
+		}
+	}
+
+	a.geomIdx = -1
+	for i := range contents {
+		if contents[i].Family() == types.GeometryFamily && (geomName == "" || geomName == a.labels[i]) {
+			a.geomIdx = i
+			break
+		}
+	}
+	if a.geomIdx == -1 {
+		if geomName == "" {
+			return pgerror.New(pgcode.InvalidParameterValue, "row does not have a column of type geometry")
+		}
+		return pgerror.Newf(pgcode.InvalidParameterValue, "could not find column %q of type geometry", geomName)
+	}
+
+	a.idIdx = -1
+	if idName != "" {
+		for i := range contents {
+			if a.labels[i] == idName {
+				a.idIdx = i
+				break
+			}
+		}
+		if a.idIdx == -1 || contents[a.idIdx].Family() != types.IntFamily {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "could not find column %q of type int", idName)
+		}
+	}
+
+	a.layer = geomvt.NewLayerBuilder(name, uint32(extent))
+	return nil
+}
+
+// mvtValueFromDatum returns the vector tile attribute value of the given
+// datum, which must not be NULL. Datums which do not have a corresponding
+// vector tile type are encoded as strings.
+func mvtValueFromDatum(d tree.Datum) geomvt.Tile_Value {
+	switch t := tree.UnwrapDatum(nil /* evalCtx */, d).(type) {
+	case *tree.DBool:
+		return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_BoolValue{BoolValue: bool(*t)}}
+	case *tree.DInt:
+		if *t < 0 {
+			return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_SintValue{SintValue: int64(*t)}}
+		}
+		return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_UintValue{UintValue: uint64(*t)}}
+	case *tree.DFloat:
+		if d.ResolvedType().Width() == 32 {
+			return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_FloatValue{FloatValue: float32(*t)}}
+		}
+		return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_DoubleValue{DoubleValue: float64(*t)}}
+	case *tree.DString:
+		return geomvt.Tile_Value{Kind: &geomvt.Tile_Value_StringValue{StringValue: string(*t)}}
+	default:
+		return geomvt.Tile_Value{
+			Kind: &geomvt.Tile_Value_StringValue{StringValue: tree.AsStringWithFlags(d, tree.FmtBareStrings)},
+		}
+	}
+}
+
+// Result returns the encoded tile, or an empty tile if no rows were added.
+func (a *stAsMVTAggregate) Result() (tree.Datum, error) {
+	if a.layer == nil {
+		return tree.NewDBytes(""), nil
+	}
+	encoded, err := a.layer.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDBytes(tree.DBytes(encoded)), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *stAsMVTAggregate) Reset(ctx context.Context) {
+	a.layer = nil
+	a.reset(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *stAsMVTAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *stAsMVTAggregate) Size() int64 {
+	return sizeOfSTAsMVTAggregate
+}
+
+// stExtentAggregate computes the bounding box of geometries.
+type stExtentAggregate struct {
+	bbox *geopb.BoundingBox
+	// first is the first non-empty geometry added, whose SRID the other
+	// geometries must match.
+	first *geo.Geometry
+}
+
+func newSTExtentAggregate([]*types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc {
+	return &stExtentAggregate{}
+}
+
+// Add extends the bounding box to cover the geometry.
+func (a *stExtentAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	g := tree.MustBeDGeometry(datum).Geometry
+	bbox := g.SpatialObject().BoundingBox
+	if bbox == nil {
+		return nil
+	}
+	if a.bbox == nil {
+		a.bbox = geopb.NewBoundingBox()
+		a.first = g
+	} else if g.SRID() != a.first.SRID() {
+		return geo.NewMismatchingSRIDsError(a.first, g)
+	}
+	a.bbox.Update(bbox.MinX, bbox.MinY)
+	a.bbox.Update(bbox.MaxX, bbox.MaxY)
+	return nil
+}
+
+// Result returns the bounding box as a geometry, or NULL if no non-empty
+// geometries were added.
+func (a *stExtentAggregate) Result() (tree.Datum, error) {
+	if a.bbox == nil {
+		return tree.DNull, nil
+	}
+	g, err := geomfn.BoundingBoxGeometry(a.bbox, a.first.SRID())
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDGeometry(g), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *stExtentAggregate) Reset(context.Context) {
+	a.bbox = nil
+	a.first = nil
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *stExtentAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *stExtentAggregate) Size() int64 {
+	return sizeOfSTExtentAggregate
+}
+
+// geometryListAggregate accumulates geometries, and combines them into a
+// single geometry once all of them have been added.
+type geometryListAggregate struct {
+	singleDatumAggregateBase
+
+	geoms []*geo.Geometry
+	// combine combines the geometries into the result. It may return nil, in
+	// which case the result is NULL.
+	combine func([]*geo.Geometry) (*geo.Geometry, error)
+	// size is the memory usage of the accumulated geometries.
+	size int64
+}
+
+func newSTCollectAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &geometryListAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		combine:                  geomfn.Collect,
+	}
+}
+
+func newSTMakeLineAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &geometryListAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		combine:                  geomfn.MakeLine,
+	}
+}
+
+// Add accumulates the geometry.
+func (a *geometryListAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.geoms = append(a.geoms, tree.MustBeDGeometry(datum).Geometry)
+	a.size += int64(datum.Size())
+	return a.updateMemoryUsage(ctx, a.size+int64(cap(a.geoms))*int64(unsafe.Sizeof(&geo.Geometry{})))
+}
+
+// Result combines the accumulated geometries, or returns NULL if none were
+// added.
+func (a *geometryListAggregate) Result() (tree.Datum, error) {
+	if len(a.geoms) == 0 {
+		return tree.DNull, nil
+	}
+	g, err := a.combine(a.geoms)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return tree.DNull, nil
+	}
+	return tree.NewDGeometry(g), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *geometryListAggregate) Reset(ctx context.Context) {
+	a.geoms = nil
+	a.size = 0
+	a.reset(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *geometryListAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *geometryListAggregate) Size() int64 {
+	return sizeOfGeometryListAggregate
+}
+
+// stUnionAggregate computes the union of geometries. The union is computed
+// as each geometry is added, so only the union so far is kept in memory.
+type stUnionAggregate struct {
+	singleDatumAggregateBase
+
+	union *geo.Geometry
+}
+
+func newSTUnionAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &stUnionAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+	}
+}
+
+// Add computes the union of the geometry with the union so far.
+func (a *stUnionAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	g := tree.MustBeDGeometry(datum).Geometry
+	if a.union == nil {
+		a.union = g
+	} else {
+		union, err := geomfn.Union(a.union, g)
+		if err != nil {
+			return err
+		}
+		a.union = union
+	}
+	return a.updateMemoryUsage(ctx, int64(len(a.union.EWKB())))
+}
+
+// Result returns the union, or NULL if no geometries were added.
+func (a *stUnionAggregate) Result() (tree.Datum, error) {
+	if a.union == nil {
+		return tree.DNull, nil
+	}
+	return tree.NewDGeometry(a.union), nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *stUnionAggregate) Reset(ctx context.Context) {
+	a.union = nil
+	a.reset(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *stUnionAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *stUnionAggregate) Size() int64 {
+	return sizeOfSTUnionAggregate
+}
@@ -37,12 +37,16 @@ func init() {
 	initGeoBuiltins()
 	initPGBuiltins()
 	initMathBuiltins()
+	initTSearchBuiltins()
 
 	AllBuiltinNames = make([]string, 0, len(builtins))
 	AllAggregateBuiltinNames = make([]string, 0, len(aggregates))
 	tree.FunDefs = make(map[string]*tree.FunctionDefinition)
 	for name, def := range builtins {
 		fDef := tree.NewFunctionDefinition(name, &def.props, def.overloads)
+		if n, ok := aggregateNonAggregates[name]; ok {
+			fDef.NonAggregate = tree.NewFunctionDefinition(name, &n.props, n.overloads)
+		}
 		tree.FunDefs[name] = fDef
 		if !fDef.ShouldDocument() {
 			// Avoid listing help for undocumented functions.
@@ -83,6 +83,7 @@ const (
 	categoryCompatibility = "Compatibility"
 	categoryDateAndTime   = "Date and time"
 	categoryEnum          = "Enum"
+	categoryTextSearch    = "Full Text Search"
 	categoryGenerator     = "Set-returning"
 	categoryGeospatial    = "Geospatial"
 	categoryIDGeneration  = "ID generation"
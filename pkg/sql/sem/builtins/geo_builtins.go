@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/geo"
 	"github.com/cockroachdb/cockroach/pkg/geo/geogfn"
 	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomvt"
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/geo/geoprojbase"
 	"github.com/cockroachdb/cockroach/pkg/geo/geotransform"
@@ -1044,6 +1045,13 @@ Options is a flag that can be bitmasked. The options are:
 			Volatility: tree.VolatilityImmutable,
 		},
 	),
+	"st_asmvtgeom": makeBuiltin(
+		defProps(),
+		asMVTGeomOverload(2),
+		asMVTGeomOverload(3),
+		asMVTGeomOverload(4),
+		asMVTGeomOverload(5),
+	),
 	"st_project": makeBuiltin(
 		defProps(),
 		tree.Overload{
@@ -2279,24 +2287,6 @@ Note if geometries are the same, it will return the LineString with the minimum
 			tree.VolatilityImmutable,
 		),
 	),
-	"st_union": makeBuiltin(
-		defProps(),
-		geometryOverload2(
-			func(ctx *tree.EvalContext, a *tree.DGeometry, b *tree.DGeometry) (tree.Datum, error) {
-				union, err := geomfn.Union(a.Geometry, b.Geometry)
-				if err != nil {
-					return nil, err
-				}
-				return tree.NewDGeometry(union), err
-			},
-			types.Geometry,
-			infoBuilder{
-				info:         "Returns the union of the given geometries as a single Geometry object.",
-				libraryUsage: usesGEOS,
-			},
-			tree.VolatilityImmutable,
-		),
-	),
 
 	//
 	// Transformations
@@ -3304,6 +3294,51 @@ func makeTableName(catalogName string, schemaName string, tableName string) tree
 	return tree.MakeUnresolvedName(tableName)
 }
 
+// asMVTGeomOverload returns an st_asmvtgeom overload which takes the first
+// numArgs of its arguments. The remaining arguments take their default values.
+func asMVTGeomOverload(numArgs int) tree.Overload {
+	argTypes := tree.ArgTypes{
+		{"geometry", types.Geometry},
+		{"bounds", types.Geometry},
+		{"extent", types.Int},
+		{"buffer", types.Int},
+		{"clip_geom", types.Bool},
+	}
+	return tree.Overload{
+		Types:      argTypes[:numArgs],
+		ReturnType: tree.FixedReturnType(types.Geometry),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			g := args[0].(*tree.DGeometry)
+			bounds := args[1].(*tree.DGeometry)
+			extent, buffer, clip := geomvt.DefaultExtent, geomvt.DefaultBuffer, true
+			if len(args) > 2 {
+				extent = int(tree.MustBeDInt(args[2]))
+			}
+			if len(args) > 3 {
+				buffer = int(tree.MustBeDInt(args[3]))
+			}
+			if len(args) > 4 {
+				clip = bool(tree.MustBeDBool(args[4]))
+			}
+			ret, err := geomfn.AsMVTGeometry(g.Geometry, bounds.Geometry, extent, buffer, clip)
+			if err != nil {
+				return nil, err
+			}
+			if ret == nil {
+				return tree.DNull, nil
+			}
+			return tree.NewDGeometry(ret), nil
+		},
+		Info: infoBuilder{
+			info: `Transforms a Geometry into the coordinate space of a Mapbox Vector Tile, for use with ST_AsMVT.
+
+The tile covers the bounding box of bounds, and has a width and height of extent (default 4096) in tile coordinate space, with the Y axis pointing down. If clip_geom is true (the default), the geometry is clipped to the tile extended by buffer (default 256) on each side. Coordinates are snapped to integers, and parts of the geometry which collapse as a result are removed. Returns NULL if nothing remains of the geometry.`,
+			libraryUsage: usesGEOS,
+		}.String(),
+		Volatility: tree.VolatilityImmutable,
+	}
+}
+
 func lineInterpolatePointForRepeatOverload(repeat bool, builtinInfo string) tree.Overload {
 	return tree.Overload{
 		Types: tree.ArgTypes{
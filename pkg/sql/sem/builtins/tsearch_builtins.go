@@ -0,0 +1,308 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+func initTSearchBuiltins() {
+	for k, v := range tsearchBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		v.props.Category = categoryTextSearch
+		builtins[k] = v
+	}
+}
+
+// tsearchBuiltins contains the text search built-in functions. The overloads
+// without a configuration argument use the default configuration; as in
+// PostgreSQL, they are stable rather than immutable.
+var tsearchBuiltins = map[string]builtinDefinition{
+	"to_tsvector": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(tsearch.DefaultConfigName)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(c.ToTSVector(string(tree.MustBeDString(args[0])))), nil
+			},
+			Info:       "Converts `document` into a tsvector with the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(c.ToTSVector(string(tree.MustBeDString(args[1])))), nil
+			},
+			Info:       "Converts `document` into a tsvector with the text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"to_tsquery": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSQuery(tsearch.DefaultConfigName, string(tree.MustBeDString(args[0])))
+			},
+			Info: "Converts `query` into a tsquery, normalizing its lexemes with the " +
+				"default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSQuery(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: "Converts `query` into a tsquery, normalizing its lexemes with the " +
+				"text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"plainto_tsquery": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(tsearch.DefaultConfigName)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(c.PlainToTSQuery(string(tree.MustBeDString(args[0])))), nil
+			},
+			Info: "Converts the plain text `query` into a tsquery matching all of its words, " +
+				"with the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(c.PlainToTSQuery(string(tree.MustBeDString(args[1])))), nil
+			},
+			Info: "Converts the plain text `query` into a tsquery matching all of its words, " +
+				"with the text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"ts_rank": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultRankWeights, args[0], args[1], 0)
+			},
+			Info:       "Ranks `vector` for `query` based on the frequency of its matching lexemes.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultRankWeights, args[0], args[1], int(tree.MustBeDInt(args[2])))
+			},
+			Info: "Ranks `vector` for `query` based on the frequency of its matching lexemes. " +
+				"`normalization` is a bit mask of the ways the rank is normalized by the " +
+				"length of the document.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.MakeArray(types.Float4)},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				w, err := rankWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(w, args[1], args[2], 0)
+			},
+			Info: "Ranks `vector` for `query` based on the frequency of its matching lexemes, " +
+				"with `weights` as the weights of the D, C, B and A lexeme weights.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.MakeArray(types.Float4)},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				w, err := rankWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(w, args[1], args[2], int(tree.MustBeDInt(args[3])))
+			},
+			Info: "Ranks `vector` for `query` based on the frequency of its matching lexemes, " +
+				"with `weights` as the weights of the D, C, B and A lexeme weights. " +
+				"`normalization` is a bit mask of the ways the rank is normalized by the " +
+				"length of the document.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"ts_headline": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(tsearch.DefaultConfigName, args[0], args[1], "")
+			},
+			Info: "Returns an excerpt of `document` with the words matching `query` " +
+				"highlighted, with the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"document", types.String},
+				{"query", types.TSQuery},
+				{"options", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(tsearch.DefaultConfigName, args[0], args[1], string(tree.MustBeDString(args[2])))
+			},
+			Info: "Returns an excerpt of `document` with the words matching `query` " +
+				"highlighted, with the default text search configuration. `options` is a " +
+				"comma-separated list of StartSel, StopSel, MaxWords, MinWords and " +
+				"HighlightAll settings.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"config", types.String},
+				{"document", types.String},
+				{"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(string(tree.MustBeDString(args[0])), args[1], args[2], "")
+			},
+			Info: "Returns an excerpt of `document` with the words matching `query` " +
+				"highlighted, with the text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"config", types.String},
+				{"document", types.String},
+				{"query", types.TSQuery},
+				{"options", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(string(tree.MustBeDString(args[0])), args[1], args[2], string(tree.MustBeDString(args[3])))
+			},
+			Info: "Returns an excerpt of `document` with the words matching `query` " +
+				"highlighted, with the text search configuration `config`. `options` is a " +
+				"comma-separated list of StartSel, StopSel, MaxWords, MinWords and " +
+				"HighlightAll settings.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+}
+
+// toTSQuery implements to_tsquery.
+func toTSQuery(config string, query string) (tree.Datum, error) {
+	c, err := tsearch.GetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	q, err := c.ToTSQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSQuery(q), nil
+}
+
+// tsRank implements ts_rank.
+func tsRank(
+	w tsearch.RankWeights, vector tree.Datum, query tree.Datum, normalization int,
+) (tree.Datum, error) {
+	if normalization < 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue,
+			"normalization must not be negative")
+	}
+	r := tsearch.Rank(
+		w, tree.MustBeDTSVector(vector).TSVector, tree.MustBeDTSQuery(query).TSQuery, normalization,
+	)
+	return tree.NewDFloat(tree.DFloat(r)), nil
+}
+
+// rankWeights returns the weights of ts_rank given as an array of the weights
+// of the D, C, B and A lexeme weights.
+func rankWeights(arr *tree.DArray) (tsearch.RankWeights, error) {
+	var w tsearch.RankWeights
+	if arr.Len() < len(w) {
+		return w, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i := range w {
+		if arr.Array[i] == tree.DNull {
+			return w, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+		}
+		w[i] = float64(tree.MustBeDFloat(arr.Array[i]))
+		if w[i] > 1 {
+			return w, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		}
+		if w[i] < 0 {
+			w[i] = tsearch.DefaultRankWeights[i]
+		}
+	}
+	return w, nil
+}
+
+// tsHeadline implements ts_headline.
+func tsHeadline(
+	config string, document tree.Datum, query tree.Datum, options string,
+) (tree.Datum, error) {
+	c, err := tsearch.GetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := tsearch.ParseHeadlineOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	doc := string(tree.MustBeDString(document))
+	return tree.NewDString(c.Headline(doc, tree.MustBeDTSQuery(query).TSQuery, opts)), nil
+}
@@ -13,11 +13,15 @@ package builtins
 import (
 	"context"
 	"fmt"
+	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 )
 
 func initWindowBuiltins() {
@@ -221,6 +225,29 @@ var windows = map[string]builtinDefinition{
 				tree.VolatilityImmutable,
 			)
 		}),
+	"st_clusterdbscan": makeBuiltin(winProps(),
+		makeWindowOverload(
+			tree.ArgTypes{{"geometry", types.Geometry}, {"eps", types.Float}, {"minpoints", types.Int}},
+			types.Int,
+			newSTClusterDBSCANWindow,
+			"Returns the cluster of the geometry within its partition, as computed by the DBSCAN algorithm. "+
+				"Geometries within `eps` of at least `minpoints` geometries (including themselves) are the "+
+				"core of a cluster, and geometries within `eps` of a core geometry join its cluster. "+
+				"Returns null for geometries which do not belong to any cluster.",
+			tree.VolatilityImmutable,
+		),
+	),
+	"st_clusterkmeans": makeBuiltin(winProps(),
+		makeWindowOverload(
+			tree.ArgTypes{{"geometry", types.Geometry}, {"number_of_clusters", types.Int}},
+			types.Int,
+			newSTClusterKMeansWindow,
+			"Returns the cluster, numbered from 0, of the geometry within its partition, as computed by "+
+				"the k-means algorithm on the centers of the bounding boxes of the geometries. "+
+				"Returns null for empty geometries.",
+			tree.VolatilityVolatile,
+		),
+	),
 }
 
 func makeWindowOverload(
@@ -251,6 +278,7 @@ var _ tree.WindowFunc = &leadLagWindow{}
 var _ tree.WindowFunc = &firstValueWindow{}
 var _ tree.WindowFunc = &lastValueWindow{}
 var _ tree.WindowFunc = &nthValueWindow{}
+var _ tree.WindowFunc = &geometryClusterWindow{}
 
 // aggregateWindowFunc aggregates over the the current row's window frame, using
 // the internal tree.AggregateFunc to perform the aggregation.
@@ -868,3 +896,103 @@ func (nthValueWindow) Compute(
 func (nthValueWindow) Reset(context.Context) {}
 
 func (nthValueWindow) Close(context.Context, *tree.EvalContext) {}
+
+// geometryClusterWindow assigns each geometry of the partition to a cluster.
+// The clusters are computed from all the geometries of the partition when
+// the first row is processed, using the arguments of that row.
+type geometryClusterWindow struct {
+	// cluster computes the cluster ID of each geometry from the geometries and
+	// the remaining arguments of the first row, which are not NULL.
+	cluster func(geoms []*geo.Geometry, params tree.Datums) ([]int, error)
+
+	// ids holds the cluster ID of each row of the partition, or is nil if the
+	// clusters have not been computed yet. It is empty if the parameters are
+	// NULL, in which case every row gets a NULL cluster ID.
+	ids []int
+	acc mon.BoundAccount
+}
+
+func newSTClusterDBSCANWindow(_ []*types.T, evalCtx *tree.EvalContext) tree.WindowFunc {
+	return &geometryClusterWindow{
+		cluster: func(geoms []*geo.Geometry, params tree.Datums) ([]int, error) {
+			eps := float64(tree.MustBeDFloat(params[0]))
+			minPoints := int(tree.MustBeDInt(params[1]))
+			return geomfn.ClusterDBSCAN(geoms, eps, minPoints)
+		},
+		acc: evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+func newSTClusterKMeansWindow(_ []*types.T, evalCtx *tree.EvalContext) tree.WindowFunc {
+	return &geometryClusterWindow{
+		cluster: func(geoms []*geo.Geometry, params tree.Datums) ([]int, error) {
+			return geomfn.ClusterKMeans(geoms, int(tree.MustBeDInt(params[0])))
+		},
+		acc: evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+func (w *geometryClusterWindow) Compute(
+	ctx context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
+) (tree.Datum, error) {
+	if w.ids == nil {
+		if err := w.computeClusters(ctx, wfr); err != nil {
+			return nil, err
+		}
+	}
+	if len(w.ids) == 0 || w.ids[wfr.RowIdx] == geomfn.NoCluster {
+		return tree.DNull, nil
+	}
+	return tree.NewDInt(tree.DInt(w.ids[wfr.RowIdx])), nil
+}
+
+// computeClusters computes the cluster ID of every row of the partition.
+func (w *geometryClusterWindow) computeClusters(
+	ctx context.Context, wfr *tree.WindowFrameRun,
+) error {
+	w.ids = []int{}
+	args, err := wfr.Args(ctx)
+	if err != nil {
+		return err
+	}
+	params := args[1:]
+	for _, param := range params {
+		if param == tree.DNull {
+			return nil
+		}
+	}
+
+	n := wfr.PartitionSize()
+	geoms := make([]*geo.Geometry, n)
+	usage := int64(n) * int64(unsafe.Sizeof(&geo.Geometry{})+unsafe.Sizeof(int(0)))
+	for i := 0; i < n; i++ {
+		rowArgs, err := wfr.ArgsByRowIdx(ctx, i)
+		if err != nil {
+			return err
+		}
+		if rowArgs[0] == tree.DNull {
+			continue
+		}
+		geoms[i] = tree.MustBeDGeometry(rowArgs[0]).Geometry
+		usage += int64(rowArgs[0].Size())
+	}
+	if err := w.acc.Grow(ctx, usage); err != nil {
+		return err
+	}
+	ids, err := w.cluster(geoms, params)
+	if err != nil {
+		return err
+	}
+	w.ids = ids
+	return nil
+}
+
+// Reset implements tree.WindowFunc interface.
+func (w *geometryClusterWindow) Reset(ctx context.Context) {
+	w.ids = nil
+	w.acc.Clear(ctx)
+}
+
+func (w *geometryClusterWindow) Close(ctx context.Context, _ *tree.EvalContext) {
+	w.acc.Close(ctx)
+}
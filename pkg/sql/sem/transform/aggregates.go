@@ -37,7 +37,7 @@ func (v *IsAggregateVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tre
 		if err != nil {
 			return false, expr
 		}
-		if fd.Class == tree.AggregateClass {
+		if fd.ForNumArgs(len(t.Exprs)).Class == tree.AggregateClass {
 			v.Aggregated = true
 			return false, expr
 		}
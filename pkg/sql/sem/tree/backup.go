@@ -97,6 +97,25 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	}
 }
 
+// AlterBackup represents an ALTER BACKUP statement.
+type AlterBackup struct {
+	Backup Expr
+	NewKMS Expr
+	OldKMS Expr
+}
+
+var _ Statement = &AlterBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER BACKUP ")
+	ctx.FormatNode(node.Backup)
+	ctx.WriteString(" ADD NEW_KMS = ")
+	ctx.FormatNode(node.NewKMS)
+	ctx.WriteString(" WITH OLD_KMS = ")
+	ctx.FormatNode(node.OldKMS)
+}
+
 // KVOption is a key-value option.
 type KVOption struct {
 	Key   Name
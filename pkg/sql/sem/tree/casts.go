@@ -159,6 +159,8 @@ var validCasts = []castInfo{
 	{from: types.OidFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.INetFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 
 	// Casts to CollatedStringFamily.
@@ -185,6 +187,8 @@ var validCasts = []castInfo{
 	{from: types.OidFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.INetFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 
 	// Casts to BytesFamily.
@@ -278,6 +282,18 @@ var validCasts = []castInfo{
 	{from: types.StringFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 
+	// Casts to TSQueryFamily.
+	{from: types.UnknownFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+
+	// Casts to TSVectorFamily.
+	{from: types.UnknownFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+
 	// Casts to EnumFamily.
 	{from: types.UnknownFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
 	{from: types.StringFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
@@ -616,6 +632,10 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			s = t.String()
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
+		case *DTSVector:
+			s = t.TSVector.String()
 		case *DEnum:
 			s = t.LogicalRep
 		}
@@ -887,6 +907,24 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		case *DJSON:
 			return v, nil
 		}
+	case types.TSQueryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return v, nil
+		}
+	case types.TSVectorFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return v, nil
+		}
 	case types.ArrayFamily:
 		switch v := d.(type) {
 		case *DString:
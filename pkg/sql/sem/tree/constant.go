@@ -476,6 +476,8 @@ var (
 		types.Jsonb,
 		types.VarBit,
 		types.AnyEnum,
+		types.TSQuery,
+		types.TSVector,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String, types.AnyEnum}
@@ -225,6 +225,215 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// FuncArg represents an argument of a user-defined function.
+type FuncArg struct {
+	// Name is empty if the argument is unnamed. Unnamed arguments can only be
+	// referenced positionally ($1, $2, ...) in the function body.
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// FuncArgs represents a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		arg := &(*node)[i]
+		if arg.Name != "" {
+			ctx.FormatNode(&arg.Name)
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatTypeReference(arg.Type)
+	}
+}
+
+// FunctionOptions contains the options of a CREATE FUNCTION statement.
+type FunctionOptions struct {
+	// Language is the language the function body is written in.
+	Language Name
+	// Volatility is zero if no volatility was specified.
+	Volatility Volatility
+	// Body is the definition of the function.
+	Body string
+}
+
+// Format implements the NodeFormatter interface.
+func (o *FunctionOptions) Format(ctx *FmtCtx) {
+	sep := ""
+	if o.Language != "" {
+		ctx.WriteString("LANGUAGE ")
+		ctx.FormatNode(&o.Language)
+		sep = " "
+	}
+	if o.Volatility != 0 {
+		ctx.WriteString(sep)
+		switch o.Volatility {
+		case VolatilityImmutable:
+			ctx.WriteString("IMMUTABLE")
+		case VolatilityStable:
+			ctx.WriteString("STABLE")
+		default:
+			ctx.WriteString("VOLATILE")
+		}
+		sep = " "
+	}
+	if o.Body != "" {
+		ctx.WriteString(sep)
+		ctx.WriteString("AS ")
+		lex.EncodeSQLString(&ctx.Buffer, o.Body)
+	}
+}
+
+// CombineWith combines two options, erroring out if the two options contain
+// incompatible settings.
+func (o *FunctionOptions) CombineWith(other *FunctionOptions) error {
+	if other.Language != "" {
+		if o.Language != "" {
+			return errors.New("LANGUAGE specified multiple times")
+		}
+		o.Language = other.Language
+	}
+	if other.Volatility != 0 {
+		if o.Volatility != 0 {
+			return errors.New("volatility specified multiple times")
+		}
+		o.Volatility = other.Volatility
+	}
+	if other.Body != "" {
+		if o.Body != "" {
+			return errors.New("AS specified multiple times")
+		}
+		o.Body = other.Body
+	}
+	return nil
+}
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Name       *UnresolvedObjectName
+	Replace    bool
+	Args       FuncArgs
+	ReturnType ResolvableTypeReference
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatTypeReference(node.ReturnType)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Options)
+}
+
+// ReturnsTrigger returns true if the function is a trigger function, that is,
+// if its return type is the TRIGGER pseudo-type.
+func (node *CreateFunction) ReturnsTrigger() bool {
+	n, ok := node.ReturnType.(*UnresolvedObjectName)
+	return ok && n.NumParts == 1 && strings.EqualFold(n.Parts[0], "trigger")
+}
+
+// TriggerActionTime is the time at which a trigger function is executed,
+// relative to the modification of a row.
+type TriggerActionTime int
+
+// TriggerActionTime values.
+const (
+	TriggerBefore TriggerActionTime = iota
+	TriggerAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerBefore: "BEFORE",
+	TriggerAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEvent is a statement which fires a trigger.
+type TriggerEvent int
+
+// TriggerEvent values.
+const (
+	TriggerEventInsert TriggerEvent = iota
+	TriggerEventUpdate
+	TriggerEventDelete
+)
+
+var triggerEventName = [...]string{
+	TriggerEventInsert: "INSERT",
+	TriggerEventUpdate: "UPDATE",
+	TriggerEventDelete: "DELETE",
+}
+
+func (e TriggerEvent) String() string {
+	return triggerEventName[e]
+}
+
+// TriggerEvents is a list of events which fire a trigger.
+type TriggerEvents []TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i, e := range *node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.WriteString(e.String())
+	}
+}
+
+// Contains returns true if the list contains the given event.
+func (node TriggerEvents) Contains(e TriggerEvent) bool {
+	for _, other := range node {
+		if other == e {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	FuncName   *UnresolvedObjectName
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" FOR EACH ROW EXECUTE FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteString("()")
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -293,10 +502,17 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
 		Expr     Expr
+		Virtual  bool
+	}
+	GeneratedIdentity struct {
+		IsGeneratedAsIdentity   bool
+		GeneratedAsIdentityType GeneratedIdentityType
+		SeqOptions              SequenceOptions
 	}
 	Family struct {
 		Name        Name
@@ -414,9 +630,19 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
+			d.Computed.Virtual = t.Virtual
+		case *GeneratedAsIdentityConstraint:
+			if d.IsGeneratedAsIdentity() {
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"multiple identity specifications for column %q", name)
+			}
+			d.GeneratedIdentity.IsGeneratedAsIdentity = true
+			d.GeneratedIdentity.GeneratedAsIdentityType = t.GeneratedAsIdentityType
+			d.GeneratedIdentity.SeqOptions = t.SeqOptions
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
@@ -429,6 +655,20 @@ func NewColumnTableDef(
 			return nil, errors.AssertionFailedf("unexpected column qualification: %T", c)
 		}
 	}
+	if d.IsGeneratedAsIdentity() {
+		if d.HasDefaultExpr() {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"both default and identity specified for column %q", name)
+		}
+		if d.IsComputed() {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"both generated and identity specified for column %q", name)
+		}
+		if d.Nullable.Nullability == Null {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"conflicting NULL/NOT NULL declarations for column %q", name)
+		}
+	}
 	return d, nil
 }
 
@@ -447,6 +687,16 @@ func (node *ColumnTableDef) IsComputed() bool {
 	return node.Computed.Computed
 }
 
+// IsVirtual returns if the ColumnTableDef is a virtual computed column.
+func (node *ColumnTableDef) IsVirtual() bool {
+	return node.Computed.Virtual
+}
+
+// IsGeneratedAsIdentity returns if the ColumnTableDef is an identity column.
+func (node *ColumnTableDef) IsGeneratedAsIdentity() bool {
+	return node.GeneratedIdentity.IsGeneratedAsIdentity
+}
+
 // HasColumnFamily returns if the ColumnTableDef has a column family.
 func (node *ColumnTableDef) HasColumnFamily() bool {
 	return node.Family.Name != "" || node.Family.Create
@@ -522,11 +772,29 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		if node.References.Deferrability != NotDeferrable {
+			ctx.WriteByte(' ')
+			ctx.WriteString(node.References.Deferrability.String())
+		}
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.IsVirtual() {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
+	}
+	if node.IsGeneratedAsIdentity() {
+		ctx.WriteString(" GENERATED ")
+		ctx.WriteString(node.GeneratedIdentity.GeneratedAsIdentityType.String())
+		ctx.WriteString(" AS IDENTITY")
+		if len(node.GeneratedIdentity.SeqOptions) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.GeneratedIdentity.SeqOptions)
+			ctx.WriteString(" )")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -574,17 +842,18 @@ type ColumnQualification interface {
 	columnQualification()
 }
 
-func (ColumnCollation) columnQualification()             {}
-func (*ColumnDefault) columnQualification()              {}
-func (NotNullConstraint) columnQualification()           {}
-func (NullConstraint) columnQualification()              {}
-func (PrimaryKeyConstraint) columnQualification()        {}
-func (ShardedPrimaryKeyConstraint) columnQualification() {}
-func (UniqueConstraint) columnQualification()            {}
-func (*ColumnCheckConstraint) columnQualification()      {}
-func (*ColumnComputedDef) columnQualification()          {}
-func (*ColumnFKConstraint) columnQualification()         {}
-func (*ColumnFamilyConstraint) columnQualification()     {}
+func (ColumnCollation) columnQualification()                {}
+func (*ColumnDefault) columnQualification()                 {}
+func (NotNullConstraint) columnQualification()              {}
+func (NullConstraint) columnQualification()                 {}
+func (PrimaryKeyConstraint) columnQualification()           {}
+func (ShardedPrimaryKeyConstraint) columnQualification()    {}
+func (UniqueConstraint) columnQualification()               {}
+func (*ColumnCheckConstraint) columnQualification()         {}
+func (*ColumnComputedDef) columnQualification()             {}
+func (*ColumnFKConstraint) columnQualification()            {}
+func (*ColumnFamilyConstraint) columnQualification()        {}
+func (*GeneratedAsIdentityConstraint) columnQualification() {}
 
 // ColumnCollation represents a COLLATE clause for a column.
 type ColumnCollation string
@@ -620,15 +889,43 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
-	Expr Expr
+	Expr    Expr
+	Virtual bool
+}
+
+// GeneratedIdentityType is the kind of an identity column, which determines
+// whether values may be explicitly provided for the column on INSERT.
+type GeneratedIdentityType int
+
+// The values for GeneratedIdentityType.
+const (
+	GeneratedAlways GeneratedIdentityType = iota
+	GeneratedByDefault
+)
+
+var generatedIdentityTypeName = [...]string{
+	GeneratedAlways:    "ALWAYS",
+	GeneratedByDefault: "BY DEFAULT",
+}
+
+func (t GeneratedIdentityType) String() string {
+	return generatedIdentityTypeName[t]
+}
+
+// GeneratedAsIdentityConstraint represents GENERATED { ALWAYS | BY DEFAULT }
+// AS IDENTITY on a column.
+type GeneratedAsIdentityConstraint struct {
+	GeneratedAsIdentityType GeneratedIdentityType
+	SeqOptions              SequenceOptions
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -814,14 +1111,37 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability describes whether the checking of a constraint can
+// be deferred until the end of the transaction, and whether it is deferred by
+// default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrable:                "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -849,6 +1169,11 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+
+	if node.Deferrability != NotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Deferrability.String())
+	}
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1184,12 +1509,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -1522,12 +1848,13 @@ func (node *AlterRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        TableName
-	ColumnNames NameList
-	AsSource    *Select
-	IfNotExists bool
-	Temporary   bool
-	Replace     bool
+	Name         TableName
+	ColumnNames  NameList
+	AsSource     *Select
+	IfNotExists  bool
+	Temporary    bool
+	Replace      bool
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1542,6 +1869,10 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteString("TEMPORARY ")
 	}
 
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+
 	ctx.WriteString("VIEW ")
 
 	if node.IfNotExists {
@@ -1560,6 +1891,23 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.AsSource)
 }
 
+// RefreshMaterializedView represents a REFRESH MATERIALIZED VIEW statement.
+type RefreshMaterializedView struct {
+	Name         *UnresolvedObjectName
+	Concurrently bool
+}
+
+var _ Statement = &RefreshMaterializedView{}
+
+// Format implements the NodeFormatter interface.
+func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
+	ctx.WriteString("REFRESH MATERIALIZED VIEW ")
+	if node.Concurrently {
+		ctx.WriteString("CONCURRENTLY ")
+	}
+	ctx.FormatNode(node.Name)
+}
+
 // CreateStats represents a CREATE STATISTICS statement.
 type CreateStats struct {
 	Name        Name
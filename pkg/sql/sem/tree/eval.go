@@ -22,6 +22,9 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geogfn"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -40,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1887,6 +1891,47 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			Volatility: VolatilityImmutable,
 		},
 	},
+
+	// Distance is the distance operator used to order shapes by their
+	// proximity to another shape. Unlike st_distance, it considers empty shapes
+	// to be infinitely far away from every other shape, so that they sort after
+	// all non-empty shapes. Geography distances are measured on the sphere.
+	Distance: {
+		&BinOp{
+			LeftType:   types.Geometry,
+			RightType:  types.Geometry,
+			ReturnType: types.Float,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ret, err := geomfn.MinDistance(left.(*DGeometry).Geometry, right.(*DGeometry).Geometry)
+				if err != nil {
+					if geo.IsEmptyGeometryError(err) {
+						return NewDFloat(DFloat(math.Inf(1))), nil
+					}
+					return nil, err
+				}
+				return NewDFloat(DFloat(ret)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&BinOp{
+			LeftType:   types.Geography,
+			RightType:  types.Geography,
+			ReturnType: types.Float,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ret, err := geogfn.Distance(
+					left.(*DGeography).Geography, right.(*DGeography).Geography, geogfn.UseSphere,
+				)
+				if err != nil {
+					if geo.IsEmptyGeometryError(err) {
+						return NewDFloat(DFloat(math.Inf(1))), nil
+					}
+					return nil, err
+				}
+				return NewDFloat(DFloat(ret)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
 }
 
 // timestampMinusBinOp is the implementation of the subtraction
@@ -2063,6 +2108,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeEqFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeEqFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeEqFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeEqFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeEqFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2116,6 +2163,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLtFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLtFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLtFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLtFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLtFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2169,6 +2218,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLeFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLeFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLeFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLeFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLeFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2232,6 +2283,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeIsFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeIsFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeIsFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeIsFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeIsFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2287,6 +2340,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.TimeTZ, VolatilityLeakProof),
 		makeEvalTupleIn(types.Timestamp, VolatilityLeakProof),
 		makeEvalTupleIn(types.TimestampTZ, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSQuery, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSVector, VolatilityLeakProof),
 		makeEvalTupleIn(types.Uuid, VolatilityLeakProof),
 		makeEvalTupleIn(types.VarBit, VolatilityLeakProof),
 	},
@@ -2500,6 +2555,29 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			Volatility: VolatilityImmutable,
 		},
 	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				v := MustBeDTSVector(left).TSVector
+				q := MustBeDTSQuery(right).TSQuery
+				return MakeDBool(DBool(tsearch.Matches(v, q))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(left).TSQuery
+				v := MustBeDTSVector(right).TSVector
+				return MakeDBool(DBool(tsearch.Matches(v, q))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
 })
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -4106,6 +4184,16 @@ func (t *DGeometry) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DEnum) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
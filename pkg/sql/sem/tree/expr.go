@@ -385,6 +385,7 @@ const (
 	JSONSomeExists
 	JSONAllExists
 	Overlaps
+	TSMatches
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -435,6 +436,7 @@ var comparisonOpName = [...]string{
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1122,6 +1124,7 @@ const (
 	JSONFetchText
 	JSONFetchValPath
 	JSONFetchTextPath
+	Distance
 
 	NumBinaryOperators
 )
@@ -1146,6 +1149,7 @@ var binaryOpName = [...]string{
 	JSONFetchText:     "->>",
 	JSONFetchValPath:  "#>",
 	JSONFetchTextPath: "#>>",
+	Distance:          "<->",
 }
 
 // binaryOpPrio follows the precedence order in the grammar. Used for pretty-printing.
@@ -1158,6 +1162,7 @@ var binaryOpPrio = [...]int{
 	Bitxor: 6,
 	Bitor:  7,
 	Concat: 8, JSONFetchVal: 8, JSONFetchText: 8, JSONFetchValPath: 8, JSONFetchTextPath: 8,
+	Distance: 8,
 }
 
 // binaryOpFullyAssoc indicates whether an operator is fully associative.
@@ -1171,6 +1176,7 @@ var binaryOpFullyAssoc = [...]bool{
 	Bitxor: true,
 	Bitor:  true,
 	Concat: true, JSONFetchVal: false, JSONFetchText: false, JSONFetchValPath: false, JSONFetchTextPath: false,
+	Distance: false,
 }
 
 func (i BinaryOperator) isPadded() bool {
@@ -1790,6 +1796,8 @@ func (node *DGeometry) String() string        { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
@@ -0,0 +1,135 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// formatForeignOptions formats the OPTIONS clause shared by the foreign data
+// wrapper, foreign server and foreign table statements.
+func formatForeignOptions(ctx *FmtCtx, options KVOptions) {
+	if len(options) == 0 {
+		return
+	}
+	ctx.WriteString(" OPTIONS (")
+	for i := range options {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&options[i].Key)
+		ctx.WriteByte(' ')
+		ctx.FormatNode(options[i].Value)
+	}
+	ctx.WriteByte(')')
+}
+
+// CreateForeignDataWrapper represents a CREATE FOREIGN DATA WRAPPER statement.
+type CreateForeignDataWrapper struct {
+	Name    Name
+	Options KVOptions
+}
+
+var _ Statement = &CreateForeignDataWrapper{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateForeignDataWrapper) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE FOREIGN DATA WRAPPER ")
+	ctx.FormatNode(&node.Name)
+	formatForeignOptions(ctx, node.Options)
+}
+
+// DropForeignDataWrapper represents a DROP FOREIGN DATA WRAPPER statement.
+type DropForeignDataWrapper struct {
+	Names        NameList
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropForeignDataWrapper{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropForeignDataWrapper) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FOREIGN DATA WRAPPER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// CreateServer represents a CREATE SERVER statement.
+type CreateServer struct {
+	Name        Name
+	IfNotExists bool
+	Wrapper     Name
+	Options     KVOptions
+}
+
+var _ Statement = &CreateServer{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateServer) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SERVER ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" FOREIGN DATA WRAPPER ")
+	ctx.FormatNode(&node.Wrapper)
+	formatForeignOptions(ctx, node.Options)
+}
+
+// DropServer represents a DROP SERVER statement.
+type DropServer struct {
+	Names        NameList
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropServer{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropServer) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SERVER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// CreateForeignTable represents a CREATE FOREIGN TABLE statement.
+type CreateForeignTable struct {
+	Table       TableName
+	IfNotExists bool
+	Defs        TableDefs
+	Server      Name
+	Options     KVOptions
+}
+
+var _ Statement = &CreateForeignTable{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateForeignTable) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE FOREIGN TABLE ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Defs)
+	ctx.WriteString(") SERVER ")
+	ctx.FormatNode(&node.Server)
+	formatForeignOptions(ctx, node.Options)
+}
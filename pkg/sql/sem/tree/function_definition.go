@@ -26,6 +26,11 @@ type FunctionDefinition struct {
 
 	// FunctionProperties are the properties common to all overloads.
 	FunctionProperties
+
+	// NonAggregate, if set, holds the normal overloads of an aggregate
+	// function which shares its name with a normal function, as st_union
+	// does. See ForNumArgs.
+	NonAggregate *FunctionDefinition
 }
 
 // FunctionProperties defines the properties of the built-in
@@ -103,6 +108,10 @@ type FunctionProperties struct {
 	// should take RegClass as the arg type for the sequence name instead of
 	// string, we will add a dependency on all RegClass types used in a view.
 	HasSequenceArguments bool
+
+	// UserDefined is set for functions created with CREATE FUNCTION. Their
+	// overloads are defined by a SQL body instead of a builtin implementation.
+	UserDefined bool
 }
 
 // ShouldDocument returns whether the built-in function should be included in
@@ -164,6 +173,45 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUserDefinedFunctionDefinition allocates a function definition for a
+// function created with CREATE FUNCTION. Unlike builtins, no telemetry is
+// collected for the overloads of user-defined functions.
+func NewUserDefinedFunctionDefinition(
+	name string, props *FunctionProperties, def []Overload,
+) *FunctionDefinition {
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		overloads[i] = &def[i]
+	}
+	props.UserDefined = true
+	return &FunctionDefinition{
+		Name:               name,
+		Definition:         overloads,
+		FunctionProperties: *props,
+	}
+}
+
+// ForNumArgs returns the definition to use for an application of the
+// function to the given number of arguments. This is the definition itself,
+// unless it is an aggregate which has normal overloads accepting that many
+// arguments and no aggregate overload that does.
+func (fd *FunctionDefinition) ForNumArgs(n int) *FunctionDefinition {
+	if fd.NonAggregate == nil {
+		return fd
+	}
+	for _, o := range fd.Definition {
+		if o.params().MatchLen(n) {
+			return fd
+		}
+	}
+	for _, o := range fd.NonAggregate.Definition {
+		if o.params().MatchLen(n) {
+			return fd.NonAggregate
+		}
+	}
+	return fd
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
@@ -11,8 +11,11 @@
 package tree
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -62,6 +65,46 @@ func (fn *ResolvableFunctionReference) Resolve(
 	}
 }
 
+// FunctionReferenceResolver is the interface that provides the ability to
+// look up user-defined functions.
+type FunctionReferenceResolver interface {
+	// ResolveFunction returns the definition of the user-defined function with
+	// the given name, or nil if there is no such function.
+	ResolveFunction(
+		ctx context.Context, name *UnresolvedName, searchPath sessiondata.SearchPath,
+	) (*FunctionDefinition, error)
+}
+
+// ResolveWithResolver is like Resolve, but falls back to looking up
+// user-defined functions with the given resolver if the name does not refer
+// to a builtin function. Unlike builtin functions, user-defined functions are
+// not cached in the reference, since they may be altered or dropped between
+// executions of a prepared statement.
+func (fn *ResolvableFunctionReference) ResolveWithResolver(
+	ctx context.Context, searchPath sessiondata.SearchPath, resolver FunctionReferenceResolver,
+) (*FunctionDefinition, error) {
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok || resolver == nil {
+		return fn.Resolve(searchPath)
+	}
+	fd, err := name.ResolveFunction(searchPath)
+	if err == nil {
+		fn.FunctionReference = fd
+		return fd, nil
+	}
+	if pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return nil, err
+	}
+	udf, udfErr := resolver.ResolveFunction(ctx, name, searchPath)
+	if udfErr != nil {
+		return nil, udfErr
+	}
+	if udf == nil {
+		return nil, err
+	}
+	return udf, nil
+}
+
 // WrapFunction creates a new ResolvableFunctionReference
 // holding a pre-resolved function. Helper for grammar rules.
 func WrapFunction(n string) ResolvableFunctionReference {
@@ -24,11 +24,38 @@ type Insert struct {
 	With       *With
 	Table      TableExpr
 	Columns    NameList
+	Overriding OverridingKind
 	Rows       *Select
 	OnConflict *OnConflict
 	Returning  ReturningClause
 }
 
+// OverridingKind represents the OVERRIDING clause of an INSERT, which
+// determines how explicit values for identity columns are handled.
+type OverridingKind int
+
+const (
+	// OverridingNone indicates that there is no OVERRIDING clause.
+	// Explicit values for GENERATED ALWAYS identity columns are rejected.
+	OverridingNone OverridingKind = iota
+	// OverridingSystemValue indicates OVERRIDING SYSTEM VALUE. Explicit
+	// values for identity columns are used as is.
+	OverridingSystemValue
+	// OverridingUserValue indicates OVERRIDING USER VALUE. Explicit values
+	// for identity columns are ignored, and the sequence values are used.
+	OverridingUserValue
+)
+
+var overridingKindName = [...]string{
+	OverridingNone:        "",
+	OverridingSystemValue: "SYSTEM",
+	OverridingUserValue:   "USER",
+}
+
+func (k OverridingKind) String() string {
+	return overridingKindName[k]
+}
+
 // Format implements the NodeFormatter interface.
 func (node *Insert) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.With)
@@ -44,6 +71,11 @@ func (node *Insert) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.Columns)
 		ctx.WriteByte(')')
 	}
+	if node.Overriding != OverridingNone {
+		ctx.WriteString(" OVERRIDING ")
+		ctx.WriteString(node.Overriding.String())
+		ctx.WriteString(" VALUE")
+	}
 	if node.DefaultValues() {
 		ctx.WriteString(" DEFAULT VALUES")
 	} else {
@@ -73,6 +73,11 @@ type Overload struct {
 	// statement which will be executed as a common table expression in the query.
 	SQLFn func(*EvalContext, Datums) (string, error)
 
+	// Body is set for the overloads of user-defined functions. It is the
+	// SELECT statement which computes the result of the function; Fn
+	// evaluates it with the internal executor.
+	Body string
+
 	// counter, if non-nil, should be incremented upon successful
 	// type check of expressions using this overload.
 	counter telemetry.Counter
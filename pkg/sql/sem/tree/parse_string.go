@@ -72,6 +72,10 @@ func ParseAndRequireString(t *types.T, s string, ctx ParseTimeContext) (Datum, e
 		return ParseDTimestamp(ctx, s, TimeFamilyPrecisionToRoundDuration(t.Precision()))
 	case types.TimestampTZFamily:
 		return ParseDTimestampTZ(ctx, s, TimeFamilyPrecisionToRoundDuration(t.Precision()))
+	case types.TSQueryFamily:
+		return ParseDTSQuery(s)
+	case types.TSVectorFamily:
+		return ParseDTSVector(s)
 	case types.UuidFamily:
 		return ParseDUuidFromString(s)
 	case types.EnumFamily:
@@ -940,6 +940,11 @@ func (node *Insert) doc(p *PrettyCfg) pretty.Doc {
 	}
 	items = append(items, p.row("INTO", into))
 
+	if node.Overriding != OverridingNone {
+		items = append(items, p.row("OVERRIDING",
+			pretty.Keyword(node.Overriding.String()+" VALUE")))
+	}
+
 	if node.DefaultValues() {
 		items = append(items, p.row("", pretty.Keyword("DEFAULT VALUES")))
 	} else {
@@ -1241,7 +1246,7 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	// Final layout:
 	//
-	// CREATE [TEMP] VIEW name ( ... ) AS
+	// CREATE [TEMP | MATERIALIZED] VIEW name ( ... ) AS
 	//     SELECT ...
 	//
 	title := pretty.Keyword("CREATE")
@@ -1251,6 +1256,9 @@ func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	if node.Temporary {
 		title = pretty.ConcatSpace(title, pretty.Keyword("TEMPORARY"))
 	}
+	if node.Materialized {
+		title = pretty.ConcatSpace(title, pretty.Keyword("MATERIALIZED"))
+	}
 	title = pretty.ConcatSpace(title, pretty.Keyword("VIEW"))
 	if node.IfNotExists {
 		title = pretty.ConcatSpace(title, pretty.Keyword("IF NOT EXISTS"))
@@ -1707,6 +1715,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl (...)
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1714,6 +1723,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl [(...)]
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 4)
 	title := pretty.ConcatSpace(
@@ -1740,6 +1750,10 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if node.Deferrability != NotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrability.String()))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -1783,11 +1797,25 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 
 	// Compute expression (for computed columns).
 	if node.IsComputed() {
+		kind := ") STORED"
+		if node.IsVirtual() {
+			kind = ") VIRTUAL"
+		}
 		clauses = append(clauses, pretty.ConcatSpace(pretty.Keyword("AS"),
-			p.bracket("(", p.Doc(node.Computed.Expr), ") STORED"),
+			p.bracket("(", p.Doc(node.Computed.Expr), kind),
 		))
 	}
 
+	// Identity specification (for identity columns).
+	if node.IsGeneratedAsIdentity() {
+		d := pretty.Keyword("GENERATED " + node.GeneratedIdentity.GeneratedAsIdentityType.String() +
+			" AS IDENTITY")
+		if len(node.GeneratedIdentity.SeqOptions) > 0 {
+			d = pretty.ConcatSpace(d, p.bracket("(", p.Doc(&node.GeneratedIdentity.SeqOptions), ")"))
+		}
+		clauses = append(clauses, d)
+	}
+
 	// Column family.
 	if node.HasColumnFamily() {
 		d := pretty.Keyword("FAMILY")
@@ -1857,6 +1885,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrability != NotDeferrable {
+			fkDetails = append(fkDetails, pretty.Keyword(node.References.Deferrability.String()))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -0,0 +1,139 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// The statements in this file are the commands of the streaming replication
+// subprotocol. They are only accepted on connections opened in replication
+// mode and are not part of the SQL grammar; see
+// parser.ParseReplicationCommand.
+
+// LSN is a position in the logical replication stream. Postgres formats it as
+// two hexadecimal numbers separated by a slash, holding the high and low 32
+// bits of the position.
+type LSN uint64
+
+// String implements the fmt.Stringer interface.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint32(l))
+}
+
+// ParseLSN parses a LSN in the format produced by LSN.String.
+func ParseLSN(s string) (LSN, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) == 2 {
+		hi, err1 := strconv.ParseUint(parts[0], 16, 32)
+		lo, err2 := strconv.ParseUint(parts[1], 16, 32)
+		if err1 == nil && err2 == nil {
+			return LSN(hi<<32 | lo), nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+		"invalid input syntax for type pg_lsn: %q", s)
+}
+
+// IdentifySystem represents an IDENTIFY_SYSTEM replication command.
+type IdentifySystem struct{}
+
+var _ Statement = &IdentifySystem{}
+
+// Format implements the NodeFormatter interface.
+func (node *IdentifySystem) Format(ctx *FmtCtx) {
+	ctx.WriteString("IDENTIFY_SYSTEM")
+}
+
+// CreateReplicationSlot represents a CREATE_REPLICATION_SLOT replication
+// command. Only logical replication slots can be created.
+type CreateReplicationSlot struct {
+	Name      Name
+	Temporary bool
+	// Plugin is the name of the output plugin used to decode the changes.
+	Plugin string
+}
+
+var _ Statement = &CreateReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Name)
+	if node.Temporary {
+		ctx.WriteString(" TEMPORARY")
+	}
+	ctx.WriteString(" LOGICAL ")
+	lex.EncodeRestrictedSQLIdent(&ctx.Buffer, node.Plugin, lex.EncNoFlags)
+}
+
+// DropReplicationSlot represents a DROP_REPLICATION_SLOT replication command.
+type DropReplicationSlot struct {
+	Name Name
+	Wait bool
+}
+
+var _ Statement = &DropReplicationSlot{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Name)
+	if node.Wait {
+		ctx.WriteString(" WAIT")
+	}
+}
+
+// ReplicationOption is an option passed to the output plugin by
+// START_REPLICATION.
+type ReplicationOption struct {
+	Key Name
+	// Value is empty if no value was specified.
+	Value string
+}
+
+// StartReplication represents a START_REPLICATION replication command. Only
+// logical replication can be started.
+type StartReplication struct {
+	Slot     Name
+	StartLSN LSN
+	Options  []ReplicationOption
+}
+
+var _ Statement = &StartReplication{}
+
+// Format implements the NodeFormatter interface.
+func (node *StartReplication) Format(ctx *FmtCtx) {
+	ctx.WriteString("START_REPLICATION SLOT ")
+	ctx.FormatNode(&node.Slot)
+	ctx.WriteString(" LOGICAL ")
+	ctx.WriteString(node.StartLSN.String())
+	if len(node.Options) > 0 {
+		ctx.WriteString(" (")
+		for i := range node.Options {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&node.Options[i].Key)
+			if node.Options[i].Value != "" {
+				ctx.WriteByte(' ')
+				lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Options[i].Value, ctx.flags.EncodeFlags())
+			}
+		}
+		ctx.WriteByte(')')
+	}
+}
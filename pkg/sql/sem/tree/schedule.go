@@ -0,0 +1,197 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// ScheduledJobExecutorType is a type identifying the names of
+// the supported scheduled job executors.
+type ScheduledJobExecutorType int
+
+const (
+	// InvalidExecutor is a placeholder for an invalid executor type.
+	InvalidExecutor ScheduledJobExecutorType = iota
+
+	// ScheduledBackupExecutor is an executor responsible for
+	// the execution of the scheduled backups.
+	ScheduledBackupExecutor
+
+	// ScheduledRowLevelTTLExecutor is an executor responsible for starting
+	// the jobs deleting the expired rows of tables with row-level TTL.
+	ScheduledRowLevelTTLExecutor
+)
+
+var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
+	InvalidExecutor:              "unknown-executor",
+	ScheduledBackupExecutor:      "scheduled-backup-executor",
+	ScheduledRowLevelTTLExecutor: "scheduled-row-level-ttl-executor",
+}
+
+// InternalName returns an internal executor name.
+// This name can be used to filter matching schedules.
+func (t ScheduledJobExecutorType) InternalName() string {
+	return scheduleExecutorInternalNames[t]
+}
+
+// UserName returns a user friendly executor name.
+func (t ScheduledJobExecutorType) UserName() string {
+	switch t {
+	case ScheduledBackupExecutor:
+		return "BACKUP"
+	case ScheduledRowLevelTTLExecutor:
+		return "ROW LEVEL TTL"
+	}
+	return "unsupported-executor"
+}
+
+// FullBackupClause describes the frequency of full backups.
+type FullBackupClause struct {
+	AlwaysFull bool
+	Recurrence Expr
+}
+
+// ScheduledBackup represents scheduled backup job.
+type ScheduledBackup struct {
+	ScheduleLabel   Expr
+	Recurrence      Expr
+	FullBackup      *FullBackupClause /* nil implies choose default */
+	Targets         *TargetList       /* nil implies tree.AllDescriptors coverage */
+	To              PartitionedBackup
+	BackupOptions   KVOptions
+	ScheduleOptions KVOptions
+}
+
+var _ Statement = &ScheduledBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+
+	if node.ScheduleLabel != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.ScheduleLabel)
+	}
+
+	ctx.WriteString(" FOR BACKUP")
+	if node.Targets != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Targets)
+	}
+
+	ctx.WriteString(" INTO ")
+	ctx.FormatNode(&node.To)
+
+	if node.BackupOptions != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.BackupOptions)
+	}
+
+	ctx.WriteString(" RECURRING ")
+	ctx.FormatNode(node.Recurrence)
+
+	if node.FullBackup != nil {
+		if node.FullBackup.AlwaysFull {
+			ctx.WriteString(" FULL BACKUP ALWAYS")
+		} else {
+			ctx.WriteString(" FULL BACKUP ")
+			ctx.FormatNode(node.FullBackup.Recurrence)
+		}
+	}
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		ctx.FormatNode(&node.ScheduleOptions)
+	}
+}
+
+// ScheduleCommand determines which type of action to effect on the selected
+// schedule(s).
+type ScheduleCommand int
+
+// ScheduleCommand values
+const (
+	PauseSchedule ScheduleCommand = iota
+	ResumeSchedule
+	DropSchedule
+)
+
+func (c ScheduleCommand) String() string {
+	switch c {
+	case PauseSchedule:
+		return "PAUSE"
+	case ResumeSchedule:
+		return "RESUME"
+	case DropSchedule:
+		return "DROP"
+	default:
+		panic("unhandled schedule command")
+	}
+}
+
+// ControlSchedules represents PAUSE/RESUME/DROP SCHEDULES statement.
+type ControlSchedules struct {
+	Schedules *Select
+	Command   ScheduleCommand
+}
+
+var _ Statement = &ControlSchedules{}
+
+// Format implements the NodeFormatter interface.
+func (n *ControlSchedules) Format(ctx *FmtCtx) {
+	ctx.WriteString(n.Command.String())
+	ctx.WriteString(" SCHEDULES ")
+	ctx.FormatNode(n.Schedules)
+}
+
+// ScheduleState describes what schedules to display.
+type ScheduleState int
+
+// ScheduleState values
+const (
+	SpecifiedSchedules ScheduleState = iota
+	ActiveSchedules
+	PausedSchedules
+)
+
+// ShowSchedules represents a SHOW SCHEDULES statement.
+type ShowSchedules struct {
+	WhichSchedules ScheduleState
+	ExecutorType   ScheduledJobExecutorType
+	ScheduleID     Expr
+}
+
+var _ Statement = &ShowSchedules{}
+
+// Format implements the NodeFormatter interface.
+func (n *ShowSchedules) Format(ctx *FmtCtx) {
+	if n.ScheduleID != nil {
+		ctx.WriteString("SHOW SCHEDULE ")
+		ctx.FormatNode(n.ScheduleID)
+		return
+	}
+	ctx.WriteString("SHOW")
+
+	switch n.WhichSchedules {
+	case ActiveSchedules:
+		ctx.WriteString(" RUNNING")
+	case PausedSchedules:
+		ctx.WriteString(" PAUSED")
+	default:
+		// Nothing
+	}
+
+	ctx.WriteString(" SCHEDULES")
+
+	switch n.ExecutorType {
+	case ScheduledBackupExecutor:
+		ctx.WriteString(" FOR BACKUP")
+	default:
+		// Nothing
+	}
+}
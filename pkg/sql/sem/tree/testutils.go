@@ -80,6 +80,12 @@ func SampleDatum(t *types.T) Datum {
 		return j
 	case types.OidFamily:
 		return NewDOid(DInt(1009))
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery(`'a' & 'b'`)
+		return q
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector(`'a':1 'b':2`)
+		return v
 	case types.GeographyFamily:
 		return NewDGeography(geo.MustParseGeographyFromEWKB([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\x3f")))
 	case types.GeometryFamily:
@@ -54,6 +54,12 @@ type SemaContext struct {
 	// TypeResolver manages resolving type names into *types.T's.
 	TypeResolver TypeReferenceResolver
 
+	// FunctionResolver resolves the names of user-defined functions. It is
+	// only consulted by the optimizer, which tracks the dependencies of
+	// statements on user-defined functions; other contexts only resolve
+	// builtin functions.
+	FunctionResolver FunctionReferenceResolver
+
 	// AsOfTimestamp denotes the explicit AS OF SYSTEM TIME timestamp for the
 	// query, if any. If the query is not an AS OF SYSTEM TIME query,
 	// AsOfTimestamp is nil.
@@ -225,6 +231,14 @@ func (sc *SemaContext) GetTypeResolver() TypeReferenceResolver {
 	return sc.TypeResolver
 }
 
+// GetFunctionResolver returns the FunctionReferenceResolver.
+func (sc *SemaContext) GetFunctionResolver() FunctionReferenceResolver {
+	if sc == nil {
+		return nil
+	}
+	return sc.FunctionResolver
+}
+
 func placeholderTypeAmbiguityError(idx PlaceholderIdx) error {
 	return pgerror.WithCandidateCode(
 		&placeholderTypeAmbiguityErr{idx},
@@ -881,6 +895,7 @@ func (expr *FuncExpr) TypeCheck(
 	if err != nil {
 		return nil, err
 	}
+	def = def.ForNumArgs(len(expr.Exprs))
 
 	if err := semaCtx.checkFunctionUsage(expr, def); err != nil {
 		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
@@ -1615,6 +1630,18 @@ func (d *DGeometry) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (Ty
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DJSON) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
@@ -56,6 +56,9 @@ func (p *planner) processSerialInColumnDef(
 	tree.SequenceOptions,
 	error,
 ) {
+	if d.IsGeneratedAsIdentity() {
+		return p.processIdentityInColumnDef(ctx, d, tableName)
+	}
 	if !d.IsSerial {
 		// Column is not SERIAL: nothing to do.
 		return d, nil, nil, nil, nil
@@ -112,6 +115,65 @@ func (p *planner) processSerialInColumnDef(
 
 	log.VEventf(ctx, 2, "creating sequence for new column %q of %q", d, tableName)
 
+	dbDesc, seqName, err := p.makeColumnSequenceName(ctx, d, tableName)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defaultExpr := makeNextvalExpr(seqName)
+
+	seqType := ""
+	seqOpts := realSequenceOpts
+	if serialNormalizationMode == sessiondata.SerialUsesVirtualSequences {
+		seqType = "virtual "
+		seqOpts = virtualSequenceOpts
+	}
+	log.VEventf(ctx, 2, "new column %q of %q will have %s sequence name %q and default %q",
+		d, tableName, seqType, seqName, defaultExpr)
+
+	newSpec.DefaultExpr.Expr = defaultExpr
+
+	return &newSpec, dbDesc, seqName, seqOpts, nil
+}
+
+// processIdentityInColumnDef is the counterpart of processSerialInColumnDef
+// for identity columns. Identity columns always draw their values from a
+// new SQL sequence, created with the sequence options of the column
+// definition, regardless of the serial normalization mode.
+func (p *planner) processIdentityInColumnDef(
+	ctx context.Context, d *tree.ColumnTableDef, tableName *TableName,
+) (
+	*tree.ColumnTableDef,
+	*sqlbase.ImmutableDatabaseDescriptor,
+	*TableName,
+	tree.SequenceOptions,
+	error,
+) {
+	if err := assertValidIdentityColumnDef(ctx, p, d, tableName); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	newSpec := *d
+
+	// Identity columns are implicitly NOT NULL, as in PostgreSQL.
+	newSpec.Nullable.Nullability = tree.NotNull
+
+	log.VEventf(ctx, 2, "creating sequence for new identity column %q of %q", d, tableName)
+
+	dbDesc, seqName, err := p.makeColumnSequenceName(ctx, d, tableName)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	newSpec.DefaultExpr.Expr = makeNextvalExpr(seqName)
+
+	return &newSpec, dbDesc, seqName, d.GeneratedIdentity.SeqOptions, nil
+}
+
+// makeColumnSequenceName generates the name of a new sequence backing the
+// given column, along with the descriptor of the database in which the
+// sequence must be created. The name is not taken by any existing object.
+func (p *planner) makeColumnSequenceName(
+	ctx context.Context, d *tree.ColumnTableDef, tableName *TableName,
+) (*sqlbase.ImmutableDatabaseDescriptor, *TableName, error) {
 	// We want a sequence; for this we need to generate a new sequence name.
 	// The constraint on the name is that an object of this name must not exist already.
 	seqName := tree.NewUnqualifiedTableName(
@@ -126,7 +188,7 @@ func (p *planner) processSerialInColumnDef(
 	un := seqName.ToUnresolvedObjectName()
 	dbDesc, prefix, err := p.ResolveUncachedDatabase(ctx, un)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, err
 	}
 	seqName.ObjectNamePrefix = prefix
 
@@ -138,30 +200,22 @@ func (p *planner) processSerialInColumnDef(
 		}
 		res, err := p.ResolveUncachedTableDescriptor(ctx, seqName, false /*required*/, resolver.ResolveAnyDescType)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, err
 		}
 		if res == nil {
 			break
 		}
 	}
+	return dbDesc, seqName, nil
+}
 
-	defaultExpr := &tree.FuncExpr{
+// makeNextvalExpr returns the default expression of a column backed by the
+// given sequence.
+func makeNextvalExpr(seqName *TableName) tree.Expr {
+	return &tree.FuncExpr{
 		Func:  tree.WrapFunction("nextval"),
 		Exprs: tree.Exprs{tree.NewStrVal(seqName.String())},
 	}
-
-	seqType := ""
-	seqOpts := realSequenceOpts
-	if serialNormalizationMode == sessiondata.SerialUsesVirtualSequences {
-		seqType = "virtual "
-		seqOpts = virtualSequenceOpts
-	}
-	log.VEventf(ctx, 2, "new column %q of %q will have %s sequence name %q and default %q",
-		d, tableName, seqType, seqName, defaultExpr)
-
-	newSpec.DefaultExpr.Expr = defaultExpr
-
-	return &newSpec, dbDesc, seqName, seqOpts, nil
 }
 
 // SimplifySerialInColumnDefWithRowID analyzes a column definition and
@@ -223,3 +277,34 @@ func assertValidSerialColumnDef(d *tree.ColumnTableDef, tableName *TableName) er
 
 	return nil
 }
+
+func assertValidIdentityColumnDef(
+	ctx context.Context, p *planner, d *tree.ColumnTableDef, tableName *TableName,
+) error {
+	if d.IsSerial {
+		// SERIAL implies a default expression, which identity columns cannot
+		// have. This is the error produced by pg in such case.
+		return pgerror.Newf(pgcode.Syntax,
+			"both default and identity specified for column %q of table %q",
+			tree.ErrString(&d.Name), tree.ErrString(tableName))
+	}
+
+	defType, err := tree.ResolveType(ctx, d.Type, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+	if defType.Family() != types.IntFamily {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"identity column type must be smallint, integer, or bigint")
+	}
+
+	for _, opt := range d.GeneratedIdentity.SeqOptions {
+		switch opt.Name {
+		case tree.SeqOptOwnedBy, tree.SeqOptVirtual:
+			return pgerror.Newf(pgcode.Syntax,
+				"%s is not supported for the sequence of identity column %q of table %q",
+				opt.Name, tree.ErrString(&d.Name), tree.ErrString(tableName))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetConstraints sets the checking mode of deferrable constraints for the rest
+// of the transaction. As in Postgres, the deferred checks of the constraints
+// that become IMMEDIATE are run right away.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	d := p.extendedEvalCtx.DeferredConstraints
+	if d == nil || p.extendedEvalCtx.TxnImplicit {
+		p.SendClientNotice(ctx, pgnotice.Newf("SET CONSTRAINTS can only be used in transaction blocks"))
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	d.setMode(n)
+	if err := validateDeferredConstraints(
+		ctx,
+		d.takeImmediate(),
+		p.Tables(),
+		p.ExtendedEvalContext().InternalExecutor.(*InternalExecutor),
+		p.txn,
+		p.ExecCfg().Codec,
+	); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
@@ -13,7 +13,9 @@ package sql
 import (
 	"bytes"
 	"context"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -75,6 +77,9 @@ func ShowCreateTable(
 	if desc.Temporary {
 		f.WriteString("TEMP ")
 	}
+	if desc.IsForeignTable() {
+		f.WriteString("FOREIGN ")
+	}
 	f.WriteString("TABLE ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
@@ -161,8 +166,11 @@ func ShowCreateTable(
 		}
 	}
 
-	// Create the FAMILY and CONSTRAINTs of the CREATE statement
-	showFamilyClause(desc, f)
+	// Create the FAMILY and CONSTRAINTs of the CREATE statement. The single
+	// column family of a foreign table is implicit.
+	if !desc.IsForeignTable() {
+		showFamilyClause(desc, f)
+	}
 	if err := showConstraintClause(ctx, desc, &p.RunParams(ctx).p.semaCtx, f); err != nil {
 		return "", err
 	}
@@ -176,6 +184,18 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if desc.IsForeignTable() {
+		showForeignTableClause(desc, f)
+	}
+
+	if storageParams, err := rowLevelTTLStorageParams(desc.TableDesc()); err != nil {
+		return "", err
+	} else if len(storageParams) > 0 {
+		f.WriteString(" WITH (")
+		f.WriteString(strings.Join(storageParams, ", "))
+		f.WriteString(")")
+	}
+
 	if !displayOptions.IgnoreComments {
 		if err := showComments(desc, selectComment(ctx, p, desc.ID), &f.Buffer); err != nil {
 			return "", err
@@ -185,6 +205,27 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showForeignTableClause creates the SERVER and OPTIONS clauses of a CREATE
+// FOREIGN TABLE statement.
+func showForeignTableClause(desc *sqlbase.ImmutableTableDescriptor, f *tree.FmtCtx) {
+	f.WriteString(" SERVER ")
+	f.FormatNameP(&desc.ForeignTable.Server)
+	if len(desc.ForeignTable.Options) == 0 {
+		return
+	}
+	f.WriteString(" OPTIONS (")
+	for i, opt := range desc.ForeignTable.Options {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		kv := strings.SplitN(opt, "=", 2)
+		f.FormatNameP(&kv[0])
+		f.WriteByte(' ')
+		lex.EncodeSQLString(&f.Buffer, kv[len(kv)-1])
+	}
+	f.WriteString(")")
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
@@ -129,6 +129,37 @@ func (desc *MutableDatabaseDescriptor) SetName(name string) {
 	desc.Name = name
 }
 
+// FindFunction returns the ID of the function with the given name in the
+// schema with the given ID, and whether such a function exists.
+func (desc *DatabaseDescriptor) FindFunction(schemaID ID, name string) (ID, bool) {
+	for i := range desc.Functions {
+		if fn := &desc.Functions[i]; fn.SchemaID == schemaID && fn.Name == name {
+			return fn.ID, true
+		}
+	}
+	return InvalidID, false
+}
+
+// AddFunction adds the name of the function with the given ID in the schema
+// with the given ID. The name must not be used by another function.
+func (desc *MutableDatabaseDescriptor) AddFunction(schemaID ID, name string, id ID) {
+	desc.Functions = append(desc.Functions, DatabaseDescriptor_FunctionEntry{
+		SchemaID: schemaID,
+		Name:     name,
+		ID:       id,
+	})
+}
+
+// RemoveFunction removes the name of the function with the given ID.
+func (desc *MutableDatabaseDescriptor) RemoveFunction(id ID) {
+	for i := range desc.Functions {
+		if desc.Functions[i].ID == id {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+			return
+		}
+	}
+}
+
 // Validate validates that the database descriptor is well formed.
 // Checks include validate the database name, and verifying that there
 // is at least one read and write user.
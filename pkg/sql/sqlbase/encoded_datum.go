@@ -289,7 +289,7 @@ func (ed *EncDatum) Fingerprint(typ *types.T, a *DatumAlloc, appendTo []byte) ([
 	// case uses ed.Encode, which has a fast path if the encoded bytes are already
 	// the right encoding.
 	switch typ.Family() {
-	case types.JsonFamily:
+	case types.JsonFamily, types.TSQueryFamily, types.TSVectorFamily:
 		if err := ed.EnsureDecoded(typ, a); err != nil {
 			return nil, err
 		}
@@ -137,6 +137,8 @@ func MakeObjectAlreadyExistsError(collidingObject *Descriptor, name string) erro
 		return NewRelationAlreadyExistsError(name)
 	case *Descriptor_Type:
 		return NewTypeAlreadyExistsError(name)
+	case *Descriptor_Function:
+		return NewFunctionAlreadyExistsError(name)
 	case *Descriptor_Database:
 		return NewDatabaseAlreadyExistsError(name)
 	case *Descriptor_Schema:
@@ -157,6 +159,11 @@ func NewTypeAlreadyExistsError(name string) error {
 	return pgerror.Newf(pgcode.DuplicateObject, "type %q already exists", name)
 }
 
+// NewFunctionAlreadyExistsError creates an error for a preexisting function.
+func NewFunctionAlreadyExistsError(name string) error {
+	return pgerror.Newf(pgcode.DuplicateFunction, "function %q already exists", name)
+}
+
 // IsRelationAlreadyExistsError checks whether this is an error for a preexisting relation.
 func IsRelationAlreadyExistsError(err error) bool {
 	return errHasCode(err, pgcode.DuplicateRelation)
@@ -0,0 +1,240 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// FunctionDescriptorInterface will eventually be called funcdesc.Descriptor.
+// It is implemented by (Imm|M)utableFunctionDescriptor.
+type FunctionDescriptorInterface interface {
+	BaseDescriptorInterface
+	FunctionDesc() *FunctionDescriptor
+}
+
+var _ FunctionDescriptorInterface = (*ImmutableFunctionDescriptor)(nil)
+var _ FunctionDescriptorInterface = (*MutableFunctionDescriptor)(nil)
+
+// ImmutableFunctionDescriptor is a custom type for wrapping
+// FunctionDescriptors when used in a read only way.
+type ImmutableFunctionDescriptor struct {
+	FunctionDescriptor
+}
+
+// MutableFunctionDescriptor is a custom type for FunctionDescriptors
+// undergoing any types of modifications.
+type MutableFunctionDescriptor struct {
+	ImmutableFunctionDescriptor
+
+	// ClusterVersion represents the version of the function descriptor read
+	// from the store.
+	ClusterVersion *ImmutableFunctionDescriptor
+}
+
+// NewImmutableFunctionDescriptor returns an ImmutableFunctionDescriptor from
+// the given FunctionDescriptor.
+func NewImmutableFunctionDescriptor(desc FunctionDescriptor) *ImmutableFunctionDescriptor {
+	return &ImmutableFunctionDescriptor{FunctionDescriptor: desc}
+}
+
+// NewMutableCreatedFunctionDescriptor returns a MutableFunctionDescriptor
+// from the given function descriptor with the cluster version being the zero
+// function. This is for a function that is created in the same transaction.
+func NewMutableCreatedFunctionDescriptor(desc FunctionDescriptor) *MutableFunctionDescriptor {
+	return &MutableFunctionDescriptor{
+		ImmutableFunctionDescriptor: ImmutableFunctionDescriptor{FunctionDescriptor: desc},
+	}
+}
+
+// NewMutableExistingFunctionDescriptor returns a MutableFunctionDescriptor
+// from the given function descriptor with the cluster version also set to the
+// descriptor. This is for functions that already exist.
+func NewMutableExistingFunctionDescriptor(desc FunctionDescriptor) *MutableFunctionDescriptor {
+	return &MutableFunctionDescriptor{
+		ImmutableFunctionDescriptor: ImmutableFunctionDescriptor{
+			FunctionDescriptor: *protoutil.Clone(&desc).(*FunctionDescriptor),
+		},
+		ClusterVersion: NewImmutableFunctionDescriptor(desc),
+	}
+}
+
+// NewDefaultFunctionPrivilegeDescriptor returns the privilege descriptor of a
+// new function: in addition to the default privileges, the public role may
+// execute the function, as in Postgres.
+func NewDefaultFunctionPrivilegeDescriptor() *PrivilegeDescriptor {
+	p := NewDefaultPrivilegeDescriptor()
+	p.Grant(PublicRole, privilege.List{privilege.EXECUTE})
+	return p
+}
+
+// NameResolutionResult implements the NameResolutionResult interface.
+func (desc *ImmutableFunctionDescriptor) NameResolutionResult() {}
+
+// DatabaseDesc implements the ObjectDescriptor interface.
+func (desc *ImmutableFunctionDescriptor) DatabaseDesc() *DatabaseDescriptor {
+	return nil
+}
+
+// SchemaDesc implements the ObjectDescriptor interface.
+func (desc *ImmutableFunctionDescriptor) SchemaDesc() *SchemaDescriptor {
+	return nil
+}
+
+// TableDesc implements the ObjectDescriptor interface.
+func (desc *ImmutableFunctionDescriptor) TableDesc() *TableDescriptor {
+	return nil
+}
+
+// TypeDesc implements the ObjectDescriptor interface.
+func (desc *ImmutableFunctionDescriptor) TypeDesc() *TypeDescriptor {
+	return nil
+}
+
+// FunctionDesc returns the underlying function descriptor.
+func (desc *ImmutableFunctionDescriptor) FunctionDesc() *FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// DescriptorProto returns a Descriptor for serialization.
+func (desc *FunctionDescriptor) DescriptorProto() *Descriptor {
+	return &Descriptor{
+		Union: &Descriptor_Function{
+			Function: desc,
+		},
+	}
+}
+
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// TypeName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// ArgTypes returns the types of the arguments of the overload.
+func (o *FunctionDescriptor_Overload) ArgTypes() tree.ArgTypes {
+	argTypes := make(tree.ArgTypes, len(o.Args))
+	for i := range o.Args {
+		name := o.Args[i].Name
+		if name == "" {
+			name = tree.PlaceholderIdx(i).String()
+		}
+		argTypes[i] = struct {
+			Name string
+			Typ  *types.T
+		}{Name: name, Typ: o.Args[i].Type}
+	}
+	return argTypes
+}
+
+// MatchesArgTypes returns whether the overload accepts exactly the given
+// argument types.
+func (o *FunctionDescriptor_Overload) MatchesArgTypes(typs []*types.T) bool {
+	if len(o.Args) != len(typs) {
+		return false
+	}
+	for i := range o.Args {
+		if !o.Args[i].Type.Equivalent(typs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Signature returns a human-readable signature of the overload, like
+// f(INT8, STRING).
+func (o *FunctionDescriptor_Overload) Signature(name string) string {
+	buf := tree.NewFmtCtx(tree.FmtSimple)
+	buf.WriteString(name)
+	buf.WriteByte('(')
+	for i := range o.Args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(o.Args[i].Type.SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.CloseAndGetString()
+}
+
+// Dependencies returns the IDs of the relations that any overload of the
+// function depends on, without duplicates.
+func (desc *FunctionDescriptor) Dependencies() []ID {
+	var ids []ID
+	seen := make(map[ID]struct{})
+	for i := range desc.Overloads {
+		for _, id := range desc.Overloads[i].DependsOn {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// FindOverload returns the index of the overload with the given argument
+// types, or -1 if there is none.
+func (desc *FunctionDescriptor) FindOverload(argTypes []*types.T) int {
+	for i := range desc.Overloads {
+		if desc.Overloads[i].MatchesArgTypes(argTypes) {
+			return i
+		}
+	}
+	return -1
+}
+
+// TriggerOverload returns the overload of the function which can be executed
+// by a trigger, or nil if there is none.
+func (desc *FunctionDescriptor) TriggerOverload() *FunctionDescriptor_Overload {
+	for i := range desc.Overloads {
+		if desc.Overloads[i].Trigger {
+			return &desc.Overloads[i]
+		}
+	}
+	return nil
+}
+
+// AddOverload adds the overload to the function, or replaces the existing
+// overload with the same argument types if replace is set.
+func (desc *MutableFunctionDescriptor) AddOverload(
+	overload FunctionDescriptor_Overload, replace bool,
+) error {
+	argTypes := make([]*types.T, len(overload.Args))
+	for i := range overload.Args {
+		argTypes[i] = overload.Args[i].Type
+	}
+	if i := desc.FindOverload(argTypes); i != -1 {
+		existing := &desc.Overloads[i]
+		if !replace {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %s already exists with same argument types", existing.Signature(desc.Name))
+		}
+		if existing.Trigger != overload.Trigger ||
+			!existing.ReturnType.Equivalent(overload.ReturnType) {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change return type of existing function %s", existing.Signature(desc.Name))
+		}
+		*existing = overload
+		return nil
+	}
+	desc.Overloads = append(desc.Overloads, overload)
+	return nil
+}
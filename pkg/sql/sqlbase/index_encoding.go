@@ -278,12 +278,17 @@ func NeededColumnFamilyIDs(
 	// columns which can be decoded from the key and columns whose value is stored
 	// in family 0.
 	family0Needed := false
+	virtualNeeded := false
 	nc := neededCols.Copy()
 	neededCols.ForEach(func(columnOrdinal int) {
 		if indexedCols.Contains(columnOrdinal) && !compositeCols.Contains(columnOrdinal) {
 			// We can decode this column from the index key, so no particular family
 			// is needed.
 			nc.Remove(columnOrdinal)
+		} else if columns[columnOrdinal].Virtual {
+			// The column is computed from other columns, which could be in any
+			// family.
+			virtualNeeded = true
 		}
 		if hasSecondaryEncoding && (compositeCols.Contains(columnOrdinal) ||
 			extraCols.Contains(columnOrdinal)) {
@@ -293,6 +298,13 @@ func NeededColumnFamilyIDs(
 			nc.Remove(columnOrdinal)
 		}
 	})
+	if virtualNeeded {
+		neededFamilyIDs := make([]FamilyID, len(table.Families))
+		for i := range table.Families {
+			neededFamilyIDs[i] = table.Families[i].ID
+		}
+		return neededFamilyIDs
+	}
 
 	// Iterate over the column families to find which ones contain needed columns.
 	// We also keep track of whether all of the needed families' columns are
@@ -847,8 +859,9 @@ func EncodeInvertedIndexKeys(
 }
 
 // EncodeInvertedIndexTableKeys produces one inverted index key per element in
-// the input datum, which should be a container (either JSON or Array). For
-// JSON, "element" means unique path through the document. Each output key is
+// the input datum, which should be a container (either JSON, Array or
+// TSVector). For JSON, "element" means unique path through the document, and
+// for TSVector it means lexeme. Each output key is
 // prefixed by inKey, and is guaranteed to be lexicographically sortable, but
 // not guaranteed to be round-trippable during decoding. If the input Datum
 // is (SQL) NULL, no inverted index keys will be produced, because inverted
@@ -863,6 +876,8 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 		return json.EncodeInvertedIndexKeys(inKey, val.(*tree.DJSON).JSON)
 	case types.ArrayFamily:
 		return encodeArrayInvertedIndexTableKeys(val.(*tree.DArray), inKey)
+	case types.TSVectorFamily:
+		return encodeTSVectorInvertedIndexTableKeys(val.(*tree.DTSVector), inKey), nil
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType())
 }
@@ -893,6 +908,20 @@ func encodeArrayInvertedIndexTableKeys(val *tree.DArray, inKey []byte) (key [][]
 	return outKeys, nil
 }
 
+// encodeTSVectorInvertedIndexTableKeys returns a list of inverted index keys
+// for the given input tsvector, one per lexeme. The positions of the lexemes
+// are not part of the keys. The input inKey is prefixed to all returned keys.
+func encodeTSVectorInvertedIndexTableKeys(val *tree.DTSVector, inKey []byte) [][]byte {
+	outKeys := make([][]byte, 0, len(val.TSVector))
+	for _, l := range val.TSVector {
+		outKey := make([]byte, len(inKey), len(inKey)+len(l.Text)+2)
+		copy(outKey, inKey)
+		outKeys = append(outKeys, encoding.EncodeStringAscending(outKey, l.Text))
+	}
+	// The lexemes of a tsvector are sorted and distinct, so the keys are too.
+	return outKeys
+}
+
 // EncodeGeoInvertedIndexTableKeys is the equivalent of EncodeInvertedIndexTableKeys
 // for Geography and Geometry.
 func EncodeGeoInvertedIndexTableKeys(
@@ -154,10 +154,11 @@ func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
 
 	if isPrivilegeSet(userPriv.Privileges, privilege.ALL) {
 		// User has 'ALL' privilege. Remove it and set
-		// all other privileges one.
+		// all other privileges one. EXECUTE only applies to functions, and is
+		// not implied on other objects.
 		userPriv.Privileges = 0
 		for _, v := range privilege.ByValue {
-			if v != privilege.ALL {
+			if v != privilege.ALL && v != privilege.EXECUTE {
 				userPriv.Privileges |= v.Mask()
 			}
 		}
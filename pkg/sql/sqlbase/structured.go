@@ -696,6 +696,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns whether or not this TableDescriptor is a
+// MaterializedView.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsAs returns true if the TableDescriptor actually describes
 // a Table resource with an As source.
 func (desc *TableDescriptor) IsAs() bool {
@@ -708,6 +714,12 @@ func (desc *TableDescriptor) IsSequence() bool {
 	return desc.SequenceOpts != nil
 }
 
+// IsForeignTable returns true if the TableDescriptor describes a foreign
+// table, whose rows are read from files in external storage.
+func (desc *TableDescriptor) IsForeignTable() bool {
+	return desc.ForeignTable != nil
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -739,9 +751,9 @@ func IsVirtualTable(id ID) bool {
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
 // Sequences count as physical tables because their values are stored in
-// the KV layer.
+// the KV layer, as do materialized views because their results are.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable()) || desc.MaterializedView()
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -1464,7 +1476,8 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		default:
 			return MustBeValueEncoded(semanticType.ArrayContents())
 		}
-	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
+	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily,
+		types.TSQueryFamily, types.TSVectorFamily:
 		return true
 	}
 	return false
@@ -1532,6 +1545,21 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 			}
 		}
 
+		if index == &desc.PrimaryIndex {
+			// Virtual columns are computed from the primary index, so they cannot
+			// be part of it.
+			for _, colID := range index.ColumnIDs {
+				col, err := desc.FindColumnByID(colID)
+				if err != nil {
+					return err
+				}
+				if col.Virtual {
+					return pgerror.Newf(pgcode.InvalidTableDefinition,
+						"virtual column %q cannot be part of the primary key", col.Name)
+				}
+			}
+		}
+
 		if index != &desc.PrimaryIndex && index.EncodingType == SecondaryIndexEncoding {
 			indexHasOldStoredColumns := index.HasOldStoredColumns()
 			// Need to clear ExtraColumnIDs and StoreColumnIDs because they are used
@@ -1551,6 +1579,10 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 				if err != nil {
 					return err
 				}
+				if col.Virtual {
+					return pgerror.Newf(pgcode.InvalidTableDefinition,
+						"index %q cannot store virtual column %q", index.Name, col.Name)
+				}
 				if desc.PrimaryIndex.ContainsColumnID(col.ID) {
 					// If the primary index contains a stored column, we don't need to
 					// store it - it's already part of the index.
@@ -1627,6 +1659,10 @@ func (desc *MutableTableDescriptor) allocateColumnFamilyIDs(columnNames map[stri
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.Virtual {
+			// Virtual columns are not stored, so they are not part of any family.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -2013,6 +2049,11 @@ func (desc *TableDescriptor) ValidateTable() error {
 				return errors.AssertionFailedf(
 					"computed column swap mutation in state %s, direction %s", errors.Safe(m.State), errors.Safe(m.Direction))
 			}
+		case *DescriptorMutation_MaterializedViewRefresh:
+			if m.Direction == DescriptorMutation_NONE {
+				return errors.AssertionFailedf(
+					"materialized view refresh mutation in state %s, direction %s", errors.Safe(m.State), errors.Safe(m.Direction))
+			}
 		default:
 			return errors.AssertionFailedf(
 				"mutation in state %s, direction %s, and no column/index descriptor",
@@ -2157,7 +2198,15 @@ func (desc *TableDescriptor) validateColumnFamilies(columnIDs map[ColumnID]strin
 		}
 	}
 	for colID := range columnIDs {
-		if _, ok := colIDToFamilyID[colID]; !ok {
+		col, err := desc.FindColumnByID(colID)
+		if err != nil {
+			return err
+		}
+		_, inFamily := colIDToFamilyID[colID]
+		if col.Virtual && inFamily {
+			return fmt.Errorf("virtual column %d is in family %d", colID, colIDToFamilyID[colID])
+		}
+		if !col.Virtual && !inFamily {
 			return fmt.Errorf("column %d is not in any column family", colID)
 		}
 	}
@@ -2491,7 +2540,8 @@ func ColumnTypeIsIndexable(t *types.T) bool {
 func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 	family := t.Family()
 	return family == types.JsonFamily || family == types.ArrayFamily ||
-		family == types.GeographyFamily || family == types.GeometryFamily
+		family == types.GeographyFamily || family == types.GeometryFamily ||
+		family == types.TSVectorFamily
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
@@ -2907,6 +2957,38 @@ func (desc *TableDescriptor) FindCheckByName(
 	return nil, fmt.Errorf("check %q does not exist", name)
 }
 
+// FindTriggerByName returns the trigger with the specified name, or nil if
+// there is no such trigger.
+func (desc *TableDescriptor) FindTriggerByName(name string) *TriggerDescriptor {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			return &desc.Triggers[i]
+		}
+	}
+	return nil
+}
+
+// AddTrigger adds a trigger to the table. The triggers are kept sorted by
+// name, which is the order in which they are fired.
+func (desc *MutableTableDescriptor) AddTrigger(trigger TriggerDescriptor) {
+	i := sort.Search(len(desc.Triggers), func(i int) bool {
+		return desc.Triggers[i].Name >= trigger.Name
+	})
+	desc.Triggers = append(desc.Triggers, TriggerDescriptor{})
+	copy(desc.Triggers[i+1:], desc.Triggers[i:])
+	desc.Triggers[i] = trigger
+}
+
+// RemoveTrigger removes the trigger with the specified name from the table.
+func (desc *MutableTableDescriptor) RemoveTrigger(name string) {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			desc.Triggers = append(desc.Triggers[:i], desc.Triggers[i+1:]...)
+			return
+		}
+	}
+}
+
 // NamesForColumnIDs returns the names for the given column ids, or an error
 // if one or more column ids was missing. Note - this allocates! It's not for
 // hot path code.
@@ -3353,6 +3435,11 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 			if err := desc.performComputedColumnSwap(t.ComputedColumnSwap); err != nil {
 				return err
 			}
+		case *DescriptorMutation_MaterializedViewRefresh:
+			// Completing a refresh mutation just means overwriting the view's
+			// indexes with the new indexes that have already been backfilled.
+			desc.PrimaryIndex = t.MaterializedViewRefresh.NewPrimaryIndex
+			desc.Indexes = t.MaterializedViewRefresh.NewIndexes
 		}
 
 	case DescriptorMutation_DROP:
@@ -3603,6 +3690,18 @@ func (desc *MutableTableDescriptor) AddComputedColumnSwapMutation(swap *Computed
 	desc.addMutation(m)
 }
 
+// AddMaterializedViewRefreshMutation adds a MaterializedViewRefreshMutation to
+// the table descriptor.
+func (desc *MutableTableDescriptor) AddMaterializedViewRefreshMutation(
+	refresh *MaterializedViewRefresh,
+) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_MaterializedViewRefresh{MaterializedViewRefresh: refresh},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
 func (desc *MutableTableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -3781,6 +3880,8 @@ func (desc *Descriptor) GetID() ID {
 		return t.Database.ID
 	case *Descriptor_Type:
 		return t.Type.ID
+	case *Descriptor_Function:
+		return t.Function.ID
 	case *Descriptor_Schema:
 		return t.Schema.ID
 	default:
@@ -3797,6 +3898,8 @@ func (desc *Descriptor) GetName() string {
 		return t.Database.Name
 	case *Descriptor_Type:
 		return t.Type.Name
+	case *Descriptor_Function:
+		return t.Function.Name
 	case *Descriptor_Schema:
 		return t.Schema.Name
 	default:
@@ -3813,6 +3916,8 @@ func (desc *Descriptor) GetVersion() DescriptorVersion {
 		return t.Database.Version
 	case *Descriptor_Type:
 		return t.Type.Version
+	case *Descriptor_Function:
+		return t.Function.Version
 	case *Descriptor_Schema:
 		return t.Schema.Version
 	default:
@@ -3829,6 +3934,8 @@ func (desc *Descriptor) GetModificationTime() hlc.Timestamp {
 		return t.Database.ModificationTime
 	case *Descriptor_Type:
 		return t.Type.ModificationTime
+	case *Descriptor_Function:
+		return t.Function.ModificationTime
 	case *Descriptor_Schema:
 		return t.Schema.ModificationTime
 	default:
@@ -3846,6 +3953,8 @@ func (desc *Descriptor) setModificationTime(ts hlc.Timestamp) {
 		t.Database.ModificationTime = ts
 	case *Descriptor_Type:
 		t.Type.ModificationTime = ts
+	case *Descriptor_Function:
+		t.Function.ModificationTime = ts
 	case *Descriptor_Schema:
 		t.Schema.ModificationTime = ts
 	default:
@@ -3997,14 +4106,22 @@ func (desc *ColumnDescriptor) SQLString() string {
 	} else {
 		f.WriteString(" NOT NULL")
 	}
-	if desc.DefaultExpr != nil {
+	if desc.IsGeneratedAlwaysAsIdentity() {
+		f.WriteString(" GENERATED ALWAYS AS IDENTITY")
+	} else if desc.IsGeneratedAsIdentity() {
+		f.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	} else if desc.DefaultExpr != nil {
 		f.WriteString(" DEFAULT ")
 		f.WriteString(*desc.DefaultExpr)
 	}
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -4218,6 +4335,22 @@ func (desc *ColumnDescriptor) IsComputed() bool {
 	return desc.ComputeExpr != nil
 }
 
+// IsVirtual returns whether the column is a virtual computed column, whose
+// values are not stored.
+func (desc *ColumnDescriptor) IsVirtual() bool {
+	return desc.Virtual
+}
+
+// IsGeneratedAsIdentity is part of the cat.Column interface.
+func (desc *ColumnDescriptor) IsGeneratedAsIdentity() bool {
+	return desc.GeneratedAsIdentityType != ColumnDescriptor_NOT_IDENTITY_COLUMN
+}
+
+// IsGeneratedAlwaysAsIdentity is part of the cat.Column interface.
+func (desc *ColumnDescriptor) IsGeneratedAlwaysAsIdentity() bool {
+	return desc.GeneratedAsIdentityType == ColumnDescriptor_GENERATED_ALWAYS
+}
+
 // DefaultExprStr is part of the cat.Column interface.
 func (desc *ColumnDescriptor) DefaultExprStr() string {
 	return *desc.DefaultExpr
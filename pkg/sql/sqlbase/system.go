@@ -41,6 +41,8 @@ func ShouldSplitAtDesc(rawDesc *roachpb.Value) bool {
 		return false
 	case *Descriptor_Type:
 		return false
+	case *Descriptor_Function:
+		return false
 	case *Descriptor_Schema:
 		return false
 	default:
@@ -331,6 +333,78 @@ CREATE TABLE system.scheduled_jobs (
        schedule_details, executor_type, execution_args, schedule_changes 
     )
 )`
+
+	// notifications holds the messages sent with NOTIFY until every node had a
+	// chance to deliver them to its listening sessions.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+  id      INT8 DEFAULT unique_rowid() PRIMARY KEY NOT NULL,
+  channel STRING NOT NULL,
+  payload STRING NOT NULL,
+  node_id INT8 NOT NULL,
+  created TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  FAMILY "primary" (id, channel, payload, node_id, created)
+)`
+
+	// publications holds the publications created with CREATE PUBLICATION,
+	// which determine the tables streamed to logical replication clients.
+	PublicationsTableSchema = `
+CREATE TABLE system.publications (
+  database_id INT8 NOT NULL,
+  name        STRING NOT NULL,
+  owner       STRING NOT NULL,
+  all_tables  BOOL NOT NULL,
+  table_ids   INT8[] NOT NULL,
+
+  PRIMARY KEY (database_id, name),
+  FAMILY "primary" (database_id, name, owner, all_tables, table_ids)
+)`
+
+	// replication_slots holds the logical replication slots. Each slot keeps
+	// the position up to which its client confirmed having received the
+	// changes, and the protected timestamp record preventing the history
+	// after that position from being garbage collected.
+	ReplicationSlotsTableSchema = `
+CREATE TABLE system.replication_slots (
+  slot_name           STRING NOT NULL PRIMARY KEY,
+  plugin              STRING NOT NULL,
+  database_id         INT8 NOT NULL,
+  owner               STRING NOT NULL,
+  confirmed_flush_lsn INT8 NOT NULL,
+  protected_ts_record UUID NOT NULL,
+  created             TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  FAMILY "primary" (slot_name, plugin, database_id, owner, confirmed_flush_lsn, protected_ts_record, created)
+)`
+
+	// foreign_data_wrappers holds the foreign data wrappers created with
+	// CREATE FOREIGN DATA WRAPPER. Their options, stored as key=value strings,
+	// are the defaults of the options of their servers and foreign tables.
+	ForeignDataWrappersTableSchema = `
+CREATE TABLE system.foreign_data_wrappers (
+  database_id INT8 NOT NULL,
+  name        STRING NOT NULL,
+  owner       STRING NOT NULL,
+  options     STRING[] NOT NULL,
+
+  PRIMARY KEY (database_id, name),
+  FAMILY "primary" (database_id, name, owner, options)
+)`
+
+	// foreign_servers holds the foreign servers created with CREATE SERVER,
+	// which point the foreign tables to their external storage location.
+	ForeignServersTableSchema = `
+CREATE TABLE system.foreign_servers (
+  database_id INT8 NOT NULL,
+  name        STRING NOT NULL,
+  wrapper     STRING NOT NULL,
+  owner       STRING NOT NULL,
+  options     STRING[] NOT NULL,
+
+  PRIMARY KEY (database_id, name),
+  FAMILY "primary" (database_id, name, wrapper, owner, options)
+)`
 )
 
 func pk(name string) IndexDescriptor {
@@ -386,6 +460,11 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.StatementDiagnosticsRequestsTableID:  privilege.ReadWriteData,
 	keys.StatementDiagnosticsTableID:          privilege.ReadWriteData,
 	keys.ScheduledJobsTableID:                 privilege.ReadWriteData,
+	keys.NotificationsTableID:                 privilege.ReadWriteData,
+	keys.PublicationsTableID:                  privilege.ReadWriteData,
+	keys.ReplicationSlotsTableID:              privilege.ReadWriteData,
+	keys.ForeignDataWrappersTableID:           privilege.ReadWriteData,
+	keys.ForeignServersTableID:                privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -1644,6 +1723,183 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	})
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = NewImmutableTableDescriptor(TableDescriptor{
+		Name:                    "notifications",
+		ID:                      keys.NotificationsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString, Nullable: false},
+			{Name: "channel", ID: 2, Type: types.String, Nullable: false},
+			{Name: "payload", ID: 3, Type: types.String, Nullable: false},
+			{Name: "node_id", ID: 4, Type: types.Int, Nullable: false},
+			{Name: "created", ID: 5, Type: types.TimestampTZ, DefaultExpr: &nowTZString, Nullable: false},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"id", "channel", "payload", "node_id", "created"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("id"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.NotificationsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// PublicationsTable is the descriptor for the publications table.
+	PublicationsTable = NewImmutableTableDescriptor(TableDescriptor{
+		Name:                    "publications",
+		ID:                      keys.PublicationsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "database_id", ID: 1, Type: types.Int, Nullable: false},
+			{Name: "name", ID: 2, Type: types.String, Nullable: false},
+			{Name: "owner", ID: 3, Type: types.String, Nullable: false},
+			{Name: "all_tables", ID: 4, Type: types.Bool, Nullable: false},
+			{Name: "table_ids", ID: 5, Type: types.IntArray, Nullable: false},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"database_id", "name", "owner", "all_tables", "table_ids"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"database_id", "name"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+			Version:          SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.PublicationsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// ReplicationSlotsTable is the descriptor for the replication slots table.
+	ReplicationSlotsTable = NewImmutableTableDescriptor(TableDescriptor{
+		Name:                    "replication_slots",
+		ID:                      keys.ReplicationSlotsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "slot_name", ID: 1, Type: types.String, Nullable: false},
+			{Name: "plugin", ID: 2, Type: types.String, Nullable: false},
+			{Name: "database_id", ID: 3, Type: types.Int, Nullable: false},
+			{Name: "owner", ID: 4, Type: types.String, Nullable: false},
+			{Name: "confirmed_flush_lsn", ID: 5, Type: types.Int, Nullable: false},
+			{Name: "protected_ts_record", ID: 6, Type: types.Uuid, Nullable: false},
+			{Name: "created", ID: 7, Type: types.TimestampTZ, DefaultExpr: &nowTZString, Nullable: false},
+		},
+		NextColumnID: 8,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ColumnNames: []string{"slot_name", "plugin", "database_id", "owner",
+					"confirmed_flush_lsn", "protected_ts_record", "created"},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("slot_name"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.ReplicationSlotsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// ForeignDataWrappersTable is the descriptor for the foreign data wrappers
+	// table.
+	ForeignDataWrappersTable = NewImmutableTableDescriptor(TableDescriptor{
+		Name:                    "foreign_data_wrappers",
+		ID:                      keys.ForeignDataWrappersTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "database_id", ID: 1, Type: types.Int, Nullable: false},
+			{Name: "name", ID: 2, Type: types.String, Nullable: false},
+			{Name: "owner", ID: 3, Type: types.String, Nullable: false},
+			{Name: "options", ID: 4, Type: types.StringArray, Nullable: false},
+		},
+		NextColumnID: 5,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"database_id", "name", "owner", "options"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"database_id", "name"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+			Version:          SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.ForeignDataWrappersTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// ForeignServersTable is the descriptor for the foreign servers table.
+	ForeignServersTable = NewImmutableTableDescriptor(TableDescriptor{
+		Name:                    "foreign_servers",
+		ID:                      keys.ForeignServersTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "database_id", ID: 1, Type: types.Int, Nullable: false},
+			{Name: "name", ID: 2, Type: types.String, Nullable: false},
+			{Name: "wrapper", ID: 3, Type: types.String, Nullable: false},
+			{Name: "owner", ID: 4, Type: types.String, Nullable: false},
+			{Name: "options", ID: 5, Type: types.StringArray, Nullable: false},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"database_id", "name", "wrapper", "owner", "options"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"database_id", "name"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+			Version:          SecondaryIndexFamilyFormatVersion,
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.ForeignServersTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
 )
 
 // addSystemDescriptorsToSchema populates the supplied MetadataSchema
@@ -1706,6 +1962,11 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	// Tables introduced in 20.2.
 
 	target.AddDescriptor(keys.SystemDatabaseID, ScheduledJobsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, NotificationsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, PublicationsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, ReplicationSlotsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, ForeignDataWrappersTable)
+	target.AddDescriptor(keys.SystemDatabaseID, ForeignServersTable)
 }
 
 // addSplitIDs adds a split point for each of the PseudoTableIDs to the supplied
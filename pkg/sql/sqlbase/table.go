@@ -106,7 +106,8 @@ func ValidateColumnDefType(t *types.T) error {
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
-		types.GeographyFamily, types.GeometryFamily, types.EnumFamily:
+		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.TSQueryFamily,
+		types.TSVectorFamily:
 		// These types are OK.
 
 	default:
@@ -143,6 +144,12 @@ func MakeColumnDefDescs(
 		return nil, nil, nil, pgerror.New(pgcode.FeatureNotSupported,
 			"SERIAL cannot be used in this context")
 	}
+	if d.IsGeneratedAsIdentity() && !d.HasDefaultExpr() {
+		// Likewise, the sequence of an identity column must be created by
+		// sql.processSerialInColumnDef() prior to this point.
+		return nil, nil, nil, pgerror.New(pgcode.FeatureNotSupported,
+			"identity columns cannot be used in this context")
+	}
 
 	if len(d.CheckExprs) > 0 {
 		// Should never happen since `HoistConstraints` moves these to table level
@@ -192,6 +199,16 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.IsVirtual()
+	}
+
+	if d.IsGeneratedAsIdentity() {
+		switch d.GeneratedIdentity.GeneratedAsIdentityType {
+		case tree.GeneratedAlways:
+			col.GeneratedAsIdentityType = ColumnDescriptor_GENERATED_ALWAYS
+		case tree.GeneratedByDefault:
+			col.GeneratedAsIdentityType = ColumnDescriptor_GENERATED_BY_DEFAULT
+		}
 	}
 
 	var idx *IndexDescriptor
@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -223,6 +224,19 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(tsearch.TSQuery{Root: randTSQueryNode(rng, 3)})
+	case types.TSVectorFamily:
+		var buf bytes.Buffer
+		for i, n := 0, rng.Intn(10); i < n; i++ {
+			fmt.Fprintf(&buf, "%s:%d%s ", randLexemes[rng.Intn(len(randLexemes))],
+				1+rng.Intn(tsearch.MaxPosition), tsearch.Weight(rng.Intn(4)))
+		}
+		d, err := tree.ParseDTSVector(buf.String())
+		if err != nil {
+			panic(err)
+		}
+		return d
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -383,6 +397,38 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 	return datum
 }
 
+// randLexemes are the lexemes of the random tsvector and tsquery datums.
+var randLexemes = []string{"a", "fat", "cat", "rat", "sat", "on", "mat"}
+
+// randTSQueryNode returns a random tsquery node of at most the given depth.
+func randTSQueryNode(rng *rand.Rand, depth int) *tsearch.Node {
+	if depth == 0 || rng.Intn(3) == 0 {
+		return &tsearch.Node{
+			Op:      tsearch.OpLexeme,
+			Lexeme:  randLexemes[rng.Intn(len(randLexemes))],
+			Prefix:  rng.Intn(4) == 0,
+			Weights: uint8(rng.Intn(16)),
+		}
+	}
+	switch op := tsearch.Operator(1 + rng.Intn(4)); op {
+	case tsearch.OpNot:
+		return &tsearch.Node{Op: op, Left: randTSQueryNode(rng, depth-1)}
+	case tsearch.OpPhrase:
+		return &tsearch.Node{
+			Op:       op,
+			Distance: uint16(rng.Intn(3)),
+			Left:     randTSQueryNode(rng, depth-1),
+			Right:    randTSQueryNode(rng, depth-1),
+		}
+	default:
+		return &tsearch.Node{
+			Op:    op,
+			Left:  randTSQueryNode(rng, depth-1),
+			Right: randTSQueryNode(rng, depth-1),
+		}
+	}
+}
+
 func randStringSimple(rng *rand.Rand) string {
 	return string('A' + rng.Intn(simpleRange))
 }
@@ -676,6 +722,36 @@ var (
 			}
 			return res
 		}(),
+		types.TSQueryFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`a`,
+				`'fat' & !'cat' | 'rat':*AB <2> 'it''s'`,
+			} {
+				d, err := tree.ParseDTSQuery(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
+		types.TSVectorFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`a`,
+				`'fat':2,4 'cat':3A,1B 'rat':5C 'it''s'`,
+			} {
+				d, err := tree.ParseDTSVector(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
 		types.BitFamily: func() []tree.Datum {
 			var res []tree.Datum
 			for _, i := range []int64{
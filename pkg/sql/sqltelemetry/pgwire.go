@@ -44,3 +44,7 @@ var InterleavedPortalRequestCounter = telemetry.GetCounterOnce("pgwire.#40195.in
 // PortalWithLimitRequestCounter is to be incremented every time a portal request is
 // made.
 var PortalWithLimitRequestCounter = telemetry.GetCounterOnce("pgwire.portal_with_limit_request")
+
+// ReplicationStreamCounter is to be incremented every time a client starts
+// a logical replication stream.
+var ReplicationStreamCounter = telemetry.GetCounterOnce("pgwire.replication.stream")
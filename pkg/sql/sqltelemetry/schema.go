@@ -92,6 +92,12 @@ func SchemaChangeDropCounter(typ string) telemetry.Counter {
 	return telemetry.GetCounter("sql.schema.drop_" + typ)
 }
 
+// SchemaChangeRefreshCounter is to be incremented every time a REFRESH
+// schema change was made.
+func SchemaChangeRefreshCounter(typ string) telemetry.Counter {
+	return telemetry.GetCounter("sql.schema.refresh_" + typ)
+}
+
 // SchemaSetZoneConfigCounter is to be incremented every time a ZoneConfig
 // argument is parsed.
 func SchemaSetZoneConfigCounter(configName, keyChange string) telemetry.Counter {
@@ -127,6 +133,12 @@ func SchemaJobControlCounter(desiredStatus string) telemetry.Counter {
 	return telemetry.GetCounter("sql.schema.job.control." + desiredStatus)
 }
 
+// ScheduleControlCounter is to be incremented every time a schedule control
+// action is taken.
+func ScheduleControlCounter(command string) telemetry.Counter {
+	return telemetry.GetCounter("sql.schedule.control." + command)
+}
+
 // SchemaChangeInExplicitTxnCounter is to be incremented every time a schema change
 // is scheduled using an explicit transaction.
 var SchemaChangeInExplicitTxnCounter = telemetry.GetCounterOnce("sql.schema.change_in_explicit_txn")
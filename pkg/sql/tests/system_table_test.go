@@ -182,6 +182,11 @@ func TestSystemTableLiterals(t *testing.T) {
 		{keys.StatementDiagnosticsRequestsTableID, sqlbase.StatementDiagnosticsRequestsTableSchema, sqlbase.StatementDiagnosticsRequestsTable},
 		{keys.StatementDiagnosticsTableID, sqlbase.StatementDiagnosticsTableSchema, sqlbase.StatementDiagnosticsTable},
 		{keys.ScheduledJobsTableID, sqlbase.ScheduledJobsTableSchema, sqlbase.ScheduledJobsTable},
+		{keys.NotificationsTableID, sqlbase.NotificationsTableSchema, sqlbase.NotificationsTable},
+		{keys.PublicationsTableID, sqlbase.PublicationsTableSchema, sqlbase.PublicationsTable},
+		{keys.ReplicationSlotsTableID, sqlbase.ReplicationSlotsTableSchema, sqlbase.ReplicationSlotsTable},
+		{keys.ForeignDataWrappersTableID, sqlbase.ForeignDataWrappersTableSchema, sqlbase.ForeignDataWrappersTable},
+		{keys.ForeignServersTableID, sqlbase.ForeignServersTableSchema, sqlbase.ForeignServersTable},
 	} {
 		privs := *test.pkg.Privileges
 		gen, err := sql.CreateTestTableDescriptor(
@@ -21,6 +21,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -69,6 +71,10 @@ func (t *truncateNode) startExec(params runParams) error {
 		if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 			return err
 		}
+		if tableDesc.IsForeignTable() {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"cannot truncate foreign table %q", tableDesc.Name)
+		}
 
 		toTruncate[tableDesc.ID] = tn.FQString()
 		toTraverse = append(toTraverse, *tableDesc)
@@ -251,6 +257,9 @@ func (p *planner) truncateTable(
 	// as the commit timestamp for the new descriptor. See the comment on
 	// sqlbase.Descriptor.Table().
 	newTableDesc.ModificationTime = hlc.Timestamp{}
+	if err := p.reassignRowLevelTTLSchedule(ctx, newTableDesc); err != nil {
+		return err
+	}
 	if err := p.createDescriptorWithID(
 		ctx, key, newID, newTableDesc, p.ExtendedEvalContext().Settings,
 		fmt.Sprintf("creating new descriptor %d for truncated table %s with id %d",
@@ -259,6 +268,11 @@ func (p *planner) truncateTable(
 		return err
 	}
 
+	// The trigger functions now depend on the new table.
+	if err := p.updateTriggerBackReferences(ctx, newTableDesc, true /* add */); err != nil {
+		return err
+	}
+
 	// Reassign comments on the table, columns and indexes.
 	if err := reassignComments(ctx, p, tableDesc, newTableDesc); err != nil {
 		return err
@@ -87,6 +87,8 @@ import (
 // | TIMETZ            | TIMETZ         | T_timetz      | 0         | 0     |
 // | JSON              | JSONB          | T_jsonb       | 0         | 0     |
 // | JSONB             | JSONB          | T_jsonb       | 0         | 0     |
+// | TSQUERY           | TSQUERY        | T_tsquery     | 0         | 0     |
+// | TSVECTOR          | TSVECTOR       | T_tsvector    | 0         | 0     |
 // |                   |                |               |           |       |
 // | BYTES             | BYTES          | T_bytea       | 0         | 0     |
 // |                   |                |               |           |       |
@@ -399,6 +401,16 @@ var (
 	Jsonb = &T{InternalType: InternalType{
 		Family: JsonFamily, Oid: oid.T_jsonb, Locale: &emptyLocale}}
 
+	// TSQuery is the type of a full-text search query, which is a tree of
+	// lexemes combined with boolean and phrase operators.
+	TSQuery = &T{InternalType: InternalType{
+		Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}
+
+	// TSVector is the type of a document optimized for full-text search, which
+	// is a sorted list of distinct lexemes with their positions.
+	TSVector = &T{InternalType: InternalType{
+		Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}
+
 	// Uuid is the type of a universally unique identifier (UUID), which is a
 	// 128-bit quantity that is very unlikely to ever be generated again, and so
 	// can be relied on to be distinct from all other UUID values.
@@ -459,6 +471,8 @@ var (
 		TimeTZ,
 		Jsonb,
 		VarBit,
+		TSQuery,
+		TSVector,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -1217,6 +1231,8 @@ var familyNames = map[Family]string{
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
 	TimeTZFamily:         "timetz",
+	TSQueryFamily:        "tsquery",
+	TSVectorFamily:       "tsvector",
 	TupleFamily:          "tuple",
 	UnknownFamily:        "unknown",
 	UuidFamily:           "uuid",
@@ -1531,6 +1547,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
 		return "record"
 	case UnknownFamily:
@@ -2546,8 +2566,10 @@ var unreservedTypeTokens = map[string]*T{
 	"smallserial": &Serial2Type,
 	"bigserial":   &Serial8Type,
 
-	"string": String,
-	"uuid":   Uuid,
+	"string":   String,
+	"tsquery":  TSQuery,
+	"tsvector": TSVector,
+	"uuid":     Uuid,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2565,8 +2587,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"money":         -1,
 	"path":          21286,
 	"pg_lsn":        -1,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
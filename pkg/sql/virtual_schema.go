@@ -241,6 +241,7 @@ func (v virtualSchemaView) initVirtualTableDesc(
 		nil,   /* semaCtx */
 		nil,   /* evalCtx */
 		false, /* temporary */
+		false, /* isMaterialized */
 	)
 	return mutDesc.TableDescriptor, err
 }
@@ -333,6 +333,27 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionAddScheduledJobsTable),
 		newDescriptorIDs:    staticIDs(keys.ScheduledJobsTableID),
 	},
+	{
+		// Introduced in v20.2.
+		name:                "create new system.notifications table",
+		workFn:              createNotificationsTable,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionListenNotify),
+		newDescriptorIDs:    staticIDs(keys.NotificationsTableID),
+	},
+	{
+		// Introduced in v20.2.
+		name:                "create new system.publications and system.replication_slots tables",
+		workFn:              createLogicalReplicationTables,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionLogicalReplication),
+		newDescriptorIDs:    staticIDs(keys.PublicationsTableID, keys.ReplicationSlotsTableID),
+	},
+	{
+		// Introduced in v20.2.
+		name:                "create new system.foreign_data_wrappers and system.foreign_servers tables",
+		workFn:              createForeignDataWrapperTables,
+		includedInBootstrap: clusterversion.VersionByKey(clusterversion.VersionForeignDataWrappers),
+		newDescriptorIDs:    staticIDs(keys.ForeignDataWrappersTableID, keys.ForeignServersTableID),
+	},
 }
 
 func staticIDs(
@@ -1893,3 +1914,21 @@ STORING (status)
 func createScheduledJobsTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.ScheduledJobsTable)
 }
+
+func createNotificationsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.NotificationsTable)
+}
+
+func createLogicalReplicationTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, sqlbase.PublicationsTable); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, sqlbase.ReplicationSlotsTable)
+}
+
+func createForeignDataWrapperTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, sqlbase.ForeignDataWrappersTable); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, sqlbase.ForeignServersTable)
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/errors"
+)
+
+// KMS provides an interface to a key management service holding a master key,
+// which is used to wrap (encrypt) the data keys protecting other data, for
+// example that of backups. The master key itself never leaves the KMS.
+type KMS interface {
+	// MasterKeyID returns an identifier of the master key. It must be stable
+	// across instances of the KMS using the same key, as it is recorded along
+	// with the data keys wrapped by the master key to find them again.
+	MasterKeyID() (string, error)
+	// Encrypt encrypts the plaintext using the master key.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Decrypt decrypts a ciphertext produced by Encrypt using the master key.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+	// Close releases any resources held by the KMS.
+	Close() error
+}
+
+// KMSFromURIFactory describes a factory function for KMS given a URI.
+type KMSFromURIFactory func(uri string, settings *cluster.Settings) (KMS, error)
+
+var kmsFactoryMap = make(map[string]KMSFromURIFactory)
+
+// RegisterKMSFromURIFactory registers the factory of the KMS implementation
+// handling URIs of the given scheme. It is intended to be called from init
+// functions.
+func RegisterKMSFromURIFactory(factory KMSFromURIFactory, scheme string) {
+	if _, ok := kmsFactoryMap[scheme]; ok {
+		panic(errors.AssertionFailedf("KMS factory for scheme %q already registered", scheme))
+	}
+	kmsFactoryMap[scheme] = factory
+}
+
+// KMSFromURI returns a KMS for the given URI using the factory registered for
+// its scheme.
+func KMSFromURI(uri string, settings *cluster.Settings) (KMS, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	factory, ok := kmsFactoryMap[parsed.Scheme]
+	if !ok {
+		return nil, errors.Errorf("unsupported KMS scheme: %q", parsed.Scheme)
+	}
+	return factory(uri, settings)
+}
@@ -416,6 +416,13 @@ type Engine interface {
 	// that the key range is compacted all the way to the bottommost level of
 	// SSTables, which is necessary to pick up changes to bloom filters.
 	CompactRange(start, end roachpb.Key, forceBottommost bool) error
+	// CompactEncryptedBefore compacts the key spans of all sstables encrypted
+	// under a data key, other than the active one, which was created before the
+	// given time, so that they are rewritten under the active data key. It
+	// returns the number of such sstables found. This bounds the age of the
+	// keys protecting data at rest, which would otherwise only age out as
+	// files happen to be compacted.
+	CompactEncryptedBefore(createdBefore time.Time) (int, error)
 	// InMem returns true if the receiver is an in-memory engine and false
 	// otherwise.
 	//
@@ -516,6 +523,9 @@ type EnvStats struct {
 	ActiveKeyFiles uint64
 	// ActiveKeyBytes is the size of files using the active data key.
 	ActiveKeyBytes uint64
+	// KeyStats breaks the files down by the key encrypting them, including the
+	// active data key and "plain" for unencrypted files.
+	KeyStats []EncryptionKeyStats
 	// EncryptionType is an enum describing the active encryption algorithm.
 	// See: ccl/storageccl/engineccl/enginepbccl/key_registry.proto
 	EncryptionType int32
@@ -523,6 +533,19 @@ type EnvStats struct {
 	EncryptionStatus []byte
 }
 
+// EncryptionKeyStats describes the files encrypted under a single key.
+type EncryptionKeyStats struct {
+	// KeyID is the ID of the data or store key, or "plain".
+	KeyID string
+	// CreationTime is the time the key was created, in seconds since the epoch.
+	// It is zero if unknown.
+	CreationTime int64
+	// Files is the number of files using the key.
+	Files uint64
+	// Bytes is the size of the sstables using the key.
+	Bytes uint64
+}
+
 // EncryptionRegistries contains the encryption-related registries:
 // Both are serialized protobufs.
 type EncryptionRegistries struct {
@@ -2339,8 +2339,9 @@ func mvccScanToBytes(
 		return MVCCScanResult{ResumeSpan: resumeSpan}, nil
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations do not support skipping locked keys.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() && !opts.SkipLocked {
 		return mvccIter.MVCCScan(key, endKey, timestamp, opts)
 	}
 
@@ -2358,6 +2359,8 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -2471,6 +2474,24 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// SkipLocked indicates that the scan should skip over keys that are locked
+	// by transactions other than the reader's, instead of returning a
+	// WriteIntentError for them. Locks that are not represented as intents in
+	// the MVCC keyspace are discovered through LockTable, which must be set if
+	// SkipLocked is.
+	SkipLocked bool
+	// LockTable is used to determine whether keys are locked in the in-memory
+	// lock table when SkipLocked is set.
+	LockTable LockTableView
+}
+
+// LockTableView is a transaction-bound view into an in-memory collection of
+// key-level locks.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a conflicting transaction, that is, a transaction other than the one
+	// that the view is bound to.
+	IsKeyLockedByConflictingTxn(roachpb.Key) bool
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2480,6 +2501,9 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.SkipLocked && opts.LockTable == nil {
+		return errors.Errorf("cannot skip locked keys without a lock table")
+	}
 	return nil
 }
 
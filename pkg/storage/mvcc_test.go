@@ -1506,6 +1506,107 @@ func mvccScanTest(ctx context.Context, t *testing.T, engine Engine) {
 	}
 }
 
+// lockedKeysView is a LockTableView that reports the provided keys as locked
+// by conflicting transactions.
+type lockedKeysView []roachpb.Key
+
+func (v lockedKeysView) IsKeyLockedByConflictingTxn(key roachpb.Key) bool {
+	for _, k := range v {
+		if k.Equal(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestMVCCScanSkipLocked verifies that scans configured to skip locked keys
+// skip over intents written by other transactions and keys locked in the lock
+// table, in both directions.
+func TestMVCCScanSkipLocked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			ts3 := hlc.Timestamp{WallTime: 3}
+			txn1ts := makeTxn(*txn1, ts2)
+			txn2ts := makeTxn(*txn2, ts3)
+
+			for _, kv := range []struct {
+				key   roachpb.Key
+				value roachpb.Value
+			}{
+				{testKey1, value1},
+				{testKey2, value2},
+				{testKey3, value3},
+				{testKey4, value4},
+			} {
+				require.NoError(t, MVCCPut(ctx, engine, nil, kv.key, ts1, kv.value, nil))
+			}
+			// Write an intent on testKey2 with txn1.
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, txn1ts.WriteTimestamp, value1, txn1ts))
+			// Pretend that testKey4 is locked in the lock table.
+			lockTable := lockedKeysView{testKey4}
+
+			testCases := []struct {
+				name    string
+				txn     *roachpb.Transaction
+				expKeys []roachpb.Key
+				expVals []roachpb.Value
+			}{
+				{
+					name:    "non-transactional",
+					txn:     nil,
+					expKeys: []roachpb.Key{testKey1, testKey3},
+					expVals: []roachpb.Value{value1, value3},
+				},
+				{
+					name:    "intent owner",
+					txn:     txn1ts,
+					expKeys: []roachpb.Key{testKey1, testKey2, testKey3},
+					expVals: []roachpb.Value{value1, value1, value3},
+				},
+				{
+					name:    "other transaction",
+					txn:     txn2ts,
+					expKeys: []roachpb.Key{testKey1, testKey3},
+					expVals: []roachpb.Value{value1, value3},
+				},
+			}
+			for _, tc := range testCases {
+				testutils.RunTrueAndFalse(t, tc.name+"/reverse", func(t *testing.T, reverse bool) {
+					res, err := MVCCScan(ctx, engine, testKey1, testKey5, ts3, MVCCScanOptions{
+						Txn:        tc.txn,
+						Reverse:    reverse,
+						SkipLocked: true,
+						LockTable:  lockTable,
+					})
+					require.NoError(t, err)
+					require.Len(t, res.Intents, 0)
+					require.Len(t, res.KVs, len(tc.expKeys))
+					for i := range tc.expKeys {
+						j := i
+						if reverse {
+							j = len(tc.expKeys) - i - 1
+						}
+						require.Equal(t, tc.expKeys[j], res.KVs[i].Key)
+						expBytes, err := tc.expVals[j].GetBytes()
+						require.NoError(t, err)
+						actBytes, err := res.KVs[i].Value.GetBytes()
+						require.NoError(t, err)
+						require.Equal(t, expBytes, actBytes)
+					}
+				})
+			}
+		})
+	}
+}
+
 func TestMVCCScan(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/pebble"
@@ -398,6 +399,9 @@ type EncryptionStatsHandler interface {
 	GetActiveStoreKeyType() int32
 	// Returns the KeyID embedded in the serialized EncryptionSettings.
 	GetKeyIDFromSettings(settings []byte) (string, error)
+	// Returns the creation time, in seconds since the epoch, of the data or
+	// store key with the given ID, and whether the key is known.
+	GetKeyCreationTime(keyID string) (int64, bool)
 }
 
 // Pebble is a wrapper around a Pebble database instance.
@@ -837,6 +841,7 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		}
 	}
 
+	keyStats := make(map[string]*EncryptionKeyStats)
 	for filePath, entry := range fr.Files {
 		keyID, err := p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
 		if err != nil {
@@ -845,25 +850,110 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		if len(keyID) == 0 {
 			keyID = "plain"
 		}
-		if keyID != activeKeyID {
-			continue
+		ks, ok := keyStats[keyID]
+		if !ok {
+			ks = &EncryptionKeyStats{KeyID: keyID}
+			ks.CreationTime, _ = p.statsHandler.GetKeyCreationTime(keyID)
+			keyStats[keyID] = ks
 		}
-		stats.ActiveKeyFiles++
+		ks.Files++
 
-		filename := p.fs.PathBase(filePath)
-		numStr := strings.TrimSuffix(filename, ".sst")
-		if len(numStr) == len(filename) {
-			continue // not a sstable
-		}
-		u, err := strconv.ParseUint(numStr, 10, 64)
+		fileNum, isSST, err := sstableFileNum(p.fs.PathBase(filePath))
 		if err != nil {
-			return nil, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+			return nil, err
+		}
+		if isSST {
+			ks.Bytes += sstSizes[fileNum]
+		}
+	}
+	for _, ks := range keyStats {
+		if ks.KeyID == activeKeyID {
+			stats.ActiveKeyFiles = ks.Files
+			stats.ActiveKeyBytes = ks.Bytes
 		}
-		stats.ActiveKeyBytes += sstSizes[pebble.FileNum(u)]
+		stats.KeyStats = append(stats.KeyStats, *ks)
 	}
+	sort.Slice(stats.KeyStats, func(i, j int) bool {
+		return stats.KeyStats[i].KeyID < stats.KeyStats[j].KeyID
+	})
 	return stats, nil
 }
 
+// CompactEncryptedBefore implements the Engine interface.
+func (p *Pebble) CompactEncryptedBefore(createdBefore time.Time) (int, error) {
+	if p.statsHandler == nil {
+		return 0, nil
+	}
+	fr := p.fileRegistry.getRegistryCopy()
+	activeKeyID, err := p.statsHandler.GetActiveDataKeyID()
+	if err != nil {
+		return 0, err
+	}
+
+	tables := make(map[pebble.FileNum]pebble.SSTableInfo)
+	for _, ssts := range p.db.SSTables() {
+		for _, sst := range ssts {
+			tables[sst.FileNum] = sst
+		}
+	}
+
+	var spans []roachpb.Span
+	for filePath, entry := range fr.Files {
+		keyID, err := p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
+		if err != nil {
+			return 0, err
+		}
+		// Unencrypted files are left alone: they only exist if encryption was
+		// disabled when they were written, and rewriting them is not what bounds
+		// key age.
+		if len(keyID) == 0 || keyID == activeKeyID {
+			continue
+		}
+		created, ok := p.statsHandler.GetKeyCreationTime(keyID)
+		if !ok || !timeutil.Unix(created, 0).Before(createdBefore) {
+			continue
+		}
+		fileNum, isSST, err := sstableFileNum(p.fs.PathBase(filePath))
+		if err != nil {
+			return 0, err
+		}
+		sst, ok := tables[fileNum]
+		if !isSST || !ok {
+			continue
+		}
+		start, err := DecodeMVCCKey(sst.Smallest.UserKey)
+		if err != nil {
+			return 0, err
+		}
+		end, err := DecodeMVCCKey(sst.Largest.UserKey)
+		if err != nil {
+			return 0, err
+		}
+		spans = append(spans, roachpb.Span{Key: start.Key, EndKey: end.Key.Next()})
+	}
+
+	for _, span := range spans {
+		if err := p.CompactRange(span.Key, span.EndKey, true /* forceBottommost */); err != nil {
+			return 0, err
+		}
+	}
+	return len(spans), nil
+}
+
+// sstableFileNum parses the file number out of the name of an sstable. It
+// returns false if the file is not an sstable.
+func sstableFileNum(filename string) (pebble.FileNum, bool, error) {
+	numStr := strings.TrimSuffix(filename, ".sst")
+	if len(numStr) == len(filename) {
+		return 0, false, nil
+	}
+	u, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+	}
+	return pebble.FileNum(u), true, nil
+}
+
 // GetAuxiliaryDir implements the Engine interface.
 func (p *Pebble) GetAuxiliaryDir() string {
 	return p.auxDir
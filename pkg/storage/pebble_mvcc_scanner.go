@@ -116,9 +116,12 @@ type pebbleMVCCScanner struct {
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
 	checkUncertainty         bool
+	skipLocked               bool
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
+	// Consulted to determine whether keys are locked when skipLocked is set.
+	lockTable LockTableView
 	// cur* variables store the "current" record we're pointing to. Updated in
 	// updateCurrent.
 	curKey   MVCCKey
@@ -273,6 +276,12 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance() bool {
+	if p.skipLocked && p.lockTable.IsKeyLockedByConflictingTxn(p.curKey.Key) {
+		// 0. The key is locked by another transaction in the lock table and
+		// we've been configured to skip locked keys. Move on to the next key.
+		return p.advanceKey()
+	}
+
 	if p.curKey.Timestamp != (hlc.Timestamp{}) {
 		if p.curKey.Timestamp.LessEq(p.ts) {
 			// 1. Fast path: there is no intent and our read timestamp is newer than
@@ -344,6 +353,13 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 	}
 	otherIntentVisible := metaTS.LessEq(maxVisibleTS) || p.failOnMoreRecent
 
+	if !ownIntent && p.skipLocked {
+		// 5a. The key contains an intent which was not written by our
+		// transaction and we've been configured to skip locked keys. Move on
+		// to the next key without recording the intent.
+		return p.advanceKey()
+	}
+
 	if !ownIntent && !otherIntentVisible {
 		// 6. The key contains an intent, but we're reading before the
 		// intent. Seek to the desired version. Note that if we own the
@@ -1008,6 +1008,13 @@ func (r *RocksDB) GetEnvStats() (*EnvStats, error) {
 	}, nil
 }
 
+// CompactEncryptedBefore implements the Engine interface. It is a no-op: the
+// RocksDB encrypted env does not expose the creation time of its keys, so
+// sstables are only rewritten under new data keys as they get compacted.
+func (r *RocksDB) CompactEncryptedBefore(createdBefore time.Time) (int, error) {
+	return 0, nil
+}
+
 // GetEncryptionRegistries returns the file and key registries when encryption is enabled
 // on the store.
 func (r *RocksDB) GetEncryptionRegistries() (*EncryptionRegistries, error) {
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -399,6 +400,13 @@ func (t *TeeEngine) CompactRange(start, end roachpb.Key, forceBottommost bool) e
 	return fatalOnErrorMismatch(t.ctx, err, err2)
 }
 
+// CompactEncryptedBefore implements the Engine interface.
+func (t *TeeEngine) CompactEncryptedBefore(createdBefore time.Time) (int, error) {
+	count, err := t.eng1.CompactEncryptedBefore(createdBefore)
+	_, err2 := t.eng2.CompactEncryptedBefore(createdBefore)
+	return count, fatalOnErrorMismatch(t.ctx, err, err2)
+}
+
 // InMem implements the Engine interface.
 func (t *TeeEngine) InMem() bool {
 	return t.eng1.InMem()
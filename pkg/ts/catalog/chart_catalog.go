@@ -1594,6 +1594,26 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{SQLLayer, "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Rows",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+			},
+			{
+				Title:   "Select Latency",
+				Metrics: []string{"jobs.row_level_ttl.select_duration"},
+			},
+			{
+				Title:   "Delete Latency",
+				Metrics: []string{"jobs.row_level_ttl.delete_duration"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{SQLLayer, "SQL"}},
 		Charts: []chartDescription{
@@ -0,0 +1,340 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// The metadata of parquet files is serialized using the thrift compact
+// protocol. Only the subset of the protocol needed by the parquet metadata is
+// implemented here. Unlike generic thrift decoders, the reader checks every
+// length it reads against the remaining input before allocating, as parquet
+// files are untrusted input.
+
+// Compact protocol type identifiers.
+const (
+	ctStop      byte = 0
+	ctBoolTrue  byte = 1
+	ctBoolFalse byte = 2
+	ctByte      byte = 3
+	ctI16       byte = 4
+	ctI32       byte = 5
+	ctI64       byte = 6
+	ctDouble    byte = 7
+	ctBinary    byte = 8
+	ctList      byte = 9
+	ctSet       byte = 10
+	ctMap       byte = 11
+	ctStruct    byte = 12
+)
+
+// maxCompactDepth bounds the nesting of the structures being decoded.
+const maxCompactDepth = 64
+
+var errTruncated = errors.New("parquet: truncated metadata")
+
+// compactReader decodes values serialized using the thrift compact protocol.
+type compactReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (r *compactReader) remaining() int {
+	return len(r.buf) - r.pos
+}
+
+func (r *compactReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *compactReader) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *compactReader) readVarint() (int64, error) {
+	v, err := r.readUvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *compactReader) readI32(typ byte) (int32, error) {
+	if typ != ctI32 {
+		return 0, errors.Errorf("parquet: expected i32, found type %d", typ)
+	}
+	v, err := r.readVarint()
+	return int32(v), err
+}
+
+func (r *compactReader) readI64(typ byte) (int64, error) {
+	if typ != ctI64 {
+		return 0, errors.Errorf("parquet: expected i64, found type %d", typ)
+	}
+	return r.readVarint()
+}
+
+func (r *compactReader) readBool(typ byte) (bool, error) {
+	switch typ {
+	case ctBoolTrue:
+		return true, nil
+	case ctBoolFalse:
+		return false, nil
+	default:
+		return false, errors.Errorf("parquet: expected bool, found type %d", typ)
+	}
+}
+
+func (r *compactReader) readBinary(typ byte) ([]byte, error) {
+	if typ != ctBinary {
+		return nil, errors.Errorf("parquet: expected binary, found type %d", typ)
+	}
+	n, err := r.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.remaining()) {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *compactReader) readString(typ byte) (string, error) {
+	b, err := r.readBinary(typ)
+	return string(b), err
+}
+
+// readListHeader reads the header of a list of the given type, returning the
+// number of elements. Every element takes at least one byte, which bounds the
+// size of the list by the remaining input.
+func (r *compactReader) readListHeader(typ byte, elemType byte) (int, error) {
+	if typ != ctList {
+		return 0, errors.Errorf("parquet: expected list, found type %d", typ)
+	}
+	b, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	if b&0x0f != elemType {
+		return 0, errors.Errorf("parquet: expected list of type %d, found %d", elemType, b&0x0f)
+	}
+	n := uint64(b >> 4)
+	if n == 0x0f {
+		if n, err = r.readUvarint(); err != nil {
+			return 0, err
+		}
+	}
+	if n > uint64(r.remaining()) {
+		return 0, errTruncated
+	}
+	return int(n), nil
+}
+
+// readStruct reads a struct, calling fn with the ID and type of each of its
+// fields. fn must consume the value of the field, or skip it.
+func (r *compactReader) readStruct(fn func(id int16, typ byte) error) error {
+	if r.depth++; r.depth > maxCompactDepth {
+		return errors.New("parquet: metadata nested too deeply")
+	}
+	defer func() { r.depth-- }()
+	var lastID int16
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		typ := b & 0x0f
+		if typ == ctStop {
+			return nil
+		}
+		id := lastID + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := r.readVarint()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		lastID = id
+		if err := fn(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip skips a value of the given type.
+func (r *compactReader) skip(typ byte) error {
+	switch typ {
+	case ctBoolTrue, ctBoolFalse:
+		return nil
+	case ctByte:
+		_, err := r.readByte()
+		return err
+	case ctI16, ctI32, ctI64:
+		_, err := r.readUvarint()
+		return err
+	case ctDouble:
+		if r.remaining() < 8 {
+			return errTruncated
+		}
+		r.pos += 8
+		return nil
+	case ctBinary:
+		_, err := r.readBinary(typ)
+		return err
+	case ctList, ctSet:
+		b, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		n := uint64(b >> 4)
+		if n == 0x0f {
+			if n, err = r.readUvarint(); err != nil {
+				return err
+			}
+		}
+		return r.skipElements(n, b&0x0f)
+	case ctMap:
+		n, err := r.readUvarint()
+		if err != nil || n == 0 {
+			return err
+		}
+		types, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := r.skipElements(1, types>>4); err != nil {
+				return err
+			}
+			if err := r.skipElements(1, types&0x0f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ctStruct:
+		return r.readStruct(func(_ int16, typ byte) error {
+			return r.skip(typ)
+		})
+	default:
+		return errors.Errorf("parquet: unknown metadata type %d", typ)
+	}
+}
+
+// skipElements skips n elements of a list, set or map. Booleans are encoded
+// as a full byte in collections.
+func (r *compactReader) skipElements(n uint64, typ byte) error {
+	if n > uint64(r.remaining()) {
+		return errTruncated
+	}
+	if r.depth++; r.depth > maxCompactDepth {
+		return errors.New("parquet: metadata nested too deeply")
+	}
+	defer func() { r.depth-- }()
+	for i := uint64(0); i < n; i++ {
+		if typ == ctBoolTrue || typ == ctBoolFalse {
+			typ = ctByte
+		}
+		if err := r.skip(typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compactWriter encodes values using the thrift compact protocol.
+type compactWriter struct {
+	buf []byte
+	// lastIDs holds the ID of the last field written in each of the structs
+	// being written, as field IDs are encoded as deltas.
+	lastIDs []int16
+}
+
+func (w *compactWriter) writeUvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf = append(w.buf, tmp[:n]...)
+}
+
+func (w *compactWriter) writeVarint(v int64) {
+	w.writeUvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *compactWriter) structBegin() {
+	w.lastIDs = append(w.lastIDs, 0)
+}
+
+func (w *compactWriter) structEnd() {
+	w.buf = append(w.buf, ctStop)
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.writeVarint(int64(id))
+	}
+	*last = id
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, ctI32)
+	w.writeVarint(int64(v))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, ctI64)
+	w.writeVarint(v)
+}
+
+func (w *compactWriter) boolField(id int16, v bool) {
+	if v {
+		w.fieldHeader(id, ctBoolTrue)
+	} else {
+		w.fieldHeader(id, ctBoolFalse)
+	}
+}
+
+func (w *compactWriter) binaryField(id int16, v []byte) {
+	w.fieldHeader(id, ctBinary)
+	w.writeBinary(v)
+}
+
+func (w *compactWriter) writeBinary(v []byte) {
+	w.writeUvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+func (w *compactWriter) listField(id int16, elemType byte, n int) {
+	w.fieldHeader(id, ctList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.writeUvarint(uint64(n))
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/cockroachdb/errors"
+)
+
+var errCorruptPage = errors.New("parquet: corrupt page")
+
+// decodeHybrid decodes n values of the given bit width encoded using the
+// RLE/bit-packed hybrid encoding, which is used for definition levels and
+// dictionary indices. It returns the number of bytes consumed.
+func decodeHybrid(data []byte, bitWidth int, n int) ([]uint32, int, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, 0, errors.Errorf("parquet: invalid bit width %d", bitWidth)
+	}
+	// n comes from a page header, so only preallocate what the input could
+	// hold if it were entirely bit-packed.
+	capacity := n
+	if max := 8 * len(data); capacity > max {
+		capacity = max
+	}
+	out := make([]uint32, 0, capacity)
+	pos := 0
+	for len(out) < n {
+		header, k := binary.Uvarint(data[pos:])
+		if k <= 0 {
+			return nil, 0, errCorruptPage
+		}
+		pos += k
+		if header&1 == 0 {
+			// RLE run: the repeated value is stored in the minimal number of
+			// bytes, little endian.
+			count := header >> 1
+			width := (bitWidth + 7) / 8
+			if pos+width > len(data) || count == 0 {
+				return nil, 0, errCorruptPage
+			}
+			var v uint32
+			for i := 0; i < width; i++ {
+				v |= uint32(data[pos+i]) << (8 * uint(i))
+			}
+			pos += width
+			if count > uint64(n-len(out)) {
+				count = uint64(n - len(out))
+			}
+			for i := uint64(0); i < count; i++ {
+				out = append(out, v)
+			}
+			continue
+		}
+		// Bit-packed run of groups of 8 values, least significant bit first.
+		groups := header >> 1
+		if groups == 0 || groups > uint64(len(data)-pos) {
+			return nil, 0, errCorruptPage
+		}
+		size := int(groups) * bitWidth
+		if pos+size > len(data) {
+			return nil, 0, errCorruptPage
+		}
+		count := int(groups) * 8
+		if count > n-len(out) {
+			count = n - len(out)
+		}
+		var bit uint
+		for i := 0; i < count; i++ {
+			var v uint32
+			for b := 0; b < bitWidth; b++ {
+				if data[pos+int(bit>>3)]&(1<<(bit&7)) != 0 {
+					v |= 1 << uint(b)
+				}
+				bit++
+			}
+			out = append(out, v)
+		}
+		pos += size
+	}
+	return out, pos, nil
+}
+
+// appendHybrid encodes the given values, all of which must fit in the given
+// bit width, using RLE runs of the RLE/bit-packed hybrid encoding.
+func appendHybrid(buf []byte, bitWidth int, values []uint32) []byte {
+	width := (bitWidth + 7) / 8
+	var tmp [binary.MaxVarintLen64]byte
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+		n := binary.PutUvarint(tmp[:], uint64(j-i)<<1)
+		buf = append(buf, tmp[:n]...)
+		for b := 0; b < width; b++ {
+			buf = append(buf, byte(values[i]>>(8*uint(b))))
+		}
+		i = j
+	}
+	return buf
+}
+
+// decodePlain decodes n PLAIN encoded values of the given column. Byte arrays
+// are returned as strings, which do not alias the input.
+func decodePlain(data []byte, elem *SchemaElement, n int) ([]interface{}, error) {
+	size := 0
+	switch elem.Type {
+	case TypeBoolean:
+		size = (n + 7) / 8
+	case TypeInt32, TypeFloat:
+		size = 4 * n
+	case TypeInt64, TypeDouble:
+		size = 8 * n
+	case TypeInt96:
+		size = 12 * n
+	case TypeFixedLenByteArray:
+		if elem.TypeLength < 0 {
+			return nil, errors.Errorf("parquet: invalid fixed length %d", elem.TypeLength)
+		}
+		size = int(elem.TypeLength) * n
+	case TypeByteArray:
+		// Every value takes at least its 4 byte length.
+		size = 4 * n
+	default:
+		return nil, errors.Errorf("parquet: unknown physical type %d", elem.Type)
+	}
+	if n < 0 || size < 0 || size > len(data) {
+		return nil, errCorruptPage
+	}
+
+	out := make([]interface{}, n)
+	switch elem.Type {
+	case TypeBoolean:
+		for i := range out {
+			out[i] = data[i/8]&(1<<uint(i%8)) != 0
+		}
+	case TypeInt32:
+		for i := range out {
+			out[i] = int32(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+	case TypeInt64:
+		for i := range out {
+			out[i] = int64(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+	case TypeFloat:
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+	case TypeDouble:
+		for i := range out {
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+	case TypeInt96:
+		for i := range out {
+			out[i] = string(data[12*i : 12*i+12])
+		}
+	case TypeFixedLenByteArray:
+		l := int(elem.TypeLength)
+		for i := range out {
+			out[i] = string(data[l*i : l*i+l])
+		}
+	case TypeByteArray:
+		pos := 0
+		for i := range out {
+			if pos+4 > len(data) {
+				return nil, errCorruptPage
+			}
+			l := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if l < 0 || l > len(data)-pos {
+				return nil, errCorruptPage
+			}
+			out[i] = string(data[pos : pos+l])
+			pos += l
+		}
+	}
+	return out, nil
+}
+
+// appendPlain appends the PLAIN encoding of the non-NULL values to buf.
+func appendPlain(buf []byte, typ Type, values []interface{}) ([]byte, error) {
+	var bits byte
+	var nbits uint
+	for _, v := range values {
+		switch typ {
+		case TypeBoolean:
+			b, ok := v.(bool)
+			if !ok {
+				return nil, errors.Errorf("parquet: expected bool, found %T", v)
+			}
+			if b {
+				bits |= 1 << nbits
+			}
+			if nbits++; nbits == 8 {
+				buf = append(buf, bits)
+				bits, nbits = 0, 0
+			}
+		case TypeInt32:
+			i, ok := v.(int32)
+			if !ok {
+				return nil, errors.Errorf("parquet: expected int32, found %T", v)
+			}
+			buf = appendUint32(buf, uint32(i))
+		case TypeInt64:
+			i, ok := v.(int64)
+			if !ok {
+				return nil, errors.Errorf("parquet: expected int64, found %T", v)
+			}
+			buf = appendUint64(buf, uint64(i))
+		case TypeFloat:
+			f, ok := v.(float32)
+			if !ok {
+				return nil, errors.Errorf("parquet: expected float32, found %T", v)
+			}
+			buf = appendUint32(buf, math.Float32bits(f))
+		case TypeDouble:
+			f, ok := v.(float64)
+			if !ok {
+				return nil, errors.Errorf("parquet: expected float64, found %T", v)
+			}
+			buf = appendUint64(buf, math.Float64bits(f))
+		case TypeByteArray:
+			switch b := v.(type) {
+			case string:
+				buf = appendUint32(buf, uint32(len(b)))
+				buf = append(buf, b...)
+			case []byte:
+				buf = appendUint32(buf, uint32(len(b)))
+				buf = append(buf, b...)
+			default:
+				return nil, errors.Errorf("parquet: expected string or []byte, found %T", v)
+			}
+		default:
+			return nil, errors.Errorf("parquet: writing physical type %d is not supported", typ)
+		}
+	}
+	if nbits > 0 {
+		buf = append(buf, bits)
+	}
+	return buf, nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
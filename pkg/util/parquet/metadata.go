@@ -0,0 +1,639 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import "github.com/cockroachdb/errors"
+
+// Type is the physical type of the values of a column.
+type Type int32
+
+// Physical types, as defined by parquet.thrift.
+const (
+	TypeBoolean           Type = 0
+	TypeInt32             Type = 1
+	TypeInt64             Type = 2
+	TypeInt96             Type = 3
+	TypeFloat             Type = 4
+	TypeDouble            Type = 5
+	TypeByteArray         Type = 6
+	TypeFixedLenByteArray Type = 7
+)
+
+// ConvertedType is the logical type of the values of a column, which refines
+// the interpretation of their physical type.
+type ConvertedType int32
+
+// Converted types, as defined by parquet.thrift.
+const (
+	// ConvertedTypeNone marks columns without a converted type.
+	ConvertedTypeNone            ConvertedType = -1
+	ConvertedTypeUTF8            ConvertedType = 0
+	ConvertedTypeMap             ConvertedType = 1
+	ConvertedTypeMapKeyValue     ConvertedType = 2
+	ConvertedTypeList            ConvertedType = 3
+	ConvertedTypeEnum            ConvertedType = 4
+	ConvertedTypeDecimal         ConvertedType = 5
+	ConvertedTypeDate            ConvertedType = 6
+	ConvertedTypeTimeMillis      ConvertedType = 7
+	ConvertedTypeTimeMicros      ConvertedType = 8
+	ConvertedTypeTimestampMillis ConvertedType = 9
+	ConvertedTypeTimestampMicros ConvertedType = 10
+	ConvertedTypeUint8           ConvertedType = 11
+	ConvertedTypeUint16          ConvertedType = 12
+	ConvertedTypeUint32          ConvertedType = 13
+	ConvertedTypeUint64          ConvertedType = 14
+	ConvertedTypeInt8            ConvertedType = 15
+	ConvertedTypeInt16           ConvertedType = 16
+	ConvertedTypeInt32           ConvertedType = 17
+	ConvertedTypeInt64           ConvertedType = 18
+	ConvertedTypeJSON            ConvertedType = 19
+	ConvertedTypeBSON            ConvertedType = 20
+	ConvertedTypeInterval        ConvertedType = 21
+)
+
+// Repetition is the repetition of the values of a column.
+type Repetition int32
+
+// Repetitions, as defined by parquet.thrift.
+const (
+	RepetitionRequired Repetition = 0
+	RepetitionOptional Repetition = 1
+	RepetitionRepeated Repetition = 2
+)
+
+// CompressionCodec is the codec with which the pages of a column chunk are
+// compressed.
+type CompressionCodec int32
+
+// Compression codecs, as defined by parquet.thrift. Only the uncompressed,
+// snappy and gzip codecs are supported.
+const (
+	CompressionUncompressed CompressionCodec = 0
+	CompressionSnappy       CompressionCodec = 1
+	CompressionGzip         CompressionCodec = 2
+	CompressionLZO          CompressionCodec = 3
+	CompressionBrotli       CompressionCodec = 4
+	CompressionLZ4          CompressionCodec = 5
+	CompressionZSTD         CompressionCodec = 6
+)
+
+// encoding is the encoding of the values or levels of a page.
+type encoding int32
+
+const (
+	encodingPlain           encoding = 0
+	encodingPlainDictionary encoding = 2
+	encodingRLE             encoding = 3
+	encodingBitPacked       encoding = 4
+	encodingRLEDictionary   encoding = 8
+)
+
+type pageType int32
+
+const (
+	pageTypeData       pageType = 0
+	pageTypeIndex      pageType = 1
+	pageTypeDictionary pageType = 2
+	pageTypeDataV2     pageType = 3
+)
+
+// SchemaElement describes a node of the schema of a file. The leaves of the
+// schema are the columns of the file.
+type SchemaElement struct {
+	Name string
+	// Type is the physical type of the column. It is only set for leaves.
+	Type    Type
+	hasType bool
+	// TypeLength is the length of the values of FIXED_LEN_BYTE_ARRAY columns.
+	TypeLength    int32
+	Repetition    Repetition
+	NumChildren   int32
+	ConvertedType ConvertedType
+	// Scale and Precision are set for DECIMAL columns.
+	Scale     int32
+	Precision int32
+}
+
+type fileMetaData struct {
+	version   int32
+	schema    []SchemaElement
+	numRows   int64
+	rowGroups []rowGroup
+	createdBy string
+}
+
+type rowGroup struct {
+	columns       []columnChunk
+	totalByteSize int64
+	numRows       int64
+}
+
+type columnChunk struct {
+	fileOffset int64
+	meta       columnMetaData
+	hasMeta    bool
+}
+
+type columnMetaData struct {
+	typ                   Type
+	encodings             []encoding
+	pathInSchema          []string
+	codec                 CompressionCodec
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+	dictionaryPageOffset  int64
+}
+
+type pageHeader struct {
+	typ              pageType
+	uncompressedSize int32
+	compressedSize   int32
+	dataPage         *dataPageHeader
+	dictionaryPage   *dictionaryPageHeader
+	dataPageV2       *dataPageHeaderV2
+}
+
+type dataPageHeader struct {
+	numValues int32
+	encoding  encoding
+	defLevels encoding
+	repLevels encoding
+}
+
+type dictionaryPageHeader struct {
+	numValues int32
+	encoding  encoding
+}
+
+type dataPageHeaderV2 struct {
+	numValues    int32
+	numNulls     int32
+	numRows      int32
+	encoding     encoding
+	defLevelsLen int32
+	repLevelsLen int32
+	isCompressed bool
+}
+
+func readFileMetaData(r *compactReader) (*fileMetaData, error) {
+	var m fileMetaData
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch id {
+		case 1:
+			m.version, err = r.readI32(typ)
+		case 2:
+			var n int
+			if n, err = r.readListHeader(typ, ctStruct); err != nil {
+				return err
+			}
+			m.schema = make([]SchemaElement, n)
+			for i := range m.schema {
+				if err := readSchemaElement(r, &m.schema[i]); err != nil {
+					return err
+				}
+			}
+		case 3:
+			m.numRows, err = r.readI64(typ)
+		case 4:
+			var n int
+			if n, err = r.readListHeader(typ, ctStruct); err != nil {
+				return err
+			}
+			m.rowGroups = make([]rowGroup, n)
+			for i := range m.rowGroups {
+				if err := readRowGroup(r, &m.rowGroups[i]); err != nil {
+					return err
+				}
+			}
+		case 6:
+			m.createdBy, err = r.readString(typ)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "parquet: reading file metadata")
+	}
+	return &m, nil
+}
+
+func readSchemaElement(r *compactReader, e *SchemaElement) error {
+	e.ConvertedType = ConvertedTypeNone
+	logical := ConvertedTypeNone
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			v, err = r.readI32(typ)
+			e.Type, e.hasType = Type(v), true
+		case 2:
+			e.TypeLength, err = r.readI32(typ)
+		case 3:
+			v, err = r.readI32(typ)
+			e.Repetition = Repetition(v)
+		case 4:
+			e.Name, err = r.readString(typ)
+		case 5:
+			e.NumChildren, err = r.readI32(typ)
+		case 6:
+			v, err = r.readI32(typ)
+			e.ConvertedType = ConvertedType(v)
+		case 7:
+			e.Scale, err = r.readI32(typ)
+		case 8:
+			e.Precision, err = r.readI32(typ)
+		case 10:
+			if typ != ctStruct {
+				return r.skip(typ)
+			}
+			logical, err = readLogicalType(r, e)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	// Writers are supposed to set the converted type along with the logical
+	// type where one exists, but some only set the latter.
+	if e.ConvertedType == ConvertedTypeNone {
+		e.ConvertedType = logical
+	}
+	return err
+}
+
+// readLogicalType reads the LogicalType union of a schema element, returning
+// the equivalent converted type, if any. The scale and precision of decimals
+// are stored in the schema element.
+func readLogicalType(r *compactReader, e *SchemaElement) (ConvertedType, error) {
+	res := ConvertedTypeNone
+	err := r.readStruct(func(id int16, typ byte) error {
+		if typ != ctStruct {
+			return r.skip(typ)
+		}
+		switch id {
+		case 1:
+			res = ConvertedTypeUTF8
+		case 4:
+			res = ConvertedTypeEnum
+		case 5:
+			res = ConvertedTypeDecimal
+			return r.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch id {
+				case 1:
+					e.Scale, err = r.readI32(typ)
+				case 2:
+					e.Precision, err = r.readI32(typ)
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		case 6:
+			res = ConvertedTypeDate
+		case 8:
+			// TimestampType: the unit is a union of MILLIS (1), MICROS (2) and
+			// NANOS (3). Nanosecond timestamps have no converted type and are
+			// read as plain integers.
+			return r.readStruct(func(id int16, typ byte) error {
+				if id != 2 || typ != ctStruct {
+					return r.skip(typ)
+				}
+				return r.readStruct(func(id int16, typ byte) error {
+					switch id {
+					case 1:
+						res = ConvertedTypeTimestampMillis
+					case 2:
+						res = ConvertedTypeTimestampMicros
+					}
+					return r.skip(typ)
+				})
+			})
+		case 12:
+			res = ConvertedTypeJSON
+		case 13:
+			res = ConvertedTypeBSON
+		}
+		return r.skip(typ)
+	})
+	return res, err
+}
+
+func readRowGroup(r *compactReader, g *rowGroup) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch id {
+		case 1:
+			var n int
+			if n, err = r.readListHeader(typ, ctStruct); err != nil {
+				return err
+			}
+			g.columns = make([]columnChunk, n)
+			for i := range g.columns {
+				if err := readColumnChunk(r, &g.columns[i]); err != nil {
+					return err
+				}
+			}
+		case 2:
+			g.totalByteSize, err = r.readI64(typ)
+		case 3:
+			g.numRows, err = r.readI64(typ)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readColumnChunk(r *compactReader, c *columnChunk) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch id {
+		case 1:
+			if _, err = r.readString(typ); err == nil {
+				err = errors.New("parquet: column chunks stored in other files are not supported")
+			}
+		case 2:
+			c.fileOffset, err = r.readI64(typ)
+		case 3:
+			if typ != ctStruct {
+				return errors.Errorf("parquet: expected struct, found type %d", typ)
+			}
+			c.hasMeta = true
+			err = readColumnMetaData(r, &c.meta)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readColumnMetaData(r *compactReader, m *columnMetaData) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			v, err = r.readI32(typ)
+			m.typ = Type(v)
+		case 2:
+			var n int
+			if n, err = r.readListHeader(typ, ctI32); err != nil {
+				return err
+			}
+			m.encodings = make([]encoding, n)
+			for i := range m.encodings {
+				if v, err = r.readI32(ctI32); err != nil {
+					return err
+				}
+				m.encodings[i] = encoding(v)
+			}
+		case 3:
+			var n int
+			if n, err = r.readListHeader(typ, ctBinary); err != nil {
+				return err
+			}
+			m.pathInSchema = make([]string, n)
+			for i := range m.pathInSchema {
+				if m.pathInSchema[i], err = r.readString(ctBinary); err != nil {
+					return err
+				}
+			}
+		case 4:
+			v, err = r.readI32(typ)
+			m.codec = CompressionCodec(v)
+		case 5:
+			m.numValues, err = r.readI64(typ)
+		case 6:
+			m.totalUncompressedSize, err = r.readI64(typ)
+		case 7:
+			m.totalCompressedSize, err = r.readI64(typ)
+		case 9:
+			m.dataPageOffset, err = r.readI64(typ)
+		case 11:
+			m.dictionaryPageOffset, err = r.readI64(typ)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readPageHeader(r *compactReader) (*pageHeader, error) {
+	var h pageHeader
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			v, err = r.readI32(typ)
+			h.typ = pageType(v)
+		case 2:
+			h.uncompressedSize, err = r.readI32(typ)
+		case 3:
+			h.compressedSize, err = r.readI32(typ)
+		case 5:
+			if typ != ctStruct {
+				return r.skip(typ)
+			}
+			h.dataPage = &dataPageHeader{}
+			err = readDataPageHeader(r, h.dataPage)
+		case 7:
+			if typ != ctStruct {
+				return r.skip(typ)
+			}
+			h.dictionaryPage = &dictionaryPageHeader{}
+			err = readDictionaryPageHeader(r, h.dictionaryPage)
+		case 8:
+			if typ != ctStruct {
+				return r.skip(typ)
+			}
+			h.dataPageV2 = &dataPageHeaderV2{isCompressed: true}
+			err = readDataPageHeaderV2(r, h.dataPageV2)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "parquet: reading page header")
+	}
+	if h.compressedSize < 0 || h.uncompressedSize < 0 {
+		return nil, errors.Errorf("parquet: invalid page sizes %d and %d",
+			h.compressedSize, h.uncompressedSize)
+	}
+	return &h, nil
+}
+
+func readDataPageHeader(r *compactReader, h *dataPageHeader) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			h.numValues, err = r.readI32(typ)
+		case 2:
+			v, err = r.readI32(typ)
+			h.encoding = encoding(v)
+		case 3:
+			v, err = r.readI32(typ)
+			h.defLevels = encoding(v)
+		case 4:
+			v, err = r.readI32(typ)
+			h.repLevels = encoding(v)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readDictionaryPageHeader(r *compactReader, h *dictionaryPageHeader) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			h.numValues, err = r.readI32(typ)
+		case 2:
+			v, err = r.readI32(typ)
+			h.encoding = encoding(v)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readDataPageHeaderV2(r *compactReader, h *dataPageHeaderV2) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int32
+		switch id {
+		case 1:
+			h.numValues, err = r.readI32(typ)
+		case 2:
+			h.numNulls, err = r.readI32(typ)
+		case 3:
+			h.numRows, err = r.readI32(typ)
+		case 4:
+			v, err = r.readI32(typ)
+			h.encoding = encoding(v)
+		case 5:
+			h.defLevelsLen, err = r.readI32(typ)
+		case 6:
+			h.repLevelsLen, err = r.readI32(typ)
+		case 7:
+			h.isCompressed, err = r.readBool(typ)
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func writeFileMetaData(w *compactWriter, m *fileMetaData) {
+	w.structBegin()
+	w.i32Field(1, m.version)
+	w.listField(2, ctStruct, len(m.schema))
+	for i := range m.schema {
+		writeSchemaElement(w, &m.schema[i])
+	}
+	w.i64Field(3, m.numRows)
+	w.listField(4, ctStruct, len(m.rowGroups))
+	for i := range m.rowGroups {
+		writeRowGroup(w, &m.rowGroups[i])
+	}
+	if m.createdBy != "" {
+		w.binaryField(6, []byte(m.createdBy))
+	}
+	w.structEnd()
+}
+
+func writeSchemaElement(w *compactWriter, e *SchemaElement) {
+	w.structBegin()
+	if e.hasType {
+		w.i32Field(1, int32(e.Type))
+		if e.Type == TypeFixedLenByteArray {
+			w.i32Field(2, e.TypeLength)
+		}
+		w.i32Field(3, int32(e.Repetition))
+	}
+	w.binaryField(4, []byte(e.Name))
+	if e.NumChildren > 0 {
+		w.i32Field(5, e.NumChildren)
+	}
+	if e.ConvertedType != ConvertedTypeNone {
+		w.i32Field(6, int32(e.ConvertedType))
+		if e.ConvertedType == ConvertedTypeDecimal {
+			w.i32Field(7, e.Scale)
+			w.i32Field(8, e.Precision)
+		}
+	}
+	w.structEnd()
+}
+
+func writeRowGroup(w *compactWriter, g *rowGroup) {
+	w.structBegin()
+	w.listField(1, ctStruct, len(g.columns))
+	for i := range g.columns {
+		c := &g.columns[i]
+		w.structBegin()
+		w.i64Field(2, c.fileOffset)
+		w.fieldHeader(3, ctStruct)
+		writeColumnMetaData(w, &c.meta)
+		w.structEnd()
+	}
+	w.i64Field(2, g.totalByteSize)
+	w.i64Field(3, g.numRows)
+	w.structEnd()
+}
+
+func writeColumnMetaData(w *compactWriter, m *columnMetaData) {
+	w.structBegin()
+	w.i32Field(1, int32(m.typ))
+	w.listField(2, ctI32, len(m.encodings))
+	for _, e := range m.encodings {
+		w.writeVarint(int64(e))
+	}
+	w.listField(3, ctBinary, len(m.pathInSchema))
+	for _, p := range m.pathInSchema {
+		w.writeBinary([]byte(p))
+	}
+	w.i32Field(4, int32(m.codec))
+	w.i64Field(5, m.numValues)
+	w.i64Field(6, m.totalUncompressedSize)
+	w.i64Field(7, m.totalCompressedSize)
+	w.i64Field(9, m.dataPageOffset)
+	if m.dictionaryPageOffset > 0 {
+		w.i64Field(11, m.dictionaryPageOffset)
+	}
+	w.structEnd()
+}
+
+func writeDataPageHeader(w *compactWriter, h *pageHeader) {
+	w.structBegin()
+	w.i32Field(1, int32(pageTypeData))
+	w.i32Field(2, h.uncompressedSize)
+	w.i32Field(3, h.compressedSize)
+	w.fieldHeader(5, ctStruct)
+	w.structBegin()
+	w.i32Field(1, h.dataPage.numValues)
+	w.i32Field(2, int32(h.dataPage.encoding))
+	w.i32Field(3, int32(h.dataPage.defLevels))
+	w.i32Field(4, int32(h.dataPage.repLevels))
+	w.structEnd()
+	w.structEnd()
+}
@@ -0,0 +1,20 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet implements a reader and a writer for the subset of the
+// Apache Parquet file format used by IMPORT, EXPORT and foreign tables: flat
+// schemas of REQUIRED or OPTIONAL columns, PLAIN and dictionary encoded
+// values, v1 and v2 data pages, and the uncompressed, snappy and gzip codecs.
+//
+// See https://github.com/apache/parquet-format for the specification.
+package parquet
+
+// magic is the marker at the start and end of parquet files.
+var magic = []byte("PAR1")
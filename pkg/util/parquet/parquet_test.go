@@ -0,0 +1,261 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r *Reader, col int, batch int) []interface{} {
+	var res []interface{}
+	for {
+		vals, err := r.ReadColumn(col, batch)
+		require.NoError(t, err)
+		if len(vals) == 0 {
+			return res
+		}
+		res = append(res, vals...)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	columns := []Column{
+		{Name: "b", Type: TypeBoolean, ConvertedType: ConvertedTypeNone},
+		{Name: "i", Type: TypeInt32, ConvertedType: ConvertedTypeDate},
+		{Name: "l", Type: TypeInt64, ConvertedType: ConvertedTypeTimestampMicros},
+		{Name: "f", Type: TypeFloat, ConvertedType: ConvertedTypeNone},
+		{Name: "d", Type: TypeDouble, ConvertedType: ConvertedTypeNone},
+		{Name: "s", Type: TypeByteArray, ConvertedType: ConvertedTypeUTF8},
+	}
+	const numRows = 5000
+	rows := make([][]interface{}, numRows)
+	for i := range rows {
+		rows[i] = []interface{}{
+			i%3 == 0, int32(-i), int64(i) << 40, float32(i) / 4, float64(i) / 3, fmt.Sprint("row", i),
+		}
+		for j := range rows[i] {
+			if (i+j)%7 == 0 {
+				rows[i][j] = nil
+			}
+		}
+	}
+
+	for _, codec := range []CompressionCodec{
+		CompressionUncompressed, CompressionSnappy, CompressionGzip,
+	} {
+		for _, rowGroupSize := range []int64{0, 1 << 10} {
+			t.Run(fmt.Sprintf("codec=%d/rowgroup=%d", codec, rowGroupSize), func(t *testing.T) {
+				var buf bytes.Buffer
+				w, err := NewWriter(&buf, columns, WriterOptions{
+					Compression: codec, RowGroupSize: rowGroupSize,
+				})
+				require.NoError(t, err)
+				for _, row := range rows {
+					require.NoError(t, w.AddRow(row))
+				}
+				require.NoError(t, w.Close())
+
+				r, err := NewReader(buf.Bytes())
+				require.NoError(t, err)
+				require.Equal(t, int64(numRows), r.NumRows())
+				if rowGroupSize != 0 {
+					require.True(t, len(r.meta.rowGroups) > 1)
+				}
+				require.Len(t, r.Columns(), len(columns))
+				for i, c := range r.Columns() {
+					require.Equal(t, columns[i].Name, c.Name)
+					require.Equal(t, columns[i].Type, c.Type)
+					require.Equal(t, columns[i].ConvertedType, c.ConvertedType)
+					require.Equal(t, RepetitionOptional, c.Repetition)
+
+					vals := readAll(t, r, i, 97)
+					require.Len(t, vals, numRows)
+					for j, v := range vals {
+						require.Equal(t, rows[j][i], v, "column %d row %d", i, j)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, nil, WriterOptions{Compression: CompressionZSTD})
+	require.EqualError(t, err, "parquet: compression codec 6 is not supported")
+
+	w, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "a", Type: TypeInt32}}, WriterOptions{})
+	require.NoError(t, err)
+	require.EqualError(t, w.AddRow([]interface{}{1, 2}), "parquet: row has 2 values, expected 1")
+	require.NoError(t, w.AddRow([]interface{}{int64(1)}))
+	require.EqualError(t, w.Close(), `column "a": parquet: expected int32, found int64`)
+}
+
+// TestReadDictionaryV2 reads a REQUIRED column stored as a dictionary page
+// followed by a v2 data page, neither of which the Writer produces.
+func TestReadDictionaryV2(t *testing.T) {
+	dict, err := appendPlain(nil, TypeByteArray, []interface{}{"a", "bb", "ccc"})
+	require.NoError(t, err)
+	indices := []uint32{2, 0, 1, 1, 2, 0, 0, 0, 0, 2}
+	// Bit-pack the indices with a bit width of 2: one run of 2 groups of 8.
+	values := []byte{2, 2<<1 | 1}
+	var packed [4]byte
+	for i, idx := range indices {
+		packed[i/4] |= byte(idx) << uint(2*(i%4))
+	}
+	values = append(values, packed[:]...)
+
+	var cw compactWriter
+	cw.buf = append(cw.buf, magic...)
+	dictOffset := int64(len(cw.buf))
+	cw.structBegin()
+	cw.i32Field(1, int32(pageTypeDictionary))
+	cw.i32Field(2, int32(len(dict)))
+	cw.i32Field(3, int32(len(dict)))
+	cw.fieldHeader(7, ctStruct)
+	cw.structBegin()
+	cw.i32Field(1, 3)
+	cw.i32Field(2, int32(encodingPlainDictionary))
+	cw.structEnd()
+	cw.structEnd()
+	cw.buf = append(cw.buf, dict...)
+	dataOffset := int64(len(cw.buf))
+	cw.structBegin()
+	cw.i32Field(1, int32(pageTypeDataV2))
+	cw.i32Field(2, int32(len(values)))
+	cw.i32Field(3, int32(len(values)))
+	cw.fieldHeader(8, ctStruct)
+	cw.structBegin()
+	cw.i32Field(1, int32(len(indices)))
+	cw.i32Field(2, 0)
+	cw.i32Field(3, int32(len(indices)))
+	cw.i32Field(4, int32(encodingRLEDictionary))
+	cw.i32Field(5, 0)
+	cw.i32Field(6, 0)
+	cw.boolField(7, false)
+	cw.structEnd()
+	cw.structEnd()
+	cw.buf = append(cw.buf, values...)
+	end := int64(len(cw.buf))
+
+	meta := fileMetaData{
+		version: 1,
+		schema: []SchemaElement{
+			{Name: "schema", NumChildren: 1, ConvertedType: ConvertedTypeNone},
+			{
+				Name: "s", Type: TypeByteArray, hasType: true,
+				Repetition: RepetitionRequired, ConvertedType: ConvertedTypeUTF8,
+			},
+		},
+		numRows: int64(len(indices)),
+		rowGroups: []rowGroup{{
+			numRows: int64(len(indices)),
+			columns: []columnChunk{{
+				fileOffset: dictOffset,
+				hasMeta:    true,
+				meta: columnMetaData{
+					typ:                  TypeByteArray,
+					encodings:            []encoding{encodingPlainDictionary, encodingRLEDictionary},
+					pathInSchema:         []string{"s"},
+					numValues:            int64(len(indices)),
+					totalCompressedSize:  end - dictOffset,
+					dataPageOffset:       dataOffset,
+					dictionaryPageOffset: dictOffset,
+				},
+			}},
+		}},
+	}
+	footerStart := len(cw.buf)
+	writeFileMetaData(&cw, &meta)
+	cw.buf = appendUint32(cw.buf, uint32(len(cw.buf)-footerStart))
+	cw.buf = append(cw.buf, magic...)
+
+	r, err := NewReader(cw.buf)
+	require.NoError(t, err)
+	expected := make([]interface{}, len(indices))
+	for i, idx := range indices {
+		expected[i] = []string{"a", "bb", "ccc"}[idx]
+	}
+	require.Equal(t, expected, readAll(t, r, 0, 3))
+}
+
+func TestReadErrors(t *testing.T) {
+	_, err := NewReader([]byte("not a parquet file"))
+	require.EqualError(t, err, "parquet: not a parquet file")
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{
+		{Name: "i", Type: TypeInt64, ConvertedType: ConvertedTypeNone},
+		{Name: "s", Type: TypeByteArray, ConvertedType: ConvertedTypeUTF8},
+	}, WriterOptions{Compression: CompressionSnappy})
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, w.AddRow([]interface{}{int64(i), fmt.Sprint(i)}))
+	}
+	require.NoError(t, w.Close())
+	data := buf.Bytes()
+
+	// Corrupt files must be rejected with an error, not a panic or an
+	// allocation sized by the corrupt data.
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 2000; i++ {
+		corrupt := append([]byte(nil), data...)
+		for j := 0; j < 1+rng.Intn(4); j++ {
+			corrupt[len(magic)+rng.Intn(len(corrupt)-2*len(magic))] = byte(rng.Intn(256))
+		}
+		r, err := NewReader(corrupt)
+		if err != nil {
+			continue
+		}
+		for c := range r.Columns() {
+			for {
+				vals, err := r.ReadColumn(c, 64)
+				if err != nil || len(vals) == 0 {
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// An RLE run of five 3s followed by a bit-packed run of 8 values with a
+	// bit width of 3.
+	data := []byte{5 << 1, 3, 1<<1 | 1}
+	packed := []uint32{0, 1, 2, 3, 4, 5, 6, 7}
+	var bits uint32
+	var nbits uint
+	for _, v := range packed {
+		bits |= v << nbits
+		nbits += 3
+		for nbits >= 8 {
+			data = append(data, byte(bits))
+			bits >>= 8
+			nbits -= 8
+		}
+	}
+	vals, n, err := decodeHybrid(data, 3, 13)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, append([]uint32{3, 3, 3, 3, 3}, packed...), vals)
+
+	_, _, err = decodeHybrid(data, 3, 14)
+	require.Error(t, err)
+
+	levels := []uint32{1, 1, 0, 1, 0, 0, 0, 1}
+	vals, _, err = decodeHybrid(appendHybrid(nil, 1, levels), 1, len(levels))
+	require.NoError(t, err)
+	require.Equal(t, levels, vals)
+}
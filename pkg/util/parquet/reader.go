@@ -0,0 +1,406 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// Reader reads the columns of a parquet file held in memory. Only flat
+// schemas, in which every column is a REQUIRED or OPTIONAL leaf of the root,
+// are supported.
+type Reader struct {
+	data    []byte
+	meta    *fileMetaData
+	columns []SchemaElement
+	readers []columnReader
+}
+
+// NewReader returns a Reader for the given parquet file.
+func NewReader(data []byte) (*Reader, error) {
+	if len(data) < 2*len(magic)+4 ||
+		!bytes.Equal(data[:len(magic)], magic) || !bytes.Equal(data[len(data)-len(magic):], magic) {
+		return nil, errors.New("parquet: not a parquet file")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(data[len(data)-len(magic)-4:]))
+	footerEnd := int64(len(data) - len(magic) - 4)
+	if footerLen > footerEnd-int64(len(magic)) {
+		return nil, errors.Errorf("parquet: invalid footer length %d", footerLen)
+	}
+	meta, err := readFileMetaData(&compactReader{buf: data[footerEnd-footerLen : footerEnd]})
+	if err != nil {
+		return nil, err
+	}
+	if meta.numRows < 0 {
+		return nil, errors.Errorf("parquet: invalid number of rows %d", meta.numRows)
+	}
+	if len(meta.schema) == 0 {
+		return nil, errors.New("parquet: file has no schema")
+	}
+	root := meta.schema[0]
+	columns := meta.schema[1:]
+	if int(root.NumChildren) != len(columns) {
+		return nil, errors.New("parquet: nested schemas are not supported")
+	}
+	for i := range columns {
+		c := &columns[i]
+		if c.NumChildren > 0 || !c.hasType {
+			return nil, errors.Errorf("parquet: nested column %q is not supported", c.Name)
+		}
+		if c.Repetition == RepetitionRepeated {
+			return nil, errors.Errorf("parquet: repeated column %q is not supported", c.Name)
+		}
+	}
+	var rows int64
+	for i := range meta.rowGroups {
+		g := &meta.rowGroups[i]
+		if len(g.columns) != len(columns) {
+			return nil, errors.Errorf("parquet: row group %d has %d columns, expected %d",
+				i, len(g.columns), len(columns))
+		}
+		if g.numRows < 0 {
+			return nil, errors.Errorf("parquet: invalid number of rows %d", g.numRows)
+		}
+		rows += g.numRows
+	}
+	if rows != meta.numRows {
+		return nil, errors.Errorf("parquet: row groups have %d rows, expected %d", rows, meta.numRows)
+	}
+
+	r := &Reader{data: data, meta: meta, columns: columns}
+	r.readers = make([]columnReader, len(columns))
+	for i := range r.readers {
+		r.readers[i] = columnReader{r: r, col: i, elem: &columns[i]}
+	}
+	return r, nil
+}
+
+// Columns returns the columns of the file.
+func (r *Reader) Columns() []SchemaElement {
+	return r.columns
+}
+
+// NumRows returns the number of rows of the file.
+func (r *Reader) NumRows() int64 {
+	return r.meta.numRows
+}
+
+// ReadColumn returns the next n values of the given column, or fewer if the
+// column has fewer values left. NULLs are returned as nil, and other values
+// as a bool, int32, int64, float32, float64 or, for byte arrays, a string,
+// depending on the physical type of the column.
+func (r *Reader) ReadColumn(col int, n int) ([]interface{}, error) {
+	if col < 0 || col >= len(r.readers) {
+		return nil, errors.Errorf("parquet: invalid column %d", col)
+	}
+	return r.readers[col].read(n)
+}
+
+// columnReader reads the values of a column, one row group at a time.
+type columnReader struct {
+	r    *Reader
+	col  int
+	elem *SchemaElement
+
+	// rowGroup is the index of the next row group to read.
+	rowGroup int
+	// chunk holds the remaining pages of the column chunk being read, and
+	// remaining the number of values left in them.
+	chunk     []byte
+	remaining int64
+	codec     CompressionCodec
+	dict      []interface{}
+	// values holds the decoded values of the current page not yet returned.
+	values []interface{}
+}
+
+func (c *columnReader) read(n int) ([]interface{}, error) {
+	var out []interface{}
+	for len(out) < n {
+		if len(c.values) == 0 {
+			if err := c.nextPage(); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, errors.Wrapf(err, "column %q", c.elem.Name)
+			}
+			continue
+		}
+		k := n - len(out)
+		if k > len(c.values) {
+			k = len(c.values)
+		}
+		out = append(out, c.values[:k]...)
+		c.values = c.values[k:]
+	}
+	return out, nil
+}
+
+// nextPage decodes the next data page of the column into c.values, moving on
+// to the next row group if needed. It returns io.EOF once all the values of
+// the column have been read.
+func (c *columnReader) nextPage() error {
+	for c.remaining == 0 {
+		if c.rowGroup >= len(c.r.meta.rowGroups) {
+			return io.EOF
+		}
+		if err := c.startChunk(&c.r.meta.rowGroups[c.rowGroup].columns[c.col]); err != nil {
+			return err
+		}
+		c.rowGroup++
+	}
+
+	for {
+		pr := compactReader{buf: c.chunk}
+		h, err := readPageHeader(&pr)
+		if err != nil {
+			return err
+		}
+		if int(h.compressedSize) > pr.remaining() {
+			return errors.New("parquet: truncated page")
+		}
+		payload := c.chunk[pr.pos : pr.pos+int(h.compressedSize)]
+		c.chunk = c.chunk[pr.pos+int(h.compressedSize):]
+
+		switch h.typ {
+		case pageTypeDictionary:
+			if h.dictionaryPage == nil {
+				return errors.New("parquet: dictionary page without header")
+			}
+			if err := c.readDictionaryPage(h, payload); err != nil {
+				return err
+			}
+		case pageTypeData:
+			if h.dataPage == nil {
+				return errors.New("parquet: data page without header")
+			}
+			return c.readDataPage(h, payload)
+		case pageTypeDataV2:
+			if h.dataPageV2 == nil {
+				return errors.New("parquet: data page without header")
+			}
+			return c.readDataPageV2(h, payload)
+		}
+		// Index pages are skipped.
+		if len(c.chunk) == 0 {
+			return errors.New("parquet: column chunk has no data pages")
+		}
+	}
+}
+
+func (c *columnReader) startChunk(chunk *columnChunk) error {
+	if !chunk.hasMeta {
+		return errors.New("parquet: column chunk has no metadata")
+	}
+	m := &chunk.meta
+	if m.typ != c.elem.Type {
+		return errors.Errorf("parquet: column chunk has type %d, expected %d", m.typ, c.elem.Type)
+	}
+	switch m.codec {
+	case CompressionUncompressed, CompressionSnappy, CompressionGzip:
+	default:
+		return errors.Errorf("parquet: compression codec %d is not supported", m.codec)
+	}
+	start := m.dataPageOffset
+	if m.dictionaryPageOffset > 0 && m.dictionaryPageOffset < start {
+		start = m.dictionaryPageOffset
+	}
+	end := start + m.totalCompressedSize
+	if start < int64(len(magic)) || m.totalCompressedSize < 0 || end > int64(len(c.r.data)) {
+		return errors.New("parquet: column chunk out of bounds")
+	}
+	if m.numValues < 0 {
+		return errors.Errorf("parquet: invalid number of values %d", m.numValues)
+	}
+	c.chunk = c.r.data[start:end]
+	c.remaining = m.numValues
+	c.codec = m.codec
+	c.dict = nil
+	return nil
+}
+
+func (c *columnReader) decompress(data []byte, size int32) ([]byte, error) {
+	switch c.codec {
+	case CompressionUncompressed:
+		return data, nil
+	case CompressionSnappy:
+		if n, err := snappy.DecodedLen(data); err != nil || n != int(size) {
+			return nil, errCorruptPage
+		}
+		return snappy.Decode(nil, data)
+	case CompressionGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		out, err := ioutil.ReadAll(io.LimitReader(gz, int64(size)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(out) != int(size) {
+			return nil, errCorruptPage
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("parquet: compression codec %d is not supported", c.codec)
+	}
+}
+
+func (c *columnReader) readDictionaryPage(h *pageHeader, payload []byte) error {
+	switch h.dictionaryPage.encoding {
+	case encodingPlain, encodingPlainDictionary:
+	default:
+		return errors.Errorf("parquet: dictionary encoding %d is not supported",
+			h.dictionaryPage.encoding)
+	}
+	data, err := c.decompress(payload, h.uncompressedSize)
+	if err != nil {
+		return err
+	}
+	c.dict, err = decodePlain(data, c.elem, int(h.dictionaryPage.numValues))
+	return err
+}
+
+func (c *columnReader) readDataPage(h *pageHeader, payload []byte) error {
+	data, err := c.decompress(payload, h.uncompressedSize)
+	if err != nil {
+		return err
+	}
+	numValues := int(h.dataPage.numValues)
+	if err := c.checkNumValues(numValues); err != nil {
+		return err
+	}
+	var defLevels []uint32
+	if c.elem.Repetition == RepetitionOptional {
+		if h.dataPage.defLevels != encodingRLE {
+			return errors.Errorf("parquet: definition level encoding %d is not supported",
+				h.dataPage.defLevels)
+		}
+		if len(data) < 4 {
+			return errCorruptPage
+		}
+		l := int64(binary.LittleEndian.Uint32(data))
+		if l > int64(len(data)-4) {
+			return errCorruptPage
+		}
+		if defLevels, _, err = decodeHybrid(data[4:4+l], 1, numValues); err != nil {
+			return err
+		}
+		data = data[4+l:]
+	}
+	return c.decodeValues(h.dataPage.encoding, data, numValues, defLevels)
+}
+
+func (c *columnReader) readDataPageV2(h *pageHeader, payload []byte) error {
+	v2 := h.dataPageV2
+	numValues := int(v2.numValues)
+	if err := c.checkNumValues(numValues); err != nil {
+		return err
+	}
+	if v2.repLevelsLen != 0 {
+		return errors.New("parquet: repetition levels are not supported")
+	}
+	if v2.defLevelsLen < 0 || int(v2.defLevelsLen) > len(payload) {
+		return errCorruptPage
+	}
+	// The levels of v2 pages are never compressed.
+	levels, data := payload[:v2.defLevelsLen], payload[v2.defLevelsLen:]
+	if v2.isCompressed {
+		var err error
+		if data, err = c.decompress(data, h.uncompressedSize-v2.defLevelsLen); err != nil {
+			return err
+		}
+	}
+	var defLevels []uint32
+	if c.elem.Repetition == RepetitionOptional {
+		var err error
+		if defLevels, _, err = decodeHybrid(levels, 1, numValues); err != nil {
+			return err
+		}
+	}
+	return c.decodeValues(v2.encoding, data, numValues, defLevels)
+}
+
+func (c *columnReader) checkNumValues(n int) error {
+	if n < 0 || int64(n) > c.remaining {
+		return errors.Errorf("parquet: page has %d values, only %d expected", n, c.remaining)
+	}
+	c.remaining -= int64(n)
+	return nil
+}
+
+// decodeValues decodes the values of a data page into c.values. defLevels,
+// if set, indicates which of the values are not NULL.
+func (c *columnReader) decodeValues(
+	enc encoding, data []byte, numValues int, defLevels []uint32,
+) error {
+	numNonNull := numValues
+	if defLevels != nil {
+		numNonNull = 0
+		for _, l := range defLevels {
+			if l > 1 {
+				return errCorruptPage
+			}
+			numNonNull += int(l)
+		}
+	}
+
+	var values []interface{}
+	switch enc {
+	case encodingPlain:
+		var err error
+		if values, err = decodePlain(data, c.elem, numNonNull); err != nil {
+			return err
+		}
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if c.dict == nil {
+			return errors.New("parquet: dictionary encoded page without dictionary")
+		}
+		if len(data) < 1 {
+			return errCorruptPage
+		}
+		indices, _, err := decodeHybrid(data[1:], int(data[0]), numNonNull)
+		if err != nil {
+			return err
+		}
+		values = make([]interface{}, len(indices))
+		for i, idx := range indices {
+			if int(idx) >= len(c.dict) {
+				return errors.Errorf("parquet: invalid dictionary index %d", idx)
+			}
+			values[i] = c.dict[idx]
+		}
+	default:
+		return errors.Errorf("parquet: encoding %d is not supported", enc)
+	}
+
+	if defLevels == nil {
+		c.values = values
+		return nil
+	}
+	c.values = make([]interface{}, numValues)
+	j := 0
+	for i, l := range defLevels {
+		if l == 1 {
+			c.values[i] = values[j]
+			j++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+const (
+	// defaultRowGroupSize is the default target size of row groups, in bytes.
+	defaultRowGroupSize = 128 << 20
+	// pageSize is the target size of data pages, in bytes.
+	pageSize = 1 << 20
+)
+
+// Column describes a column of a file being written. All the columns are
+// OPTIONAL leaves of the root of the schema.
+type Column struct {
+	Name          string
+	Type          Type
+	ConvertedType ConvertedType
+}
+
+// WriterOptions are the options of a Writer.
+type WriterOptions struct {
+	// Compression is the codec the pages are compressed with. Only the
+	// uncompressed, snappy and gzip codecs are supported.
+	Compression CompressionCodec
+	// RowGroupSize is the target size of row groups, in bytes. Zero means the
+	// default of 128MiB.
+	RowGroupSize int64
+}
+
+// Writer writes a parquet file. The values of each column chunk are PLAIN
+// encoded, in v1 data pages of about 1MiB.
+type Writer struct {
+	w       io.Writer
+	columns []Column
+	opts    WriterOptions
+	// offset is the number of bytes written so far.
+	offset int64
+	// values buffers the values of each column of the current row group, and
+	// bufferedSize their approximate encoded size.
+	values       [][]interface{}
+	bufferedRows int64
+	bufferedSize int64
+	meta         fileMetaData
+	err          error
+}
+
+// NewWriter returns a Writer which writes a parquet file with the given
+// columns to w.
+func NewWriter(w io.Writer, columns []Column, opts WriterOptions) (*Writer, error) {
+	switch opts.Compression {
+	case CompressionUncompressed, CompressionSnappy, CompressionGzip:
+	default:
+		return nil, errors.Errorf("parquet: compression codec %d is not supported", opts.Compression)
+	}
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = defaultRowGroupSize
+	}
+	pw := &Writer{
+		w:       w,
+		columns: columns,
+		opts:    opts,
+		values:  make([][]interface{}, len(columns)),
+		meta: fileMetaData{
+			version:   1,
+			createdBy: "CockroachDB",
+		},
+	}
+	pw.meta.schema = append(pw.meta.schema, SchemaElement{
+		Name:          "schema",
+		NumChildren:   int32(len(columns)),
+		ConvertedType: ConvertedTypeNone,
+	})
+	for _, c := range columns {
+		switch c.Type {
+		case TypeBoolean, TypeInt32, TypeInt64, TypeFloat, TypeDouble, TypeByteArray:
+		default:
+			return nil, errors.Errorf("parquet: writing physical type %d is not supported", c.Type)
+		}
+		pw.meta.schema = append(pw.meta.schema, SchemaElement{
+			Name:          c.Name,
+			Type:          c.Type,
+			hasType:       true,
+			Repetition:    RepetitionOptional,
+			ConvertedType: c.ConvertedType,
+		})
+	}
+	pw.write(magic)
+	return pw, pw.err
+}
+
+// AddRow adds a row to the file. Each value must be nil, for NULL, or of the
+// Go type corresponding to the physical type of its column, with byte arrays
+// given as a string or []byte. The values are retained until the row group
+// they belong to is written.
+func (w *Writer) AddRow(row []interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(row) != len(w.columns) {
+		return errors.Errorf("parquet: row has %d values, expected %d", len(row), len(w.columns))
+	}
+	for i, v := range row {
+		w.values[i] = append(w.values[i], v)
+		w.bufferedSize += valueSize(v)
+	}
+	w.bufferedRows++
+	if w.bufferedSize >= w.opts.RowGroupSize {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the footer of the file. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if w.bufferedRows > 0 {
+		if err := w.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	if w.err != nil {
+		return w.err
+	}
+	var cw compactWriter
+	writeFileMetaData(&cw, &w.meta)
+	w.write(cw.buf)
+	w.write(appendUint32(nil, uint32(len(cw.buf))))
+	w.write(magic)
+	return w.err
+}
+
+func (w *Writer) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	w.err = err
+}
+
+func valueSize(v interface{}) int64 {
+	switch v := v.(type) {
+	case string:
+		return int64(4 + len(v))
+	case []byte:
+		return int64(4 + len(v))
+	case int64, float64:
+		return 8
+	default:
+		return 4
+	}
+}
+
+// flushRowGroup writes the buffered rows as a row group.
+func (w *Writer) flushRowGroup() error {
+	g := rowGroup{numRows: w.bufferedRows}
+	for i, c := range w.columns {
+		chunk, err := w.writeColumnChunk(c, w.values[i])
+		if err != nil {
+			w.err = err
+			return err
+		}
+		g.totalByteSize += chunk.meta.totalUncompressedSize
+		g.columns = append(g.columns, chunk)
+		w.values[i] = w.values[i][:0]
+	}
+	w.meta.rowGroups = append(w.meta.rowGroups, g)
+	w.meta.numRows += w.bufferedRows
+	w.bufferedRows, w.bufferedSize = 0, 0
+	return w.err
+}
+
+func (w *Writer) writeColumnChunk(c Column, values []interface{}) (columnChunk, error) {
+	chunk := columnChunk{
+		fileOffset: w.offset,
+		hasMeta:    true,
+		meta: columnMetaData{
+			typ:            c.Type,
+			encodings:      []encoding{encodingPlain, encodingRLE},
+			pathInSchema:   []string{c.Name},
+			codec:          w.opts.Compression,
+			numValues:      int64(len(values)),
+			dataPageOffset: w.offset,
+		},
+	}
+	for len(values) > 0 {
+		n, size := 0, int64(0)
+		for n < len(values) && size < pageSize {
+			size += valueSize(values[n])
+			n++
+		}
+		uncompressed, err := w.writeDataPage(c, values[:n])
+		if err != nil {
+			return columnChunk{}, err
+		}
+		chunk.meta.totalUncompressedSize += uncompressed
+		values = values[n:]
+	}
+	chunk.meta.totalCompressedSize = w.offset - chunk.fileOffset
+	return chunk, w.err
+}
+
+// writeDataPage writes a v1 data page holding the given values, returning its
+// uncompressed size including the page header.
+func (w *Writer) writeDataPage(c Column, values []interface{}) (int64, error) {
+	defLevels := make([]uint32, len(values))
+	nonNull := make([]interface{}, 0, len(values))
+	for i, v := range values {
+		if v != nil {
+			defLevels[i] = 1
+			nonNull = append(nonNull, v)
+		}
+	}
+	levels := appendHybrid(nil, 1, defLevels)
+	body := appendUint32(make([]byte, 0, 4+len(levels)), uint32(len(levels)))
+	body = append(body, levels...)
+	body, err := appendPlain(body, c.Type, nonNull)
+	if err != nil {
+		return 0, errors.Wrapf(err, "column %q", c.Name)
+	}
+
+	compressed := body
+	switch w.opts.Compression {
+	case CompressionSnappy:
+		compressed = snappy.Encode(nil, body)
+	case CompressionGzip:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return 0, err
+		}
+		if err := gz.Close(); err != nil {
+			return 0, err
+		}
+		compressed = buf.Bytes()
+	}
+
+	h := pageHeader{
+		typ:              pageTypeData,
+		uncompressedSize: int32(len(body)),
+		compressedSize:   int32(len(compressed)),
+		dataPage: &dataPageHeader{
+			numValues: int32(len(values)),
+			encoding:  encodingPlain,
+			defLevels: encodingRLE,
+			repLevels: encodingRLE,
+		},
+	}
+	var cw compactWriter
+	writeDataPageHeader(&cw, &h)
+	w.write(cw.buf)
+	w.write(compressed)
+	return int64(len(cw.buf) + len(body)), w.err
+}
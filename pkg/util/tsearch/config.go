@@ -0,0 +1,192 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Config is a text search configuration, which determines how documents are
+// split into words and how words are normalized into lexemes.
+type Config struct {
+	name string
+	// stopwords is the set of words which are ignored, if any.
+	stopwords map[string]struct{}
+	// stem, if set, reduces a lowercase word to its stem.
+	stem func(string) string
+}
+
+var (
+	// SimpleConfig lowercases words and does not ignore any of them.
+	SimpleConfig = &Config{name: "simple"}
+
+	// EnglishConfig lowercases and stems words, and ignores common English
+	// words.
+	EnglishConfig = &Config{name: "english", stopwords: englishStopwords, stem: porterStem}
+)
+
+// DefaultConfigName is the name of the configuration used when none is
+// specified.
+const DefaultConfigName = "english"
+
+// GetConfig returns the configuration with the given name, which may be
+// qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	switch strings.TrimPrefix(strings.ToLower(name), "pg_catalog.") {
+	case "simple":
+		return SimpleConfig, nil
+	case "english":
+		return EnglishConfig, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// normalize returns the lexeme of the given word, or false if the word is a
+// stopword.
+func (c *Config) normalize(word string) (string, bool) {
+	word = strings.ToLower(word)
+	if _, ok := c.stopwords[word]; ok {
+		return "", false
+	}
+	if c.stem != nil {
+		word = c.stem(word)
+	}
+	if len(word) > maxLexemeLen {
+		word = word[:maxLexemeLen]
+		for !utf8.ValidString(word) {
+			word = word[:len(word)-1]
+		}
+	}
+	return word, true
+}
+
+// token is a word of a document, as the byte offsets of its start and end.
+type token struct {
+	start, end int
+}
+
+// tokenize splits a document into words, which are maximal sequences of
+// letters and digits.
+func tokenize(doc string) []token {
+	var res []token
+	start := -1
+	for i, r := range doc {
+		isWordChar := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordChar && start < 0 {
+			start = i
+		} else if !isWordChar && start >= 0 {
+			res = append(res, token{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, token{start: start, end: len(doc)})
+	}
+	return res
+}
+
+// ToTSVector converts a document into a tsvector. The positions of the
+// lexemes are the positions of the words in the document, counting stopwords.
+func (c *Config) ToTSVector(doc string) TSVector {
+	var v TSVector
+	for i, tok := range tokenize(doc) {
+		lexeme, ok := c.normalize(doc[tok.start:tok.end])
+		if !ok {
+			continue
+		}
+		pos := i + 1
+		if pos > MaxPosition {
+			pos = MaxPosition
+		}
+		v = append(v, Lexeme{Text: lexeme, Positions: []Position{{Pos: uint16(pos)}}})
+	}
+	return v.normalize()
+}
+
+// ToTSQuery parses a tsquery and normalizes its lexemes. Lexemes which are
+// stopwords are removed from the query. Lexemes which are made of several
+// words are replaced by a phrase of the words.
+func (c *Config) ToTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, func(n *Node) (*Node, error) {
+		var res *Node
+		// dist is the distance from the last word in the phrase.
+		dist := 0
+		for _, tok := range tokenize(n.Lexeme) {
+			dist++
+			lexeme, ok := c.normalize(n.Lexeme[tok.start:tok.end])
+			if !ok {
+				continue
+			}
+			word := &Node{Op: OpLexeme, Lexeme: lexeme, Prefix: n.Prefix, Weights: n.Weights}
+			if res == nil {
+				res = word
+			} else {
+				res = &Node{Op: OpPhrase, Distance: uint16(dist), Left: res, Right: word}
+			}
+			dist = 0
+		}
+		if res == nil {
+			return stopword, nil
+		}
+		return res, nil
+	})
+}
+
+// PlainToTSQuery converts plain text into a tsquery matching all of its
+// words.
+func (c *Config) PlainToTSQuery(s string) TSQuery {
+	var root *Node
+	for _, tok := range tokenize(s) {
+		lexeme, ok := c.normalize(s[tok.start:tok.end])
+		if !ok {
+			continue
+		}
+		word := &Node{Op: OpLexeme, Lexeme: lexeme}
+		if root == nil {
+			root = word
+		} else {
+			root = &Node{Op: OpAnd, Left: root, Right: word}
+		}
+	}
+	return TSQuery{Root: root}
+}
+
+// englishStopwords is the list of English stopwords of PostgreSQL.
+var englishStopwords = makeSet(`
+i me my myself we our ours ourselves you your yours yourself yourselves he
+him his himself she her hers herself it its itself they them their theirs
+themselves what which who whom this that these those am is are was were be
+been being have has had having do does did doing a an the and but if or
+because as until while of at by for with about against between into through
+during before after above below to from up down in out on off over under
+again further then once here there when where why how all any both each few
+more most other some such no nor not only own same so than too very s t can
+will just don should now
+`)
+
+func makeSet(words string) map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, w := range strings.Fields(words) {
+		res[w] = struct{}{}
+	}
+	return res
+}
@@ -0,0 +1,237 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// The binary encodings of tsvectors and tsqueries are the ones of the binary
+// format of PostgreSQL, which are also used to store them.
+
+var errInvalidEncoding = pgerror.New(pgcode.InvalidBinaryRepresentation, "invalid text search encoding")
+
+// Encode appends the binary encoding of the tsvector to buf: the number of
+// lexemes, followed by each lexeme as a NUL-terminated string, its number of
+// positions and its positions, with their weight in their two high bits.
+func (v TSVector) Encode(buf []byte) []byte {
+	buf = appendUint32(buf, uint32(len(v)))
+	for _, l := range v {
+		buf = append(buf, l.Text...)
+		buf = append(buf, 0)
+		buf = appendUint16(buf, uint16(len(l.Positions)))
+		for _, p := range l.Positions {
+			buf = appendUint16(buf, uint16(p.Weight)<<14|p.Pos)
+		}
+	}
+	return buf
+}
+
+// DecodeTSVector decodes a tsvector encoded with Encode.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	n, b, err := readUint32(b)
+	if err != nil {
+		return nil, err
+	}
+	// Each lexeme takes at least four bytes.
+	if int(n) > len(b)/4 {
+		return nil, errInvalidEncoding
+	}
+	v := make(TSVector, n)
+	for i := range v {
+		if v[i].Text, b, err = readCString(b); err != nil {
+			return nil, err
+		}
+		var npos uint16
+		if npos, b, err = readUint16(b); err != nil {
+			return nil, err
+		}
+		if npos > 0 {
+			v[i].Positions = make([]Position, npos)
+		}
+		for j := range v[i].Positions {
+			var p uint16
+			if p, b, err = readUint16(b); err != nil {
+				return nil, err
+			}
+			v[i].Positions[j] = Position{Pos: p & MaxPosition, Weight: Weight(p >> 14)}
+		}
+	}
+	if len(b) != 0 {
+		return nil, errInvalidEncoding
+	}
+	return v.normalize(), nil
+}
+
+// Encode appends the binary encoding of the tsquery to buf: the number of
+// nodes, followed by the nodes in prefix order, with the right operand of
+// binary operators before their left operand.
+func (q TSQuery) Encode(buf []byte) []byte {
+	var count uint32
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		count++
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(q.Root)
+	buf = appendUint32(buf, count)
+	if q.Root != nil {
+		buf = q.Root.encode(buf)
+	}
+	return buf
+}
+
+const (
+	itemLexeme   = 1
+	itemOperator = 2
+)
+
+func (n *Node) encode(buf []byte) []byte {
+	switch n.Op {
+	case OpLexeme:
+		buf = append(buf, itemLexeme, n.Weights)
+		if n.Prefix {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		buf = append(buf, n.Lexeme...)
+		return append(buf, 0)
+	case OpNot:
+		buf = append(buf, itemOperator, byte(n.Op))
+		return n.Left.encode(buf)
+	default:
+		buf = append(buf, itemOperator, byte(n.Op))
+		if n.Op == OpPhrase {
+			buf = appendUint16(buf, n.Distance)
+		}
+		buf = n.Right.encode(buf)
+		return n.Left.encode(buf)
+	}
+}
+
+// DecodeTSQuery decodes a tsquery encoded with Encode.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	n, b, err := readUint32(b)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if n == 0 {
+		if len(b) != 0 {
+			return TSQuery{}, errInvalidEncoding
+		}
+		return TSQuery{}, nil
+	}
+	d := tsQueryDecoder{buf: b, remaining: int(n)}
+	root, err := d.node()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	if d.remaining != 0 || len(d.buf) != 0 {
+		return TSQuery{}, errInvalidEncoding
+	}
+	return TSQuery{Root: root}, nil
+}
+
+type tsQueryDecoder struct {
+	buf       []byte
+	remaining int
+}
+
+func (d *tsQueryDecoder) node() (*Node, error) {
+	if d.remaining == 0 || len(d.buf) < 2 {
+		return nil, errInvalidEncoding
+	}
+	d.remaining--
+	typ, b := d.buf[0], d.buf[1:]
+	switch typ {
+	case itemLexeme:
+		if len(b) < 2 {
+			return nil, errInvalidEncoding
+		}
+		n := &Node{Op: OpLexeme, Weights: b[0] & 0xf, Prefix: b[1] != 0}
+		var err error
+		if n.Lexeme, d.buf, err = readCString(b[2:]); err != nil {
+			return nil, err
+		}
+		if n.Lexeme == "" {
+			return nil, errInvalidEncoding
+		}
+		return n, nil
+	case itemOperator:
+		n := &Node{Op: Operator(b[0])}
+		d.buf = b[1:]
+		switch n.Op {
+		case OpNot:
+			var err error
+			n.Left, err = d.node()
+			return n, err
+		case OpPhrase:
+			var err error
+			if n.Distance, d.buf, err = readUint16(d.buf); err != nil {
+				return nil, err
+			}
+			if n.Distance > MaxPosition {
+				return nil, errInvalidEncoding
+			}
+		case OpAnd, OpOr:
+		default:
+			return nil, errInvalidEncoding
+		}
+		var err error
+		if n.Right, err = d.node(); err != nil {
+			return nil, err
+		}
+		if n.Left, err = d.node(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, errInvalidEncoding
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func readUint32(b []byte) (uint32, []byte, error) {
+	if len(b) < 4 {
+		return 0, nil, errInvalidEncoding
+	}
+	return binary.BigEndian.Uint32(b), b[4:], nil
+}
+
+func readUint16(b []byte) (uint16, []byte, error) {
+	if len(b) < 2 {
+		return 0, nil, errInvalidEncoding
+	}
+	return binary.BigEndian.Uint16(b), b[2:], nil
+}
+
+func readCString(b []byte) (string, []byte, error) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return "", nil, errInvalidEncoding
+	}
+	return string(b[:i]), b[i+1:], nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "sort"
+
+// Matches returns whether the given tsvector matches the given tsquery, which
+// is the semantics of the @@ operator.
+func Matches(v TSVector, q TSQuery) bool {
+	if q.Root == nil {
+		return false
+	}
+	return evalNode(v, q.Root)
+}
+
+func evalNode(v TSVector, n *Node) bool {
+	switch n.Op {
+	case OpLexeme:
+		_, found, _ := lexemePositions(v, n)
+		return found
+	case OpNot:
+		return !evalNode(v, n.Left)
+	case OpAnd:
+		return evalNode(v, n.Left) && evalNode(v, n.Right)
+	case OpOr:
+		return evalNode(v, n.Left) || evalNode(v, n.Right)
+	default:
+		res := evalPhrase(v, n)
+		return res.matches()
+	}
+}
+
+// lexemePositions returns the positions of the lexemes matched by the given
+// lexeme node whose weight is allowed by the node. found is set if a lexeme
+// matched; noPos is set if one of the matched lexemes has no positions, in
+// which case phrase operators can't be checked and are assumed to match.
+func lexemePositions(v TSVector, n *Node) (positions []uint16, found, noPos bool) {
+	start, end := 0, 0
+	if n.Prefix {
+		start, end = v.findPrefix(n.Lexeme)
+	} else if i := v.find(n.Lexeme); i >= 0 {
+		start, end = i, i+1
+	}
+	for i := start; i < end; i++ {
+		if len(v[i].Positions) == 0 {
+			found, noPos = true, true
+			continue
+		}
+		for _, p := range v[i].Positions {
+			if n.Weights == 0 || n.Weights&(1<<p.Weight) != 0 {
+				positions = append(positions, p.Pos)
+				found = true
+			}
+		}
+	}
+	if end-start > 1 {
+		sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	}
+	return positions, found, noPos
+}
+
+// phraseResult is the result of the evaluation of a tsquery in a phrase. It is
+// the set of positions at which the query matches, or its complement if
+// negated is set.
+type phraseResult struct {
+	positions map[uint16]struct{}
+	negated   bool
+	// noPos is set if positions could not be checked, in which case the
+	// query is assumed to match.
+	noPos bool
+}
+
+func (r phraseResult) matches() bool {
+	return r.noPos || r.negated || len(r.positions) > 0
+}
+
+// contains returns whether the result matches at the given position.
+func (r phraseResult) contains(pos int) bool {
+	if pos < 0 || pos > MaxPosition {
+		return r.negated
+	}
+	_, ok := r.positions[uint16(pos)]
+	return ok != r.negated
+}
+
+// evalPhrase evaluates the given node at the positions of the document.
+func evalPhrase(v TSVector, n *Node) phraseResult {
+	switch n.Op {
+	case OpLexeme:
+		positions, _, noPos := lexemePositions(v, n)
+		res := phraseResult{positions: make(map[uint16]struct{}, len(positions)), noPos: noPos}
+		for _, p := range positions {
+			res.positions[p] = struct{}{}
+		}
+		return res
+	case OpNot:
+		res := evalPhrase(v, n.Left)
+		if res.noPos {
+			return phraseResult{}
+		}
+		res.negated = !res.negated
+		return res
+	}
+
+	left, right := evalPhrase(v, n.Left), evalPhrase(v, n.Right)
+	if left.noPos || right.noPos {
+		// At least one side can't be located in the document: fall back to the
+		// boolean semantics of AND for phrases.
+		if n.Op == OpOr {
+			return phraseResult{noPos: left.matches() || right.matches()}
+		}
+		return phraseResult{noPos: left.matches() && right.matches()}
+	}
+
+	// offset is the distance between the positions of the left operand and the
+	// positions of the right operand.
+	offset := 0
+	if n.Op == OpPhrase {
+		offset = int(n.Distance)
+	}
+	in := func(pos int) bool {
+		switch n.Op {
+		case OpOr:
+			return left.contains(pos) || right.contains(pos)
+		default:
+			return left.contains(pos-offset) && right.contains(pos)
+		}
+	}
+
+	// The candidate positions are the positions of both operands, shifted to
+	// the positions of the right operand. If the operator matches at all the
+	// positions but a few, which is the case when both operands are negated or
+	// when one operand of an OR is, the result is negated and its positions
+	// are the candidates at which the operator does not match.
+	res := phraseResult{positions: make(map[uint16]struct{})}
+	if (left.negated && right.negated) || (n.Op == OpOr && (left.negated || right.negated)) {
+		res.negated = true
+		matches := in
+		in = func(pos int) bool { return !matches(pos) }
+	}
+	add := func(pos int) {
+		if pos >= 0 && pos <= MaxPosition && in(pos) {
+			res.positions[uint16(pos)] = struct{}{}
+		}
+	}
+	for p := range left.positions {
+		add(int(p) + offset)
+	}
+	for p := range right.positions {
+		add(int(p))
+	}
+	return res
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// HeadlineOptions are the options of Headline.
+type HeadlineOptions struct {
+	// StartSel and StopSel delimit the words matching the query.
+	StartSel, StopSel string
+	// MaxWords and MinWords are the longest and shortest headlines to output,
+	// in words.
+	MaxWords, MinWords int
+	// HighlightAll outputs the whole document.
+	HighlightAll bool
+}
+
+// DefaultHeadlineOptions returns the default options of Headline.
+func DefaultHeadlineOptions() HeadlineOptions {
+	return HeadlineOptions{
+		StartSel: "<b>",
+		StopSel:  "</b>",
+		MaxWords: 35,
+		MinWords: 15,
+	}
+}
+
+// ParseHeadlineOptions parses a comma-separated list of options of the form
+// `option=value`, as in `MaxWords=10, StartSel=<<`, on top of the default
+// options. Values may be quoted with double quotes.
+func ParseHeadlineOptions(s string) (HeadlineOptions, error) {
+	opts := DefaultHeadlineOptions()
+	for _, item := range splitHeadlineOptions(s) {
+		eq := strings.IndexByte(item, '=')
+		if eq < 0 {
+			return opts, pgerror.Newf(pgcode.Syntax, "invalid headline parameter: %q", item)
+		}
+		key, val := strings.TrimSpace(item[:eq]), strings.TrimSpace(item[eq+1:])
+		if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+			val = val[1 : len(val)-1]
+		}
+		var err error
+		switch strings.ToLower(key) {
+		case "startsel":
+			opts.StartSel = val
+		case "stopsel":
+			opts.StopSel = val
+		case "maxwords":
+			opts.MaxWords, err = strconv.Atoi(val)
+		case "minwords":
+			opts.MinWords, err = strconv.Atoi(val)
+		case "highlightall":
+			switch strings.ToLower(val) {
+			case "1", "on", "true", "t", "y", "yes":
+				opts.HighlightAll = true
+			default:
+				opts.HighlightAll = false
+			}
+		case "shortword", "maxfragments", "fragmentdelimiter":
+			return opts, pgerror.Newf(pgcode.FeatureNotSupported,
+				"headline parameter %q is not supported", key)
+		default:
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized headline parameter: %q", key)
+		}
+		if err != nil {
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid value for headline parameter %q: %q", key, val)
+		}
+	}
+	if !opts.HighlightAll {
+		if opts.MinWords >= opts.MaxWords {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be less than MaxWords")
+		}
+		if opts.MinWords <= 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be positive")
+		}
+	}
+	return opts, nil
+}
+
+// splitHeadlineOptions splits a list of options on the commas which are not
+// quoted.
+func splitHeadlineOptions(s string) []string {
+	var res []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		res = append(res, s[start:])
+	}
+	return res
+}
+
+// Headline returns an excerpt of the given document with the words matching
+// the query highlighted, like the ts_headline function of PostgreSQL. If
+// there are no matches, the excerpt is the first MinWords words of the
+// document. Otherwise, it is the whole document if it has at most MaxWords
+// words, or the window of MaxWords words which contains the most matches.
+func (c *Config) Headline(doc string, q TSQuery, opts HeadlineOptions) string {
+	toks := tokenize(doc)
+	if len(toks) == 0 {
+		return doc
+	}
+	nodes := q.positiveLexemes()
+	matches := make([]bool, len(toks))
+	for i, tok := range toks {
+		lexeme, ok := c.normalize(doc[tok.start:tok.end])
+		if !ok {
+			continue
+		}
+		for _, n := range nodes {
+			if lexeme == n.Lexeme || (n.Prefix && strings.HasPrefix(lexeme, n.Lexeme)) {
+				matches[i] = true
+				break
+			}
+		}
+	}
+
+	start, end := 0, len(toks)
+	if !opts.HighlightAll {
+		start, end = headlineWindow(matches, opts)
+	}
+
+	var buf strings.Builder
+	textStart, textEnd := toks[start].start, toks[end-1].end
+	if opts.HighlightAll {
+		textStart, textEnd = 0, len(doc)
+	}
+	last := textStart
+	for i := start; i < end; i++ {
+		if !matches[i] {
+			continue
+		}
+		buf.WriteString(doc[last:toks[i].start])
+		buf.WriteString(opts.StartSel)
+		buf.WriteString(doc[toks[i].start:toks[i].end])
+		buf.WriteString(opts.StopSel)
+		last = toks[i].end
+	}
+	buf.WriteString(doc[last:textEnd])
+	return buf.String()
+}
+
+// headlineWindow returns the range of words of the headline.
+func headlineWindow(matches []bool, opts HeadlineOptions) (start, end int) {
+	n := len(matches)
+	best, bestCount := -1, 0
+	for i := range matches {
+		if !matches[i] {
+			continue
+		}
+		count := 0
+		for j := i; j < n && j < i+opts.MaxWords; j++ {
+			if matches[j] {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+	if best < 0 {
+		if n > opts.MinWords {
+			n = opts.MinWords
+		}
+		return 0, n
+	}
+	if n <= opts.MaxWords {
+		return 0, n
+	}
+	start, end = best, best+opts.MaxWords
+	if end > n {
+		end = n
+	}
+	// Extend short windows at the end of the document backwards.
+	if end-start < opts.MinWords {
+		start = end - opts.MinWords
+		if start < 0 {
+			start = 0
+		}
+	}
+	return start, end
+}
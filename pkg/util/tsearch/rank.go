@@ -0,0 +1,217 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// RankWeights are the weights given to the positions of lexemes by Rank,
+// indexed by Weight: {D, C, B, A}.
+type RankWeights [4]float64
+
+// DefaultRankWeights are the default weights of Rank.
+var DefaultRankWeights = RankWeights{0.1, 0.2, 0.4, 1.0}
+
+// The normalization flags of Rank, which can be combined.
+const (
+	// RankNormLogLength divides the rank by 1 + the logarithm of the length of
+	// the document.
+	RankNormLogLength = 0x01
+	// RankNormLength divides the rank by the length of the document.
+	RankNormLength = 0x02
+	// RankNormExtDist is only used by the cover density ranking, which is not
+	// supported, and is ignored.
+	RankNormExtDist = 0x04
+	// RankNormUniq divides the rank by the number of unique words in the
+	// document.
+	RankNormUniq = 0x08
+	// RankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	RankNormLogUniq = 0x10
+	// RankNormRDivRPlus1 divides the rank by itself + 1.
+	RankNormRDivRPlus1 = 0x20
+)
+
+// Rank computes the relevance of the given document for the given query, based
+// on the frequency of its matching lexemes, like the ts_rank function of
+// PostgreSQL. If the query is a conjunction, the proximity of the matching
+// lexemes is taken into account.
+func Rank(w RankWeights, v TSVector, q TSQuery, method int) float32 {
+	if len(v) == 0 || q.Root == nil {
+		return 0
+	}
+	var res float64
+	if q.Root.Op == OpAnd || q.Root.Op == OpPhrase {
+		res = rankAnd(w, v, q)
+	} else {
+		res = rankOr(w, v, q)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if method&RankNormLogLength != 0 {
+		res /= math.Log(float64(documentLength(v)+1)) / math.Log(2.0)
+	}
+	if method&RankNormLength != 0 {
+		if l := documentLength(v); l > 0 {
+			res /= float64(l)
+		}
+	}
+	if method&RankNormUniq != 0 {
+		res /= float64(len(v))
+	}
+	if method&RankNormLogUniq != 0 {
+		res /= math.Log(float64(len(v)+1)) / math.Log(2.0)
+	}
+	if method&RankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return float32(res)
+}
+
+// documentLength returns the number of words of a document, which is the
+// total number of positions of its lexemes; lexemes without positions count
+// as one word.
+func documentLength(v TSVector) int {
+	n := 0
+	for _, l := range v {
+		if len(l.Positions) == 0 {
+			n++
+		} else {
+			n += len(l.Positions)
+		}
+	}
+	return n
+}
+
+// uniqueLexemes returns the distinct lexeme nodes of the query.
+func uniqueLexemes(q TSQuery) []*Node {
+	nodes := q.Lexemes()
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Lexeme != nodes[j].Lexeme {
+			return nodes[i].Lexeme < nodes[j].Lexeme
+		}
+		return !nodes[i].Prefix && nodes[j].Prefix
+	})
+	res := nodes[:0]
+	for i, n := range nodes {
+		if i > 0 && n.Lexeme == nodes[i-1].Lexeme && n.Prefix == nodes[i-1].Prefix {
+			continue
+		}
+		res = append(res, n)
+	}
+	return res
+}
+
+// matchingLexemes returns the lexemes of v matched by the given node,
+// ignoring weights.
+func matchingLexemes(v TSVector, n *Node) TSVector {
+	if n.Prefix {
+		start, end := v.findPrefix(n.Lexeme)
+		return v[start:end]
+	}
+	if i := v.find(n.Lexeme); i >= 0 {
+		return v[i : i+1]
+	}
+	return nil
+}
+
+// rankPositions returns the positions of a lexeme, or a single position with
+// the default weight if it has none.
+func rankPositions(l Lexeme, noPos Position) []Position {
+	if len(l.Positions) == 0 {
+		return []Position{noPos}
+	}
+	return l.Positions
+}
+
+func rankOr(w RankWeights, v TSVector, q TSQuery) float64 {
+	nodes := uniqueLexemes(q)
+	var res float64
+	for _, n := range nodes {
+		for _, l := range matchingLexemes(v, n) {
+			// The rank of a lexeme is the sum of the weights of its positions,
+			// decreasing with the square of their rank, with the highest weight
+			// counted first: sum(w(i)/i^2) tends to pi^2/6.
+			var resj float64
+			wjm, jm := -1.0, 0
+			for j, p := range rankPositions(l, Position{}) {
+				wp := w[p.Weight]
+				resj += wp / float64((j+1)*(j+1))
+				if wp > wjm {
+					wjm, jm = wp, j
+				}
+			}
+			res += (wjm + resj - wjm/float64((jm+1)*(jm+1))) / 1.64493406685
+		}
+	}
+	if len(nodes) > 0 {
+		res /= float64(len(nodes))
+	}
+	return res
+}
+
+func rankAnd(w RankWeights, v TSVector, q TSQuery) float64 {
+	nodes := uniqueLexemes(q)
+	if len(nodes) < 2 {
+		return rankOr(w, v, q)
+	}
+	// Lexemes without positions are considered to be far from the others.
+	noPos := Position{Pos: MaxPosition}
+	res := -1.0
+	// positions[i] are the positions of the last lexeme matched by nodes[i],
+	// and hasNoPos[i] is set if that lexeme has no positions.
+	positions := make([][]Position, len(nodes))
+	hasNoPos := make([]bool, len(nodes))
+	for i, n := range nodes {
+		for _, l := range matchingLexemes(v, n) {
+			positions[i] = rankPositions(l, noPos)
+			hasNoPos[i] = len(l.Positions) == 0
+			for k := 0; k < i; k++ {
+				if positions[k] == nil {
+					continue
+				}
+				for _, p := range positions[i] {
+					for _, o := range positions[k] {
+						dist := int(p.Pos) - int(o.Pos)
+						if dist < 0 {
+							dist = -dist
+						}
+						if dist == 0 {
+							if !hasNoPos[i] && !hasNoPos[k] {
+								continue
+							}
+							dist = MaxPosition + 1
+						}
+						curw := math.Sqrt(w[p.Weight] * w[o.Weight] * wordDistance(dist))
+						if res < 0 {
+							res = curw
+						} else {
+							res = 1 - (1-res)*(1-curw)
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// wordDistance returns the factor of the rank of two lexemes at the given
+// distance.
+func wordDistance(dist int) float64 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return 1.0 / (1.005 + 0.05*math.Exp(float64(dist)/1.5-2))
+}
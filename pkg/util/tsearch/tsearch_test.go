@@ -0,0 +1,387 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input string
+		exp   string
+		err   string
+	}{
+		{``, ``, ``},
+		{`a fat cat`, `'a' 'cat' 'fat'`, ``},
+		{`cat fat cat`, `'cat' 'fat'`, ``},
+		{`  'spaced  out'  `, `'spaced  out'`, ``},
+		{`'it''s' back\ slash`, `'back slash' 'it''s'`, ``},
+		{`'a\\b'`, `'a\\b'`, ``},
+		{`fat:2,4 cat:3A,1b rat:5C,5A`, `'cat':1B,3A 'fat':2,4 'rat':5A`, ``},
+		{`a:3 a:1,2`, `'a':1,2,3`, ``},
+		{`a:99999`, `'a':16383`, ``},
+		{`a:`, ``, `syntax error`},
+		{`a:0`, ``, `wrong position info`},
+		{`a:1x`, ``, `syntax error`},
+		{`'unterminated`, ``, `syntax error`},
+		{`''`, ``, `syntax error`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := v.String(); s != tc.exp {
+				t.Fatalf("expected %s, got %s", tc.exp, s)
+			}
+			// The output must parse back to the same tsvector.
+			v2, err := ParseTSVector(v.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Compare(v2) != 0 {
+				t.Fatalf("%s did not round-trip: got %s", v, v2)
+			}
+		})
+	}
+}
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input string
+		exp   string
+		err   string
+	}{
+		{``, ``, ``},
+		{`fat`, `'fat'`, ``},
+		{`fat & rat`, `'fat' & 'rat'`, ``},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`, ``},
+		{`fat | rat & cat`, `'fat' | 'rat' & 'cat'`, ``},
+		{`!fat & !(rat | cat)`, `!'fat' & !( 'rat' | 'cat' )`, ``},
+		{`fat <-> rat <2> cat`, `'fat' <-> 'rat' <2> 'cat'`, ``},
+		{`fat <-> (rat <-> cat)`, `'fat' <-> ( 'rat' <-> 'cat' )`, ``},
+		{`fat & rat <-> cat`, `'fat' & 'rat' <-> 'cat'`, ``},
+		{`super:*`, `'super':*`, ``},
+		{`cat:ab`, `'cat':AB`, ``},
+		{`'it''s':*A`, `'it''s':*A`, ``},
+		{`fat &`, ``, `syntax error`},
+		{`(fat`, ``, `syntax error`},
+		{`fat rat`, ``, `syntax error`},
+		{`fat <x> rat`, ``, `syntax error`},
+		{`fat <99999> rat`, ``, `distance in phrase operator`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.exp {
+				t.Fatalf("expected %s, got %s", tc.exp, s)
+			}
+			q2, err := ParseTSQuery(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Compare(q2) != 0 {
+				t.Fatalf("%s did not round-trip: got %s", q, q2)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("to_tsvector", func(t *testing.T) {
+		testCases := []struct {
+			config string
+			doc    string
+			exp    string
+		}{
+			{"english", "The Fat Rats ate the fat cats.", `'at':4 'cat':7 'fat':2,6 'rat':3`},
+			{"simple", "The Fat Rats", `'fat':2 'rats':3 'the':1`},
+			{"english", "Jumping, running; hopping!", `'hop':3 'jump':1 'run':2`},
+			{"english", "", ``},
+		}
+		for _, tc := range testCases {
+			c, err := GetConfig(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := c.ToTSVector(tc.doc).String(); s != tc.exp {
+				t.Errorf("%s: expected %s, got %s", tc.doc, tc.exp, s)
+			}
+		}
+	})
+
+	t.Run("to_tsquery", func(t *testing.T) {
+		testCases := []struct {
+			input string
+			exp   string
+		}{
+			{`Fat & Rats`, `'fat' & 'rat'`},
+			{`the & rats`, `'rat'`},
+			{`the`, ``},
+			{`fat <-> the <-> rats`, `'fat' <2> 'rat'`},
+			{`'fat-rats'`, `'fat' <-> 'rat'`},
+			{`jump:*A`, `'jump':*A`},
+		}
+		for _, tc := range testCases {
+			q, err := EnglishConfig.ToTSQuery(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.exp {
+				t.Errorf("%s: expected %s, got %s", tc.input, tc.exp, s)
+			}
+		}
+		if s := EnglishConfig.PlainToTSQuery("The fat rats!").String(); s != `'fat' & 'rat'` {
+			t.Errorf("unexpected plain query %s", s)
+		}
+	})
+
+	if _, err := GetConfig("pg_catalog.english"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfig("klingon"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	doc := `fat:1 rat:2 ate:3 cat:5A super:6`
+	testCases := []struct {
+		query string
+		exp   bool
+	}{
+		{``, false},
+		{`fat`, true},
+		{`dog`, false},
+		{`fat & cat`, true},
+		{`fat & dog`, false},
+		{`dog | cat`, true},
+		{`!dog`, true},
+		{`!fat`, false},
+		{`fat <-> rat`, true},
+		{`rat <-> fat`, false},
+		{`fat <2> ate`, true},
+		{`fat <-> ate`, false},
+		{`ate <2> cat`, true},
+		{`fat <-> rat <-> ate`, true},
+		{`fat <-> !ate`, true},
+		{`rat <-> !ate`, false},
+		{`(fat | cat) <-> rat`, true},
+		{`cat:A`, true},
+		{`cat:B`, false},
+		{`fat:A`, false},
+		{`sup:*`, true},
+		{`sup:*A`, false},
+		{`supe:* <-> cat`, false},
+	}
+	v, err := ParseTSVector(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		q, err := ParseTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := Matches(v, q); res != tc.exp {
+			t.Errorf("%s @@ %s: expected %t, got %t", doc, tc.query, tc.exp, res)
+		}
+	}
+
+	// Phrases match lexemes without positions.
+	stripped, err := ParseTSVector(`fat rat`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := ParseTSQuery(`rat <-> fat`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Matches(stripped, q) {
+		t.Errorf("expected %s to match %s", stripped, q)
+	}
+}
+
+func TestRequiredLexemes(t *testing.T) {
+	testCases := []struct {
+		query string
+		exp   string
+	}{
+		{``, ``},
+		{`fat`, `fat`},
+		{`fat & cat`, `cat fat`},
+		{`fat | cat`, ``},
+		{`(fat & rat) | (cat & rat)`, `rat`},
+		{`fat & !cat`, `fat`},
+		{`!(fat & cat)`, ``},
+		{`fat <-> rat <2> cat`, `cat fat rat`},
+		{`sup:* & cat`, `cat`},
+		{`fat & fat:A`, `fat`},
+	}
+	for _, tc := range testCases {
+		q, err := ParseTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := strings.Join(q.RequiredLexemes(), " "); res != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.query, tc.exp, res)
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	for _, s := range []string{``, `a`, `fat:2,4 cat:3A,1B rat:5C 'it''s'`} {
+		v, err := ParseTSVector(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := DecodeTSVector(v.Encode(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Compare(res) != 0 {
+			t.Errorf("expected %s, got %s", v, res)
+		}
+	}
+	for _, s := range []string{``, `a`, `!a & (b:*AB | c <3> d) & !(e <-> f)`} {
+		q, err := ParseTSQuery(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := DecodeTSQuery(q.Encode(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.String() != res.String() {
+			t.Errorf("expected %s, got %s", q, res)
+		}
+	}
+	if _, err := DecodeTSVector([]byte{0, 0, 0, 1, 'a'}); err == nil {
+		t.Error("expected error decoding truncated tsvector")
+	}
+	if _, err := DecodeTSQuery([]byte{0, 0, 0, 2, itemOperator, byte(OpNot)}); err == nil {
+		t.Error("expected error decoding truncated tsquery")
+	}
+}
+
+func TestPorterStem(t *testing.T) {
+	testCases := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"cats":           "cat",
+		"feed":           "feed",
+		"agreed":         "agre",
+		"plastered":      "plaster",
+		"motoring":       "motor",
+		"hopping":        "hop",
+		"filing":         "file",
+		"happy":          "happi",
+		"relational":     "relat",
+		"conditional":    "condit",
+		"generalization": "gener",
+		"hopefulness":    "hope",
+		"adjustment":     "adjust",
+		"controlling":    "control",
+		"rolling":        "roll",
+		"is":             "is",
+		"café":           "café",
+	}
+	for word, exp := range testCases {
+		if res := porterStem(word); res != exp {
+			t.Errorf("%s: expected %s, got %s", word, exp, res)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	rank := func(doc, query string, method int) float32 {
+		t.Helper()
+		q, err := EnglishConfig.ToTSQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Rank(DefaultRankWeights, EnglishConfig.ToTSVector(doc), q, method)
+	}
+	if r := rank("a fat cat", "dog", 0); r != 0 {
+		t.Errorf("expected 0 rank for no match, got %f", r)
+	}
+	// More occurrences rank higher.
+	if a, b := rank("fat cat", "cat", 0), rank("fat cat cat", "cat", 0); a >= b {
+		t.Errorf("expected %f < %f", a, b)
+	}
+	// Closer lexemes rank higher in conjunctions.
+	if a, b := rank("fat big large huge cat", "fat & cat", 0), rank("fat cat", "fat & cat", 0); a >= b {
+		t.Errorf("expected %f < %f", a, b)
+	}
+	// Normalization by the document length lowers the rank of long documents.
+	if a, b := rank("cat", "cat", RankNormLength), rank("cat rat bat", "cat", RankNormLength); a <= b {
+		t.Errorf("expected %f > %f", a, b)
+	}
+	if r := rank("cat", "cat", RankNormRDivRPlus1); r >= 1 {
+		t.Errorf("expected rank < 1, got %f", r)
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	doc := "The fat cat sat on the mat. The rat ran away from the fat cat."
+	testCases := []struct {
+		query string
+		opts  string
+		exp   string
+		err   string
+	}{
+		{`cat`, ``, `The fat <b>cat</b> sat on the mat. The rat ran away from the fat <b>cat</b>`, ``},
+		{`rats`, `StartSel=[, StopSel=]`, `The fat cat sat on the mat. The [rat] ran away from the fat cat`, ``},
+		{`dog`, `MaxWords=5, MinWords=3`, `The fat cat`, ``},
+		{`rat`, `MaxWords=4, MinWords=2`, `<b>rat</b> ran away from`, ``},
+		{`cat`, `HighlightAll=true, StartSel="<em>", StopSel="</em>"`,
+			`The fat <em>cat</em> sat on the mat. The rat ran away from the fat <em>cat</em>.`, ``},
+		{`cat`, `MaxWords=2, MinWords=3`, ``, `MinWords should be less than MaxWords`},
+		{`cat`, `Colour=red`, ``, `unrecognized headline parameter`},
+	}
+	for _, tc := range testCases {
+		q, err := EnglishConfig.ToTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts, err := ParseHeadlineOptions(tc.opts)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.opts, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := EnglishConfig.Headline(doc, q, opts); res != tc.exp {
+			t.Errorf("%s %s: expected %q, got %q", tc.query, tc.opts, tc.exp, res)
+		}
+	}
+}
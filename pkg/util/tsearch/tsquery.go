@@ -0,0 +1,461 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Operator is an operator of a tsquery. The values match the ones used by
+// the binary format of PostgreSQL.
+type Operator uint8
+
+const (
+	// OpLexeme is the pseudo-operator of leaf nodes.
+	OpLexeme Operator = iota
+	// OpNot matches if its operand does not match: !a.
+	OpNot
+	// OpAnd matches if both its operands match: a & b.
+	OpAnd
+	// OpOr matches if one of its operands match: a | b.
+	OpOr
+	// OpPhrase matches if its right operand matches Distance positions after
+	// its left operand: a <-> b, a <2> b.
+	OpPhrase
+)
+
+// priority returns the binding strength of the operator.
+func (o Operator) priority() int {
+	switch o {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpPhrase:
+		return 3
+	case OpNot:
+		return 4
+	}
+	return 5
+}
+
+// Node is a node of a tsquery: either a lexeme or an operator.
+type Node struct {
+	Op Operator
+
+	// Lexeme is the text of a lexeme node.
+	Lexeme string
+	// Prefix is set if the lexeme node matches all the lexemes starting with
+	// Lexeme: 'supern':*.
+	Prefix bool
+	// Weights is the set of weights a lexeme node matches, as a bitmask
+	// indexed by Weight. Zero matches all weights: 'cat':AB.
+	Weights uint8
+
+	// Distance is the distance of a phrase node.
+	Distance uint16
+
+	// Left is the operand of a NOT node, and the left operand of the other
+	// operators. Right is the right operand of binary operators.
+	Left, Right *Node
+}
+
+// TSQuery is a text search query. The query of an empty string has no root
+// and matches nothing.
+type TSQuery struct {
+	Root *Node
+}
+
+// ParseTSQuery parses the text representation of a tsquery, in which lexemes
+// are combined with the operators !, &, | and <N>, as in `fat & (rat | cat)`.
+// Lexemes are not normalized.
+func ParseTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, nil /* normalize */)
+}
+
+// parseTSQuery parses a tsquery. If normalize is not nil, it is called to
+// turn each lexeme into a (possibly empty) subtree.
+func parseTSQuery(s string, normalize func(*Node) (*Node, error)) (TSQuery, error) {
+	p := tsQueryParser{tsParser: tsParser{input: s, typ: "tsquery"}, normalize: normalize}
+	p.skipSpace()
+	if p.done() {
+		return TSQuery{}, nil
+	}
+	root, err := p.expr(0)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return TSQuery{}, p.syntaxError()
+	}
+	if p.normalize != nil {
+		root, _, _ = cleanStopwords(root)
+	}
+	return TSQuery{Root: root}, nil
+}
+
+type tsQueryParser struct {
+	tsParser
+	normalize func(*Node) (*Node, error)
+}
+
+// expr parses an expression whose binary operators bind more strongly than
+// the given priority.
+func (p *tsQueryParser) expr(minPriority int) (*Node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.done() {
+			return left, nil
+		}
+		start := p.pos
+		op, dist, ok, err := p.binaryOp()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || op.priority() <= minPriority {
+			p.pos = start
+			return left, nil
+		}
+		right, err := p.expr(op.priority())
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Op: op, Distance: dist, Left: left, Right: right}
+	}
+}
+
+// binaryOp parses a binary operator, if there is one.
+func (p *tsQueryParser) binaryOp() (op Operator, dist uint16, ok bool, _ error) {
+	switch p.peek() {
+	case '&':
+		p.pos++
+		return OpAnd, 0, true, nil
+	case '|':
+		p.pos++
+		return OpOr, 0, true, nil
+	case '<':
+		end := strings.IndexByte(p.input[p.pos:], '>')
+		if end < 0 {
+			return 0, 0, false, p.syntaxError()
+		}
+		inner := p.input[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+		if inner == "-" {
+			return OpPhrase, 1, true, nil
+		}
+		n, err := strconv.Atoi(inner)
+		if err != nil || n < 0 {
+			return 0, 0, false, p.syntaxError()
+		}
+		if n > MaxPosition {
+			return 0, 0, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"distance in phrase operator must be an integer value between zero and %d inclusive",
+				MaxPosition)
+		}
+		return OpPhrase, uint16(n), true, nil
+	}
+	return 0, 0, false, nil
+}
+
+// unary parses a lexeme, a negation or a parenthesized expression.
+func (p *tsQueryParser) unary() (*Node, error) {
+	p.skipSpace()
+	if p.done() {
+		return nil, p.syntaxError()
+	}
+	switch p.peek() {
+	case '!':
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Op: OpNot, Left: operand}, nil
+	case '(':
+		p.pos++
+		n, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.done() || p.peek() != ')' {
+			return nil, p.syntaxError()
+		}
+		p.pos++
+		return n, nil
+	}
+	text, err := p.lexeme()
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{Op: OpLexeme, Lexeme: text}
+	if !p.done() && p.peek() == ':' {
+		p.pos++
+		for !p.done() {
+			c := p.peek()
+			if c == '*' {
+				n.Prefix = true
+			} else if w, ok := parseWeight(c); ok {
+				n.Weights |= 1 << w
+			} else {
+				break
+			}
+			p.pos++
+		}
+	}
+	if p.normalize != nil {
+		return p.normalize(n)
+	}
+	return n, nil
+}
+
+// stopword is the node which replaces the lexemes removed by normalization
+// until they are cleaned up by cleanStopwords.
+var stopword = &Node{}
+
+// cleanStopwords removes the stopword nodes from the given tree. The
+// distances of the phrase operators around removed lexemes are adjusted so
+// that `a <-> the <-> b` becomes `a <2> b`. It returns the distances to add
+// to the enclosing phrase operators on the left and right of the tree.
+func cleanStopwords(n *Node) (_ *Node, ladd, radd int) {
+	switch n.Op {
+	case OpLexeme:
+		if n == stopword {
+			return nil, 0, 0
+		}
+		return n, 0, 0
+	case OpNot:
+		operand, _, _ := cleanStopwords(n.Left)
+		if operand == nil {
+			return nil, 0, 0
+		}
+		n.Left = operand
+		return n, 0, 0
+	case OpAnd, OpOr:
+		left, _, _ := cleanStopwords(n.Left)
+		right, _, _ := cleanStopwords(n.Right)
+		switch {
+		case left == nil:
+			return right, 0, 0
+		case right == nil:
+			return left, 0, 0
+		}
+		n.Left, n.Right = left, right
+		return n, 0, 0
+	default:
+		left, lladd, lradd := cleanStopwords(n.Left)
+		right, rladd, rradd := cleanStopwords(n.Right)
+		dist := int(n.Distance)
+		switch {
+		case left == nil && right == nil:
+			return nil, lladd + dist + rradd, lladd + dist + rradd
+		case left == nil:
+			return right, lladd + dist + rladd, rradd
+		case right == nil:
+			return left, lladd, lradd + dist + rradd
+		}
+		dist += lradd + rladd
+		if dist > MaxPosition {
+			dist = MaxPosition
+		}
+		n.Left, n.Right, n.Distance = left, right, uint16(dist)
+		return n, lladd, rradd
+	}
+}
+
+// String returns the text representation of the tsquery.
+func (q TSQuery) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	q.Root.format(&buf)
+	return buf.String()
+}
+
+func (n *Node) format(buf *bytes.Buffer) {
+	switch n.Op {
+	case OpLexeme:
+		writeQuotedLexeme(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights&(1<<w) != 0 {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+	case OpNot:
+		buf.WriteByte('!')
+		n.Left.formatOperand(buf, n.Op.priority(), false /* right */)
+	default:
+		n.Left.formatOperand(buf, n.Op.priority(), false /* right */)
+		switch n.Op {
+		case OpAnd:
+			buf.WriteString(" & ")
+		case OpOr:
+			buf.WriteString(" | ")
+		case OpPhrase:
+			if n.Distance == 1 {
+				buf.WriteString(" <-> ")
+			} else {
+				buf.WriteString(" <")
+				buf.WriteString(strconv.Itoa(int(n.Distance)))
+				buf.WriteString("> ")
+			}
+		}
+		n.Right.formatOperand(buf, n.Op.priority(), true /* right */)
+	}
+}
+
+// formatOperand formats an operand of an operator with the given priority,
+// parenthesizing it if needed.
+func (n *Node) formatOperand(buf *bytes.Buffer, priority int, right bool) {
+	p := n.Op.priority()
+	if p < priority || (right && p == priority && n.Op == OpPhrase) {
+		buf.WriteString("( ")
+		n.format(buf)
+		buf.WriteString(" )")
+		return
+	}
+	n.format(buf)
+}
+
+// Compare returns -1, 0 or 1 if q is respectively less than, equal to or
+// greater than other. Queries are ordered by their text representation.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Lexemes returns the lexeme nodes of the query, in order.
+func (q TSQuery) Lexemes() []*Node {
+	var res []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		if n.Op == OpLexeme {
+			res = append(res, n)
+			return
+		}
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(q.Root)
+	return res
+}
+
+// positiveLexemes returns the lexeme nodes of the query which are not
+// negated.
+func (q TSQuery) positiveLexemes() []*Node {
+	var res []*Node
+	var walk func(n *Node, negated bool)
+	walk = func(n *Node, negated bool) {
+		switch n.Op {
+		case OpLexeme:
+			if !negated {
+				res = append(res, n)
+			}
+		case OpNot:
+			walk(n.Left, !negated)
+		default:
+			walk(n.Left, negated)
+			walk(n.Right, negated)
+		}
+	}
+	if q.Root != nil {
+		walk(q.Root, false)
+	}
+	return res
+}
+
+// RequiredLexemes returns the sorted, distinct lexemes which are contained by
+// every tsvector matching the query. Prefix lexemes are not included. This is
+// used to constrain scans of inverted indexes.
+func (q TSQuery) RequiredLexemes() []string {
+	if q.Root == nil {
+		return nil
+	}
+	var res []string
+	for l := range requiredLexemes(q.Root) {
+		res = append(res, l)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func requiredLexemes(n *Node) map[string]struct{} {
+	switch n.Op {
+	case OpLexeme:
+		if n.Prefix {
+			return nil
+		}
+		return map[string]struct{}{n.Lexeme: {}}
+	case OpAnd, OpPhrase:
+		res := requiredLexemes(n.Left)
+		for l := range requiredLexemes(n.Right) {
+			if res == nil {
+				res = make(map[string]struct{})
+			}
+			res[l] = struct{}{}
+		}
+		return res
+	case OpOr:
+		// Only the lexemes required by both sides are required.
+		left, right := requiredLexemes(n.Left), requiredLexemes(n.Right)
+		var res map[string]struct{}
+		for l := range left {
+			if _, ok := right[l]; ok {
+				if res == nil {
+					res = make(map[string]struct{})
+				}
+				res[l] = struct{}{}
+			}
+		}
+		return res
+	}
+	// Negated lexemes are never required.
+	return nil
+}
+
+// Size returns the approximate size of the tsquery in bytes.
+func (q TSQuery) Size() uintptr {
+	var sz uintptr
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		sz += 64 + uintptr(len(n.Lexeme))
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(q.Root)
+	return sz
+}
@@ -0,0 +1,395 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the text search types of PostgreSQL: tsvector,
+// a sorted list of normalized lexemes with their positions in a document, and
+// tsquery, a boolean expression over lexemes that can be matched against a
+// tsvector.
+package tsearch
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Weight is the weight of a position of a lexeme. Weights are used to mark
+// parts of a document, such as its title or its body, and are taken into
+// account when ranking matches.
+type Weight uint8
+
+// The weights are ordered as in PostgreSQL: D, the default weight, has the
+// lowest value and A the highest.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String returns the letter of the weight.
+func (w Weight) String() string {
+	return string(rune('D' - w))
+}
+
+// parseWeight returns the weight of the given letter.
+func parseWeight(c byte) (Weight, bool) {
+	switch c {
+	case 'a', 'A':
+		return WeightA, true
+	case 'b', 'B':
+		return WeightB, true
+	case 'c', 'C':
+		return WeightC, true
+	case 'd', 'D':
+		return WeightD, true
+	}
+	return 0, false
+}
+
+const (
+	// MaxPosition is the largest position that can be stored in a tsvector.
+	// Larger positions are clamped to it.
+	MaxPosition = 1<<14 - 1
+	// maxPositions is the maximum number of positions stored per lexeme.
+	// Further positions are dropped.
+	maxPositions = 256
+	// maxLexemeLen is the maximum length of a lexeme in bytes.
+	maxLexemeLen = 2047
+)
+
+// Position is a position of a lexeme in a document, with its weight.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Lexeme is a normalized word and the positions at which it appears in a
+// document. Lexemes of tsvectors built from plain strings have no positions.
+type Lexeme struct {
+	Text      string
+	Positions []Position
+}
+
+// TSVector is a sorted list of distinct lexemes.
+type TSVector []Lexeme
+
+// ParseTSVector parses the text representation of a tsvector, in which
+// lexemes are separated by whitespace, may be quoted with single quotes, and
+// may be followed by a comma-separated list of positions with optional
+// weights, as in `'fat':2 'cat':3A,5`. Lexemes are not normalized.
+func ParseTSVector(s string) (TSVector, error) {
+	p := tsParser{input: s, typ: "tsvector"}
+	var v TSVector
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		text, err := p.lexeme()
+		if err != nil {
+			return nil, err
+		}
+		l := Lexeme{Text: text}
+		if !p.done() && p.peek() == ':' {
+			p.pos++
+			if l.Positions, err = p.positions(); err != nil {
+				return nil, err
+			}
+		}
+		v = append(v, l)
+	}
+	return v.normalize(), nil
+}
+
+// normalize sorts the lexemes of v, merging the duplicate ones, and sorts
+// their positions.
+func (v TSVector) normalize() TSVector {
+	if len(v) == 0 {
+		return v
+	}
+	sort.SliceStable(v, func(i, j int) bool { return v[i].Text < v[j].Text })
+	res := v[:1]
+	for _, l := range v[1:] {
+		last := &res[len(res)-1]
+		if l.Text == last.Text {
+			last.Positions = append(last.Positions, l.Positions...)
+			continue
+		}
+		res = append(res, l)
+	}
+	for i := range res {
+		res[i].Positions = normalizePositions(res[i].Positions)
+	}
+	return res
+}
+
+// normalizePositions sorts the given positions and removes the duplicate ones,
+// keeping the highest weight of a position.
+func normalizePositions(ps []Position) []Position {
+	if len(ps) == 0 {
+		return nil
+	}
+	sort.SliceStable(ps, func(i, j int) bool { return ps[i].Pos < ps[j].Pos })
+	res := ps[:1]
+	for _, p := range ps[1:] {
+		last := &res[len(res)-1]
+		if p.Pos == last.Pos {
+			if p.Weight > last.Weight {
+				last.Weight = p.Weight
+			}
+			continue
+		}
+		res = append(res, p)
+	}
+	if len(res) > maxPositions {
+		res = res[:maxPositions]
+	}
+	return res
+}
+
+// String returns the text representation of the tsvector.
+func (v TSVector) String() string {
+	var buf bytes.Buffer
+	for i, l := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeQuotedLexeme(&buf, l.Text)
+		for j, p := range l.Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeQuotedLexeme writes the given lexeme quoted with single quotes,
+// doubling the quotes and backslashes it contains.
+func writeQuotedLexeme(buf *bytes.Buffer, s string) {
+	buf.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\'' || s[i] == '\\' {
+			buf.WriteByte(s[i])
+		}
+		buf.WriteByte(s[i])
+	}
+	buf.WriteByte('\'')
+}
+
+// Compare returns -1, 0 or 1 if v is respectively less than, equal to or
+// greater than other. The order is a total order consistent with equality;
+// it has no meaning beyond that.
+func (v TSVector) Compare(other TSVector) int {
+	if len(v) != len(other) {
+		return cmpInt(len(v), len(other))
+	}
+	for i := range v {
+		if c := strings.Compare(v[i].Text, other[i].Text); c != 0 {
+			return c
+		}
+		a, b := v[i].Positions, other[i].Positions
+		if len(a) != len(b) {
+			return cmpInt(len(a), len(b))
+		}
+		for j := range a {
+			if a[j] != b[j] {
+				if a[j].Pos != b[j].Pos {
+					return cmpInt(int(a[j].Pos), int(b[j].Pos))
+				}
+				return cmpInt(int(a[j].Weight), int(b[j].Weight))
+			}
+		}
+	}
+	return 0
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// find returns the index of the lexeme with the given text, or -1.
+func (v TSVector) find(text string) int {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Text >= text })
+	if i < len(v) && v[i].Text == text {
+		return i
+	}
+	return -1
+}
+
+// findPrefix returns the range of lexemes starting with the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start = sort.Search(len(v), func(i int) bool { return v[i].Text >= prefix })
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].Text, prefix) {
+		end++
+	}
+	return start, end
+}
+
+// Size returns the approximate size of the tsvector in bytes.
+func (v TSVector) Size() uintptr {
+	sz := uintptr(len(v)) * 40
+	for _, l := range v {
+		sz += uintptr(len(l.Text)) + uintptr(len(l.Positions))*4
+	}
+	return sz
+}
+
+// tsParser is a parser of the text representation of tsvectors and tsqueries.
+type tsParser struct {
+	input string
+	pos   int
+	// typ is the name of the type being parsed, used in errors.
+	typ string
+}
+
+func (p *tsParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tsParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *tsParser) skipSpace() {
+	for !p.done() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *tsParser) syntaxError() error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", p.typ, p.input)
+}
+
+// lexeme parses a lexeme, either quoted with single quotes or ending at
+// whitespace or at one of the characters which are special in the type being
+// parsed. Backslashes escape the next character in both forms.
+func (p *tsParser) lexeme() (string, error) {
+	var buf strings.Builder
+	if p.peek() == '\'' {
+		p.pos++
+		for {
+			if p.done() {
+				return "", p.syntaxError()
+			}
+			c := p.peek()
+			p.pos++
+			if c == '\\' {
+				if p.done() {
+					return "", p.syntaxError()
+				}
+				c = p.peek()
+				p.pos++
+			} else if c == '\'' {
+				if p.done() || p.peek() != '\'' {
+					break
+				}
+				p.pos++
+			}
+			buf.WriteByte(c)
+		}
+	} else {
+		for !p.done() {
+			c := p.peek()
+			if isSpace(c) || p.isSpecial(c) {
+				break
+			}
+			p.pos++
+			if c == '\\' {
+				if p.done() {
+					return "", p.syntaxError()
+				}
+				c = p.peek()
+				p.pos++
+			}
+			buf.WriteByte(c)
+		}
+	}
+	if buf.Len() == 0 {
+		return "", p.syntaxError()
+	}
+	if buf.Len() > maxLexemeLen {
+		return "", pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", buf.Len(), maxLexemeLen)
+	}
+	return buf.String(), nil
+}
+
+// isSpecial returns whether c ends an unquoted lexeme.
+func (p *tsParser) isSpecial(c byte) bool {
+	switch c {
+	case ':':
+		return true
+	case '\'', '&', '|', '!', '(', ')', '<':
+		return p.typ == "tsquery"
+	}
+	return false
+}
+
+// positions parses a comma-separated list of positions with optional weights.
+func (p *tsParser) positions() ([]Position, error) {
+	var res []Position
+	for {
+		start := p.pos
+		for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, p.syntaxError()
+		}
+		n, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || n > MaxPosition {
+			n = MaxPosition
+		}
+		if n == 0 {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"wrong position info in %s: %q", p.typ, p.input)
+		}
+		pos := Position{Pos: uint16(n)}
+		if !p.done() {
+			if w, ok := parseWeight(p.peek()); ok {
+				pos.Weight = w
+				p.pos++
+			}
+		}
+		res = append(res, pos)
+		if p.done() || p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if !p.done() && !isSpace(p.peek()) {
+		return nil, p.syntaxError()
+	}
+	return res, nil
+}
+
+func isSpace(c byte) bool {
+	return c < 0x80 && unicode.IsSpace(rune(c))
+}